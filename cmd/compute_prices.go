@@ -0,0 +1,241 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/ojo-network/price-feeder/config"
+	"github.com/ojo-network/price-feeder/oracle"
+	"github.com/ojo-network/price-feeder/oracle/client"
+	"github.com/ojo-network/price-feeder/oracle/provider"
+	"github.com/ojo-network/price-feeder/oracle/types"
+)
+
+const (
+	flagTimeout = "timeout"
+
+	computePricesInterval = time.Second
+)
+
+func getComputePricesCmd() *cobra.Command {
+	computePricesCmd := &cobra.Command{
+		Use:   "compute-prices [config-file]",
+		Args:  cobra.ExactArgs(1),
+		Short: "Print the prices the price-feeder would vote right now and exit",
+		Long: `Connect to the configured providers, wait until a price is available for
+every configured currency pair, print the aggregated prices and exit. Neither
+the keyring nor the chain is used.`,
+		RunE: computePricesCmdHandler,
+	}
+
+	computePricesCmd.Flags().Duration(flagTimeout, time.Minute, "maximum time to wait for a price of every currency pair")
+	computePricesCmd.Flags().String(flagFormat, "text", "Print the prices in the given format (text|json)")
+
+	return computePricesCmd
+}
+
+func computePricesCmdHandler(cmd *cobra.Command, args []string) error {
+	timeout, err := cmd.Flags().GetDuration(flagTimeout)
+	if err != nil {
+		return err
+	}
+
+	format, err := cmd.Flags().GetString(flagFormat)
+	if err != nil {
+		return err
+	}
+	if format != "text" && format != "json" {
+		return fmt.Errorf("invalid format: %s", format)
+	}
+
+	cfg, err := config.LoadConfigFromFlags(args[0], "")
+	if err != nil {
+		return err
+	}
+	logger, err := getLogger(cmd, cfg)
+	if err != nil {
+		return err
+	}
+	logger = cfg.InstanceLogger(logger)
+	cfg.LogMergedPairs(logger)
+
+	providerTimeout, err := time.ParseDuration(cfg.ProviderTimeout)
+	if err != nil {
+		return fmt.Errorf("failed to parse provider timeout: %w", err)
+	}
+
+	providerSilenceTimeout, err := time.ParseDuration(cfg.ProviderSilenceTimeout)
+	if err != nil {
+		return fmt.Errorf("failed to parse provider silence timeout: %w", err)
+	}
+
+	symbolCacheTTL, err := time.ParseDuration(cfg.SymbolCache.TTL)
+	if err != nil {
+		return fmt.Errorf("failed to parse symbol cache ttl: %w", err)
+	}
+	provider.SetSymbolCache(cfg.SymbolCache.Path, symbolCacheTTL, cfg.SymbolCache.MaxRetries)
+
+	maxForexAges, err := cfg.MaxForexAges()
+	if err != nil {
+		return err
+	}
+
+	minBookDepths, err := cfg.MinBookDepths()
+	if err != nil {
+		return err
+	}
+
+	maxSpreads, err := cfg.MaxSpreads()
+	if err != nil {
+		return err
+	}
+
+	deviations, err := cfg.DeviationsMap()
+	if err != nil {
+		return err
+	}
+
+	priceBands, err := cfg.PriceBandsMap()
+	if err != nil {
+		return err
+	}
+
+	tvwapWeightings, err := cfg.TvwapWeightingsMap()
+	if err != nil {
+		return err
+	}
+
+	referencePrices, err := cfg.ReferencePricesMap()
+	if err != nil {
+		return err
+	}
+
+	anchorPairs, err := cfg.AnchorPairsMap()
+	if err != nil {
+		return err
+	}
+
+	providerAgreements, err := cfg.ProviderAgreements()
+	if err != nil {
+		return err
+	}
+
+	zeroVolumeWeight, err := cfg.ZeroVolumeWeightDec()
+	if err != nil {
+		return err
+	}
+
+	oracle := oracle.New(
+		logger,
+		client.OracleClient{},
+		cfg.ProviderPairs(),
+		providerTimeout,
+		deviations,
+		cfg.ProviderEndpointsMap(),
+	)
+	oracle.SetPriceBands(priceBands)
+	oracle.SetTvwapWeightings(tvwapWeightings)
+	oracle.SetReferencePrices(referencePrices)
+	oracle.SetAnchorPairs(anchorPairs)
+	oracle.SetConversionRoutes(cfg.ConversionRoutesMap())
+	oracle.SetRedemptionRates(cfg.RedemptionRateUnderlyings())
+	oracle.SetZeroVolumeWeight(zeroVolumeWeight)
+	oracle.SetProviderRoles(cfg.ProviderRoles())
+	oracle.SetTickerWindows(cfg.TickerWindowsMap())
+	oracle.SetProviderSilenceTimeout(providerSilenceTimeout)
+	oracle.SetProviderAgreements(providerAgreements)
+	oracle.SetRequiredProviders(cfg.RequiredProviders())
+	oracle.SetProviderGroups(cfg.ProviderGroupsMap(), cfg.MinProviderGroups())
+	oracle.SetSpotSources(cfg.SpotSources())
+	oracle.SetMedianTieBreaks(cfg.MedianTieBreaks())
+	oracle.SetMaxBufferedCandles(cfg.MaxBufferedCandles)
+	oracle.SetMaxProviders(cfg.MaxProviders())
+	oracle.SetMaxForexAges(maxForexAges)
+	oracle.SetMinBookDepths(minBookDepths)
+	oracle.SetMaxSpreads(maxSpreads)
+	oracle.SetSpotOnlyBases(cfg.SpotOnlyBases())
+
+	ctx := cmd.Context()
+	deadline := time.After(timeout)
+	ticker := time.NewTicker(computePricesInterval)
+	defer ticker.Stop()
+
+	for {
+		if err := oracle.SetPrices(ctx); err != nil {
+			logger.Debug().Err(err).Msg("failed to compute prices")
+		}
+
+		prices := oracle.GetPrices()
+		missing := missingRates(oracle.RequiredRates(), prices)
+		if len(missing) == 0 {
+			return printPrices(prices, format)
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+
+		case <-deadline:
+			if len(prices) > 0 {
+				if err := printPrices(prices, format); err != nil {
+					return err
+				}
+			}
+			return fmt.Errorf("timed out waiting for prices of %s", strings.Join(missing, ", "))
+
+		case <-ticker.C:
+		}
+	}
+}
+
+// missingRates returns the sorted currency pairs of the required rates which
+// have no price.
+func missingRates(requiredRates []types.CurrencyPair, prices types.CurrencyPairDec) []string {
+	missing := []string{}
+	for _, cp := range requiredRates {
+		if _, ok := prices[cp]; !ok {
+			missing = append(missing, cp.String())
+		}
+	}
+	sort.Strings(missing)
+	return missing
+}
+
+// printPrices prints the prices sorted by currency pair as a table or as a
+// JSON object mapping each currency pair to its price.
+func printPrices(prices types.CurrencyPairDec, format string) error {
+	pairs := make([]types.CurrencyPair, 0, len(prices))
+	for cp := range prices {
+		pairs = append(pairs, cp)
+	}
+	sort.Slice(pairs, func(i, j int) bool {
+		return pairs[i].String() < pairs[j].String()
+	})
+
+	if format == "json" {
+		pricesByPair := make(map[string]string, len(prices))
+		for _, cp := range pairs {
+			pricesByPair[cp.String()] = prices[cp].String()
+		}
+		bz, err := json.Marshal(pricesByPair)
+		if err != nil {
+			return err
+		}
+		_, err = fmt.Println(string(bz))
+		return err
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "PAIR\tPRICE")
+	for _, cp := range pairs {
+		fmt.Fprintf(w, "%s\t%s\n", cp.String(), prices[cp].String())
+	}
+	return w.Flush()
+}
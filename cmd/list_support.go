@@ -0,0 +1,104 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"text/tabwriter"
+
+	"github.com/spf13/cobra"
+
+	"github.com/ojo-network/price-feeder/config"
+)
+
+type (
+	// supportInfo defines the providers, quotes and forex currencies the
+	// price-feeder supports.
+	supportInfo struct {
+		Providers       []providerSupport `json:"providers"`
+		Quotes          []string          `json:"quotes"`
+		ForexCurrencies []string          `json:"forex_currencies"`
+	}
+
+	// providerSupport defines a supported provider and whether it requires
+	// an API key.
+	providerSupport struct {
+		Name           string `json:"name"`
+		APIKeyRequired bool   `json:"api_key_required"`
+	}
+)
+
+func getListSupportCmd() *cobra.Command {
+	listSupportCmd := &cobra.Command{
+		Use:   "list-support",
+		Args:  cobra.NoArgs,
+		Short: "Print the supported providers, quotes and forex currencies",
+		Long: `Print the supported providers and whether they require an API key, the
+quotes a currency pair can be quoted in and the supported forex currencies,
+which are supported quotes once their USD rate is configured.`,
+		RunE: listSupportCmdHandler,
+	}
+
+	listSupportCmd.Flags().String(flagFormat, "text", "Print the tables in the given format (text|json)")
+
+	return listSupportCmd
+}
+
+func listSupportCmdHandler(cmd *cobra.Command, _ []string) error {
+	format, err := cmd.Flags().GetString(flagFormat)
+	if err != nil {
+		return err
+	}
+	if format != "text" && format != "json" {
+		return fmt.Errorf("invalid format: %s", format)
+	}
+
+	info := getSupportInfo()
+	if format == "json" {
+		bz, err := json.Marshal(info)
+		if err != nil {
+			return err
+		}
+		_, err = fmt.Println(string(bz))
+		return err
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "PROVIDER\tAPI KEY REQUIRED")
+	for _, p := range info.Providers {
+		fmt.Fprintf(w, "%s\t%t\n", p.Name, p.APIKeyRequired)
+	}
+	fmt.Fprintln(w)
+	fmt.Fprintln(w, "QUOTE")
+	for _, quote := range info.Quotes {
+		fmt.Fprintln(w, quote)
+	}
+	fmt.Fprintln(w)
+	fmt.Fprintln(w, "FOREX CURRENCY")
+	for _, currency := range info.ForexCurrencies {
+		fmt.Fprintln(w, currency)
+	}
+	return w.Flush()
+}
+
+// getSupportInfo returns the supported providers sorted by name, quotes and
+// forex currencies.
+func getSupportInfo() supportInfo {
+	providers := make([]providerSupport, 0, len(config.SupportedProviders))
+	for name, apiKeyRequired := range config.SupportedProviders {
+		providers = append(providers, providerSupport{
+			Name:           name.String(),
+			APIKeyRequired: bool(apiKeyRequired),
+		})
+	}
+	sort.Slice(providers, func(i, j int) bool {
+		return providers[i].Name < providers[j].Name
+	})
+
+	return supportInfo{
+		Providers:       providers,
+		Quotes:          config.SupportedQuotes(),
+		ForexCurrencies: config.SupportedForexCurrencySlice(),
+	}
+}
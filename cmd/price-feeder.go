@@ -13,6 +13,7 @@ import (
 	"time"
 
 	"github.com/cosmos/cosmos-sdk/client/input"
+	cryptotypes "github.com/cosmos/cosmos-sdk/crypto/types"
 	"github.com/mitchellh/mapstructure"
 
 	"github.com/gorilla/mux"
@@ -25,6 +26,9 @@ import (
 	"github.com/ojo-network/price-feeder/config"
 	"github.com/ojo-network/price-feeder/oracle"
 	"github.com/ojo-network/price-feeder/oracle/client"
+	"github.com/ojo-network/price-feeder/oracle/provider"
+	"github.com/ojo-network/price-feeder/pkg/logrotate"
+	"github.com/ojo-network/price-feeder/router/profiling"
 	v1 "github.com/ojo-network/price-feeder/router/v1"
 )
 
@@ -35,6 +39,9 @@ const (
 	flagLogLevel          = "log-level"
 	flagLogFormat         = "log-format"
 	flagSkipProviderCheck = "skip-provider-check"
+	flagDeterministic     = "deterministic"
+	flagDeterministicSeed = "deterministic-seed"
+	flagDeterministicTime = "deterministic-time"
 
 	envVariablePass = "PRICE_FEEDER_PASS"
 )
@@ -60,8 +67,13 @@ func init() {
 	rootCmd.PersistentFlags().String(flagLogLevel, zerolog.InfoLevel.String(), "logging level")
 	rootCmd.PersistentFlags().String(flagLogFormat, logLevelText, "logging format; must be either json or text")
 	rootCmd.PersistentFlags().Bool(flagSkipProviderCheck, false, "skip the coingecko API provider check")
+	rootCmd.PersistentFlags().Bool(flagDeterministic, false, "run deterministically with a fixed clock and seeded salts; for testing only")
+	rootCmd.PersistentFlags().Int64(flagDeterministicSeed, 1, "seed of the vote salts in deterministic mode")
+	rootCmd.PersistentFlags().String(flagDeterministicTime, "2023-01-01T00:00:00Z", "fixed RFC3339 time of the clock in deterministic mode")
 
 	rootCmd.AddCommand(getVersionCmd())
+	rootCmd.AddCommand(getComputePricesCmd())
+	rootCmd.AddCommand(getListSupportCmd())
 }
 
 // Execute adds all child commands to the root command and sets flags appropriately.
@@ -74,51 +86,36 @@ func Execute() {
 }
 
 func priceFeederCmdHandler(cmd *cobra.Command, args []string) error {
-	logLvlStr, err := cmd.Flags().GetString(flagLogLevel)
+	skipProviderCheck, err := cmd.Flags().GetBool(flagSkipProviderCheck)
 	if err != nil {
 		return err
 	}
 
-	logLvl, err := zerolog.ParseLevel(logLvlStr)
+	deterministic, deterministicSeed, deterministicTime, err := getDeterministicFlags(cmd)
 	if err != nil {
 		return err
 	}
 
-	logFormatStr, err := cmd.Flags().GetString(flagLogFormat)
+	cfg, err := config.LoadConfigFromFlags(args[0], "")
 	if err != nil {
 		return err
 	}
-
-	skipProviderCheck, err := cmd.Flags().GetBool(flagSkipProviderCheck)
-	if err != nil {
-		return err
-	}
-
-	var logWriter io.Writer
-	switch strings.ToLower(logFormatStr) {
-	case logLevelJSON:
-		logWriter = os.Stderr
-
-	case logLevelText:
-		logWriter = zerolog.ConsoleWriter{Out: os.Stderr}
-
-	default:
-		return fmt.Errorf("invalid logging format: %s", logFormatStr)
-	}
-
-	logger := zerolog.New(logWriter).Level(logLvl).With().Timestamp().Logger()
-
-	cfg, err := config.LoadConfigFromFlags(args[0], "")
+	logger, err := getLogger(cmd, cfg)
 	if err != nil {
 		return err
 	}
+	logger = cfg.InstanceLogger(logger)
+	cfg.LogMergedPairs(logger)
+	cfg.LogOrphanedDeviations(logger)
 
+	var providerMins map[string]int
 	if !skipProviderCheck {
-		err = config.CheckProviderMins(cmd.Context(), logger, cfg)
+		providerMins, err = config.ProviderMins(cmd.Context(), logger, cfg)
 		if err != nil {
 			return err
 		}
 	}
+	cfg.LogStartupPlan(logger, providerMins)
 
 	ctx, cancel := context.WithCancel(cmd.Context())
 	g, ctx := errgroup.WithContext(ctx)
@@ -137,49 +134,264 @@ func priceFeederCmdHandler(cmd *cobra.Command, args []string) error {
 		return err
 	}
 
-	oracleClient, err := client.NewOracleClient(
-		ctx,
-		logger,
-		cfg.Account.ChainID,
-		cfg.Keyring.Backend,
-		cfg.Keyring.Dir,
-		keyringPass,
-		cfg.RPC.TMRPCEndpoint,
-		rpcTimeout,
-		cfg.Account.Address,
-		cfg.Account.Validator,
-		cfg.RPC.GRPCEndpoint,
-		cfg.GasAdjustment,
-		cfg.Gas,
-	)
-	if err != nil {
-		return err
-	}
+	// the chain client is created by the startup of the oracle, in the
+	// configured startup order
+	var oracleClient client.OracleClient
 
 	providerTimeout, err := time.ParseDuration(cfg.ProviderTimeout)
 	if err != nil {
 		return fmt.Errorf("failed to parse provider timeout: %w", err)
 	}
 
+	providerSilenceTimeout, err := time.ParseDuration(cfg.ProviderSilenceTimeout)
+	if err != nil {
+		return fmt.Errorf("failed to parse provider silence timeout: %w", err)
+	}
+
+	symbolCacheTTL, err := time.ParseDuration(cfg.SymbolCache.TTL)
+	if err != nil {
+		return fmt.Errorf("failed to parse symbol cache ttl: %w", err)
+	}
+	provider.SetSymbolCache(cfg.SymbolCache.Path, symbolCacheTTL, cfg.SymbolCache.MaxRetries)
+
+	reconnectCooldown, err := time.ParseDuration(cfg.ReconnectCooldown)
+	if err != nil {
+		return fmt.Errorf("failed to parse reconnect cooldown: %w", err)
+	}
+
+	providerHealthInterval, err := time.ParseDuration(cfg.ProviderHealthInterval)
+	if err != nil {
+		return fmt.Errorf("failed to parse provider health interval: %w", err)
+	}
+
+	reconcileInterval, reconcileMaxAge, err := cfg.SubscriptionReconcileDurations()
+	if err != nil {
+		return err
+	}
+
 	deviations, err := cfg.DeviationsMap()
 	if err != nil {
 		return err
 	}
 
+	priceBands, err := cfg.PriceBandsMap()
+	if err != nil {
+		return err
+	}
+
+	tvwapWeightings, err := cfg.TvwapWeightingsMap()
+	if err != nil {
+		return err
+	}
+
+	latencyThresholds, err := cfg.LatencyThresholdsSlice()
+	if err != nil {
+		return err
+	}
+
+	referencePrices, err := cfg.ReferencePricesMap()
+	if err != nil {
+		return err
+	}
+
+	anchorPairs, err := cfg.AnchorPairsMap()
+	if err != nil {
+		return err
+	}
+
+	providerAgreements, err := cfg.ProviderAgreements()
+	if err != nil {
+		return err
+	}
+
+	zeroVolumeWeight, err := cfg.ZeroVolumeWeightDec()
+	if err != nil {
+		return err
+	}
+
+	voteWarmup, err := cfg.VoteWarmupDuration()
+	if err != nil {
+		return err
+	}
+
+	voteSafetyMargin, err := cfg.VoteSafetyMarginDuration()
+	if err != nil {
+		return err
+	}
+
+	maxVoteChanges, err := cfg.MaxVoteChanges()
+	if err != nil {
+		return err
+	}
+
+	maxForexAges, err := cfg.MaxForexAges()
+	if err != nil {
+		return err
+	}
+
+	minBookDepths, err := cfg.MinBookDepths()
+	if err != nil {
+		return err
+	}
+
+	maxSpreads, err := cfg.MaxSpreads()
+	if err != nil {
+		return err
+	}
+
+	dynamicPairs, err := cfg.DynamicPairSettings()
+	if err != nil {
+		return err
+	}
+
+	startupTimeout, err := time.ParseDuration(cfg.StartupTimeout)
+	if err != nil {
+		return fmt.Errorf("failed to parse startup timeout: %w", err)
+	}
+
+	var priceCache *oracle.PriceCache
+	if cfg.PriceCache.Path != "" {
+		maxAge, err := time.ParseDuration(cfg.PriceCache.MaxAge)
+		if err != nil {
+			return fmt.Errorf("failed to parse price cache max age: %w", err)
+		}
+		writeInterval, err := time.ParseDuration(cfg.PriceCache.WriteInterval)
+		if err != nil {
+			return fmt.Errorf("failed to parse price cache write interval: %w", err)
+		}
+		priceCache = oracle.NewPriceCache(cfg.PriceCache.Path, maxAge, writeInterval)
+	}
+
+	var attestor *oracle.Attestor
+	if cfg.Attestation.URL != "" {
+		timeout, err := time.ParseDuration(cfg.Attestation.Timeout)
+		if err != nil {
+			return fmt.Errorf("failed to parse attestation timeout: %w", err)
+		}
+		attestor = oracle.NewAttestor(
+			logger,
+			cfg.Attestation.URL,
+			cfg.Attestation.AuthToken,
+			timeout,
+			cfg.Attestation.MaxRetries,
+			func(msg []byte) ([]byte, cryptotypes.PubKey, error) {
+				return oracleClient.SignBytes(msg)
+			},
+		)
+	}
+
+	var webhook *oracle.Webhook
+	if cfg.Webhook.URL != "" {
+		timeout, err := time.ParseDuration(cfg.Webhook.Timeout)
+		if err != nil {
+			return fmt.Errorf("failed to parse webhook timeout: %w", err)
+		}
+		rateLimit, err := time.ParseDuration(cfg.Webhook.RateLimit)
+		if err != nil {
+			return fmt.Errorf("failed to parse webhook rate limit: %w", err)
+		}
+		priceChange, err := cfg.WebhookPriceChangeDec()
+		if err != nil {
+			return err
+		}
+		webhook = oracle.NewWebhook(
+			logger,
+			cfg.Webhook.URL,
+			timeout,
+			oracle.WebhookTriggers{
+				PriceChange: priceChange,
+				Omission:    cfg.Webhook.AlertOmissions,
+				PriceBand:   cfg.Webhook.AlertPriceBands,
+			},
+			rateLimit,
+		)
+	}
+
+	var statsdExporter *oracle.StatsdExporter
+	if cfg.Statsd.Address != "" {
+		flushInterval, err := time.ParseDuration(cfg.Statsd.FlushInterval)
+		if err != nil {
+			return fmt.Errorf("failed to parse statsd flush interval: %w", err)
+		}
+		statsdExporter, err = oracle.NewStatsdExporter(cfg.Statsd.Address, cfg.Statsd.Prefix, flushInterval)
+		if err != nil {
+			return fmt.Errorf("failed to create statsd exporter: %w", err)
+		}
+	}
+
 	oracle := oracle.New(
 		logger,
-		oracleClient,
+		client.OracleClient{},
 		cfg.ProviderPairs(),
 		providerTimeout,
 		deviations,
 		cfg.ProviderEndpointsMap(),
 	)
+	oracle.SetPriceBands(priceBands)
+	oracle.SetTvwapWeightings(tvwapWeightings)
+	oracle.SetLatencyThresholds(latencyThresholds)
+	oracle.SetReferencePrices(referencePrices)
+	oracle.SetAnchorPairs(anchorPairs)
+	oracle.SetConversionRoutes(cfg.ConversionRoutesMap())
+	oracle.SetRedemptionRates(cfg.RedemptionRateUnderlyings())
+	oracle.SetZeroVolumeWeight(zeroVolumeWeight)
+	oracle.SetProviderRoles(cfg.ProviderRoles())
+	oracle.SetTickerWindows(cfg.TickerWindowsMap())
+	oracle.SetProviderAgreements(providerAgreements)
+	oracle.SetRequiredProviders(cfg.RequiredProviders())
+	oracle.SetProviderGroups(cfg.ProviderGroupsMap(), cfg.MinProviderGroups())
+	oracle.SetSpotSources(cfg.SpotSources())
+	oracle.SetMedianTieBreaks(cfg.MedianTieBreaks())
+	oracle.SetMaxBufferedCandles(cfg.MaxBufferedCandles)
+	oracle.SetMaxProviders(cfg.MaxProviders())
+	oracle.SetMaxForexAges(maxForexAges)
+	oracle.SetMinBookDepths(minBookDepths)
+	oracle.SetMaxSpreads(maxSpreads)
+	oracle.SetMaxVoteSize(cfg.MaxVoteSize, cfg.VotePriorities())
+	oracle.SetVoteExponents(cfg.VoteExponents())
+	oracle.SetVoteRoundings(cfg.VoteRoundings())
+	oracle.SetMaxVoteChanges(maxVoteChanges)
+	oracle.SetSpotOnlyBases(cfg.SpotOnlyBases())
+	oracle.SetHealthSummaryInterval(providerHealthInterval)
+	oracle.SetProviderSilenceTimeout(providerSilenceTimeout)
+	oracle.SetProviderReconnectCooldown(reconnectCooldown)
+	oracle.SetSubscriptionReconcile(reconcileInterval, reconcileMaxAge)
+	oracle.SetPriceCache(priceCache)
+	oracle.SetAttestor(attestor)
+	oracle.SetWebhook(webhook)
+	oracle.SetStatsdExporter(statsdExporter)
+	oracle.SetVoteWarmup(voteWarmup, cfg.VoteWarmup.MinProviders)
+	oracle.SetHaltFile(cfg.HaltFile)
+	oracle.SetPrevoteFile(cfg.PrevoteFile)
+	oracle.SetVoteSafetyMargin(voteSafetyMargin)
+	oracle.SetStartupPolicy(cfg.StartupPolicy, startupTimeout, providerMins)
+	oracle.SetDynamicPairs(dynamicPairs)
+
+	var clock provider.Clock = provider.SystemClock{}
+	if deterministic {
+		logger.Warn().
+			Time("time", deterministicTime).
+			Int64("seed", deterministicSeed).
+			Msg("running in deterministic mode; do not use on a live chain")
+		clock = provider.FixedClock(deterministicTime)
+		oracle.SetDeterministic(clock, deterministicSeed)
+	}
+
+	err = oracle.Startup(ctx, cfg.StartupOrder, func(ctx context.Context) (client.OracleClient, error) {
+		var err error
+		oracleClient, err = newOracleClient(ctx, logger, cfg, keyringPass, rpcTimeout, clock)
+		return oracleClient, err
+	})
+	if err != nil {
+		return err
+	}
 
 	telemetryCfg := telemetry.Config{}
 	err = mapstructure.Decode(cfg.Telemetry, &telemetryCfg)
 	if err != nil {
 		return err
 	}
+	telemetryCfg.GlobalLabels = append(telemetryCfg.GlobalLabels, cfg.InstanceLabels()...)
 	metrics, err := telemetry.New(telemetryCfg)
 	if err != nil {
 		return err
@@ -193,12 +405,178 @@ func priceFeederCmdHandler(cmd *cobra.Command, args []string) error {
 		// start the process that calculates oracle prices and votes
 		return startPriceOracle(ctx, logger, oracle)
 	})
+	if cfg.Profiling.Enabled {
+		g.Go(func() error {
+			// start the process that serves the profiles of the price-feeder
+			return startProfilingServer(ctx, logger, cfg)
+		})
+	}
 
 	// Block main process until all spawned goroutines have gracefully exited and
 	// signal has been captured in the main process or if an error occurs.
 	return g.Wait()
 }
 
+// newOracleClient creates the chain client of the price-feeder and checks its
+// RPC, keyring, authz grants, clock skew and feeder account.
+func newOracleClient(
+	ctx context.Context,
+	logger zerolog.Logger,
+	cfg config.Config,
+	keyringPass string,
+	rpcTimeout time.Duration,
+	clock provider.Clock,
+) (client.OracleClient, error) {
+	oracleClient, err := client.NewOracleClient(
+		ctx,
+		logger,
+		cfg.Account.ChainID,
+		cfg.Keyring.Backend,
+		cfg.Keyring.Dir,
+		keyringPass,
+		cfg.RPC.TMRPCEndpoint,
+		rpcTimeout,
+		cfg.Account.Address,
+		cfg.Account.Validator,
+		cfg.RPC.GRPCEndpoint,
+		cfg.GasAdjustment,
+		cfg.Gas,
+	)
+	if err != nil {
+		return client.OracleClient{}, err
+	}
+
+	oracleClient.GRPCConn, err = client.NewGRPCConn(
+		ctx,
+		logger,
+		cfg.RPC.GRPCEndpoint,
+		oracle.GRPCDialOptions()...,
+	)
+	if err != nil {
+		return client.OracleClient{}, err
+	}
+
+	oracleClient.Memo, err = cfg.VoteMemoTemplate()
+	if err != nil {
+		return client.OracleClient{}, err
+	}
+
+	oracleClient.VoteBuilder, err = cfg.OracleVoteBuilder()
+	if err != nil {
+		return client.OracleClient{}, err
+	}
+
+	if authzBuilder, ok := oracleClient.VoteBuilder.(client.AuthzVoteBuilder); ok {
+		grantCtx, cancel := context.WithTimeout(ctx, rpcTimeout)
+		err = authzBuilder.CheckGrants(
+			grantCtx,
+			oracleClient.GRPCConn.Conn(),
+			oracleClient.OracleAddrString,
+			clock.Now(),
+		)
+		cancel()
+		if err != nil {
+			return client.OracleClient{}, err
+		}
+		logger.Info().Str("granter", authzBuilder.Granter).Msg("submitting votes through an authz grant")
+	}
+
+	maxClockSkew, err := time.ParseDuration(cfg.MaxClockSkew)
+	if err != nil {
+		return client.OracleClient{}, fmt.Errorf("failed to parse max clock skew: %w", err)
+	}
+	oracleClient.ChainHeight.SetMaxClockSkew(maxClockSkew)
+
+	clockSkew, err := oracleClient.CheckClockSkew(ctx, maxClockSkew)
+	switch {
+	case err != nil && cfg.EnforceMaxClockSkew:
+		return client.OracleClient{}, err
+	case err != nil:
+		logger.Warn().Err(err).Msg("failed to check clock skew")
+	default:
+		logger.Info().Dur("clock_skew", clockSkew).Msg("checked clock skew")
+	}
+
+	if err := oracleClient.CheckAccount(); err != nil {
+		return client.OracleClient{}, err
+	}
+
+	return oracleClient, nil
+}
+
+// getLogger returns a logger with the level and format set by the command's
+// flags. It writes to the rotated log file of the config if one is set, and
+// to stderr otherwise.
+func getLogger(cmd *cobra.Command, cfg config.Config) (zerolog.Logger, error) {
+	logLvlStr, err := cmd.Flags().GetString(flagLogLevel)
+	if err != nil {
+		return zerolog.Logger{}, err
+	}
+
+	logLvl, err := zerolog.ParseLevel(logLvlStr)
+	if err != nil {
+		return zerolog.Logger{}, err
+	}
+
+	logFormatStr, err := cmd.Flags().GetString(flagLogFormat)
+	if err != nil {
+		return zerolog.Logger{}, err
+	}
+
+	var out io.Writer = os.Stderr
+	if cfg.Log.File != "" {
+		maxAge, err := cfg.LogMaxAge()
+		if err != nil {
+			return zerolog.Logger{}, err
+		}
+		// the file stays open until the process exits
+		out, err = logrotate.NewWriter(cfg.Log.File, int64(cfg.Log.MaxSize)*1024*1024, maxAge, cfg.Log.MaxBackups)
+		if err != nil {
+			return zerolog.Logger{}, err
+		}
+	}
+
+	var logWriter io.Writer
+	switch strings.ToLower(logFormatStr) {
+	case logLevelJSON:
+		logWriter = out
+
+	case logLevelText:
+		logWriter = zerolog.ConsoleWriter{Out: out, NoColor: cfg.Log.File != ""}
+
+	default:
+		return zerolog.Logger{}, fmt.Errorf("invalid logging format: %s", logFormatStr)
+	}
+
+	return zerolog.New(logWriter).Level(logLvl).With().Timestamp().Logger(), nil
+}
+
+// getDeterministicFlags returns whether deterministic mode is enabled along
+// with its seed and fixed time.
+func getDeterministicFlags(cmd *cobra.Command) (bool, int64, time.Time, error) {
+	deterministic, err := cmd.Flags().GetBool(flagDeterministic)
+	if err != nil {
+		return false, 0, time.Time{}, err
+	}
+
+	seed, err := cmd.Flags().GetInt64(flagDeterministicSeed)
+	if err != nil {
+		return false, 0, time.Time{}, err
+	}
+
+	timeStr, err := cmd.Flags().GetString(flagDeterministicTime)
+	if err != nil {
+		return false, 0, time.Time{}, err
+	}
+
+	fixedTime, err := time.Parse(time.RFC3339, timeStr)
+	if err != nil {
+		return false, 0, time.Time{}, fmt.Errorf("failed to parse deterministic time: %w", err)
+	}
+
+	return deterministic, seed, fixedTime, nil
+}
+
 func getKeyringPassword() (string, error) {
 	reader := bufio.NewReader(os.Stdin)
 
@@ -244,6 +622,11 @@ func startPriceFeeder(
 		return err
 	}
 
+	tlsConfig, err := cfg.Server.TLSConfig()
+	if err != nil {
+		return err
+	}
+
 	srvErrCh := make(chan error, 1)
 	srv := &http.Server{
 		Handler:           rtr,
@@ -251,10 +634,20 @@ func startPriceFeeder(
 		WriteTimeout:      writeTimeout,
 		ReadTimeout:       readTimeout,
 		ReadHeaderTimeout: readTimeout,
+		TLSConfig:         tlsConfig,
 	}
 
 	go func() {
-		logger.Info().Str("listen_addr", cfg.Server.ListenAddr).Msg("starting price-feeder server...")
+		logger.Info().
+			Str("listen_addr", cfg.Server.ListenAddr).
+			Bool("tls", tlsConfig != nil).
+			Bool("client_auth", tlsConfig != nil && tlsConfig.ClientCAs != nil).
+			Msg("starting price-feeder server...")
+		if tlsConfig != nil {
+			// the certificate is loaded in the TLS config
+			srvErrCh <- srv.ListenAndServeTLS("", "")
+			return
+		}
 		srvErrCh <- srv.ListenAndServe()
 	}()
 
@@ -279,6 +672,57 @@ func startPriceFeeder(
 	}
 }
 
+// startProfilingServer serves the pprof handlers and the runtime stats on the
+// profiling listen address. The server sets no write timeout, as CPU profiles
+// and traces are written once their requested duration elapsed.
+func startProfilingServer(ctx context.Context, logger zerolog.Logger, cfg config.Config) error {
+	rtr := mux.NewRouter()
+	profiling.New(logger).RegisterRoutes(rtr)
+
+	readTimeout, err := time.ParseDuration(cfg.Server.ReadTimeout)
+	if err != nil {
+		return err
+	}
+
+	srvErrCh := make(chan error, 1)
+	srv := &http.Server{
+		Handler:           rtr,
+		Addr:              cfg.Profiling.ListenAddr,
+		ReadTimeout:       readTimeout,
+		ReadHeaderTimeout: readTimeout,
+	}
+
+	go func() {
+		if cfg.Profiling.AllowRemote {
+			logger.Warn().
+				Str("listen_addr", cfg.Profiling.ListenAddr).
+				Msg("profiling server allows remote access")
+		}
+		logger.Info().Str("listen_addr", cfg.Profiling.ListenAddr).Msg("starting profiling server...")
+		srvErrCh <- srv.ListenAndServe()
+	}()
+
+	for {
+		select {
+		case <-ctx.Done():
+			shutdownCtx, cancel := context.WithTimeout(ctx, 15*time.Second)
+			defer cancel()
+
+			logger.Info().Str("listen_addr", cfg.Profiling.ListenAddr).Msg("shutting down profiling server...")
+			if err := srv.Shutdown(shutdownCtx); err != nil {
+				logger.Error().Err(err).Msg("failed to gracefully shutdown profiling server")
+				return err
+			}
+
+			return nil
+
+		case err := <-srvErrCh:
+			logger.Error().Err(err).Msg("failed to start profiling server")
+			return err
+		}
+	}
+}
+
 func startPriceOracle(ctx context.Context, logger zerolog.Logger, oracle *oracle.Oracle) error {
 	srvErrCh := make(chan error, 1)
 
@@ -3,12 +3,21 @@ package config
 import (
 	"errors"
 	"fmt"
+	"net"
+	"net/url"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
 	"time"
 
 	"github.com/cosmos/cosmos-sdk/telemetry"
 	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/cosmos/cosmos-sdk/types/bech32"
 	"github.com/go-playground/validator/v10"
+	"github.com/rs/zerolog"
 
+	"github.com/ojo-network/price-feeder/oracle/client"
 	"github.com/ojo-network/price-feeder/oracle/provider"
 	"github.com/ojo-network/price-feeder/oracle/types"
 )
@@ -16,12 +25,119 @@ import (
 const (
 	DenomUSD = "USD"
 
+	// DefaultDeviationThreshold is the number of standard deviations a
+	// provider can be away from the mean of a base without a configured
+	// deviation before it is filtered out.
+	DefaultDeviationThreshold = "1.0"
+
+	// DefaultTwapWindow is the period of the candles aggregated into the
+	// TVWAP of a base without a configured TWAP window.
+	DefaultTwapWindow = 10 * time.Minute
+
 	defaultListenAddr      = "0.0.0.0:7171"
 	defaultSrvWriteTimeout = 15 * time.Second
 	defaultSrvReadTimeout  = 15 * time.Second
 	defaultProviderTimeout = 100 * time.Millisecond
 
+	defaultProfilingListenAddr = "127.0.0.1:6060"
+
+	defaultProviderSilenceTimeout = 5 * time.Minute
+	defaultProviderHealthInterval = 5 * time.Minute
+	defaultReconnectCooldown      = 5 * time.Second
+	defaultMaxClockSkew           = 15 * time.Second
+	defaultStartupTimeout         = 2 * time.Minute
+	defaultReconcileInterval      = time.Minute
+	defaultReconcileMaxAge        = 5 * time.Minute
+	defaultDynamicPairsRefresh    = 10 * time.Minute
+	defaultMaxBufferedCandles     = 4096
+
+	// maxTickerWindowSamples bounds the ticker samples kept for a provider
+	// pair.
+	maxTickerWindowSamples = 100
+
+	// maxOsmosisTwapWindow is the retention of the TWAP records of the
+	// Osmosis x/twap module, past which a TWAP can't be queried.
+	maxOsmosisTwapWindow = 48 * time.Hour
+
+	// maxUniswapV3FeeTier is the fee of a Uniswap v3 pool in hundredths of a
+	// bip which a fee tier must stay below.
+	maxUniswapV3FeeTier = 1000000
+
+	// maxUniswapV3TwapWindow bounds the TWAP window of a Uniswap v3 pool,
+	// well past the observations pools usually keep.
+	maxUniswapV3TwapWindow = 24 * time.Hour
+
+	// maxTwapWindow bounds the TWAP window of a currency pair, as its
+	// candles are kept in memory for the whole window.
+	maxTwapWindow = 24 * time.Hour
+
+	// minCandlesPerTwapWindow is the fewest candles of the candle interval
+	// selected for a TWAP window which fit in the window.
+	minCandlesPerTwapWindow = 5
+
+	// maxCurvePoolCoins is the most coins a Curve pool holds.
+	maxCurvePoolCoins = 8
+
+	// defaultDydxIndexer is the public dYdX v4 indexer.
+	defaultDydxIndexer = "https://indexer.dydx.trade"
+
+	defaultAttestationTimeout    = 10 * time.Second
+	defaultAttestationMaxRetries = 3
+
+	defaultStatsdPrefix        = "price_feeder"
+	defaultStatsdFlushInterval = 10 * time.Second
+
+	defaultWebhookTimeout   = 5 * time.Second
+	defaultWebhookRateLimit = 10 * time.Minute
+
+	defaultPriceCacheMaxAge        = 10 * time.Minute
+	defaultPriceCacheWriteInterval = 30 * time.Second
+	defaultLogMaxSize              = 100
+	defaultSymbolCacheTTL          = 24 * time.Hour
+	defaultSymbolFetchRetries      = 2
+
+	// MinVotePriority and MaxVotePriority bound the vote priority of a
+	// currency pair, which is DefaultVotePriority if unset.
+	MinVotePriority     = 1
+	MaxVotePriority     = 9
+	DefaultVotePriority = 5
+
+	// MaxVoteExponent bounds the vote exponent of a currency pair to the
+	// precision of the submitted decimals.
+	MaxVoteExponent = sdk.Precision
+
 	SampleNodeConfigPath = "price-feeder.example.toml"
+
+	// DuplicatePairsError fails the parsing of configs defining the same
+	// currency pair more than once.
+	DuplicatePairsError = "error"
+
+	// InstanceIDLabel and EnvironmentLabel are the telemetry global labels
+	// and log fields identifying the price-feeder instance.
+	InstanceIDLabel  = "instance_id"
+	EnvironmentLabel = "environment"
+
+	// DuplicatePairsMerge merges the providers of currency pairs defined more
+	// than once.
+	DuplicatePairsMerge = "merge"
+
+	// StartupPolicyWarn votes right after the start and warns about the
+	// assets still priced by fewer than their minimum providers once the
+	// startup timeout elapses. StartupPolicyFail holds back votes until every
+	// asset reaches its minimum providers and stops the price-feeder if they
+	// don't within the startup timeout. StartupPolicyWait holds back votes
+	// for up to the startup timeout and then votes with a warning.
+	StartupPolicyWarn = "warn"
+	StartupPolicyFail = "fail"
+	StartupPolicyWait = "wait-with-timeout"
+
+	// StartupOrderChainFirst connects the chain client and checks its RPC,
+	// keyring and account before dialing the providers, so that a broken
+	// chain setup fails fast. StartupOrderProvidersFirst dials the providers
+	// first, so that their prices are warming up while the chain client
+	// connects.
+	StartupOrderChainFirst     = "chain-first"
+	StartupOrderProvidersFirst = "providers-first"
 )
 
 var (
@@ -33,33 +149,320 @@ var (
 	// maxDeviationThreshold is the maxmimum allowed amount of standard
 	// deviations which validators are able to set for a given asset.
 	maxDeviationThreshold = sdk.MustNewDecFromStr("3.0")
+
+	// defaultAgreementBand is the fraction of the price the prices of the
+	// providers of a pair must agree within if its band is unset.
+	defaultAgreementBand = sdk.MustNewDecFromStr("0.005")
+
+	// labelValueRegex matches the instance label values which are safe to use
+	// as metric labels and log fields.
+	labelValueRegex = regexp.MustCompile(`^[a-zA-Z0-9_.:-]+$`)
+
+	// evmAddressRegex matches the hex address of an EVM contract.
+	evmAddressRegex = regexp.MustCompile(`^0x[0-9a-fA-F]{40}$`)
+
+	// dydxMarketRegex matches the ticker of a dYdX market, ex. BTC-USD.
+	dydxMarketRegex = regexp.MustCompile(`^[A-Z0-9]+-[A-Z0-9]+$`)
 )
 
 type (
 	// Config defines all necessary price-feeder configuration parameters.
 	Config struct {
-		ConfigDir           string              `mapstructure:"config_dir"`
-		Server              Server              `mapstructure:"server"`
-		CurrencyPairs       []CurrencyPair      `mapstructure:"currency_pairs" validate:"required,gt=0,dive,required"`
-		Deviations          []Deviation         `mapstructure:"deviation_thresholds"`
-		Account             Account             `mapstructure:"account" validate:"required,gt=0,dive,required"`
-		Keyring             Keyring             `mapstructure:"keyring" validate:"required,gt=0,dive,required"`
-		RPC                 RPC                 `mapstructure:"rpc" validate:"required,gt=0,dive,required"`
-		Telemetry           telemetry.Config    `mapstructure:"telemetry"`
-		GasAdjustment       float64             `mapstructure:"gas_adjustment"`
-		Gas                 uint64              `mapstructure:"gas"`
-		ProviderTimeout     string              `mapstructure:"provider_timeout"`
-		ProviderMinOverride bool                `mapstructure:"provider_min_override"`
-		ProviderEndpoints   []provider.Endpoint `mapstructure:"provider_endpoints" validate:"dive"`
-	}
-
-	// Server defines the API server configuration.
+		ConfigDir              string               `mapstructure:"config_dir"`
+		Server                 Server               `mapstructure:"server"`
+		Profiling              Profiling            `mapstructure:"profiling"`
+		CurrencyPairs          []CurrencyPair       `mapstructure:"currency_pairs" validate:"required,gt=0,dive,required"`
+		DefaultProviders       []types.ProviderName `mapstructure:"default_providers"`
+		AllowedQuotes          []string             `mapstructure:"allowed_quotes"`
+		Deviations             []Deviation          `mapstructure:"deviation_thresholds"`
+		StrictDeviations       bool                 `mapstructure:"strict_deviation_thresholds"`
+		PriceBands             []PriceBand          `mapstructure:"price_bands"`
+		TvwapWeightings        []TvwapWeighting     `mapstructure:"tvwap_weightings"`
+		LatencyThresholds      []LatencyThreshold   `mapstructure:"latency_thresholds"`
+		TickerWindows          []TickerWindow       `mapstructure:"ticker_windows"`
+		ReferencePrices        []ReferencePrice     `mapstructure:"reference_prices"`
+		AnchorPairs            []AnchorPair         `mapstructure:"anchor_pairs"`
+		ConversionRoutes       []ConversionRoute    `mapstructure:"conversion_routes"`
+		ProviderGroups         []ProviderGroup      `mapstructure:"provider_groups" validate:"dive"`
+		ZeroVolumeWeight       string               `mapstructure:"zero_volume_weight"`
+		Account                Account              `mapstructure:"account" validate:"required,gt=0,dive,required"`
+		Authz                  Authz                `mapstructure:"authz"`
+		DynamicPairs           DynamicPairs         `mapstructure:"dynamic_pairs"`
+		Keyring                Keyring              `mapstructure:"keyring" validate:"required,gt=0,dive,required"`
+		RPC                    RPC                  `mapstructure:"rpc" validate:"required,gt=0,dive,required"`
+		Telemetry              telemetry.Config     `mapstructure:"telemetry"`
+		InstanceID             string               `mapstructure:"instance_id"`
+		Environment            string               `mapstructure:"environment"`
+		VoteMemo               string               `mapstructure:"vote_memo"`
+		VoteBuilder            string               `mapstructure:"vote_builder"`
+		MaxVoteSize            int                  `mapstructure:"max_vote_size"`
+		GasAdjustment          float64              `mapstructure:"gas_adjustment"`
+		Gas                    uint64               `mapstructure:"gas"`
+		ProviderTimeout        string               `mapstructure:"provider_timeout"`
+		ProviderSilenceTimeout string               `mapstructure:"provider_silence_timeout"`
+		MaxBufferedCandles     int                  `mapstructure:"max_buffered_candles"`
+		ProviderHealthInterval string               `mapstructure:"provider_health_interval"`
+		ReconnectCooldown      string               `mapstructure:"reconnect_cooldown"`
+		MaxClockSkew           string               `mapstructure:"max_clock_skew"`
+		EnforceMaxClockSkew    bool                 `mapstructure:"enforce_max_clock_skew"`
+		VoteSafetyMargin       string               `mapstructure:"vote_safety_margin"`
+		ProviderMinOverride    bool                 `mapstructure:"provider_min_override"`
+		StartupPolicy          string               `mapstructure:"startup_policy"`
+		StartupTimeout         string               `mapstructure:"startup_timeout"`
+		StartupOrder           string               `mapstructure:"startup_order"`
+		ProviderEndpoints      []provider.Endpoint  `mapstructure:"provider_endpoints" validate:"dive"`
+		PriceCache             PriceCache           `mapstructure:"price_cache"`
+		SymbolCache            SymbolCache          `mapstructure:"symbol_cache"`
+		Log                    Log                  `mapstructure:"log"`
+		Chainlink              Chainlink            `mapstructure:"chainlink"`
+		OsmosisTwap            OsmosisTwap          `mapstructure:"osmosis_twap"`
+		UniswapV3              UniswapV3            `mapstructure:"uniswap_v3"`
+		Curve                  Curve                `mapstructure:"curve"`
+		Dydx                   Dydx                 `mapstructure:"dydx"`
+		RedemptionRate         RedemptionRate       `mapstructure:"redemption_rate"`
+		Attestation            Attestation          `mapstructure:"attestation"`
+		Statsd                 Statsd               `mapstructure:"statsd"`
+		Webhook                Webhook              `mapstructure:"webhook"`
+		VoteWarmup             VoteWarmup           `mapstructure:"vote_warmup"`
+		SubscriptionReconcile  Reconciliation       `mapstructure:"subscription_reconcile"`
+		DuplicatePairs         string               `mapstructure:"duplicate_pairs"`
+		HaltFile               string               `mapstructure:"halt_file"`
+		PrevoteFile            string               `mapstructure:"prevote_file"`
+
+		// mergedPairs holds the currency pairs merged from duplicate
+		// definitions while parsing the configs.
+		mergedPairs []mergedPair
+	}
+
+	// PriceCache defines the optional on-disk cache of prices and candles the
+	// oracle warms up from after a restart. The cache is disabled if no path
+	// is set.
+	PriceCache struct {
+		Path          string `mapstructure:"path"`
+		MaxAge        string `mapstructure:"max_age"`
+		WriteInterval string `mapstructure:"write_interval"`
+	}
+
+	// SymbolCache defines the optional on-disk cache of the pairs available on
+	// the providers, which their pairs are confirmed against if fetching the
+	// live list fails after MaxRetries retries. A cached list older than TTL
+	// is not used. The cache is disabled if no path is set.
+	SymbolCache struct {
+		Path       string `mapstructure:"path"`
+		TTL        string `mapstructure:"ttl"`
+		MaxRetries int    `mapstructure:"max_retries"`
+	}
+
+	// Attestation defines the optional external collector the aggregated
+	// prices of every voting period are posted to, signed with the feeder key.
+	// A post times out after Timeout and is retried up to MaxRetries times.
+	// Attestations are disabled if no URL is set.
+	Attestation struct {
+		URL        string `mapstructure:"url"`
+		AuthToken  string `mapstructure:"auth_token"`
+		Timeout    string `mapstructure:"timeout"`
+		MaxRetries int    `mapstructure:"max_retries"`
+	}
+
+	// Statsd defines the optional StatsD server, such as the Datadog agent,
+	// the aggregated prices and provider health are exported to every
+	// FlushInterval, with their names prefixed by Prefix. The export is
+	// disabled if no Address is set.
+	Statsd struct {
+		Address       string `mapstructure:"address"`
+		Prefix        string `mapstructure:"prefix"`
+		FlushInterval string `mapstructure:"flush_interval"`
+	}
+
+	// Webhook defines the optional URL alerts are posted to as JSON once per
+	// voting period: when the aggregated price of a pair moves by more than
+	// PriceChange, a fraction of the price, from the previous voting period,
+	// and, if enabled, when a pair is omitted or outside of its price band.
+	// An alert of a pair and kind is posted at most once per RateLimit, and a
+	// post times out after Timeout. Alerts are disabled if no URL is set.
+	Webhook struct {
+		URL             string `mapstructure:"url"`
+		PriceChange     string `mapstructure:"price_change"`
+		AlertOmissions  bool   `mapstructure:"alert_omissions"`
+		AlertPriceBands bool   `mapstructure:"alert_price_bands"`
+		RateLimit       string `mapstructure:"rate_limit"`
+		Timeout         string `mapstructure:"timeout"`
+	}
+
+	// Log defines the optional file the logs are written to instead of
+	// stderr. The file is rotated once it reaches MaxSize megabytes, and the
+	// rotated files are removed once older than MaxAge or beyond the
+	// MaxBackups most recent. Rotated files are kept if MaxAge or MaxBackups
+	// is not set.
+	Log struct {
+		File       string `mapstructure:"file"`
+		MaxSize    int    `mapstructure:"max_size"`
+		MaxAge     string `mapstructure:"max_age"`
+		MaxBackups int    `mapstructure:"max_backups"`
+	}
+
+	// Chainlink defines the EVM JSON-RPC endpoint the chainlink provider
+	// reads the aggregator contracts of its feeds from. A round is stale
+	// once it was last updated longer ago than MaxRoundAge, which a feed may
+	// override to match the heartbeat of its aggregator.
+	Chainlink struct {
+		RPC         string          `mapstructure:"rpc"`
+		MaxRoundAge string          `mapstructure:"max_round_age"`
+		Feeds       []ChainlinkFeed `mapstructure:"feeds" validate:"dive"`
+	}
+
+	// ChainlinkFeed defines the aggregator contract of a currency pair.
+	ChainlinkFeed struct {
+		Base        string `mapstructure:"base" validate:"required"`
+		Quote       string `mapstructure:"quote" validate:"required"`
+		Address     string `mapstructure:"address" validate:"required"`
+		MaxRoundAge string `mapstructure:"max_round_age"`
+	}
+
+	// OsmosisTwap defines the Osmosis node REST endpoint the osmosis-twap
+	// provider queries the arithmetic TWAP of pools from. The TWAP of a pool
+	// is computed over its own Window, or else the section's.
+	OsmosisTwap struct {
+		LCD    string            `mapstructure:"lcd"`
+		Window string            `mapstructure:"window"`
+		Pools  []OsmosisTwapPool `mapstructure:"pools" validate:"dive"`
+	}
+
+	// OsmosisTwapPool defines the pool of a currency pair, the denoms of its
+	// base and quote assets in the pool and their decimals.
+	OsmosisTwapPool struct {
+		Base          string `mapstructure:"base" validate:"required"`
+		Quote         string `mapstructure:"quote" validate:"required"`
+		PoolID        uint64 `mapstructure:"pool_id" validate:"required"`
+		BaseDenom     string `mapstructure:"base_denom" validate:"required"`
+		QuoteDenom    string `mapstructure:"quote_denom" validate:"required"`
+		BaseDecimals  int    `mapstructure:"base_decimals"`
+		QuoteDecimals int    `mapstructure:"quote_decimals"`
+		Window        string `mapstructure:"window"`
+	}
+
+	// UniswapV3 defines the EVM JSON-RPC endpoint the uniswap-v3 provider
+	// reads the pools of its pairs from. A pool is priced by the TWAP of its
+	// oracle observations over its own TwapWindow, or else the section's, and
+	// by its spot price when neither is set.
+	UniswapV3 struct {
+		RPC        string          `mapstructure:"rpc"`
+		TwapWindow string          `mapstructure:"twap_window"`
+		Pools      []UniswapV3Pool `mapstructure:"pools" validate:"dive"`
+	}
+
+	// UniswapV3Pool defines the pool of a currency pair, the address of the
+	// pool's token that is the base asset and the pool's fee tier.
+	UniswapV3Pool struct {
+		Base       string `mapstructure:"base" validate:"required"`
+		Quote      string `mapstructure:"quote" validate:"required"`
+		Address    string `mapstructure:"address" validate:"required"`
+		BaseToken  string `mapstructure:"base_token" validate:"required"`
+		FeeTier    uint32 `mapstructure:"fee_tier" validate:"required"`
+		TwapWindow string `mapstructure:"twap_window"`
+	}
+
+	// Curve defines the EVM JSON-RPC endpoint the curve provider reads the
+	// pools of its pairs from.
+	Curve struct {
+		RPC   string      `mapstructure:"rpc"`
+		Pools []CurvePool `mapstructure:"pools" validate:"dive"`
+	}
+
+	// Dydx defines the dYdX v4 indexer the dydx provider polls the oracle
+	// prices of the markets of its pairs from, every PollInterval. The
+	// public indexer is used if Indexer is not set.
+	Dydx struct {
+		Indexer      string       `mapstructure:"indexer"`
+		PollInterval string       `mapstructure:"poll_interval"`
+		Markets      []DydxMarket `mapstructure:"markets" validate:"dive"`
+	}
+
+	// DydxMarket defines the dYdX market ticker of a currency pair.
+	DydxMarket struct {
+		Base   string `mapstructure:"base" validate:"required"`
+		Quote  string `mapstructure:"quote" validate:"required"`
+		Market string `mapstructure:"market" validate:"required"`
+	}
+
+	// RedemptionRate defines the liquid staking assets the redemption-rate
+	// provider polls the redemption rate of every PollInterval. The USD rate
+	// of an asset is its redemption rate times the USD rate of its underlying
+	// asset, and it is not priced once its rate is older than MaxAge.
+	RedemptionRate struct {
+		PollInterval string                `mapstructure:"poll_interval"`
+		MaxAge       string                `mapstructure:"max_age"`
+		Assets       []RedemptionRateAsset `mapstructure:"assets" validate:"dive"`
+	}
+
+	// RedemptionRateAsset defines the underlying asset of a liquid staking
+	// asset and the REST query of its redemption rate, being the dot separated
+	// Field of the JSON response of the Path of the Rest endpoint of its chain.
+	RedemptionRateAsset struct {
+		Base       string `mapstructure:"base" validate:"required"`
+		Underlying string `mapstructure:"underlying" validate:"required"`
+		Rest       string `mapstructure:"rest" validate:"required"`
+		Path       string `mapstructure:"path" validate:"required"`
+		Field      string `mapstructure:"field" validate:"required"`
+	}
+
+	// CurvePool defines the pool of a currency pair and the indices of its
+	// base and quote coins in the pool.
+	CurvePool struct {
+		Base       string `mapstructure:"base" validate:"required"`
+		Quote      string `mapstructure:"quote" validate:"required"`
+		Address    string `mapstructure:"address" validate:"required"`
+		BaseIndex  int    `mapstructure:"base_index"`
+		QuoteIndex int    `mapstructure:"quote_index"`
+	}
+
+	// VoteWarmup defines the warm-up after the start during which prices are
+	// computed but no vote is submitted. It lasts at least Duration and until
+	// every asset is priced by at least MinProviders providers.
+	VoteWarmup struct {
+		Duration     string `mapstructure:"duration"`
+		MinProviders int    `mapstructure:"min_providers"`
+	}
+
+	// Reconciliation defines how often the pairs expected from each provider
+	// are reconciled with the pairs it recently received market data of. The
+	// pairs which received none for MaxAge are subscribed to again. A zero
+	// Interval disables the reconciliation.
+	Reconciliation struct {
+		Interval string `mapstructure:"interval"`
+		MaxAge   string `mapstructure:"max_age"`
+	}
+
+	// Server defines the API server configuration. The server serves TLS
+	// if TLSCertFile and TLSKeyFile are set, and requires client
+	// certificates signed by TLSClientCAFile if it is set too. In SafeMode,
+	// the prices API also serves the last aggregated price of the pairs
+	// without a fresh price, flagged as stale, unless it is older than
+	// SafeModeMaxAge. PairControl serves the endpoints pausing and resuming
+	// the votes of single pairs, only on a loopback address or behind mTLS.
 	Server struct {
-		ListenAddr     string   `mapstructure:"listen_addr"`
-		WriteTimeout   string   `mapstructure:"write_timeout"`
-		ReadTimeout    string   `mapstructure:"read_timeout"`
-		VerboseCORS    bool     `mapstructure:"verbose_cors"`
-		AllowedOrigins []string `mapstructure:"allowed_origins"`
+		ListenAddr      string   `mapstructure:"listen_addr"`
+		WriteTimeout    string   `mapstructure:"write_timeout"`
+		ReadTimeout     string   `mapstructure:"read_timeout"`
+		VerboseCORS     bool     `mapstructure:"verbose_cors"`
+		AllowedOrigins  []string `mapstructure:"allowed_origins"`
+		TLSCertFile     string   `mapstructure:"tls_cert_file"`
+		TLSKeyFile      string   `mapstructure:"tls_key_file"`
+		TLSClientCAFile string   `mapstructure:"tls_client_ca_file"`
+		SafeMode        bool     `mapstructure:"safe_mode"`
+		SafeModeMaxAge  string   `mapstructure:"safe_mode_max_age"`
+		PairControl     bool     `mapstructure:"pair_control"`
+	}
+
+	// Profiling defines the optional profiling server, serving the standard
+	// net/http/pprof handlers and the runtime stats of the process on its own
+	// ListenAddr, apart from the API server. It exposes the internals of the
+	// process, so it is disabled by default and its ListenAddr must be a
+	// loopback address unless AllowRemote is set.
+	Profiling struct {
+		Enabled     bool   `mapstructure:"enabled"`
+		ListenAddr  string `mapstructure:"listen_addr"`
+		AllowRemote bool   `mapstructure:"allow_remote"`
 	}
 
 	// CurrencyPair defines a price quote of the exchange rate for two different
@@ -69,6 +472,92 @@ type (
 		Quote       string                `mapstructure:"quote" validate:"required"`
 		PairAddress []PairAddressProvider `mapstructure:"pair_address_providers" validate:"dive"`
 		Providers   []types.ProviderName  `mapstructure:"providers" validate:"required,gt=0,dive,required"`
+
+		// TickerOnlyProviders and CandleOnlyProviders restrict providers of
+		// the pair to the VWAP aggregate of their tickers or to the TVWAP
+		// aggregate of their candles.
+		TickerOnlyProviders []types.ProviderName `mapstructure:"ticker_only_providers" validate:"dive,required"`
+		CandleOnlyProviders []types.ProviderName `mapstructure:"candle_only_providers" validate:"dive,required"`
+
+		// CandleSpotProviders use the close of their latest candle of the pair
+		// rather than their ticker price as their spot price, the single
+		// price they contribute to the spot median and provider filters.
+		CandleSpotProviders []types.ProviderName `mapstructure:"candle_spot_providers" validate:"dive,required"`
+
+		// MedianTieBreak takes the price of that provider, rather than the
+		// average of the two middle prices, as the median of an even number
+		// of providers of the pair's base if it is one of the two. The two
+		// middle prices are averaged by default.
+		MedianTieBreak types.ProviderName `mapstructure:"median_tie_break"`
+
+		// MinAgreeingProviders requires the prices of at least that many
+		// providers of the pair to agree within AgreementBand, a fraction of
+		// the price, for the pair to be used.
+		MinAgreeingProviders int    `mapstructure:"min_agreeing_providers"`
+		AgreementBand        string `mapstructure:"agreement_band"`
+
+		// RequiredProviders must all price the pair for it to be used, which
+		// is omitted otherwise however many other providers price it.
+		RequiredProviders []types.ProviderName `mapstructure:"required_providers" validate:"dive,required"`
+
+		// MinProviderGroups is the number of distinct provider groups which
+		// must price the pair for it to be used, a provider outside of the
+		// provider_groups being its own group. It is not enforced if zero.
+		MinProviderGroups int `mapstructure:"min_provider_groups"`
+
+		// MaxProviders trims the providers pricing the pair to that count
+		// before it is aggregated, dropping the provider furthest from the
+		// median first. All providers are aggregated by default.
+		MaxProviders int `mapstructure:"max_providers"`
+
+		// VotePriority ranks the pair's base when the vote is trimmed to fit
+		// MaxVoteSize, the lowest priorities being dropped first.
+		VotePriority int `mapstructure:"vote_priority"`
+
+		// VoteExponent scales the price of the pair's base by 10^VoteExponent
+		// in the vote, for chains expecting prices of the base unit of the
+		// denom, ex. 6 for micro-units. Prices are aggregated in human units.
+		VoteExponent int `mapstructure:"vote_exponent"`
+
+		// VoteRounding rounds the vote value of the pair's base to
+		// VoteDecimals decimals with the given mode, ex. down to never
+		// overstate a collateral price. Vote values are submitted at full
+		// precision by default.
+		VoteRounding string `mapstructure:"vote_rounding"`
+		VoteDecimals int    `mapstructure:"vote_decimals"`
+
+		// SpotOnly aggregates the pair's base from the spot prices of its
+		// providers only, as their median without TWAP smoothing, for fast
+		// moving assets whose TWAP lags real moves. TWAP is used by default.
+		SpotOnly bool `mapstructure:"spot_only"`
+
+		// MaxVoteChange limits the move of the voted price of the pair's
+		// base per voting period to that fraction of its previously voted
+		// price, ex. 0.05 for 5%. Voted prices are not limited by default.
+		MaxVoteChange string `mapstructure:"max_vote_change"`
+
+		// MaxForexAge excludes the pair, quoted in a forex currency, from the
+		// USD price of its base while the USD rate of its quote is older than
+		// that duration, even if the price of the pair is fresh.
+		MaxForexAge string `mapstructure:"max_forex_age"`
+
+		// MinBookDepth scales down the weight of a provider of the pair
+		// whose order book depth, in the quote, is below that value, in
+		// proportion to it. It applies to the providers measuring their
+		// order book depth only.
+		MinBookDepth string `mapstructure:"min_book_depth"`
+
+		// MaxSpread excludes the price of the pair from a provider whose
+		// spread between the best bid and ask, as a fraction of their mid
+		// price, is above that value, ex. 0.01 for 1%. It applies to the
+		// providers streaming the best quote of their pairs only.
+		MaxSpread string `mapstructure:"max_spread"`
+
+		// TwapWindow sets the period of the candles of the pair's base
+		// aggregated into its TVWAP. The candle intervals of the providers
+		// supporting several of them are selected to fit the window, unless
+		// their endpoint sets its candle intervals.
+		TwapWindow string `mapstructure:"twap_window"`
 	}
 
 	PairAddressProvider struct {
@@ -83,6 +572,79 @@ type (
 		Threshold string `mapstructure:"threshold" validate:"required"`
 	}
 
+	// PriceBand defines the absolute range of USD prices that a given asset
+	// must be within to be aggregated and voted.
+	PriceBand struct {
+		Base string `mapstructure:"base" validate:"required"`
+		Min  string `mapstructure:"min"`
+		Max  string `mapstructure:"max"`
+	}
+
+	// TvwapWeighting defines how the candles of a given asset are weighted by
+	// their age in its TVWAP, and how the TVWAPs of its providers are
+	// combined. HalfLife only applies to the exponential mode and
+	// ProviderWeights, by provider name, to the weighted combination.
+	TvwapWeighting struct {
+		Base            string            `mapstructure:"base" validate:"required"`
+		Mode            string            `mapstructure:"mode" validate:"required"`
+		HalfLife        string            `mapstructure:"half_life"`
+		Combination     string            `mapstructure:"combination"`
+		ProviderWeights map[string]string `mapstructure:"provider_weights"`
+	}
+
+	// LatencyThreshold defines the weight the volume of a provider is scaled
+	// by once its newest candle lags the clock by more than Latency. A zero
+	// weight excludes the provider.
+	LatencyThreshold struct {
+		Latency string `mapstructure:"latency" validate:"required"`
+		Weight  string `mapstructure:"weight" validate:"required"`
+	}
+
+	// TickerWindow defines the number of recent ticker samples of a provider
+	// for a given currency pair whose median is used as its ticker price.
+	TickerWindow struct {
+		Provider types.ProviderName `mapstructure:"provider" validate:"required"`
+		Base     string             `mapstructure:"base" validate:"required"`
+		Quote    string             `mapstructure:"quote" validate:"required"`
+		Samples  int                `mapstructure:"samples" validate:"required"`
+	}
+
+	// ReferencePrice defines a trusted provider pair the aggregated USD price
+	// of a given asset is compared to without being used in the vote.
+	// Threshold is the percent divergence past which a warning is logged.
+	ReferencePrice struct {
+		Base      string             `mapstructure:"base" validate:"required"`
+		Quote     string             `mapstructure:"quote" validate:"required"`
+		Provider  types.ProviderName `mapstructure:"provider" validate:"required"`
+		Threshold string             `mapstructure:"threshold" validate:"required"`
+	}
+
+	// AnchorPair defines the stricter aggregation of the USD rate of a given
+	// stablecoin, which anchors the conversion of the pairs quoted in it.
+	// Deviation and Band are optional.
+	AnchorPair struct {
+		Base         string `mapstructure:"base" validate:"required"`
+		MinProviders int    `mapstructure:"min_providers"`
+		Deviation    string `mapstructure:"deviation"`
+		Band         string `mapstructure:"band"`
+	}
+
+	// ConversionRoute defines the quotes, in order of preference, the USD rate
+	// of a given asset is converted from. The pairs quoted in the first quote
+	// which yields a rate are used.
+	ConversionRoute struct {
+		Base   string   `mapstructure:"base" validate:"required"`
+		Quotes []string `mapstructure:"quotes" validate:"required"`
+	}
+
+	// ProviderGroup defines providers which share an upstream data source, ex.
+	// aggregators of the same market, and so count as a single independent
+	// source toward the min_provider_groups of a pair.
+	ProviderGroup struct {
+		Name      string               `mapstructure:"name" validate:"required"`
+		Providers []types.ProviderName `mapstructure:"providers" validate:"required"`
+	}
+
 	// Account defines account related configuration that is related to the Ojo
 	// network and transaction signing functionality.
 	Account struct {
@@ -91,6 +653,27 @@ type (
 		Validator string `mapstructure:"validator" validate:"required"`
 	}
 
+	// Authz defines whether the account submits the votes of the granter, the
+	// validator's feeder, through an authz grant of the granter, instead of
+	// being the validator's feeder itself.
+	Authz struct {
+		Enabled bool   `mapstructure:"enabled"`
+		Granter string `mapstructure:"granter"`
+	}
+
+	// DynamicPairs defines the currency pairs derived from the accept list of
+	// the x/oracle params, refreshed every RefreshInterval, so that the
+	// denoms added by governance are priced without editing the currency
+	// pairs. Each accepted denom without a configured currency pair is priced
+	// by Providers against its quote in Quotes, by base, or else Quote.
+	DynamicPairs struct {
+		Enabled         bool                 `mapstructure:"enabled"`
+		Providers       []types.ProviderName `mapstructure:"providers"`
+		Quote           string               `mapstructure:"quote"`
+		Quotes          map[string]string    `mapstructure:"quotes"`
+		RefreshInterval string               `mapstructure:"refresh_interval"`
+	}
+
 	// Keyring defines the required Ojo keyring configuration.
 	Keyring struct {
 		Backend string `mapstructure:"backend" validate:"required"`
@@ -118,7 +701,19 @@ func telemetryValidation(sl validator.StructLevel) {
 func endpointValidation(sl validator.StructLevel) {
 	endpoint := sl.Current().Interface().(provider.Endpoint)
 
-	if len(endpoint.Name) < 1 || len(endpoint.Rest) < 1 || len(endpoint.Websocket) < 1 {
+	// the injective, jupiter, chainlink, osmosis-twap, uniswap-v3, curve,
+	// dydx and redemption-rate providers poll their REST endpoint and have no
+	// websocket endpoint
+	hasWebsocket := len(endpoint.Websocket) > 0 ||
+		endpoint.Name == provider.ProviderInjective ||
+		endpoint.Name == provider.ProviderJupiter ||
+		endpoint.Name == provider.ProviderChainlink ||
+		endpoint.Name == provider.ProviderOsmosisTwap ||
+		endpoint.Name == provider.ProviderUniswapV3 ||
+		endpoint.Name == provider.ProviderCurve ||
+		endpoint.Name == provider.ProviderDydx ||
+		endpoint.Name == provider.ProviderRedemptionRate
+	if len(endpoint.Name) < 1 || len(endpoint.Rest) < 1 || !hasWebsocket {
 		sl.ReportError(endpoint, "endpoint", "Endpoint", "unsupportedEndpointType", "")
 	}
 	if _, ok := SupportedProviders[endpoint.Name]; !ok {
@@ -139,21 +734,221 @@ func hasAPIKey(endpointName types.ProviderName, endpoints []provider.Endpoint) b
 
 // Validate returns an error if the Config object is invalid.
 func (c Config) Validate() (err error) {
+	if err = c.validateAllowedQuotes(); err != nil {
+		return err
+	}
 	if err = c.validateCurrencyPairs(); err != nil {
 		return err
 	}
+	if err = c.validateEndpointProviders(); err != nil {
+		return err
+	}
+	if err = c.validateServerTLS(); err != nil {
+		return err
+	}
+	if err = c.validateServerSafeMode(); err != nil {
+		return err
+	}
+	if err = c.validateServerPairControl(); err != nil {
+		return err
+	}
+	if err = c.validateProfiling(); err != nil {
+		return err
+	}
 	if err = c.validateDeviations(); err != nil {
 		return err
 	}
 	if err = c.validateGas(); err != nil {
 		return err
 	}
+	if err = c.validateVotePriorities(); err != nil {
+		return err
+	}
+	if err = c.validateMaxBufferedCandles(); err != nil {
+		return err
+	}
+	if err = c.validateVoteExponents(); err != nil {
+		return err
+	}
+	if err = c.validateVoteRoundings(); err != nil {
+		return err
+	}
+	if err = c.validateSpotOnly(); err != nil {
+		return err
+	}
+	if err = c.validateMedianTieBreaks(); err != nil {
+		return err
+	}
+	if _, err = c.MaxVoteChanges(); err != nil {
+		return err
+	}
+	if _, err = c.MaxForexAges(); err != nil {
+		return err
+	}
+	if err = c.validatePriceBands(); err != nil {
+		return err
+	}
+	if err = c.validateTvwapWeightings(); err != nil {
+		return err
+	}
+	if _, err = c.LatencyThresholdsSlice(); err != nil {
+		return err
+	}
+	if err = c.validateTickerWindows(); err != nil {
+		return err
+	}
+	if err = c.validateReferencePrices(); err != nil {
+		return err
+	}
+	if err = c.validateAnchorPairs(); err != nil {
+		return err
+	}
+	if err = c.validateConversionRoutes(); err != nil {
+		return err
+	}
+	if err = c.validateProviderGroups(); err != nil {
+		return err
+	}
+	if err = c.validatePriceTypes(); err != nil {
+		return err
+	}
+	if err = c.validatePriceSources(); err != nil {
+		return err
+	}
+	if err = c.validateCandleIntervals(); err != nil {
+		return err
+	}
+	if err = c.validateZeroVolumeWeight(); err != nil {
+		return err
+	}
+	if err = c.validateMaxClockSkew(); err != nil {
+		return err
+	}
+	if err = c.validateVoteSafetyMargin(); err != nil {
+		return err
+	}
+	if err = c.validateProviderHealthInterval(); err != nil {
+		return err
+	}
+	if err = c.validateReconnectCooldown(); err != nil {
+		return err
+	}
+	if err = c.validateSubscriptionReconcile(); err != nil {
+		return err
+	}
+	if err = c.validateStartupPolicy(); err != nil {
+		return err
+	}
+	if err = c.validateStartupOrder(); err != nil {
+		return err
+	}
+	if err = c.validatePriceCache(); err != nil {
+		return err
+	}
+	if err = c.validateSymbolCache(); err != nil {
+		return err
+	}
+	if err = c.validateChainlink(); err != nil {
+		return err
+	}
+	if err = c.validateOsmosisTwap(); err != nil {
+		return err
+	}
+	if err = c.validateUniswapV3(); err != nil {
+		return err
+	}
+	if err = c.validateCurve(); err != nil {
+		return err
+	}
+	if err = c.validateDydx(); err != nil {
+		return err
+	}
+	if err = c.validateRedemptionRate(); err != nil {
+		return err
+	}
+	if err = c.validateAttestation(); err != nil {
+		return err
+	}
+	if err = c.validateStatsd(); err != nil {
+		return err
+	}
+	if err = c.validateWebhook(); err != nil {
+		return err
+	}
+	if err = c.validateLog(); err != nil {
+		return err
+	}
+	if err = c.validateVoteWarmup(); err != nil {
+		return err
+	}
+	if err = c.validateAuthz(); err != nil {
+		return err
+	}
+	if err = c.validateDynamicPairs(); err != nil {
+		return err
+	}
+	if err = c.validateProviderTLS(); err != nil {
+		return err
+	}
+	if err = c.validateProviderHeaders(); err != nil {
+		return err
+	}
+	if err = c.validateSubscriptionBatching(); err != nil {
+		return err
+	}
+	if err = c.validateCompression(); err != nil {
+		return err
+	}
+	if err = c.validateCandleGaps(); err != nil {
+		return err
+	}
+	if err = c.validateOrderBookDepths(); err != nil {
+		return err
+	}
+	if err = c.validateTickerReorderWindows(); err != nil {
+		return err
+	}
+	if err = c.validateCombinedStreams(); err != nil {
+		return err
+	}
+	if _, err = c.MinBookDepths(); err != nil {
+		return err
+	}
+	if err = c.validateMaxSpreads(); err != nil {
+		return err
+	}
+	if _, err = c.TwapWindows(); err != nil {
+		return err
+	}
+	if err = c.validateDuplicatePairs(); err != nil {
+		return err
+	}
+	if err = c.validateInstanceLabels(); err != nil {
+		return err
+	}
+	if _, err = c.VoteMemoTemplate(); err != nil {
+		return err
+	}
+	if _, err = c.OracleVoteBuilder(); err != nil {
+		return err
+	}
 
 	validate.RegisterStructValidation(telemetryValidation, telemetry.Config{})
 	validate.RegisterStructValidation(endpointValidation, provider.Endpoint{})
 	return validate.Struct(c)
 }
 
+// validateEndpointProviders returns a descriptive error for an endpoint of an
+// unsupported provider, ahead of the struct validation which rejects it too.
+func (c Config) validateEndpointProviders() error {
+	for _, endpoint := range c.ProviderEndpoints {
+		if _, ok := SupportedProviders[endpoint.Name]; !ok {
+			return unsupportedProviderError("endpoint provider", endpoint.Name)
+		}
+	}
+	return nil
+}
+
 func (c Config) validateDeviations() error {
 	for _, deviation := range c.Deviations {
 		threshold, err := sdk.NewDecFromStr(deviation.Threshold)
@@ -165,121 +960,2848 @@ func (c Config) validateDeviations() error {
 			return fmt.Errorf("deviation thresholds must not exceed 3.0")
 		}
 	}
+
+	if c.StrictDeviations {
+		if orphaned := c.OrphanedDeviationBases(); len(orphaned) > 0 {
+			return fmt.Errorf("deviation thresholds of %v match no currency pair base", orphaned)
+		}
+	}
 	return nil
 }
 
-func (c Config) validateGas() error {
-	if c.Gas <= 0 && c.GasAdjustment <= 0 {
-		return fmt.Errorf("gas or gas adjustment must be set")
+// OrphanedDeviationBases returns the bases of the deviation thresholds which
+// match no currency pair base, and so have no effect.
+func (c Config) OrphanedDeviationBases() []string {
+	bases := make(map[string]struct{}, len(c.CurrencyPairs))
+	for _, cp := range c.CurrencyPairs {
+		bases[cp.Base] = struct{}{}
 	}
-	if c.GasAdjustment > 0 && c.Gas > 0 {
-		return fmt.Errorf("gas and gas adjustment may not both be set")
+
+	var orphaned []string
+	for _, deviation := range c.Deviations {
+		if _, ok := bases[deviation.Base]; !ok {
+			orphaned = append(orphaned, deviation.Base)
+		}
 	}
-	return nil
+	return orphaned
 }
 
-func (c Config) validateCurrencyPairs() error {
-OUTER:
+// LogOrphanedDeviations logs the deviation thresholds which match no currency
+// pair base. They are rejected when the config is loaded if
+// strict_deviation_thresholds is set.
+func (c Config) LogOrphanedDeviations(logger zerolog.Logger) {
+	for _, base := range c.OrphanedDeviationBases() {
+		logger.Warn().
+			Str("base", base).
+			Msg("deviation threshold matches no currency pair base and has no effect")
+	}
+}
+
+// PairPlan defines how a currency pair is priced, as resolved from the config
+// once the default providers are set and duplicate pairs merged, and from the
+// provider minimums checked at startup. A zero MinProviders or MaxProviders
+// is not enforced.
+type PairPlan struct {
+	Pair                string               `json:"pair"`
+	Providers           []types.ProviderName `json:"providers"`
+	TickerOnlyProviders []types.ProviderName `json:"ticker_only_providers,omitempty"`
+	CandleOnlyProviders []types.ProviderName `json:"candle_only_providers,omitempty"`
+	RequiredProviders   []types.ProviderName `json:"required_providers,omitempty"`
+	MinProviders        int                  `json:"min_providers"`
+	MinProviderGroups   int                  `json:"min_provider_groups,omitempty"`
+	MaxProviders        int                  `json:"max_providers,omitempty"`
+	Deviation           string               `json:"deviation"`
+	TwapWindow          string               `json:"twap_window"`
+	SpotOnly            bool                 `json:"spot_only,omitempty"`
+	ConversionRoute     []string             `json:"conversion_route,omitempty"`
+	RedemptionRate      string               `json:"redemption_rate_underlying,omitempty"`
+}
+
+// StartupPlan returns the plan of every currency pair, in the order of the
+// config, with the given provider minimums by base. The bases without a
+// deviation threshold or TWAP window have the defaults of the oracle.
+func (c Config) StartupPlan(providerMins map[string]int) []PairPlan {
+	deviations := make(map[string]string, len(c.Deviations))
+	for _, deviation := range c.Deviations {
+		deviations[deviation.Base] = deviation.Threshold
+	}
+	windows, _ := c.TwapWindows()
+	spotOnlyBases := c.SpotOnlyBases()
+	conversionRoutes := c.ConversionRoutesMap()
+	underlyings := c.RedemptionRateUnderlyings()
+
+	plans := make([]PairPlan, 0, len(c.CurrencyPairs))
 	for _, cp := range c.CurrencyPairs {
-		if cp.Base == "" {
-			return fmt.Errorf("currency pair base cannot be empty")
+		plan := PairPlan{
+			Pair:                types.CurrencyPair{Base: cp.Base, Quote: cp.Quote}.String(),
+			Providers:           cp.Providers,
+			TickerOnlyProviders: cp.TickerOnlyProviders,
+			CandleOnlyProviders: cp.CandleOnlyProviders,
+			RequiredProviders:   cp.RequiredProviders,
+			MinProviders:        providerMins[cp.Base],
+			MinProviderGroups:   cp.MinProviderGroups,
+			MaxProviders:        cp.MaxProviders,
+			Deviation:           DefaultDeviationThreshold,
+			TwapWindow:          DefaultTwapWindow.String(),
+			ConversionRoute:     conversionRoutes[cp.Base],
+			RedemptionRate:      underlyings[cp.Base],
 		}
-		if cp.Quote == "" {
-			return fmt.Errorf("currency pair quote cannot be empty")
+		if deviation, ok := deviations[cp.Base]; ok {
+			plan.Deviation = deviation
 		}
-		if cp.Base == cp.Quote {
-			return fmt.Errorf("currency pair base and quote cannot be the same")
+		if window, ok := windows[cp.Base]; ok {
+			plan.TwapWindow = window.String()
 		}
-		if len(cp.Providers) == 0 {
-			return fmt.Errorf("currency pair must have at least one provider")
+		if _, ok := spotOnlyBases[cp.Base]; ok {
+			plan.SpotOnly = true
 		}
-		for _, prov := range cp.Providers {
+		plans = append(plans, plan)
+	}
+	return plans
+}
+
+// LogStartupPlan logs the plan of every currency pair once, as a single
+// summary of what the price-feeder will price and how.
+func (c Config) LogStartupPlan(logger zerolog.Logger, providerMins map[string]int) {
+	plans := c.StartupPlan(providerMins)
+	logger.Info().
+		Int("currency_pairs", len(plans)).
+		Bool("provider_mins_enforced", providerMins != nil).
+		Interface("plan", plans).
+		Msg("startup plan")
+}
+
+func (c Config) validatePriceBands() error {
+	bases := make(map[string]struct{}, len(c.PriceBands))
+	for _, priceBand := range c.PriceBands {
+		if _, ok := bases[priceBand.Base]; ok {
+			return fmt.Errorf("duplicate price band for %s", priceBand.Base)
+		}
+		bases[priceBand.Base] = struct{}{}
+
+		band, err := priceBand.toPriceBand()
+		if err != nil {
+			return err
+		}
+		if band.Min.IsNil() && band.Max.IsNil() {
+			return fmt.Errorf("price band for %s must set a min or a max", priceBand.Base)
+		}
+		if !band.Min.IsNil() && band.Min.IsNegative() {
+			return fmt.Errorf("price band min for %s must not be negative", priceBand.Base)
+		}
+		if !band.Min.IsNil() && !band.Max.IsNil() && band.Min.GTE(band.Max) {
+			return fmt.Errorf("price band min for %s must be lower than its max", priceBand.Base)
+		}
+	}
+	return nil
+}
+
+func (c Config) validateTvwapWeightings() error {
+	bases := make(map[string]struct{}, len(c.TvwapWeightings))
+	for _, tvwapWeighting := range c.TvwapWeightings {
+		if _, ok := bases[tvwapWeighting.Base]; ok {
+			return fmt.Errorf("duplicate tvwap weighting for %s", tvwapWeighting.Base)
+		}
+		bases[tvwapWeighting.Base] = struct{}{}
+
+		if _, err := tvwapWeighting.toTvwapWeighting(); err != nil {
+			return err
+		}
+		for providerName := range tvwapWeighting.ProviderWeights {
+			if !c.hasBaseProvider(tvwapWeighting.Base, types.ProviderName(providerName)) {
+				return fmt.Errorf(
+					"tvwap weighting provider %s of %s is not a provider of any of its pairs",
+					providerName,
+					tvwapWeighting.Base,
+				)
+			}
+		}
+	}
+	return nil
+}
+
+// hasBaseProvider returns true if the provider is a provider of a currency
+// pair of the base.
+func (c Config) hasBaseProvider(base string, providerName types.ProviderName) bool {
+	for _, cp := range c.CurrencyPairs {
+		if cp.Base == base && hasProvider(cp.Providers, providerName) {
+			return true
+		}
+	}
+	return false
+}
+
+func (c Config) validateTickerWindows() error {
+	windows := make(map[string]struct{}, len(c.TickerWindows))
+	for _, tickerWindow := range c.TickerWindows {
+		pair := tickerWindow.Base + tickerWindow.Quote
+		key := tickerWindow.Provider.String() + "/" + pair
+		if _, ok := windows[key]; ok {
+			return fmt.Errorf("duplicate ticker window for %s of %s", pair, tickerWindow.Provider)
+		}
+		windows[key] = struct{}{}
+
+		provided := false
+		for _, cp := range c.CurrencyPairs {
+			if cp.Base == tickerWindow.Base && cp.Quote == tickerWindow.Quote && hasProvider(cp.Providers, tickerWindow.Provider) {
+				provided = true
+				break
+			}
+		}
+		if !provided {
+			return fmt.Errorf("ticker window set for %s which %s does not provide", pair, tickerWindow.Provider)
+		}
+
+		if tickerWindow.Samples < 2 || tickerWindow.Samples > maxTickerWindowSamples {
+			return fmt.Errorf(
+				"ticker window samples for %s of %s must be between 2 and %d",
+				pair,
+				tickerWindow.Provider,
+				maxTickerWindowSamples,
+			)
+		}
+	}
+	return nil
+}
+
+func (c Config) validateReferencePrices() error {
+	bases := make(map[string]struct{}, len(c.ReferencePrices))
+	for _, referencePrice := range c.ReferencePrices {
+		if _, ok := bases[referencePrice.Base]; ok {
+			return fmt.Errorf("duplicate reference price for %s", referencePrice.Base)
+		}
+		bases[referencePrice.Base] = struct{}{}
+
+		if _, ok := SupportedProviders[referencePrice.Provider]; !ok {
+			return unsupportedProviderError("reference price provider", referencePrice.Provider)
+		}
+		if bool(SupportedProviders[referencePrice.Provider]) &&
+			!hasAPIKey(referencePrice.Provider, c.ProviderEndpoints) {
+			return fmt.Errorf("provider %s requires an API Key", referencePrice.Provider)
+		}
+		if !c.hasBase(referencePrice.Base) {
+			return fmt.Errorf("reference price set for %s which is not a configured asset", referencePrice.Base)
+		}
+		if referencePrice.Quote != DenomUSD && !c.hasBase(referencePrice.Quote) {
+			return fmt.Errorf(
+				"reference price quote %s of %s must be USD or a configured asset",
+				referencePrice.Quote,
+				referencePrice.Base,
+			)
+		}
+
+		threshold, err := sdk.NewDecFromStr(referencePrice.Threshold)
+		if err != nil {
+			return fmt.Errorf("reference price threshold for %s must be numeric: %w", referencePrice.Base, err)
+		}
+		if !threshold.IsPositive() {
+			return fmt.Errorf("reference price threshold for %s must be positive", referencePrice.Base)
+		}
+	}
+	return nil
+}
+
+func (c Config) validateAnchorPairs() error {
+	bases := make(map[string]struct{}, len(c.AnchorPairs))
+	for _, anchorPair := range c.AnchorPairs {
+		if _, ok := bases[anchorPair.Base]; ok {
+			return fmt.Errorf("duplicate anchor pair for %s", anchorPair.Base)
+		}
+		bases[anchorPair.Base] = struct{}{}
+
+		usdPair := types.CurrencyPair{Base: anchorPair.Base, Quote: DenomUSD}
+		providers := c.pairProviders(usdPair)
+		if len(providers) == 0 {
+			return fmt.Errorf("anchor pair set for %s which is not a configured currency pair", usdPair)
+		}
+		if anchorPair.MinProviders < 1 {
+			return fmt.Errorf("anchor pair min providers for %s must be positive", anchorPair.Base)
+		}
+		if anchorPair.MinProviders > len(providers) {
+			return fmt.Errorf(
+				"anchor pair min providers %d exceeds the %d providers of %s",
+				anchorPair.MinProviders, len(providers), usdPair,
+			)
+		}
+
+		if _, err := anchorPair.toAnchorPair(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (c Config) validateConversionRoutes() error {
+	bases := make(map[string]struct{}, len(c.ConversionRoutes))
+	for _, route := range c.ConversionRoutes {
+		if _, ok := bases[route.Base]; ok {
+			return fmt.Errorf("duplicate conversion route for %s", route.Base)
+		}
+		bases[route.Base] = struct{}{}
+
+		if len(route.Quotes) == 0 {
+			return fmt.Errorf("conversion route for %s must set at least one quote", route.Base)
+		}
+		quotes := make(map[string]struct{}, len(route.Quotes))
+		for _, quote := range route.Quotes {
+			if _, ok := quotes[quote]; ok {
+				return fmt.Errorf("duplicate conversion route quote %s for %s", quote, route.Base)
+			}
+			quotes[quote] = struct{}{}
+
+			cp := types.CurrencyPair{Base: route.Base, Quote: quote}
+			if len(c.pairProviders(cp)) == 0 {
+				return fmt.Errorf("conversion route of %s through %s which is not a configured currency pair", route.Base, cp)
+			}
+		}
+	}
+	return nil
+}
+
+func (c Config) validateProviderGroups() error {
+	names := make(map[string]struct{}, len(c.ProviderGroups))
+	for _, group := range c.ProviderGroups {
+		if _, ok := names[group.Name]; ok {
+			return fmt.Errorf("duplicate provider group %s", group.Name)
+		}
+		names[group.Name] = struct{}{}
+
+		if len(group.Providers) == 0 {
+			return fmt.Errorf("provider group %s must set at least one provider", group.Name)
+		}
+		// a group named after a provider outside of it would count as the
+		// same source as that provider
+		if _, ok := SupportedProviders[types.ProviderName(group.Name)]; ok &&
+			!hasProvider(group.Providers, types.ProviderName(group.Name)) {
+			return fmt.Errorf("provider group %s must not be named after a provider outside of it", group.Name)
+		}
+	}
+
+	groups := make(types.ProviderGroups)
+	for _, group := range c.ProviderGroups {
+		for _, prov := range group.Providers {
 			if _, ok := SupportedProviders[prov]; !ok {
-				return fmt.Errorf("unsupported provider: %s", prov)
+				return fmt.Errorf("unsupported provider %s in provider group %s", prov, group.Name)
 			}
-			if bool(SupportedProviders[prov]) && !hasAPIKey(prov, c.ProviderEndpoints) {
-				return fmt.Errorf("provider %s requires an API Key", prov)
+			if existing, ok := groups[prov]; ok {
+				return fmt.Errorf("provider %s is in both provider groups %s and %s", prov, existing, group.Name)
 			}
+			groups[prov] = group.Name
 		}
-		if cp.Quote == DenomUSD {
+	}
+
+	for _, cp := range c.CurrencyPairs {
+		if cp.MinProviderGroups == 0 {
 			continue
 		}
-		// verify a conversion pair exists for the quote currency
-		for _, conversionPair := range SupportedConversionSlice() {
-			if cp.Quote == conversionPair.Base {
-				continue OUTER
-			}
+		pair := cp.Base + cp.Quote
+		if cp.MinProviderGroups < 0 {
+			return fmt.Errorf("min provider groups of %s must not be negative", pair)
+		}
+
+		pairGroups := make(map[string]struct{}, len(cp.Providers))
+		for _, prov := range cp.Providers {
+			pairGroups[groups.Group(prov)] = struct{}{}
+		}
+		if cp.MinProviderGroups > len(pairGroups) {
+			return fmt.Errorf(
+				"min provider groups of %s must not exceed the %d provider groups of its providers",
+				pair, len(pairGroups),
+			)
 		}
-		return fmt.Errorf("currency pair quote %s is not supported", cp.Quote)
 	}
 	return nil
 }
 
-func (c *Config) setDefaults() {
-	if c.Server.ListenAddr == "" {
-		c.Server.ListenAddr = defaultListenAddr
+// pairProviders returns the providers configured for the given currency pair.
+func (c Config) pairProviders(cp types.CurrencyPair) map[types.ProviderName]struct{} {
+	providers := make(map[types.ProviderName]struct{})
+	for _, pair := range c.CurrencyPairs {
+		if pair.Base != cp.Base || pair.Quote != cp.Quote {
+			continue
+		}
+		for _, providerName := range pair.Providers {
+			providers[providerName] = struct{}{}
+		}
 	}
-	if c.Server.WriteTimeout == "" {
-		c.Server.WriteTimeout = defaultSrvWriteTimeout.String()
+	return providers
+}
+
+// hasBase returns true if a currency pair with the given base is configured.
+func (c Config) hasBase(base string) bool {
+	for _, cp := range c.CurrencyPairs {
+		if cp.Base == base {
+			return true
+		}
 	}
-	if c.Server.ReadTimeout == "" {
-		c.Server.ReadTimeout = defaultSrvReadTimeout.String()
+	return false
+}
+
+func (c Config) validateMaxBufferedCandles() error {
+	if c.MaxBufferedCandles < 0 {
+		return fmt.Errorf("max buffered candles must not be negative")
 	}
-	if c.ProviderTimeout == "" {
-		c.ProviderTimeout = defaultProviderTimeout.String()
+	return nil
+}
+
+func (c Config) validateVotePriorities() error {
+	if c.MaxVoteSize < 0 {
+		return fmt.Errorf("max vote size must not be negative")
+	}
+
+	// the vote carries a rate per base, so the pairs of a base must agree
+	priorities := make(map[string]int)
+	for _, cp := range c.CurrencyPairs {
+		if cp.VotePriority == 0 {
+			continue
+		}
+		if cp.VotePriority < MinVotePriority || cp.VotePriority > MaxVotePriority {
+			return fmt.Errorf(
+				"vote priority of %s must be between %d and %d",
+				cp.Base+cp.Quote,
+				MinVotePriority,
+				MaxVotePriority,
+			)
+		}
+		if priority, ok := priorities[cp.Base]; ok && priority != cp.VotePriority {
+			return fmt.Errorf("conflicting vote priorities %d and %d of %s", priority, cp.VotePriority, cp.Base)
+		}
+		priorities[cp.Base] = cp.VotePriority
 	}
+	return nil
 }
 
-// ProviderPairs returns a map of provider.CurrencyPair where the key is the
-// provider name.
-func (c Config) ProviderPairs() map[types.ProviderName][]types.CurrencyPair {
-	providerPairs := make(map[types.ProviderName][]types.CurrencyPair)
+func (c Config) validateVoteExponents() error {
+	// the vote carries a rate per base, so the pairs of a base must agree
+	exponents := make(map[string]int)
+	for _, cp := range c.CurrencyPairs {
+		if cp.VoteExponent < 0 || cp.VoteExponent > MaxVoteExponent {
+			return fmt.Errorf(
+				"vote exponent of %s must be between 0 and %d",
+				cp.Base+cp.Quote,
+				MaxVoteExponent,
+			)
+		}
+		if exponent, ok := exponents[cp.Base]; ok && exponent != cp.VoteExponent {
+			return fmt.Errorf("conflicting vote exponents %d and %d of %s", exponent, cp.VoteExponent, cp.Base)
+		}
+		exponents[cp.Base] = cp.VoteExponent
+	}
+	return nil
+}
 
-	for _, pair := range c.CurrencyPairs {
-		for _, provider := range pair.Providers {
-			if len(pair.PairAddress) > 0 {
-				for _, uniPair := range pair.PairAddress {
-					if (uniPair.Provider == provider) && (uniPair.Address != "") {
-						providerPairs[uniPair.Provider] = append(providerPairs[uniPair.Provider], types.CurrencyPair{
-							Base:    pair.Base,
-							Quote:   pair.Quote,
-							Address: uniPair.Address,
-						})
-					}
-				}
-			} else {
-				providerPairs[provider] = append(providerPairs[provider], types.CurrencyPair{
-					Base:  pair.Base,
-					Quote: pair.Quote,
-				})
-			}
+func (c Config) validateVoteRoundings() error {
+	// the vote carries a rate per base, so the pairs of a base must agree
+	roundings := make(map[string]CurrencyPair)
+	for _, cp := range c.CurrencyPairs {
+		pair := cp.Base + cp.Quote
+		if cp.VoteRounding == "" && cp.VoteDecimals != 0 {
+			return fmt.Errorf("vote decimals of %s require a vote rounding", pair)
+		}
+		if cp.VoteRounding != "" && !isRoundingMode(cp.VoteRounding) {
+			return fmt.Errorf("invalid vote rounding mode %q for %s", cp.VoteRounding, pair)
+		}
+		if cp.VoteDecimals < 0 || cp.VoteDecimals > sdk.Precision {
+			return fmt.Errorf("vote decimals of %s must be between 0 and %d", pair, sdk.Precision)
+		}
+		if rounding, ok := roundings[cp.Base]; ok &&
+			(rounding.VoteRounding != cp.VoteRounding || rounding.VoteDecimals != cp.VoteDecimals) {
+			return fmt.Errorf("conflicting vote roundings of the pairs of %s", cp.Base)
 		}
+		roundings[cp.Base] = cp
 	}
-	return providerPairs
+	return nil
 }
 
-// ProviderEndpointsMap converts the provider_endpoints from the config
-// file into a map of provider.Endpoint where the key is the provider name.
-func (c Config) ProviderEndpointsMap() map[types.ProviderName]provider.Endpoint {
-	endpoints := make(map[types.ProviderName]provider.Endpoint, len(c.ProviderEndpoints))
-	for _, endpoint := range c.ProviderEndpoints {
-		endpoints[endpoint.Name] = endpoint
+// isRoundingMode returns true if the mode is a supported vote rounding mode.
+func isRoundingMode(mode string) bool {
+	for _, roundingMode := range types.RoundingModes {
+		if mode == string(roundingMode) {
+			return true
+		}
 	}
-	return endpoints
+	return false
 }
 
-// DeviationsMap converts the deviation_thresholds from the config file into
-// a map of sdk.Dec where the key is the base asset.
-func (c Config) DeviationsMap() (map[string]sdk.Dec, error) {
-	deviations := make(map[string]sdk.Dec, len(c.Deviations))
-	for _, deviation := range c.Deviations {
-		threshold, err := sdk.NewDecFromStr(deviation.Threshold)
-		if err != nil {
-			return nil, err
+func (c Config) validateSpotOnly() error {
+	// the rates of a base are aggregated together, so its pairs must agree
+	spotOnly := make(map[string]bool)
+	for _, cp := range c.CurrencyPairs {
+		if isSpotOnly, ok := spotOnly[cp.Base]; ok && isSpotOnly != cp.SpotOnly {
+			return fmt.Errorf("conflicting spot only settings of the pairs of %s", cp.Base)
 		}
-		deviations[deviation.Base] = threshold
+		spotOnly[cp.Base] = cp.SpotOnly
 	}
-	return deviations, nil
+	return nil
+}
+
+func (c Config) validateMedianTieBreaks() error {
+	// the medians are taken by base, so its pairs must agree
+	tieBreaks := make(map[string]types.ProviderName)
+	for _, cp := range c.CurrencyPairs {
+		if cp.MedianTieBreak == "" {
+			continue
+		}
+		if tieBreak, ok := tieBreaks[cp.Base]; ok && tieBreak != cp.MedianTieBreak {
+			return fmt.Errorf("conflicting median tie breaks of the pairs of %s", cp.Base)
+		}
+		tieBreaks[cp.Base] = cp.MedianTieBreak
+	}
+	return nil
+}
+
+func (c Config) validateServerTLS() error {
+	if (c.Server.TLSCertFile == "") != (c.Server.TLSKeyFile == "") {
+		return fmt.Errorf("server TLS requires both tls_cert_file and tls_key_file")
+	}
+	if c.Server.TLSClientCAFile != "" && c.Server.TLSCertFile == "" {
+		return fmt.Errorf("server tls_client_ca_file requires tls_cert_file and tls_key_file")
+	}
+	return nil
+}
+
+func (c Config) validateServerSafeMode() error {
+	if c.Server.SafeModeMaxAge == "" {
+		return nil
+	}
+	if !c.Server.SafeMode {
+		return fmt.Errorf("server safe_mode_max_age requires safe_mode")
+	}
+	maxAge, err := time.ParseDuration(c.Server.SafeModeMaxAge)
+	if err != nil {
+		return fmt.Errorf("invalid server safe_mode_max_age: %w", err)
+	}
+	if maxAge <= 0 {
+		return fmt.Errorf("server safe_mode_max_age must be positive")
+	}
+	return nil
+}
+
+func (c Config) validateServerPairControl() error {
+	if !c.Server.PairControl || c.Server.TLSClientCAFile != "" {
+		return nil
+	}
+	// pausing pairs changes the votes, so it must not be exposed to anyone
+	// able to reach the API
+	loopback, err := isLoopbackAddr(c.Server.ListenAddr)
+	if err != nil {
+		return fmt.Errorf("invalid server listen_addr: %w", err)
+	}
+	if !loopback {
+		return fmt.Errorf("server pair_control requires a loopback listen_addr or tls_client_ca_file")
+	}
+	return nil
+}
+
+func (c Config) validateProfiling() error {
+	if !c.Profiling.Enabled {
+		return nil
+	}
+	_, port, err := net.SplitHostPort(c.Profiling.ListenAddr)
+	if err != nil {
+		return fmt.Errorf("invalid profiling listen_addr: %w", err)
+	}
+	if portNum, err := strconv.ParseUint(port, 10, 16); err != nil || portNum == 0 {
+		return fmt.Errorf("invalid profiling port: %s", port)
+	}
+	if c.Profiling.ListenAddr == c.Server.ListenAddr {
+		return fmt.Errorf("profiling listen_addr must differ from the server listen_addr")
+	}
+	// the profiles expose the internals of the process
+	if loopback, _ := isLoopbackAddr(c.Profiling.ListenAddr); !loopback && !c.Profiling.AllowRemote {
+		return fmt.Errorf("profiling requires a loopback listen_addr unless allow_remote is set")
+	}
+	return nil
+}
+
+// isLoopbackAddr returns whether the host:port address listens on a loopback
+// address only. It fails if the address has no port.
+func isLoopbackAddr(addr string) (bool, error) {
+	host, _, err := net.SplitHostPort(addr)
+	if err != nil {
+		return false, err
+	}
+	ip := net.ParseIP(host)
+	return host == "localhost" || (ip != nil && ip.IsLoopback()), nil
+}
+
+// SafeModeMaxAgeDuration returns the age past which stale prices are no longer
+// served in safe mode, and zero if they are served however old.
+func (s Server) SafeModeMaxAgeDuration() (time.Duration, error) {
+	if s.SafeModeMaxAge == "" {
+		return 0, nil
+	}
+	return time.ParseDuration(s.SafeModeMaxAge)
+}
+
+func (c Config) validateGas() error {
+	if c.Gas <= 0 && c.GasAdjustment <= 0 {
+		return fmt.Errorf("gas or gas adjustment must be set")
+	}
+	if c.GasAdjustment > 0 && c.Gas > 0 {
+		return fmt.Errorf("gas and gas adjustment may not both be set")
+	}
+	return nil
+}
+
+func (c Config) validatePriceTypes() error {
+	providerPairs := c.ProviderPairs()
+	for _, endpoint := range c.ProviderEndpoints {
+		for _, ppt := range endpoint.PriceTypes {
+			if !ppt.Type.IsValid() {
+				return fmt.Errorf("unsupported price type %s for %s", ppt.Type, endpoint.Name)
+			}
+			if ppt.Type == provider.PriceTypeSpot {
+				continue
+			}
+			if !supportsPriceType(endpoint.Name, ppt.Type) {
+				return fmt.Errorf("provider %s does not support the %s price type", endpoint.Name, ppt.Type)
+			}
+
+			found := false
+			for _, cp := range providerPairs[endpoint.Name] {
+				if cp.Base == ppt.Base && cp.Quote == ppt.Quote {
+					found = true
+					break
+				}
+			}
+			if !found {
+				return fmt.Errorf(
+					"%s price type set for %s which is not a configured pair of %s",
+					ppt.Type,
+					ppt.CurrencyPair(),
+					endpoint.Name,
+				)
+			}
+		}
+	}
+	return nil
+}
+
+func (c Config) validatePriceSources() error {
+	providerPairs := c.ProviderPairs()
+	for _, endpoint := range c.ProviderEndpoints {
+		for _, pps := range endpoint.PriceSources {
+			if !pps.Source.IsValid() {
+				return fmt.Errorf("unsupported price source %s for %s", pps.Source, endpoint.Name)
+			}
+			if pps.Source == provider.PriceSourceLast {
+				continue
+			}
+			if _, ok := SupportedPriceSourceProviders[endpoint.Name]; !ok {
+				return fmt.Errorf("provider %s does not support the %s price source", endpoint.Name, pps.Source)
+			}
+
+			found := false
+			for _, cp := range providerPairs[endpoint.Name] {
+				if cp.Base == pps.Base && cp.Quote == pps.Quote {
+					found = true
+					break
+				}
+			}
+			if !found {
+				return fmt.Errorf(
+					"%s price source set for %s which is not a configured pair of %s",
+					pps.Source,
+					pps.CurrencyPair(),
+					endpoint.Name,
+				)
+			}
+
+			for _, ppt := range endpoint.PriceTypes {
+				if ppt.Base == pps.Base && ppt.Quote == pps.Quote && ppt.Type != provider.PriceTypeSpot {
+					return fmt.Errorf(
+						"%s price source set for %s which is priced by its %s price on %s",
+						pps.Source,
+						pps.CurrencyPair(),
+						ppt.Type,
+						endpoint.Name,
+					)
+				}
+			}
+		}
+	}
+	return nil
+}
+
+func (c Config) validateCandleIntervals() error {
+	for _, endpoint := range c.ProviderEndpoints {
+		if len(endpoint.CandleIntervals) == 0 {
+			continue
+		}
+		supported, ok := SupportedCandleIntervals[endpoint.Name]
+		if !ok {
+			return fmt.Errorf("provider %s does not support candle intervals", endpoint.Name)
+		}
+
+		intervals := make(map[string]struct{}, len(endpoint.CandleIntervals))
+		for _, interval := range endpoint.CandleIntervals {
+			if _, ok := intervals[interval]; ok {
+				return fmt.Errorf("duplicate candle interval %s for %s", interval, endpoint.Name)
+			}
+			intervals[interval] = struct{}{}
+
+			if !supportsCandleInterval(supported, interval) {
+				return fmt.Errorf("unsupported candle interval %s for %s", interval, endpoint.Name)
+			}
+		}
+	}
+	return nil
+}
+
+// AutoCandleInterval returns the coarsest of the supported candle intervals,
+// ordered from the finest, of which at least five candles fit in the TWAP
+// window, or the finest interval if none does. Ex. a window of 5m uses 1m
+// candles and a window of 1h 5m candles.
+func AutoCandleInterval(window time.Duration, supported []string) string {
+	if len(supported) == 0 {
+		return ""
+	}
+
+	selected := supported[0]
+	for _, interval := range supported[1:] {
+		duration, err := time.ParseDuration(interval)
+		if err != nil || duration*minCandlesPerTwapWindow > window {
+			break
+		}
+		selected = interval
+	}
+	return selected
+}
+
+// autoCandleIntervals returns the candle intervals selected for the TWAP
+// windows of the pairs of each provider supporting several intervals. The
+// finest interval, the default of these providers, is kept for their pairs
+// without a TWAP window. Providers without any pair setting a TWAP window are
+// left out.
+func (c Config) autoCandleIntervals() map[types.ProviderName][]string {
+	windows, _ := c.TwapWindows()
+	if len(windows) == 0 {
+		return nil
+	}
+
+	selected := make(map[types.ProviderName]map[string]struct{})
+	hasWindow := make(map[types.ProviderName]bool)
+	for _, cp := range c.CurrencyPairs {
+		window, pairHasWindow := windows[cp.Base]
+		for _, providerName := range cp.Providers {
+			supported, ok := SupportedCandleIntervals[providerName]
+			if !ok {
+				continue
+			}
+
+			interval := supported[0]
+			if pairHasWindow {
+				interval = AutoCandleInterval(window, supported)
+				hasWindow[providerName] = true
+			}
+			if selected[providerName] == nil {
+				selected[providerName] = make(map[string]struct{})
+			}
+			selected[providerName][interval] = struct{}{}
+		}
+	}
+
+	intervals := make(map[types.ProviderName][]string, len(hasWindow))
+	for providerName := range hasWindow {
+		// keep the order of the supported intervals, from the finest
+		for _, interval := range SupportedCandleIntervals[providerName] {
+			if _, ok := selected[providerName][interval]; ok {
+				intervals[providerName] = append(intervals[providerName], interval)
+			}
+		}
+	}
+	return intervals
+}
+
+func supportsCandleInterval(supported []string, interval string) bool {
+	for _, s := range supported {
+		if s == interval {
+			return true
+		}
+	}
+	return false
+}
+
+func (c Config) validatePriceCache() error {
+	if c.PriceCache.Path == "" {
+		return nil
+	}
+	if _, err := time.ParseDuration(c.PriceCache.MaxAge); err != nil {
+		return fmt.Errorf("price cache max age must be a duration: %w", err)
+	}
+	if _, err := time.ParseDuration(c.PriceCache.WriteInterval); err != nil {
+		return fmt.Errorf("price cache write interval must be a duration: %w", err)
+	}
+	return nil
+}
+
+func (c Config) validateSymbolCache() error {
+	if c.SymbolCache.MaxRetries < 0 {
+		return fmt.Errorf("symbol cache max retries must not be negative")
+	}
+	if c.SymbolCache.TTL == "" {
+		return nil
+	}
+	ttl, err := time.ParseDuration(c.SymbolCache.TTL)
+	if err != nil {
+		return fmt.Errorf("symbol cache ttl must be a duration: %w", err)
+	}
+	if ttl <= 0 {
+		return fmt.Errorf("symbol cache ttl must be positive")
+	}
+	return nil
+}
+
+func (c Config) validateAttestation() error {
+	if c.Attestation.URL == "" {
+		return nil
+	}
+	collectorURL, err := url.Parse(c.Attestation.URL)
+	if err != nil {
+		return fmt.Errorf("invalid attestation url: %w", err)
+	}
+	if (collectorURL.Scheme != "http" && collectorURL.Scheme != "https") || collectorURL.Host == "" {
+		return fmt.Errorf("attestation url must be an http or https url: %s", c.Attestation.URL)
+	}
+	timeout, err := time.ParseDuration(c.Attestation.Timeout)
+	if err != nil {
+		return fmt.Errorf("attestation timeout must be a duration: %w", err)
+	}
+	if timeout <= 0 {
+		return fmt.Errorf("attestation timeout must be positive")
+	}
+	if c.Attestation.MaxRetries < 0 {
+		return fmt.Errorf("attestation max retries must not be negative")
+	}
+	return nil
+}
+
+func (c Config) validateStatsd() error {
+	if c.Statsd.Address == "" {
+		return nil
+	}
+	host, port, err := net.SplitHostPort(c.Statsd.Address)
+	if err != nil {
+		return fmt.Errorf("statsd address must be a host:port: %w", err)
+	}
+	if host == "" {
+		return fmt.Errorf("statsd address must set a host: %s", c.Statsd.Address)
+	}
+	if portNum, err := strconv.ParseUint(port, 10, 16); err != nil || portNum == 0 {
+		return fmt.Errorf("invalid statsd port: %s", port)
+	}
+	if c.Statsd.Prefix == "" {
+		return fmt.Errorf("statsd prefix must not be empty")
+	}
+	interval, err := time.ParseDuration(c.Statsd.FlushInterval)
+	if err != nil {
+		return fmt.Errorf("statsd flush interval must be a duration: %w", err)
+	}
+	if interval <= 0 {
+		return fmt.Errorf("statsd flush interval must be positive")
+	}
+	return nil
+}
+
+func (c Config) validateWebhook() error {
+	if c.Webhook.URL == "" {
+		return nil
+	}
+	webhookURL, err := url.Parse(c.Webhook.URL)
+	if err != nil {
+		return fmt.Errorf("invalid webhook url: %w", err)
+	}
+	if (webhookURL.Scheme != "http" && webhookURL.Scheme != "https") || webhookURL.Host == "" {
+		return fmt.Errorf("webhook url must be an http or https url: %s", c.Webhook.URL)
+	}
+	priceChange, err := c.WebhookPriceChangeDec()
+	if err != nil {
+		return err
+	}
+	if priceChange.IsNil() && !c.Webhook.AlertOmissions && !c.Webhook.AlertPriceBands {
+		return fmt.Errorf("webhook requires a price change, alert omissions or alert price bands")
+	}
+	rateLimit, err := time.ParseDuration(c.Webhook.RateLimit)
+	if err != nil {
+		return fmt.Errorf("webhook rate limit must be a duration: %w", err)
+	}
+	if rateLimit < 0 {
+		return fmt.Errorf("webhook rate limit must not be negative")
+	}
+	timeout, err := time.ParseDuration(c.Webhook.Timeout)
+	if err != nil {
+		return fmt.Errorf("webhook timeout must be a duration: %w", err)
+	}
+	if timeout <= 0 {
+		return fmt.Errorf("webhook timeout must be positive")
+	}
+	return nil
+}
+
+func (c Config) validateChainlink() error {
+	if err := validateRoundAge(c.Chainlink.MaxRoundAge); err != nil {
+		return err
+	}
+
+	feeds := make(map[string]struct{}, len(c.Chainlink.Feeds))
+	for _, feed := range c.Chainlink.Feeds {
+		pair := feed.Base + feed.Quote
+		if _, ok := feeds[pair]; ok {
+			return fmt.Errorf("duplicate chainlink feed for %s", pair)
+		}
+		feeds[pair] = struct{}{}
+
+		if !evmAddressRegex.MatchString(feed.Address) {
+			return fmt.Errorf("invalid chainlink aggregator address %s for %s", feed.Address, pair)
+		}
+		if err := validateRoundAge(feed.MaxRoundAge); err != nil {
+			return fmt.Errorf("%s: %w", pair, err)
+		}
+	}
+
+	for _, cp := range c.CurrencyPairs {
+		if !hasProvider(cp.Providers, provider.ProviderChainlink) {
+			continue
+		}
+		if c.Chainlink.RPC == "" {
+			return fmt.Errorf("chainlink rpc must be set to use the chainlink provider")
+		}
+		if _, ok := feeds[cp.Base+cp.Quote]; !ok {
+			return fmt.Errorf("no chainlink feed configured for %s", cp.Base+cp.Quote)
+		}
+	}
+	return nil
+}
+
+// validateRoundAge returns an error if a set max round age is not a positive
+// duration.
+func validateRoundAge(maxRoundAge string) error {
+	if maxRoundAge == "" {
+		return nil
+	}
+	age, err := time.ParseDuration(maxRoundAge)
+	if err != nil {
+		return fmt.Errorf("chainlink max round age must be a duration: %w", err)
+	}
+	if age <= 0 {
+		return fmt.Errorf("chainlink max round age must be positive")
+	}
+	return nil
+}
+
+func (c Config) validateOsmosisTwap() error {
+	if err := validateTwapWindow(c.OsmosisTwap.Window); err != nil {
+		return err
+	}
+
+	pools := make(map[string]struct{}, len(c.OsmosisTwap.Pools))
+	for _, pool := range c.OsmosisTwap.Pools {
+		pair := pool.Base + pool.Quote
+		if _, ok := pools[pair]; ok {
+			return fmt.Errorf("duplicate osmosis twap pool for %s", pair)
+		}
+		pools[pair] = struct{}{}
+
+		if pool.BaseDecimals < 0 || pool.BaseDecimals > sdk.Precision ||
+			pool.QuoteDecimals < 0 || pool.QuoteDecimals > sdk.Precision {
+			return fmt.Errorf("osmosis twap decimals of %s must be between 0 and %d", pair, sdk.Precision)
+		}
+		if err := validateTwapWindow(pool.Window); err != nil {
+			return fmt.Errorf("%s: %w", pair, err)
+		}
+	}
+
+	for _, cp := range c.CurrencyPairs {
+		if !hasProvider(cp.Providers, provider.ProviderOsmosisTwap) {
+			continue
+		}
+		if c.OsmosisTwap.LCD == "" {
+			return fmt.Errorf("osmosis twap lcd must be set to use the osmosis-twap provider")
+		}
+		if _, ok := pools[cp.Base+cp.Quote]; !ok {
+			return fmt.Errorf("no osmosis twap pool configured for %s", cp.Base+cp.Quote)
+		}
+	}
+	return nil
+}
+
+// validateTwapWindow returns an error if a set TWAP window is not a positive
+// duration within the retention of the TWAP records.
+func validateTwapWindow(window string) error {
+	if window == "" {
+		return nil
+	}
+	duration, err := time.ParseDuration(window)
+	if err != nil {
+		return fmt.Errorf("osmosis twap window must be a duration: %w", err)
+	}
+	if duration <= 0 || duration > maxOsmosisTwapWindow {
+		return fmt.Errorf("osmosis twap window must be positive and at most %s", maxOsmosisTwapWindow)
+	}
+	return nil
+}
+
+func (c Config) validateUniswapV3() error {
+	if err := validateUniswapV3TwapWindow(c.UniswapV3.TwapWindow); err != nil {
+		return err
+	}
+
+	pools := make(map[string]struct{}, len(c.UniswapV3.Pools))
+	for _, pool := range c.UniswapV3.Pools {
+		pair := pool.Base + pool.Quote
+		if _, ok := pools[pair]; ok {
+			return fmt.Errorf("duplicate uniswap v3 pool for %s", pair)
+		}
+		pools[pair] = struct{}{}
+
+		if !evmAddressRegex.MatchString(pool.Address) {
+			return fmt.Errorf("invalid uniswap v3 pool address %s for %s", pool.Address, pair)
+		}
+		if !evmAddressRegex.MatchString(pool.BaseToken) {
+			return fmt.Errorf("invalid uniswap v3 base token address %s for %s", pool.BaseToken, pair)
+		}
+		if pool.FeeTier >= maxUniswapV3FeeTier {
+			return fmt.Errorf("uniswap v3 fee tier of %s must be below %d", pair, maxUniswapV3FeeTier)
+		}
+		if err := validateUniswapV3TwapWindow(pool.TwapWindow); err != nil {
+			return fmt.Errorf("%s: %w", pair, err)
+		}
+	}
+
+	for _, cp := range c.CurrencyPairs {
+		if !hasProvider(cp.Providers, provider.ProviderUniswapV3) {
+			continue
+		}
+		if c.UniswapV3.RPC == "" {
+			return fmt.Errorf("uniswap v3 rpc must be set to use the uniswap-v3 provider")
+		}
+		if _, ok := pools[cp.Base+cp.Quote]; !ok {
+			return fmt.Errorf("no uniswap v3 pool configured for %s", cp.Base+cp.Quote)
+		}
+	}
+	return nil
+}
+
+// validateUniswapV3TwapWindow returns an error if a set TWAP window is not a
+// whole number of seconds between a second and the max window.
+func validateUniswapV3TwapWindow(window string) error {
+	if window == "" {
+		return nil
+	}
+	duration, err := time.ParseDuration(window)
+	if err != nil {
+		return fmt.Errorf("uniswap v3 twap window must be a duration: %w", err)
+	}
+	if duration < time.Second || duration > maxUniswapV3TwapWindow || duration%time.Second != 0 {
+		return fmt.Errorf(
+			"uniswap v3 twap window must be whole seconds between 1s and %s", maxUniswapV3TwapWindow,
+		)
+	}
+	return nil
+}
+
+func (c Config) validateCurve() error {
+	pools := make(map[string]struct{}, len(c.Curve.Pools))
+	for _, pool := range c.Curve.Pools {
+		pair := pool.Base + pool.Quote
+		if _, ok := pools[pair]; ok {
+			return fmt.Errorf("duplicate curve pool for %s", pair)
+		}
+		pools[pair] = struct{}{}
+
+		if !evmAddressRegex.MatchString(pool.Address) {
+			return fmt.Errorf("invalid curve pool address %s for %s", pool.Address, pair)
+		}
+		if pool.BaseIndex < 0 || pool.BaseIndex >= maxCurvePoolCoins ||
+			pool.QuoteIndex < 0 || pool.QuoteIndex >= maxCurvePoolCoins {
+			return fmt.Errorf("curve coin indices of %s must be between 0 and %d", pair, maxCurvePoolCoins-1)
+		}
+		if pool.BaseIndex == pool.QuoteIndex {
+			return fmt.Errorf("curve base and quote coins of %s must differ", pair)
+		}
+	}
+
+	for _, cp := range c.CurrencyPairs {
+		if !hasProvider(cp.Providers, provider.ProviderCurve) {
+			continue
+		}
+		if c.Curve.RPC == "" {
+			return fmt.Errorf("curve rpc must be set to use the curve provider")
+		}
+		if _, ok := pools[cp.Base+cp.Quote]; !ok {
+			return fmt.Errorf("no curve pool configured for %s", cp.Base+cp.Quote)
+		}
+	}
+	return nil
+}
+
+func (c Config) validateDydx() error {
+	if c.Dydx.PollInterval != "" {
+		pollInterval, err := time.ParseDuration(c.Dydx.PollInterval)
+		if err != nil {
+			return fmt.Errorf("dydx poll interval must be a duration: %w", err)
+		}
+		if pollInterval <= 0 {
+			return fmt.Errorf("dydx poll interval must be positive")
+		}
+	}
+
+	markets := make(map[string]struct{}, len(c.Dydx.Markets))
+	for _, market := range c.Dydx.Markets {
+		pair := market.Base + market.Quote
+		if _, ok := markets[pair]; ok {
+			return fmt.Errorf("duplicate dydx market for %s", pair)
+		}
+		markets[pair] = struct{}{}
+
+		if !dydxMarketRegex.MatchString(market.Market) {
+			return fmt.Errorf("invalid dydx market %s for %s", market.Market, pair)
+		}
+	}
+
+	for _, cp := range c.CurrencyPairs {
+		if !hasProvider(cp.Providers, provider.ProviderDydx) {
+			continue
+		}
+		if _, ok := markets[cp.Base+cp.Quote]; !ok {
+			return fmt.Errorf("no dydx market configured for %s", cp.Base+cp.Quote)
+		}
+	}
+	return nil
+}
+
+func (c Config) validateRedemptionRate() error {
+	var (
+		pollInterval, maxAge time.Duration
+		err                  error
+	)
+	if c.RedemptionRate.PollInterval != "" {
+		pollInterval, err = time.ParseDuration(c.RedemptionRate.PollInterval)
+		if err != nil {
+			return fmt.Errorf("redemption rate poll interval must be a duration: %w", err)
+		}
+		if pollInterval <= 0 {
+			return fmt.Errorf("redemption rate poll interval must be positive")
+		}
+	}
+	if c.RedemptionRate.MaxAge != "" {
+		maxAge, err = time.ParseDuration(c.RedemptionRate.MaxAge)
+		if err != nil {
+			return fmt.Errorf("redemption rate max age must be a duration: %w", err)
+		}
+		if maxAge <= 0 {
+			return fmt.Errorf("redemption rate max age must be positive")
+		}
+		if maxAge < pollInterval {
+			return fmt.Errorf("redemption rate max age must be at least the poll interval")
+		}
+	}
+
+	bases := make(map[string]struct{}, len(c.CurrencyPairs))
+	for _, cp := range c.CurrencyPairs {
+		bases[cp.Base] = struct{}{}
+	}
+	routes := c.ConversionRoutesMap()
+
+	assets := make(map[string]string, len(c.RedemptionRate.Assets))
+	for _, asset := range c.RedemptionRate.Assets {
+		if _, ok := assets[asset.Base]; ok {
+			return fmt.Errorf("duplicate redemption rate for %s", asset.Base)
+		}
+		assets[asset.Base] = asset.Underlying
+
+		if asset.Base == asset.Underlying {
+			return fmt.Errorf("redemption rate of %s must have another underlying asset", asset.Base)
+		}
+		if _, ok := bases[asset.Underlying]; !ok {
+			return fmt.Errorf(
+				"underlying asset %s of the redemption rate of %s is not a configured currency pair base",
+				asset.Underlying, asset.Base,
+			)
+		}
+		if _, ok := routes[asset.Base]; ok {
+			return fmt.Errorf("%s cannot have both a redemption rate and a conversion route", asset.Base)
+		}
+
+		cp := types.CurrencyPair{Base: asset.Base, Quote: asset.Underlying}
+		if _, ok := c.pairProviders(cp)[provider.ProviderRedemptionRate]; !ok {
+			return fmt.Errorf("redemption rate of %s needs the currency pair %s with the %s provider",
+				asset.Base, cp, provider.ProviderRedemptionRate)
+		}
+	}
+
+	for _, cp := range c.CurrencyPairs {
+		if !hasProvider(cp.Providers, provider.ProviderRedemptionRate) {
+			continue
+		}
+		if underlying, ok := assets[cp.Base]; !ok || underlying != cp.Quote {
+			return fmt.Errorf("no redemption rate configured for %s", cp.Base+cp.Quote)
+		}
+	}
+	return nil
+}
+
+func (c Config) validateLog() error {
+	if c.Log.File == "" {
+		return nil
+	}
+	if c.Log.MaxSize <= 0 {
+		return fmt.Errorf("log max size must be positive")
+	}
+	if c.Log.MaxBackups < 0 {
+		return fmt.Errorf("log max backups must not be negative")
+	}
+	if _, err := c.LogMaxAge(); err != nil {
+		return err
+	}
+	return nil
+}
+
+func (c Config) validateAuthz() error {
+	if !c.Authz.Enabled {
+		if c.Authz.Granter != "" {
+			return fmt.Errorf("authz granter set without enabling authz")
+		}
+		return nil
+	}
+	if c.Authz.Granter == "" {
+		return fmt.Errorf("authz requires a granter")
+	}
+	if _, _, err := bech32.DecodeAndConvert(c.Authz.Granter); err != nil {
+		return fmt.Errorf("authz granter must be a bech32 address: %w", err)
+	}
+	if c.Authz.Granter == c.Account.Address {
+		return fmt.Errorf("authz granter must differ from the account address")
+	}
+	return nil
+}
+
+func (c Config) validateDynamicPairs() error {
+	if !c.DynamicPairs.Enabled {
+		return nil
+	}
+	if len(c.DynamicPairs.Providers) == 0 {
+		return fmt.Errorf("dynamic pairs require at least one provider")
+	}
+	for _, providerName := range c.DynamicPairs.Providers {
+		if _, ok := SupportedProviders[providerName]; !ok {
+			return fmt.Errorf("dynamic pairs provider %s is not supported", providerName)
+		}
+	}
+	if c.DynamicPairs.Quote == "" {
+		return fmt.Errorf("dynamic pairs require a quote")
+	}
+
+	// a quote other than USD is converted by the USD rate of a configured
+	// currency pair
+	quotes := []string{strings.ToUpper(c.DynamicPairs.Quote)}
+	for _, quote := range c.dynamicPairQuotes() {
+		quotes = append(quotes, quote)
+	}
+	for _, quote := range quotes {
+		if quote != DenomUSD && !c.hasCurrencyPair(quote, DenomUSD) {
+			return fmt.Errorf("dynamic pairs quote %s requires a configured %s/%s currency pair", quote, quote, DenomUSD)
+		}
+	}
+
+	_, err := c.dynamicPairsRefreshInterval()
+	return err
+}
+
+// hasCurrencyPair returns true if a currency pair of the base and quote is
+// configured.
+func (c Config) hasCurrencyPair(base, quote string) bool {
+	for _, cp := range c.CurrencyPairs {
+		if cp.Base == base && cp.Quote == quote {
+			return true
+		}
+	}
+	return false
+}
+
+func (c Config) validateVoteWarmup() error {
+	duration, err := c.VoteWarmupDuration()
+	if err != nil {
+		return err
+	}
+	if duration < 0 {
+		return fmt.Errorf("vote warm-up duration must not be negative")
+	}
+
+	minProviders := c.VoteWarmup.MinProviders
+	if minProviders < 0 {
+		return fmt.Errorf("vote warm-up min providers must not be negative")
+	}
+
+	// the warm-up would never complete if an asset can't reach min providers
+	baseProviders := make(map[string]map[types.ProviderName]struct{})
+	for _, cp := range c.CurrencyPairs {
+		if _, ok := baseProviders[cp.Base]; !ok {
+			baseProviders[cp.Base] = make(map[types.ProviderName]struct{})
+		}
+		for _, providerName := range cp.Providers {
+			baseProviders[cp.Base][providerName] = struct{}{}
+		}
+	}
+	for base, providers := range baseProviders {
+		if len(providers) < minProviders {
+			return fmt.Errorf(
+				"vote warm-up min providers %d exceeds the %d providers of %s",
+				minProviders, len(providers), base,
+			)
+		}
+	}
+	return nil
+}
+
+func (c Config) validateZeroVolumeWeight() error {
+	zeroVolumeWeight, err := c.ZeroVolumeWeightDec()
+	if err != nil {
+		return err
+	}
+	if zeroVolumeWeight.IsNegative() {
+		return fmt.Errorf("zero volume weight must not be negative")
+	}
+	return nil
+}
+
+func (c Config) validateMaxClockSkew() error {
+	if c.MaxClockSkew == "" {
+		return nil
+	}
+	maxClockSkew, err := time.ParseDuration(c.MaxClockSkew)
+	if err != nil {
+		return fmt.Errorf("max clock skew must be a duration: %w", err)
+	}
+	if maxClockSkew <= 0 {
+		return fmt.Errorf("max clock skew must be positive")
+	}
+	return nil
+}
+
+func (c Config) validateVoteSafetyMargin() error {
+	margin, err := c.VoteSafetyMarginDuration()
+	if err != nil {
+		return err
+	}
+	if margin < 0 {
+		return fmt.Errorf("vote safety margin must not be negative")
+	}
+	return nil
+}
+
+func (c Config) validateProviderHealthInterval() error {
+	if c.ProviderHealthInterval == "" {
+		return nil
+	}
+	interval, err := time.ParseDuration(c.ProviderHealthInterval)
+	if err != nil {
+		return fmt.Errorf("provider health interval must be a duration: %w", err)
+	}
+	if interval < 0 {
+		return fmt.Errorf("provider health interval must not be negative")
+	}
+	return nil
+}
+
+func (c Config) validateSubscriptionReconcile() error {
+	interval, maxAge, err := c.SubscriptionReconcileDurations()
+	if err != nil {
+		return err
+	}
+	if interval < 0 {
+		return fmt.Errorf("subscription reconcile interval must not be negative")
+	}
+	if interval > 0 && maxAge <= 0 {
+		return fmt.Errorf("subscription reconcile max age must be positive")
+	}
+	return nil
+}
+
+func (c Config) validateReconnectCooldown() error {
+	if c.ReconnectCooldown == "" {
+		return nil
+	}
+	cooldown, err := time.ParseDuration(c.ReconnectCooldown)
+	if err != nil {
+		return fmt.Errorf("reconnect cooldown must be a duration: %w", err)
+	}
+	if cooldown < 0 {
+		return fmt.Errorf("reconnect cooldown must not be negative")
+	}
+	return nil
+}
+
+func (c Config) validateStartupPolicy() error {
+	switch c.StartupPolicy {
+	case "", StartupPolicyWarn, StartupPolicyFail, StartupPolicyWait:
+	default:
+		return fmt.Errorf(
+			"startup policy must be %s, %s or %s, got %s",
+			StartupPolicyWarn,
+			StartupPolicyFail,
+			StartupPolicyWait,
+			c.StartupPolicy,
+		)
+	}
+
+	if c.StartupTimeout == "" {
+		return nil
+	}
+	timeout, err := time.ParseDuration(c.StartupTimeout)
+	if err != nil {
+		return fmt.Errorf("startup timeout must be a duration: %w", err)
+	}
+	if timeout <= 0 {
+		return fmt.Errorf("startup timeout must be positive")
+	}
+	return nil
+}
+
+func (c Config) validateStartupOrder() error {
+	switch c.StartupOrder {
+	case "", StartupOrderChainFirst, StartupOrderProvidersFirst:
+		return nil
+	default:
+		return fmt.Errorf(
+			"startup order must be %s or %s, got %s",
+			StartupOrderChainFirst,
+			StartupOrderProvidersFirst,
+			c.StartupOrder,
+		)
+	}
+}
+
+func (c Config) validateProviderTLS() error {
+	for _, endpoint := range c.ProviderEndpoints {
+		if _, err := endpoint.TLSConfig(); err != nil {
+			return fmt.Errorf("invalid TLS config for provider %s: %w", endpoint.Name, err)
+		}
+	}
+	return nil
+}
+
+func (c Config) validateSubscriptionBatching() error {
+	for _, endpoint := range c.ProviderEndpoints {
+		batching, err := endpoint.SubscriptionBatching()
+		if err != nil {
+			return err
+		}
+		// the polling providers have no websocket subscriptions to pace
+		if batching.Enabled() && endpoint.Websocket == "" {
+			return fmt.Errorf("subscription batching set for provider %s without a websocket", endpoint.Name)
+		}
+	}
+	return nil
+}
+
+func (c Config) validateCompression() error {
+	for _, endpoint := range c.ProviderEndpoints {
+		if _, err := endpoint.MessageCompression(); err != nil {
+			return err
+		}
+		// the polling providers have no websocket messages to decompress
+		if endpoint.Compression != "" && endpoint.Websocket == "" {
+			return fmt.Errorf("compression set for provider %s without a websocket", endpoint.Name)
+		}
+	}
+	return nil
+}
+
+func (c Config) validateCandleGaps() error {
+	for _, endpoint := range c.ProviderEndpoints {
+		if _, _, err := endpoint.CandleGap(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (c Config) validateOrderBookDepths() error {
+	for _, endpoint := range c.ProviderEndpoints {
+		if _, _, err := endpoint.OrderBookDepthBand(); err != nil {
+			return err
+		}
+		if endpoint.OrderBookDepth == "" {
+			continue
+		}
+		if _, ok := SupportedOrderBookDepthProviders[endpoint.Name]; !ok {
+			return fmt.Errorf("provider %s does not support measuring order book depth", endpoint.Name)
+		}
+	}
+	return nil
+}
+
+// validateMaxSpreads rejects a max spread set on a pair none of whose
+// providers streams its best quote, which would never exclude a price.
+func (c Config) validateMaxSpreads() error {
+	if _, err := c.MaxSpreads(); err != nil {
+		return err
+	}
+	for _, cp := range c.CurrencyPairs {
+		if cp.MaxSpread == "" {
+			continue
+		}
+		supported := false
+		for _, providerName := range cp.Providers {
+			if _, ok := SupportedBestQuoteProviders[providerName]; ok {
+				supported = true
+				break
+			}
+		}
+		if !supported {
+			return fmt.Errorf("max spread of %s%s set but none of its providers streams the best quote", cp.Base, cp.Quote)
+		}
+	}
+	return nil
+}
+
+func (c Config) validateTickerReorderWindows() error {
+	for _, endpoint := range c.ProviderEndpoints {
+		if _, _, err := endpoint.ReorderWindow(); err != nil {
+			return err
+		}
+		if endpoint.TickerReorderWindow == "" {
+			continue
+		}
+		if _, ok := SupportedTickerReorderProviders[endpoint.Name]; !ok {
+			return fmt.Errorf("provider %s does not timestamp its ticks to reorder them", endpoint.Name)
+		}
+	}
+	return nil
+}
+
+func (c Config) validateCombinedStreams() error {
+	for _, endpoint := range c.ProviderEndpoints {
+		if endpoint.CombinedStream && endpoint.Name != provider.ProviderBinance {
+			return fmt.Errorf("provider %s does not support a combined stream", endpoint.Name)
+		}
+	}
+	return nil
+}
+
+func (c Config) validateProviderHeaders() error {
+	for _, endpoint := range c.ProviderEndpoints {
+		if _, err := endpoint.HTTPHeader(); err != nil {
+			return fmt.Errorf("invalid headers for provider %s: %w", endpoint.Name, err)
+		}
+	}
+	return nil
+}
+
+func (c Config) validateDuplicatePairs() error {
+	switch c.DuplicatePairs {
+	case "", DuplicatePairsError, DuplicatePairsMerge:
+		return nil
+	}
+	return fmt.Errorf(
+		"duplicate pairs must be %s or %s, got %s",
+		DuplicatePairsError,
+		DuplicatePairsMerge,
+		c.DuplicatePairs,
+	)
+}
+
+// supportsPriceType returns whether the given provider can price a pair by
+// the given derivative price type.
+func (c Config) validateInstanceLabels() error {
+	for _, label := range c.InstanceLabels() {
+		if !labelValueRegex.MatchString(label[1]) {
+			return fmt.Errorf("%s %q must only contain letters, digits, '_', '.', ':' and '-'", label[0], label[1])
+		}
+		for _, globalLabel := range c.Telemetry.GlobalLabels {
+			if len(globalLabel) > 0 && globalLabel[0] == label[0] {
+				return fmt.Errorf("%s is set both in the config and in the telemetry global labels", label[0])
+			}
+		}
+	}
+	return nil
+}
+
+func supportsPriceType(providerName types.ProviderName, priceType provider.PriceType) bool {
+	for _, pt := range SupportedDerivativePriceProviders[providerName] {
+		if pt == priceType {
+			return true
+		}
+	}
+	return false
+}
+
+func (c Config) validateCurrencyPairs() error {
+	for _, prov := range c.DefaultProviders {
+		if _, ok := SupportedProviders[prov]; !ok {
+			return unsupportedProviderError("default provider", prov)
+		}
+	}
+
+OUTER:
+	for _, cp := range c.CurrencyPairs {
+		if cp.Base == "" {
+			return fmt.Errorf("currency pair base cannot be empty")
+		}
+		if cp.Quote == "" {
+			return fmt.Errorf("currency pair quote cannot be empty")
+		}
+		if cp.Base == cp.Quote {
+			return fmt.Errorf("currency pair base and quote cannot be the same")
+		}
+		if len(cp.Providers) == 0 {
+			return fmt.Errorf("currency pair must have at least one provider")
+		}
+		for _, prov := range cp.Providers {
+			if _, ok := SupportedProviders[prov]; !ok {
+				return unsupportedProviderError("provider", prov)
+			}
+			if bool(SupportedProviders[prov]) && !hasAPIKey(prov, c.ProviderEndpoints) {
+				return fmt.Errorf("provider %s requires an API Key", prov)
+			}
+		}
+		if _, err := cp.toProviderAgreement(); err != nil {
+			return err
+		}
+		if err := cp.validateProviderRoles(); err != nil {
+			return err
+		}
+		if err := cp.validateMaxProviders(); err != nil {
+			return err
+		}
+		if !c.allowsQuote(cp.Quote) {
+			return fmt.Errorf("currency pair quote %s is not one of the allowed quotes", cp.Quote)
+		}
+		// verify the quote is USD or a conversion pair exists for it
+		for _, quote := range SupportedQuotes() {
+			if cp.Quote == quote {
+				continue OUTER
+			}
+		}
+		// forex quotes are converted with their configured USD rate
+		if c.hasForexRate(cp.Quote) {
+			continue
+		}
+		return fmt.Errorf("currency pair quote %s is not supported", cp.Quote)
+	}
+	return nil
+}
+
+// validateProviderRoles returns an error if a provider restricted to the
+// ticker or candle aggregate, required, breaking median ties or using its
+// candles as its spot price does not provide the pair, or if a provider is
+// restricted to both aggregates or uses the candles it is restricted from.
+func (cp CurrencyPair) validateProviderRoles() error {
+	pair := cp.Base + cp.Quote
+	for _, prov := range append(cp.TickerOnlyProviders, cp.CandleOnlyProviders...) {
+		if !hasProvider(cp.Providers, prov) {
+			return fmt.Errorf("provider %s restricted to a single aggregate of %s is not one of its providers", prov, pair)
+		}
+	}
+	for _, prov := range cp.TickerOnlyProviders {
+		if hasProvider(cp.CandleOnlyProviders, prov) {
+			return fmt.Errorf("provider %s cannot be both ticker and candle only for %s", prov, pair)
+		}
+	}
+	for _, prov := range cp.RequiredProviders {
+		if !hasProvider(cp.Providers, prov) {
+			return fmt.Errorf("required provider %s of %s is not one of its providers", prov, pair)
+		}
+	}
+	if cp.MedianTieBreak != "" && !hasProvider(cp.Providers, cp.MedianTieBreak) {
+		return fmt.Errorf("median tie break provider %s of %s is not one of its providers", cp.MedianTieBreak, pair)
+	}
+	for _, prov := range cp.CandleSpotProviders {
+		if !hasProvider(cp.Providers, prov) {
+			return fmt.Errorf("candle spot provider %s of %s is not one of its providers", prov, pair)
+		}
+		if hasProvider(cp.TickerOnlyProviders, prov) {
+			return fmt.Errorf("provider %s cannot be both ticker only and candle spot for %s", prov, pair)
+		}
+	}
+	return nil
+}
+
+// validateMaxProviders returns an error if the max providers of the pair does
+// not trim any of its providers.
+func (cp CurrencyPair) validateMaxProviders() error {
+	pair := cp.Base + cp.Quote
+	if cp.MaxProviders < 0 {
+		return fmt.Errorf("max providers of %s must not be negative", pair)
+	}
+	if cp.MaxProviders > 0 && cp.MaxProviders >= len(cp.Providers) {
+		return fmt.Errorf("max providers of %s must be less than its %d providers", pair, len(cp.Providers))
+	}
+	return nil
+}
+
+// toProviderAgreement parses the agreement requirement of the pair. The band
+// defaults to defaultAgreementBand.
+func (cp CurrencyPair) toProviderAgreement() (types.ProviderAgreement, error) {
+	pair := cp.Base + cp.Quote
+	switch {
+	case cp.MinAgreeingProviders < 0:
+		return types.ProviderAgreement{}, fmt.Errorf("min agreeing providers of %s must not be negative", pair)
+	case cp.MinAgreeingProviders == 0 && cp.AgreementBand != "":
+		return types.ProviderAgreement{}, fmt.Errorf("agreement band of %s requires min agreeing providers", pair)
+	case cp.MinAgreeingProviders == 1:
+		return types.ProviderAgreement{}, fmt.Errorf("min agreeing providers of %s must be at least 2", pair)
+	case cp.MinAgreeingProviders > len(cp.Providers):
+		return types.ProviderAgreement{}, fmt.Errorf(
+			"min agreeing providers of %s exceeds its %d providers",
+			pair,
+			len(cp.Providers),
+		)
+	}
+
+	band := defaultAgreementBand
+	if cp.AgreementBand != "" {
+		var err error
+		if band, err = sdk.NewDecFromStr(cp.AgreementBand); err != nil {
+			return types.ProviderAgreement{}, fmt.Errorf("agreement band of %s must be numeric: %w", pair, err)
+		}
+		if !band.IsPositive() || band.GTE(sdk.OneDec()) {
+			return types.ProviderAgreement{}, fmt.Errorf("agreement band of %s must be between 0 and 1", pair)
+		}
+	}
+	return types.ProviderAgreement{MinProviders: cp.MinAgreeingProviders, Band: band}, nil
+}
+
+func hasProvider(providers []types.ProviderName, providerName types.ProviderName) bool {
+	for _, prov := range providers {
+		if prov == providerName {
+			return true
+		}
+	}
+	return false
+}
+
+// hasForexRate returns true if the USD rate of the given forex currency is
+// configured as a currency pair.
+// validateAllowedQuotes returns an error if an allowed quote is not a
+// supported quote or a supported forex currency, or is allowed twice.
+func (c Config) validateAllowedQuotes() error {
+	supported := make(map[string]struct{})
+	for _, quote := range SupportedQuotes() {
+		supported[quote] = struct{}{}
+	}
+
+	allowed := make(map[string]struct{}, len(c.AllowedQuotes))
+	for _, quote := range c.AllowedQuotes {
+		if _, ok := allowed[quote]; ok {
+			return fmt.Errorf("duplicate allowed quote %s", quote)
+		}
+		allowed[quote] = struct{}{}
+
+		_, isSupported := supported[quote]
+		_, isForex := SupportedForexCurrencies[quote]
+		if !isSupported && !isForex {
+			return fmt.Errorf("allowed quote %s is not supported", quote)
+		}
+	}
+	return nil
+}
+
+// allowsQuote returns true if currency pairs may be quoted in the quote. Every
+// quote is allowed unless allowed quotes are set, and USD, which the votes and
+// the conversion rates are quoted in, is always allowed.
+func (c Config) allowsQuote(quote string) bool {
+	if len(c.AllowedQuotes) == 0 || quote == DenomUSD {
+		return true
+	}
+	for _, allowed := range c.AllowedQuotes {
+		if quote == allowed {
+			return true
+		}
+	}
+	return false
+}
+
+func (c Config) hasForexRate(base string) bool {
+	for _, cp := range c.CurrencyPairs {
+		if cp.Base == base && IsForexPair(types.CurrencyPair{Base: cp.Base, Quote: cp.Quote}) {
+			return true
+		}
+	}
+	return false
+}
+
+func (c *Config) setDefaults() {
+	if c.Server.ListenAddr == "" {
+		c.Server.ListenAddr = defaultListenAddr
+	}
+	if c.Server.WriteTimeout == "" {
+		c.Server.WriteTimeout = defaultSrvWriteTimeout.String()
+	}
+	if c.Profiling.ListenAddr == "" {
+		c.Profiling.ListenAddr = defaultProfilingListenAddr
+	}
+	if c.Server.ReadTimeout == "" {
+		c.Server.ReadTimeout = defaultSrvReadTimeout.String()
+	}
+	if c.ProviderTimeout == "" {
+		c.ProviderTimeout = defaultProviderTimeout.String()
+	}
+	if c.ProviderSilenceTimeout == "" {
+		c.ProviderSilenceTimeout = defaultProviderSilenceTimeout.String()
+	}
+	if c.MaxBufferedCandles == 0 {
+		c.MaxBufferedCandles = defaultMaxBufferedCandles
+	}
+	if c.ProviderHealthInterval == "" {
+		c.ProviderHealthInterval = defaultProviderHealthInterval.String()
+	}
+	if c.ReconnectCooldown == "" {
+		c.ReconnectCooldown = defaultReconnectCooldown.String()
+	}
+	if c.StartupPolicy == "" {
+		c.StartupPolicy = StartupPolicyWarn
+	}
+	if c.StartupTimeout == "" {
+		c.StartupTimeout = defaultStartupTimeout.String()
+	}
+	if c.StartupOrder == "" {
+		c.StartupOrder = StartupOrderChainFirst
+	}
+	if c.MaxClockSkew == "" {
+		c.MaxClockSkew = defaultMaxClockSkew.String()
+	}
+	if c.SubscriptionReconcile.Interval == "" {
+		c.SubscriptionReconcile.Interval = defaultReconcileInterval.String()
+	}
+	if c.SubscriptionReconcile.MaxAge == "" {
+		c.SubscriptionReconcile.MaxAge = defaultReconcileMaxAge.String()
+	}
+	c.setDefaultProviders()
+	if c.PriceCache.MaxAge == "" {
+		c.PriceCache.MaxAge = defaultPriceCacheMaxAge.String()
+	}
+	if c.SymbolCache.TTL == "" {
+		c.SymbolCache.TTL = defaultSymbolCacheTTL.String()
+	}
+	if c.SymbolCache.MaxRetries == 0 {
+		c.SymbolCache.MaxRetries = defaultSymbolFetchRetries
+	}
+	if c.Attestation.Timeout == "" {
+		c.Attestation.Timeout = defaultAttestationTimeout.String()
+	}
+	if c.Attestation.MaxRetries == 0 {
+		c.Attestation.MaxRetries = defaultAttestationMaxRetries
+	}
+	if c.Statsd.Prefix == "" {
+		c.Statsd.Prefix = defaultStatsdPrefix
+	}
+	if c.Statsd.FlushInterval == "" {
+		c.Statsd.FlushInterval = defaultStatsdFlushInterval.String()
+	}
+	if c.Webhook.RateLimit == "" {
+		c.Webhook.RateLimit = defaultWebhookRateLimit.String()
+	}
+	if c.Webhook.Timeout == "" {
+		c.Webhook.Timeout = defaultWebhookTimeout.String()
+	}
+	if c.PriceCache.WriteInterval == "" {
+		c.PriceCache.WriteInterval = defaultPriceCacheWriteInterval.String()
+	}
+	if c.DuplicatePairs == "" {
+		c.DuplicatePairs = DuplicatePairsError
+	}
+	if c.Log.MaxSize == 0 {
+		c.Log.MaxSize = defaultLogMaxSize
+	}
+}
+
+// setDefaultProviders sets the default providers on every currency pair which
+// does not define its own providers.
+func (c *Config) setDefaultProviders() {
+	if len(c.DefaultProviders) == 0 {
+		return
+	}
+	for i, cp := range c.CurrencyPairs {
+		if len(cp.Providers) == 0 {
+			c.CurrencyPairs[i].Providers = append([]types.ProviderName{}, c.DefaultProviders...)
+		}
+	}
+}
+
+// ProviderPairs returns a map of provider.CurrencyPair where the key is the
+// provider name.
+func (c Config) ProviderPairs() map[types.ProviderName][]types.CurrencyPair {
+	providerPairs := make(map[types.ProviderName][]types.CurrencyPair)
+
+	for _, pair := range c.CurrencyPairs {
+		pairAddresses := c.pairAddresses(pair)
+		for _, provider := range pair.Providers {
+			hasAddress := false
+			for _, uniPair := range pairAddresses {
+				if uniPair.Provider != provider {
+					continue
+				}
+				hasAddress = true
+				if uniPair.Address != "" {
+					providerPairs[uniPair.Provider] = append(providerPairs[uniPair.Provider], types.CurrencyPair{
+						Base:    pair.Base,
+						Quote:   pair.Quote,
+						Address: uniPair.Address,
+					})
+				}
+			}
+			// providers without a pair address, ex. centralized exchanges,
+			// are queried by the pair itself
+			if !hasAddress {
+				providerPairs[provider] = append(providerPairs[provider], types.CurrencyPair{
+					Base:  pair.Base,
+					Quote: pair.Quote,
+				})
+			}
+		}
+	}
+	return providerPairs
+}
+
+// pairAddresses returns the pair addresses of a currency pair, including the
+// aggregator of its chainlink feed.
+func (c Config) pairAddresses(pair CurrencyPair) []PairAddressProvider {
+	for _, feed := range c.Chainlink.Feeds {
+		if feed.Base == pair.Base && feed.Quote == pair.Quote {
+			return append(append([]PairAddressProvider{}, pair.PairAddress...), PairAddressProvider{
+				Address:  feed.Address,
+				Provider: provider.ProviderChainlink,
+			})
+		}
+	}
+	return pair.PairAddress
+}
+
+// ProviderEndpointsMap converts the provider_endpoints from the config
+// file into a map of provider.Endpoint where the key is the provider name.
+// The chainlink, osmosis-twap, uniswap-v3, curve, dydx and redemption-rate
+// endpoints are set from their sections.
+func (c Config) ProviderEndpointsMap() map[types.ProviderName]provider.Endpoint {
+	endpoints := make(map[types.ProviderName]provider.Endpoint, len(c.ProviderEndpoints))
+	for _, endpoint := range c.ProviderEndpoints {
+		endpoints[endpoint.Name] = endpoint
+	}
+
+	if c.Chainlink.RPC != "" {
+		endpoint := endpoints[provider.ProviderChainlink]
+		endpoint.Name = provider.ProviderChainlink
+		endpoint.Rest = c.Chainlink.RPC
+		endpoint.MaxRoundAges = c.chainlinkMaxRoundAges()
+		endpoints[provider.ProviderChainlink] = endpoint
+	}
+	if c.OsmosisTwap.LCD != "" {
+		endpoint := endpoints[provider.ProviderOsmosisTwap]
+		endpoint.Name = provider.ProviderOsmosisTwap
+		endpoint.Rest = c.OsmosisTwap.LCD
+		endpoint.TwapPools = c.osmosisTwapPools()
+		endpoints[provider.ProviderOsmosisTwap] = endpoint
+	}
+	if c.UniswapV3.RPC != "" {
+		endpoint := endpoints[provider.ProviderUniswapV3]
+		endpoint.Name = provider.ProviderUniswapV3
+		endpoint.Rest = c.UniswapV3.RPC
+		endpoint.UniswapV3Pools = c.uniswapV3Pools()
+		endpoints[provider.ProviderUniswapV3] = endpoint
+	}
+	if c.Curve.RPC != "" {
+		endpoint := endpoints[provider.ProviderCurve]
+		endpoint.Name = provider.ProviderCurve
+		endpoint.Rest = c.Curve.RPC
+		endpoint.CurvePools = c.curvePools()
+		endpoints[provider.ProviderCurve] = endpoint
+	}
+	if len(c.Dydx.Markets) > 0 {
+		endpoint := endpoints[provider.ProviderDydx]
+		endpoint.Name = provider.ProviderDydx
+		if c.Dydx.Indexer != "" {
+			endpoint.Rest = c.Dydx.Indexer
+		} else if endpoint.Rest == "" {
+			endpoint.Rest = defaultDydxIndexer
+		}
+		endpoint.DydxMarkets = c.dydxMarkets()
+		endpoint.PollInterval, _ = time.ParseDuration(c.Dydx.PollInterval)
+		endpoints[provider.ProviderDydx] = endpoint
+	}
+	if len(c.RedemptionRate.Assets) > 0 {
+		endpoint := endpoints[provider.ProviderRedemptionRate]
+		endpoint.Name = provider.ProviderRedemptionRate
+		endpoint.RedemptionRates = c.redemptionRateQueries()
+		endpoint.PollInterval, _ = time.ParseDuration(c.RedemptionRate.PollInterval)
+		endpoints[provider.ProviderRedemptionRate] = endpoint
+	}
+	for providerName, intervals := range c.autoCandleIntervals() {
+		// candle intervals set on the endpoint override the selected ones
+		endpoint := endpoints[providerName]
+		if len(endpoint.CandleIntervals) == 0 {
+			endpoint.CandleIntervals = intervals
+			endpoints[providerName] = endpoint
+		}
+	}
+	return endpoints
+}
+
+// redemptionRateQueries returns the redemption rate query of every
+// redemption-rate pair by its pair. The max age is validated when the config
+// is loaded.
+func (c Config) redemptionRateQueries() map[string]provider.RedemptionRateQuery {
+	maxAge, _ := time.ParseDuration(c.RedemptionRate.MaxAge)
+	queries := make(map[string]provider.RedemptionRateQuery, len(c.RedemptionRate.Assets))
+	for _, asset := range c.RedemptionRate.Assets {
+		queries[asset.Base+asset.Underlying] = provider.RedemptionRateQuery{
+			Rest:   asset.Rest,
+			Path:   asset.Path,
+			Field:  asset.Field,
+			MaxAge: maxAge,
+		}
+	}
+	return queries
+}
+
+// RedemptionRateUnderlyings returns the underlying asset of every liquid
+// staking asset priced by its redemption rate, by the liquid staking asset.
+func (c Config) RedemptionRateUnderlyings() map[string]string {
+	underlyings := make(map[string]string, len(c.RedemptionRate.Assets))
+	for _, asset := range c.RedemptionRate.Assets {
+		underlyings[asset.Base] = asset.Underlying
+	}
+	return underlyings
+}
+
+// dydxMarkets returns the dYdX market of every dydx pair by its pair.
+func (c Config) dydxMarkets() map[string]string {
+	markets := make(map[string]string, len(c.Dydx.Markets))
+	for _, market := range c.Dydx.Markets {
+		markets[market.Base+market.Quote] = market.Market
+	}
+	return markets
+}
+
+// curvePools returns the pool of every curve pair by its pair.
+func (c Config) curvePools() map[string]provider.CurvePool {
+	pools := make(map[string]provider.CurvePool, len(c.Curve.Pools))
+	for _, pool := range c.Curve.Pools {
+		pools[pool.Base+pool.Quote] = provider.CurvePool{
+			Address:    pool.Address,
+			BaseIndex:  pool.BaseIndex,
+			QuoteIndex: pool.QuoteIndex,
+		}
+	}
+	return pools
+}
+
+// uniswapV3Pools returns the pool of every uniswap v3 pair by its pair, with
+// the pool's own TWAP window or else the section's. The windows are
+// validated when the config is loaded.
+func (c Config) uniswapV3Pools() map[string]provider.UniswapV3Pool {
+	pools := make(map[string]provider.UniswapV3Pool, len(c.UniswapV3.Pools))
+	for _, pool := range c.UniswapV3.Pools {
+		window := pool.TwapWindow
+		if window == "" {
+			window = c.UniswapV3.TwapWindow
+		}
+		duration, _ := time.ParseDuration(window)
+		pools[pool.Base+pool.Quote] = provider.UniswapV3Pool{
+			Address:    pool.Address,
+			BaseToken:  pool.BaseToken,
+			FeeTier:    pool.FeeTier,
+			TwapWindow: duration,
+		}
+	}
+	return pools
+}
+
+// osmosisTwapPools returns the pool of every osmosis twap pair by its pair,
+// with the pool's own window or else the section's. The windows are
+// validated when the config is loaded.
+func (c Config) osmosisTwapPools() map[string]provider.TwapPool {
+	pools := make(map[string]provider.TwapPool, len(c.OsmosisTwap.Pools))
+	for _, pool := range c.OsmosisTwap.Pools {
+		window := pool.Window
+		if window == "" {
+			window = c.OsmosisTwap.Window
+		}
+		duration, _ := time.ParseDuration(window)
+		pools[pool.Base+pool.Quote] = provider.TwapPool{
+			PoolID:        pool.PoolID,
+			BaseDenom:     pool.BaseDenom,
+			QuoteDenom:    pool.QuoteDenom,
+			BaseDecimals:  pool.BaseDecimals,
+			QuoteDecimals: pool.QuoteDecimals,
+			Window:        duration,
+		}
+	}
+	return pools
+}
+
+// chainlinkMaxRoundAges returns the max round age of every chainlink feed by
+// its pair, which is the feed's own or else the section's. The ages are
+// validated when the config is loaded.
+func (c Config) chainlinkMaxRoundAges() map[string]time.Duration {
+	maxRoundAges := make(map[string]time.Duration, len(c.Chainlink.Feeds))
+	for _, feed := range c.Chainlink.Feeds {
+		maxRoundAge := feed.MaxRoundAge
+		if maxRoundAge == "" {
+			maxRoundAge = c.Chainlink.MaxRoundAge
+		}
+		if age, err := time.ParseDuration(maxRoundAge); err == nil {
+			maxRoundAges[feed.Base+feed.Quote] = age
+		}
+	}
+	return maxRoundAges
+}
+
+// DeviationsMap converts the deviation_thresholds from the config file into
+// a map of sdk.Dec where the key is the base asset.
+func (c Config) DeviationsMap() (map[string]sdk.Dec, error) {
+	deviations := make(map[string]sdk.Dec, len(c.Deviations))
+	for _, deviation := range c.Deviations {
+		threshold, err := sdk.NewDecFromStr(deviation.Threshold)
+		if err != nil {
+			return nil, err
+		}
+		deviations[deviation.Base] = threshold
+	}
+	return deviations, nil
+}
+
+// PriceBandsMap converts the price_bands from the config file into a map of
+// price bands where the key is the base asset.
+func (c Config) PriceBandsMap() (map[string]types.PriceBand, error) {
+	priceBands := make(map[string]types.PriceBand, len(c.PriceBands))
+	for _, priceBand := range c.PriceBands {
+		band, err := priceBand.toPriceBand()
+		if err != nil {
+			return nil, err
+		}
+		priceBands[priceBand.Base] = band
+	}
+	return priceBands, nil
+}
+
+// TvwapWeightingsMap converts the tvwap_weightings from the config file into
+// a map of TVWAP weightings where the key is the base asset.
+func (c Config) TvwapWeightingsMap() (map[string]types.TvwapWeighting, error) {
+	tvwapWeightings := make(map[string]types.TvwapWeighting, len(c.TvwapWeightings))
+	for _, tvwapWeighting := range c.TvwapWeightings {
+		weighting, err := tvwapWeighting.toTvwapWeighting()
+		if err != nil {
+			return nil, err
+		}
+		tvwapWeightings[tvwapWeighting.Base] = weighting
+	}
+
+	windows, err := c.TwapWindows()
+	if err != nil {
+		return nil, err
+	}
+	for base, window := range windows {
+		weighting := tvwapWeightings[base]
+		weighting.Window = window
+		tvwapWeightings[base] = weighting
+	}
+	return tvwapWeightings, nil
+}
+
+// LatencyThresholdsSlice converts the latency_thresholds from the config file
+// into latency thresholds sorted by ascending latency. A higher latency must
+// not have a higher weight.
+func (c Config) LatencyThresholdsSlice() ([]types.LatencyThreshold, error) {
+	thresholds := make([]types.LatencyThreshold, 0, len(c.LatencyThresholds))
+	for _, latencyThreshold := range c.LatencyThresholds {
+		latency, err := time.ParseDuration(latencyThreshold.Latency)
+		if err != nil {
+			return nil, fmt.Errorf("latency threshold latency must be a duration: %w", err)
+		}
+		if latency <= 0 {
+			return nil, fmt.Errorf("latency threshold latency must be positive")
+		}
+
+		weight, err := sdk.NewDecFromStr(latencyThreshold.Weight)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse latency threshold weight for %s: %w", latency, err)
+		}
+		if weight.IsNegative() || weight.GTE(sdk.OneDec()) {
+			return nil, fmt.Errorf("latency threshold weight for %s must be at least 0 and less than 1", latency)
+		}
+
+		thresholds = append(thresholds, types.LatencyThreshold{Latency: latency, Weight: weight})
+	}
+
+	sort.Slice(thresholds, func(i, j int) bool {
+		return thresholds[i].Latency < thresholds[j].Latency
+	})
+	for i := 1; i < len(thresholds); i++ {
+		if thresholds[i].Latency == thresholds[i-1].Latency {
+			return nil, fmt.Errorf("duplicate latency threshold for %s", thresholds[i].Latency)
+		}
+		if thresholds[i].Weight.GT(thresholds[i-1].Weight) {
+			return nil, fmt.Errorf(
+				"latency threshold weight for %s must not exceed the weight for %s",
+				thresholds[i].Latency, thresholds[i-1].Latency,
+			)
+		}
+	}
+	return thresholds, nil
+}
+
+// TickerWindowsMap converts the ticker_windows from the config file into the
+// number of ticker samples of each provider pair.
+func (c Config) TickerWindowsMap() types.TickerWindows {
+	tickerWindows := make(types.TickerWindows)
+	for _, tickerWindow := range c.TickerWindows {
+		if _, ok := tickerWindows[tickerWindow.Provider]; !ok {
+			tickerWindows[tickerWindow.Provider] = make(map[types.CurrencyPair]int)
+		}
+		cp := types.CurrencyPair{Base: tickerWindow.Base, Quote: tickerWindow.Quote}
+		tickerWindows[tickerWindow.Provider][cp] = tickerWindow.Samples
+	}
+	return tickerWindows
+}
+
+// ReferencePricesMap converts the reference_prices from the config file into
+// a map of reference prices where the key is the base asset.
+func (c Config) ReferencePricesMap() (map[string]types.ReferencePrice, error) {
+	referencePrices := make(map[string]types.ReferencePrice, len(c.ReferencePrices))
+	for _, referencePrice := range c.ReferencePrices {
+		threshold, err := sdk.NewDecFromStr(referencePrice.Threshold)
+		if err != nil {
+			return nil, err
+		}
+		referencePrices[referencePrice.Base] = types.ReferencePrice{
+			Pair:      types.CurrencyPair{Base: referencePrice.Base, Quote: referencePrice.Quote},
+			Provider:  referencePrice.Provider,
+			Threshold: threshold,
+		}
+	}
+	return referencePrices, nil
+}
+
+// AnchorPairsMap converts the anchor_pairs from the config file into a map of
+// anchor pairs where the key is the base asset.
+func (c Config) AnchorPairsMap() (map[string]types.AnchorPair, error) {
+	anchorPairs := make(map[string]types.AnchorPair, len(c.AnchorPairs))
+	for _, anchorPair := range c.AnchorPairs {
+		pair, err := anchorPair.toAnchorPair()
+		if err != nil {
+			return nil, err
+		}
+		anchorPairs[anchorPair.Base] = pair
+	}
+	return anchorPairs, nil
+}
+
+// ConversionRoutesMap converts the conversion_routes from the config file into
+// a map of the quotes in order of preference where the key is the base asset.
+func (c Config) ConversionRoutesMap() map[string][]string {
+	conversionRoutes := make(map[string][]string, len(c.ConversionRoutes))
+	for _, route := range c.ConversionRoutes {
+		conversionRoutes[route.Base] = route.Quotes
+	}
+	return conversionRoutes
+}
+
+// ZeroVolumeWeightDec parses the volume weighting tickers with a zero or
+// missing volume in their VWAP. It is zero, which excludes them, if unset.
+func (c Config) ZeroVolumeWeightDec() (sdk.Dec, error) {
+	if c.ZeroVolumeWeight == "" {
+		return sdk.ZeroDec(), nil
+	}
+	zeroVolumeWeight, err := sdk.NewDecFromStr(c.ZeroVolumeWeight)
+	if err != nil {
+		return sdk.Dec{}, fmt.Errorf("zero volume weight must be numeric: %w", err)
+	}
+	return zeroVolumeWeight, nil
+}
+
+// WebhookPriceChangeDec parses the price change firing a webhook alert. It is
+// nil if unset, which disables price change alerts.
+func (c Config) WebhookPriceChangeDec() (sdk.Dec, error) {
+	if c.Webhook.PriceChange == "" {
+		return sdk.Dec{}, nil
+	}
+	priceChange, err := sdk.NewDecFromStr(c.Webhook.PriceChange)
+	if err != nil {
+		return sdk.Dec{}, fmt.Errorf("webhook price change must be numeric: %w", err)
+	}
+	if !priceChange.IsPositive() {
+		return sdk.Dec{}, fmt.Errorf("webhook price change must be positive")
+	}
+	return priceChange, nil
+}
+
+// VoteWarmupDuration parses the duration of the vote warm-up. It is zero if
+// unset.
+func (c Config) VoteWarmupDuration() (time.Duration, error) {
+	if c.VoteWarmup.Duration == "" {
+		return 0, nil
+	}
+	duration, err := time.ParseDuration(c.VoteWarmup.Duration)
+	if err != nil {
+		return 0, fmt.Errorf("vote warm-up duration must be a duration: %w", err)
+	}
+	return duration, nil
+}
+
+// SubscriptionReconcileDurations parses the interval of the subscription
+// reconciliation and the max age of the market data of a pair. Both are zero
+// if unset.
+func (c Config) SubscriptionReconcileDurations() (time.Duration, time.Duration, error) {
+	var interval, maxAge time.Duration
+	if c.SubscriptionReconcile.Interval != "" {
+		var err error
+		interval, err = time.ParseDuration(c.SubscriptionReconcile.Interval)
+		if err != nil {
+			return 0, 0, fmt.Errorf("subscription reconcile interval must be a duration: %w", err)
+		}
+	}
+	if c.SubscriptionReconcile.MaxAge != "" {
+		var err error
+		maxAge, err = time.ParseDuration(c.SubscriptionReconcile.MaxAge)
+		if err != nil {
+			return 0, 0, fmt.Errorf("subscription reconcile max age must be a duration: %w", err)
+		}
+	}
+	return interval, maxAge, nil
+}
+
+// DynamicPairSettings returns the settings of the dynamic pairs, or nil if
+// they are not enabled.
+func (c Config) DynamicPairSettings() (*types.DynamicPairs, error) {
+	if !c.DynamicPairs.Enabled {
+		return nil, nil
+	}
+
+	refreshInterval, err := c.dynamicPairsRefreshInterval()
+	if err != nil {
+		return nil, err
+	}
+	return &types.DynamicPairs{
+		Providers:       c.DynamicPairs.Providers,
+		Quote:           strings.ToUpper(c.DynamicPairs.Quote),
+		Quotes:          c.dynamicPairQuotes(),
+		RefreshInterval: refreshInterval,
+	}, nil
+}
+
+// dynamicPairQuotes returns the quotes of the dynamic pairs set by base.
+// Bases and quotes are upper-cased, as the keys of the quotes are lower-cased
+// when the config is loaded.
+func (c Config) dynamicPairQuotes() map[string]string {
+	quotes := make(map[string]string, len(c.DynamicPairs.Quotes))
+	for base, quote := range c.DynamicPairs.Quotes {
+		quotes[strings.ToUpper(base)] = strings.ToUpper(quote)
+	}
+	return quotes
+}
+
+// dynamicPairsRefreshInterval returns how often the dynamic pairs are derived
+// again from the accept list.
+func (c Config) dynamicPairsRefreshInterval() (time.Duration, error) {
+	if c.DynamicPairs.RefreshInterval == "" {
+		return defaultDynamicPairsRefresh, nil
+	}
+
+	interval, err := time.ParseDuration(c.DynamicPairs.RefreshInterval)
+	if err != nil {
+		return 0, fmt.Errorf("dynamic pairs refresh interval must be a duration: %w", err)
+	}
+	if interval <= 0 {
+		return 0, fmt.Errorf("dynamic pairs refresh interval must be positive")
+	}
+	return interval, nil
+}
+
+// VoteSafetyMarginDuration returns the minimum time that must remain in the
+// voting period to broadcast a pre-vote or vote, which is zero if the guard is
+// disabled.
+func (c Config) VoteSafetyMarginDuration() (time.Duration, error) {
+	if c.VoteSafetyMargin == "" {
+		return 0, nil
+	}
+	margin, err := time.ParseDuration(c.VoteSafetyMargin)
+	if err != nil {
+		return 0, fmt.Errorf("vote safety margin must be a duration: %w", err)
+	}
+	return margin, nil
+}
+
+// LogMaxAge returns the age after which rotated log files are removed, which
+// is zero if they are kept.
+func (c Config) LogMaxAge() (time.Duration, error) {
+	if c.Log.MaxAge == "" {
+		return 0, nil
+	}
+	maxAge, err := time.ParseDuration(c.Log.MaxAge)
+	if err != nil {
+		return 0, fmt.Errorf("log max age must be a duration: %w", err)
+	}
+	if maxAge < 0 {
+		return 0, fmt.Errorf("log max age must not be negative")
+	}
+	return maxAge, nil
+}
+
+// InstanceLabels returns the instance_id and environment labels which are set
+// as name and value pairs, to be merged into the telemetry global labels.
+func (c Config) InstanceLabels() [][]string {
+	labels := [][]string{}
+	if c.InstanceID != "" {
+		labels = append(labels, []string{InstanceIDLabel, c.InstanceID})
+	}
+	if c.Environment != "" {
+		labels = append(labels, []string{EnvironmentLabel, c.Environment})
+	}
+	return labels
+}
+
+// InstanceLogger returns the logger with the instance labels added as fields.
+func (c Config) InstanceLogger(logger zerolog.Logger) zerolog.Logger {
+	logCtx := logger.With()
+	for _, label := range c.InstanceLabels() {
+		logCtx = logCtx.Str(label[0], label[1])
+	}
+	return logCtx.Logger()
+}
+
+// VoteMemoTemplate parses the vote_memo template of the vote transactions. It
+// returns nil if no memo is set.
+func (c Config) VoteMemoTemplate() (*client.Memo, error) {
+	if c.VoteMemo == "" {
+		return nil, nil
+	}
+	return client.NewMemo(c.VoteMemo, c.InstanceID, c.Environment)
+}
+
+// OracleVoteBuilder returns the builder of the vote_builder message format,
+// the Umee format if none is set, submitting the messages of the granter if
+// authz is enabled.
+func (c Config) OracleVoteBuilder() (client.OracleVoteBuilder, error) {
+	builder, err := client.NewOracleVoteBuilder(c.VoteBuilder)
+	if err != nil {
+		return nil, err
+	}
+	if c.Authz.Enabled {
+		return client.NewAuthzVoteBuilder(builder, c.Authz.Granter), nil
+	}
+	return builder, nil
+}
+
+// ProviderRoles returns the providers restricted to the ticker or candle
+// aggregate of a currency pair.
+func (c Config) ProviderRoles() types.ProviderRoles {
+	providerRoles := make(types.ProviderRoles)
+	setRole := func(providerName types.ProviderName, cp types.CurrencyPair, role types.ProviderRole) {
+		if _, ok := providerRoles[providerName]; !ok {
+			providerRoles[providerName] = make(map[types.CurrencyPair]types.ProviderRole)
+		}
+		providerRoles[providerName][cp] = role
+	}
+
+	for _, pair := range c.CurrencyPairs {
+		cp := types.CurrencyPair{Base: pair.Base, Quote: pair.Quote}
+		for _, prov := range pair.TickerOnlyProviders {
+			setRole(prov, cp, types.ProviderRoleTicker)
+		}
+		for _, prov := range pair.CandleOnlyProviders {
+			setRole(prov, cp, types.ProviderRoleCandle)
+		}
+	}
+	return providerRoles
+}
+
+// SpotSources returns the providers using the close of their latest candle as
+// their spot price of a currency pair. As prices are converted to USD before
+// they are aggregated, the USD pair of the base uses the same spot source.
+func (c Config) SpotSources() types.SpotSources {
+	spotSources := make(types.SpotSources)
+	for _, pair := range c.CurrencyPairs {
+		for _, prov := range pair.CandleSpotProviders {
+			if _, ok := spotSources[prov]; !ok {
+				spotSources[prov] = make(map[types.CurrencyPair]types.SpotSource)
+			}
+			spotSources[prov][types.CurrencyPair{Base: pair.Base, Quote: pair.Quote}] = types.SpotSourceCandle
+			spotSources[prov][types.CurrencyPair{Base: pair.Base, Quote: DenomUSD}] = types.SpotSourceCandle
+		}
+	}
+	return spotSources
+}
+
+// ProviderAgreements returns the agreement requirements of the currency pairs
+// which set min_agreeing_providers.
+func (c Config) ProviderAgreements() (map[types.CurrencyPair]types.ProviderAgreement, error) {
+	providerAgreements := make(map[types.CurrencyPair]types.ProviderAgreement)
+	for _, pair := range c.CurrencyPairs {
+		if pair.MinAgreeingProviders == 0 {
+			continue
+		}
+		agreement, err := pair.toProviderAgreement()
+		if err != nil {
+			return nil, err
+		}
+		providerAgreements[types.CurrencyPair{Base: pair.Base, Quote: pair.Quote}] = agreement
+	}
+	return providerAgreements, nil
+}
+
+// RequiredProviders returns the required providers of the currency pairs
+// which set required_providers.
+func (c Config) RequiredProviders() map[types.CurrencyPair][]types.ProviderName {
+	requiredProviders := make(map[types.CurrencyPair][]types.ProviderName)
+	for _, pair := range c.CurrencyPairs {
+		if len(pair.RequiredProviders) == 0 {
+			continue
+		}
+		requiredProviders[types.CurrencyPair{Base: pair.Base, Quote: pair.Quote}] = pair.RequiredProviders
+	}
+	return requiredProviders
+}
+
+// ProviderGroupsMap returns the provider group of every provider in one, by
+// provider.
+func (c Config) ProviderGroupsMap() types.ProviderGroups {
+	providerGroups := make(types.ProviderGroups)
+	for _, group := range c.ProviderGroups {
+		for _, prov := range group.Providers {
+			providerGroups[prov] = group.Name
+		}
+	}
+	return providerGroups
+}
+
+// MinProviderGroups returns the min provider groups of the currency pairs
+// which set min_provider_groups.
+func (c Config) MinProviderGroups() map[types.CurrencyPair]int {
+	minProviderGroups := make(map[types.CurrencyPair]int)
+	for _, pair := range c.CurrencyPairs {
+		if pair.MinProviderGroups == 0 {
+			continue
+		}
+		minProviderGroups[types.CurrencyPair{Base: pair.Base, Quote: pair.Quote}] = pair.MinProviderGroups
+	}
+	return minProviderGroups
+}
+
+// MaxProviders returns the max providers of the currency pairs which set
+// max_providers.
+func (c Config) MaxProviders() map[types.CurrencyPair]int {
+	maxProviders := make(map[types.CurrencyPair]int)
+	for _, pair := range c.CurrencyPairs {
+		if pair.MaxProviders > 0 {
+			maxProviders[types.CurrencyPair{Base: pair.Base, Quote: pair.Quote}] = pair.MaxProviders
+		}
+	}
+	return maxProviders
+}
+
+// VotePriorities returns the vote priority of every configured base, which is
+// DefaultVotePriority unless one of its pairs sets it.
+func (c Config) VotePriorities() map[string]int {
+	priorities := make(map[string]int)
+	for _, cp := range c.CurrencyPairs {
+		if cp.VotePriority != 0 {
+			priorities[cp.Base] = cp.VotePriority
+		} else if _, ok := priorities[cp.Base]; !ok {
+			priorities[cp.Base] = DefaultVotePriority
+		}
+	}
+	return priorities
+}
+
+// VoteExponents returns the vote exponent of every base whose prices are
+// scaled in the vote.
+func (c Config) VoteExponents() map[string]int {
+	exponents := make(map[string]int)
+	for _, cp := range c.CurrencyPairs {
+		if cp.VoteExponent != 0 {
+			exponents[cp.Base] = cp.VoteExponent
+		}
+	}
+	return exponents
+}
+
+// VoteRoundings returns the vote rounding of every base whose vote values are
+// rounded.
+func (c Config) VoteRoundings() map[string]types.VoteRounding {
+	roundings := make(map[string]types.VoteRounding)
+	for _, cp := range c.CurrencyPairs {
+		if cp.VoteRounding != "" {
+			roundings[cp.Base] = types.VoteRounding{
+				Decimals: cp.VoteDecimals,
+				Mode:     types.RoundingMode(cp.VoteRounding),
+			}
+		}
+	}
+	return roundings
+}
+
+// SpotOnlyBases returns the bases aggregated from the spot prices of their
+// providers only.
+func (c Config) SpotOnlyBases() map[string]struct{} {
+	bases := make(map[string]struct{})
+	for _, cp := range c.CurrencyPairs {
+		if cp.SpotOnly {
+			bases[cp.Base] = struct{}{}
+		}
+	}
+	return bases
+}
+
+// MedianTieBreaks returns the provider whose price is preferred over the
+// average of the two middle prices of the median of every base setting one.
+func (c Config) MedianTieBreaks() map[string]types.ProviderName {
+	tieBreaks := make(map[string]types.ProviderName)
+	for _, cp := range c.CurrencyPairs {
+		if cp.MedianTieBreak != "" {
+			tieBreaks[cp.Base] = cp.MedianTieBreak
+		}
+	}
+	return tieBreaks
+}
+
+// MaxVoteChanges returns the max vote change of every base whose voted price
+// is limited per voting period.
+func (c Config) MaxVoteChanges() (map[string]sdk.Dec, error) {
+	// the vote carries a rate per base, so the pairs of a base must agree
+	maxChanges := make(map[string]sdk.Dec)
+	seen := make(map[string]string)
+	for _, cp := range c.CurrencyPairs {
+		if maxChange, ok := seen[cp.Base]; ok && maxChange != cp.MaxVoteChange {
+			return nil, fmt.Errorf("conflicting max vote changes of the pairs of %s", cp.Base)
+		}
+		seen[cp.Base] = cp.MaxVoteChange
+		if cp.MaxVoteChange == "" {
+			continue
+		}
+
+		maxChange, err := sdk.NewDecFromStr(cp.MaxVoteChange)
+		if err != nil {
+			return nil, fmt.Errorf("invalid max vote change of %s%s: %w", cp.Base, cp.Quote, err)
+		}
+		if !maxChange.IsPositive() || maxChange.GT(sdk.OneDec()) {
+			return nil, fmt.Errorf("max vote change of %s%s must be greater than 0 and at most 1", cp.Base, cp.Quote)
+		}
+		maxChanges[cp.Base] = maxChange
+	}
+	return maxChanges, nil
+}
+
+// MaxForexAges returns the max age of the USD rate of the forex quote of every
+// pair which is excluded while that rate is stale.
+func (c Config) MaxForexAges() (map[types.CurrencyPair]time.Duration, error) {
+	maxAges := make(map[types.CurrencyPair]time.Duration)
+	for _, cp := range c.CurrencyPairs {
+		if cp.MaxForexAge == "" {
+			continue
+		}
+
+		if _, ok := SupportedForexCurrencies[cp.Quote]; !ok || !c.hasForexRate(cp.Quote) {
+			return nil, fmt.Errorf(
+				"max forex age of %s%s requires its quote to be a forex currency with a configured USD rate",
+				cp.Base,
+				cp.Quote,
+			)
+		}
+		maxAge, err := time.ParseDuration(cp.MaxForexAge)
+		if err != nil {
+			return nil, fmt.Errorf("max forex age of %s%s must be a duration: %w", cp.Base, cp.Quote, err)
+		}
+		if maxAge <= 0 {
+			return nil, fmt.Errorf("max forex age of %s%s must be positive", cp.Base, cp.Quote)
+		}
+		maxAges[types.CurrencyPair{Base: cp.Base, Quote: cp.Quote}] = maxAge
+	}
+	return maxAges, nil
+}
+
+// MinBookDepths returns the minimum order book depth of each currency pair
+// which sets one, below which the weight of a provider is scaled down.
+func (c Config) MinBookDepths() (map[types.CurrencyPair]sdk.Dec, error) {
+	minDepths := make(map[types.CurrencyPair]sdk.Dec)
+	for _, cp := range c.CurrencyPairs {
+		if cp.MinBookDepth == "" {
+			continue
+		}
+
+		minDepth, err := sdk.NewDecFromStr(cp.MinBookDepth)
+		if err != nil {
+			return nil, fmt.Errorf("min book depth of %s%s must be a decimal: %w", cp.Base, cp.Quote, err)
+		}
+		if !minDepth.IsPositive() {
+			return nil, fmt.Errorf("min book depth of %s%s must be positive", cp.Base, cp.Quote)
+		}
+		minDepths[types.CurrencyPair{Base: cp.Base, Quote: cp.Quote}] = minDepth
+	}
+	return minDepths, nil
+}
+
+// MaxSpreads returns the max bid/ask spread of each currency pair which sets
+// one, above which the price of a provider is excluded.
+func (c Config) MaxSpreads() (map[types.CurrencyPair]sdk.Dec, error) {
+	maxSpreads := make(map[types.CurrencyPair]sdk.Dec)
+	for _, cp := range c.CurrencyPairs {
+		if cp.MaxSpread == "" {
+			continue
+		}
+
+		maxSpread, err := sdk.NewDecFromStr(cp.MaxSpread)
+		if err != nil {
+			return nil, fmt.Errorf("max spread of %s%s must be a decimal: %w", cp.Base, cp.Quote, err)
+		}
+		if !maxSpread.IsPositive() || maxSpread.GT(sdk.OneDec()) {
+			return nil, fmt.Errorf("max spread of %s%s must be greater than 0 and at most 1", cp.Base, cp.Quote)
+		}
+		maxSpreads[types.CurrencyPair{Base: cp.Base, Quote: cp.Quote}] = maxSpread
+	}
+	return maxSpreads, nil
+}
+
+// TwapWindows returns the TWAP window of each base whose pairs set one. The
+// pairs of a base must not set different windows.
+func (c Config) TwapWindows() (map[string]time.Duration, error) {
+	windows := make(map[string]time.Duration)
+	for _, cp := range c.CurrencyPairs {
+		if cp.TwapWindow == "" {
+			continue
+		}
+
+		window, err := time.ParseDuration(cp.TwapWindow)
+		if err != nil {
+			return nil, fmt.Errorf("twap window of %s%s must be a duration: %w", cp.Base, cp.Quote, err)
+		}
+		if window <= 0 || window > maxTwapWindow {
+			return nil, fmt.Errorf("twap window of %s%s must be positive and at most %s", cp.Base, cp.Quote, maxTwapWindow)
+		}
+		if existing, ok := windows[cp.Base]; ok && existing != window {
+			return nil, fmt.Errorf("conflicting twap windows %s and %s for %s", existing, window, cp.Base)
+		}
+		windows[cp.Base] = window
+	}
+	return windows, nil
+}
+
+// toPriceBand parses the bounds of the price band. An empty bound is left
+// open.
+func (pb PriceBand) toPriceBand() (types.PriceBand, error) {
+	var (
+		band types.PriceBand
+		err  error
+	)
+	if pb.Min != "" {
+		if band.Min, err = sdk.NewDecFromStr(pb.Min); err != nil {
+			return types.PriceBand{}, fmt.Errorf("price band min for %s must be numeric: %w", pb.Base, err)
+		}
+	}
+	if pb.Max != "" {
+		if band.Max, err = sdk.NewDecFromStr(pb.Max); err != nil {
+			return types.PriceBand{}, fmt.Errorf("price band max for %s must be numeric: %w", pb.Base, err)
+		}
+	}
+	return band, nil
+}
+
+// toTvwapWeighting parses the mode and the half-life of the TVWAP weighting.
+// The half-life is required by the exponential mode only.
+func (tw TvwapWeighting) toTvwapWeighting() (types.TvwapWeighting, error) {
+	weighting := types.TvwapWeighting{Mode: types.TvwapWeightingMode(tw.Mode)}
+	if !weighting.Mode.IsValid() {
+		return types.TvwapWeighting{}, fmt.Errorf("invalid tvwap weighting mode %q for %s", tw.Mode, tw.Base)
+	}
+
+	combination, providerWeights, err := tw.toTvwapCombination()
+	if err != nil {
+		return types.TvwapWeighting{}, err
+	}
+	weighting.Combination = combination
+	weighting.ProviderWeights = providerWeights
+
+	if weighting.Mode != types.TvwapWeightingExponential {
+		if tw.HalfLife != "" {
+			return types.TvwapWeighting{}, fmt.Errorf("tvwap weighting half_life for %s requires the exponential mode", tw.Base)
+		}
+		return weighting, nil
+	}
+
+	halfLife, err := time.ParseDuration(tw.HalfLife)
+	if err != nil {
+		return types.TvwapWeighting{}, fmt.Errorf("failed to parse tvwap weighting half_life for %s: %w", tw.Base, err)
+	}
+	if halfLife <= 0 {
+		return types.TvwapWeighting{}, fmt.Errorf("tvwap weighting half_life for %s must be positive", tw.Base)
+	}
+	weighting.HalfLife = halfLife
+	return weighting, nil
+}
+
+// toTvwapCombination returns the TVWAP combination, the volume combination
+// by default, and the provider weights of the weighted combination, which
+// requires at least one positive weight.
+func (tw TvwapWeighting) toTvwapCombination() (types.TvwapCombination, map[types.ProviderName]sdk.Dec, error) {
+	combination := types.TvwapCombination(tw.Combination)
+	if combination == "" {
+		combination = types.TvwapCombinationVolume
+	}
+	if !combination.IsValid() {
+		return "", nil, fmt.Errorf("invalid tvwap combination %q for %s", tw.Combination, tw.Base)
+	}
+
+	if combination != types.TvwapCombinationWeighted {
+		if len(tw.ProviderWeights) > 0 {
+			return "", nil, fmt.Errorf("tvwap provider_weights for %s require the weighted combination", tw.Base)
+		}
+		return combination, nil, nil
+	}
+	if len(tw.ProviderWeights) == 0 {
+		return "", nil, fmt.Errorf("tvwap weighted combination for %s requires provider_weights", tw.Base)
+	}
+
+	providerWeights := make(map[types.ProviderName]sdk.Dec, len(tw.ProviderWeights))
+	for providerName, weight := range tw.ProviderWeights {
+		weightDec, err := sdk.NewDecFromStr(weight)
+		if err != nil {
+			return "", nil, fmt.Errorf(
+				"failed to parse tvwap provider weight of %s for %s: %w",
+				providerName,
+				tw.Base,
+				err,
+			)
+		}
+		if !weightDec.IsPositive() {
+			return "", nil, fmt.Errorf("tvwap provider weight of %s for %s must be positive", providerName, tw.Base)
+		}
+		providerWeights[types.ProviderName(providerName)] = weightDec
+	}
+	return combination, providerWeights, nil
+}
+
+func (ap AnchorPair) toAnchorPair() (types.AnchorPair, error) {
+	anchorPair := types.AnchorPair{
+		Pair:         types.CurrencyPair{Base: ap.Base, Quote: DenomUSD},
+		MinProviders: ap.MinProviders,
+		Deviation:    sdk.ZeroDec(),
+		Band:         sdk.ZeroDec(),
+	}
+
+	if ap.Deviation != "" {
+		deviation, err := sdk.NewDecFromStr(ap.Deviation)
+		if err != nil {
+			return types.AnchorPair{}, fmt.Errorf("anchor pair deviation for %s must be numeric: %w", ap.Base, err)
+		}
+		if !deviation.IsPositive() || deviation.GT(maxDeviationThreshold) {
+			return types.AnchorPair{}, fmt.Errorf("anchor pair deviation for %s must be positive and not exceed 3.0", ap.Base)
+		}
+		anchorPair.Deviation = deviation
+	}
+
+	if ap.Band != "" {
+		band, err := sdk.NewDecFromStr(ap.Band)
+		if err != nil {
+			return types.AnchorPair{}, fmt.Errorf("anchor pair band for %s must be numeric: %w", ap.Base, err)
+		}
+		if !band.IsPositive() || !band.LT(sdk.OneDec()) {
+			return types.AnchorPair{}, fmt.Errorf("anchor pair band for %s must be between 0 and 1", ap.Base)
+		}
+		anchorPair.Band = band
+	}
+
+	return anchorPair, nil
 }
 
 // ExpectedSymbols returns a slice of all unique base symbols from the config object.
@@ -1,12 +1,31 @@
 package config_test
 
 import (
+	"bytes"
 	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"fmt"
 	"io/ioutil"
+	"math/big"
+	"net"
+	"net/http"
+	"net/http/httptest"
 	"os"
+	"path/filepath"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/cosmos/cosmos-sdk/telemetry"
+	sdk "github.com/cosmos/cosmos-sdk/types"
 	"github.com/rs/zerolog"
 	"github.com/stretchr/testify/require"
 
@@ -56,6 +75,22 @@ func TestValidate(t *testing.T) {
 	emptyPairs := validConfig()
 	emptyPairs.CurrencyPairs = []config.CurrencyPair{}
 
+	validAllowedQuotes := validConfig()
+	validAllowedQuotes.AllowedQuotes = []string{"USDT", "USDC"}
+	validAllowedQuotes.CurrencyPairs = append(
+		validAllowedQuotes.CurrencyPairs,
+		config.CurrencyPair{Base: "USDT", Quote: "USD", Providers: []types.ProviderName{provider.ProviderKraken}},
+	)
+
+	disallowedQuote := validConfig()
+	disallowedQuote.AllowedQuotes = []string{"USDC"}
+
+	unsupportedAllowedQuote := validConfig()
+	unsupportedAllowedQuote.AllowedQuotes = []string{"USDT", "OJO"}
+
+	duplicateAllowedQuote := validConfig()
+	duplicateAllowedQuote.AllowedQuotes = []string{"USDT", "USDT"}
+
 	invalidBase := validConfig()
 	invalidBase.CurrencyPairs = []config.CurrencyPair{
 		{Base: "", Quote: "USDT", Providers: []types.ProviderName{provider.ProviderKraken}},
@@ -87,56 +122,3302 @@ func TestValidate(t *testing.T) {
 		},
 	}
 
-	testCases := []struct {
-		name      string
-		cfg       config.Config
-		expectErr bool
-	}{
+	validPriceType := validConfig()
+	validPriceType.CurrencyPairs = append(validPriceType.CurrencyPairs, config.CurrencyPair{
+		Base: "BTC", Quote: "USDT", Providers: []types.ProviderName{provider.ProviderOkx},
+	})
+	validPriceType.ProviderEndpoints = []provider.Endpoint{
 		{
-			"valid config",
-			validConfig(),
-			false,
+			Name:      provider.ProviderOkx,
+			Rest:      "https://www.okx.com",
+			Websocket: "ws.okx.com:8443",
+			PriceTypes: []provider.PairPriceType{
+				{Base: "BTC", Quote: "USDT", Type: provider.PriceTypeMark},
+			},
 		},
+	}
+
+	invalidPriceType := validPriceType
+	invalidPriceType.ProviderEndpoints = []provider.Endpoint{
 		{
-			"empty pairs",
-			emptyPairs,
-			true,
+			Name:      provider.ProviderOkx,
+			Rest:      "https://www.okx.com",
+			Websocket: "ws.okx.com:8443",
+			PriceTypes: []provider.PairPriceType{
+				{Base: "BTC", Quote: "USDT", Type: "funding"},
+			},
 		},
+	}
+
+	unsupportedPriceTypeProvider := validConfig()
+	unsupportedPriceTypeProvider.ProviderEndpoints = []provider.Endpoint{
 		{
-			"invalid base",
-			invalidBase,
-			true,
+			Name:      provider.ProviderKraken,
+			Rest:      "https://api.kraken.com",
+			Websocket: "ws.kraken.com",
+			PriceTypes: []provider.PairPriceType{
+				{Base: "ATOM", Quote: "USDT", Type: provider.PriceTypeIndex},
+			},
 		},
+	}
+
+	unconfiguredPriceTypePair := validPriceType
+	unconfiguredPriceTypePair.ProviderEndpoints = []provider.Endpoint{
 		{
-			"invalid quote",
-			invalidQuote,
-			true,
+			Name:      provider.ProviderOkx,
+			Rest:      "https://www.okx.com",
+			Websocket: "ws.okx.com:8443",
+			PriceTypes: []provider.PairPriceType{
+				{Base: "ETH", Quote: "USDT", Type: provider.PriceTypeMark},
+			},
+		},
+	}
+
+	invalidDefaultProvider := validConfig()
+	invalidDefaultProvider.DefaultProviders = []types.ProviderName{"foobar"}
+
+	forexQuote := validConfig()
+	forexQuote.CurrencyPairs = []config.CurrencyPair{
+		{Base: "ATOM", Quote: "EUR", Providers: []types.ProviderName{provider.ProviderKraken}},
+		{Base: "EUR", Quote: "USD", Providers: []types.ProviderName{provider.ProviderKraken, provider.ProviderMock}},
+	}
+
+	forexQuoteWithoutRate := validConfig()
+	forexQuoteWithoutRate.CurrencyPairs = []config.CurrencyPair{
+		{Base: "ATOM", Quote: "EUR", Providers: []types.ProviderName{provider.ProviderKraken}},
+	}
+
+	validPriceSource := validPriceType
+	validPriceSource.ProviderEndpoints = []provider.Endpoint{
+		{
+			Name:      provider.ProviderOkx,
+			Rest:      "https://www.okx.com",
+			Websocket: "ws.okx.com:8443",
+			PriceSources: []provider.PairPriceSource{
+				{Base: "BTC", Quote: "USDT", Source: provider.PriceSourceMid},
+			},
 		},
+	}
+
+	invalidPriceSource := validPriceType
+	invalidPriceSource.ProviderEndpoints = []provider.Endpoint{
 		{
-			"empty providers",
-			emptyProviders,
-			true,
+			Name:      provider.ProviderOkx,
+			Rest:      "https://www.okx.com",
+			Websocket: "ws.okx.com:8443",
+			PriceSources: []provider.PairPriceSource{
+				{Base: "BTC", Quote: "USDT", Source: "vwap"},
+			},
 		},
+	}
+
+	unsupportedPriceSourceProvider := validConfig()
+	unsupportedPriceSourceProvider.ProviderEndpoints = []provider.Endpoint{
 		{
-			"invalid endpoints",
-			invalidEndpoints,
-			true,
+			Name:      provider.ProviderKraken,
+			Rest:      "https://api.kraken.com",
+			Websocket: "ws.kraken.com",
+			PriceSources: []provider.PairPriceSource{
+				{Base: "ATOM", Quote: "USDT", Source: provider.PriceSourceBid},
+			},
 		},
+	}
+
+	derivativePriceSource := validPriceType
+	derivativePriceSource.ProviderEndpoints = []provider.Endpoint{
 		{
-			"invalid endpoint provider",
-			invalidEndpointsProvider,
-			true,
+			Name:      provider.ProviderOkx,
+			Rest:      "https://www.okx.com",
+			Websocket: "ws.okx.com:8443",
+			PriceTypes: []provider.PairPriceType{
+				{Base: "BTC", Quote: "USDT", Type: provider.PriceTypeMark},
+			},
+			PriceSources: []provider.PairPriceSource{
+				{Base: "BTC", Quote: "USDT", Source: provider.PriceSourceMid},
+			},
+		},
+	}
+
+	validProviderRoles := validConfig()
+	validProviderRoles.CurrencyPairs = []config.CurrencyPair{{
+		Base:                "ATOM",
+		Quote:               "USDT",
+		Providers:           []types.ProviderName{provider.ProviderKraken, provider.ProviderBinance},
+		TickerOnlyProviders: []types.ProviderName{provider.ProviderKraken},
+	}}
+
+	unconfiguredRoleProvider := validConfig()
+	unconfiguredRoleProvider.CurrencyPairs = []config.CurrencyPair{{
+		Base:                "ATOM",
+		Quote:               "USDT",
+		Providers:           []types.ProviderName{provider.ProviderKraken},
+		CandleOnlyProviders: []types.ProviderName{provider.ProviderBinance},
+	}}
+
+	conflictingProviderRoles := validConfig()
+	conflictingProviderRoles.CurrencyPairs = []config.CurrencyPair{{
+		Base:                "ATOM",
+		Quote:               "USDT",
+		Providers:           []types.ProviderName{provider.ProviderKraken},
+		TickerOnlyProviders: []types.ProviderName{provider.ProviderKraken},
+		CandleOnlyProviders: []types.ProviderName{provider.ProviderKraken},
+	}}
+
+	validRequiredProviders := validConfig()
+	validRequiredProviders.CurrencyPairs = []config.CurrencyPair{{
+		Base:              "ATOM",
+		Quote:             "USDT",
+		Providers:         []types.ProviderName{provider.ProviderKraken, provider.ProviderBinance},
+		RequiredProviders: []types.ProviderName{provider.ProviderKraken},
+	}}
+
+	unconfiguredRequiredProvider := validConfig()
+	unconfiguredRequiredProvider.CurrencyPairs = []config.CurrencyPair{{
+		Base:              "ATOM",
+		Quote:             "USDT",
+		Providers:         []types.ProviderName{provider.ProviderKraken},
+		RequiredProviders: []types.ProviderName{provider.ProviderBinance},
+	}}
+
+	providerGroupsConfig := func(minProviderGroups int, groups ...config.ProviderGroup) config.Config {
+		cfg := validConfig()
+		cfg.CurrencyPairs = []config.CurrencyPair{{
+			Base:  "ATOM",
+			Quote: "USDT",
+			Providers: []types.ProviderName{
+				provider.ProviderKraken,
+				provider.ProviderBinance,
+				provider.ProviderBinanceUS,
+			},
+			MinProviderGroups: minProviderGroups,
+		}}
+		cfg.ProviderGroups = groups
+		return cfg
+	}
+	binanceGroup := config.ProviderGroup{
+		Name:      "binance",
+		Providers: []types.ProviderName{provider.ProviderBinance, provider.ProviderBinanceUS},
+	}
+
+	validProviderGroups := providerGroupsConfig(2, binanceGroup)
+
+	unreachableProviderGroups := providerGroupsConfig(3, binanceGroup)
+
+	negativeProviderGroups := providerGroupsConfig(-1)
+
+	duplicateProviderGroup := providerGroupsConfig(2, binanceGroup, binanceGroup)
+
+	overlappingProviderGroups := providerGroupsConfig(2, binanceGroup, config.ProviderGroup{
+		Name:      "us",
+		Providers: []types.ProviderName{provider.ProviderBinanceUS},
+	})
+
+	misnamedProviderGroup := providerGroupsConfig(2, config.ProviderGroup{
+		Name:      "kraken",
+		Providers: []types.ProviderName{provider.ProviderBinance, provider.ProviderBinanceUS},
+	})
+
+	unsupportedProviderGroup := providerGroupsConfig(2, config.ProviderGroup{
+		Name:      "aggregators",
+		Providers: []types.ProviderName{"foo"},
+	})
+
+	spotSourcesConfig := func(candleSpot, tickerOnly []types.ProviderName) config.Config {
+		cfg := validConfig()
+		cfg.CurrencyPairs = []config.CurrencyPair{{
+			Base:                "ATOM",
+			Quote:               "USDT",
+			Providers:           []types.ProviderName{provider.ProviderKraken, provider.ProviderBinance},
+			TickerOnlyProviders: tickerOnly,
+			CandleSpotProviders: candleSpot,
+		}}
+		return cfg
+	}
+
+	maxProvidersConfig := func(maxProviders int) config.Config {
+		cfg := validConfig()
+		cfg.CurrencyPairs = []config.CurrencyPair{{
+			Base:         "ATOM",
+			Quote:        "USDT",
+			Providers:    []types.ProviderName{provider.ProviderKraken, provider.ProviderBinance, provider.ProviderOkx},
+			MaxProviders: maxProviders,
+		}}
+		return cfg
+	}
+	validMaxProviders := maxProvidersConfig(2)
+	negativeMaxProviders := maxProvidersConfig(-1)
+	untrimmedMaxProviders := maxProvidersConfig(3)
+
+	agreementConfig := func(minAgreeingProviders int, agreementBand string) config.Config {
+		cfg := validConfig()
+		cfg.CurrencyPairs = []config.CurrencyPair{{
+			Base:                 "ATOM",
+			Quote:                "USDT",
+			Providers:            []types.ProviderName{provider.ProviderKraken, provider.ProviderBinance},
+			MinAgreeingProviders: minAgreeingProviders,
+			AgreementBand:        agreementBand,
+		}}
+		return cfg
+	}
+	validProviderAgreement := agreementConfig(2, "0.005")
+	defaultAgreementBand := agreementConfig(2, "")
+	unreachableProviderAgreement := agreementConfig(3, "")
+	singleProviderAgreement := agreementConfig(1, "")
+	bandWithoutProviderAgreement := agreementConfig(0, "0.005")
+	invalidAgreementBand := agreementConfig(2, "1")
+
+	validPriceBand := validConfig()
+	validPriceBand.PriceBands = []config.PriceBand{
+		{Base: "ATOM", Min: "1", Max: "100"},
+		{Base: "OJO", Max: "1000"},
+	}
+
+	invalidPriceBandRange := validConfig()
+	invalidPriceBandRange.PriceBands = []config.PriceBand{{Base: "ATOM", Min: "100", Max: "1"}}
+
+	invalidPriceBandBound := validConfig()
+	invalidPriceBandBound.PriceBands = []config.PriceBand{{Base: "ATOM", Min: "one"}}
+
+	emptyPriceBand := validConfig()
+	emptyPriceBand.PriceBands = []config.PriceBand{{Base: "ATOM"}}
+
+	validTvwapWeighting := validConfig()
+	validTvwapWeighting.TvwapWeightings = []config.TvwapWeighting{
+		{Base: "ATOM", Mode: "exponential", HalfLife: "2m"},
+		{Base: "OJO", Mode: "uniform"},
+	}
+
+	invalidTvwapWeightingMode := validConfig()
+	invalidTvwapWeightingMode.TvwapWeightings = []config.TvwapWeighting{{Base: "ATOM", Mode: "quadratic"}}
+
+	missingTvwapWeightingHalfLife := validConfig()
+	missingTvwapWeightingHalfLife.TvwapWeightings = []config.TvwapWeighting{{Base: "ATOM", Mode: "exponential"}}
+
+	linearTvwapWeightingHalfLife := validConfig()
+	linearTvwapWeightingHalfLife.TvwapWeightings = []config.TvwapWeighting{{Base: "ATOM", Mode: "linear", HalfLife: "2m"}}
+
+	tvwapCombinationConfig := func(combination string, providerWeights map[string]string) config.Config {
+		cfg := validConfig()
+		cfg.TvwapWeightings = []config.TvwapWeighting{{
+			Base:            "ATOM",
+			Mode:            "linear",
+			Combination:     combination,
+			ProviderWeights: providerWeights,
+		}}
+		return cfg
+	}
+
+	duplicateTvwapWeighting := validConfig()
+	duplicateTvwapWeighting.TvwapWeightings = []config.TvwapWeighting{
+		{Base: "ATOM", Mode: "uniform"},
+		{Base: "ATOM", Mode: "linear"},
+	}
+
+	validReferencePrice := validConfig()
+	validReferencePrice.ReferencePrices = []config.ReferencePrice{
+		{Base: "ATOM", Quote: "USD", Provider: provider.ProviderKraken, Threshold: "2"},
+	}
+
+	anchorConfig := func(anchorPairs ...config.AnchorPair) config.Config {
+		cfg := validConfig()
+		cfg.CurrencyPairs = append(cfg.CurrencyPairs, config.CurrencyPair{
+			Base:      "USDT",
+			Quote:     "USD",
+			Providers: []types.ProviderName{provider.ProviderKraken, provider.ProviderCoinbase},
+		})
+		cfg.AnchorPairs = anchorPairs
+		return cfg
+	}
+	validAnchorPair := anchorConfig(config.AnchorPair{Base: "USDT", MinProviders: 2, Deviation: "0.5", Band: "0.05"})
+	unconfiguredAnchorPair := anchorConfig(config.AnchorPair{Base: "USDC", MinProviders: 1})
+	duplicateAnchorPair := anchorConfig(
+		config.AnchorPair{Base: "USDT", MinProviders: 1},
+		config.AnchorPair{Base: "USDT", MinProviders: 2},
+	)
+	unreachableAnchorPair := anchorConfig(config.AnchorPair{Base: "USDT", MinProviders: 3})
+	missingAnchorMinProviders := anchorConfig(config.AnchorPair{Base: "USDT"})
+	invalidAnchorBand := anchorConfig(config.AnchorPair{Base: "USDT", MinProviders: 2, Band: "1.5"})
+	invalidAnchorDeviation := anchorConfig(config.AnchorPair{Base: "USDT", MinProviders: 2, Deviation: "4"})
+
+	routeConfig := func(conversionRoutes ...config.ConversionRoute) config.Config {
+		cfg := validConfig()
+		cfg.CurrencyPairs = append(cfg.CurrencyPairs, config.CurrencyPair{
+			Base:      "ATOM",
+			Quote:     "BTC",
+			Providers: []types.ProviderName{provider.ProviderBinance},
+		})
+		cfg.ConversionRoutes = conversionRoutes
+		return cfg
+	}
+	validConversionRoute := routeConfig(config.ConversionRoute{Base: "ATOM", Quotes: []string{"USDT", "BTC"}})
+	emptyConversionRoute := routeConfig(config.ConversionRoute{Base: "ATOM"})
+	duplicateConversionRoute := routeConfig(
+		config.ConversionRoute{Base: "ATOM", Quotes: []string{"USDT"}},
+		config.ConversionRoute{Base: "ATOM", Quotes: []string{"BTC"}},
+	)
+	duplicateConversionRouteQuote := routeConfig(config.ConversionRoute{Base: "ATOM", Quotes: []string{"BTC", "BTC"}})
+	unconfiguredConversionRoute := routeConfig(config.ConversionRoute{Base: "ATOM", Quotes: []string{"USDT", "ETH"}})
+
+	unconfiguredReferenceQuote := validConfig()
+	unconfiguredReferenceQuote.ReferencePrices = []config.ReferencePrice{
+		{Base: "ATOM", Quote: "USDT", Provider: provider.ProviderKraken, Threshold: "2"},
+	}
+
+	unsupportedReferenceProvider := validConfig()
+	unsupportedReferenceProvider.ReferencePrices = []config.ReferencePrice{
+		{Base: "ATOM", Quote: "USD", Provider: "foo", Threshold: "2"},
+	}
+
+	unconfiguredReferenceBase := validConfig()
+	unconfiguredReferenceBase.ReferencePrices = []config.ReferencePrice{
+		{Base: "BTC", Quote: "USD", Provider: provider.ProviderKraken, Threshold: "2"},
+	}
+
+	invalidReferenceThreshold := validConfig()
+	invalidReferenceThreshold.ReferencePrices = []config.ReferencePrice{
+		{Base: "ATOM", Quote: "USD", Provider: provider.ProviderKraken, Threshold: "0"},
+	}
+
+	validZeroVolumeWeight := validConfig()
+	validZeroVolumeWeight.ZeroVolumeWeight = "0.001"
+
+	negativeZeroVolumeWeight := validConfig()
+	negativeZeroVolumeWeight.ZeroVolumeWeight = "-1"
+
+	nonNumericZeroVolumeWeight := validConfig()
+	nonNumericZeroVolumeWeight.ZeroVolumeWeight = "low"
+
+	validInstanceLabels := validConfig()
+	validInstanceLabels.InstanceID = "feeder-01"
+	validInstanceLabels.Environment = "mainnet"
+
+	unsafeInstanceID := validConfig()
+	unsafeInstanceID.InstanceID = "feeder 01"
+
+	conflictingEnvironment := validConfig()
+	conflictingEnvironment.Environment = "mainnet"
+	conflictingEnvironment.Telemetry.GlobalLabels = [][]string{{"environment", "testnet"}}
+
+	validVoteMemo := validConfig()
+	validVoteMemo.InstanceID = "feeder-01"
+	validVoteMemo.VoteMemo = "price-feeder {{.InstanceID}} at {{.Height}}"
+
+	invalidVoteMemo := validConfig()
+	invalidVoteMemo.VoteMemo = "price-feeder {{.Height"
+
+	longVoteMemo := validConfig()
+	longVoteMemo.VoteMemo = strings.Repeat("a", 300)
+
+	validVoteWarmup := validConfig()
+	validVoteWarmup.VoteWarmup = config.VoteWarmup{Duration: "2m", MinProviders: 1}
+
+	invalidVoteWarmup := validConfig()
+	invalidVoteWarmup.VoteWarmup.Duration = "120"
+
+	negativeVoteWarmup := validConfig()
+	negativeVoteWarmup.VoteWarmup.Duration = "-2m"
+
+	unreachableVoteWarmup := validConfig()
+	unreachableVoteWarmup.VoteWarmup.MinProviders = 2
+
+	validLog := validConfig()
+	validLog.Log = config.Log{File: "/var/log/price-feeder.log", MaxSize: 100, MaxAge: "168h", MaxBackups: 5}
+
+	invalidLogMaxAge := validConfig()
+	invalidLogMaxAge.Log = config.Log{File: "/var/log/price-feeder.log", MaxSize: 100, MaxAge: "7d"}
+
+	negativeLogMaxBackups := validConfig()
+	negativeLogMaxBackups.Log = config.Log{File: "/var/log/price-feeder.log", MaxSize: 100, MaxBackups: -1}
+
+	zeroLogMaxSize := validConfig()
+	zeroLogMaxSize.Log = config.Log{File: "/var/log/price-feeder.log"}
+
+	chainlinkConfig := func(feeds ...config.ChainlinkFeed) config.Config {
+		cfg := validConfig()
+		cfg.CurrencyPairs = []config.CurrencyPair{
+			{Base: "ETH", Quote: "USD", Providers: []types.ProviderName{provider.ProviderChainlink}},
+		}
+		cfg.Chainlink = config.Chainlink{
+			RPC:         "https://ethereum-rpc.publicnode.com",
+			MaxRoundAge: "1h",
+			Feeds:       feeds,
+		}
+		return cfg
+	}
+	ethUSDFeed := config.ChainlinkFeed{Base: "ETH", Quote: "USD", Address: "0x5f4eC3Df9cbd43714FE2740f5E3616155c5b8419"}
+
+	validChainlink := chainlinkConfig(ethUSDFeed)
+
+	missingChainlinkRPC := chainlinkConfig(ethUSDFeed)
+	missingChainlinkRPC.Chainlink.RPC = ""
+
+	missingChainlinkFeed := chainlinkConfig()
+
+	invalidChainlinkAddress := chainlinkConfig(config.ChainlinkFeed{Base: "ETH", Quote: "USD", Address: "0x5f4eC3Df"})
+
+	invalidChainlinkRoundAge := chainlinkConfig(config.ChainlinkFeed{
+		Base:        "ETH",
+		Quote:       "USD",
+		Address:     ethUSDFeed.Address,
+		MaxRoundAge: "-1h",
+	})
+
+	duplicateChainlinkFeed := chainlinkConfig(ethUSDFeed, ethUSDFeed)
+
+	osmosisTwapConfig := func(pools ...config.OsmosisTwapPool) config.Config {
+		cfg := validConfig()
+		cfg.CurrencyPairs = []config.CurrencyPair{
+			{Base: "ATOM", Quote: "OSMO", Providers: []types.ProviderName{provider.ProviderOsmosisTwap}},
+		}
+		cfg.OsmosisTwap = config.OsmosisTwap{
+			LCD:    "https://lcd.osmosis.zone",
+			Window: "30m",
+			Pools:  pools,
+		}
+		return cfg
+	}
+	atomOSMOPool := config.OsmosisTwapPool{
+		Base:       "ATOM",
+		Quote:      "OSMO",
+		PoolID:     1,
+		BaseDenom:  "ibc/27394FB092D2ECCD56123C74F36E4C1F926001CEADA9CA97EA622B25F41E5EB2",
+		QuoteDenom: "uosmo",
+	}
+
+	validOsmosisTwap := osmosisTwapConfig(atomOSMOPool)
+
+	missingOsmosisTwapLCD := osmosisTwapConfig(atomOSMOPool)
+	missingOsmosisTwapLCD.OsmosisTwap.LCD = ""
+
+	missingOsmosisTwapPool := osmosisTwapConfig()
+
+	missingOsmosisTwapDenom := osmosisTwapConfig(config.OsmosisTwapPool{Base: "ATOM", Quote: "OSMO", PoolID: 1})
+
+	invalidOsmosisTwapDecimals := osmosisTwapConfig(atomOSMOPool)
+	invalidOsmosisTwapDecimals.OsmosisTwap.Pools[0].BaseDecimals = 19
+
+	longOsmosisTwapWindow := osmosisTwapConfig(atomOSMOPool)
+	longOsmosisTwapWindow.OsmosisTwap.Pools[0].Window = "72h"
+
+	duplicateOsmosisTwapPool := osmosisTwapConfig(atomOSMOPool, atomOSMOPool)
+
+	uniswapV3Config := func(pools ...config.UniswapV3Pool) config.Config {
+		cfg := validConfig()
+		cfg.CurrencyPairs = []config.CurrencyPair{
+			{Base: "WETH", Quote: "USDC", Providers: []types.ProviderName{provider.ProviderUniswapV3}},
+		}
+		cfg.UniswapV3 = config.UniswapV3{
+			RPC:        "https://ethereum-rpc.publicnode.com",
+			TwapWindow: "30m",
+			Pools:      pools,
+		}
+		return cfg
+	}
+	wethUSDCPool := config.UniswapV3Pool{
+		Base:      "WETH",
+		Quote:     "USDC",
+		Address:   "0x88e6A0c2dDD26FEEb64F039a2c41296FcB3f5640",
+		BaseToken: "0xC02aaA39b223FE8D0A0e5C4F27eAD9083C756Cc2",
+		FeeTier:   500,
+	}
+
+	validUniswapV3 := uniswapV3Config(wethUSDCPool)
+
+	missingUniswapV3RPC := uniswapV3Config(wethUSDCPool)
+	missingUniswapV3RPC.UniswapV3.RPC = ""
+
+	missingUniswapV3Pool := uniswapV3Config()
+
+	invalidUniswapV3Address := uniswapV3Config(wethUSDCPool)
+	invalidUniswapV3Address.UniswapV3.Pools[0].BaseToken = "weth"
+
+	invalidUniswapV3FeeTier := uniswapV3Config(wethUSDCPool)
+	invalidUniswapV3FeeTier.UniswapV3.Pools[0].FeeTier = 1000000
+
+	invalidUniswapV3TwapWindow := uniswapV3Config(wethUSDCPool)
+	invalidUniswapV3TwapWindow.UniswapV3.Pools[0].TwapWindow = "1500ms"
+
+	duplicateUniswapV3Pool := uniswapV3Config(wethUSDCPool, wethUSDCPool)
+
+	curveConfig := func(pools ...config.CurvePool) config.Config {
+		cfg := validConfig()
+		cfg.CurrencyPairs = []config.CurrencyPair{
+			{Base: "USDC", Quote: "USDT", Providers: []types.ProviderName{provider.ProviderCurve}},
+		}
+		cfg.Curve = config.Curve{
+			RPC:   "https://ethereum-rpc.publicnode.com",
+			Pools: pools,
+		}
+		return cfg
+	}
+	usdcUSDTPool := config.CurvePool{
+		Base:       "USDC",
+		Quote:      "USDT",
+		Address:    "0xbEbc44782C7dB0a1A60Cb6fe97d0b483032FF1C7",
+		BaseIndex:  1,
+		QuoteIndex: 2,
+	}
+
+	validCurve := curveConfig(usdcUSDTPool)
+
+	missingCurveRPC := curveConfig(usdcUSDTPool)
+	missingCurveRPC.Curve.RPC = ""
+
+	missingCurvePool := curveConfig()
+
+	invalidCurveAddress := curveConfig(usdcUSDTPool)
+	invalidCurveAddress.Curve.Pools[0].Address = "3pool"
+
+	invalidCurveIndex := curveConfig(usdcUSDTPool)
+	invalidCurveIndex.Curve.Pools[0].QuoteIndex = 8
+
+	sameCurveIndices := curveConfig(usdcUSDTPool)
+	sameCurveIndices.Curve.Pools[0].QuoteIndex = 1
+
+	duplicateCurvePool := curveConfig(usdcUSDTPool, usdcUSDTPool)
+
+	dydxConfig := func(markets ...config.DydxMarket) config.Config {
+		cfg := validConfig()
+		cfg.CurrencyPairs = []config.CurrencyPair{
+			{Base: "BTC", Quote: "USD", Providers: []types.ProviderName{provider.ProviderDydx}},
+		}
+		cfg.Dydx = config.Dydx{
+			PollInterval: "5s",
+			Markets:      markets,
+		}
+		return cfg
+	}
+	btcUSDMarket := config.DydxMarket{Base: "BTC", Quote: "USD", Market: "BTC-USD"}
+
+	validDydx := dydxConfig(btcUSDMarket)
+
+	missingDydxMarket := dydxConfig()
+
+	invalidDydxMarket := dydxConfig(btcUSDMarket)
+	invalidDydxMarket.Dydx.Markets[0].Market = "BTCUSD"
+
+	invalidDydxPollInterval := dydxConfig(btcUSDMarket)
+	invalidDydxPollInterval.Dydx.PollInterval = "-5s"
+
+	duplicateDydxMarket := dydxConfig(btcUSDMarket, btcUSDMarket)
+
+	redemptionRateConfig := func(assets ...config.RedemptionRateAsset) config.Config {
+		cfg := validConfig()
+		cfg.CurrencyPairs = append(cfg.CurrencyPairs, config.CurrencyPair{
+			Base:      "STATOM",
+			Quote:     "ATOM",
+			Providers: []types.ProviderName{provider.ProviderRedemptionRate},
+		})
+		cfg.RedemptionRate = config.RedemptionRate{
+			PollInterval: "1m",
+			MaxAge:       "10m",
+			Assets:       assets,
+		}
+		return cfg
+	}
+	statomRedemptionRate := config.RedemptionRateAsset{
+		Base:       "STATOM",
+		Underlying: "ATOM",
+		Rest:       "https://stride-api.polkachu.com",
+		Path:       "/Stride-Labs/stride/stakeibc/host_zone/cosmoshub-4",
+		Field:      "host_zone.redemption_rate",
+	}
+
+	validRedemptionRate := redemptionRateConfig(statomRedemptionRate)
+
+	missingRedemptionRate := redemptionRateConfig()
+
+	duplicateRedemptionRate := redemptionRateConfig(statomRedemptionRate, statomRedemptionRate)
+
+	unpricedRedemptionRateUnderlying := redemptionRateConfig(statomRedemptionRate)
+	unpricedRedemptionRateUnderlying.CurrencyPairs[0].Base = "OSMO"
+
+	mismatchedRedemptionRateUnderlying := redemptionRateConfig(statomRedemptionRate)
+	mismatchedRedemptionRateUnderlying.CurrencyPairs[1].Quote = "OSMO"
+
+	routedRedemptionRate := redemptionRateConfig(statomRedemptionRate)
+	routedRedemptionRate.CurrencyPairs = append(routedRedemptionRate.CurrencyPairs, config.CurrencyPair{
+		Base:      "STATOM",
+		Quote:     "USDT",
+		Providers: []types.ProviderName{provider.ProviderOsmosis},
+	})
+	routedRedemptionRate.ConversionRoutes = []config.ConversionRoute{{Base: "STATOM", Quotes: []string{"USDT"}}}
+
+	shortRedemptionRateMaxAge := redemptionRateConfig(statomRedemptionRate)
+	shortRedemptionRateMaxAge.RedemptionRate.MaxAge = "30s"
+
+	invalidRedemptionRatePollInterval := redemptionRateConfig(statomRedemptionRate)
+	invalidRedemptionRatePollInterval.RedemptionRate.PollInterval = "-1m"
+
+	orphanedDeviation := validConfig()
+	orphanedDeviation.Deviations = []config.Deviation{
+		{Base: "ATOM", Threshold: "1.5"},
+		{Base: "OSMO", Threshold: "2"},
+	}
+
+	strictOrphanedDeviation := orphanedDeviation
+	strictOrphanedDeviation.StrictDeviations = true
+
+	strictDeviation := validConfig()
+	strictDeviation.Deviations = []config.Deviation{{Base: "ATOM", Threshold: "1.5"}}
+	strictDeviation.StrictDeviations = true
+
+	validVoteBuilder := validConfig()
+	validVoteBuilder.VoteBuilder = "umee"
+
+	unsupportedVoteBuilder := validConfig()
+	unsupportedVoteBuilder.VoteBuilder = "terra"
+
+	validAuthz := validConfig()
+	validAuthz.Authz = config.Authz{Enabled: true, Granter: "umee1vaexzmn5v4e97ctyv3ex2umnta047h6lhad7q7"}
+
+	missingAuthzGranter := validConfig()
+	missingAuthzGranter.Authz = config.Authz{Enabled: true}
+
+	invalidAuthzGranter := validConfig()
+	invalidAuthzGranter.Authz = config.Authz{Enabled: true, Granter: "granter"}
+
+	disabledAuthzGranter := validConfig()
+	disabledAuthzGranter.Authz = config.Authz{Granter: "umee1vaexzmn5v4e97ctyv3ex2umnta047h6lhad7q7"}
+
+	validVotePriority := validConfig()
+	validVotePriority.MaxVoteSize = 2048
+	validVotePriority.CurrencyPairs[0].VotePriority = 9
+
+	outOfRangeVotePriority := validConfig()
+	outOfRangeVotePriority.CurrencyPairs[0].VotePriority = 10
+
+	conflictingVotePriorities := validConfig()
+	conflictingVotePriorities.CurrencyPairs = []config.CurrencyPair{
+		{Base: "ATOM", Quote: "USDT", Providers: []types.ProviderName{provider.ProviderKraken}, VotePriority: 9},
+		{Base: "ATOM", Quote: "USD", Providers: []types.ProviderName{provider.ProviderKraken}, VotePriority: 1},
+	}
+
+	negativeMaxVoteSize := validConfig()
+	negativeMaxVoteSize.MaxVoteSize = -1
+
+	validMaxBufferedCandles := validConfig()
+	validMaxBufferedCandles.MaxBufferedCandles = 2048
+
+	negativeMaxBufferedCandles := validConfig()
+	negativeMaxBufferedCandles.MaxBufferedCandles = -1
+
+	validVoteExponent := validConfig()
+	validVoteExponent.CurrencyPairs[0].VoteExponent = 6
+
+	negativeVoteExponent := validConfig()
+	negativeVoteExponent.CurrencyPairs[0].VoteExponent = -6
+
+	outOfRangeVoteExponent := validConfig()
+	outOfRangeVoteExponent.CurrencyPairs[0].VoteExponent = config.MaxVoteExponent + 1
+
+	conflictingVoteExponents := validConfig()
+	conflictingVoteExponents.CurrencyPairs = []config.CurrencyPair{
+		{Base: "ATOM", Quote: "USDT", Providers: []types.ProviderName{provider.ProviderKraken}, VoteExponent: 6},
+		{Base: "ATOM", Quote: "USD", Providers: []types.ProviderName{provider.ProviderKraken}},
+	}
+
+	validServerTLS := validConfig()
+	validServerTLS.Server.TLSCertFile = "server.crt"
+	validServerTLS.Server.TLSKeyFile = "server.key"
+	validServerTLS.Server.TLSClientCAFile = "client-ca.crt"
+
+	serverTLSWithoutKey := validConfig()
+	serverTLSWithoutKey.Server.TLSCertFile = "server.crt"
+
+	serverTLSClientCAWithoutCert := validConfig()
+	serverTLSClientCAWithoutCert.Server.TLSClientCAFile = "client-ca.crt"
+
+	serverSafeModeConfig := func(safeMode bool, maxAge string) config.Config {
+		cfg := validConfig()
+		cfg.Server.SafeMode = safeMode
+		cfg.Server.SafeModeMaxAge = maxAge
+		return cfg
+	}
+
+	serverPairControlConfig := func(listenAddr string) config.Config {
+		cfg := validConfig()
+		cfg.Server.ListenAddr = listenAddr
+		cfg.Server.PairControl = true
+		return cfg
+	}
+
+	profilingConfig := func(listenAddr string, allowRemote bool) config.Config {
+		cfg := validConfig()
+		cfg.Server.ListenAddr = "0.0.0.0:7171"
+		cfg.Profiling.Enabled = true
+		cfg.Profiling.ListenAddr = listenAddr
+		cfg.Profiling.AllowRemote = allowRemote
+		return cfg
+	}
+
+	pairControlBehindMTLS := serverPairControlConfig("0.0.0.0:7171")
+	pairControlBehindMTLS.Server.TLSCertFile = "server.crt"
+	pairControlBehindMTLS.Server.TLSKeyFile = "server.key"
+	pairControlBehindMTLS.Server.TLSClientCAFile = "client-ca.crt"
+
+	validVoteRounding := validConfig()
+	validVoteRounding.CurrencyPairs[0].VoteRounding = string(types.RoundDown)
+	validVoteRounding.CurrencyPairs[0].VoteDecimals = 6
+
+	invalidVoteRounding := validConfig()
+	invalidVoteRounding.CurrencyPairs[0].VoteRounding = "nearest"
+
+	voteDecimalsWithoutRounding := validConfig()
+	voteDecimalsWithoutRounding.CurrencyPairs[0].VoteDecimals = 6
+
+	outOfRangeVoteDecimals := validConfig()
+	outOfRangeVoteDecimals.CurrencyPairs[0].VoteRounding = string(types.RoundHalfUp)
+	outOfRangeVoteDecimals.CurrencyPairs[0].VoteDecimals = sdk.Precision + 1
+
+	conflictingVoteRoundings := validConfig()
+	conflictingVoteRoundings.CurrencyPairs = []config.CurrencyPair{
+		{
+			Base:         "ATOM",
+			Quote:        "USDT",
+			Providers:    []types.ProviderName{provider.ProviderKraken},
+			VoteRounding: string(types.RoundDown),
+			VoteDecimals: 6,
+		},
+		{
+			Base:         "ATOM",
+			Quote:        "USD",
+			Providers:    []types.ProviderName{provider.ProviderKraken},
+			VoteRounding: string(types.RoundDown),
+			VoteDecimals: 4,
+		},
+	}
+
+	validSpotOnly := validConfig()
+	validSpotOnly.CurrencyPairs = []config.CurrencyPair{
+		{Base: "ATOM", Quote: "USDT", Providers: []types.ProviderName{provider.ProviderKraken}, SpotOnly: true},
+		{Base: "ATOM", Quote: "USD", Providers: []types.ProviderName{provider.ProviderKraken}, SpotOnly: true},
+	}
+
+	conflictingSpotOnly := validConfig()
+	conflictingSpotOnly.CurrencyPairs = []config.CurrencyPair{
+		{Base: "ATOM", Quote: "USDT", Providers: []types.ProviderName{provider.ProviderKraken}, SpotOnly: true},
+		{Base: "ATOM", Quote: "USD", Providers: []types.ProviderName{provider.ProviderKraken}},
+	}
+
+	validMedianTieBreak := validConfig()
+	validMedianTieBreak.CurrencyPairs = []config.CurrencyPair{
+		{
+			Base:           "ATOM",
+			Quote:          "USDT",
+			Providers:      []types.ProviderName{provider.ProviderKraken, provider.ProviderBinance},
+			MedianTieBreak: provider.ProviderBinance,
+		},
+		{Base: "ATOM", Quote: "USD", Providers: []types.ProviderName{provider.ProviderKraken}},
+	}
+
+	unknownMedianTieBreak := validConfig()
+	unknownMedianTieBreak.CurrencyPairs[0].MedianTieBreak = provider.ProviderBinance
+
+	conflictingMedianTieBreaks := validConfig()
+	conflictingMedianTieBreaks.CurrencyPairs = []config.CurrencyPair{
+		{
+			Base:           "ATOM",
+			Quote:          "USDT",
+			Providers:      []types.ProviderName{provider.ProviderKraken, provider.ProviderBinance},
+			MedianTieBreak: provider.ProviderBinance,
+		},
+		{
+			Base:           "ATOM",
+			Quote:          "USD",
+			Providers:      []types.ProviderName{provider.ProviderKraken},
+			MedianTieBreak: provider.ProviderKraken,
 		},
 	}
 
-	for _, tc := range testCases {
-		t.Run(tc.name, func(t *testing.T) {
-			require.Equal(t, tc.cfg.Validate() != nil, tc.expectErr)
-		})
-	}
+	validMaxVoteChange := validConfig()
+	validMaxVoteChange.CurrencyPairs[0].MaxVoteChange = "0.05"
+
+	invalidMaxVoteChange := validConfig()
+	invalidMaxVoteChange.CurrencyPairs[0].MaxVoteChange = "5%"
+
+	outOfRangeMaxVoteChange := validConfig()
+	outOfRangeMaxVoteChange.CurrencyPairs[0].MaxVoteChange = "1.5"
+
+	zeroMaxVoteChange := validConfig()
+	zeroMaxVoteChange.CurrencyPairs[0].MaxVoteChange = "0"
+
+	conflictingMaxVoteChanges := validConfig()
+	conflictingMaxVoteChanges.CurrencyPairs = []config.CurrencyPair{
+		{Base: "ATOM", Quote: "USDT", Providers: []types.ProviderName{provider.ProviderKraken}, MaxVoteChange: "0.05"},
+		{Base: "ATOM", Quote: "USD", Providers: []types.ProviderName{provider.ProviderKraken}},
+	}
+
+	maxForexAgeConfig := func(quote, maxForexAge string) config.Config {
+		cfg := validConfig()
+		cfg.CurrencyPairs = []config.CurrencyPair{
+			{Base: "ATOM", Quote: quote, Providers: []types.ProviderName{provider.ProviderKraken}, MaxForexAge: maxForexAge},
+			{Base: "EUR", Quote: "USD", Providers: []types.ProviderName{provider.ProviderKraken, provider.ProviderMock}},
+			{Base: "USDT", Quote: "USD", Providers: []types.ProviderName{provider.ProviderKraken}},
+		}
+		return cfg
+	}
+	validMaxForexAge := maxForexAgeConfig("EUR", "2m")
+	invalidMaxForexAge := maxForexAgeConfig("EUR", "2")
+	zeroMaxForexAge := maxForexAgeConfig("EUR", "0s")
+	nonForexMaxForexAge := maxForexAgeConfig("USDT", "2m")
+
+	validMaxClockSkew := validConfig()
+	validMaxClockSkew.MaxClockSkew = "10s"
+	validMaxClockSkew.EnforceMaxClockSkew = true
+
+	invalidMaxClockSkew := validConfig()
+	invalidMaxClockSkew.MaxClockSkew = "10"
+
+	negativeMaxClockSkew := validConfig()
+	negativeMaxClockSkew.MaxClockSkew = "-10s"
+
+	validVoteSafetyMargin := validConfig()
+	validVoteSafetyMargin.VoteSafetyMargin = "1500ms"
+
+	invalidVoteSafetyMargin := validConfig()
+	invalidVoteSafetyMargin.VoteSafetyMargin = "2"
+
+	negativeVoteSafetyMargin := validConfig()
+	negativeVoteSafetyMargin.VoteSafetyMargin = "-2s"
+
+	disabledProviderHealthInterval := validConfig()
+	disabledProviderHealthInterval.ProviderHealthInterval = "0s"
+
+	invalidProviderHealthInterval := validConfig()
+	invalidProviderHealthInterval.ProviderHealthInterval = "5"
+
+	negativeProviderHealthInterval := validConfig()
+	negativeProviderHealthInterval.ProviderHealthInterval = "-5m"
+
+	validSubscriptionReconcile := validConfig()
+	validSubscriptionReconcile.SubscriptionReconcile = config.Reconciliation{Interval: "1m", MaxAge: "5m"}
+
+	disabledSubscriptionReconcile := validConfig()
+	disabledSubscriptionReconcile.SubscriptionReconcile = config.Reconciliation{Interval: "0s"}
+
+	invalidSubscriptionReconcileInterval := validConfig()
+	invalidSubscriptionReconcileInterval.SubscriptionReconcile = config.Reconciliation{Interval: "1", MaxAge: "5m"}
+
+	negativeSubscriptionReconcileInterval := validConfig()
+	negativeSubscriptionReconcileInterval.SubscriptionReconcile = config.Reconciliation{Interval: "-1m", MaxAge: "5m"}
+
+	zeroSubscriptionReconcileMaxAge := validConfig()
+	zeroSubscriptionReconcileMaxAge.SubscriptionReconcile = config.Reconciliation{Interval: "1m", MaxAge: "0s"}
+
+	disabledReconnectCooldown := validConfig()
+	disabledReconnectCooldown.ReconnectCooldown = "0s"
+
+	invalidReconnectCooldown := validConfig()
+	invalidReconnectCooldown.ReconnectCooldown = "5"
+
+	negativeReconnectCooldown := validConfig()
+	negativeReconnectCooldown.ReconnectCooldown = "-5s"
+
+	validLatencyThresholds := validConfig()
+	validLatencyThresholds.LatencyThresholds = []config.LatencyThreshold{
+		{Latency: "2m", Weight: "0"},
+		{Latency: "30s", Weight: "0.5"},
+	}
+
+	invalidLatencyThreshold := validConfig()
+	invalidLatencyThreshold.LatencyThresholds = []config.LatencyThreshold{{Latency: "30", Weight: "0.5"}}
+
+	fullLatencyThresholdWeight := validConfig()
+	fullLatencyThresholdWeight.LatencyThresholds = []config.LatencyThreshold{{Latency: "30s", Weight: "1"}}
+
+	increasingLatencyThresholdWeight := validConfig()
+	increasingLatencyThresholdWeight.LatencyThresholds = []config.LatencyThreshold{
+		{Latency: "30s", Weight: "0.2"},
+		{Latency: "2m", Weight: "0.5"},
+	}
+
+	duplicateLatencyThreshold := validConfig()
+	duplicateLatencyThreshold.LatencyThresholds = []config.LatencyThreshold{
+		{Latency: "30s", Weight: "0.5"},
+		{Latency: "30s", Weight: "0.5"},
+	}
+
+	validTickerWindow := validConfig()
+	validTickerWindow.TickerWindows = []config.TickerWindow{
+		{Provider: provider.ProviderKraken, Base: "ATOM", Quote: "USDT", Samples: 5},
+	}
+
+	unprovidedTickerWindow := validConfig()
+	unprovidedTickerWindow.TickerWindows = []config.TickerWindow{
+		{Provider: provider.ProviderBinance, Base: "ATOM", Quote: "USDT", Samples: 5},
+	}
+
+	singleSampleTickerWindow := validConfig()
+	singleSampleTickerWindow.TickerWindows = []config.TickerWindow{
+		{Provider: provider.ProviderKraken, Base: "ATOM", Quote: "USDT", Samples: 1},
+	}
+
+	duplicateTickerWindow := validConfig()
+	duplicateTickerWindow.TickerWindows = []config.TickerWindow{
+		{Provider: provider.ProviderKraken, Base: "ATOM", Quote: "USDT", Samples: 5},
+		{Provider: provider.ProviderKraken, Base: "ATOM", Quote: "USDT", Samples: 3},
+	}
+
+	waitStartupPolicy := validConfig()
+	waitStartupPolicy.StartupPolicy = config.StartupPolicyWait
+	waitStartupPolicy.StartupTimeout = "5m"
+
+	invalidStartupPolicy := validConfig()
+	invalidStartupPolicy.StartupPolicy = "retry"
+
+	invalidStartupTimeout := validConfig()
+	invalidStartupTimeout.StartupTimeout = "5"
+
+	zeroStartupTimeout := validConfig()
+	zeroStartupTimeout.StartupTimeout = "0s"
+
+	providersFirstStartupOrder := validConfig()
+	providersFirstStartupOrder.StartupOrder = config.StartupOrderProvidersFirst
+
+	invalidStartupOrder := validConfig()
+	invalidStartupOrder.StartupOrder = "keyring-first"
+
+	dynamicPairsConfig := func(dynamicPairs config.DynamicPairs) config.Config {
+		cfg := validConfig()
+		cfg.CurrencyPairs = append(cfg.CurrencyPairs, config.CurrencyPair{
+			Base: "USDT", Quote: "USD", Providers: []types.ProviderName{provider.ProviderKraken},
+		})
+		cfg.DynamicPairs = dynamicPairs
+		return cfg
+	}
+	validDynamicPairs := dynamicPairsConfig(config.DynamicPairs{
+		Enabled:         true,
+		Providers:       []types.ProviderName{provider.ProviderBinance, provider.ProviderKraken},
+		Quote:           "usdt",
+		Quotes:          map[string]string{"statom": "usd"},
+		RefreshInterval: "5m",
+	})
+	dynamicPairsWithoutProviders := dynamicPairsConfig(config.DynamicPairs{Enabled: true, Quote: "USDT"})
+	unsupportedDynamicPairsProvider := dynamicPairsConfig(config.DynamicPairs{
+		Enabled: true, Providers: []types.ProviderName{"foo"}, Quote: "USDT",
+	})
+	dynamicPairsWithoutQuote := dynamicPairsConfig(config.DynamicPairs{
+		Enabled: true, Providers: []types.ProviderName{provider.ProviderKraken},
+	})
+	dynamicPairsQuoteWithoutUSDPair := dynamicPairsConfig(config.DynamicPairs{
+		Enabled: true, Providers: []types.ProviderName{provider.ProviderKraken}, Quote: "USDT",
+		Quotes: map[string]string{"STATOM": "ATOM"},
+	})
+	invalidDynamicPairsRefresh := dynamicPairsConfig(config.DynamicPairs{
+		Enabled: true, Providers: []types.ProviderName{provider.ProviderKraken}, Quote: "USDT",
+		RefreshInterval: "0s",
+	})
+
+	validAttestation := validConfig()
+	validAttestation.Attestation = config.Attestation{URL: "https://collector.example.com", Timeout: "10s", MaxRetries: 3}
+
+	invalidAttestationURL := validConfig()
+	invalidAttestationURL.Attestation = config.Attestation{URL: "collector.example.com", Timeout: "10s"}
+
+	invalidAttestationTimeout := validConfig()
+	invalidAttestationTimeout.Attestation = config.Attestation{URL: "https://collector.example.com", Timeout: "0s"}
+
+	validStatsd := validConfig()
+	validStatsd.Statsd = config.Statsd{Address: "localhost:8125", Prefix: "price_feeder", FlushInterval: "10s"}
+
+	invalidStatsdAddress := validConfig()
+	invalidStatsdAddress.Statsd = config.Statsd{Address: "localhost", Prefix: "price_feeder", FlushInterval: "10s"}
+
+	invalidStatsdPort := validConfig()
+	invalidStatsdPort.Statsd = config.Statsd{Address: "localhost:statsd", Prefix: "price_feeder", FlushInterval: "10s"}
+
+	invalidStatsdFlushInterval := validConfig()
+	invalidStatsdFlushInterval.Statsd = config.Statsd{Address: "localhost:8125", Prefix: "price_feeder", FlushInterval: "0s"}
+
+	webhookConfig := func(webhook config.Webhook) config.Config {
+		cfg := validConfig()
+		webhook.RateLimit = "10m"
+		if webhook.Timeout == "" {
+			webhook.Timeout = "5s"
+		}
+		cfg.Webhook = webhook
+		return cfg
+	}
+	validWebhook := webhookConfig(config.Webhook{URL: "https://alerts.example.com", PriceChange: "0.05", AlertOmissions: true})
+	invalidWebhookURL := webhookConfig(config.Webhook{URL: "alerts.example.com", AlertOmissions: true})
+	webhookWithoutTrigger := webhookConfig(config.Webhook{URL: "https://alerts.example.com"})
+	invalidWebhookPriceChange := webhookConfig(config.Webhook{URL: "https://alerts.example.com", PriceChange: "-0.05"})
+	invalidWebhookTimeout := webhookConfig(config.Webhook{URL: "https://alerts.example.com", AlertPriceBands: true, Timeout: "0s"})
+
+	negativeAttestationRetries := validConfig()
+	negativeAttestationRetries.Attestation = config.Attestation{
+		URL:        "https://collector.example.com",
+		Timeout:    "10s",
+		MaxRetries: -1,
+	}
+
+	symbolCacheConfig := func(symbolCache config.SymbolCache) config.Config {
+		cfg := validConfig()
+		cfg.SymbolCache = symbolCache
+		return cfg
+	}
+	validSymbolCache := symbolCacheConfig(config.SymbolCache{Path: "/tmp/symbols.json", TTL: "24h", MaxRetries: 2})
+	invalidSymbolCacheTTL := symbolCacheConfig(config.SymbolCache{Path: "/tmp/symbols.json", TTL: "1 day"})
+	zeroSymbolCacheTTL := symbolCacheConfig(config.SymbolCache{Path: "/tmp/symbols.json", TTL: "0s"})
+	negativeSymbolCacheRetries := symbolCacheConfig(config.SymbolCache{MaxRetries: -1})
+
+	candleIntervalsEndpoint := func(name types.ProviderName, intervals ...string) []provider.Endpoint {
+		return []provider.Endpoint{{
+			Name:            name,
+			Rest:            "https://example.com",
+			Websocket:       "example.com",
+			CandleIntervals: intervals,
+		}}
+	}
+
+	validCandleIntervals := validConfig()
+	validCandleIntervals.ProviderEndpoints = candleIntervalsEndpoint(provider.ProviderBinance, "1m", "5m")
+
+	unsupportedCandleInterval := validConfig()
+	unsupportedCandleInterval.ProviderEndpoints = candleIntervalsEndpoint(provider.ProviderBinance, "1m", "2m")
+
+	duplicateCandleInterval := validConfig()
+	duplicateCandleInterval.ProviderEndpoints = candleIntervalsEndpoint(provider.ProviderBinance, "1m", "1m")
+
+	unsupportedCandleIntervalProvider := validConfig()
+	unsupportedCandleIntervalProvider.ProviderEndpoints = candleIntervalsEndpoint(provider.ProviderOkx, "1m", "5m")
+
+	validKrakenCandleIntervals := validConfig()
+	validKrakenCandleIntervals.ProviderEndpoints = candleIntervalsEndpoint(provider.ProviderKraken, "5m", "15m")
+
+	unsupportedKrakenCandleInterval := validConfig()
+	unsupportedKrakenCandleInterval.ProviderEndpoints = candleIntervalsEndpoint(provider.ProviderKraken, "3m")
+
+	missingCACert := validConfig()
+	missingCACert.ProviderEndpoints = []provider.Endpoint{
+		{
+			Name:      provider.ProviderKraken,
+			Rest:      "https://api.kraken.com",
+			Websocket: "ws.kraken.com",
+			CACert:    "/nonexistent/ca.pem",
+		},
+	}
+
+	insecureEndpoint := validConfig()
+	insecureEndpoint.ProviderEndpoints = []provider.Endpoint{
+		{
+			Name:               provider.ProviderKraken,
+			Rest:               "https://api.kraken.com",
+			Websocket:          "ws.kraken.com",
+			InsecureSkipVerify: true,
+		},
+	}
+
+	headersEndpoint := func(headers map[string]string) []provider.Endpoint {
+		return []provider.Endpoint{
+			{
+				Name:      provider.ProviderKraken,
+				Rest:      "https://api.kraken.com",
+				Websocket: "ws.kraken.com",
+				Headers:   headers,
+			},
+		}
+	}
+
+	validHeaders := validConfig()
+	validHeaders.ProviderEndpoints = headersEndpoint(map[string]string{"x-api-version": "2", "authorization": "Bearer abc"})
+
+	invalidHeaderName := validConfig()
+	invalidHeaderName.ProviderEndpoints = headersEndpoint(map[string]string{"x partner": "ojo"})
+
+	reservedHeader := validConfig()
+	reservedHeader.ProviderEndpoints = headersEndpoint(map[string]string{"upgrade": "websocket"})
+
+	batchingEndpoint := func(size int, delay string) []provider.Endpoint {
+		return []provider.Endpoint{
+			{
+				Name:                   provider.ProviderKraken,
+				Rest:                   "https://api.kraken.com",
+				Websocket:              "ws.kraken.com",
+				SubscriptionBatchSize:  size,
+				SubscriptionBatchDelay: delay,
+			},
+		}
+	}
+
+	validSubscriptionBatching := validConfig()
+	validSubscriptionBatching.ProviderEndpoints = batchingEndpoint(10, "250ms")
+
+	invalidSubscriptionBatchDelay := validConfig()
+	invalidSubscriptionBatchDelay.ProviderEndpoints = batchingEndpoint(10, "250")
+
+	subscriptionBatchDelayWithoutSize := validConfig()
+	subscriptionBatchDelayWithoutSize.ProviderEndpoints = batchingEndpoint(0, "250ms")
+
+	pollingSubscriptionBatching := validConfig()
+	pollingSubscriptionBatching.ProviderEndpoints = []provider.Endpoint{
+		{
+			Name:                  provider.ProviderInjective,
+			Rest:                  "https://lcd.injective.network",
+			SubscriptionBatchSize: 10,
+		},
+	}
+
+	compressionEndpoint := func(compression string) []provider.Endpoint {
+		return []provider.Endpoint{
+			{
+				Name:        provider.ProviderKraken,
+				Rest:        "https://api.kraken.com",
+				Websocket:   "ws.kraken.com",
+				Compression: compression,
+			},
+		}
+	}
+
+	validCompression := validConfig()
+	validCompression.ProviderEndpoints = compressionEndpoint(provider.CompressionZstd)
+
+	unsupportedCompression := validConfig()
+	unsupportedCompression.ProviderEndpoints = compressionEndpoint("brotli")
+
+	pollingCompression := validConfig()
+	pollingCompression.ProviderEndpoints = []provider.Endpoint{
+		{
+			Name:        provider.ProviderInjective,
+			Rest:        "https://lcd.injective.network",
+			Compression: provider.CompressionGzip,
+		},
+	}
+
+	candleGapEndpoint := func(gap string) []provider.Endpoint {
+		return []provider.Endpoint{
+			{
+				Name:         provider.ProviderKraken,
+				Rest:         "https://api.kraken.com",
+				Websocket:    "ws.kraken.com",
+				MaxCandleGap: gap,
+			},
+		}
+	}
+
+	validCandleGap := validConfig()
+	validCandleGap.ProviderEndpoints = candleGapEndpoint("2m")
+
+	invalidCandleGap := validConfig()
+	invalidCandleGap.ProviderEndpoints = candleGapEndpoint("2")
+
+	negativeCandleGap := validConfig()
+	negativeCandleGap.ProviderEndpoints = candleGapEndpoint("-1m")
+
+	orderBookDepthEndpoint := func(name types.ProviderName, depth string) []provider.Endpoint {
+		return []provider.Endpoint{
+			{
+				Name:           name,
+				Rest:           "https://api.example.com",
+				Websocket:      "ws.example.com",
+				OrderBookDepth: depth,
+			},
+		}
+	}
+
+	validOrderBookDepth := validConfig()
+	validOrderBookDepth.ProviderEndpoints = orderBookDepthEndpoint(provider.ProviderGate, "0.01")
+
+	outOfRangeOrderBookDepth := validConfig()
+	outOfRangeOrderBookDepth.ProviderEndpoints = orderBookDepthEndpoint(provider.ProviderHuobi, "1")
+
+	unsupportedOrderBookDepth := validConfig()
+	unsupportedOrderBookDepth.ProviderEndpoints = orderBookDepthEndpoint(provider.ProviderKraken, "0.01")
+
+	reorderEndpoint := func(name types.ProviderName, window string) []provider.Endpoint {
+		return []provider.Endpoint{
+			{
+				Name:                name,
+				Rest:                "https://api.example.com",
+				Websocket:           "ws.example.com",
+				TickerReorderWindow: window,
+			},
+		}
+	}
+
+	validTickerReorder := validConfig()
+	validTickerReorder.ProviderEndpoints = reorderEndpoint(provider.ProviderBinance, "500ms")
+
+	invalidTickerReorder := validConfig()
+	invalidTickerReorder.ProviderEndpoints = reorderEndpoint(provider.ProviderOkx, "500")
+
+	unsupportedTickerReorder := validConfig()
+	unsupportedTickerReorder.ProviderEndpoints = reorderEndpoint(provider.ProviderKraken, "500ms")
+
+	combinedStreamEndpoint := func(name types.ProviderName) []provider.Endpoint {
+		return []provider.Endpoint{
+			{
+				Name:           name,
+				Rest:           "https://api.example.com",
+				Websocket:      "ws.example.com",
+				CombinedStream: true,
+			},
+		}
+	}
+
+	validCombinedStream := validConfig()
+	validCombinedStream.ProviderEndpoints = combinedStreamEndpoint(provider.ProviderBinance)
+
+	unsupportedCombinedStream := validConfig()
+	unsupportedCombinedStream.ProviderEndpoints = combinedStreamEndpoint(provider.ProviderOkx)
+
+	validMinBookDepth := validConfig()
+	validMinBookDepth.CurrencyPairs[0].MinBookDepth = "50000"
+
+	invalidMinBookDepth := validConfig()
+	invalidMinBookDepth.CurrencyPairs[0].MinBookDepth = "50k"
+
+	zeroMinBookDepth := validConfig()
+	zeroMinBookDepth.CurrencyPairs[0].MinBookDepth = "0"
+
+	validMaxSpread := validConfig()
+	validMaxSpread.CurrencyPairs[0].MaxSpread = "0.01"
+	validMaxSpread.CurrencyPairs[0].Providers = []types.ProviderName{provider.ProviderKraken, provider.ProviderOkx}
+
+	unsupportedMaxSpread := validConfig()
+	unsupportedMaxSpread.CurrencyPairs[0].MaxSpread = "0.01"
+
+	invalidMaxSpread := validConfig()
+	invalidMaxSpread.CurrencyPairs[0].MaxSpread = "1%"
+
+	zeroMaxSpread := validConfig()
+	zeroMaxSpread.CurrencyPairs[0].MaxSpread = "0"
+
+	tooLargeMaxSpread := validConfig()
+	tooLargeMaxSpread.CurrencyPairs[0].MaxSpread = "1.5"
+
+	validTwapWindow := validConfig()
+	validTwapWindow.CurrencyPairs[0].TwapWindow = "1h"
+
+	invalidTwapWindow := validConfig()
+	invalidTwapWindow.CurrencyPairs[0].TwapWindow = "60"
+
+	longTwapWindow := validConfig()
+	longTwapWindow.CurrencyPairs[0].TwapWindow = "48h"
+
+	conflictingTwapWindows := validConfig()
+	conflictingTwapWindows.CurrencyPairs[0].TwapWindow = "1h"
+	conflictingTwapWindows.CurrencyPairs = append(conflictingTwapWindows.CurrencyPairs, config.CurrencyPair{
+		Base:       "ATOM",
+		Quote:      "USD",
+		Providers:  []types.ProviderName{provider.ProviderKraken},
+		TwapWindow: "5m",
+	})
+
+	injectiveEndpoint := validConfig()
+	injectiveEndpoint.ProviderEndpoints = []provider.Endpoint{
+		{
+			Name: provider.ProviderInjective,
+			Rest: "https://lcd.injective.network",
+		},
+	}
+
+	testCases := []struct {
+		name      string
+		cfg       config.Config
+		expectErr bool
+	}{
+		{
+			"valid config",
+			validConfig(),
+			false,
+		},
+		{
+			"valid allowed quotes",
+			validAllowedQuotes,
+			false,
+		},
+		{
+			"quote outside of the allowed quotes",
+			disallowedQuote,
+			true,
+		},
+		{
+			"unsupported allowed quote",
+			unsupportedAllowedQuote,
+			true,
+		},
+		{
+			"duplicate allowed quote",
+			duplicateAllowedQuote,
+			true,
+		},
+		{
+			"unsupported default provider",
+			invalidDefaultProvider,
+			true,
+		},
+		{
+			"forex quote with forex rate",
+			forexQuote,
+			false,
+		},
+		{
+			"forex quote without forex rate",
+			forexQuoteWithoutRate,
+			true,
+		},
+		{
+			"valid price source",
+			validPriceSource,
+			false,
+		},
+		{
+			"invalid price source",
+			invalidPriceSource,
+			true,
+		},
+		{
+			"unsupported price source provider",
+			unsupportedPriceSourceProvider,
+			true,
+		},
+		{
+			"price source for derivative price type",
+			derivativePriceSource,
+			true,
+		},
+		{
+			"valid provider roles",
+			validProviderRoles,
+			false,
+		},
+		{
+			"role for unconfigured provider",
+			unconfiguredRoleProvider,
+			true,
+		},
+		{
+			"ticker and candle only provider",
+			conflictingProviderRoles,
+			true,
+		},
+		{
+			"valid max providers",
+			validMaxProviders,
+			false,
+		},
+		{
+			"negative max providers",
+			negativeMaxProviders,
+			true,
+		},
+		{
+			"max providers not trimming any provider",
+			untrimmedMaxProviders,
+			true,
+		},
+		{
+			"valid required providers",
+			validRequiredProviders,
+			false,
+		},
+		{
+			"required provider not providing the pair",
+			unconfiguredRequiredProvider,
+			true,
+		},
+		{
+			"valid provider groups",
+			validProviderGroups,
+			false,
+		},
+		{
+			"min provider groups above the groups of the pair",
+			unreachableProviderGroups,
+			true,
+		},
+		{
+			"negative min provider groups",
+			negativeProviderGroups,
+			true,
+		},
+		{
+			"duplicate provider group",
+			duplicateProviderGroup,
+			true,
+		},
+		{
+			"provider in several provider groups",
+			overlappingProviderGroups,
+			true,
+		},
+		{
+			"provider group named after a provider outside of it",
+			misnamedProviderGroup,
+			true,
+		},
+		{
+			"unsupported provider in a provider group",
+			unsupportedProviderGroup,
+			true,
+		},
+		{
+			"valid candle spot providers",
+			spotSourcesConfig(
+				[]types.ProviderName{provider.ProviderKraken},
+				[]types.ProviderName{provider.ProviderBinance},
+			),
+			false,
+		},
+		{
+			"candle spot provider not providing the pair",
+			spotSourcesConfig([]types.ProviderName{provider.ProviderOkx}, nil),
+			true,
+		},
+		{
+			"ticker only candle spot provider",
+			spotSourcesConfig(
+				[]types.ProviderName{provider.ProviderKraken},
+				[]types.ProviderName{provider.ProviderKraken},
+			),
+			true,
+		},
+		{
+			"valid provider agreement",
+			validProviderAgreement,
+			false,
+		},
+		{
+			"provider agreement with the default band",
+			defaultAgreementBand,
+			false,
+		},
+		{
+			"min agreeing providers exceeding the providers",
+			unreachableProviderAgreement,
+			true,
+		},
+		{
+			"single agreeing provider",
+			singleProviderAgreement,
+			true,
+		},
+		{
+			"agreement band without min agreeing providers",
+			bandWithoutProviderAgreement,
+			true,
+		},
+		{
+			"agreement band outside of (0, 1)",
+			invalidAgreementBand,
+			true,
+		},
+		{
+			"valid price band",
+			validPriceBand,
+			false,
+		},
+		{
+			"price band min above max",
+			invalidPriceBandRange,
+			true,
+		},
+		{
+			"non-numeric price band bound",
+			invalidPriceBandBound,
+			true,
+		},
+		{
+			"price band without bounds",
+			emptyPriceBand,
+			true,
+		},
+		{
+			"valid tvwap weighting",
+			validTvwapWeighting,
+			false,
+		},
+		{
+			"invalid tvwap weighting mode",
+			invalidTvwapWeightingMode,
+			true,
+		},
+		{
+			"exponential tvwap weighting without half-life",
+			missingTvwapWeightingHalfLife,
+			true,
+		},
+		{
+			"linear tvwap weighting with half-life",
+			linearTvwapWeightingHalfLife,
+			true,
+		},
+		{
+			"duplicate tvwap weighting",
+			duplicateTvwapWeighting,
+			true,
+		},
+		{
+			"valid equal tvwap combination",
+			tvwapCombinationConfig("equal", nil),
+			false,
+		},
+		{
+			"valid weighted tvwap combination",
+			tvwapCombinationConfig("weighted", map[string]string{"kraken": "2.5"}),
+			false,
+		},
+		{
+			"invalid tvwap combination",
+			tvwapCombinationConfig("median", nil),
+			true,
+		},
+		{
+			"weighted tvwap combination without provider weights",
+			tvwapCombinationConfig("weighted", nil),
+			true,
+		},
+		{
+			"tvwap provider weights without the weighted combination",
+			tvwapCombinationConfig("equal", map[string]string{"kraken": "1"}),
+			true,
+		},
+		{
+			"non-positive tvwap provider weight",
+			tvwapCombinationConfig("weighted", map[string]string{"kraken": "0"}),
+			true,
+		},
+		{
+			"invalid tvwap provider weight",
+			tvwapCombinationConfig("weighted", map[string]string{"kraken": "heavy"}),
+			true,
+		},
+		{
+			"tvwap provider weight of a provider not pricing the asset",
+			tvwapCombinationConfig("weighted", map[string]string{"binance": "1"}),
+			true,
+		},
+		{
+			"valid reference price",
+			validReferencePrice,
+			false,
+		},
+		{
+			"unsupported reference price provider",
+			unsupportedReferenceProvider,
+			true,
+		},
+		{
+			"reference price of an unconfigured asset",
+			unconfiguredReferenceBase,
+			true,
+		},
+		{
+			"reference price quoted in an unconfigured asset",
+			unconfiguredReferenceQuote,
+			true,
+		},
+		{
+			"valid anchor pair",
+			validAnchorPair,
+			false,
+		},
+		{
+			"anchor pair of an unconfigured USD pair",
+			unconfiguredAnchorPair,
+			true,
+		},
+		{
+			"duplicate anchor pair",
+			duplicateAnchorPair,
+			true,
+		},
+		{
+			"anchor pair min providers exceeding its providers",
+			unreachableAnchorPair,
+			true,
+		},
+		{
+			"anchor pair without min providers",
+			missingAnchorMinProviders,
+			true,
+		},
+		{
+			"anchor pair band outside of (0, 1)",
+			invalidAnchorBand,
+			true,
+		},
+		{
+			"anchor pair deviation exceeding 3",
+			invalidAnchorDeviation,
+			true,
+		},
+		{
+			"valid conversion route",
+			validConversionRoute,
+			false,
+		},
+		{
+			"conversion route without quotes",
+			emptyConversionRoute,
+			true,
+		},
+		{
+			"duplicate conversion route",
+			duplicateConversionRoute,
+			true,
+		},
+		{
+			"duplicate conversion route quote",
+			duplicateConversionRouteQuote,
+			true,
+		},
+		{
+			"conversion route through an unconfigured currency pair",
+			unconfiguredConversionRoute,
+			true,
+		},
+		{
+			"non-positive reference price threshold",
+			invalidReferenceThreshold,
+			true,
+		},
+		{
+			"valid zero volume weight",
+			validZeroVolumeWeight,
+			false,
+		},
+		{
+			"negative zero volume weight",
+			negativeZeroVolumeWeight,
+			true,
+		},
+		{
+			"non-numeric zero volume weight",
+			nonNumericZeroVolumeWeight,
+			true,
+		},
+		{
+			"valid instance labels",
+			validInstanceLabels,
+			false,
+		},
+		{
+			"instance id with a space",
+			unsafeInstanceID,
+			true,
+		},
+		{
+			"environment also set as a global label",
+			conflictingEnvironment,
+			true,
+		},
+		{
+			"valid vote memo",
+			validVoteMemo,
+			false,
+		},
+		{
+			"invalid vote memo template",
+			invalidVoteMemo,
+			true,
+		},
+		{
+			"vote memo exceeding the maximum length",
+			longVoteMemo,
+			true,
+		},
+		{
+			"valid chainlink feed",
+			validChainlink,
+			false,
+		},
+		{
+			"chainlink provider without rpc",
+			missingChainlinkRPC,
+			true,
+		},
+		{
+			"chainlink provider without a feed of the pair",
+			missingChainlinkFeed,
+			true,
+		},
+		{
+			"invalid chainlink aggregator address",
+			invalidChainlinkAddress,
+			true,
+		},
+		{
+			"negative chainlink max round age",
+			invalidChainlinkRoundAge,
+			true,
+		},
+		{
+			"duplicate chainlink feed",
+			duplicateChainlinkFeed,
+			true,
+		},
+		{
+			"valid osmosis twap pool",
+			validOsmosisTwap,
+			false,
+		},
+		{
+			"osmosis-twap provider without lcd",
+			missingOsmosisTwapLCD,
+			true,
+		},
+		{
+			"osmosis-twap provider without a pool of the pair",
+			missingOsmosisTwapPool,
+			true,
+		},
+		{
+			"osmosis twap pool without denoms",
+			missingOsmosisTwapDenom,
+			true,
+		},
+		{
+			"osmosis twap decimals above precision",
+			invalidOsmosisTwapDecimals,
+			true,
+		},
+		{
+			"osmosis twap window beyond the twap retention",
+			longOsmosisTwapWindow,
+			true,
+		},
+		{
+			"duplicate osmosis twap pool",
+			duplicateOsmosisTwapPool,
+			true,
+		},
+		{
+			"orphaned deviation threshold",
+			orphanedDeviation,
+			false,
+		},
+		{
+			"strict orphaned deviation threshold",
+			strictOrphanedDeviation,
+			true,
+		},
+		{
+			"strict deviation threshold",
+			strictDeviation,
+			false,
+		},
+		{
+			"valid uniswap v3",
+			validUniswapV3,
+			false,
+		},
+		{
+			"uniswap v3 without rpc",
+			missingUniswapV3RPC,
+			true,
+		},
+		{
+			"uniswap v3 pair without pool",
+			missingUniswapV3Pool,
+			true,
+		},
+		{
+			"invalid uniswap v3 base token address",
+			invalidUniswapV3Address,
+			true,
+		},
+		{
+			"invalid uniswap v3 fee tier",
+			invalidUniswapV3FeeTier,
+			true,
+		},
+		{
+			"uniswap v3 twap window of fractional seconds",
+			invalidUniswapV3TwapWindow,
+			true,
+		},
+		{
+			"duplicate uniswap v3 pool",
+			duplicateUniswapV3Pool,
+			true,
+		},
+		{
+			"valid curve",
+			validCurve,
+			false,
+		},
+		{
+			"curve without rpc",
+			missingCurveRPC,
+			true,
+		},
+		{
+			"curve pair without pool",
+			missingCurvePool,
+			true,
+		},
+		{
+			"invalid curve pool address",
+			invalidCurveAddress,
+			true,
+		},
+		{
+			"curve coin index out of range",
+			invalidCurveIndex,
+			true,
+		},
+		{
+			"curve base and quote of the same coin",
+			sameCurveIndices,
+			true,
+		},
+		{
+			"duplicate curve pool",
+			duplicateCurvePool,
+			true,
+		},
+		{
+			"valid dydx",
+			validDydx,
+			false,
+		},
+		{
+			"dydx pair without a market",
+			missingDydxMarket,
+			true,
+		},
+		{
+			"invalid dydx market",
+			invalidDydxMarket,
+			true,
+		},
+		{
+			"non-positive dydx poll interval",
+			invalidDydxPollInterval,
+			true,
+		},
+		{
+			"duplicate dydx market",
+			duplicateDydxMarket,
+			true,
+		},
+		{
+			"valid redemption rate",
+			validRedemptionRate,
+			false,
+		},
+		{
+			"redemption-rate pair without a redemption rate",
+			missingRedemptionRate,
+			true,
+		},
+		{
+			"duplicate redemption rate",
+			duplicateRedemptionRate,
+			true,
+		},
+		{
+			"redemption rate of an unpriced underlying asset",
+			unpricedRedemptionRateUnderlying,
+			true,
+		},
+		{
+			"redemption-rate pair not quoted in the underlying asset",
+			mismatchedRedemptionRateUnderlying,
+			true,
+		},
+		{
+			"redemption rate with a conversion route",
+			routedRedemptionRate,
+			true,
+		},
+		{
+			"redemption rate max age shorter than the poll interval",
+			shortRedemptionRateMaxAge,
+			true,
+		},
+		{
+			"non-positive redemption rate poll interval",
+			invalidRedemptionRatePollInterval,
+			true,
+		},
+		{
+			"valid log file",
+			validLog,
+			false,
+		},
+		{
+			"log max age without a valid unit",
+			invalidLogMaxAge,
+			true,
+		},
+		{
+			"negative log max backups",
+			negativeLogMaxBackups,
+			true,
+		},
+		{
+			"zero log max size",
+			zeroLogMaxSize,
+			true,
+		},
+		{
+			"valid vote warm-up",
+			validVoteWarmup,
+			false,
+		},
+		{
+			"vote warm-up duration without a unit",
+			invalidVoteWarmup,
+			true,
+		},
+		{
+			"negative vote warm-up duration",
+			negativeVoteWarmup,
+			true,
+		},
+		{
+			"vote warm-up min providers exceeding the providers of an asset",
+			unreachableVoteWarmup,
+			true,
+		},
+		{
+			"valid vote builder",
+			validVoteBuilder,
+			false,
+		},
+		{
+			"unsupported vote builder",
+			unsupportedVoteBuilder,
+			true,
+		},
+		{
+			"valid authz",
+			validAuthz,
+			false,
+		},
+		{
+			"authz without a granter",
+			missingAuthzGranter,
+			true,
+		},
+		{
+			"authz granter not a bech32 address",
+			invalidAuthzGranter,
+			true,
+		},
+		{
+			"authz granter without enabling authz",
+			disabledAuthzGranter,
+			true,
+		},
+		{
+			"valid vote priority",
+			validVotePriority,
+			false,
+		},
+		{
+			"vote priority out of range",
+			outOfRangeVotePriority,
+			true,
+		},
+		{
+			"conflicting vote priorities of a base",
+			conflictingVotePriorities,
+			true,
+		},
+		{
+			"negative max vote size",
+			negativeMaxVoteSize,
+			true,
+		},
+		{
+			"valid max buffered candles",
+			validMaxBufferedCandles,
+			false,
+		},
+		{
+			"negative max buffered candles",
+			negativeMaxBufferedCandles,
+			true,
+		},
+		{
+			"valid vote exponent",
+			validVoteExponent,
+			false,
+		},
+		{
+			"negative vote exponent",
+			negativeVoteExponent,
+			true,
+		},
+		{
+			"vote exponent above the decimal precision",
+			outOfRangeVoteExponent,
+			true,
+		},
+		{
+			"conflicting vote exponents of a base",
+			conflictingVoteExponents,
+			true,
+		},
+		{
+			"valid server TLS",
+			validServerTLS,
+			false,
+		},
+		{
+			"server TLS certificate without a key",
+			serverTLSWithoutKey,
+			true,
+		},
+		{
+			"server TLS client CA without a certificate",
+			serverTLSClientCAWithoutCert,
+			true,
+		},
+		{
+			"valid server safe mode",
+			serverSafeModeConfig(true, "1h"),
+			false,
+		},
+		{
+			"server safe mode without a max age",
+			serverSafeModeConfig(true, ""),
+			false,
+		},
+		{
+			"server safe mode max age without safe mode",
+			serverSafeModeConfig(false, "1h"),
+			true,
+		},
+		{
+			"server pair control on a loopback address",
+			serverPairControlConfig("127.0.0.1:7171"),
+			false,
+		},
+		{
+			"server pair control on localhost",
+			serverPairControlConfig("localhost:7171"),
+			false,
+		},
+		{
+			"server pair control on a public address",
+			serverPairControlConfig("0.0.0.0:7171"),
+			true,
+		},
+		{
+			"server pair control behind mtls",
+			pairControlBehindMTLS,
+			false,
+		},
+		{
+			"profiling on localhost",
+			profilingConfig("127.0.0.1:6060", false),
+			false,
+		},
+		{
+			"profiling on the localhost name",
+			profilingConfig("localhost:6060", false),
+			false,
+		},
+		{
+			"profiling on the ipv6 loopback",
+			profilingConfig("[::1]:6060", false),
+			false,
+		},
+		{
+			"profiling on a public address",
+			profilingConfig("0.0.0.0:6060", false),
+			true,
+		},
+		{
+			"profiling on every address",
+			profilingConfig(":6060", false),
+			true,
+		},
+		{
+			"profiling on a public address allowing remote access",
+			profilingConfig("0.0.0.0:6060", true),
+			false,
+		},
+		{
+			"profiling without a port",
+			profilingConfig("127.0.0.1", false),
+			true,
+		},
+		{
+			"profiling on an invalid port",
+			profilingConfig("127.0.0.1:0", false),
+			true,
+		},
+		{
+			"profiling on the server listen addr",
+			profilingConfig("0.0.0.0:7171", true),
+			true,
+		},
+		{
+			"invalid server safe mode max age",
+			serverSafeModeConfig(true, "1 hour"),
+			true,
+		},
+		{
+			"non-positive server safe mode max age",
+			serverSafeModeConfig(true, "0s"),
+			true,
+		},
+		{
+			"valid vote rounding",
+			validVoteRounding,
+			false,
+		},
+		{
+			"invalid vote rounding mode",
+			invalidVoteRounding,
+			true,
+		},
+		{
+			"vote decimals without a vote rounding",
+			voteDecimalsWithoutRounding,
+			true,
+		},
+		{
+			"vote decimals above the decimal precision",
+			outOfRangeVoteDecimals,
+			true,
+		},
+		{
+			"conflicting vote roundings of a base",
+			conflictingVoteRoundings,
+			true,
+		},
+		{
+			"valid spot only",
+			validSpotOnly,
+			false,
+		},
+		{
+			"conflicting spot only settings of a base",
+			conflictingSpotOnly,
+			true,
+		},
+		{
+			"valid median tie break",
+			validMedianTieBreak,
+			false,
+		},
+		{
+			"median tie break not a provider of the pair",
+			unknownMedianTieBreak,
+			true,
+		},
+		{
+			"conflicting median tie breaks of a base",
+			conflictingMedianTieBreaks,
+			true,
+		},
+		{
+			"valid max vote change",
+			validMaxVoteChange,
+			false,
+		},
+		{
+			"invalid max vote change",
+			invalidMaxVoteChange,
+			true,
+		},
+		{
+			"max vote change above 1",
+			outOfRangeMaxVoteChange,
+			true,
+		},
+		{
+			"zero max vote change",
+			zeroMaxVoteChange,
+			true,
+		},
+		{
+			"conflicting max vote changes of a base",
+			conflictingMaxVoteChanges,
+			true,
+		},
+		{
+			"valid max forex age",
+			validMaxForexAge,
+			false,
+		},
+		{
+			"max forex age not a duration",
+			invalidMaxForexAge,
+			true,
+		},
+		{
+			"zero max forex age",
+			zeroMaxForexAge,
+			true,
+		},
+		{
+			"max forex age of a pair not quoted in a forex currency",
+			nonForexMaxForexAge,
+			true,
+		},
+		{
+			"valid max clock skew",
+			validMaxClockSkew,
+			false,
+		},
+		{
+			"max clock skew without a unit",
+			invalidMaxClockSkew,
+			true,
+		},
+		{
+			"negative max clock skew",
+			negativeMaxClockSkew,
+			true,
+		},
+		{
+			"valid vote safety margin",
+			validVoteSafetyMargin,
+			false,
+		},
+		{
+			"invalid vote safety margin",
+			invalidVoteSafetyMargin,
+			true,
+		},
+		{
+			"negative vote safety margin",
+			negativeVoteSafetyMargin,
+			true,
+		},
+		{
+			"disabled provider health interval",
+			disabledProviderHealthInterval,
+			false,
+		},
+		{
+			"provider health interval without a unit",
+			invalidProviderHealthInterval,
+			true,
+		},
+		{
+			"negative provider health interval",
+			negativeProviderHealthInterval,
+			true,
+		},
+		{
+			"valid subscription reconcile",
+			validSubscriptionReconcile,
+			false,
+		},
+		{
+			"disabled subscription reconcile",
+			disabledSubscriptionReconcile,
+			false,
+		},
+		{
+			"subscription reconcile interval without a unit",
+			invalidSubscriptionReconcileInterval,
+			true,
+		},
+		{
+			"negative subscription reconcile interval",
+			negativeSubscriptionReconcileInterval,
+			true,
+		},
+		{
+			"subscription reconcile without a max age",
+			zeroSubscriptionReconcileMaxAge,
+			true,
+		},
+		{
+			"valid attestation",
+			validAttestation,
+			false,
+		},
+		{
+			"attestation url without a scheme",
+			invalidAttestationURL,
+			true,
+		},
+		{
+			"zero attestation timeout",
+			invalidAttestationTimeout,
+			true,
+		},
+		{
+			"negative attestation max retries",
+			negativeAttestationRetries,
+			true,
+		},
+		{
+			"valid symbol cache",
+			validSymbolCache,
+			false,
+		},
+		{
+			"symbol cache ttl not a duration",
+			invalidSymbolCacheTTL,
+			true,
+		},
+		{
+			"zero symbol cache ttl",
+			zeroSymbolCacheTTL,
+			true,
+		},
+		{
+			"negative symbol cache max retries",
+			negativeSymbolCacheRetries,
+			true,
+		},
+		{
+			"valid statsd",
+			validStatsd,
+			false,
+		},
+		{
+			"statsd address without port",
+			invalidStatsdAddress,
+			true,
+		},
+		{
+			"statsd address with invalid port",
+			invalidStatsdPort,
+			true,
+		},
+		{
+			"zero statsd flush interval",
+			invalidStatsdFlushInterval,
+			true,
+		},
+		{
+			"valid webhook",
+			validWebhook,
+			false,
+		},
+		{
+			"webhook url without a scheme",
+			invalidWebhookURL,
+			true,
+		},
+		{
+			"webhook without a trigger",
+			webhookWithoutTrigger,
+			true,
+		},
+		{
+			"negative webhook price change",
+			invalidWebhookPriceChange,
+			true,
+		},
+		{
+			"zero webhook timeout",
+			invalidWebhookTimeout,
+			true,
+		},
+		{
+			"disabled reconnect cooldown",
+			disabledReconnectCooldown,
+			false,
+		},
+		{
+			"reconnect cooldown without a unit",
+			invalidReconnectCooldown,
+			true,
+		},
+		{
+			"negative reconnect cooldown",
+			negativeReconnectCooldown,
+			true,
+		},
+		{
+			"valid latency thresholds",
+			validLatencyThresholds,
+			false,
+		},
+		{
+			"latency threshold without a unit",
+			invalidLatencyThreshold,
+			true,
+		},
+		{
+			"latency threshold weight of one",
+			fullLatencyThresholdWeight,
+			true,
+		},
+		{
+			"latency threshold weight increasing with latency",
+			increasingLatencyThresholdWeight,
+			true,
+		},
+		{
+			"duplicate latency threshold",
+			duplicateLatencyThreshold,
+			true,
+		},
+		{
+			"valid ticker window",
+			validTickerWindow,
+			false,
+		},
+		{
+			"ticker window of a provider not providing the pair",
+			unprovidedTickerWindow,
+			true,
+		},
+		{
+			"ticker window of a single sample",
+			singleSampleTickerWindow,
+			true,
+		},
+		{
+			"duplicate ticker window",
+			duplicateTickerWindow,
+			true,
+		},
+		{
+			"wait-with-timeout startup policy",
+			waitStartupPolicy,
+			false,
+		},
+		{
+			"unknown startup policy",
+			invalidStartupPolicy,
+			true,
+		},
+		{
+			"startup timeout without a unit",
+			invalidStartupTimeout,
+			true,
+		},
+		{
+			"zero startup timeout",
+			zeroStartupTimeout,
+			true,
+		},
+		{
+			"providers-first startup order",
+			providersFirstStartupOrder,
+			false,
+		},
+		{
+			"unknown startup order",
+			invalidStartupOrder,
+			true,
+		},
+		{
+			"valid dynamic pairs",
+			validDynamicPairs,
+			false,
+		},
+		{
+			"dynamic pairs without providers",
+			dynamicPairsWithoutProviders,
+			true,
+		},
+		{
+			"unsupported dynamic pairs provider",
+			unsupportedDynamicPairsProvider,
+			true,
+		},
+		{
+			"dynamic pairs without quote",
+			dynamicPairsWithoutQuote,
+			true,
+		},
+		{
+			"dynamic pairs quote without usd pair",
+			dynamicPairsQuoteWithoutUSDPair,
+			true,
+		},
+		{
+			"invalid dynamic pairs refresh interval",
+			invalidDynamicPairsRefresh,
+			true,
+		},
+		{
+			"valid candle intervals",
+			validCandleIntervals,
+			false,
+		},
+		{
+			"unsupported candle interval",
+			unsupportedCandleInterval,
+			true,
+		},
+		{
+			"duplicate candle interval",
+			duplicateCandleInterval,
+			true,
+		},
+		{
+			"candle intervals on an unsupported provider",
+			unsupportedCandleIntervalProvider,
+			true,
+		},
+		{
+			"valid kraken candle intervals",
+			validKrakenCandleIntervals,
+			false,
+		},
+		{
+			"unsupported kraken candle interval",
+			unsupportedKrakenCandleInterval,
+			true,
+		},
+		{
+			"missing CA certificate",
+			missingCACert,
+			true,
+		},
+		{
+			"insecure skip verify",
+			insecureEndpoint,
+			false,
+		},
+		{
+			"valid provider headers",
+			validHeaders,
+			false,
+		},
+		{
+			"invalid provider header name",
+			invalidHeaderName,
+			true,
+		},
+		{
+			"reserved provider header",
+			reservedHeader,
+			true,
+		},
+		{
+			"valid subscription batching",
+			validSubscriptionBatching,
+			false,
+		},
+		{
+			"subscription batch delay without a unit",
+			invalidSubscriptionBatchDelay,
+			true,
+		},
+		{
+			"subscription batch delay without a batch size",
+			subscriptionBatchDelayWithoutSize,
+			true,
+		},
+		{
+			"subscription batching of a provider without websocket",
+			pollingSubscriptionBatching,
+			true,
+		},
+		{
+			"valid compression",
+			validCompression,
+			false,
+		},
+		{
+			"unsupported compression",
+			unsupportedCompression,
+			true,
+		},
+		{
+			"compression of a provider without websocket",
+			pollingCompression,
+			true,
+		},
+		{
+			"valid max candle gap",
+			validCandleGap,
+			false,
+		},
+		{
+			"max candle gap without a unit",
+			invalidCandleGap,
+			true,
+		},
+		{
+			"negative max candle gap",
+			negativeCandleGap,
+			true,
+		},
+		{
+			"valid order book depth",
+			validOrderBookDepth,
+			false,
+		},
+		{
+			"order book depth out of range",
+			outOfRangeOrderBookDepth,
+			true,
+		},
+		{
+			"order book depth of an unsupported provider",
+			unsupportedOrderBookDepth,
+			true,
+		},
+		{
+			"valid ticker reorder window",
+			validTickerReorder,
+			false,
+		},
+		{
+			"ticker reorder window without a unit",
+			invalidTickerReorder,
+			true,
+		},
+		{
+			"ticker reorder window of an unsupported provider",
+			unsupportedTickerReorder,
+			true,
+		},
+		{
+			"valid combined stream",
+			validCombinedStream,
+			false,
+		},
+		{
+			"combined stream of an unsupported provider",
+			unsupportedCombinedStream,
+			true,
+		},
+		{
+			"valid min book depth",
+			validMinBookDepth,
+			false,
+		},
+		{
+			"min book depth not a decimal",
+			invalidMinBookDepth,
+			true,
+		},
+		{
+			"zero min book depth",
+			zeroMinBookDepth,
+			true,
+		},
+		{
+			"valid max spread",
+			validMaxSpread,
+			false,
+		},
+		{
+			"max spread without a best quote provider",
+			unsupportedMaxSpread,
+			true,
+		},
+		{
+			"max spread not a decimal",
+			invalidMaxSpread,
+			true,
+		},
+		{
+			"zero max spread",
+			zeroMaxSpread,
+			true,
+		},
+		{
+			"max spread above 1",
+			tooLargeMaxSpread,
+			true,
+		},
+		{
+			"valid twap window",
+			validTwapWindow,
+			false,
+		},
+		{
+			"twap window without a unit",
+			invalidTwapWindow,
+			true,
+		},
+		{
+			"twap window over a day",
+			longTwapWindow,
+			true,
+		},
+		{
+			"conflicting twap windows of a base",
+			conflictingTwapWindows,
+			true,
+		},
+		{
+			"injective endpoint without websocket",
+			injectiveEndpoint,
+			false,
+		},
+		{
+			"valid price type",
+			validPriceType,
+			false,
+		},
+		{
+			"invalid price type",
+			invalidPriceType,
+			true,
+		},
+		{
+			"unsupported price type provider",
+			unsupportedPriceTypeProvider,
+			true,
+		},
+		{
+			"price type for unconfigured pair",
+			unconfiguredPriceTypePair,
+			true,
+		},
+		{
+			"empty pairs",
+			emptyPairs,
+			true,
+		},
+		{
+			"invalid base",
+			invalidBase,
+			true,
+		},
+		{
+			"invalid quote",
+			invalidQuote,
+			true,
+		},
+		{
+			"empty providers",
+			emptyProviders,
+			true,
+		},
+		{
+			"invalid endpoints",
+			invalidEndpoints,
+			true,
+		},
+		{
+			"invalid endpoint provider",
+			invalidEndpointsProvider,
+			true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			require.Equal(t, tc.cfg.Validate() != nil, tc.expectErr)
+		})
+	}
+}
+
+func TestConfig_InstanceLabels(t *testing.T) {
+	cfg := config.Config{}
+	require.Empty(t, cfg.InstanceLabels())
+
+	cfg.InstanceID = "feeder-01"
+	require.Equal(t, [][]string{{"instance_id", "feeder-01"}}, cfg.InstanceLabels())
+
+	cfg.Environment = "mainnet"
+	require.Equal(t, [][]string{
+		{"instance_id", "feeder-01"},
+		{"environment", "mainnet"},
+	}, cfg.InstanceLabels())
+
+	var buf bytes.Buffer
+	logger := cfg.InstanceLogger(zerolog.New(&buf))
+	logger.Info().Msg("started")
+	require.JSONEq(t, `{"level":"info","instance_id":"feeder-01","environment":"mainnet","message":"started"}`, buf.String())
+}
+
+func TestConfig_OrphanedDeviationBases(t *testing.T) {
+	cfg := config.Config{
+		CurrencyPairs: []config.CurrencyPair{
+			{Base: "ATOM", Quote: "USDT"},
+			{Base: "ATOM", Quote: "USD"},
+			{Base: "OJO", Quote: "ATOM"},
+		},
+		Deviations: []config.Deviation{
+			{Base: "ATOM", Threshold: "1.5"},
+			{Base: "USDT", Threshold: "2"},
+			{Base: "OJO", Threshold: "1"},
+			{Base: "OSMO", Threshold: "2"},
+		},
+	}
+	require.Equal(t, []string{"USDT", "OSMO"}, cfg.OrphanedDeviationBases())
+
+	var buf bytes.Buffer
+	cfg.LogOrphanedDeviations(zerolog.New(&buf))
+	require.Equal(t, 2, strings.Count(buf.String(), "deviation threshold matches no currency pair base"))
+	require.Contains(t, buf.String(), `"base":"USDT"`)
+	require.Contains(t, buf.String(), `"base":"OSMO"`)
+
+	cfg.Deviations = cfg.Deviations[:1]
+	require.Empty(t, cfg.OrphanedDeviationBases())
+}
+
+func TestConfig_StartupPlan(t *testing.T) {
+	cfg := config.Config{
+		CurrencyPairs: []config.CurrencyPair{
+			{
+				Base:                "ATOM",
+				Quote:               "USDT",
+				Providers:           []types.ProviderName{provider.ProviderBinance, provider.ProviderKraken},
+				TickerOnlyProviders: []types.ProviderName{provider.ProviderKraken},
+				MaxProviders:        2,
+				TwapWindow:          "30m",
+			},
+			{
+				Base:      "FOO",
+				Quote:     "USDT",
+				Providers: []types.ProviderName{provider.ProviderOkx},
+				SpotOnly:  true,
+			},
+		},
+		Deviations:       []config.Deviation{{Base: "ATOM", Threshold: "1.5"}},
+		ConversionRoutes: []config.ConversionRoute{{Base: "FOO", Quotes: []string{"USDT"}}},
+	}
+
+	plans := cfg.StartupPlan(map[string]int{"ATOM": 3, "FOO": 1})
+	require.Equal(t, []config.PairPlan{
+		{
+			Pair:                "ATOMUSDT",
+			Providers:           []types.ProviderName{provider.ProviderBinance, provider.ProviderKraken},
+			TickerOnlyProviders: []types.ProviderName{provider.ProviderKraken},
+			MinProviders:        3,
+			MaxProviders:        2,
+			Deviation:           "1.5",
+			TwapWindow:          "30m0s",
+		},
+		{
+			Pair:            "FOOUSDT",
+			Providers:       []types.ProviderName{provider.ProviderOkx},
+			MinProviders:    1,
+			Deviation:       config.DefaultDeviationThreshold,
+			TwapWindow:      config.DefaultTwapWindow.String(),
+			SpotOnly:        true,
+			ConversionRoute: []string{"USDT"},
+		},
+	}, plans)
+
+	var buf bytes.Buffer
+	cfg.LogStartupPlan(zerolog.New(&buf), nil)
+	require.Equal(t, 1, strings.Count(buf.String(), "startup plan"))
+	require.Contains(t, buf.String(), `"provider_mins_enforced":false`)
+	require.Contains(t, buf.String(), `"pair":"ATOMUSDT"`)
+	require.Contains(t, buf.String(), `"min_providers":0`)
+}
+
+func TestParseConfig_Valid(t *testing.T) {
+	tmpFile, err := ioutil.TempFile("", "price-feeder*.toml")
+	require.NoError(t, err)
+	defer os.Remove(tmpFile.Name())
+
+	content := []byte(`
+gas_adjustment = 1.5
+
+[server]
+listen_addr = "0.0.0.0:99999"
+read_timeout = "20s"
+verbose_cors = true
+write_timeout = "20s"
+
+[[currency_pairs]]
+base = "ATOM"
+quote = "USDT"
+providers = [
+	"kraken",
+	"binance",
+	"huobi"
+]
+
+[[currency_pairs]]
+base = "OJO"
+quote = "USDT"
+providers = [
+	"kraken",
+	"binance",
+	"huobi"
+]
+
+[[currency_pairs]]
+base = "USDT"
+quote = "USD"
+providers = [
+	"kraken",
+	"binance",
+	"huobi"
+]
+
+[account]
+address = "ojo15nejfgcaanqpw25ru4arvfd0fwy6j8clccvwx4"
+validator = "ojovalcons14rjlkfzp56733j5l5nfk6fphjxymgf8mj04d5p"
+chain_id = "ojo-local-testnet"
+
+[keyring]
+backend = "test"
+dir = "/Users/username/.ojo"
+pass = "keyringPassword"
+
+[rpc]
+tmrpc_endpoint = "http://localhost:26657"
+grpc_endpoint = "localhost:9090"
+rpc_timeout = "100ms"
+
+[telemetry]
+service-name = "price-feeder"
+enabled = true
+enable-hostname = true
+enable-hostname-label = true
+enable-service-label = true
+prometheus-retention = 120
+global-labels = [["chain-id", "ojo-local-testnet"]]
+`)
+	_, err = tmpFile.Write(content)
+	require.NoError(t, err)
+
+	cfg, err := config.ParseConfig(tmpFile.Name())
+	require.NoError(t, err)
+
+	require.Equal(t, "0.0.0.0:99999", cfg.Server.ListenAddr)
+	require.Equal(t, "20s", cfg.Server.WriteTimeout)
+	require.Equal(t, "20s", cfg.Server.ReadTimeout)
+	require.True(t, cfg.Server.VerboseCORS)
+	require.False(t, cfg.Profiling.Enabled)
+	require.Equal(t, "127.0.0.1:6060", cfg.Profiling.ListenAddr)
+	require.Len(t, cfg.CurrencyPairs, 3)
+	require.Equal(t, "ATOM", cfg.CurrencyPairs[0].Base)
+	require.Equal(t, "USDT", cfg.CurrencyPairs[0].Quote)
+	require.Len(t, cfg.CurrencyPairs[0].Providers, 3)
+	require.Equal(t, provider.ProviderKraken, cfg.CurrencyPairs[0].Providers[0])
+	require.Equal(t, provider.ProviderBinance, cfg.CurrencyPairs[0].Providers[1])
+}
+
+func TestParseConfig_Valid_NoTelemetry(t *testing.T) {
+	tmpFile, err := ioutil.TempFile("", "price-feeder*.toml")
+	require.NoError(t, err)
+	defer os.Remove(tmpFile.Name())
+
+	content := []byte(`
+gas_adjustment = 1.5
+
+[server]
+listen_addr = "0.0.0.0:99999"
+read_timeout = "20s"
+verbose_cors = true
+write_timeout = "20s"
+
+[[currency_pairs]]
+base = "ATOM"
+quote = "USDT"
+providers = [
+	"kraken",
+	"binance",
+	"huobi"
+]
+
+[[currency_pairs]]
+base = "OJO"
+quote = "USDT"
+providers = [
+	"kraken",
+	"binance",
+	"huobi"
+]
+
+[[currency_pairs]]
+base = "USDT"
+quote = "USD"
+providers = [
+	"kraken",
+	"binance",
+	"huobi"
+]
+
+[account]
+address = "ojo15nejfgcaanqpw25ru4arvfd0fwy6j8clccvwx4"
+validator = "ojovalcons14rjlkfzp56733j5l5nfk6fphjxymgf8mj04d5p"
+chain_id = "ojo-local-testnet"
+
+[keyring]
+backend = "test"
+dir = "/Users/username/.ojo"
+pass = "keyringPassword"
+
+[rpc]
+tmrpc_endpoint = "http://localhost:26657"
+grpc_endpoint = "localhost:9090"
+rpc_timeout = "100ms"
+
+[telemetry]
+enabled = false
+`)
+	_, err = tmpFile.Write(content)
+	require.NoError(t, err)
+
+	cfg, err := config.ParseConfig(tmpFile.Name())
+	require.NoError(t, err)
+
+	require.Equal(t, "0.0.0.0:99999", cfg.Server.ListenAddr)
+	require.Equal(t, "20s", cfg.Server.WriteTimeout)
+	require.Equal(t, "20s", cfg.Server.ReadTimeout)
+	require.True(t, cfg.Server.VerboseCORS)
+	require.Len(t, cfg.CurrencyPairs, 3)
+	require.Equal(t, "ATOM", cfg.CurrencyPairs[0].Base)
+	require.Equal(t, "USDT", cfg.CurrencyPairs[0].Quote)
+	require.Len(t, cfg.CurrencyPairs[0].Providers, 3)
+	require.Equal(t, provider.ProviderKraken, cfg.CurrencyPairs[0].Providers[0])
+	require.Equal(t, provider.ProviderBinance, cfg.CurrencyPairs[0].Providers[1])
+	require.Equal(t, cfg.Telemetry.Enabled, false)
+}
+
+func TestParseConfig_InvalidProvider(t *testing.T) {
+	tmpFile, err := ioutil.TempFile("", "price-feeder*.toml")
+	require.NoError(t, err)
+	defer os.Remove(tmpFile.Name())
+
+	content := []byte(`
+listen_addr = ""
+
+[[currency_pairs]]
+base = "ATOM"
+quote = "USD"
+providers = [
+	"kraken",
+	"binance"
+]
+
+[[currency_pairs]]
+base = "OJO"
+quote = "USD"
+providers = [
+	"kraken",
+	"foobar"
+]
+`)
+	_, err = tmpFile.Write(content)
+	require.NoError(t, err)
+
+	_, err = config.ParseConfig(tmpFile.Name())
+	require.ErrorContains(t, err, "unsupported provider: foobar (supported providers: ascendex, binance, binanceus,")
+	require.NotContains(t, err.Error(), "did you mean")
+}
+
+func TestValidate_UnsupportedProviderSuggestion(t *testing.T) {
+	cfg := config.Config{
+		CurrencyPairs: []config.CurrencyPair{
+			{Base: "ATOM", Quote: "USDT", Providers: []types.ProviderName{provider.ProviderBinance, "krakn"}},
+		},
+	}
+	err := cfg.Validate()
+	require.ErrorContains(t, err, "unsupported provider: krakn; did you mean kraken?")
+
+	cfg.CurrencyPairs[0].Providers = []types.ProviderName{"Coinbase"}
+	require.ErrorContains(t, cfg.Validate(), "unsupported provider: Coinbase; did you mean coinbase?")
+
+	cfg.CurrencyPairs[0].Providers = []types.ProviderName{provider.ProviderBinance}
+	cfg.DefaultProviders = []types.ProviderName{"okex"}
+	require.ErrorContains(t, cfg.Validate(), "unsupported default provider: okex; did you mean okx?")
+
+	cfg.DefaultProviders = nil
+	cfg.ProviderEndpoints = []provider.Endpoint{{Name: "binanse", Rest: "https://api1.binance.com"}}
+	require.ErrorContains(t, cfg.Validate(), "unsupported endpoint provider: binanse; did you mean binance?")
+}
+
+func TestParseConfig_NonUSDQuote(t *testing.T) {
+	tmpFile, err := ioutil.TempFile("", "price-feeder*.toml")
+	require.NoError(t, err)
+	defer os.Remove(tmpFile.Name())
+
+	content := []byte(`
+listen_addr = ""
+
+[[currency_pairs]]
+base = "ATOM"
+quote = "USDT"
+providers = [
+	"kraken",
+	"binance"
+]
+
+[[currency_pairs]]
+base = "stOJO"
+quote = "OJO"
+providers = [
+	"kraken",
+	"binance"
+]
+`)
+	_, err = tmpFile.Write(content)
+	require.NoError(t, err)
+
+	_, err = config.ParseConfig(tmpFile.Name())
+	require.Error(t, err)
+}
+
+func TestParseConfig_Valid_Deviations(t *testing.T) {
+	tmpFile, err := ioutil.TempFile("", "price-feeder*.toml")
+	require.NoError(t, err)
+	defer os.Remove(tmpFile.Name())
+
+	content := []byte(`
+gas_adjustment = 1.5
+
+[server]
+listen_addr = "0.0.0.0:99999"
+read_timeout = "20s"
+verbose_cors = true
+write_timeout = "20s"
+
+[[deviation_thresholds]]
+base = "USDT"
+threshold = "2"
+
+[[deviation_thresholds]]
+base = "ATOM"
+threshold = "1.5"
+
+[[currency_pairs]]
+base = "ATOM"
+quote = "USDT"
+providers = [
+	"kraken",
+	"binance",
+	"huobi"
+]
+
+[[currency_pairs]]
+base = "OJO"
+quote = "USDT"
+providers = [
+	"kraken",
+	"binance",
+	"huobi"
+]
+
+[[currency_pairs]]
+base = "USDT"
+quote = "USD"
+providers = [
+	"kraken",
+	"binance",
+	"huobi"
+]
+
+[account]
+address = "ojo15nejfgcaanqpw25ru4arvfd0fwy6j8clccvwx4"
+validator = "ojovalcons14rjlkfzp56733j5l5nfk6fphjxymgf8mj04d5p"
+chain_id = "ojo-local-testnet"
+
+[keyring]
+backend = "test"
+dir = "/Users/username/.ojo"
+pass = "keyringPassword"
+
+[rpc]
+tmrpc_endpoint = "http://localhost:26657"
+grpc_endpoint = "localhost:9090"
+rpc_timeout = "100ms"
+
+[telemetry]
+service-name = "price-feeder"
+enabled = true
+enable-hostname = true
+enable-hostname-label = true
+enable-service-label = true
+prometheus-retention = 120
+global-labels = [["chain-id", "ojo-local-testnet"]]
+`)
+	_, err = tmpFile.Write(content)
+	require.NoError(t, err)
+
+	cfg, err := config.ParseConfig(tmpFile.Name())
+	require.NoError(t, err)
+
+	require.Equal(t, "0.0.0.0:99999", cfg.Server.ListenAddr)
+	require.Equal(t, "20s", cfg.Server.WriteTimeout)
+	require.Equal(t, "20s", cfg.Server.ReadTimeout)
+	require.True(t, cfg.Server.VerboseCORS)
+	require.Len(t, cfg.CurrencyPairs, 3)
+	require.Equal(t, "ATOM", cfg.CurrencyPairs[0].Base)
+	require.Equal(t, "USDT", cfg.CurrencyPairs[0].Quote)
+	require.Len(t, cfg.CurrencyPairs[0].Providers, 3)
+	require.Equal(t, provider.ProviderKraken, cfg.CurrencyPairs[0].Providers[0])
+	require.Equal(t, provider.ProviderBinance, cfg.CurrencyPairs[0].Providers[1])
+	require.Equal(t, "2", cfg.Deviations[0].Threshold)
+	require.Equal(t, "USDT", cfg.Deviations[0].Base)
+	require.Equal(t, "1.5", cfg.Deviations[1].Threshold)
+	require.Equal(t, "ATOM", cfg.Deviations[1].Base)
+}
+
+func TestParseConfig_Invalid_Deviations(t *testing.T) {
+	tmpFile, err := ioutil.TempFile("", "price-feeder*.toml")
+	require.NoError(t, err)
+	defer os.Remove(tmpFile.Name())
+
+	content := []byte(`
+gas_adjustment = 1.5
+
+[server]
+listen_addr = "0.0.0.0:99999"
+read_timeout = "20s"
+verbose_cors = true
+write_timeout = "20s"
+
+[[deviation_thresholds]]
+base = "USDT"
+threshold = "4.0"
+
+[[deviation_thresholds]]
+base = "ATOM"
+threshold = "1.5"
+
+[[currency_pairs]]
+base = "ATOM"
+quote = "USDT"
+providers = [
+	"kraken",
+	"binance",
+	"huobi"
+]
+
+[[currency_pairs]]
+base = "OJO"
+quote = "USDT"
+providers = [
+	"kraken",
+	"binance",
+	"huobi"
+]
+
+[[currency_pairs]]
+base = "USDT"
+quote = "USD"
+providers = [
+	"kraken",
+	"binance",
+	"huobi"
+]
+
+[account]
+address = "ojo15nejfgcaanqpw25ru4arvfd0fwy6j8clccvwx4"
+validator = "ojovalcons14rjlkfzp56733j5l5nfk6fphjxymgf8mj04d5p"
+chain_id = "ojo-local-testnet"
+
+[keyring]
+backend = "test"
+dir = "/Users/username/.ojo"
+pass = "keyringPassword"
+
+[rpc]
+tmrpc_endpoint = "http://localhost:26657"
+grpc_endpoint = "localhost:9090"
+rpc_timeout = "100ms"
+
+[telemetry]
+service-name = "price-feeder"
+enabled = true
+enable-hostname = true
+enable-hostname_label = true
+enable-service_label = true
+prometheus-retention = 120
+global-labels = [["chain-id", "ojo-local-testnet"]]
+`)
+	_, err = tmpFile.Write(content)
+	require.NoError(t, err)
+
+	_, err = config.ParseConfig(tmpFile.Name())
+	require.Error(t, err)
 }
 
-func TestParseConfig_Valid(t *testing.T) {
+func TestParseConfig_Env_Vars(t *testing.T) {
 	tmpFile, err := ioutil.TempFile("", "price-feeder*.toml")
 	require.NoError(t, err)
 	defer os.Remove(tmpFile.Name())
@@ -150,6 +3431,10 @@ read_timeout = "20s"
 verbose_cors = true
 write_timeout = "20s"
 
+[[deviation_thresholds]]
+base = "USDT"
+threshold = "3.0"
+
 [[currency_pairs]]
 base = "ATOM"
 quote = "USDT"
@@ -196,21 +3481,27 @@ rpc_timeout = "100ms"
 service-name = "price-feeder"
 enabled = true
 enable-hostname = true
-enable-hostname-label = true
-enable-service-label = true
+enable-hostname_label = true
+enable-service_label = true
 prometheus-retention = 120
 global-labels = [["chain-id", "ojo-local-testnet"]]
 `)
 	_, err = tmpFile.Write(content)
 	require.NoError(t, err)
 
+	// Set env variables to overwrite config files
+	os.Setenv("SERVER_LISTEN_ADDR", "0.0.0.0:888888")
+	os.Setenv("SERVER_WRITE_TIMEOUT", "10s")
+	os.Setenv("SERVER_READ_TIMEOUT", "10s")
+	os.Setenv("SERVER_VERBOSE_CORS", "false")
+
 	cfg, err := config.ParseConfig(tmpFile.Name())
 	require.NoError(t, err)
 
-	require.Equal(t, "0.0.0.0:99999", cfg.Server.ListenAddr)
-	require.Equal(t, "20s", cfg.Server.WriteTimeout)
-	require.Equal(t, "20s", cfg.Server.ReadTimeout)
-	require.True(t, cfg.Server.VerboseCORS)
+	require.Equal(t, "0.0.0.0:888888", cfg.Server.ListenAddr)
+	require.Equal(t, "10s", cfg.Server.WriteTimeout)
+	require.Equal(t, "10s", cfg.Server.ReadTimeout)
+	require.False(t, cfg.Server.VerboseCORS)
 	require.Len(t, cfg.CurrencyPairs, 3)
 	require.Equal(t, "ATOM", cfg.CurrencyPairs[0].Base)
 	require.Equal(t, "USDT", cfg.CurrencyPairs[0].Quote)
@@ -219,7 +3510,104 @@ global-labels = [["chain-id", "ojo-local-testnet"]]
 	require.Equal(t, provider.ProviderBinance, cfg.CurrencyPairs[0].Providers[1])
 }
 
-func TestParseConfig_Valid_NoTelemetry(t *testing.T) {
+func TestParseConfig_Remote(t *testing.T) {
+	content := `
+gas_adjustment = 1.5
+
+[server]
+listen_addr = "0.0.0.0:99999"
+read_timeout = "20s"
+verbose_cors = true
+write_timeout = "20s"
+
+[[currency_pairs]]
+base = "ATOM"
+quote = "USDT"
+providers = [
+	"kraken",
+	"binance",
+]
+
+[[currency_pairs]]
+base = "USDT"
+quote = "USD"
+providers = [
+	"kraken",
+	"binance",
+]
+
+[account]
+address = "ojo15nejfgcaanqpw25ru4arvfd0fwy6j8clccvwx4"
+validator = "ojovalcons14rjlkfzp56733j5l5nfk6fphjxymgf8mj04d5p"
+chain_id = "ojo-local-testnet"
+
+[keyring]
+backend = "test"
+dir = "/Users/username/.ojo"
+
+[rpc]
+tmrpc_endpoint = "http://localhost:26657"
+grpc_endpoint = "localhost:9090"
+rpc_timeout = "100ms"
+`
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/price-feeder.toml":
+			_, _ = w.Write([]byte(content))
+		case "/v1/kv/price-feeder/config":
+			require.Contains(t, r.URL.Query(), "raw")
+			require.Equal(t, "secret", r.Header.Get("X-Consul-Token"))
+			_, _ = w.Write([]byte(content))
+		case "/v3/kv/range":
+			var req struct {
+				Key string `json:"key"`
+			}
+			require.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+			key, err := base64.StdEncoding.DecodeString(req.Key)
+			require.NoError(t, err)
+			if string(key) != "/price-feeder/config.toml" {
+				_, _ = w.Write([]byte(`{"header":{}}`))
+				return
+			}
+			_, _ = fmt.Fprintf(w, `{"kvs":[{"value":%q}]}`, base64.StdEncoding.EncodeToString([]byte(content)))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+	host := strings.TrimPrefix(server.URL, "http://")
+	t.Setenv("CONSUL_HTTP_TOKEN", "secret")
+
+	for _, location := range []string{
+		server.URL + "/price-feeder.toml",
+		"consul://" + host + "/price-feeder/config",
+		"etcd://" + host + "/price-feeder/config.toml",
+	} {
+		t.Run(location, func(t *testing.T) {
+			// env variables override the remote config
+			t.Setenv("SERVER_READ_TIMEOUT", "10s")
+
+			cfg, err := config.ParseConfig(location)
+			require.NoError(t, err)
+			require.Equal(t, "10s", cfg.Server.ReadTimeout)
+			require.Equal(t, 1.5, cfg.GasAdjustment)
+			require.Len(t, cfg.CurrencyPairs, 2)
+			require.Equal(t, "ATOM", cfg.CurrencyPairs[0].Base)
+		})
+	}
+
+	_, err := config.ParseConfig(server.URL + "/missing.toml")
+	require.ErrorContains(t, err, "failed to fetch config from "+server.URL+"/missing.toml: unexpected status 404")
+
+	_, err = config.ParseConfig("etcd://" + host + "/missing.toml")
+	require.ErrorContains(t, err, "etcd key /missing.toml not found")
+
+	_, err = config.ParseConfig("consul://" + host)
+	require.ErrorContains(t, err, "missing consul key")
+}
+
+func TestCheckProviderMins_Valid(t *testing.T) {
 	tmpFile, err := ioutil.TempFile("", "price-feeder*.toml")
 	require.NoError(t, err)
 	defer os.Remove(tmpFile.Name())
@@ -284,82 +3672,81 @@ enabled = false
 	cfg, err := config.ParseConfig(tmpFile.Name())
 	require.NoError(t, err)
 
-	require.Equal(t, "0.0.0.0:99999", cfg.Server.ListenAddr)
-	require.Equal(t, "20s", cfg.Server.WriteTimeout)
-	require.Equal(t, "20s", cfg.Server.ReadTimeout)
-	require.True(t, cfg.Server.VerboseCORS)
-	require.Len(t, cfg.CurrencyPairs, 3)
-	require.Equal(t, "ATOM", cfg.CurrencyPairs[0].Base)
-	require.Equal(t, "USDT", cfg.CurrencyPairs[0].Quote)
-	require.Len(t, cfg.CurrencyPairs[0].Providers, 3)
-	require.Equal(t, provider.ProviderKraken, cfg.CurrencyPairs[0].Providers[0])
-	require.Equal(t, provider.ProviderBinance, cfg.CurrencyPairs[0].Providers[1])
-	require.Equal(t, cfg.Telemetry.Enabled, false)
+	logger := zerolog.New(zerolog.ConsoleWriter{Out: os.Stderr}).Level(zerolog.InfoLevel).With().Timestamp().Logger()
+	err = config.CheckProviderMins(context.TODO(), logger, cfg)
+	require.NoError(t, err)
 }
 
-func TestParseConfig_InvalidProvider(t *testing.T) {
+func TestCheckProviderMins_Invalid(t *testing.T) {
 	tmpFile, err := ioutil.TempFile("", "price-feeder*.toml")
 	require.NoError(t, err)
 	defer os.Remove(tmpFile.Name())
 
 	content := []byte(`
-listen_addr = ""
+gas_adjustment = 1.5
+
+[server]
+listen_addr = "0.0.0.0:99999"
+read_timeout = "20s"
+verbose_cors = true
+write_timeout = "20s"
 
 [[currency_pairs]]
 base = "ATOM"
-quote = "USD"
+quote = "USDT"
 providers = [
 	"kraken",
-	"binance"
+	"binance",
 ]
 
 [[currency_pairs]]
 base = "OJO"
-quote = "USD"
-providers = [
-	"kraken",
-	"foobar"
-]
-`)
-	_, err = tmpFile.Write(content)
-	require.NoError(t, err)
-
-	_, err = config.ParseConfig(tmpFile.Name())
-	require.Error(t, err)
-}
-
-func TestParseConfig_NonUSDQuote(t *testing.T) {
-	tmpFile, err := ioutil.TempFile("", "price-feeder*.toml")
-	require.NoError(t, err)
-	defer os.Remove(tmpFile.Name())
-
-	content := []byte(`
-listen_addr = ""
-
-[[currency_pairs]]
-base = "ATOM"
 quote = "USDT"
 providers = [
 	"kraken",
-	"binance"
+	"binance",
+	"huobi"
 ]
 
 [[currency_pairs]]
-base = "stOJO"
-quote = "OJO"
+base = "USDT"
+quote = "USD"
 providers = [
 	"kraken",
-	"binance"
+	"binance",
+	"huobi"
 ]
+
+[account]
+address = "ojo15nejfgcaanqpw25ru4arvfd0fwy6j8clccvwx4"
+validator = "ojovalcons14rjlkfzp56733j5l5nfk6fphjxymgf8mj04d5p"
+chain_id = "ojo-local-testnet"
+
+[keyring]
+backend = "test"
+dir = "/Users/username/.ojo"
+pass = "keyringPassword"
+
+[rpc]
+tmrpc_endpoint = "http://localhost:26657"
+grpc_endpoint = "localhost:9090"
+rpc_timeout = "100ms"
+
+[telemetry]
+enabled = false
 `)
 	_, err = tmpFile.Write(content)
 	require.NoError(t, err)
 
-	_, err = config.ParseConfig(tmpFile.Name())
-	require.Error(t, err)
+	cfg, err := config.ParseConfig(tmpFile.Name())
+	require.NoError(t, err)
+
+	logger := zerolog.New(zerolog.ConsoleWriter{Out: os.Stderr}).Level(zerolog.InfoLevel).With().Timestamp().Logger()
+	err = config.CheckProviderMins(context.TODO(), logger, cfg)
+	require.EqualError(t, err, "must have at least 3 providers for ATOM")
 }
 
-func TestParseConfig_Valid_Deviations(t *testing.T) {
+func TestProviderWithAPIKey_Valid(t *testing.T) {
 	tmpFile, err := ioutil.TempFile("", "price-feeder*.toml")
 	require.NoError(t, err)
 	defer os.Remove(tmpFile.Name())
@@ -373,14 +3760,6 @@ read_timeout = "20s"
 verbose_cors = true
 write_timeout = "20s"
 
-[[deviation_thresholds]]
-base = "USDT"
-threshold = "2"
-
-[[deviation_thresholds]]
-base = "ATOM"
-threshold = "1.5"
-
 [[currency_pairs]]
 base = "ATOM"
 quote = "USDT"
@@ -408,6 +3787,13 @@ providers = [
 	"huobi"
 ]
 
+[[currency_pairs]]
+base = "EUR"
+providers = [
+  "polygon",
+]
+quote = "USD"
+
 [account]
 address = "ojo15nejfgcaanqpw25ru4arvfd0fwy6j8clccvwx4"
 validator = "ojovalcons14rjlkfzp56733j5l5nfk6fphjxymgf8mj04d5p"
@@ -424,13 +3810,13 @@ grpc_endpoint = "localhost:9090"
 rpc_timeout = "100ms"
 
 [telemetry]
-service-name = "price-feeder"
-enabled = true
-enable-hostname = true
-enable-hostname-label = true
-enable-service-label = true
-prometheus-retention = 120
-global-labels = [["chain-id", "ojo-local-testnet"]]
+enabled = false
+
+[[provider_endpoints]]
+name = "polygon"
+rest = "https://api.polygon.io/v2/"
+websocket = "wss://socket.polygon.io/forex"
+apikey = "test"
 `)
 	_, err = tmpFile.Write(content)
 	require.NoError(t, err)
@@ -438,23 +3824,13 @@ global-labels = [["chain-id", "ojo-local-testnet"]]
 	cfg, err := config.ParseConfig(tmpFile.Name())
 	require.NoError(t, err)
 
-	require.Equal(t, "0.0.0.0:99999", cfg.Server.ListenAddr)
-	require.Equal(t, "20s", cfg.Server.WriteTimeout)
-	require.Equal(t, "20s", cfg.Server.ReadTimeout)
-	require.True(t, cfg.Server.VerboseCORS)
-	require.Len(t, cfg.CurrencyPairs, 3)
-	require.Equal(t, "ATOM", cfg.CurrencyPairs[0].Base)
-	require.Equal(t, "USDT", cfg.CurrencyPairs[0].Quote)
-	require.Len(t, cfg.CurrencyPairs[0].Providers, 3)
-	require.Equal(t, provider.ProviderKraken, cfg.CurrencyPairs[0].Providers[0])
-	require.Equal(t, provider.ProviderBinance, cfg.CurrencyPairs[0].Providers[1])
-	require.Equal(t, "2", cfg.Deviations[0].Threshold)
-	require.Equal(t, "USDT", cfg.Deviations[0].Base)
-	require.Equal(t, "1.5", cfg.Deviations[1].Threshold)
-	require.Equal(t, "ATOM", cfg.Deviations[1].Base)
+	// Forex currency should allow 1 provider minumum
+	logger := zerolog.New(zerolog.ConsoleWriter{Out: os.Stderr}).Level(zerolog.InfoLevel).With().Timestamp().Logger()
+	err = config.CheckProviderMins(context.TODO(), logger, cfg)
+	require.NoError(t, err)
 }
 
-func TestParseConfig_Invalid_Deviations(t *testing.T) {
+func TestProviderWithAPIKey_Invalid(t *testing.T) {
 	tmpFile, err := ioutil.TempFile("", "price-feeder*.toml")
 	require.NoError(t, err)
 	defer os.Remove(tmpFile.Name())
@@ -465,16 +3841,8 @@ gas_adjustment = 1.5
 [server]
 listen_addr = "0.0.0.0:99999"
 read_timeout = "20s"
-verbose_cors = true
-write_timeout = "20s"
-
-[[deviation_thresholds]]
-base = "USDT"
-threshold = "4.0"
-
-[[deviation_thresholds]]
-base = "ATOM"
-threshold = "1.5"
+verbose_cors = true
+write_timeout = "20s"
 
 [[currency_pairs]]
 base = "ATOM"
@@ -503,6 +3871,13 @@ providers = [
 	"huobi"
 ]
 
+[[currency_pairs]]
+base = "EUR"
+providers = [
+  "polygon",
+]
+quote = "USD"
+
 [account]
 address = "ojo15nejfgcaanqpw25ru4arvfd0fwy6j8clccvwx4"
 validator = "ojovalcons14rjlkfzp56733j5l5nfk6fphjxymgf8mj04d5p"
@@ -519,22 +3894,21 @@ grpc_endpoint = "localhost:9090"
 rpc_timeout = "100ms"
 
 [telemetry]
-service-name = "price-feeder"
-enabled = true
-enable-hostname = true
-enable-hostname_label = true
-enable-service_label = true
-prometheus-retention = 120
-global-labels = [["chain-id", "ojo-local-testnet"]]
+enabled = false
+
+[[provider_endpoints]]
+name = "polygon"
+rest = "https://api.polygon.io/v2/"
+websocket = "wss://socket.polygon.io/forex"
 `)
 	_, err = tmpFile.Write(content)
 	require.NoError(t, err)
 
 	_, err = config.ParseConfig(tmpFile.Name())
-	require.Error(t, err)
+	require.EqualError(t, err, "provider polygon requires an API Key")
 }
 
-func TestParseConfig_Env_Vars(t *testing.T) {
+func TestInvalidCurrencyPairs(t *testing.T) {
 	tmpFile, err := ioutil.TempFile("", "price-feeder*.toml")
 	require.NoError(t, err)
 	defer os.Remove(tmpFile.Name())
@@ -548,10 +3922,6 @@ read_timeout = "20s"
 verbose_cors = true
 write_timeout = "20s"
 
-[[deviation_thresholds]]
-base = "USDT"
-threshold = "3.0"
-
 [[currency_pairs]]
 base = "ATOM"
 quote = "USDT"
@@ -571,8 +3941,8 @@ providers = [
 ]
 
 [[currency_pairs]]
-base = "USDT"
-quote = "USD"
+base = "stUMEE"
+quote = "BAD_QUOTE"
 providers = [
 	"kraken",
 	"binance",
@@ -595,39 +3965,16 @@ grpc_endpoint = "localhost:9090"
 rpc_timeout = "100ms"
 
 [telemetry]
-service-name = "price-feeder"
-enabled = true
-enable-hostname = true
-enable-hostname_label = true
-enable-service_label = true
-prometheus-retention = 120
-global-labels = [["chain-id", "ojo-local-testnet"]]
+enabled = false
 `)
 	_, err = tmpFile.Write(content)
 	require.NoError(t, err)
 
-	// Set env variables to overwrite config files
-	os.Setenv("SERVER_LISTEN_ADDR", "0.0.0.0:888888")
-	os.Setenv("SERVER_WRITE_TIMEOUT", "10s")
-	os.Setenv("SERVER_READ_TIMEOUT", "10s")
-	os.Setenv("SERVER_VERBOSE_CORS", "false")
-
-	cfg, err := config.ParseConfig(tmpFile.Name())
-	require.NoError(t, err)
-
-	require.Equal(t, "0.0.0.0:888888", cfg.Server.ListenAddr)
-	require.Equal(t, "10s", cfg.Server.WriteTimeout)
-	require.Equal(t, "10s", cfg.Server.ReadTimeout)
-	require.False(t, cfg.Server.VerboseCORS)
-	require.Len(t, cfg.CurrencyPairs, 3)
-	require.Equal(t, "ATOM", cfg.CurrencyPairs[0].Base)
-	require.Equal(t, "USDT", cfg.CurrencyPairs[0].Quote)
-	require.Len(t, cfg.CurrencyPairs[0].Providers, 3)
-	require.Equal(t, provider.ProviderKraken, cfg.CurrencyPairs[0].Providers[0])
-	require.Equal(t, provider.ProviderBinance, cfg.CurrencyPairs[0].Providers[1])
+	_, err = config.ParseConfig(tmpFile.Name())
+	require.Error(t, err, "currency pair quote UMEE is not supported")
 }
 
-func TestCheckProviderMins_Valid(t *testing.T) {
+func TestValidCurrencyPairs(t *testing.T) {
 	tmpFile, err := ioutil.TempFile("", "price-feeder*.toml")
 	require.NoError(t, err)
 	defer os.Remove(tmpFile.Name())
@@ -660,8 +4007,8 @@ providers = [
 ]
 
 [[currency_pairs]]
-base = "USDT"
-quote = "USD"
+base = "stOSMO"
+quote = "OSMO"
 providers = [
 	"kraken",
 	"binance",
@@ -689,15 +4036,11 @@ enabled = false
 	_, err = tmpFile.Write(content)
 	require.NoError(t, err)
 
-	cfg, err := config.ParseConfig(tmpFile.Name())
-	require.NoError(t, err)
-
-	logger := zerolog.New(zerolog.ConsoleWriter{Out: os.Stderr}).Level(zerolog.InfoLevel).With().Timestamp().Logger()
-	err = config.CheckProviderMins(context.TODO(), logger, cfg)
+	_, err = config.ParseConfig(tmpFile.Name())
 	require.NoError(t, err)
 }
 
-func TestCheckProviderMins_Invalid(t *testing.T) {
+func TestMultipleConfigs(t *testing.T) {
 	tmpFile, err := ioutil.TempFile("", "price-feeder*.toml")
 	require.NoError(t, err)
 	defer os.Remove(tmpFile.Name())
@@ -711,12 +4054,39 @@ read_timeout = "20s"
 verbose_cors = true
 write_timeout = "20s"
 
+[account]
+address = "ojo15nejfgcaanqpw25ru4arvfd0fwy6j8clccvwx4"
+validator = "ojovalcons14rjlkfzp56733j5l5nfk6fphjxymgf8mj04d5p"
+chain_id = "ojo-local-testnet"
+
+[keyring]
+backend = "test"
+dir = "/Users/username/.ojo"
+pass = "keyringPassword"
+
+[rpc]
+tmrpc_endpoint = "http://localhost:26657"
+grpc_endpoint = "localhost:9090"
+rpc_timeout = "100ms"
+
+[telemetry]
+enabled = false
+`)
+	_, err = tmpFile.Write(content)
+	require.NoError(t, err)
+
+	tmpFile2, err := ioutil.TempFile("", "provider-config*.toml")
+	require.NoError(t, err)
+	defer os.Remove(tmpFile2.Name())
+
+	content2 := []byte(`
 [[currency_pairs]]
 base = "ATOM"
 quote = "USDT"
 providers = [
 	"kraken",
 	"binance",
+	"huobi"
 ]
 
 [[currency_pairs]]
@@ -729,13 +4099,32 @@ providers = [
 ]
 
 [[currency_pairs]]
-base = "USDT"
-quote = "USD"
+base = "stOSMO"
+quote = "OSMO"
 providers = [
 	"kraken",
 	"binance",
 	"huobi"
 ]
+`)
+	_, err = tmpFile2.Write(content2)
+	require.NoError(t, err)
+
+	_, err = config.ParseConfigs([]string{tmpFile.Name(), tmpFile2.Name()})
+	require.NoError(t, err)
+}
+
+func TestMultipleConfigs_DuplicatePairs(t *testing.T) {
+	dir := t.TempDir()
+	writeConfig := func(name, content string) string {
+		path := filepath.Join(dir, name)
+		require.NoError(t, os.WriteFile(path, []byte(content), 0o600))
+		return path
+	}
+
+	nodeConfig := `
+gas_adjustment = 1.5
+duplicate_pairs = "%s"
 
 [account]
 address = "ojo15nejfgcaanqpw25ru4arvfd0fwy6j8clccvwx4"
@@ -745,196 +4134,570 @@ chain_id = "ojo-local-testnet"
 [keyring]
 backend = "test"
 dir = "/Users/username/.ojo"
-pass = "keyringPassword"
 
 [rpc]
 tmrpc_endpoint = "http://localhost:26657"
 grpc_endpoint = "localhost:9090"
 rpc_timeout = "100ms"
 
-[telemetry]
-enabled = false
-`)
-	_, err = tmpFile.Write(content)
-	require.NoError(t, err)
-
-	cfg, err := config.ParseConfig(tmpFile.Name())
-	require.NoError(t, err)
-
-	logger := zerolog.New(zerolog.ConsoleWriter{Out: os.Stderr}).Level(zerolog.InfoLevel).With().Timestamp().Logger()
-	err = config.CheckProviderMins(context.TODO(), logger, cfg)
-	require.EqualError(t, err, "must have at least 3 providers for ATOM")
-}
-
-func TestProviderWithAPIKey_Valid(t *testing.T) {
-	tmpFile, err := ioutil.TempFile("", "price-feeder*.toml")
-	require.NoError(t, err)
-	defer os.Remove(tmpFile.Name())
-
-	content := []byte(`
-gas_adjustment = 1.5
-
-[server]
-listen_addr = "0.0.0.0:99999"
-read_timeout = "20s"
-verbose_cors = true
-write_timeout = "20s"
-
 [[currency_pairs]]
 base = "ATOM"
 quote = "USDT"
 providers = [
 	"kraken",
 	"binance",
-	"huobi"
 ]
 
 [[currency_pairs]]
 base = "OJO"
 quote = "USDT"
 providers = [
-	"kraken",
 	"binance",
-	"huobi"
 ]
-
+`
+	providerConfig := writeConfig("providers.toml", `
 [[currency_pairs]]
-base = "USDT"
-quote = "USD"
+base = "ATOM"
+quote = "USDT"
 providers = [
-	"kraken",
 	"binance",
-	"huobi"
+	"huobi",
 ]
 
 [[currency_pairs]]
-base = "EUR"
+base = "OSMO"
+quote = "USDT"
 providers = [
-  "polygon",
+	"huobi",
 ]
-quote = "USD"
+`)
 
-[account]
-address = "ojo15nejfgcaanqpw25ru4arvfd0fwy6j8clccvwx4"
-validator = "ojovalcons14rjlkfzp56733j5l5nfk6fphjxymgf8mj04d5p"
-chain_id = "ojo-local-testnet"
+	errorConfig := writeConfig("error.toml", fmt.Sprintf(nodeConfig, config.DuplicatePairsError))
+	_, err := config.ParseConfigs([]string{errorConfig, providerConfig})
+	require.ErrorContains(t, err, "currency pair ATOM/USDT is defined more than once")
 
-[keyring]
-backend = "test"
-dir = "/Users/username/.ojo"
-pass = "keyringPassword"
+	mergeConfig := writeConfig("merge.toml", fmt.Sprintf(nodeConfig, config.DuplicatePairsMerge))
+	cfg, err := config.ParseConfigs([]string{mergeConfig, providerConfig})
+	require.NoError(t, err)
+	require.Equal(t, []config.CurrencyPair{
+		{
+			Base:      "ATOM",
+			Quote:     "USDT",
+			Providers: []types.ProviderName{provider.ProviderKraken, provider.ProviderBinance, provider.ProviderHuobi},
+		},
+		{Base: "OJO", Quote: "USDT", Providers: []types.ProviderName{provider.ProviderBinance}},
+		{Base: "OSMO", Quote: "USDT", Providers: []types.ProviderName{provider.ProviderHuobi}},
+	}, cfg.CurrencyPairs)
+}
 
-[rpc]
-tmrpc_endpoint = "http://localhost:26657"
-grpc_endpoint = "localhost:9090"
-rpc_timeout = "100ms"
+func TestProviderPairs_PairAddress(t *testing.T) {
+	cfg := config.Config{
+		CurrencyPairs: []config.CurrencyPair{
+			{
+				Base:      "INJ",
+				Quote:     "USDT",
+				Providers: []types.ProviderName{provider.ProviderBinance, provider.ProviderInjective},
+				PairAddress: []config.PairAddressProvider{
+					{
+						Address:  "0xa508cb32923323679f29a032c70342c147c17d0145625922b0ef22e955c844c0",
+						Provider: provider.ProviderInjective,
+					},
+				},
+			},
+		},
+	}
 
-[telemetry]
-enabled = false
+	providerPairs := cfg.ProviderPairs()
+	require.Equal(t, []types.CurrencyPair{{Base: "INJ", Quote: "USDT"}}, providerPairs[provider.ProviderBinance])
+	require.Equal(t, []types.CurrencyPair{{
+		Base:    "INJ",
+		Quote:   "USDT",
+		Address: "0xa508cb32923323679f29a032c70342c147c17d0145625922b0ef22e955c844c0",
+	}}, providerPairs[provider.ProviderInjective])
+}
+
+func TestConfig_VotePriorities(t *testing.T) {
+	cfg := config.Config{
+		CurrencyPairs: []config.CurrencyPair{
+			{Base: "ATOM", Quote: "USDT"},
+			{Base: "ATOM", Quote: "USD", VotePriority: 8},
+			{Base: "OSMO", Quote: "USDT"},
+		},
+	}
+	require.Equal(t, map[string]int{"ATOM": 8, "OSMO": config.DefaultVotePriority}, cfg.VotePriorities())
+}
+
+func TestConfig_VoteExponents(t *testing.T) {
+	cfg := config.Config{
+		CurrencyPairs: []config.CurrencyPair{
+			{Base: "ATOM", Quote: "USDT"},
+			{Base: "ATOM", Quote: "USD", VoteExponent: 6},
+			{Base: "OSMO", Quote: "USDT"},
+		},
+	}
+	require.Equal(t, map[string]int{"ATOM": 6}, cfg.VoteExponents())
+}
+
+func TestConfig_VoteRoundings(t *testing.T) {
+	cfg := config.Config{
+		CurrencyPairs: []config.CurrencyPair{
+			{Base: "ATOM", Quote: "USDT"},
+			{Base: "ATOM", Quote: "USD"},
+			{Base: "OSMO", Quote: "USDT", VoteRounding: string(types.RoundHalfEven)},
+			{Base: "USDC", Quote: "USD", VoteRounding: string(types.RoundDown), VoteDecimals: 4},
+		},
+	}
+	require.Equal(t, map[string]types.VoteRounding{
+		"OSMO": {Decimals: 0, Mode: types.RoundHalfEven},
+		"USDC": {Decimals: 4, Mode: types.RoundDown},
+	}, cfg.VoteRoundings())
+}
+
+func TestConfig_SpotOnlyBases(t *testing.T) {
+	cfg := config.Config{
+		CurrencyPairs: []config.CurrencyPair{
+			{Base: "ATOM", Quote: "USDT"},
+			{Base: "PEPE", Quote: "USDT", SpotOnly: true},
+			{Base: "PEPE", Quote: "USD", SpotOnly: true},
+		},
+	}
+	require.Equal(t, map[string]struct{}{"PEPE": {}}, cfg.SpotOnlyBases())
+}
+
+func TestConfig_RequiredProviders(t *testing.T) {
+	cfg := config.Config{
+		CurrencyPairs: []config.CurrencyPair{
+			{
+				Base:              "ATOM",
+				Quote:             "USDT",
+				Providers:         []types.ProviderName{provider.ProviderKraken, provider.ProviderBinance},
+				RequiredProviders: []types.ProviderName{provider.ProviderKraken},
+			},
+			{Base: "OSMO", Quote: "USDT", Providers: []types.ProviderName{provider.ProviderKraken}},
+		},
+	}
+	require.Equal(t, map[types.CurrencyPair][]types.ProviderName{
+		{Base: "ATOM", Quote: "USDT"}: {provider.ProviderKraken},
+	}, cfg.RequiredProviders())
+}
+
+func TestConfig_ProviderGroups(t *testing.T) {
+	cfg := config.Config{
+		CurrencyPairs: []config.CurrencyPair{
+			{
+				Base:              "ATOM",
+				Quote:             "USDT",
+				Providers:         []types.ProviderName{provider.ProviderKraken, provider.ProviderBinance},
+				MinProviderGroups: 2,
+			},
+			{Base: "OSMO", Quote: "USDT", Providers: []types.ProviderName{provider.ProviderKraken}},
+		},
+		ProviderGroups: []config.ProviderGroup{{
+			Name:      "binance",
+			Providers: []types.ProviderName{provider.ProviderBinance, provider.ProviderBinanceUS},
+		}},
+	}
+	require.Equal(t, types.ProviderGroups{
+		provider.ProviderBinance:   "binance",
+		provider.ProviderBinanceUS: "binance",
+	}, cfg.ProviderGroupsMap())
+	require.Equal(t, map[types.CurrencyPair]int{
+		{Base: "ATOM", Quote: "USDT"}: 2,
+	}, cfg.MinProviderGroups())
+}
+
+func TestConfig_TvwapWeightingsMap(t *testing.T) {
+	cfg := config.Config{
+		TvwapWeightings: []config.TvwapWeighting{
+			{Base: "ATOM", Mode: "linear", Combination: "weighted", ProviderWeights: map[string]string{"kraken": "3"}},
+			{Base: "OJO", Mode: "uniform"},
+		},
+	}
+	tvwapWeightings, err := cfg.TvwapWeightingsMap()
+	require.NoError(t, err)
+	require.Equal(t, map[string]types.TvwapWeighting{
+		"ATOM": {
+			Mode:            types.TvwapWeightingLinear,
+			Combination:     types.TvwapCombinationWeighted,
+			ProviderWeights: map[types.ProviderName]sdk.Dec{provider.ProviderKraken: sdk.NewDec(3)},
+		},
+		// the providers are combined by volume by default
+		"OJO": {Mode: types.TvwapWeightingUniform, Combination: types.TvwapCombinationVolume},
+	}, tvwapWeightings)
+}
+
+func TestConfig_SpotSources(t *testing.T) {
+	cfg := config.Config{
+		CurrencyPairs: []config.CurrencyPair{
+			{
+				Base:                "ATOM",
+				Quote:               "USDT",
+				Providers:           []types.ProviderName{provider.ProviderKraken, provider.ProviderBinance},
+				CandleSpotProviders: []types.ProviderName{provider.ProviderKraken},
+			},
+			{Base: "OSMO", Quote: "USDT", Providers: []types.ProviderName{provider.ProviderKraken}},
+		},
+	}
+	spotSources := cfg.SpotSources()
+	require.Equal(t, types.SpotSources{
+		provider.ProviderKraken: {
+			{Base: "ATOM", Quote: "USDT"}: types.SpotSourceCandle,
+			{Base: "ATOM", Quote: "USD"}:  types.SpotSourceCandle,
+		},
+	}, spotSources)
+
+	// providers and pairs without a spot source use their ticker
+	require.Equal(
+		t,
+		types.SpotSourceTicker,
+		spotSources.Source(provider.ProviderBinance, types.CurrencyPair{Base: "ATOM", Quote: "USDT"}),
+	)
+	require.Equal(
+		t,
+		types.SpotSourceTicker,
+		spotSources.Source(provider.ProviderKraken, types.CurrencyPair{Base: "OSMO", Quote: "USDT"}),
+	)
+}
+
+func TestConfig_MaxProviders(t *testing.T) {
+	cfg := config.Config{
+		CurrencyPairs: []config.CurrencyPair{
+			{Base: "ATOM", Quote: "USDT", MaxProviders: 3},
+			{Base: "OSMO", Quote: "USDT"},
+		},
+	}
+	require.Equal(t, map[types.CurrencyPair]int{{Base: "ATOM", Quote: "USDT"}: 3}, cfg.MaxProviders())
+}
+
+func TestConfig_MaxVoteChanges(t *testing.T) {
+	cfg := config.Config{
+		CurrencyPairs: []config.CurrencyPair{
+			{Base: "ATOM", Quote: "USDT", MaxVoteChange: "0.05"},
+			{Base: "ATOM", Quote: "USD", MaxVoteChange: "0.05"},
+			{Base: "OSMO", Quote: "USDT"},
+		},
+	}
+	maxChanges, err := cfg.MaxVoteChanges()
+	require.NoError(t, err)
+	require.Equal(t, map[string]sdk.Dec{"ATOM": sdk.MustNewDecFromStr("0.05")}, maxChanges)
+}
+
+func TestSupportedQuotes(t *testing.T) {
+	quotes := config.SupportedQuotes()
+	require.IsIncreasing(t, quotes)
+	require.Contains(t, quotes, config.DenomUSD)
+	for pair := range config.SupportedConversions {
+		require.Contains(t, quotes, pair.Base)
+	}
+	require.NotContains(t, quotes, "EUR")
+}
+
+func TestConfig_LatencyThresholdsSlice(t *testing.T) {
+	cfg := config.Config{
+		LatencyThresholds: []config.LatencyThreshold{
+			{Latency: "2m", Weight: "0"},
+			{Latency: "30s", Weight: "0.5"},
+		},
+	}
+
+	thresholds, err := cfg.LatencyThresholdsSlice()
+	require.NoError(t, err)
+	require.Equal(t, []types.LatencyThreshold{
+		{Latency: 30 * time.Second, Weight: sdk.MustNewDecFromStr("0.5")},
+		{Latency: 2 * time.Minute, Weight: sdk.ZeroDec()},
+	}, thresholds)
+}
+
+func TestConfig_TickerWindowsMap(t *testing.T) {
+	cfg := config.Config{
+		TickerWindows: []config.TickerWindow{
+			{Provider: provider.ProviderCoinbase, Base: "ATOM", Quote: "USD", Samples: 5},
+			{Provider: provider.ProviderCoinbase, Base: "OSMO", Quote: "USD", Samples: 3},
+		},
+	}
+	require.Equal(t, types.TickerWindows{
+		provider.ProviderCoinbase: {
+			{Base: "ATOM", Quote: "USD"}: 5,
+			{Base: "OSMO", Quote: "USD"}: 3,
+		},
+	}, cfg.TickerWindowsMap())
+}
+
+func TestProviderPairs_Chainlink(t *testing.T) {
+	cfg := config.Config{
+		CurrencyPairs: []config.CurrencyPair{
+			{
+				Base:      "ETH",
+				Quote:     "USD",
+				Providers: []types.ProviderName{provider.ProviderCoinbase, provider.ProviderChainlink},
+			},
+			{
+				Base:      "LINK",
+				Quote:     "USD",
+				Providers: []types.ProviderName{provider.ProviderChainlink},
+			},
+		},
+		Chainlink: config.Chainlink{
+			RPC:         "https://ethereum-rpc.publicnode.com",
+			MaxRoundAge: "1h",
+			Feeds: []config.ChainlinkFeed{
+				{Base: "ETH", Quote: "USD", Address: "0x5f4eC3Df9cbd43714FE2740f5E3616155c5b8419"},
+				{Base: "LINK", Quote: "USD", Address: "0x2c1d072e956AFFC0D435Cb7AC38EF18d24d9127c", MaxRoundAge: "24h"},
+			},
+		},
+	}
+
+	providerPairs := cfg.ProviderPairs()
+	require.Equal(t, []types.CurrencyPair{{Base: "ETH", Quote: "USD"}}, providerPairs[provider.ProviderCoinbase])
+	require.Equal(t, []types.CurrencyPair{
+		{Base: "ETH", Quote: "USD", Address: "0x5f4eC3Df9cbd43714FE2740f5E3616155c5b8419"},
+		{Base: "LINK", Quote: "USD", Address: "0x2c1d072e956AFFC0D435Cb7AC38EF18d24d9127c"},
+	}, providerPairs[provider.ProviderChainlink])
+
+	endpoint := cfg.ProviderEndpointsMap()[provider.ProviderChainlink]
+	require.Equal(t, provider.ProviderChainlink, endpoint.Name)
+	require.Equal(t, "https://ethereum-rpc.publicnode.com", endpoint.Rest)
+	require.Equal(t, map[string]time.Duration{"ETHUSD": time.Hour, "LINKUSD": 24 * time.Hour}, endpoint.MaxRoundAges)
+}
+
+func TestProviderEndpointsMap_OsmosisTwap(t *testing.T) {
+	cfg := config.Config{
+		OsmosisTwap: config.OsmosisTwap{
+			LCD:    "https://lcd.osmosis.zone",
+			Window: "30m",
+			Pools: []config.OsmosisTwapPool{
+				{Base: "ATOM", Quote: "OSMO", PoolID: 1, BaseDenom: "ibc/27394FB0", QuoteDenom: "uosmo"},
+				{
+					Base:          "ETH",
+					Quote:         "USDC",
+					PoolID:        1464,
+					BaseDenom:     "ibc/EA1D43981D",
+					QuoteDenom:    "ibc/498A0751C7",
+					BaseDecimals:  18,
+					QuoteDecimals: 6,
+					Window:        "2h",
+				},
+			},
+		},
+	}
 
-[[provider_endpoints]]
-name = "polygon"
-rest = "https://api.polygon.io/v2/"
-websocket = "wss://socket.polygon.io/forex"
-apikey = "test"
-`)
-	_, err = tmpFile.Write(content)
-	require.NoError(t, err)
+	endpoint := cfg.ProviderEndpointsMap()[provider.ProviderOsmosisTwap]
+	require.Equal(t, provider.ProviderOsmosisTwap, endpoint.Name)
+	require.Equal(t, "https://lcd.osmosis.zone", endpoint.Rest)
+	require.Equal(t, map[string]provider.TwapPool{
+		"ATOMOSMO": {PoolID: 1, BaseDenom: "ibc/27394FB0", QuoteDenom: "uosmo", Window: 30 * time.Minute},
+		"ETHUSDC": {
+			PoolID:        1464,
+			BaseDenom:     "ibc/EA1D43981D",
+			QuoteDenom:    "ibc/498A0751C7",
+			BaseDecimals:  18,
+			QuoteDecimals: 6,
+			Window:        2 * time.Hour,
+		},
+	}, endpoint.TwapPools)
+}
 
-	cfg, err := config.ParseConfig(tmpFile.Name())
-	require.NoError(t, err)
+func TestProviderEndpointsMap_UniswapV3(t *testing.T) {
+	cfg := config.Config{
+		UniswapV3: config.UniswapV3{
+			RPC:        "https://ethereum-rpc.publicnode.com",
+			TwapWindow: "30m",
+			Pools: []config.UniswapV3Pool{
+				{
+					Base:      "WETH",
+					Quote:     "USDC",
+					Address:   "0x88e6A0c2dDD26FEEb64F039a2c41296FcB3f5640",
+					BaseToken: "0xC02aaA39b223FE8D0A0e5C4F27eAD9083C756Cc2",
+					FeeTier:   500,
+				},
+				{
+					Base:       "UNI",
+					Quote:      "WETH",
+					Address:    "0x1d42064Fc4Beb5F8aAF85F4617AE8b3b5B8Bd801",
+					BaseToken:  "0x1f9840a85d5aF5bf1D1762F925BDADdC4201F984",
+					FeeTier:    3000,
+					TwapWindow: "1h",
+				},
+			},
+		},
+	}
 
-	// Forex currency should allow 1 provider minumum
-	logger := zerolog.New(zerolog.ConsoleWriter{Out: os.Stderr}).Level(zerolog.InfoLevel).With().Timestamp().Logger()
-	err = config.CheckProviderMins(context.TODO(), logger, cfg)
-	require.NoError(t, err)
+	endpoint := cfg.ProviderEndpointsMap()[provider.ProviderUniswapV3]
+	require.Equal(t, provider.ProviderUniswapV3, endpoint.Name)
+	require.Equal(t, "https://ethereum-rpc.publicnode.com", endpoint.Rest)
+	require.Equal(t, map[string]provider.UniswapV3Pool{
+		"WETHUSDC": {
+			Address:    "0x88e6A0c2dDD26FEEb64F039a2c41296FcB3f5640",
+			BaseToken:  "0xC02aaA39b223FE8D0A0e5C4F27eAD9083C756Cc2",
+			FeeTier:    500,
+			TwapWindow: 30 * time.Minute,
+		},
+		"UNIWETH": {
+			Address:    "0x1d42064Fc4Beb5F8aAF85F4617AE8b3b5B8Bd801",
+			BaseToken:  "0x1f9840a85d5aF5bf1D1762F925BDADdC4201F984",
+			FeeTier:    3000,
+			TwapWindow: time.Hour,
+		},
+	}, endpoint.UniswapV3Pools)
 }
 
-func TestProviderWithAPIKey_Invalid(t *testing.T) {
-	tmpFile, err := ioutil.TempFile("", "price-feeder*.toml")
-	require.NoError(t, err)
-	defer os.Remove(tmpFile.Name())
+func TestProviderEndpointsMap_Curve(t *testing.T) {
+	cfg := config.Config{
+		Curve: config.Curve{
+			RPC: "https://ethereum-rpc.publicnode.com",
+			Pools: []config.CurvePool{
+				{
+					Base:       "USDC",
+					Quote:      "USDT",
+					Address:    "0xbEbc44782C7dB0a1A60Cb6fe97d0b483032FF1C7",
+					BaseIndex:  1,
+					QuoteIndex: 2,
+				},
+				{
+					Base:       "STETH",
+					Quote:      "ETH",
+					Address:    "0x21E27a5E5513D6e65C4f830167390997aA84843a",
+					BaseIndex:  1,
+					QuoteIndex: 0,
+				},
+			},
+		},
+	}
 
-	content := []byte(`
-gas_adjustment = 1.5
+	endpoint := cfg.ProviderEndpointsMap()[provider.ProviderCurve]
+	require.Equal(t, provider.ProviderCurve, endpoint.Name)
+	require.Equal(t, "https://ethereum-rpc.publicnode.com", endpoint.Rest)
+	require.Equal(t, map[string]provider.CurvePool{
+		"USDCUSDT": {Address: "0xbEbc44782C7dB0a1A60Cb6fe97d0b483032FF1C7", BaseIndex: 1, QuoteIndex: 2},
+		"STETHETH": {Address: "0x21E27a5E5513D6e65C4f830167390997aA84843a", BaseIndex: 1, QuoteIndex: 0},
+	}, endpoint.CurvePools)
+}
 
-[server]
-listen_addr = "0.0.0.0:99999"
-read_timeout = "20s"
-verbose_cors = true
-write_timeout = "20s"
+func TestProviderEndpointsMap_Dydx(t *testing.T) {
+	cfg := config.Config{
+		Dydx: config.Dydx{
+			PollInterval: "5s",
+			Markets: []config.DydxMarket{
+				{Base: "BTC", Quote: "USD", Market: "BTC-USD"},
+				{Base: "ETH", Quote: "USD", Market: "ETH-USD"},
+			},
+		},
+	}
 
-[[currency_pairs]]
-base = "ATOM"
-quote = "USDT"
-providers = [
-	"kraken",
-	"binance",
-	"huobi"
-]
+	// the public indexer is used by default
+	endpoint := cfg.ProviderEndpointsMap()[provider.ProviderDydx]
+	require.Equal(t, provider.ProviderDydx, endpoint.Name)
+	require.Equal(t, "https://indexer.dydx.trade", endpoint.Rest)
+	require.Equal(t, 5*time.Second, endpoint.PollInterval)
+	require.Equal(t, map[string]string{"BTCUSD": "BTC-USD", "ETHUSD": "ETH-USD"}, endpoint.DydxMarkets)
 
-[[currency_pairs]]
-base = "OJO"
-quote = "USDT"
-providers = [
-	"kraken",
-	"binance",
-	"huobi"
-]
+	cfg.Dydx.Indexer = "https://indexer.example.com"
+	require.Equal(t, "https://indexer.example.com", cfg.ProviderEndpointsMap()[provider.ProviderDydx].Rest)
+}
 
-[[currency_pairs]]
-base = "USDT"
-quote = "USD"
-providers = [
-	"kraken",
-	"binance",
-	"huobi"
-]
+func TestProviderEndpointsMap_RedemptionRate(t *testing.T) {
+	cfg := config.Config{
+		RedemptionRate: config.RedemptionRate{
+			PollInterval: "30s",
+			MaxAge:       "5m",
+			Assets: []config.RedemptionRateAsset{
+				{
+					Base:       "STATOM",
+					Underlying: "ATOM",
+					Rest:       "https://stride-api.polkachu.com",
+					Path:       "/Stride-Labs/stride/stakeibc/host_zone/cosmoshub-4",
+					Field:      "host_zone.redemption_rate",
+				},
+			},
+		},
+	}
 
-[[currency_pairs]]
-base = "EUR"
-providers = [
-  "polygon",
-]
-quote = "USD"
+	endpoint := cfg.ProviderEndpointsMap()[provider.ProviderRedemptionRate]
+	require.Equal(t, provider.ProviderRedemptionRate, endpoint.Name)
+	require.Equal(t, 30*time.Second, endpoint.PollInterval)
+	require.Equal(t, map[string]provider.RedemptionRateQuery{
+		"STATOMATOM": {
+			Rest:   "https://stride-api.polkachu.com",
+			Path:   "/Stride-Labs/stride/stakeibc/host_zone/cosmoshub-4",
+			Field:  "host_zone.redemption_rate",
+			MaxAge: 5 * time.Minute,
+		},
+	}, endpoint.RedemptionRates)
+	require.Equal(t, map[string]string{"STATOM": "ATOM"}, cfg.RedemptionRateUnderlyings())
+}
 
-[account]
-address = "ojo15nejfgcaanqpw25ru4arvfd0fwy6j8clccvwx4"
-validator = "ojovalcons14rjlkfzp56733j5l5nfk6fphjxymgf8mj04d5p"
-chain_id = "ojo-local-testnet"
+func TestAutoCandleInterval(t *testing.T) {
+	binance := config.SupportedCandleIntervals[provider.ProviderBinance]
+	kraken := config.SupportedCandleIntervals[provider.ProviderKraken]
 
-[keyring]
-backend = "test"
-dir = "/Users/username/.ojo"
-pass = "keyringPassword"
+	testCases := []struct {
+		window    time.Duration
+		supported []string
+		expected  string
+	}{
+		{time.Minute, binance, "1m"},
+		{5 * time.Minute, binance, "1m"},
+		{15 * time.Minute, binance, "3m"},
+		{15 * time.Minute, kraken, "1m"},
+		{30 * time.Minute, binance, "5m"},
+		{time.Hour, binance, "5m"},
+		{2 * time.Hour, binance, "15m"},
+		{2 * time.Hour, kraken, "15m"},
+		{6 * time.Hour, binance, "1h"},
+		{24 * time.Hour, kraken, "1h"},
+	}
 
-[rpc]
-tmrpc_endpoint = "http://localhost:26657"
-grpc_endpoint = "localhost:9090"
-rpc_timeout = "100ms"
+	for _, tc := range testCases {
+		require.Equal(t, tc.expected, config.AutoCandleInterval(tc.window, tc.supported), tc.window)
+	}
+}
 
-[telemetry]
-enabled = false
+func TestProviderEndpointsMap_TwapWindows(t *testing.T) {
+	cfg := config.Config{
+		CurrencyPairs: []config.CurrencyPair{
+			{
+				Base:       "ATOM",
+				Quote:      "USDT",
+				Providers:  []types.ProviderName{provider.ProviderBinance, provider.ProviderKraken, provider.ProviderOkx},
+				TwapWindow: "1h",
+			},
+			{
+				Base:      "OJO",
+				Quote:     "USDT",
+				Providers: []types.ProviderName{provider.ProviderBinance},
+			},
+		},
+	}
 
-[[provider_endpoints]]
-name = "polygon"
-rest = "https://api.polygon.io/v2/"
-websocket = "wss://socket.polygon.io/forex"
-`)
-	_, err = tmpFile.Write(content)
+	endpoints := cfg.ProviderEndpointsMap()
+	require.Equal(t, []string{"1m", "5m"}, endpoints[provider.ProviderBinance].CandleIntervals)
+	require.Equal(t, []string{"5m"}, endpoints[provider.ProviderKraken].CandleIntervals)
+	require.NotContains(t, endpoints, provider.ProviderOkx)
+
+	weightings, err := cfg.TvwapWeightingsMap()
 	require.NoError(t, err)
+	require.Equal(t, time.Hour, weightings["ATOM"].Window)
+	require.NotContains(t, weightings, "OJO")
 
-	_, err = config.ParseConfig(tmpFile.Name())
-	require.EqualError(t, err, "provider polygon requires an API Key")
+	// candle intervals set on the endpoint are kept
+	cfg.ProviderEndpoints = []provider.Endpoint{
+		{Name: provider.ProviderKraken, CandleIntervals: []string{"15m"}},
+	}
+	require.Equal(t, []string{"15m"}, cfg.ProviderEndpointsMap()[provider.ProviderKraken].CandleIntervals)
 }
 
-func TestInvalidCurrencyPairs(t *testing.T) {
+func TestParseConfig_DefaultProviders(t *testing.T) {
 	tmpFile, err := ioutil.TempFile("", "price-feeder*.toml")
 	require.NoError(t, err)
 	defer os.Remove(tmpFile.Name())
 
 	content := []byte(`
 gas_adjustment = 1.5
+default_providers = [
+	"kraken",
+	"binance",
+	"huobi"
+]
 
 [server]
 listen_addr = "0.0.0.0:99999"
@@ -945,28 +4708,13 @@ write_timeout = "20s"
 [[currency_pairs]]
 base = "ATOM"
 quote = "USDT"
-providers = [
-	"kraken",
-	"binance",
-	"huobi"
-]
 
 [[currency_pairs]]
 base = "OJO"
 quote = "USDT"
 providers = [
-	"kraken",
-	"binance",
-	"huobi"
-]
-
-[[currency_pairs]]
-base = "stUMEE"
-quote = "BAD_QUOTE"
-providers = [
-	"kraken",
-	"binance",
-	"huobi"
+	"okx",
+	"gate"
 ]
 
 [account]
@@ -990,17 +4738,25 @@ enabled = false
 	_, err = tmpFile.Write(content)
 	require.NoError(t, err)
 
-	_, err = config.ParseConfig(tmpFile.Name())
-	require.Error(t, err, "currency pair quote UMEE is not supported")
-}
-
-func TestValidCurrencyPairs(t *testing.T) {
-	tmpFile, err := ioutil.TempFile("", "price-feeder*.toml")
+	cfg, err := config.ParseConfig(tmpFile.Name())
 	require.NoError(t, err)
-	defer os.Remove(tmpFile.Name())
 
-	content := []byte(`
+	require.Len(t, cfg.CurrencyPairs, 2)
+	require.Equal(t, []types.ProviderName{
+		provider.ProviderKraken,
+		provider.ProviderBinance,
+		provider.ProviderHuobi,
+	}, cfg.CurrencyPairs[0].Providers)
+	require.Equal(t, []types.ProviderName{
+		provider.ProviderOkx,
+		provider.ProviderGate,
+	}, cfg.CurrencyPairs[1].Providers)
+}
+
+func TestParseConfig_AllowedQuotes(t *testing.T) {
+	content := `
 gas_adjustment = 1.5
+allowed_quotes = ["USDT"]
 
 [server]
 listen_addr = "0.0.0.0:99999"
@@ -1011,30 +4767,13 @@ write_timeout = "20s"
 [[currency_pairs]]
 base = "ATOM"
 quote = "USDT"
-providers = [
-	"kraken",
-	"binance",
-	"huobi"
-]
-
-[[currency_pairs]]
-base = "OJO"
-quote = "USDT"
-providers = [
-	"kraken",
-	"binance",
-	"huobi"
-]
+providers = ["kraken"]
 
 [[currency_pairs]]
-base = "stOSMO"
-quote = "OSMO"
-providers = [
-	"kraken",
-	"binance",
-	"huobi"
-]
-
+base = "USDT"
+quote = "USD"
+providers = ["kraken"]
+%s
 [account]
 address = "ojo15nejfgcaanqpw25ru4arvfd0fwy6j8clccvwx4"
 validator = "ojovalcons14rjlkfzp56733j5l5nfk6fphjxymgf8mj04d5p"
@@ -1052,84 +4791,144 @@ rpc_timeout = "100ms"
 
 [telemetry]
 enabled = false
-`)
-	_, err = tmpFile.Write(content)
-	require.NoError(t, err)
+`
+	parse := func(extraPairs string) (config.Config, error) {
+		tmpFile, err := ioutil.TempFile("", "price-feeder*.toml")
+		require.NoError(t, err)
+		defer os.Remove(tmpFile.Name())
+
+		_, err = tmpFile.Write([]byte(fmt.Sprintf(content, extraPairs)))
+		require.NoError(t, err)
+		return config.ParseConfig(tmpFile.Name())
+	}
 
-	_, err = config.ParseConfig(tmpFile.Name())
+	cfg, err := parse("")
 	require.NoError(t, err)
+	require.Equal(t, []string{"USDT"}, cfg.AllowedQuotes)
+
+	// USDC is a supported quote, but not one of the allowed quotes
+	_, err = parse(`
+[[currency_pairs]]
+base = "ATOM"
+quote = "USDC"
+providers = ["kraken"]
+`)
+	require.ErrorContains(t, err, "currency pair quote USDC is not one of the allowed quotes")
 }
 
-func TestMultipleConfigs(t *testing.T) {
-	tmpFile, err := ioutil.TempFile("", "price-feeder*.toml")
-	require.NoError(t, err)
-	defer os.Remove(tmpFile.Name())
+func TestCheckProviderMins_DefaultProviders(t *testing.T) {
+	cfg := config.Config{
+		DefaultProviders: []types.ProviderName{
+			provider.ProviderKraken,
+			provider.ProviderBinance,
+			provider.ProviderHuobi,
+		},
+		CurrencyPairs: []config.CurrencyPair{
+			{Base: "ATOM", Quote: "USDT"},
+		},
+	}
 
-	content := []byte(`
-gas_adjustment = 1.5
+	logger := zerolog.New(zerolog.ConsoleWriter{Out: os.Stderr}).Level(zerolog.InfoLevel).With().Timestamp().Logger()
+	err := config.CheckProviderMins(context.TODO(), logger, cfg)
+	require.NoError(t, err)
 
-[server]
-listen_addr = "0.0.0.0:99999"
-read_timeout = "20s"
-verbose_cors = true
-write_timeout = "20s"
+	// a per-pair list overrides the default providers
+	cfg.CurrencyPairs[0].Providers = []types.ProviderName{provider.ProviderKraken}
+	err = config.CheckProviderMins(context.TODO(), logger, cfg)
+	require.Error(t, err)
+}
 
-[account]
-address = "ojo15nejfgcaanqpw25ru4arvfd0fwy6j8clccvwx4"
-validator = "ojovalcons14rjlkfzp56733j5l5nfk6fphjxymgf8mj04d5p"
-chain_id = "ojo-local-testnet"
+// writeTestCertificate writes a self-signed certificate valid for localhost
+// server and client authentication, and its key, to the directory.
+func writeTestCertificate(t *testing.T, dir string) (certFile, keyFile string) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
 
-[keyring]
-backend = "test"
-dir = "/Users/username/.ojo"
-pass = "keyringPassword"
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "price-feeder"},
+		DNSNames:              []string{"localhost"},
+		IPAddresses:           []net.IP{net.ParseIP("127.0.0.1")},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		KeyUsage:              x509.KeyUsageDigitalSignature | x509.KeyUsageCertSign,
+		ExtKeyUsage:           []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth, x509.ExtKeyUsageClientAuth},
+		BasicConstraintsValid: true,
+		IsCA:                  true,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	require.NoError(t, err)
+	keyDer, err := x509.MarshalECPrivateKey(key)
+	require.NoError(t, err)
 
-[rpc]
-tmrpc_endpoint = "http://localhost:26657"
-grpc_endpoint = "localhost:9090"
-rpc_timeout = "100ms"
+	certFile = filepath.Join(dir, "server.crt")
+	keyFile = filepath.Join(dir, "server.key")
+	require.NoError(t, os.WriteFile(certFile, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}), 0o600))
+	require.NoError(t, os.WriteFile(keyFile, pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDer}), 0o600))
+	return certFile, keyFile
+}
 
-[telemetry]
-enabled = false
-`)
-	_, err = tmpFile.Write(content)
+func TestServer_TLSConfig(t *testing.T) {
+	tlsConfig, err := config.Server{}.TLSConfig()
 	require.NoError(t, err)
+	require.Nil(t, tlsConfig)
 
-	tmpFile2, err := ioutil.TempFile("", "provider-config*.toml")
-	require.NoError(t, err)
-	defer os.Remove(tmpFile2.Name())
+	dir := t.TempDir()
+	certFile, keyFile := writeTestCertificate(t, dir)
 
-	content2 := []byte(`
-[[currency_pairs]]
-base = "ATOM"
-quote = "USDT"
-providers = [
-	"kraken",
-	"binance",
-	"huobi"
-]
+	_, err = config.Server{TLSCertFile: certFile, TLSKeyFile: filepath.Join(dir, "missing.key")}.TLSConfig()
+	require.Error(t, err)
 
-[[currency_pairs]]
-base = "OJO"
-quote = "USDT"
-providers = [
-	"kraken",
-	"binance",
-	"huobi"
-]
+	invalidCA := filepath.Join(dir, "invalid.crt")
+	require.NoError(t, os.WriteFile(invalidCA, []byte("not a certificate"), 0o600))
+	_, err = config.Server{TLSCertFile: certFile, TLSKeyFile: keyFile, TLSClientCAFile: invalidCA}.TLSConfig()
+	require.Error(t, err)
 
-[[currency_pairs]]
-base = "stOSMO"
-quote = "OSMO"
-providers = [
-	"kraken",
-	"binance",
-	"huobi"
-]
-`)
-	_, err = tmpFile2.Write(content2)
+	certPool := x509.NewCertPool()
+	certPEM, err := os.ReadFile(certFile)
 	require.NoError(t, err)
-
-	_, err = config.ParseConfigs([]string{tmpFile.Name(), tmpFile2.Name()})
+	require.True(t, certPool.AppendCertsFromPEM(certPEM))
+	clientCert, err := tls.LoadX509KeyPair(certFile, keyFile)
 	require.NoError(t, err)
+
+	serve := func(t *testing.T, server config.Server) string {
+		tlsConfig, err := server.TLSConfig()
+		require.NoError(t, err)
+
+		srv := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+			_, _ = w.Write([]byte("ok"))
+		}))
+		srv.TLS = tlsConfig
+		srv.StartTLS()
+		t.Cleanup(srv.Close)
+		return srv.URL
+	}
+	client := func(certificates ...tls.Certificate) *http.Client {
+		return &http.Client{Transport: &http.Transport{TLSClientConfig: &tls.Config{
+			MinVersion:   tls.VersionTLS12,
+			RootCAs:      certPool,
+			Certificates: certificates,
+		}}}
+	}
+
+	t.Run("tls", func(t *testing.T) {
+		url := serve(t, config.Server{TLSCertFile: certFile, TLSKeyFile: keyFile})
+		resp, err := client().Get(url)
+		require.NoError(t, err)
+		resp.Body.Close()
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+	})
+
+	t.Run("mtls", func(t *testing.T) {
+		url := serve(t, config.Server{TLSCertFile: certFile, TLSKeyFile: keyFile, TLSClientCAFile: certFile})
+
+		// a client without a certificate is rejected
+		_, err := client().Get(url)
+		require.Error(t, err)
+
+		resp, err := client(clientCert).Get(url)
+		require.NoError(t, err)
+		resp.Body.Close()
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+	})
 }
@@ -276,13 +276,26 @@ func (t *CurrencyProviderTracker) trackCurrencyProviders(ctx context.Context) {
 // providers available for a currency by querying CoinGecko's API. It will enforce
 // a provider minimum for a given currency based on its available providers.
 func CheckProviderMins(ctx context.Context, logger zerolog.Logger, cfg Config) error {
+	_, err := ProviderMins(ctx, logger, cfg)
+	return err
+}
+
+// ProviderMins returns the minimum amount of providers of every configured
+// base, and fails if a base is configured with fewer providers than its
+// minimum. Bases priced by the mock provider have no minimum. No minimums are
+// returned if the currency provider tracker failed and the override flag is
+// set.
+func ProviderMins(ctx context.Context, logger zerolog.Logger, cfg Config) (map[string]int, error) {
+	cfg.CurrencyPairs = append([]CurrencyPair{}, cfg.CurrencyPairs...)
+	cfg.setDefaultProviders()
+
 	currencyProviderTracker, err := NewCurrencyProviderTracker(ctx, logger, cfg.CurrencyPairs...)
 	if err != nil {
 		logger.Error().Err(err).Msg("failed to start currency provider tracker")
 		// If currency tracker errors out and override flag is set, the price-feeder
 		// will run without enforcing provider minimums.
 		if cfg.ProviderMinOverride {
-			return nil
+			return nil, nil
 		}
 	}
 
@@ -296,26 +309,39 @@ func CheckProviderMins(ctx context.Context, logger zerolog.Logger, cfg Config) e
 		}
 	}
 
+	mins := make(map[string]int, len(pairs))
 	for base, providers := range pairs {
 		var minProviders int
 		_, isForexBase := SupportedForexCurrencies[base]
 		_, isUniBase := SupportedUniswapCurrencies[base]
 
-		// If currency provider tracker errored, default to three providers as
-		// the minimum.
+		// Forex currencies are not tracked by CoinGecko, so their rates only
+		// require a single provider. If currency provider tracker errored,
+		// default to three providers as the minimum.
 		switch {
+		case isForexBase:
+			minProviders = 1
+			if len(providers) == 1 {
+				logger.Warn().
+					Str("currency", base).
+					Msg("forex rate has a single provider and goes stale if it fails")
+			}
 		case currencyProviderTracker != nil:
 			minProviders = currencyProviderTracker.CurrencyProviderMin[base]
-		case isForexBase || isUniBase:
+		case isUniBase:
 			minProviders = 1
 		default:
 			minProviders = 3
 		}
 
-		if _, ok := pairs[base][provider.ProviderMock]; !ok && len(providers) < minProviders {
-			return fmt.Errorf("must have at least %d providers for %s", minProviders, base)
+		if _, ok := pairs[base][provider.ProviderMock]; ok {
+			continue
+		}
+		if len(providers) < minProviders {
+			return nil, fmt.Errorf("must have at least %d providers for %s", minProviders, base)
 		}
+		mins[base] = minProviders
 	}
 
-	return nil
+	return mins, nil
 }
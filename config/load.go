@@ -6,7 +6,26 @@ import (
 	"path/filepath"
 	"strings"
 
+	"github.com/rs/zerolog"
 	"github.com/spf13/viper"
+
+	"github.com/ojo-network/price-feeder/oracle/types"
+)
+
+type (
+	// fileCurrencyPair is a currency pair along with the config file defining
+	// it.
+	fileCurrencyPair struct {
+		CurrencyPair
+		path string
+	}
+
+	// mergedPair records a currency pair merged from several definitions.
+	mergedPair struct {
+		pair      string
+		paths     []string
+		providers []types.ProviderName
+	}
 )
 
 // LoadConfigFromFlags attempts to read and parse configuration from the node config file path.
@@ -47,24 +66,30 @@ func filesInFolder(folder string) ([]string, error) {
 // parseConfigDir attempts to read the config_dir from the node config file.
 func parseConfigDir(nodeConfigPath string) (string, error) {
 	var cfg Config
-	viper.SetConfigFile(nodeConfigPath)
-	if err := viper.ReadInConfig(); err != nil {
-		return "", fmt.Errorf("failed to read node config: %w", err)
+	source, err := readConfigSource(nodeConfigPath)
+	if err != nil {
+		return "", err
 	}
-	if err := viper.Unmarshal(&cfg); err != nil {
+	v := viper.New()
+	if err := source.mergeInto(v); err != nil {
+		return "", err
+	}
+	if err := v.Unmarshal(&cfg); err != nil {
 		return "", fmt.Errorf("failed to decode node config: %w", err)
 	}
 	return cfg.ConfigDir, nil
 }
 
-// ParseConfig attempts to read and parse configuration from the given file path.
-// An error is returned if reading or parsing the config fails.
+// ParseConfig attempts to read and parse configuration from the given file path,
+// URL or consul or etcd key. An error is returned if reading or parsing the
+// config fails.
 func ParseConfig(configPath string) (Config, error) {
 	return ParseConfigs([]string{configPath})
 }
 
-// ParseConfigs attempts to read and parse configuration from the given file paths.
-// An error is returned if reading or parsing the configs fails.
+// ParseConfigs attempts to read and parse configuration from the given file paths,
+// URLs or consul or etcd keys. Environment variables override the values of
+// every config. An error is returned if reading or parsing the configs fails.
 func ParseConfigs(configPaths []string) (Config, error) {
 	var cfg Config
 
@@ -73,21 +98,178 @@ func ParseConfigs(configPaths []string) (Config, error) {
 	viper.SetEnvKeyReplacer(strings.NewReplacer(".", "_"))
 
 	// Loop over each config path and merge its values into the previous one
+	var filePairs []fileCurrencyPair
 	for _, configPath := range configPaths {
-		if configPath == "" {
-			return cfg, ErrEmptyConfigPath
+		source, err := readConfigSource(configPath)
+		if err != nil {
+			return cfg, err
 		}
-		viper.SetConfigFile(configPath)
-		if err := viper.MergeInConfig(); err != nil {
-			return cfg, fmt.Errorf("failed to read config: %w", err)
+		if err := source.mergeInto(viper.GetViper()); err != nil {
+			return cfg, err
 		}
+
+		pairs, err := readCurrencyPairs(source)
+		if err != nil {
+			return cfg, err
+		}
+		filePairs = append(filePairs, pairs...)
 	}
 
 	if err := viper.Unmarshal(&cfg); err != nil {
 		return cfg, fmt.Errorf("failed to decode config: %w", err)
 	}
 
+	// merging the configs replaces the currency pairs of a file with the pairs
+	// of the next one, so the pairs of every file are combined separately
+	if len(filePairs) > 0 {
+		if err := cfg.combineCurrencyPairs(filePairs); err != nil {
+			return cfg, err
+		}
+	}
+
 	cfg.setDefaults()
 
 	return cfg, cfg.Validate()
 }
+
+// readCurrencyPairs reads the currency pairs defined in a single config.
+func readCurrencyPairs(source configSource) ([]fileCurrencyPair, error) {
+	v := viper.New()
+	if err := source.mergeInto(v); err != nil {
+		return nil, err
+	}
+
+	var fileCfg struct {
+		CurrencyPairs []CurrencyPair `mapstructure:"currency_pairs"`
+	}
+	if err := v.Unmarshal(&fileCfg); err != nil {
+		return nil, fmt.Errorf("failed to decode config: %w", err)
+	}
+
+	pairs := make([]fileCurrencyPair, 0, len(fileCfg.CurrencyPairs))
+	for _, cp := range fileCfg.CurrencyPairs {
+		pairs = append(pairs, fileCurrencyPair{CurrencyPair: cp, path: source.location})
+	}
+	return pairs, nil
+}
+
+// combineCurrencyPairs sets the currency pairs of the config to the pairs of
+// every config file. A pair defined more than once is an error unless
+// duplicate pairs are set to be merged, in which case its providers are
+// combined.
+func (c *Config) combineCurrencyPairs(filePairs []fileCurrencyPair) error {
+	var (
+		currencyPairs []CurrencyPair
+		paths         = make(map[string][]string)
+		index         = make(map[string]int)
+	)
+
+	for _, fp := range filePairs {
+		pair := fp.Base + "/" + fp.Quote
+		paths[pair] = append(paths[pair], fp.path)
+
+		i, ok := index[pair]
+		if !ok {
+			index[pair] = len(currencyPairs)
+			currencyPairs = append(currencyPairs, fp.CurrencyPair)
+			continue
+		}
+
+		if c.DuplicatePairs != DuplicatePairsMerge {
+			return fmt.Errorf(
+				"currency pair %s is defined more than once in %s; "+
+					"remove the duplicate or set duplicate_pairs = \"%s\"",
+				pair,
+				strings.Join(paths[pair], ", "),
+				DuplicatePairsMerge,
+			)
+		}
+		currencyPairs[i] = currencyPairs[i].merge(fp.CurrencyPair)
+	}
+
+	c.mergedPairs = nil
+	for _, cp := range currencyPairs {
+		pair := cp.Base + "/" + cp.Quote
+		if len(paths[pair]) > 1 {
+			c.mergedPairs = append(c.mergedPairs, mergedPair{
+				pair:      pair,
+				paths:     paths[pair],
+				providers: cp.Providers,
+			})
+		}
+	}
+
+	c.CurrencyPairs = currencyPairs
+	return nil
+}
+
+// merge returns the currency pair with the providers of the other definition
+// of the same pair appended.
+func (cp CurrencyPair) merge(other CurrencyPair) CurrencyPair {
+	merged := cp
+	merged.Providers = mergeProviders(cp.Providers, other.Providers)
+	merged.TickerOnlyProviders = mergeProviders(cp.TickerOnlyProviders, other.TickerOnlyProviders)
+	merged.CandleOnlyProviders = mergeProviders(cp.CandleOnlyProviders, other.CandleOnlyProviders)
+	merged.RequiredProviders = mergeProviders(cp.RequiredProviders, other.RequiredProviders)
+	merged.CandleSpotProviders = mergeProviders(cp.CandleSpotProviders, other.CandleSpotProviders)
+	if merged.MinAgreeingProviders == 0 {
+		merged.MinAgreeingProviders = other.MinAgreeingProviders
+		merged.AgreementBand = other.AgreementBand
+	}
+	if merged.MaxProviders == 0 {
+		merged.MaxProviders = other.MaxProviders
+	}
+	if merged.VotePriority == 0 {
+		merged.VotePriority = other.VotePriority
+	}
+	if merged.VoteExponent == 0 {
+		merged.VoteExponent = other.VoteExponent
+	}
+	if merged.VoteRounding == "" {
+		merged.VoteRounding = other.VoteRounding
+		merged.VoteDecimals = other.VoteDecimals
+	}
+	merged.SpotOnly = merged.SpotOnly || other.SpotOnly
+	if merged.MaxVoteChange == "" {
+		merged.MaxVoteChange = other.MaxVoteChange
+	}
+
+	merged.PairAddress = append([]PairAddressProvider(nil), cp.PairAddress...)
+	for _, pa := range other.PairAddress {
+		found := false
+		for _, existing := range merged.PairAddress {
+			if existing == pa {
+				found = true
+				break
+			}
+		}
+		if !found {
+			merged.PairAddress = append(merged.PairAddress, pa)
+		}
+	}
+	return merged
+}
+
+// mergeProviders returns the providers of a followed by the providers of b
+// which are not in a.
+func mergeProviders(a, b []types.ProviderName) []types.ProviderName {
+	merged := append([]types.ProviderName(nil), a...)
+	for _, prov := range b {
+		if !hasProvider(merged, prov) {
+			merged = append(merged, prov)
+		}
+	}
+	return merged
+}
+
+// LogMergedPairs logs the currency pairs whose duplicate definitions were
+// merged while parsing the configs.
+func (c Config) LogMergedPairs(logger zerolog.Logger) {
+	for _, mp := range c.mergedPairs {
+		logger.Warn().
+			Str("currency_pair", mp.pair).
+			Strs("files", mp.paths).
+			Interface("providers", mp.providers).
+			Msg("merged duplicate currency pair definitions")
+	}
+}
@@ -0,0 +1,76 @@
+package config
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/ojo-network/price-feeder/oracle/types"
+)
+
+// unsupportedProviderError returns the error of a provider name which is not
+// supported, listing the supported providers and suggesting the closest one if
+// the name looks like a misspelling of it. kind describes where the name is
+// set, ex. "default provider".
+func unsupportedProviderError(kind string, name types.ProviderName) error {
+	msg := fmt.Sprintf("unsupported %s: %s", kind, name)
+	if suggestion, ok := suggestProvider(name); ok {
+		msg += fmt.Sprintf("; did you mean %s?", suggestion)
+	}
+	return fmt.Errorf("%s (supported providers: %s)", msg, strings.Join(supportedProviderNames(), ", "))
+}
+
+// supportedProviderNames returns the sorted names of the supported providers.
+func supportedProviderNames() []string {
+	names := make([]string, 0, len(SupportedProviders))
+	for name := range SupportedProviders {
+		names = append(names, name.String())
+	}
+	sort.Strings(names)
+	return names
+}
+
+// suggestProvider returns the supported provider closest to the name by
+// edit distance, if the distance is small enough for the name to be a
+// misspelling of it, ex. "krakn" for "kraken".
+func suggestProvider(name types.ProviderName) (string, bool) {
+	lowerName := strings.ToLower(name.String())
+	maxDistance := len(lowerName) / 3
+	if maxDistance < 1 {
+		maxDistance = 1
+	}
+
+	suggestion := ""
+	bestDistance := maxDistance + 1
+	for _, supported := range supportedProviderNames() {
+		if distance := levenshtein(lowerName, supported); distance < bestDistance {
+			suggestion = supported
+			bestDistance = distance
+		}
+	}
+	return suggestion, suggestion != ""
+}
+
+// levenshtein returns the number of single character insertions, deletions
+// and substitutions needed to turn a into b.
+func levenshtein(a, b string) int {
+	ra, rb := []rune(a), []rune(b)
+	prev := make([]int, len(rb)+1)
+	curr := make([]int, len(rb)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+
+	for i := 1; i <= len(ra); i++ {
+		curr[0] = i
+		for j := 1; j <= len(rb); j++ {
+			cost := 1
+			if ra[i-1] == rb[j-1] {
+				cost = 0
+			}
+			curr[j] = min(prev[j]+1, curr[j-1]+1, prev[j-1]+cost)
+		}
+		prev, curr = curr, prev
+	}
+	return prev[len(rb)]
+}
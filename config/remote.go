@@ -0,0 +1,214 @@
+package config
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/spf13/viper"
+)
+
+const (
+	// RemoteConfigTimeout bounds fetching a config from a remote source.
+	RemoteConfigTimeout = 10 * time.Second
+
+	// defaultRemoteConfigType is the format of a remote config whose location
+	// has no supported extension.
+	defaultRemoteConfigType = "toml"
+
+	// consulTokenEnv is the environment variable of the ACL token sent to
+	// consul, as read by the consul CLI.
+	consulTokenEnv = "CONSUL_HTTP_TOKEN"
+
+	schemeHTTP   = "http"
+	schemeHTTPS  = "https"
+	schemeConsul = "consul"
+	schemeEtcd   = "etcd"
+)
+
+// configSource is the content of a config read from a file or fetched from a
+// remote source, along with its format.
+type configSource struct {
+	location string
+	format   string
+	content  []byte
+}
+
+// readConfigSource reads the config at the given location: a file path, an
+// http(s) URL, a consul://host:port/key consul KV key or an etcd://host:port/key
+// etcd v3 key. The format is that of the extension of the location, and TOML
+// for a remote config without a supported extension.
+func readConfigSource(location string) (configSource, error) {
+	if location == "" {
+		return configSource{}, ErrEmptyConfigPath
+	}
+
+	u, err := url.Parse(location)
+	if err != nil || !isRemoteScheme(u.Scheme) {
+		return readConfigFile(location)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), RemoteConfigTimeout)
+	defer cancel()
+
+	var content []byte
+	switch u.Scheme {
+	case schemeHTTP, schemeHTTPS:
+		content, err = fetchHTTPConfig(ctx, u)
+	case schemeConsul:
+		content, err = fetchConsulConfig(ctx, u)
+	case schemeEtcd:
+		content, err = fetchEtcdConfig(ctx, u)
+	}
+	if err != nil {
+		return configSource{}, fmt.Errorf("failed to fetch config from %s: %w", u.Redacted(), err)
+	}
+
+	format := configType(u.Path)
+	if format == "" {
+		format = defaultRemoteConfigType
+	}
+	return configSource{location: u.Redacted(), format: format, content: content}, nil
+}
+
+// readConfigFile reads the config file at the given path.
+func readConfigFile(path string) (configSource, error) {
+	format := configType(path)
+	if format == "" {
+		return configSource{}, fmt.Errorf("failed to read config: %w", viper.UnsupportedConfigError(filepath.Ext(path)))
+	}
+
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return configSource{}, fmt.Errorf("failed to read config: %w", err)
+	}
+	return configSource{location: path, format: format, content: content}, nil
+}
+
+// mergeInto merges the config into the given viper instance.
+func (s configSource) mergeInto(v *viper.Viper) error {
+	v.SetConfigType(s.format)
+	if err := v.MergeConfig(bytes.NewReader(s.content)); err != nil {
+		return fmt.Errorf("failed to read config %s: %w", s.location, err)
+	}
+	return nil
+}
+
+// isRemoteScheme returns true if the URL scheme is that of a remote config
+// source.
+func isRemoteScheme(scheme string) bool {
+	switch scheme {
+	case schemeHTTP, schemeHTTPS, schemeConsul, schemeEtcd:
+		return true
+	}
+	return false
+}
+
+// configType returns the config format of the extension of the path, or an
+// empty string if viper does not support it.
+func configType(path string) string {
+	ext := strings.TrimPrefix(filepath.Ext(path), ".")
+	for _, supported := range viper.SupportedExts {
+		if ext == supported {
+			return ext
+		}
+	}
+	return ""
+}
+
+// fetchHTTPConfig fetches the config served at the URL.
+func fetchHTTPConfig(ctx context.Context, u *url.URL) ([]byte, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u.String(), nil)
+	if err != nil {
+		return nil, err
+	}
+	return doConfigRequest(req)
+}
+
+// fetchConsulConfig fetches the raw value of the consul KV key at the path of
+// the URL from the consul agent at its host.
+func fetchConsulConfig(ctx context.Context, u *url.URL) ([]byte, error) {
+	key := strings.TrimPrefix(u.Path, "/")
+	if key == "" {
+		return nil, fmt.Errorf("missing consul key")
+	}
+
+	endpoint := url.URL{
+		Scheme:   schemeHTTP,
+		Host:     u.Host,
+		Path:     "/v1/kv/" + key,
+		RawQuery: "raw",
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint.String(), nil)
+	if err != nil {
+		return nil, err
+	}
+	if token := os.Getenv(consulTokenEnv); token != "" {
+		req.Header.Set("X-Consul-Token", token)
+	}
+	return doConfigRequest(req)
+}
+
+// fetchEtcdConfig fetches the value of the etcd key at the path of the URL
+// through the v3 JSON gateway of the etcd server at its host.
+func fetchEtcdConfig(ctx context.Context, u *url.URL) ([]byte, error) {
+	if u.Path == "" || u.Path == "/" {
+		return nil, fmt.Errorf("missing etcd key")
+	}
+
+	body, err := json.Marshal(struct {
+		Key string `json:"key"`
+	}{Key: base64.StdEncoding.EncodeToString([]byte(u.Path))})
+	if err != nil {
+		return nil, err
+	}
+
+	endpoint := url.URL{Scheme: schemeHTTP, Host: u.Host, Path: "/v3/kv/range"}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint.String(), bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	content, err := doConfigRequest(req)
+	if err != nil {
+		return nil, err
+	}
+
+	var resp struct {
+		Kvs []struct {
+			Value string `json:"value"`
+		} `json:"kvs"`
+	}
+	if err := json.Unmarshal(content, &resp); err != nil {
+		return nil, fmt.Errorf("failed to decode etcd response: %w", err)
+	}
+	if len(resp.Kvs) == 0 {
+		return nil, fmt.Errorf("etcd key %s not found", u.Path)
+	}
+	return base64.StdEncoding.DecodeString(resp.Kvs[0].Value)
+}
+
+// doConfigRequest sends the request and returns the body of a successful
+// response.
+func doConfigRequest(req *http.Request) ([]byte, error) {
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unexpected status %s", resp.Status)
+	}
+	return io.ReadAll(resp.Body)
+}
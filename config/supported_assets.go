@@ -1,6 +1,8 @@
 package config
 
 import (
+	"sort"
+
 	"github.com/ojo-network/price-feeder/oracle/provider"
 	"github.com/ojo-network/price-feeder/oracle/types"
 )
@@ -11,22 +13,88 @@ var (
 	// SupportedProviders defines a lookup table of all the supported currency API
 	// providers and whether or not they require an API key to be passed in.
 	SupportedProviders = map[types.ProviderName]APIKeyRequired{
-		provider.ProviderKraken:     false,
-		provider.ProviderBinance:    false,
-		provider.ProviderBinanceUS:  false,
-		provider.ProviderCrescent:   false,
-		provider.ProviderOsmosis:    false,
-		provider.ProviderOkx:        false,
-		provider.ProviderHuobi:      false,
-		provider.ProviderGate:       false,
-		provider.ProviderCoinbase:   false,
-		provider.ProviderBitget:     false,
-		provider.ProviderMexc:       false,
-		provider.ProviderCrypto:     false,
-		provider.ProviderPolygon:    true,
-		provider.ProviderEthUniswap: false,
-		provider.ProviderKujira:     false,
-		provider.ProviderMock:       false,
+		provider.ProviderKraken:         false,
+		provider.ProviderBinance:        false,
+		provider.ProviderBinanceUS:      false,
+		provider.ProviderCrescent:       false,
+		provider.ProviderOsmosis:        false,
+		provider.ProviderOkx:            false,
+		provider.ProviderHuobi:          false,
+		provider.ProviderGate:           false,
+		provider.ProviderCoinbase:       false,
+		provider.ProviderBitget:         false,
+		provider.ProviderMexc:           false,
+		provider.ProviderCrypto:         false,
+		provider.ProviderPolygon:        true,
+		provider.ProviderEthUniswap:     false,
+		provider.ProviderKujira:         false,
+		provider.ProviderInjective:      false,
+		provider.ProviderLbank:          false,
+		provider.ProviderBingx:          false,
+		provider.ProviderXt:             false,
+		provider.ProviderCoincheck:      false,
+		provider.ProviderJupiter:        false,
+		provider.ProviderChainlink:      false,
+		provider.ProviderOsmosisTwap:    false,
+		provider.ProviderUniswapV3:      false,
+		provider.ProviderCurve:          false,
+		provider.ProviderDydx:           false,
+		provider.ProviderRedemptionRate: false,
+		provider.ProviderAscendex:       false,
+		provider.ProviderMock:           false,
+	}
+
+	// SupportedDerivativePriceProviders defines a lookup table of the providers
+	// that can price a pair by its perpetual mark or index price, and the
+	// price types each of them supports.
+	SupportedDerivativePriceProviders = map[types.ProviderName][]provider.PriceType{
+		provider.ProviderBinance:   {provider.PriceTypeMark, provider.PriceTypeIndex},
+		provider.ProviderOkx:       {provider.PriceTypeMark, provider.PriceTypeIndex},
+		provider.ProviderInjective: {provider.PriceTypeMark},
+	}
+
+	// SupportedPriceSourceProviders defines a lookup table of the providers
+	// whose tickers carry the best bid and ask of a pair, which can price a
+	// pair by its best quote instead of the last traded price.
+	SupportedPriceSourceProviders = map[types.ProviderName]struct{}{
+		provider.ProviderBinance:   {},
+		provider.ProviderOkx:       {},
+		provider.ProviderCoincheck: {},
+	}
+
+	// SupportedOrderBookDepthProviders defines a lookup table of the
+	// providers which can subscribe to the order books of their pairs to
+	// measure their depth.
+	SupportedOrderBookDepthProviders = map[types.ProviderName]struct{}{
+		provider.ProviderGate:  {},
+		provider.ProviderHuobi: {},
+	}
+
+	// SupportedBestQuoteProviders defines a lookup table of the providers
+	// which stream the best bid and ask of their pairs, whose prices can be
+	// excluded by the max spread of a pair.
+	SupportedBestQuoteProviders = map[types.ProviderName]struct{}{
+		provider.ProviderBinance:   {},
+		provider.ProviderBinanceUS: {},
+		provider.ProviderOkx:       {},
+	}
+
+	// SupportedTickerReorderProviders defines a lookup table of the providers
+	// whose ticks carry their exchange timestamp, which can be buffered to use
+	// the newest tick by exchange time.
+	SupportedTickerReorderProviders = map[types.ProviderName]struct{}{
+		provider.ProviderBinance:   {},
+		provider.ProviderBinanceUS: {},
+		provider.ProviderOkx:       {},
+	}
+
+	// SupportedCandleIntervals defines a lookup table of the providers which
+	// can subscribe to candles of several intervals, and the intervals each
+	// of them supports.
+	SupportedCandleIntervals = map[types.ProviderName][]string{
+		provider.ProviderBinance: {"1m", "3m", "5m", "15m", "30m", "1h"},
+		provider.ProviderKraken:  {"1m", "5m", "15m", "30m", "1h"},
+		provider.ProviderMexc:    {"1m", "5m", "15m", "30m", "1h"},
 	}
 
 	// SupportedConversions defines a lookup table for which currency pairs we
@@ -219,6 +287,40 @@ var (
 	}
 )
 
+// IsForexPair returns true if the currency pair is the USD rate of a supported
+// forex currency.
+func IsForexPair(cp types.CurrencyPair) bool {
+	_, ok := SupportedForexCurrencies[cp.Base]
+	return ok && cp.Quote == DenomUSD
+}
+
+// SupportedQuotes returns the sorted quotes a currency pair can be quoted in
+// without a forex rate, being USD and every currency with a supported USD
+// conversion. Forex currencies are supported quotes once their USD rate is
+// configured.
+func SupportedQuotes() []string {
+	quotes := map[string]struct{}{DenomUSD: {}}
+	for pair := range SupportedConversions {
+		quotes[pair.Base] = struct{}{}
+	}
+	return sortedKeys(quotes)
+}
+
+// SupportedForexCurrencySlice returns the sorted supported forex currencies.
+func SupportedForexCurrencySlice() []string {
+	return sortedKeys(SupportedForexCurrencies)
+}
+
+// sortedKeys returns the sorted keys of a set.
+func sortedKeys(set map[string]struct{}) []string {
+	keys := make([]string, 0, len(set))
+	for key := range set {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
 func SupportedConversionSlice() []types.CurrencyPair {
 	pairs := make([]types.CurrencyPair, 0, len(SupportedConversions))
 	for pair := range SupportedConversions {
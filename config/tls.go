@@ -0,0 +1,42 @@
+package config
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"os"
+)
+
+// TLSConfig returns the TLS configuration of the API server, or nil if it
+// serves plaintext. Client certificates signed by the client CA are required
+// if one is set. It fails if a certificate file cannot be loaded.
+func (s Server) TLSConfig() (*tls.Config, error) {
+	if s.TLSCertFile == "" && s.TLSKeyFile == "" {
+		return nil, nil
+	}
+
+	cert, err := tls.LoadX509KeyPair(s.TLSCertFile, s.TLSKeyFile)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load server TLS certificate: %w", err)
+	}
+
+	tlsConfig := &tls.Config{
+		MinVersion:   tls.VersionTLS12,
+		Certificates: []tls.Certificate{cert},
+	}
+
+	if s.TLSClientCAFile != "" {
+		pem, err := os.ReadFile(s.TLSClientCAFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read server TLS client CA: %w", err)
+		}
+		clientCAs := x509.NewCertPool()
+		if !clientCAs.AppendCertsFromPEM(pem) {
+			return nil, fmt.Errorf("no valid PEM certificate in server TLS client CA %s", s.TLSClientCAFile)
+		}
+		tlsConfig.ClientCAs = clientCAs
+		tlsConfig.ClientAuth = tls.RequireAndVerifyClientCert
+	}
+
+	return tlsConfig, nil
+}
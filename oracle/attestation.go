@@ -0,0 +1,237 @@
+package oracle
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"time"
+
+	cryptotypes "github.com/cosmos/cosmos-sdk/crypto/types"
+	"github.com/cosmos/cosmos-sdk/telemetry"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/rs/zerolog"
+
+	"github.com/ojo-network/price-feeder/oracle/types"
+)
+
+const (
+	// attestationQueueSize is the number of attestations waiting to be posted
+	// past which new attestations are dropped.
+	attestationQueueSize = 4
+
+	// defaultAttestationRetryBackoff is the delay before the first retry of a
+	// failed post, doubled on every following retry.
+	defaultAttestationRetryBackoff = time.Second
+)
+
+type (
+	// AttestationSigner signs the given bytes with the feeder key and returns
+	// the signature and the public key it can be verified with.
+	AttestationSigner func(msg []byte) ([]byte, cryptotypes.PubKey, error)
+
+	// Attestor posts the aggregated prices of every voting period, signed with
+	// the feeder key, to an external collector. Attestations are queued and
+	// posted in the background with retries, so a slow or unavailable
+	// collector never delays the vote.
+	Attestor struct {
+		logger       zerolog.Logger
+		url          string
+		authToken    string
+		client       *http.Client
+		maxRetries   int
+		retryBackoff time.Duration
+		sign         AttestationSigner
+
+		queue          chan PriceAttestation
+		lastVotePeriod int64
+	}
+
+	// PriceAttestation defines the aggregated prices of a voting period
+	// attested by a feeder.
+	PriceAttestation struct {
+		ChainID     string          `json:"chain_id"`
+		Validator   string          `json:"validator"`
+		Feeder      string          `json:"feeder"`
+		BlockHeight int64           `json:"block_height"`
+		VotePeriod  int64           `json:"vote_period"`
+		Timestamp   time.Time       `json:"timestamp"`
+		Prices      []AttestedPrice `json:"prices"`
+	}
+
+	// AttestedPrice defines the USD price of a denom in an attestation.
+	AttestedPrice struct {
+		Denom string  `json:"denom"`
+		Price sdk.Dec `json:"price"`
+	}
+
+	// SignedPriceAttestation defines the body posted to the collector. The
+	// signature covers the exact bytes of the attestation.
+	SignedPriceAttestation struct {
+		Attestation json.RawMessage `json:"attestation"`
+		PubKey      string          `json:"pub_key"`      // base64 encoded public key
+		PubKeyType  string          `json:"pub_key_type"` // ex.: secp256k1
+		Signature   string          `json:"signature"`    // base64 encoded signature
+	}
+)
+
+// NewAttestor returns an Attestor posting to the collector at url, with the
+// bearer authToken if set. A post times out after timeout and is retried up
+// to maxRetries times.
+func NewAttestor(
+	logger zerolog.Logger,
+	url string,
+	authToken string,
+	timeout time.Duration,
+	maxRetries int,
+	sign AttestationSigner,
+) *Attestor {
+	return &Attestor{
+		logger:       logger.With().Str("module", "attestor").Logger(),
+		url:          url,
+		authToken:    authToken,
+		client:       &http.Client{Timeout: timeout},
+		maxRetries:   maxRetries,
+		retryBackoff: defaultAttestationRetryBackoff,
+		sign:         sign,
+		queue:        make(chan PriceAttestation, attestationQueueSize),
+	}
+}
+
+// NewPriceAttestation returns the attestation of the USD prices of a voting
+// period, sorted by denom.
+func NewPriceAttestation(
+	chainID, validator, feeder string,
+	blockHeight, votePeriod int64,
+	timestamp time.Time,
+	prices types.CurrencyPairDec,
+) PriceAttestation {
+	attestedPrices := make([]AttestedPrice, 0, len(prices))
+	for cp, price := range prices {
+		attestedPrices = append(attestedPrices, AttestedPrice{Denom: cp.Base, Price: price})
+	}
+	sort.Slice(attestedPrices, func(i, j int) bool {
+		return attestedPrices[i].Denom < attestedPrices[j].Denom
+	})
+
+	return PriceAttestation{
+		ChainID:     chainID,
+		Validator:   validator,
+		Feeder:      feeder,
+		BlockHeight: blockHeight,
+		VotePeriod:  votePeriod,
+		Timestamp:   timestamp.UTC(),
+		Prices:      attestedPrices,
+	}
+}
+
+// Start posts the queued attestations until the context is canceled.
+func (a *Attestor) Start(ctx context.Context) {
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case attestation := <-a.queue:
+			if err := a.post(ctx, attestation); err != nil {
+				telemetry.IncrCounter(1, "attestation", "failure")
+				a.logger.Err(err).Int64("vote_period", attestation.VotePeriod).Msg("failed to post price attestation")
+				continue
+			}
+			a.logger.Debug().Int64("vote_period", attestation.VotePeriod).Msg("posted price attestation")
+		}
+	}
+}
+
+// Submit queues the attestation to be posted without blocking. Attestations
+// of a voting period already submitted are ignored, and the attestation is
+// dropped if the queue is full. It returns true if the attestation was queued.
+func (a *Attestor) Submit(attestation PriceAttestation) bool {
+	if attestation.VotePeriod <= a.lastVotePeriod {
+		return false
+	}
+	a.lastVotePeriod = attestation.VotePeriod
+
+	select {
+	case a.queue <- attestation:
+		return true
+	default:
+		telemetry.IncrCounter(1, "attestation", "failure")
+		a.logger.Warn().
+			Int64("vote_period", attestation.VotePeriod).
+			Msg("dropped price attestation; collector is falling behind")
+		return false
+	}
+}
+
+// post signs the attestation and posts it to the collector, retrying with an
+// exponential backoff.
+func (a *Attestor) post(ctx context.Context, attestation PriceAttestation) error {
+	body, err := a.signedBody(attestation)
+	if err != nil {
+		return err
+	}
+
+	backoff := a.retryBackoff
+	for retry := 0; ; retry++ {
+		err = a.postOnce(ctx, body)
+		if err == nil || retry >= a.maxRetries {
+			return err
+		}
+
+		a.logger.Debug().Err(err).Int("retry", retry+1).Msg("retrying price attestation")
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(backoff):
+		}
+		backoff *= 2
+	}
+}
+
+// signedBody returns the body of the signed attestation.
+func (a *Attestor) signedBody(attestation PriceAttestation) ([]byte, error) {
+	bz, err := json.Marshal(attestation)
+	if err != nil {
+		return nil, err
+	}
+
+	signature, pubKey, err := a.sign(bz)
+	if err != nil {
+		return nil, fmt.Errorf("failed to sign price attestation: %w", err)
+	}
+
+	return json.Marshal(SignedPriceAttestation{
+		Attestation: bz,
+		PubKey:      base64.StdEncoding.EncodeToString(pubKey.Bytes()),
+		PubKeyType:  pubKey.Type(),
+		Signature:   base64.StdEncoding.EncodeToString(signature),
+	})
+}
+
+// postOnce posts the body to the collector, failing on a non 2xx status.
+func (a *Attestor) postOnce(ctx context.Context, body []byte) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, a.url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if a.authToken != "" {
+		req.Header.Set("Authorization", "Bearer "+a.authToken)
+	}
+
+	resp, err := a.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	_, _ = io.Copy(io.Discard, resp.Body)
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf("collector responded with status %d", resp.StatusCode)
+	}
+	return nil
+}
@@ -0,0 +1,100 @@
+package oracle
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/cosmos/cosmos-sdk/crypto/keys/secp256k1"
+	cryptotypes "github.com/cosmos/cosmos-sdk/crypto/types"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ojo-network/price-feeder/oracle/types"
+)
+
+func TestAttestor_post(t *testing.T) {
+	privKey := secp256k1.GenPrivKey()
+	sign := func(msg []byte) ([]byte, cryptotypes.PubKey, error) {
+		sig, err := privKey.Sign(msg)
+		return sig, privKey.PubKey(), err
+	}
+
+	var requests int32
+	received := make(chan SignedPriceAttestation, 1)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.Equal(t, http.MethodPost, r.Method)
+		require.Equal(t, "Bearer secret", r.Header.Get("Authorization"))
+
+		// the collector fails twice before accepting the attestation
+		if atomic.AddInt32(&requests, 1) <= 2 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+
+		var signed SignedPriceAttestation
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&signed))
+		received <- signed
+	}))
+	defer server.Close()
+
+	attestor := NewAttestor(zerolog.Nop(), server.URL, "secret", time.Second, 2, sign)
+	attestor.retryBackoff = time.Millisecond
+
+	attestation := NewPriceAttestation(
+		"ojo-testnet",
+		"ojovaloper1",
+		"ojo1",
+		101,
+		20,
+		time.Unix(1700000000, 0),
+		types.CurrencyPairDec{
+			{Base: "OJO", Quote: "USD"}:  sdk.MustNewDecFromStr("0.25"),
+			{Base: "ATOM", Quote: "USD"}: sdk.MustNewDecFromStr("10.5"),
+		},
+	)
+	require.NoError(t, attestor.post(context.Background(), attestation))
+	require.Equal(t, int32(3), atomic.LoadInt32(&requests))
+
+	signed := <-received
+	signature, err := base64.StdEncoding.DecodeString(signed.Signature)
+	require.NoError(t, err)
+	require.True(t, privKey.PubKey().VerifySignature(signed.Attestation, signature))
+	require.Equal(t, base64.StdEncoding.EncodeToString(privKey.PubKey().Bytes()), signed.PubKey)
+	require.Equal(t, "secp256k1", signed.PubKeyType)
+
+	var posted PriceAttestation
+	require.NoError(t, json.Unmarshal(signed.Attestation, &posted))
+	require.Equal(t, attestation, posted)
+	require.Equal(t, []AttestedPrice{
+		{Denom: "ATOM", Price: sdk.MustNewDecFromStr("10.5")},
+		{Denom: "OJO", Price: sdk.MustNewDecFromStr("0.25")},
+	}, posted.Prices)
+
+	// the post fails once the retries are exhausted
+	atomic.StoreInt32(&requests, -10)
+	require.Error(t, attestor.post(context.Background(), attestation))
+	require.Equal(t, int32(-7), atomic.LoadInt32(&requests))
+}
+
+func TestAttestor_Submit(t *testing.T) {
+	attestor := NewAttestor(zerolog.Nop(), "http://localhost", "", time.Second, 0, nil)
+
+	require.True(t, attestor.Submit(PriceAttestation{VotePeriod: 1}))
+
+	// a voting period is attested once
+	require.False(t, attestor.Submit(PriceAttestation{VotePeriod: 1}))
+
+	// attestations are dropped instead of blocking once the queue is full
+	for period := int64(2); period <= attestationQueueSize; period++ {
+		require.True(t, attestor.Submit(PriceAttestation{VotePeriod: period}))
+	}
+	require.False(t, attestor.Submit(PriceAttestation{VotePeriod: attestationQueueSize + 1}))
+	require.Len(t, attestor.queue, attestationQueueSize)
+}
@@ -0,0 +1,111 @@
+package client
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	codectypes "github.com/cosmos/cosmos-sdk/codec/types"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/cosmos/cosmos-sdk/x/authz"
+	"google.golang.org/grpc"
+)
+
+// AuthzVoteBuilder builds the prevote and vote messages of its builder on
+// behalf of the granter, the validator's feeder, wrapped in an authz MsgExec
+// submitted by the feeder account, the grantee of an authz grant for them.
+type AuthzVoteBuilder struct {
+	OracleVoteBuilder
+	Granter string
+}
+
+// NewAuthzVoteBuilder returns a builder wrapping the messages of the given
+// builder in an authz MsgExec on behalf of the granter.
+func NewAuthzVoteBuilder(builder OracleVoteBuilder, granter string) AuthzVoteBuilder {
+	return AuthzVoteBuilder{
+		OracleVoteBuilder: builder,
+		Granter:           granter,
+	}
+}
+
+// PrevoteMsg returns the prevote message of the granter, executed by the
+// feeder.
+func (b AuthzVoteBuilder) PrevoteMsg(hash, feeder, validator string) sdk.Msg {
+	return newMsgExec(feeder, b.OracleVoteBuilder.PrevoteMsg(hash, b.Granter, validator))
+}
+
+// VoteMsg returns the vote message of the granter, executed by the feeder.
+func (b AuthzVoteBuilder) VoteMsg(salt, exchangeRates, feeder, validator string) sdk.Msg {
+	return newMsgExec(feeder, b.OracleVoteBuilder.VoteMsg(salt, exchangeRates, b.Granter, validator))
+}
+
+// IsVoteMsg returns true if the message is a MsgExec of a vote message.
+func (b AuthzVoteBuilder) IsVoteMsg(msg sdk.Msg) bool {
+	execMsg, ok := msg.(*authz.MsgExec)
+	if !ok {
+		return false
+	}
+
+	msgs, err := execMsg.GetMessages()
+	if err != nil {
+		return false
+	}
+	for _, msg := range msgs {
+		if b.OracleVoteBuilder.IsVoteMsg(msg) {
+			return true
+		}
+	}
+	return false
+}
+
+// CheckGrants returns an error unless the grantee holds a grant of the granter
+// unexpired at now for both the prevote and the vote messages.
+func (b AuthzVoteBuilder) CheckGrants(
+	ctx context.Context,
+	conn *grpc.ClientConn,
+	grantee string,
+	now time.Time,
+) error {
+	queryClient := authz.NewQueryClient(conn)
+	msgTypeURLs := []string{
+		sdk.MsgTypeURL(b.OracleVoteBuilder.PrevoteMsg("", b.Granter, "")),
+		sdk.MsgTypeURL(b.OracleVoteBuilder.VoteMsg("", "", b.Granter, "")),
+	}
+
+	for _, msgTypeURL := range msgTypeURLs {
+		resp, err := queryClient.Grants(ctx, &authz.QueryGrantsRequest{
+			Granter:    b.Granter,
+			Grantee:    grantee,
+			MsgTypeUrl: msgTypeURL,
+		})
+		if err != nil {
+			return fmt.Errorf("failed to query authz grant of %s to %s for %s: %w", b.Granter, grantee, msgTypeURL, err)
+		}
+		if !hasActiveGrant(resp.Grants, now) {
+			return fmt.Errorf("no authz grant of %s to %s for %s", b.Granter, grantee, msgTypeURL)
+		}
+	}
+	return nil
+}
+
+// hasActiveGrant returns true if any of the grants has not expired.
+func hasActiveGrant(grants []*authz.Grant, now time.Time) bool {
+	for _, grant := range grants {
+		if grant.Expiration == nil || grant.Expiration.After(now) {
+			return true
+		}
+	}
+	return false
+}
+
+// newMsgExec wraps the message in a MsgExec of the grantee.
+func newMsgExec(grantee string, msg sdk.Msg) *authz.MsgExec {
+	msgAny, err := codectypes.NewAnyWithValue(msg)
+	if err != nil {
+		panic(err)
+	}
+	return &authz.MsgExec{
+		Grantee: grantee,
+		Msgs:    []*codectypes.Any{msgAny},
+	}
+}
@@ -0,0 +1,63 @@
+package client
+
+import (
+	"testing"
+	"time"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/cosmos/cosmos-sdk/x/authz"
+	"github.com/stretchr/testify/require"
+	oracletypes "github.com/umee-network/umee/v6/x/oracle/types"
+)
+
+func TestAuthzVoteBuilder(t *testing.T) {
+	var (
+		builder   = NewAuthzVoteBuilder(UmeeVoteBuilder{}, "granter")
+		valAddr   = sdk.ValAddress([]byte("validator"))
+		salt      = "a1b2"
+		rates     = "ATOM:10.000000000000000000"
+		feeder    = "feeder"
+		validator = valAddr.String()
+	)
+
+	hash := builder.PrevoteHash(salt, rates, valAddr)
+	require.Equal(t, UmeeVoteBuilder{}.PrevoteHash(salt, rates, valAddr), hash)
+
+	prevote, ok := builder.PrevoteMsg(hash, feeder, validator).(*authz.MsgExec)
+	require.True(t, ok)
+	require.Equal(t, feeder, prevote.Grantee)
+	msgs, err := prevote.GetMessages()
+	require.NoError(t, err)
+	require.Equal(t, []sdk.Msg{&oracletypes.MsgAggregateExchangeRatePrevote{
+		Hash:      hash,
+		Feeder:    "granter",
+		Validator: validator,
+	}}, msgs)
+	require.False(t, builder.IsVoteMsg(prevote))
+
+	vote, ok := builder.VoteMsg(salt, rates, feeder, validator).(*authz.MsgExec)
+	require.True(t, ok)
+	require.Equal(t, feeder, vote.Grantee)
+	msgs, err = vote.GetMessages()
+	require.NoError(t, err)
+	require.Equal(t, []sdk.Msg{&oracletypes.MsgAggregateExchangeRateVote{
+		Salt:          salt,
+		ExchangeRates: rates,
+		Feeder:        "granter",
+		Validator:     validator,
+	}}, msgs)
+	require.True(t, builder.IsVoteMsg(vote))
+
+	// an unwrapped vote is not the builder's vote
+	require.False(t, builder.IsVoteMsg(UmeeVoteBuilder{}.VoteMsg(salt, rates, feeder, validator)))
+}
+
+func TestHasActiveGrant(t *testing.T) {
+	now := time.Unix(1700000000, 0)
+	past, future := now.Add(-time.Hour), now.Add(time.Hour)
+
+	require.False(t, hasActiveGrant(nil, now))
+	require.False(t, hasActiveGrant([]*authz.Grant{{Expiration: &past}}, now))
+	require.True(t, hasActiveGrant([]*authz.Grant{{Expiration: &past}, {Expiration: &future}}, now))
+	require.True(t, hasActiveGrant([]*authz.Grant{{}}, now))
+}
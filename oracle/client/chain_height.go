@@ -5,6 +5,7 @@ import (
 	"errors"
 	"fmt"
 	"sync"
+	"time"
 
 	tmrpcclient "github.com/cometbft/cometbft/rpc/client"
 	rpchttp "github.com/cometbft/cometbft/rpc/client/http"
@@ -13,8 +14,14 @@ import (
 	"github.com/rs/zerolog"
 
 	"github.com/cosmos/cosmos-sdk/client"
+	"github.com/cosmos/cosmos-sdk/telemetry"
 )
 
+// maxInitialHeightLag is the number of blocks the initial height may differ
+// from the latest height reported by the node's status before a warning is
+// logged.
+const maxInitialHeightLag = 5
+
 var (
 	errParseEventDataNewBlockHeader = errors.New("error parsing EventDataNewBlockHeader")
 	queryEventNewBlockHeader        = tmtypes.QueryForEvent(tmtypes.EventNewBlockHeader)
@@ -30,6 +37,15 @@ type ChainHeight struct {
 	mtx               sync.RWMutex
 	errGetChainHeight error
 	lastChainHeight   int64
+	clockSkew         time.Duration
+	maxClockSkew      time.Duration
+
+	// lastBlockHeaderTime is the time of the last block header and
+	// lastBlockReceived when it was received. blockTime is the moving
+	// average of the time between the block headers.
+	lastBlockHeaderTime time.Time
+	lastBlockReceived   time.Time
+	blockTime           time.Duration
 }
 
 // NewChainHeight returns a new ChainHeight struct that
@@ -39,6 +55,7 @@ func NewChainHeight(
 	client client.TendermintRPC,
 	logger zerolog.Logger,
 	initialHeight int64,
+	rpcTimeout time.Duration,
 ) (*ChainHeight, error) {
 	if initialHeight < 1 {
 		return nil, fmt.Errorf("expected positive initial block height")
@@ -63,12 +80,48 @@ func NewChainHeight(
 		errGetChainHeight: nil,
 		lastChainHeight:   initialHeight,
 	}
+	chainHeight.reconcileInitialHeight(ctx, rpcClient, rpcTimeout)
 
 	go chainHeight.subscribe(ctx, rpcClient, newBlockHeaderSubscription)
 
 	return chainHeight, nil
 }
 
+// reconcileInitialHeight queries the latest height of the node once subscribed
+// and keeps the highest of it and the initial height, so a stale initial
+// height doesn't skew the voting period math until the first block event.
+// The query gives up after rpcTimeout so an unresponsive node doesn't block
+// startup.
+func (chainHeight *ChainHeight) reconcileInitialHeight(
+	ctx context.Context,
+	statusClient tmrpcclient.StatusClient,
+	rpcTimeout time.Duration,
+) {
+	ctx, cancel := context.WithTimeout(ctx, rpcTimeout)
+	defer cancel()
+
+	status, err := statusClient.Status(ctx)
+	if err != nil {
+		chainHeight.Logger.Warn().Err(err).Msg("failed to query node status; using the initial block height")
+		return
+	}
+	statusHeight := status.SyncInfo.LatestBlockHeight
+
+	chainHeight.mtx.Lock()
+	defer chainHeight.mtx.Unlock()
+
+	initialHeight := chainHeight.lastChainHeight
+	if lag := statusHeight - initialHeight; lag > maxInitialHeightLag || lag < -maxInitialHeightLag {
+		chainHeight.Logger.Warn().
+			Int64("initial_height", initialHeight).
+			Int64("status_height", statusHeight).
+			Msg("initial block height differs from the node's latest height")
+	}
+	if statusHeight > initialHeight {
+		chainHeight.lastChainHeight = statusHeight
+	}
+}
+
 // updateChainHeight receives the data to be updated thread safe.
 func (chainHeight *ChainHeight) updateChainHeight(blockHeight int64, err error) {
 	chainHeight.mtx.Lock()
@@ -104,6 +157,8 @@ func (chainHeight *ChainHeight) subscribe(
 				continue
 			}
 			chainHeight.updateChainHeight(eventDataNewBlockHeader.Header.Height, nil)
+			chainHeight.updateClockSkew(time.Since(eventDataNewBlockHeader.Header.Time))
+			chainHeight.updateBlockTiming(eventDataNewBlockHeader.Header.Time, time.Now())
 		}
 	}
 }
@@ -115,3 +170,76 @@ func (chainHeight *ChainHeight) GetChainHeight() (int64, error) {
 
 	return chainHeight.lastChainHeight, chainHeight.errGetChainHeight
 }
+
+// SetMaxClockSkew sets the clock skew between the local clock and the block
+// times past which a warning is logged. A zero bound disables the warning.
+func (chainHeight *ChainHeight) SetMaxClockSkew(maxClockSkew time.Duration) {
+	chainHeight.mtx.Lock()
+	defer chainHeight.mtx.Unlock()
+
+	chainHeight.maxClockSkew = maxClockSkew
+}
+
+// GetClockSkew returns how far the local clock was ahead of the time of the
+// last block when its header was received. It is negative if the local clock
+// is behind.
+func (chainHeight *ChainHeight) GetClockSkew() time.Duration {
+	chainHeight.mtx.RLock()
+	defer chainHeight.mtx.RUnlock()
+
+	return chainHeight.clockSkew
+}
+
+// updateClockSkew sets the clock skew measured on a new block, and logs a
+// warning if it exceeds the maximum clock skew.
+func (chainHeight *ChainHeight) updateClockSkew(clockSkew time.Duration) {
+	chainHeight.mtx.Lock()
+	chainHeight.clockSkew = clockSkew
+	maxClockSkew := chainHeight.maxClockSkew
+	chainHeight.mtx.Unlock()
+
+	telemetry.SetGauge(float32(clockSkew.Seconds()), "clock_skew_seconds")
+
+	if maxClockSkew > 0 && absDuration(clockSkew) > maxClockSkew {
+		chainHeight.Logger.Warn().
+			Dur("clock_skew", clockSkew).
+			Dur("max_clock_skew", maxClockSkew).
+			Msg("local clock is skewed from the chain's block time")
+	}
+}
+
+// updateBlockTiming records the time of a new block header and when it was
+// received, and updates the average block time, weighting the latest block
+// by a quarter.
+func (chainHeight *ChainHeight) updateBlockTiming(headerTime, received time.Time) {
+	chainHeight.mtx.Lock()
+	defer chainHeight.mtx.Unlock()
+
+	if !chainHeight.lastBlockHeaderTime.IsZero() && headerTime.After(chainHeight.lastBlockHeaderTime) {
+		interval := headerTime.Sub(chainHeight.lastBlockHeaderTime)
+		if chainHeight.blockTime == 0 {
+			chainHeight.blockTime = interval
+		} else {
+			chainHeight.blockTime = (3*chainHeight.blockTime + interval) / 4
+		}
+	}
+	chainHeight.lastBlockHeaderTime = headerTime
+	chainHeight.lastBlockReceived = received
+}
+
+// GetBlockTiming returns when the last block header was received and the
+// average block time, zero until two blocks were received.
+func (chainHeight *ChainHeight) GetBlockTiming() (time.Time, time.Duration) {
+	chainHeight.mtx.RLock()
+	defer chainHeight.mtx.RUnlock()
+
+	return chainHeight.lastBlockReceived, chainHeight.blockTime
+}
+
+// absDuration returns the absolute value of the duration.
+func absDuration(d time.Duration) time.Duration {
+	if d < 0 {
+		return -d
+	}
+	return d
+}
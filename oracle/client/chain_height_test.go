@@ -0,0 +1,93 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	tmctypes "github.com/cometbft/cometbft/rpc/core/types"
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/require"
+)
+
+type mockStatusClient struct {
+	height int64
+	err    error
+	hang   bool
+}
+
+func (m mockStatusClient) Status(ctx context.Context) (*tmctypes.ResultStatus, error) {
+	if m.hang {
+		<-ctx.Done()
+		return nil, ctx.Err()
+	}
+	if m.err != nil {
+		return nil, m.err
+	}
+	return &tmctypes.ResultStatus{SyncInfo: tmctypes.SyncInfo{LatestBlockHeight: m.height}}, nil
+}
+
+func TestChainHeight_reconcileInitialHeight(t *testing.T) {
+	testCases := []struct {
+		name          string
+		initialHeight int64
+		status        mockStatusClient
+		expected      int64
+	}{
+		{"initial height far behind", 100, mockStatusClient{height: 5000}, 5000},
+		{"initial height ahead", 5000, mockStatusClient{height: 4998}, 5000},
+		{"heights match", 5000, mockStatusClient{height: 5000}, 5000},
+		{"status error", 100, mockStatusClient{err: errors.New("unavailable")}, 100},
+		{"status timeout", 100, mockStatusClient{hang: true}, 100},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			chainHeight := &ChainHeight{Logger: zerolog.Nop(), lastChainHeight: tc.initialHeight}
+			chainHeight.reconcileInitialHeight(context.Background(), tc.status, 10*time.Millisecond)
+
+			height, err := chainHeight.GetChainHeight()
+			require.NoError(t, err)
+			require.Equal(t, tc.expected, height)
+		})
+	}
+}
+
+func TestChainHeight_ClockSkew(t *testing.T) {
+	chainHeight := &ChainHeight{Logger: zerolog.Nop()}
+	require.Zero(t, chainHeight.GetClockSkew())
+
+	chainHeight.SetMaxClockSkew(time.Second)
+	chainHeight.updateClockSkew(-3 * time.Second)
+	require.Equal(t, -3*time.Second, chainHeight.GetClockSkew())
+
+	chainHeight.updateClockSkew(500 * time.Millisecond)
+	require.Equal(t, 500*time.Millisecond, chainHeight.GetClockSkew())
+}
+
+func TestChainHeight_BlockTiming(t *testing.T) {
+	chainHeight := &ChainHeight{Logger: zerolog.Nop()}
+	headerTime := time.Unix(1700000000, 0)
+
+	chainHeight.updateBlockTiming(headerTime, headerTime.Add(100*time.Millisecond))
+	received, blockTime := chainHeight.GetBlockTiming()
+	require.Equal(t, headerTime.Add(100*time.Millisecond), received)
+	require.Zero(t, blockTime)
+
+	chainHeight.updateBlockTiming(headerTime.Add(6*time.Second), headerTime.Add(6*time.Second))
+	_, blockTime = chainHeight.GetBlockTiming()
+	require.Equal(t, 6*time.Second, blockTime)
+
+	// later blocks move the average by a quarter of their difference
+	chainHeight.updateBlockTiming(headerTime.Add(8*time.Second), headerTime.Add(8*time.Second))
+	received, blockTime = chainHeight.GetBlockTiming()
+	require.Equal(t, headerTime.Add(8*time.Second), received)
+	require.Equal(t, 5*time.Second, blockTime)
+}
+
+func TestAbsDuration(t *testing.T) {
+	require.Equal(t, time.Second, absDuration(time.Second))
+	require.Equal(t, time.Second, absDuration(-time.Second))
+	require.Zero(t, absDuration(0))
+}
@@ -3,13 +3,16 @@ package client
 import (
 	"bytes"
 	"context"
+	"encoding/hex"
 	"errors"
 	"fmt"
 	"io"
 	"os"
+	"strings"
 	"time"
 
 	rpchttp "github.com/cometbft/cometbft/rpc/client/http"
+	tmctypes "github.com/cometbft/cometbft/rpc/core/types"
 	tmjsonclient "github.com/cometbft/cometbft/rpc/jsonrpc/client"
 	"github.com/rs/zerolog"
 	umeeapp "github.com/umee-network/umee/v6/app"
@@ -19,6 +22,7 @@ import (
 	"github.com/cosmos/cosmos-sdk/client/rpc"
 	"github.com/cosmos/cosmos-sdk/client/tx"
 	"github.com/cosmos/cosmos-sdk/crypto/keyring"
+	cryptotypes "github.com/cosmos/cosmos-sdk/crypto/types"
 	"github.com/cosmos/cosmos-sdk/telemetry"
 	sdk "github.com/cosmos/cosmos-sdk/types"
 	"github.com/cosmos/cosmos-sdk/types/module/testutil"
@@ -26,6 +30,21 @@ import (
 	authtypes "github.com/cosmos/cosmos-sdk/x/auth/types"
 )
 
+const (
+	// txConfirmPollInterval defines how often the node is queried for the
+	// result of a broadcasted transaction.
+	txConfirmPollInterval = 500 * time.Millisecond
+
+	// txConfirmMaxQueryErrors is the number of consecutive failed queries of
+	// a transaction, other than it not being found yet, after which its
+	// inclusion is left unconfirmed, ex. on a node without a tx index.
+	txConfirmMaxQueryErrors = 3
+)
+
+// ErrTxUnconfirmed is returned for a transaction which was broadcasted and
+// accepted by the node, but whose inclusion could not be queried.
+var ErrTxUnconfirmed = errors.New("tx inclusion unconfirmed")
+
 type (
 	// OracleClient defines a structure that interfaces with the Umee node.
 	OracleClient struct {
@@ -47,12 +66,30 @@ type (
 		GRPCEndpoint        string
 		KeyringPassphrase   string
 		ChainHeight         *ChainHeight
+
+		// GRPCConn is the health-checked gRPC connection to the node. Queries
+		// dial a connection of their own if it is not set.
+		GRPCConn *GRPCConn
+
+		// Memo is the optional memo template of the broadcasted
+		// transactions.
+		Memo *Memo
+
+		// VoteBuilder builds the prevote and vote messages of the chain's
+		// oracle module.
+		VoteBuilder OracleVoteBuilder
 	}
 
 	passReader struct {
 		pass string
 		buf  *bytes.Buffer
 	}
+
+	// txQuerier queries the result of a transaction by its hash, ex. the RPC
+	// client of the node.
+	txQuerier interface {
+		Tx(ctx context.Context, hash []byte, prove bool) (*tmctypes.ResultTx, error)
+	}
 )
 
 func NewOracleClient(
@@ -91,6 +128,7 @@ func NewOracleClient(
 		GasAdjustment:       gasAdjustment,
 		Gas:                 gas,
 		GRPCEndpoint:        grpcEndpoint,
+		VoteBuilder:         UmeeVoteBuilder{},
 	}
 
 	clientCtx, err := oracleClient.CreateClientContext()
@@ -108,6 +146,7 @@ func NewOracleClient(
 		clientCtx.Client,
 		oracleClient.Logger,
 		blockHeight,
+		oracleClient.RPCTimeout,
 	)
 	if err != nil {
 		return OracleClient{}, err
@@ -137,26 +176,38 @@ func (r *passReader) Read(p []byte) (n int, err error) {
 
 // BroadcastTx attempts to broadcast a signed transaction. If it fails, a few re-attempts
 // will be made until the transaction succeeds or ultimately times out or fails.
+// Its inclusion is then polled for until the chain passes confirmHeight, the
+// end of the voting window, and the height of the block the transaction was
+// included in is returned. ErrTxUnconfirmed is returned if the inclusion of
+// the broadcasted transaction could not be queried.
 // Ref: https://github.com/terra-money/oracle-feeder/blob/baef2a4a02f57a2ffeaa207932b2e03d7fb0fb25/feeder/src/vote.ts#L230
-func (oc OracleClient) BroadcastTx(nextBlockHeight, timeoutHeight int64, msgs ...sdk.Msg) error {
+func (oc OracleClient) BroadcastTx(nextBlockHeight, timeoutHeight, confirmHeight int64, msgs ...sdk.Msg) (int64, error) {
 	maxBlockHeight := nextBlockHeight + timeoutHeight
 	lastCheckHeight := nextBlockHeight - 1
 
 	clientCtx, err := oc.CreateClientContext()
 	if err != nil {
-		return err
+		return 0, err
 	}
 
 	factory, err := oc.CreateTxFactory()
 	if err != nil {
-		return err
+		return 0, err
+	}
+
+	if oc.Memo != nil {
+		memo, err := oc.Memo.Render(nextBlockHeight)
+		if err != nil {
+			return 0, err
+		}
+		factory = factory.WithMemo(memo)
 	}
 
 	// re-try voting until timeout
 	for lastCheckHeight < maxBlockHeight {
 		latestBlockHeight, err := oc.ChainHeight.GetChainHeight()
 		if err != nil {
-			return err
+			return 0, err
 		}
 
 		if latestBlockHeight <= lastCheckHeight {
@@ -199,16 +250,129 @@ func (oc OracleClient) BroadcastTx(nextBlockHeight, timeoutHeight int64, msgs ..
 			Int64("tx_height", resp.Height).
 			Msg("successfully broadcasted tx")
 
-		return nil
+		if confirmHeight > maxBlockHeight {
+			confirmHeight = maxBlockHeight
+		}
+		txHeight, err := oc.confirmTx(clientCtx.Client, resp.TxHash, confirmHeight)
+		switch {
+		case errors.Is(err, ErrTxUnconfirmed):
+			oc.Logger.Warn().Err(err).Str("tx_hash", resp.TxHash).Msg("failed to confirm tx inclusion")
+			if oc.containsVoteMsg(msgs) {
+				telemetry.IncrCounter(1, "votes", "unconfirmed", "total")
+			}
+			return 0, err
+		case err != nil:
+			if oc.containsVoteMsg(msgs) {
+				telemetry.IncrCounter(1, "votes", "failed", "total")
+			}
+			return 0, err
+		}
+		if oc.containsVoteMsg(msgs) {
+			telemetry.IncrCounter(1, "votes", "confirmed", "total")
+		}
+
+		return txHeight, nil
 	}
 
 	telemetry.IncrCounter(1, "failure", "tx", "timeout")
-	return errors.New("broadcasting tx timed out")
+	return 0, errors.New("broadcasting tx timed out")
 }
 
-// CreateClientContext creates an SDK client Context instance used for transaction
-// generation, signing and broadcasting.
-func (oc OracleClient) CreateClientContext() (client.Context, error) {
+// confirmTx polls the node for the result of the transaction with the given
+// hash until it has been included in a block and returns the block's height.
+// The polling is bounded by the chain passing confirmHeight, and by the time
+// the remaining blocks take at the average block time, so a stalled chain
+// height can't hold the oracle. It returns an error if the transaction failed
+// on chain or was not found in time, and ErrTxUnconfirmed if the queries kept
+// failing for another reason than the transaction not being found yet.
+func (oc OracleClient) confirmTx(querier txQuerier, txHash string, confirmHeight int64) (int64, error) {
+	hash, err := hex.DecodeString(txHash)
+	if err != nil {
+		return 0, fmt.Errorf("failed to decode tx hash %s: %w", txHash, err)
+	}
+
+	var queryErrors int
+	for {
+		latestBlockHeight, err := oc.ChainHeight.GetChainHeight()
+		if err != nil {
+			return 0, err
+		}
+		if latestBlockHeight > confirmHeight {
+			return 0, fmt.Errorf("tx %s was not included before height %d", txHash, confirmHeight)
+		}
+		deadline := oc.confirmDeadline(confirmHeight - latestBlockHeight + 1)
+		if !deadline.IsZero() && time.Now().After(deadline) {
+			return 0, fmt.Errorf("tx %s was not included before height %d: chain height stalled", txHash, confirmHeight)
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), oc.RPCTimeout)
+		res, err := querier.Tx(ctx, hash, false)
+		cancel()
+		switch {
+		case err == nil:
+			if res.TxResult.Code != 0 {
+				return 0, fmt.Errorf("tx %s failed on chain with code %d: %s", txHash, res.TxResult.Code, res.TxResult.Log)
+			}
+
+			oc.Logger.Info().
+				Str("tx_hash", txHash).
+				Int64("tx_height", res.Height).
+				Msg("confirmed tx inclusion")
+			return res.Height, nil
+
+		case isTxNotFound(err):
+			queryErrors = 0
+
+		default:
+			queryErrors++
+			if queryErrors >= txConfirmMaxQueryErrors {
+				return 0, fmt.Errorf("%w: %s: %s", ErrTxUnconfirmed, txHash, err)
+			}
+		}
+
+		time.Sleep(txConfirmPollInterval)
+	}
+}
+
+// confirmDeadline returns when the given number of blocks are expected to
+// have passed at the average block time, or the zero time until the block
+// time is known.
+func (oc OracleClient) confirmDeadline(blocks int64) time.Time {
+	lastBlockReceived, blockTime := oc.ChainHeight.GetBlockTiming()
+	if blockTime <= 0 || lastBlockReceived.IsZero() {
+		return time.Time{}
+	}
+	return lastBlockReceived.Add(time.Duration(blocks) * blockTime)
+}
+
+// isTxNotFound returns whether the error of a tx query is the transaction not
+// being indexed yet.
+func isTxNotFound(err error) bool {
+	return strings.Contains(err.Error(), "not found")
+}
+
+// GetVoteBuilder returns the builder of the oracle messages, the Umee builder
+// if none is set.
+func (oc OracleClient) GetVoteBuilder() OracleVoteBuilder {
+	if oc.VoteBuilder == nil {
+		return UmeeVoteBuilder{}
+	}
+	return oc.VoteBuilder
+}
+
+// containsVoteMsg returns true if any of the given messages is a vote message.
+func (oc OracleClient) containsVoteMsg(msgs []sdk.Msg) bool {
+	voteBuilder := oc.GetVoteBuilder()
+	for _, msg := range msgs {
+		if voteBuilder.IsVoteMsg(msg) {
+			return true
+		}
+	}
+	return false
+}
+
+// keyring opens the keyring holding the feeder key.
+func (oc OracleClient) keyring() (keyring.Keyring, error) {
 	var keyringInput io.Reader
 	if len(oc.KeyringPass) > 0 {
 		keyringInput = newPassReader(oc.KeyringPass)
@@ -216,7 +380,23 @@ func (oc OracleClient) CreateClientContext() (client.Context, error) {
 		keyringInput = os.Stdin
 	}
 
-	kr, err := keyring.New("oracle", oc.KeyringBackend, oc.KeyringDir, keyringInput, oc.Encoding.Codec)
+	return keyring.New("oracle", oc.KeyringBackend, oc.KeyringDir, keyringInput, oc.Encoding.Codec)
+}
+
+// SignBytes signs arbitrary bytes with the feeder key, ex. an off-chain price
+// attestation, and returns the signature and the feeder public key.
+func (oc OracleClient) SignBytes(msg []byte) ([]byte, cryptotypes.PubKey, error) {
+	kr, err := oc.keyring()
+	if err != nil {
+		return nil, nil, err
+	}
+	return kr.SignByAddress(oc.OracleAddr, msg)
+}
+
+// CreateClientContext creates an SDK client Context instance used for transaction
+// generation, signing and broadcasting.
+func (oc OracleClient) CreateClientContext() (client.Context, error) {
+	kr, err := oc.keyring()
 	if err != nil {
 		return client.Context{}, err
 	}
@@ -264,6 +444,43 @@ func (oc OracleClient) CreateClientContext() (client.Context, error) {
 	return clientCtx, nil
 }
 
+// CheckClockSkew measures the skew between the local clock and the time of the
+// latest block, and returns an error if it exceeds maxClockSkew.
+func (oc OracleClient) CheckClockSkew(ctx context.Context, maxClockSkew time.Duration) (time.Duration, error) {
+	clientCtx, err := oc.CreateClientContext()
+	if err != nil {
+		return 0, err
+	}
+
+	block, err := clientCtx.Client.Block(ctx, nil)
+	if err != nil {
+		return 0, fmt.Errorf("failed to query the latest block: %w", err)
+	}
+
+	clockSkew := time.Since(block.Block.Header.Time)
+	if absDuration(clockSkew) > maxClockSkew {
+		return clockSkew, fmt.Errorf(
+			"local clock is skewed by %s from the latest block time, exceeding the maximum of %s",
+			clockSkew,
+			maxClockSkew,
+		)
+	}
+	return clockSkew, nil
+}
+
+// CheckAccount returns an error unless the feeder account exists on chain.
+func (oc OracleClient) CheckAccount() error {
+	clientCtx, err := oc.CreateClientContext()
+	if err != nil {
+		return err
+	}
+
+	if err := clientCtx.AccountRetriever.EnsureExists(clientCtx, oc.OracleAddr); err != nil {
+		return fmt.Errorf("failed to find feeder account %s: %w", oc.OracleAddrString, err)
+	}
+	return nil
+}
+
 // CreateTxFactory creates an SDK Factory instance used for transaction
 // generation, signing and broadcasting.
 func (oc OracleClient) CreateTxFactory() (tx.Factory, error) {
@@ -0,0 +1,130 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	abci "github.com/cometbft/cometbft/abci/types"
+	tmctypes "github.com/cometbft/cometbft/rpc/core/types"
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/require"
+)
+
+const testTxHash = "0A1B2C3D"
+
+// mockTxQuerier returns its results in turn, the last one repeatedly, and
+// advances the chain height by one block per query.
+type mockTxQuerier struct {
+	chainHeight *ChainHeight
+	results     []mockTxResult
+	queries     int
+}
+
+type mockTxResult struct {
+	res *tmctypes.ResultTx
+	err error
+}
+
+func (m *mockTxQuerier) Tx(context.Context, []byte, bool) (*tmctypes.ResultTx, error) {
+	result := m.results[len(m.results)-1]
+	if m.queries < len(m.results) {
+		result = m.results[m.queries]
+	}
+	m.queries++
+
+	m.chainHeight.mtx.Lock()
+	m.chainHeight.lastChainHeight++
+	m.chainHeight.mtx.Unlock()
+
+	return result.res, result.err
+}
+
+func notFound() mockTxResult {
+	return mockTxResult{err: fmt.Errorf("tx (%s) not found", testTxHash)}
+}
+
+func included(height int64, code uint32) mockTxResult {
+	return mockTxResult{res: &tmctypes.ResultTx{
+		Height:   height,
+		TxResult: abci.ResponseDeliverTx{Code: code, Log: "out of gas"},
+	}}
+}
+
+func TestOracleClient_confirmTx(t *testing.T) {
+	testCases := []struct {
+		name           string
+		results        []mockTxResult
+		confirmHeight  int64
+		expHeight      int64
+		expQueries     int
+		expectErr      bool
+		expUnconfirmed bool
+	}{
+		{
+			name:          "confirmed",
+			results:       []mockTxResult{notFound(), included(11, 0)},
+			confirmHeight: 20,
+			expHeight:     11,
+			expQueries:    2,
+		},
+		{
+			name:          "failed on chain",
+			results:       []mockTxResult{included(11, 11)},
+			confirmHeight: 20,
+			expQueries:    1,
+			expectErr:     true,
+		},
+		{
+			name:          "not included before the end of the window",
+			results:       []mockTxResult{notFound()},
+			confirmHeight: 11,
+			expQueries:    2,
+			expectErr:     true,
+		},
+		{
+			name:           "query errors persist",
+			results:        []mockTxResult{{err: errors.New("transaction indexing is disabled")}},
+			confirmHeight:  20,
+			expQueries:     txConfirmMaxQueryErrors,
+			expectErr:      true,
+			expUnconfirmed: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			chainHeight := &ChainHeight{Logger: zerolog.Nop(), lastChainHeight: 10}
+			querier := &mockTxQuerier{chainHeight: chainHeight, results: tc.results}
+			oc := OracleClient{Logger: zerolog.Nop(), RPCTimeout: time.Second, ChainHeight: chainHeight}
+
+			height, err := oc.confirmTx(querier, testTxHash, tc.confirmHeight)
+			require.Equal(t, tc.expQueries, querier.queries)
+			if tc.expectErr {
+				require.Error(t, err)
+				require.Equal(t, tc.expUnconfirmed, errors.Is(err, ErrTxUnconfirmed))
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tc.expHeight, height)
+		})
+	}
+}
+
+func TestOracleClient_confirmTxStalledChain(t *testing.T) {
+	// the last block was received longer ago than the remaining blocks take
+	chainHeight := &ChainHeight{
+		Logger:            zerolog.Nop(),
+		lastChainHeight:   10,
+		lastBlockReceived: time.Now().Add(-time.Minute),
+		blockTime:         5 * time.Second,
+	}
+	querier := &mockTxQuerier{chainHeight: chainHeight, results: []mockTxResult{notFound()}}
+	oc := OracleClient{Logger: zerolog.Nop(), RPCTimeout: time.Second, ChainHeight: chainHeight}
+
+	_, err := oc.confirmTx(querier, testTxHash, 12)
+	require.Error(t, err)
+	require.Zero(t, querier.queries)
+}
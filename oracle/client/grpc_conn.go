@@ -0,0 +1,141 @@
+package client
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/cosmos/cosmos-sdk/client/grpc/tmservice"
+	"github.com/cosmos/cosmos-sdk/telemetry"
+	"github.com/rs/zerolog"
+	"google.golang.org/grpc"
+)
+
+const (
+	// grpcHealthCheckInterval defines how often the gRPC connection to the
+	// node is health-checked.
+	grpcHealthCheckInterval = 15 * time.Second
+
+	// grpcHealthCheckTimeout defines how long the health check query may take
+	// before the connection is considered wedged and re-dialed.
+	grpcHealthCheckTimeout = 5 * time.Second
+)
+
+// GRPCConn holds a gRPC connection to the node. A connection can get wedged,
+// with every query hanging until its deadline, so GRPCConn periodically runs a
+// lightweight query with a short timeout and re-dials the connection when it
+// fails.
+type GRPCConn struct {
+	Logger zerolog.Logger
+
+	endpoint    string
+	dialOptions []grpc.DialOption
+	healthCheck func(ctx context.Context, conn *grpc.ClientConn) error
+	timeout     time.Duration
+
+	mtx  sync.RWMutex
+	conn *grpc.ClientConn
+}
+
+// NewGRPCConn dials the gRPC endpoint of the node and starts a goroutine
+// health-checking the connection until the context is canceled.
+func NewGRPCConn(
+	ctx context.Context,
+	logger zerolog.Logger,
+	endpoint string,
+	dialOptions ...grpc.DialOption,
+) (*GRPCConn, error) {
+	conn, err := grpc.Dial(endpoint, dialOptions...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to dial Cosmos gRPC service: %w", err)
+	}
+
+	grpcConn := &GRPCConn{
+		Logger:      logger.With().Str("oracle_client", "grpc_conn").Logger(),
+		endpoint:    endpoint,
+		dialOptions: dialOptions,
+		healthCheck: querySyncing,
+		timeout:     grpcHealthCheckTimeout,
+		conn:        conn,
+	}
+
+	go grpcConn.monitor(ctx, grpcHealthCheckInterval)
+
+	return grpcConn, nil
+}
+
+// querySyncing queries the syncing status of the node, a cheap query served
+// by every node.
+func querySyncing(ctx context.Context, conn *grpc.ClientConn) error {
+	_, err := tmservice.NewServiceClient(conn).GetSyncing(ctx, &tmservice.GetSyncingRequest{})
+	return err
+}
+
+// Conn returns the current gRPC connection to the node.
+func (c *GRPCConn) Conn() *grpc.ClientConn {
+	c.mtx.RLock()
+	defer c.mtx.RUnlock()
+
+	return c.conn
+}
+
+// CheckHealth runs the health check query against the current connection and
+// re-dials it if the query fails or times out.
+func (c *GRPCConn) CheckHealth(ctx context.Context) error {
+	conn := c.Conn()
+
+	checkCtx, cancel := context.WithTimeout(ctx, c.timeout)
+	err := c.healthCheck(checkCtx, conn)
+	cancel()
+	if err == nil || ctx.Err() != nil {
+		return nil
+	}
+
+	c.Logger.Warn().Err(err).Str("endpoint", c.endpoint).Msg("gRPC connection unhealthy; re-dialing")
+	return c.reconnect(conn)
+}
+
+// reconnect replaces the given connection with a newly dialed one, unless it
+// was already replaced.
+func (c *GRPCConn) reconnect(conn *grpc.ClientConn) error {
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+
+	if c.conn != conn {
+		return nil
+	}
+
+	newConn, err := grpc.Dial(c.endpoint, c.dialOptions...)
+	if err != nil {
+		return fmt.Errorf("failed to re-dial Cosmos gRPC service: %w", err)
+	}
+	c.conn = newConn
+	telemetry.IncrCounter(1, "grpc", "reconnects")
+
+	if err := conn.Close(); err != nil {
+		c.Logger.Debug().Err(err).Msg("failed to close the previous gRPC connection")
+	}
+	return nil
+}
+
+// monitor health-checks the connection every interval until the context is
+// canceled, and closes the connection then.
+func (c *GRPCConn) monitor(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			if err := c.Conn().Close(); err != nil {
+				c.Logger.Debug().Err(err).Msg("failed to close the gRPC connection")
+			}
+			return
+		case <-ticker.C:
+			if err := c.CheckHealth(ctx); err != nil {
+				c.Logger.Error().Err(err).Msg("failed to recover the gRPC connection")
+			}
+		}
+	}
+}
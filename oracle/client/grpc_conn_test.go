@@ -0,0 +1,76 @@
+package client
+
+import (
+	"context"
+	"net"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/cosmos/cosmos-sdk/client/grpc/tmservice"
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+)
+
+// hangingServiceServer is a node gRPC service whose queries hang until their
+// deadline while hang is set.
+type hangingServiceServer struct {
+	tmservice.UnimplementedServiceServer
+	hang  atomic.Bool
+	calls atomic.Int32
+}
+
+func (s *hangingServiceServer) GetSyncing(
+	ctx context.Context,
+	_ *tmservice.GetSyncingRequest,
+) (*tmservice.GetSyncingResponse, error) {
+	s.calls.Add(1)
+	if s.hang.Load() {
+		<-ctx.Done()
+		return nil, ctx.Err()
+	}
+	return &tmservice.GetSyncingResponse{}, nil
+}
+
+func TestGRPCConn_CheckHealth(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+
+	stub := &hangingServiceServer{}
+	server := grpc.NewServer()
+	tmservice.RegisterServiceServer(server, stub)
+	go func() { _ = server.Serve(listener) }()
+	t.Cleanup(server.Stop)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(cancel)
+
+	grpcConn, err := NewGRPCConn(
+		ctx,
+		zerolog.Nop(),
+		listener.Addr().String(),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	)
+	require.NoError(t, err)
+	grpcConn.timeout = 100 * time.Millisecond
+
+	// a healthy connection is kept
+	conn := grpcConn.Conn()
+	require.NoError(t, grpcConn.CheckHealth(ctx))
+	require.Equal(t, int32(1), stub.calls.Load())
+	require.Same(t, conn, grpcConn.Conn())
+
+	// a wedged connection is re-dialed once the health check times out
+	stub.hang.Store(true)
+	require.NoError(t, grpcConn.CheckHealth(ctx))
+	require.Equal(t, int32(2), stub.calls.Load())
+	require.NotSame(t, conn, grpcConn.Conn())
+
+	// and the new connection serves queries once the node recovers
+	stub.hang.Store(false)
+	conn = grpcConn.Conn()
+	require.NoError(t, grpcConn.CheckHealth(ctx))
+	require.Same(t, conn, grpcConn.Conn())
+}
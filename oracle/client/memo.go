@@ -0,0 +1,70 @@
+package client
+
+import (
+	"fmt"
+	"math"
+	"strings"
+	"text/template"
+)
+
+// MaxMemoLength is the maximum length of a transaction memo allowed by the
+// default max_memo_characters parameter of the auth module.
+const MaxMemoLength = 256
+
+type (
+	// Memo defines the memo template of the transactions broadcasted by the
+	// oracle client.
+	Memo struct {
+		tmpl        *template.Template
+		instanceID  string
+		environment string
+	}
+
+	// MemoData defines the variables available to a memo template, e.g.
+	// "feeder {{.InstanceID}} at {{.Height}}".
+	MemoData struct {
+		Height      int64
+		InstanceID  string
+		Environment string
+	}
+)
+
+// NewMemo parses the memo template and returns an error if the rendered memo
+// can exceed MaxMemoLength.
+func NewMemo(text, instanceID, environment string) (*Memo, error) {
+	tmpl, err := template.New("memo").Parse(text)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse memo template: %w", err)
+	}
+
+	memo := &Memo{
+		tmpl:        tmpl,
+		instanceID:  instanceID,
+		environment: environment,
+	}
+
+	// the largest height gives the longest memo
+	longest, err := memo.Render(math.MaxInt64)
+	if err != nil {
+		return nil, err
+	}
+	if len(longest) > MaxMemoLength {
+		return nil, fmt.Errorf("memo can be %d characters long, exceeding the maximum of %d", len(longest), MaxMemoLength)
+	}
+
+	return memo, nil
+}
+
+// Render returns the memo of a transaction broadcasted at the given height.
+func (m *Memo) Render(height int64) (string, error) {
+	var sb strings.Builder
+	err := m.tmpl.Execute(&sb, MemoData{
+		Height:      height,
+		InstanceID:  m.instanceID,
+		Environment: m.environment,
+	})
+	if err != nil {
+		return "", fmt.Errorf("failed to render memo: %w", err)
+	}
+	return sb.String(), nil
+}
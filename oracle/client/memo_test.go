@@ -0,0 +1,39 @@
+package client
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestMemo_Render(t *testing.T) {
+	memo, err := NewMemo("price-feeder {{.InstanceID}}/{{.Environment}} at {{.Height}}", "feeder-01", "mainnet")
+	require.NoError(t, err)
+
+	rendered, err := memo.Render(1234)
+	require.NoError(t, err)
+	require.Equal(t, "price-feeder feeder-01/mainnet at 1234", rendered)
+
+	memo, err = NewMemo("config abc123", "", "")
+	require.NoError(t, err)
+
+	rendered, err = memo.Render(1234)
+	require.NoError(t, err)
+	require.Equal(t, "config abc123", rendered)
+}
+
+func TestNewMemo_Invalid(t *testing.T) {
+	_, err := NewMemo("{{.Height", "", "")
+	require.Error(t, err)
+
+	_, err = NewMemo("{{.Unknown}}", "", "")
+	require.Error(t, err)
+
+	_, err = NewMemo(strings.Repeat("a", MaxMemoLength), "", "")
+	require.NoError(t, err)
+
+	// the height can add up to 19 characters
+	_, err = NewMemo(strings.Repeat("a", MaxMemoLength-10)+"{{.Height}}", "", "")
+	require.Error(t, err)
+}
@@ -0,0 +1,94 @@
+package client
+
+import (
+	"fmt"
+	"sort"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	oracletypes "github.com/umee-network/umee/v6/x/oracle/types"
+)
+
+// VoteBuilderUmee defines the vote message format of the Umee oracle module,
+// the default format.
+const VoteBuilderUmee = "umee"
+
+// voteBuilders maps the supported vote message formats to their builder. A
+// chain with a different format is supported by adding its builder here.
+var voteBuilders = map[string]OracleVoteBuilder{
+	VoteBuilderUmee: UmeeVoteBuilder{},
+}
+
+type (
+	// OracleVoteBuilder defines the construction of the prevote and vote
+	// messages of a chain's oracle module.
+	OracleVoteBuilder interface {
+		// PrevoteHash returns the hash committed to by the prevote of the
+		// exchange rates.
+		PrevoteHash(salt, exchangeRates string, validator sdk.ValAddress) string
+
+		// PrevoteMsg returns the prevote message of the hash.
+		PrevoteMsg(hash, feeder, validator string) sdk.Msg
+
+		// VoteMsg returns the vote message revealing the prevoted exchange
+		// rates.
+		VoteMsg(salt, exchangeRates, feeder, validator string) sdk.Msg
+
+		// IsVoteMsg returns true if the message is a vote message.
+		IsVoteMsg(msg sdk.Msg) bool
+	}
+
+	// UmeeVoteBuilder builds the aggregate exchange rate messages of the Umee
+	// oracle module.
+	UmeeVoteBuilder struct{}
+)
+
+// NewOracleVoteBuilder returns the builder of the given vote message format,
+// the Umee format if none is given.
+func NewOracleVoteBuilder(name string) (OracleVoteBuilder, error) {
+	if name == "" {
+		return UmeeVoteBuilder{}, nil
+	}
+
+	builder, ok := voteBuilders[name]
+	if !ok {
+		return nil, fmt.Errorf("unsupported vote builder %s, expected one of %v", name, SupportedVoteBuilders())
+	}
+	return builder, nil
+}
+
+// SupportedVoteBuilders returns the sorted names of the supported vote message
+// formats.
+func SupportedVoteBuilders() []string {
+	names := make([]string, 0, len(voteBuilders))
+	for name := range voteBuilders {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+func (UmeeVoteBuilder) PrevoteHash(salt, exchangeRates string, validator sdk.ValAddress) string {
+	return oracletypes.GetAggregateVoteHash(salt, exchangeRates, validator).String()
+}
+
+func (UmeeVoteBuilder) PrevoteMsg(hash, feeder, validator string) sdk.Msg {
+	return &oracletypes.MsgAggregateExchangeRatePrevote{
+		Hash:      hash,
+		Feeder:    feeder,
+		Validator: validator,
+	}
+}
+
+func (UmeeVoteBuilder) VoteMsg(salt, exchangeRates, feeder, validator string) sdk.Msg {
+	return &oracletypes.MsgAggregateExchangeRateVote{
+		Salt:          salt,
+		ExchangeRates: exchangeRates,
+		Feeder:        feeder,
+		Validator:     validator,
+	}
+}
+
+func (UmeeVoteBuilder) IsVoteMsg(msg sdk.Msg) bool {
+	_, ok := msg.(*oracletypes.MsgAggregateExchangeRateVote)
+	return ok
+}
@@ -0,0 +1,53 @@
+package client
+
+import (
+	"testing"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/stretchr/testify/require"
+	oracletypes "github.com/umee-network/umee/v6/x/oracle/types"
+)
+
+func TestNewOracleVoteBuilder(t *testing.T) {
+	builder, err := NewOracleVoteBuilder("")
+	require.NoError(t, err)
+	require.Equal(t, UmeeVoteBuilder{}, builder)
+
+	builder, err = NewOracleVoteBuilder(VoteBuilderUmee)
+	require.NoError(t, err)
+	require.Equal(t, UmeeVoteBuilder{}, builder)
+
+	_, err = NewOracleVoteBuilder("terra")
+	require.Error(t, err)
+}
+
+func TestUmeeVoteBuilder(t *testing.T) {
+	var (
+		builder   UmeeVoteBuilder
+		valAddr   = sdk.ValAddress([]byte("validator"))
+		salt      = "a1b2"
+		rates     = "ATOM:10.000000000000000000,UMEE:0.010000000000000000"
+		feeder    = "feeder"
+		validator = valAddr.String()
+	)
+
+	hash := builder.PrevoteHash(salt, rates, valAddr)
+	require.Equal(t, oracletypes.GetAggregateVoteHash(salt, rates, valAddr).String(), hash)
+
+	prevote := builder.PrevoteMsg(hash, feeder, validator)
+	require.Equal(t, &oracletypes.MsgAggregateExchangeRatePrevote{
+		Hash:      hash,
+		Feeder:    feeder,
+		Validator: validator,
+	}, prevote)
+	require.False(t, builder.IsVoteMsg(prevote))
+
+	vote := builder.VoteMsg(salt, rates, feeder, validator)
+	require.Equal(t, &oracletypes.MsgAggregateExchangeRateVote{
+		Salt:          salt,
+		ExchangeRates: rates,
+		Feeder:        feeder,
+		Validator:     validator,
+	}, vote)
+	require.True(t, builder.IsVoteMsg(vote))
+}
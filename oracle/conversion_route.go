@@ -0,0 +1,115 @@
+package oracle
+
+import (
+	"github.com/armon/go-metrics"
+	"github.com/cosmos/cosmos-sdk/telemetry"
+
+	"github.com/ojo-network/price-feeder/config"
+	"github.com/ojo-network/price-feeder/oracle/types"
+)
+
+// SetConversionRoutes sets the quotes, in order of preference, the USD rates
+// of the assets are converted from. The pairs of an asset quoted in its first
+// quote which yields a rate are used, instead of all of its pairs.
+func (o *Oracle) SetConversionRoutes(conversionRoutes map[string][]string) {
+	o.conversionRoutes = conversionRoutes
+}
+
+// calcRoutedRates computes the USD rate of each asset with a conversion route
+// from its pairs quoted in the first quote of the route which yields a rate,
+// trying the next quote while the pairs of a quote have no fresh candles or
+// tickers or the quote has no USD rate. Assets whose quotes all fail have no
+// rate.
+func (o *Oracle) calcRoutedRates(
+	candles types.AggregatedProviderCandles,
+	tickers types.AggregatedProviderPrices,
+	usdRates types.CurrencyPairDec,
+) (types.CurrencyPairDec, error) {
+	rates := make(types.CurrencyPairDec, len(o.conversionRoutes))
+	for base, quotes := range o.conversionRoutes {
+		usdPair := types.CurrencyPair{Base: base, Quote: config.DenomUSD}
+		for i, quote := range quotes {
+			cp := types.CurrencyPair{Base: base, Quote: quote}
+			routeCandles := FilterCandlePriceBands(
+				o.logger,
+				ConvertAggregatedCandles(filterPairCandles(candles, cp), usdRates),
+				o.priceBands,
+			)
+			routeTickers := FilterTickerPriceBands(
+				o.logger,
+				ConvertAggregatedTickers(filterPairTickers(tickers, cp), usdRates),
+				o.priceBands,
+			)
+
+			routeRates, err := o.calcRates(routeCandles, routeTickers, []types.CurrencyPair{usdPair})
+			if err != nil {
+				return nil, err
+			}
+			rate, ok := routeRates[usdPair]
+			if !ok {
+				continue
+			}
+
+			rates[usdPair] = rate
+			if i > 0 {
+				o.logger.Warn().
+					Str("asset", usdPair.String()).
+					Str("quote", quote).
+					Strs("preferred_quotes", quotes[:i]).
+					Msg("preferred conversion routes yielded no rate; falling back")
+				telemetry.IncrCounterWithLabels(
+					[]string{"conversion_route", "fallback"},
+					1,
+					[]metrics.Label{{Name: "base", Value: base}, {Name: "quote", Value: quote}},
+				)
+			}
+			break
+		}
+	}
+	return rates, nil
+}
+
+// applyRoutedRates sets the USD rates of the assets with a conversion route to
+// their routed rate, or removes them if they have none.
+func applyRoutedRates(
+	rates types.CurrencyPairDec,
+	routedRates types.CurrencyPairDec,
+	conversionRoutes map[string][]string,
+) {
+	for base := range conversionRoutes {
+		usdPair := types.CurrencyPair{Base: base, Quote: config.DenomUSD}
+		if rate, ok := routedRates[usdPair]; ok {
+			rates[usdPair] = rate
+		} else {
+			delete(rates, usdPair)
+		}
+	}
+}
+
+// filterPairCandles returns the candles of the currency pair only.
+func filterPairCandles(
+	candles types.AggregatedProviderCandles,
+	cp types.CurrencyPair,
+) types.AggregatedProviderCandles {
+	filtered := make(types.AggregatedProviderCandles)
+	for providerName, pairCandles := range candles {
+		if cc, ok := pairCandles[cp]; ok {
+			filtered[providerName] = types.CurrencyPairCandles{cp: cc}
+		}
+	}
+	return filtered
+}
+
+// filterPairTickers returns the tickers of the currency pair only.
+func filterPairTickers(
+	tickers types.AggregatedProviderPrices,
+	cp types.CurrencyPair,
+) types.AggregatedProviderPrices {
+	filtered := make(types.AggregatedProviderPrices)
+	for providerName, pairTickers := range tickers {
+		if ticker, ok := pairTickers[cp]; ok {
+			filtered[providerName] = types.CurrencyPairTickers{cp: ticker}
+		}
+	}
+	return filtered
+}
@@ -0,0 +1,100 @@
+package oracle
+
+import (
+	"testing"
+	"time"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ojo-network/price-feeder/oracle/provider"
+	"github.com/ojo-network/price-feeder/oracle/types"
+)
+
+func TestOracle_calcRoutedRates(t *testing.T) {
+	fooUSDT := types.CurrencyPair{Base: "FOO", Quote: "USDT"}
+	fooBTC := types.CurrencyPair{Base: "FOO", Quote: "BTC"}
+	fooETH := types.CurrencyPair{Base: "FOO", Quote: "ETH"}
+	fooUSD := types.CurrencyPair{Base: "FOO", Quote: "USD"}
+	candle := func(price string, age time.Duration) types.CandlePrice {
+		return types.CandlePrice{
+			Price:     sdk.MustNewDecFromStr(price),
+			Volume:    sdk.OneDec(),
+			TimeStamp: provider.PastUnixTime(age),
+		}
+	}
+
+	// the TVWAP of a candle deviates from its price by its time weighting
+	requireRoutedRate := func(t *testing.T, expected string, rates types.CurrencyPairDec) {
+		t.Helper()
+		require.Len(t, rates, 1)
+		require.InDelta(t, sdk.MustNewDecFromStr(expected).MustFloat64(), rates[fooUSD].MustFloat64(), 1e-9)
+	}
+
+	o := &Oracle{
+		logger:           zerolog.Nop(),
+		zeroVolumeWeight: sdk.ZeroDec(),
+	}
+	o.SetConversionRoutes(map[string][]string{"FOO": {"USDT", "BTC", "ETH"}})
+
+	usdRates := types.CurrencyPairDec{
+		{Base: "USDT", Quote: "USD"}: sdk.OneDec(),
+		{Base: "BTC", Quote: "USD"}:  sdk.MustNewDecFromStr("30000"),
+		{Base: "ETH", Quote: "USD"}:  sdk.MustNewDecFromStr("2000"),
+	}
+	candles := types.AggregatedProviderCandles{
+		provider.ProviderBinance: {
+			fooUSDT: {candle("1.01", time.Minute)},
+			fooBTC:  {candle("0.00004", time.Minute)},
+		},
+		provider.ProviderKraken: {
+			fooETH: {candle("0.0006", time.Minute)},
+		},
+	}
+
+	// the preferred route is used while it yields a rate
+	rates, err := o.calcRoutedRates(candles, types.AggregatedProviderPrices{}, usdRates)
+	require.NoError(t, err)
+	requireRoutedRate(t, "1.01", rates)
+
+	// the USDT market stopped trading, so its candles are stale and the BTC
+	// route is used
+	candles[provider.ProviderBinance][fooUSDT] = []types.CandlePrice{candle("1.01", time.Hour)}
+	rates, err = o.calcRoutedRates(candles, types.AggregatedProviderPrices{}, usdRates)
+	require.NoError(t, err)
+	requireRoutedRate(t, "1.2", rates)
+
+	// then the ETH route once BTC has no USD rate either
+	delete(usdRates, types.CurrencyPair{Base: "BTC", Quote: "USD"})
+	rates, err = o.calcRoutedRates(candles, types.AggregatedProviderPrices{}, usdRates)
+	require.NoError(t, err)
+	requireRoutedRate(t, "1.2", rates)
+
+	// and the asset has no rate once every route failed
+	delete(candles, provider.ProviderKraken)
+	rates, err = o.calcRoutedRates(candles, types.AggregatedProviderPrices{}, usdRates)
+	require.NoError(t, err)
+	require.Empty(t, rates)
+}
+
+func TestApplyRoutedRates(t *testing.T) {
+	fooUSD := types.CurrencyPair{Base: "FOO", Quote: "USD"}
+	barUSD := types.CurrencyPair{Base: "BAR", Quote: "USD"}
+	atomUSD := types.CurrencyPair{Base: "ATOM", Quote: "USD"}
+
+	rates := types.CurrencyPairDec{
+		fooUSD:  sdk.MustNewDecFromStr("1.1"),
+		barUSD:  sdk.MustNewDecFromStr("2"),
+		atomUSD: sdk.MustNewDecFromStr("10"),
+	}
+	applyRoutedRates(
+		rates,
+		types.CurrencyPairDec{fooUSD: sdk.MustNewDecFromStr("1.2")},
+		map[string][]string{"FOO": {"BTC"}, "BAR": {"USDT"}},
+	)
+	require.Equal(t, types.CurrencyPairDec{
+		fooUSD:  sdk.MustNewDecFromStr("1.2"),
+		atomUSD: sdk.MustNewDecFromStr("10"),
+	}, rates)
+}
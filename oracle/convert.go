@@ -5,6 +5,7 @@ import (
 	"github.com/rs/zerolog"
 
 	"github.com/ojo-network/price-feeder/config"
+	"github.com/ojo-network/price-feeder/oracle/provider"
 	"github.com/ojo-network/price-feeder/oracle/types"
 )
 
@@ -33,14 +34,21 @@ func ConvertRatesToUSD(rates types.CurrencyPairDec) types.CurrencyPairDec {
 // list provided, then filters candles/tickers outside of the deviation threshold,
 // and finally computes the rates for the given currency pairs using TVWAP for candles
 // and VWAP for tickers. It will first compute rates with candles and then attempt
-// to fill in any missing prices with ticker data.
+// to fill in any missing prices with ticker data. Forex rates are computed
+// separately by CalcForexRates.
 func CalcCurrencyPairRates(
 	candles types.AggregatedProviderCandles,
 	tickers types.AggregatedProviderPrices,
 	deviationThresholds map[string]sdk.Dec,
+	tvwapWeightings map[string]types.TvwapWeighting,
+	zeroVolumeWeight sdk.Dec,
+	medianTieBreaks map[string]types.ProviderName,
 	currencyPairs []types.CurrencyPair,
 	logger zerolog.Logger,
 ) (types.CurrencyPairDec, error) {
+	var forexPairs []types.CurrencyPair
+	currencyPairs, forexPairs = splitForexPairs(currencyPairs)
+
 	candlesFilteredByCP := make(types.AggregatedProviderCandles)
 	for _, ratePair := range currencyPairs {
 		for provider, cpCandles := range candles {
@@ -64,7 +72,7 @@ func CalcCurrencyPairRates(
 		return nil, err
 	}
 
-	conversionRates, err := ComputeTVWAP(candlesFilteredByDeviation)
+	conversionRates, err := ComputeWeightedTVWAP(candlesFilteredByDeviation, tvwapWeightings)
 	if err != nil {
 		return nil, err
 	}
@@ -97,14 +105,254 @@ func CalcCurrencyPairRates(
 		return nil, err
 	}
 
-	vwap := ComputeVWAP(tickersFilteredByDeviation)
+	vwap := ComputeZeroVolumeVWAP(tickersFilteredByDeviation, zeroVolumeWeight)
 	for cp, rate := range vwap {
 		conversionRates[cp] = rate
 	}
 
+	forexRates, err := CalcForexRates(candles, tickers, medianTieBreaks, forexPairs)
+	if err != nil {
+		return nil, err
+	}
+	for cp, rate := range forexRates {
+		conversionRates[cp] = rate
+	}
+
 	return conversionRates, nil
 }
 
+// CalcAnchorRates computes the USD rates of the anchor pairs with their
+// stricter aggregation, from their candles or else from their tickers. The
+// rate of an anchor pair priced by fewer than its minimum providers within its
+// deviation threshold is omitted, and a rate outside of its band is clamped.
+func CalcAnchorRates(
+	candles types.AggregatedProviderCandles,
+	tickers types.AggregatedProviderPrices,
+	anchorPairs map[string]types.AnchorPair,
+	deviationThresholds map[string]sdk.Dec,
+	tvwapWeightings map[string]types.TvwapWeighting,
+	zeroVolumeWeight sdk.Dec,
+	logger zerolog.Logger,
+) (types.CurrencyPairDec, error) {
+	rates := make(types.CurrencyPairDec, len(anchorPairs))
+	for base, anchorPair := range anchorPairs {
+		deviations := make(map[string]sdk.Dec, 1)
+		if threshold, ok := deviationThresholds[base]; ok {
+			deviations[base] = threshold
+		}
+		if !anchorPair.Deviation.IsNil() && anchorPair.Deviation.IsPositive() {
+			deviations[base] = anchorPair.Deviation
+		}
+
+		rate, providers, err := calcAnchorRate(
+			candles,
+			tickers,
+			anchorPair.Pair,
+			deviations,
+			tvwapWeightings,
+			zeroVolumeWeight,
+			logger,
+		)
+		if err != nil {
+			return nil, err
+		}
+		if providers == 0 || providers < anchorPair.MinProviders {
+			logger.Error().
+				Str("pair", anchorPair.Pair.String()).
+				Int("providers", providers).
+				Int("min_providers", anchorPair.MinProviders).
+				Msg("not enough providers agree on anchor pair")
+			continue
+		}
+
+		provider.TelemetryAnchorDepeg(anchorPair.Pair, float32(rate.Sub(sdk.OneDec()).Abs().MustFloat64()))
+		clampedRate, clamped := anchorPair.Clamp(rate)
+		if clamped {
+			logger.Warn().
+				Str("pair", anchorPair.Pair.String()).
+				Str("rate", rate.String()).
+				Str("clamped_rate", clampedRate.String()).
+				Msg("anchor pair outside of its band")
+		}
+		rates[anchorPair.Pair] = clampedRate
+	}
+	return rates, nil
+}
+
+// calcAnchorRate computes the rate of the pair from the candles, or from the
+// tickers if no candle is available, and returns the number of providers it is
+// computed from.
+func calcAnchorRate(
+	candles types.AggregatedProviderCandles,
+	tickers types.AggregatedProviderPrices,
+	cp types.CurrencyPair,
+	deviationThresholds map[string]sdk.Dec,
+	tvwapWeightings map[string]types.TvwapWeighting,
+	zeroVolumeWeight sdk.Dec,
+	logger zerolog.Logger,
+) (sdk.Dec, int, error) {
+	pairCandles := make(types.AggregatedProviderCandles)
+	for providerName, cpCandles := range candles {
+		if candles, ok := cpCandles[cp]; ok && len(candles) > 0 {
+			pairCandles[providerName] = types.CurrencyPairCandles{cp: candles}
+		}
+	}
+	pairCandles, err := FilterCandleDeviations(logger, pairCandles, deviationThresholds)
+	if err != nil {
+		return sdk.Dec{}, 0, err
+	}
+	tvwap, err := ComputeWeightedTVWAP(pairCandles, tvwapWeightings)
+	if err != nil {
+		return sdk.Dec{}, 0, err
+	}
+	if rate, ok := tvwap[cp]; ok {
+		return rate, len(pairCandles), nil
+	}
+
+	pairTickers := make(types.AggregatedProviderPrices)
+	for providerName, cpTickers := range tickers {
+		if ticker, ok := cpTickers[cp]; ok {
+			pairTickers[providerName] = types.CurrencyPairTickers{cp: ticker}
+		}
+	}
+	pairTickers, err = FilterTickerDeviations(logger, pairTickers, deviationThresholds)
+	if err != nil {
+		return sdk.Dec{}, 0, err
+	}
+	vwap := ComputeZeroVolumeVWAP(pairTickers, zeroVolumeWeight)
+	if rate, ok := vwap[cp]; ok {
+		return rate, len(pairTickers), nil
+	}
+	return sdk.Dec{}, 0, nil
+}
+
+// CalcForexRates computes the rates for the given forex pairs as the median of
+// the rate of each provider, using its TVWAP if it has candles and its ticker
+// price otherwise. Forex providers report tick counts instead of traded volume,
+// so their rates are not weighted by volume, and a provider which stops
+// reporting a pair drops out of the median without affecting the others. The
+// median of an even number of providers is broken by the tie break provider of
+// the base, if any.
+func CalcForexRates(
+	candles types.AggregatedProviderCandles,
+	tickers types.AggregatedProviderPrices,
+	medianTieBreaks map[string]types.ProviderName,
+	forexPairs []types.CurrencyPair,
+) (types.CurrencyPairDec, error) {
+	forexCandles := make(types.AggregatedProviderCandles)
+	for provider, cpCandles := range candles {
+		for _, cp := range forexPairs {
+			if candles, ok := cpCandles[cp]; ok {
+				if _, ok := forexCandles[provider]; !ok {
+					forexCandles[provider] = make(types.CurrencyPairCandles)
+				}
+				forexCandles[provider][cp] = candles
+			}
+		}
+	}
+
+	tvwaps, err := ComputeTvwapsByProvider(forexCandles)
+	if err != nil {
+		return nil, err
+	}
+
+	forexRates := make(types.CurrencyPairDec)
+	for _, cp := range forexPairs {
+		providerRates := make(map[types.ProviderName]sdk.Dec)
+		for provider, cpTickers := range tickers {
+			if rate, ok := tvwaps[provider][cp]; ok {
+				providerRates[provider] = rate
+			} else if ticker, ok := cpTickers[cp]; ok {
+				providerRates[provider] = ticker.Price
+			}
+		}
+		// include providers which only report candles
+		for provider, cpRates := range tvwaps {
+			if _, ok := tickers[provider]; ok {
+				continue
+			}
+			if rate, ok := cpRates[cp]; ok {
+				providerRates[provider] = rate
+			}
+		}
+
+		if len(providerRates) > 0 {
+			forexRates[cp] = providerMedian(providerRates, medianTieBreaks[cp.Base])
+		}
+	}
+
+	return forexRates, nil
+}
+
+// CalcSpotRates computes the rates for the given currency pairs as the median
+// of the spot price of each provider within the deviation threshold, without
+// TWAP smoothing or volume weighting. The spot price of a provider is its
+// ticker price or the close of its latest candle, as set by its spot source,
+// or the other one if it reports only that. The median of an even number of
+// providers is broken by the tie break provider of the base, if any.
+func CalcSpotRates(
+	candles types.AggregatedProviderCandles,
+	tickers types.AggregatedProviderPrices,
+	deviationThresholds map[string]sdk.Dec,
+	spotSources types.SpotSources,
+	medianTieBreaks map[string]types.ProviderName,
+	currencyPairs []types.CurrencyPair,
+	logger zerolog.Logger,
+) (types.CurrencyPairDec, error) {
+	providerNames := make(map[types.ProviderName]struct{})
+	for providerName := range tickers {
+		providerNames[providerName] = struct{}{}
+	}
+	for providerName := range candles {
+		providerNames[providerName] = struct{}{}
+	}
+
+	spots := make(types.AggregatedProviderPrices)
+	for providerName := range providerNames {
+		for _, cp := range currencyPairs {
+			spot, ok := spotSources.SpotPrice(providerName, cp, tickers[providerName], candles[providerName])
+			if !ok {
+				continue
+			}
+			if _, ok := spots[providerName]; !ok {
+				spots[providerName] = make(types.CurrencyPairTickers)
+			}
+			spots[providerName][cp] = spot
+		}
+	}
+
+	spotsFilteredByDeviation, err := FilterTickerDeviations(logger, spots, deviationThresholds)
+	if err != nil {
+		return nil, err
+	}
+
+	rates := make(types.CurrencyPairDec, len(currencyPairs))
+	for _, cp := range currencyPairs {
+		providerSpots := make(map[types.ProviderName]sdk.Dec)
+		for providerName, cpSpots := range spotsFilteredByDeviation {
+			if spot, ok := cpSpots[cp]; ok {
+				providerSpots[providerName] = spot.Price
+			}
+		}
+		if len(providerSpots) > 0 {
+			rates[cp] = providerMedian(providerSpots, medianTieBreaks[cp.Base])
+		}
+	}
+	return rates, nil
+}
+
+// splitForexPairs separates the forex pairs from the given currency pairs.
+func splitForexPairs(currencyPairs []types.CurrencyPair) (pairs, forexPairs []types.CurrencyPair) {
+	for _, cp := range currencyPairs {
+		if config.IsForexPair(cp) {
+			forexPairs = append(forexPairs, cp)
+		} else {
+			pairs = append(pairs, cp)
+		}
+	}
+	return pairs, forexPairs
+}
+
 // ConvertAggregatedCandles converts the candles to USD and updates the currency pair
 // with a USD quote. If no conversion exists the rate is omitted in the return.
 func ConvertAggregatedCandles(
@@ -2,11 +2,15 @@ package oracle_test
 
 import (
 	"testing"
+	"time"
 
 	sdk "github.com/cosmos/cosmos-sdk/types"
 	"github.com/ojo-network/price-feeder/oracle"
+	"github.com/ojo-network/price-feeder/oracle/provider"
 	"github.com/ojo-network/price-feeder/oracle/types"
+	"github.com/rs/zerolog"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestConvertRatesToUSD(t *testing.T) {
@@ -148,3 +152,300 @@ func TestConvertAggregatedTickers(t *testing.T) {
 
 	assert.Equal(t, expectedResult, result, "The converted tickers do not match the expected result.")
 }
+
+func TestCalcForexRates(t *testing.T) {
+	eurusd := types.CurrencyPair{Base: "EUR", Quote: "USD"}
+
+	candles := types.AggregatedProviderCandles{
+		provider.ProviderPolygon: types.CurrencyPairCandles{
+			eurusd: []types.CandlePrice{
+				{
+					Price:     sdk.MustNewDecFromStr("1.08"),
+					Volume:    sdk.MustNewDecFromStr("120"),
+					TimeStamp: provider.PastUnixTime(time.Minute),
+				},
+			},
+		},
+	}
+	tickers := types.AggregatedProviderPrices{
+		provider.ProviderPolygon: types.CurrencyPairTickers{
+			eurusd: {Price: sdk.MustNewDecFromStr("1.09"), Volume: sdk.MustNewDecFromStr("120")},
+		},
+		provider.ProviderKraken: types.CurrencyPairTickers{
+			eurusd: {Price: sdk.MustNewDecFromStr("1.10"), Volume: sdk.MustNewDecFromStr("5")},
+		},
+		provider.ProviderMock: types.CurrencyPairTickers{
+			eurusd: {Price: sdk.MustNewDecFromStr("1.20"), Volume: sdk.MustNewDecFromStr("1000000")},
+		},
+	}
+
+	t.Run("median", func(t *testing.T) {
+		rates, err := oracle.CalcForexRates(candles, tickers, nil, []types.CurrencyPair{eurusd})
+		require.NoError(t, err)
+		require.Equal(t, sdk.MustNewDecFromStr("1.10"), rates[eurusd])
+	})
+
+	t.Run("failover", func(t *testing.T) {
+		failedTickers := types.AggregatedProviderPrices{
+			provider.ProviderKraken: tickers[provider.ProviderKraken],
+			provider.ProviderMock:   tickers[provider.ProviderMock],
+		}
+		rates, err := oracle.CalcForexRates(types.AggregatedProviderCandles{}, failedTickers, nil, []types.CurrencyPair{eurusd})
+		require.NoError(t, err)
+		require.Equal(t, sdk.MustNewDecFromStr("1.15"), rates[eurusd])
+
+		// a provider's candles take precedence over its ticker
+		rates, err = oracle.CalcForexRates(
+			candles,
+			types.AggregatedProviderPrices{provider.ProviderPolygon: tickers[provider.ProviderPolygon]},
+			nil,
+			[]types.CurrencyPair{eurusd},
+		)
+		require.NoError(t, err)
+		require.InDelta(t, 1.08, rates[eurusd].MustFloat64(), 0.000001)
+
+		rates, err = oracle.CalcForexRates(
+			types.AggregatedProviderCandles{},
+			types.AggregatedProviderPrices{},
+			nil,
+			[]types.CurrencyPair{eurusd},
+		)
+		require.NoError(t, err)
+		require.Empty(t, rates)
+	})
+
+	t.Run("tie_break", func(t *testing.T) {
+		tiedTickers := types.AggregatedProviderPrices{
+			provider.ProviderKraken: tickers[provider.ProviderKraken],
+			provider.ProviderMock:   tickers[provider.ProviderMock],
+		}
+		rates, err := oracle.CalcForexRates(
+			types.AggregatedProviderCandles{},
+			tiedTickers,
+			map[string]types.ProviderName{"EUR": provider.ProviderMock},
+			[]types.CurrencyPair{eurusd},
+		)
+		require.NoError(t, err)
+		require.Equal(t, sdk.MustNewDecFromStr("1.20"), rates[eurusd])
+
+		// the middle rates are averaged if the tie break provider has neither
+		rates, err = oracle.CalcForexRates(
+			types.AggregatedProviderCandles{},
+			tiedTickers,
+			map[string]types.ProviderName{"EUR": provider.ProviderPolygon},
+			[]types.CurrencyPair{eurusd},
+		)
+		require.NoError(t, err)
+		require.Equal(t, sdk.MustNewDecFromStr("1.15"), rates[eurusd])
+	})
+
+	t.Run("currency_pair_rates", func(t *testing.T) {
+		atomusd := types.CurrencyPair{Base: "ATOM", Quote: "USD"}
+		tickers := types.AggregatedProviderPrices{
+			provider.ProviderKraken: types.CurrencyPairTickers{
+				eurusd:  tickers[provider.ProviderKraken][eurusd],
+				atomusd: {Price: sdk.MustNewDecFromStr("11.52"), Volume: sdk.MustNewDecFromStr("100")},
+			},
+			provider.ProviderMock: tickers[provider.ProviderMock],
+		}
+
+		rates, err := oracle.CalcCurrencyPairRates(
+			candles,
+			tickers,
+			make(map[string]sdk.Dec),
+			nil,
+			sdk.Dec{},
+			nil,
+			[]types.CurrencyPair{eurusd, atomusd},
+			zerolog.Nop(),
+		)
+		require.NoError(t, err)
+		require.Equal(t, sdk.MustNewDecFromStr("1.10"), rates[eurusd])
+		require.Equal(t, sdk.MustNewDecFromStr("11.52"), rates[atomusd])
+	})
+}
+
+func TestConvertAggregatedTickers_Forex(t *testing.T) {
+	jpyusd := types.CurrencyPair{Base: "JPY", Quote: "USD"}
+	btcjpy := types.CurrencyPair{Base: "BTC", Quote: "JPY"}
+	btcusd := types.CurrencyPair{Base: "BTC", Quote: "USD"}
+
+	tickers := types.AggregatedProviderPrices{
+		provider.ProviderPolygon: types.CurrencyPairTickers{
+			jpyusd: {Price: sdk.MustNewDecFromStr("0.0067"), Volume: sdk.MustNewDecFromStr("120")},
+		},
+		provider.ProviderCoincheck: types.CurrencyPairTickers{
+			btcjpy: {Price: sdk.MustNewDecFromStr("9810000"), Volume: sdk.MustNewDecFromStr("0.75")},
+		},
+	}
+
+	// the JPY rate is aggregated as a forex rate and converts the JPY market
+	rates, err := oracle.CalcForexRates(types.AggregatedProviderCandles{}, tickers, nil, []types.CurrencyPair{jpyusd})
+	require.NoError(t, err)
+	require.Equal(t, sdk.MustNewDecFromStr("0.0067"), rates[jpyusd])
+
+	converted := oracle.ConvertAggregatedTickers(tickers, rates)
+	require.Equal(t, types.TickerPrice{
+		Price:  sdk.MustNewDecFromStr("65727"),
+		Volume: sdk.MustNewDecFromStr("0.75"),
+	}, converted[provider.ProviderCoincheck][btcusd])
+}
+
+func TestCalcAnchorRates(t *testing.T) {
+	USDTUSD := types.CurrencyPair{Base: "USDT", Quote: "USD"}
+	USDCUSD := types.CurrencyPair{Base: "USDC", Quote: "USD"}
+	DAIUSD := types.CurrencyPair{Base: "DAI", Quote: "USD"}
+	ticker := func(price string) types.TickerPrice {
+		return types.TickerPrice{Price: sdk.MustNewDecFromStr(price), Volume: sdk.OneDec()}
+	}
+
+	tickers := types.AggregatedProviderPrices{
+		"Provider1": {USDTUSD: ticker("1.000"), USDCUSD: ticker("1.000"), DAIUSD: ticker("0.900")},
+		"Provider2": {USDTUSD: ticker("1.001"), USDCUSD: ticker("1.001")},
+		"Provider3": {USDTUSD: ticker("0.999")},
+		"Provider4": {USDTUSD: ticker("1.200")},
+	}
+	anchorPairs := map[string]types.AnchorPair{
+		// the default deviation threshold of 2 would keep the 1.2 price
+		"USDT": {Pair: USDTUSD, MinProviders: 3, Deviation: sdk.OneDec(), Band: sdk.ZeroDec()},
+		"USDC": {Pair: USDCUSD, MinProviders: 3, Deviation: sdk.ZeroDec(), Band: sdk.ZeroDec()},
+		"DAI":  {Pair: DAIUSD, MinProviders: 1, Deviation: sdk.ZeroDec(), Band: sdk.MustNewDecFromStr("0.05")},
+	}
+
+	rates, err := oracle.CalcAnchorRates(
+		types.AggregatedProviderCandles{},
+		tickers,
+		anchorPairs,
+		map[string]sdk.Dec{"USDT": sdk.MustNewDecFromStr("2")},
+		nil,
+		sdk.ZeroDec(),
+		zerolog.Nop(),
+	)
+	require.NoError(t, err)
+	require.Equal(t, types.CurrencyPairDec{
+		USDTUSD: sdk.OneDec(),
+		DAIUSD:  sdk.MustNewDecFromStr("0.95"),
+	}, rates)
+
+	// without the stricter deviation the 1.2 price is aggregated
+	anchorPairs["USDT"] = types.AnchorPair{Pair: USDTUSD, MinProviders: 4, Deviation: sdk.ZeroDec(), Band: sdk.ZeroDec()}
+	rates, err = oracle.CalcAnchorRates(
+		types.AggregatedProviderCandles{},
+		tickers,
+		anchorPairs,
+		map[string]sdk.Dec{"USDT": sdk.MustNewDecFromStr("2")},
+		nil,
+		sdk.ZeroDec(),
+		zerolog.Nop(),
+	)
+	require.NoError(t, err)
+	require.Equal(t, sdk.MustNewDecFromStr("1.05"), rates[USDTUSD])
+}
+
+func TestCalcSpotRates(t *testing.T) {
+	atomusd := types.CurrencyPair{Base: "ATOM", Quote: "USD"}
+
+	candles := types.AggregatedProviderCandles{
+		provider.ProviderBinance: types.CurrencyPairCandles{
+			atomusd: []types.CandlePrice{
+				{
+					Price:     sdk.MustNewDecFromStr("9"),
+					Volume:    sdk.MustNewDecFromStr("100000"),
+					TimeStamp: provider.PastUnixTime(time.Minute),
+				},
+			},
+		},
+		provider.ProviderOkx: types.CurrencyPairCandles{
+			atomusd: []types.CandlePrice{
+				{
+					Price:     sdk.MustNewDecFromStr("10.2"),
+					Volume:    sdk.MustNewDecFromStr("10"),
+					TimeStamp: provider.PastUnixTime(time.Minute),
+				},
+				{
+					Price:     sdk.MustNewDecFromStr("9"),
+					Volume:    sdk.MustNewDecFromStr("10"),
+					TimeStamp: provider.PastUnixTime(2 * time.Minute),
+				},
+			},
+		},
+	}
+	tickers := types.AggregatedProviderPrices{
+		provider.ProviderBinance: types.CurrencyPairTickers{
+			atomusd: {Price: sdk.MustNewDecFromStr("10.4"), Volume: sdk.MustNewDecFromStr("100000")},
+		},
+		provider.ProviderKraken: types.CurrencyPairTickers{
+			atomusd: {Price: sdk.MustNewDecFromStr("10"), Volume: sdk.MustNewDecFromStr("1")},
+		},
+	}
+
+	// binance's ticker takes precedence over its candles, and okx contributes
+	// the close of its latest candle
+	rates, err := oracle.CalcSpotRates(
+		candles,
+		tickers,
+		make(map[string]sdk.Dec),
+		nil,
+		nil,
+		[]types.CurrencyPair{atomusd},
+		zerolog.Nop(),
+	)
+	require.NoError(t, err)
+	require.Equal(t, sdk.MustNewDecFromStr("10.2"), rates[atomusd])
+
+	// binance contributes the close of its latest candle when it is its spot
+	// source
+	rates, err = oracle.CalcSpotRates(
+		candles,
+		tickers,
+		map[string]sdk.Dec{"ATOM": sdk.NewDec(2)},
+		types.SpotSources{provider.ProviderBinance: {atomusd: types.SpotSourceCandle}},
+		nil,
+		[]types.CurrencyPair{atomusd},
+		zerolog.Nop(),
+	)
+	require.NoError(t, err)
+	require.Equal(t, sdk.MustNewDecFromStr("10"), rates[atomusd])
+
+	// the tie break provider's spot is taken over the average of the two
+	// middle spots
+	tiedTickers := types.AggregatedProviderPrices{
+		provider.ProviderBinance: tickers[provider.ProviderBinance],
+		provider.ProviderKraken:  tickers[provider.ProviderKraken],
+	}
+	rates, err = oracle.CalcSpotRates(
+		types.AggregatedProviderCandles{},
+		tiedTickers,
+		make(map[string]sdk.Dec),
+		nil,
+		nil,
+		[]types.CurrencyPair{atomusd},
+		zerolog.Nop(),
+	)
+	require.NoError(t, err)
+	require.Equal(t, sdk.MustNewDecFromStr("10.2"), rates[atomusd])
+
+	rates, err = oracle.CalcSpotRates(
+		types.AggregatedProviderCandles{},
+		tiedTickers,
+		make(map[string]sdk.Dec),
+		nil,
+		map[string]types.ProviderName{"ATOM": provider.ProviderKraken},
+		[]types.CurrencyPair{atomusd},
+		zerolog.Nop(),
+	)
+	require.NoError(t, err)
+	require.Equal(t, sdk.MustNewDecFromStr("10"), rates[atomusd])
+
+	rates, err = oracle.CalcSpotRates(
+		types.AggregatedProviderCandles{},
+		types.AggregatedProviderPrices{},
+		make(map[string]sdk.Dec),
+		nil,
+		nil,
+		[]types.CurrencyPair{atomusd},
+		zerolog.Nop(),
+	)
+	require.NoError(t, err)
+	require.Empty(t, rates)
+}
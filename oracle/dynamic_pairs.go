@@ -0,0 +1,117 @@
+package oracle
+
+import (
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/cosmos/cosmos-sdk/telemetry"
+	oracletypes "github.com/umee-network/umee/v6/x/oracle/types"
+
+	"github.com/ojo-network/price-feeder/config"
+	"github.com/ojo-network/price-feeder/oracle/types"
+)
+
+// SetDynamicPairs makes the oracle price the denoms accepted by the x/oracle
+// module without a configured currency pair, by deriving their pairs from the
+// accept list of the params. Until the params are first queried, and while
+// the query fails, the configured pairs are priced only.
+func (o *Oracle) SetDynamicPairs(dynamicPairs *types.DynamicPairs) {
+	o.dynamicPairs = dynamicPairs
+	o.staticPairs = o.providerPairs
+}
+
+// refreshDynamicPairs derives the pairs of the accepted denoms again, at most
+// once per refresh interval, and subscribes the initialized providers to the
+// pairs added to them. The pairs of denoms removed from the accept list are
+// no longer priced.
+func (o *Oracle) refreshDynamicPairs(acceptList oracletypes.DenomList, now time.Time) {
+	if o.dynamicPairs == nil || now.Sub(o.lastDynamic) < o.dynamicPairs.RefreshInterval {
+		return
+	}
+	o.lastDynamic = now
+
+	providerPairs, dynamicBases := o.deriveProviderPairs(acceptList)
+	added := addedPairs(o.providerPairs, providerPairs)
+
+	o.pricesMutex.Lock()
+	o.providerPairs = providerPairs
+	o.pricesMutex.Unlock()
+
+	telemetry.SetGauge(float32(len(dynamicBases)), "dynamic_pairs")
+	for providerName, pairs := range added {
+		pairNames := make([]string, len(pairs))
+		for i, cp := range pairs {
+			pairNames[i] = cp.String()
+		}
+		o.logger.Info().
+			Str("provider", providerName.String()).
+			Strs("pairs", pairNames).
+			Msg("adding pairs of accepted denoms")
+
+		// subscribing confirms the availability of the pairs, which must
+		// not hold up the tick
+		if priceProvider, ok := o.priceProviders[providerName]; ok {
+			go priceProvider.SubscribeCurrencyPairs(pairs...)
+		}
+	}
+	o.logger.Debug().Strs("bases", dynamicBases).Msg("derived pairs of accepted denoms")
+}
+
+// deriveProviderPairs returns the configured provider pairs along with the
+// pairs of the accepted denoms without a configured currency pair, and the
+// sorted bases of the latter.
+func (o *Oracle) deriveProviderPairs(
+	acceptList oracletypes.DenomList,
+) (map[types.ProviderName][]types.CurrencyPair, []string) {
+	staticBases := make(map[string]struct{})
+	providerPairs := make(map[types.ProviderName][]types.CurrencyPair, len(o.staticPairs))
+	for providerName, pairs := range o.staticPairs {
+		providerPairs[providerName] = append([]types.CurrencyPair{}, pairs...)
+		for _, pair := range pairs {
+			staticBases[pair.Base] = struct{}{}
+		}
+	}
+
+	dynamicBases := make([]string, 0, len(acceptList))
+	for _, denom := range acceptList {
+		base := strings.ToUpper(denom.SymbolDenom)
+		quote := o.dynamicPairs.QuoteOf(base)
+		if _, ok := staticBases[base]; ok || base == "" || base == quote || base == config.DenomUSD {
+			continue
+		}
+		// a denom listed twice is priced once
+		staticBases[base] = struct{}{}
+		dynamicBases = append(dynamicBases, base)
+
+		for _, providerName := range o.dynamicPairs.Providers {
+			providerPairs[providerName] = append(providerPairs[providerName], types.CurrencyPair{
+				Base:  base,
+				Quote: quote,
+			})
+		}
+	}
+	sort.Strings(dynamicBases)
+	return providerPairs, dynamicBases
+}
+
+// addedPairs returns the pairs of each provider in newPairs missing from
+// oldPairs.
+func addedPairs(
+	oldPairs map[types.ProviderName][]types.CurrencyPair,
+	newPairs map[types.ProviderName][]types.CurrencyPair,
+) map[types.ProviderName][]types.CurrencyPair {
+	added := make(map[types.ProviderName][]types.CurrencyPair)
+	for providerName, pairs := range newPairs {
+		existing := make(map[types.CurrencyPair]struct{}, len(oldPairs[providerName]))
+		for _, pair := range oldPairs[providerName] {
+			existing[pair] = struct{}{}
+		}
+		for _, pair := range pairs {
+			if _, ok := existing[pair]; !ok {
+				added[providerName] = append(added[providerName], pair)
+			}
+		}
+	}
+	return added
+}
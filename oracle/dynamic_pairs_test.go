@@ -0,0 +1,83 @@
+package oracle
+
+import (
+	"testing"
+	"time"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/require"
+	oracletypes "github.com/umee-network/umee/v6/x/oracle/types"
+
+	"github.com/ojo-network/price-feeder/oracle/client"
+	"github.com/ojo-network/price-feeder/oracle/provider"
+	"github.com/ojo-network/price-feeder/oracle/types"
+)
+
+func TestOracle_refreshDynamicPairs(t *testing.T) {
+	o := New(
+		zerolog.Nop(),
+		client.OracleClient{},
+		map[types.ProviderName][]types.CurrencyPair{
+			provider.ProviderBinance: {{Base: "ATOM", Quote: "USDT"}, {Base: "USDT", Quote: "USD"}},
+		},
+		time.Millisecond*100,
+		make(map[string]sdk.Dec),
+		make(map[types.ProviderName]provider.Endpoint),
+	)
+	o.SetDynamicPairs(&types.DynamicPairs{
+		Providers:       []types.ProviderName{provider.ProviderBinance, provider.ProviderKraken},
+		Quote:           "USDT",
+		Quotes:          map[string]string{"STATOM": "ATOM"},
+		RefreshInterval: time.Minute,
+	})
+
+	acceptList := oracletypes.DenomList{
+		{BaseDenom: "uatom", SymbolDenom: "atom"},
+		{BaseDenom: "uumee", SymbolDenom: "umee"},
+		{BaseDenom: "stuatom", SymbolDenom: "statom"},
+	}
+	now := time.Now()
+	o.refreshDynamicPairs(acceptList, now)
+
+	// the configured pairs are kept and the other accepted denoms are added
+	require.Equal(t, map[types.ProviderName][]types.CurrencyPair{
+		provider.ProviderBinance: {
+			{Base: "ATOM", Quote: "USDT"},
+			{Base: "USDT", Quote: "USD"},
+			{Base: "UMEE", Quote: "USDT"},
+			{Base: "STATOM", Quote: "ATOM"},
+		},
+		provider.ProviderKraken: {
+			{Base: "UMEE", Quote: "USDT"},
+			{Base: "STATOM", Quote: "ATOM"},
+		},
+	}, o.providerPairs)
+
+	// the accept list is not derived again within the refresh interval
+	o.refreshDynamicPairs(acceptList[:1], now.Add(30*time.Second))
+	require.Len(t, o.providerPairs[provider.ProviderKraken], 2)
+
+	// denoms removed from the accept list are no longer priced
+	o.refreshDynamicPairs(acceptList[:2], now.Add(time.Minute))
+	require.Equal(t, []types.CurrencyPair{{Base: "UMEE", Quote: "USDT"}}, o.providerPairs[provider.ProviderKraken])
+	require.Len(t, o.providerPairs[provider.ProviderBinance], 3)
+}
+
+func TestOracle_refreshDynamicPairsDisabled(t *testing.T) {
+	providerPairs := map[types.ProviderName][]types.CurrencyPair{
+		provider.ProviderBinance: {{Base: "ATOM", Quote: "USDT"}},
+	}
+	o := New(
+		zerolog.Nop(),
+		client.OracleClient{},
+		providerPairs,
+		time.Millisecond*100,
+		make(map[string]sdk.Dec),
+		make(map[types.ProviderName]provider.Endpoint),
+	)
+	o.SetDynamicPairs(nil)
+
+	o.refreshDynamicPairs(oracletypes.DenomList{{BaseDenom: "uumee", SymbolDenom: "umee"}}, time.Now())
+	require.Equal(t, providerPairs, o.providerPairs)
+}
@@ -1,9 +1,13 @@
 package oracle
 
 import (
+	"sort"
+	"time"
+
 	sdk "github.com/cosmos/cosmos-sdk/types"
 	"github.com/rs/zerolog"
 
+	"github.com/ojo-network/price-feeder/config"
 	"github.com/ojo-network/price-feeder/oracle/provider"
 	"github.com/ojo-network/price-feeder/oracle/types"
 )
@@ -11,7 +15,7 @@ import (
 // defaultDeviationThreshold defines how many 𝜎 a provider can be away
 // from the mean without being considered faulty. This can be overridden
 // in the config.
-var defaultDeviationThreshold = sdk.MustNewDecFromStr("1.0")
+var defaultDeviationThreshold = sdk.MustNewDecFromStr(config.DefaultDeviationThreshold)
 
 // FilterTickerDeviations finds the standard deviations of the prices of
 // all assets, and filters out any providers that are not within 2𝜎 of the mean.
@@ -146,6 +150,493 @@ func FilterCandleDeviations(
 	return filteredCandles, nil
 }
 
+// FilterTickerPriceBands filters out the USD tickers whose price is outside of
+// the price band of their base asset.
+func FilterTickerPriceBands(
+	logger zerolog.Logger,
+	prices types.AggregatedProviderPrices,
+	priceBands map[string]types.PriceBand,
+) types.AggregatedProviderPrices {
+	if len(priceBands) == 0 {
+		return prices
+	}
+
+	filteredPrices := make(types.AggregatedProviderPrices)
+
+	for providerName, priceTickers := range prices {
+		filteredPrices[providerName] = make(types.CurrencyPairTickers)
+		for cp, tp := range priceTickers {
+			band, ok := priceBands[cp.Base]
+			if ok && cp.Quote == config.DenomUSD && !band.Contains(tp.Price) {
+				provider.TelemetryPriceBandRejected(providerName, cp)
+				logger.Warn().
+					Interface("currency_pair", cp).
+					Str("provider", string(providerName)).
+					Str("price", tp.Price.String()).
+					Msg("provider ticker outside of price band")
+				continue
+			}
+			filteredPrices[providerName][cp] = tp
+		}
+	}
+
+	return filteredPrices
+}
+
+// FilterCandlePriceBands filters out the USD candles whose price is outside of
+// the price band of their base asset.
+func FilterCandlePriceBands(
+	logger zerolog.Logger,
+	candles types.AggregatedProviderCandles,
+	priceBands map[string]types.PriceBand,
+) types.AggregatedProviderCandles {
+	if len(priceBands) == 0 {
+		return candles
+	}
+
+	filteredCandles := make(types.AggregatedProviderCandles)
+
+	for providerName, priceCandles := range candles {
+		filteredCandles[providerName] = make(types.CurrencyPairCandles)
+		for cp, cps := range priceCandles {
+			band, ok := priceBands[cp.Base]
+			if !ok || cp.Quote != config.DenomUSD {
+				filteredCandles[providerName][cp] = cps
+				continue
+			}
+
+			filtered := []types.CandlePrice{}
+			for _, candle := range cps {
+				if !band.Contains(candle.Price) {
+					provider.TelemetryPriceBandRejected(providerName, cp)
+					logger.Warn().
+						Interface("currency_pair", cp).
+						Str("provider", string(providerName)).
+						Str("price", candle.Price.String()).
+						Msg("provider candle outside of price band")
+					continue
+				}
+				filtered = append(filtered, candle)
+			}
+			if len(filtered) > 0 {
+				filteredCandles[providerName][cp] = filtered
+			}
+		}
+	}
+
+	return filteredCandles
+}
+
+// FilterTickerProviderRoles filters out the tickers of the providers which are
+// restricted to the candle aggregate of a currency pair.
+func FilterTickerProviderRoles(
+	prices types.AggregatedProviderPrices,
+	providerRoles types.ProviderRoles,
+) types.AggregatedProviderPrices {
+	if len(providerRoles) == 0 {
+		return prices
+	}
+
+	filteredPrices := make(types.AggregatedProviderPrices)
+
+	for providerName, priceTickers := range prices {
+		filteredPrices[providerName] = make(types.CurrencyPairTickers)
+		for cp, tp := range priceTickers {
+			if providerRoles.Role(providerName, cp).Has(types.ProviderRoleTicker) {
+				filteredPrices[providerName][cp] = tp
+			}
+		}
+	}
+
+	return filteredPrices
+}
+
+// FilterCandleProviderRoles filters out the candles of the providers which are
+// restricted to the ticker aggregate of a currency pair.
+func FilterCandleProviderRoles(
+	candles types.AggregatedProviderCandles,
+	providerRoles types.ProviderRoles,
+) types.AggregatedProviderCandles {
+	if len(providerRoles) == 0 {
+		return candles
+	}
+
+	filteredCandles := make(types.AggregatedProviderCandles)
+
+	for providerName, priceCandles := range candles {
+		filteredCandles[providerName] = make(types.CurrencyPairCandles)
+		for cp, cps := range priceCandles {
+			if providerRoles.Role(providerName, cp).Has(types.ProviderRoleCandle) {
+				filteredCandles[providerName][cp] = cps
+			}
+		}
+	}
+
+	return filteredCandles
+}
+
+// FilterProviderAgreement filters out the tickers and candles of the pairs
+// whose providers' prices don't meet their agreement requirement. The price of
+// a provider is its spot price from its spot source.
+func FilterProviderAgreement(
+	logger zerolog.Logger,
+	candles types.AggregatedProviderCandles,
+	prices types.AggregatedProviderPrices,
+	spotSources types.SpotSources,
+	agreements map[types.CurrencyPair]types.ProviderAgreement,
+) (types.AggregatedProviderCandles, types.AggregatedProviderPrices) {
+	if len(agreements) == 0 {
+		return candles, prices
+	}
+
+	providerPrices := latestProviderPrices(candles, prices, spotSources, func(cp types.CurrencyPair) bool {
+		_, ok := agreements[cp]
+		return ok
+	})
+
+	unmet := make(map[types.CurrencyPair]bool)
+	for cp, agreement := range agreements {
+		pairPrices := make([]sdk.Dec, 0, len(providerPrices[cp]))
+		for _, price := range providerPrices[cp] {
+			pairPrices = append(pairPrices, price)
+		}
+		if agreeing := agreement.Agreeing(pairPrices); agreeing < agreement.MinProviders {
+			unmet[cp] = true
+			provider.TelemetryAgreementUnmet(cp)
+			logger.Warn().
+				Interface("currency_pair", cp).
+				Int("agreeing_providers", agreeing).
+				Int("min_agreeing_providers", agreement.MinProviders).
+				Msg("not enough providers agree on the price of the pair")
+		}
+	}
+	return omitPairs(candles, prices, unmet)
+}
+
+// latestProviderPrices returns the spot price of every provider of the pairs
+// matching the filter, from the spot source of the provider.
+func latestProviderPrices(
+	candles types.AggregatedProviderCandles,
+	prices types.AggregatedProviderPrices,
+	spotSources types.SpotSources,
+	filter func(types.CurrencyPair) bool,
+) map[types.CurrencyPair]map[types.ProviderName]sdk.Dec {
+	pairs := make(map[types.ProviderName]map[types.CurrencyPair]struct{})
+	addPairs := func(providerName types.ProviderName, cp types.CurrencyPair) {
+		if !filter(cp) {
+			return
+		}
+		if _, ok := pairs[providerName]; !ok {
+			pairs[providerName] = make(map[types.CurrencyPair]struct{})
+		}
+		pairs[providerName][cp] = struct{}{}
+	}
+	for providerName, priceCandles := range candles {
+		for cp := range priceCandles {
+			addPairs(providerName, cp)
+		}
+	}
+	for providerName, priceTickers := range prices {
+		for cp := range priceTickers {
+			addPairs(providerName, cp)
+		}
+	}
+
+	providerPrices := make(map[types.CurrencyPair]map[types.ProviderName]sdk.Dec)
+	for providerName, cps := range pairs {
+		for cp := range cps {
+			spot, ok := spotSources.SpotPrice(providerName, cp, prices[providerName], candles[providerName])
+			if !ok {
+				continue
+			}
+			if _, ok := providerPrices[cp]; !ok {
+				providerPrices[cp] = make(map[types.ProviderName]sdk.Dec)
+			}
+			providerPrices[cp][providerName] = spot.Price
+		}
+	}
+	return providerPrices
+}
+
+// FilterProviderCount trims the providers of the pairs priced by more than
+// their max providers down to that count, dropping the provider furthest from
+// the median of the remaining prices one at a time. Unlike the deviation
+// filter, which only removes outliers, it always trims to the count, so the
+// pair is aggregated from its most agreeing providers.
+func FilterProviderCount(
+	logger zerolog.Logger,
+	candles types.AggregatedProviderCandles,
+	prices types.AggregatedProviderPrices,
+	spotSources types.SpotSources,
+	maxProviders map[types.CurrencyPair]int,
+) (types.AggregatedProviderCandles, types.AggregatedProviderPrices) {
+	if len(maxProviders) == 0 {
+		return candles, prices
+	}
+
+	providerPrices := latestProviderPrices(candles, prices, spotSources, func(cp types.CurrencyPair) bool {
+		_, ok := maxProviders[cp]
+		return ok
+	})
+
+	dropped := make(map[types.ProviderName]map[types.CurrencyPair]bool)
+	for cp, maxCount := range maxProviders {
+		for _, providerName := range trimProviders(providerPrices[cp], maxCount) {
+			if _, ok := dropped[providerName]; !ok {
+				dropped[providerName] = make(map[types.CurrencyPair]bool)
+			}
+			dropped[providerName][cp] = true
+			logger.Debug().
+				Interface("currency_pair", cp).
+				Str("provider", providerName.String()).
+				Int("max_providers", maxCount).
+				Msg("dropped the provider furthest from the median of the pair")
+		}
+	}
+	if len(dropped) == 0 {
+		return candles, prices
+	}
+
+	filteredCandles := make(types.AggregatedProviderCandles)
+	for providerName, priceCandles := range candles {
+		filteredCandles[providerName] = make(types.CurrencyPairCandles)
+		for cp, cps := range priceCandles {
+			if !dropped[providerName][cp] {
+				filteredCandles[providerName][cp] = cps
+			}
+		}
+	}
+	filteredPrices := make(types.AggregatedProviderPrices)
+	for providerName, priceTickers := range prices {
+		filteredPrices[providerName] = make(types.CurrencyPairTickers)
+		for cp, tp := range priceTickers {
+			if !dropped[providerName][cp] {
+				filteredPrices[providerName][cp] = tp
+			}
+		}
+	}
+
+	return filteredCandles, filteredPrices
+}
+
+// trimProviders returns the providers to drop for at most maxCount of them to
+// remain, in the order they are dropped. The provider furthest from the median
+// of the remaining prices is dropped first, the first by name on ties.
+func trimProviders(providerPrices map[types.ProviderName]sdk.Dec, maxCount int) []types.ProviderName {
+	remaining := make([]types.ProviderName, 0, len(providerPrices))
+	for providerName := range providerPrices {
+		remaining = append(remaining, providerName)
+	}
+	sort.Slice(remaining, func(i, j int) bool { return remaining[i] < remaining[j] })
+
+	var dropped []types.ProviderName
+	for len(remaining) > maxCount {
+		rates := make([]sdk.Dec, len(remaining))
+		for i, providerName := range remaining {
+			rates[i] = providerPrices[providerName]
+		}
+		mid := median(rates)
+
+		furthest := 0
+		for i := 1; i < len(remaining); i++ {
+			if rates[i].Sub(mid).Abs().GT(rates[furthest].Sub(mid).Abs()) {
+				furthest = i
+			}
+		}
+		dropped = append(dropped, remaining[furthest])
+		remaining = append(remaining[:furthest], remaining[furthest+1:]...)
+	}
+	return dropped
+}
+
+// FilterRequiredProviders filters out the tickers and candles of the pairs
+// missing the price of any of their required providers, regardless of how
+// many other providers price them.
+func FilterRequiredProviders(
+	logger zerolog.Logger,
+	candles types.AggregatedProviderCandles,
+	prices types.AggregatedProviderPrices,
+	requiredProviders map[types.CurrencyPair][]types.ProviderName,
+) (types.AggregatedProviderCandles, types.AggregatedProviderPrices) {
+	if len(requiredProviders) == 0 {
+		return candles, prices
+	}
+
+	missing := make(map[types.CurrencyPair]bool)
+	for cp, providerNames := range requiredProviders {
+		for _, providerName := range providerNames {
+			if _, ok := prices[providerName][cp]; ok {
+				continue
+			}
+			if len(candles[providerName][cp]) > 0 {
+				continue
+			}
+			missing[cp] = true
+			provider.TelemetryRequiredProviderMissing(providerName, cp)
+			logger.Warn().
+				Interface("currency_pair", cp).
+				Str("provider", providerName.String()).
+				Msg("omitting the pair missing the price of a required provider")
+		}
+	}
+
+	return omitPairs(candles, prices, missing)
+}
+
+// FilterProviderGroups filters out the tickers and candles of the pairs priced
+// by fewer independence groups than their minimum, so that providers sharing an
+// upstream data source count as a single source. A provider counts toward its
+// group if it has a ticker or candles of the pair.
+func FilterProviderGroups(
+	logger zerolog.Logger,
+	candles types.AggregatedProviderCandles,
+	prices types.AggregatedProviderPrices,
+	providerGroups types.ProviderGroups,
+	minProviderGroups map[types.CurrencyPair]int,
+) (types.AggregatedProviderCandles, types.AggregatedProviderPrices) {
+	if len(minProviderGroups) == 0 {
+		return candles, prices
+	}
+
+	lacking := make(map[types.CurrencyPair]bool)
+	for cp, minGroups := range minProviderGroups {
+		groups := make(map[string]struct{})
+		for providerName, providerPrices := range prices {
+			if _, ok := providerPrices[cp]; ok {
+				groups[providerGroups.Group(providerName)] = struct{}{}
+			}
+		}
+		for providerName, providerCandles := range candles {
+			if len(providerCandles[cp]) > 0 {
+				groups[providerGroups.Group(providerName)] = struct{}{}
+			}
+		}
+
+		provider.TelemetryProviderGroups(cp, len(groups))
+		if len(groups) < minGroups {
+			lacking[cp] = true
+			logger.Warn().
+				Interface("currency_pair", cp).
+				Int("provider_groups", len(groups)).
+				Int("min_provider_groups", minGroups).
+				Msg("omitting the pair priced by too few independent provider groups")
+		}
+	}
+
+	return omitPairs(candles, prices, lacking)
+}
+
+// FilterStaleForexRates omits the pairs quoted in a forex currency while the
+// USD rate of their quote is older than their max forex age, so that no USD
+// price is built on a stale forex rate. The age of a forex rate is that of its
+// newest candle, and a forex rate without candles is stale.
+func FilterStaleForexRates(
+	logger zerolog.Logger,
+	candles types.AggregatedProviderCandles,
+	prices types.AggregatedProviderPrices,
+	maxForexAges map[types.CurrencyPair]time.Duration,
+	now time.Time,
+) (types.AggregatedProviderCandles, types.AggregatedProviderPrices) {
+	if len(maxForexAges) == 0 {
+		return candles, prices
+	}
+
+	stale := make(map[types.CurrencyPair]bool)
+	for cp, maxAge := range maxForexAges {
+		forexPair := types.CurrencyPair{Base: cp.Quote, Quote: config.DenomUSD}
+		var newest int64
+		for _, providerCandles := range candles {
+			for _, candle := range providerCandles[forexPair] {
+				if candle.TimeStamp > newest {
+					newest = candle.TimeStamp
+				}
+			}
+		}
+
+		age := now.Sub(time.UnixMilli(newest))
+		if newest != 0 && age <= maxAge {
+			continue
+		}
+		stale[cp] = true
+		provider.TelemetryStaleForexRate(cp)
+		logEvent := logger.Warn().
+			Interface("currency_pair", cp).
+			Str("forex_pair", forexPair.String()).
+			Dur("max_forex_age", maxAge)
+		if newest != 0 {
+			logEvent = logEvent.Dur("forex_age", age)
+		}
+		logEvent.Msg("omitting the pair whose forex rate is stale")
+	}
+
+	return omitPairs(candles, prices, stale)
+}
+
+// omitPairs returns the tickers and candles without those of the given pairs.
+func omitPairs(
+	candles types.AggregatedProviderCandles,
+	prices types.AggregatedProviderPrices,
+	omitted map[types.CurrencyPair]bool,
+) (types.AggregatedProviderCandles, types.AggregatedProviderPrices) {
+	if len(omitted) == 0 {
+		return candles, prices
+	}
+
+	filteredCandles := make(types.AggregatedProviderCandles)
+	for providerName, priceCandles := range candles {
+		filteredCandles[providerName] = make(types.CurrencyPairCandles)
+		for cp, cps := range priceCandles {
+			if !omitted[cp] {
+				filteredCandles[providerName][cp] = cps
+			}
+		}
+	}
+	filteredPrices := make(types.AggregatedProviderPrices)
+	for providerName, priceTickers := range prices {
+		filteredPrices[providerName] = make(types.CurrencyPairTickers)
+		for cp, tp := range priceTickers {
+			if !omitted[cp] {
+				filteredPrices[providerName][cp] = tp
+			}
+		}
+	}
+
+	return filteredCandles, filteredPrices
+}
+
+// omitProviderPairs returns the tickers and candles without those of the
+// given pairs of each provider.
+func omitProviderPairs(
+	candles types.AggregatedProviderCandles,
+	prices types.AggregatedProviderPrices,
+	omitted map[types.ProviderName]map[types.CurrencyPair]bool,
+) (types.AggregatedProviderCandles, types.AggregatedProviderPrices) {
+	if len(omitted) == 0 {
+		return candles, prices
+	}
+
+	filteredCandles := make(types.AggregatedProviderCandles)
+	for providerName, priceCandles := range candles {
+		filteredCandles[providerName] = make(types.CurrencyPairCandles)
+		for cp, cps := range priceCandles {
+			if !omitted[providerName][cp] {
+				filteredCandles[providerName][cp] = cps
+			}
+		}
+	}
+	filteredPrices := make(types.AggregatedProviderPrices)
+	for providerName, priceTickers := range prices {
+		filteredPrices[providerName] = make(types.CurrencyPairTickers)
+		for cp, tp := range priceTickers {
+			if !omitted[providerName][cp] {
+				filteredPrices[providerName][cp] = tp
+			}
+		}
+	}
+
+	return filteredCandles, filteredPrices
+}
+
 func isBetween(p, mean, margin sdk.Dec) bool {
 	return p.GTE(mean.Sub(margin)) &&
 		p.LTE(mean.Add(margin))
@@ -126,3 +126,490 @@ func TestSuccessFilterTickerDeviations(t *testing.T) {
 	require.NoError(t, err, "It should successfully not filter out coinbase")
 	require.True(t, ok, "The filtered candle deviation price of coinbase should remain")
 }
+
+func TestSuccessFilterTickerDeviationsTinyPrices(t *testing.T) {
+	pair := types.CurrencyPair{
+		Base:  "OJO",
+		Quote: "USDT",
+	}
+	volume := sdk.MustNewDecFromStr("1994674.34000000")
+
+	providerTickers := types.AggregatedProviderPrices{
+		provider.ProviderBinance: {
+			pair: {Price: sdk.MustNewDecFromStr("0.0000012300"), Volume: volume},
+		},
+		provider.ProviderHuobi: {
+			pair: {Price: sdk.MustNewDecFromStr("0.0000012301"), Volume: volume},
+		},
+		provider.ProviderKraken: {
+			pair: {Price: sdk.MustNewDecFromStr("0.0000012301"), Volume: volume},
+		},
+		provider.ProviderCoinbase: {
+			pair: {Price: sdk.MustNewDecFromStr("0.0000012302"), Volume: volume},
+		},
+	}
+
+	// all prices are within 1.5𝜎 of the mean
+	pricesFiltered, err := FilterTickerDeviations(
+		zerolog.Nop(),
+		providerTickers,
+		map[string]sdk.Dec{pair.Base: sdk.MustNewDecFromStr("1.5")},
+	)
+	require.NoError(t, err)
+	require.Len(t, pricesFiltered, 4)
+
+	providerTickers[provider.ProviderCoinbase] = types.CurrencyPairTickers{
+		pair: {Price: sdk.MustNewDecFromStr("0.0000012400"), Volume: volume},
+	}
+
+	pricesFiltered, err = FilterTickerDeviations(
+		zerolog.Nop(),
+		providerTickers,
+		make(map[string]sdk.Dec),
+	)
+	require.NoError(t, err)
+	_, ok := pricesFiltered[provider.ProviderCoinbase]
+	require.False(t, ok, "The deviating tiny price at coinbase should be filtered out")
+	require.Len(t, pricesFiltered, 3)
+}
+
+func TestFilterTickerPriceBands(t *testing.T) {
+	atomUSD := types.CurrencyPair{Base: "ATOM", Quote: "USD"}
+	atomUSDT := types.CurrencyPair{Base: "ATOM", Quote: "USDT"}
+	ojoUSD := types.CurrencyPair{Base: "OJO", Quote: "USD"}
+	volume := sdk.MustNewDecFromStr("1994674.34000000")
+
+	prices := types.AggregatedProviderPrices{
+		provider.ProviderBinance: {
+			atomUSD: {Price: sdk.MustNewDecFromStr("11.52"), Volume: volume},
+			ojoUSD:  {Price: sdk.MustNewDecFromStr("0"), Volume: volume},
+		},
+		provider.ProviderKraken: {
+			// a decimal shift bug
+			atomUSD:  {Price: sdk.MustNewDecFromStr("1152"), Volume: volume},
+			atomUSDT: {Price: sdk.MustNewDecFromStr("1152"), Volume: volume},
+		},
+	}
+	priceBands := map[string]types.PriceBand{
+		"ATOM": {Min: sdk.MustNewDecFromStr("1"), Max: sdk.MustNewDecFromStr("100")},
+	}
+
+	filteredPrices := FilterTickerPriceBands(zerolog.Nop(), prices, priceBands)
+	require.Equal(t, prices[provider.ProviderBinance], filteredPrices[provider.ProviderBinance])
+	require.Equal(t, types.CurrencyPairTickers{
+		// only USD prices are compared to the band
+		atomUSDT: prices[provider.ProviderKraken][atomUSDT],
+	}, filteredPrices[provider.ProviderKraken])
+
+	// open bands only bound one side
+	priceBands["OJO"] = types.PriceBand{Min: sdk.MustNewDecFromStr("0.000001")}
+	filteredPrices = FilterTickerPriceBands(zerolog.Nop(), prices, priceBands)
+	require.NotContains(t, filteredPrices[provider.ProviderBinance], ojoUSD)
+}
+
+func TestFilterCandlePriceBands(t *testing.T) {
+	atomUSD := types.CurrencyPair{Base: "ATOM", Quote: "USD"}
+	volume := sdk.MustNewDecFromStr("1994674.34000000")
+
+	candles := types.AggregatedProviderCandles{
+		provider.ProviderBinance: {
+			atomUSD: {
+				{Price: sdk.MustNewDecFromStr("11.52"), Volume: volume, TimeStamp: provider.PastUnixTime(2 * time.Minute)},
+				{Price: sdk.MustNewDecFromStr("1152"), Volume: volume, TimeStamp: provider.PastUnixTime(1 * time.Minute)},
+			},
+		},
+		provider.ProviderKraken: {
+			atomUSD: {
+				{Price: sdk.MustNewDecFromStr("0"), Volume: volume, TimeStamp: provider.PastUnixTime(1 * time.Minute)},
+			},
+		},
+	}
+	priceBands := map[string]types.PriceBand{
+		"ATOM": {Min: sdk.MustNewDecFromStr("1"), Max: sdk.MustNewDecFromStr("100")},
+	}
+
+	filteredCandles := FilterCandlePriceBands(zerolog.Nop(), candles, priceBands)
+	require.Equal(t, []types.CandlePrice{candles[provider.ProviderBinance][atomUSD][0]}, filteredCandles[provider.ProviderBinance][atomUSD])
+	require.NotContains(t, filteredCandles[provider.ProviderKraken], atomUSD)
+}
+
+func TestFilterProviderRoles(t *testing.T) {
+	atomUSD := types.CurrencyPair{Base: "ATOM", Quote: "USD"}
+	ojoUSD := types.CurrencyPair{Base: "OJO", Quote: "USD"}
+	volume := sdk.MustNewDecFromStr("1994674.34000000")
+	ticker := types.TickerPrice{Price: sdk.MustNewDecFromStr("11.52"), Volume: volume}
+	candles := []types.CandlePrice{
+		{Price: sdk.MustNewDecFromStr("11.52"), Volume: volume, TimeStamp: provider.PastUnixTime(1 * time.Minute)},
+	}
+
+	prices := types.AggregatedProviderPrices{
+		provider.ProviderBinance: {atomUSD: ticker, ojoUSD: ticker},
+		provider.ProviderKraken:  {atomUSD: ticker},
+	}
+	providerCandles := types.AggregatedProviderCandles{
+		provider.ProviderBinance: {atomUSD: candles, ojoUSD: candles},
+		provider.ProviderKraken:  {atomUSD: candles},
+	}
+	providerRoles := types.ProviderRoles{
+		provider.ProviderBinance: {atomUSD: types.ProviderRoleCandle},
+		provider.ProviderKraken:  {atomUSD: types.ProviderRoleTicker},
+	}
+
+	filteredPrices := FilterTickerProviderRoles(prices, providerRoles)
+	require.Equal(t, types.AggregatedProviderPrices{
+		provider.ProviderBinance: {ojoUSD: ticker},
+		provider.ProviderKraken:  {atomUSD: ticker},
+	}, filteredPrices)
+
+	filteredCandles := FilterCandleProviderRoles(providerCandles, providerRoles)
+	require.Equal(t, types.AggregatedProviderCandles{
+		provider.ProviderBinance: {atomUSD: candles, ojoUSD: candles},
+		provider.ProviderKraken:  {},
+	}, filteredCandles)
+}
+
+func TestFilterProviderAgreement(t *testing.T) {
+	atomUSDT := types.CurrencyPair{Base: "ATOM", Quote: "USDT"}
+	ojoUSDT := types.CurrencyPair{Base: "OJO", Quote: "USDT"}
+	volume := sdk.MustNewDecFromStr("1994674.34000000")
+
+	prices := types.AggregatedProviderPrices{
+		provider.ProviderBinance: {
+			atomUSDT: {Price: sdk.MustNewDecFromStr("11.52"), Volume: volume},
+			ojoUSDT:  {Price: sdk.MustNewDecFromStr("0.051"), Volume: volume},
+		},
+		provider.ProviderKraken: {
+			atomUSDT: {Price: sdk.MustNewDecFromStr("11.55"), Volume: volume},
+			ojoUSDT:  {Price: sdk.MustNewDecFromStr("0.060"), Volume: volume},
+		},
+	}
+	candles := types.AggregatedProviderCandles{
+		provider.ProviderOkx: {
+			// the latest candle is the price of the provider
+			atomUSDT: {
+				{Price: sdk.MustNewDecFromStr("13"), Volume: volume, TimeStamp: provider.PastUnixTime(2 * time.Minute)},
+				{Price: sdk.MustNewDecFromStr("11.50"), Volume: volume, TimeStamp: provider.PastUnixTime(1 * time.Minute)},
+			},
+			ojoUSDT: {
+				{Price: sdk.MustNewDecFromStr("0.051"), Volume: volume, TimeStamp: provider.PastUnixTime(1 * time.Minute)},
+			},
+		},
+	}
+	agreements := map[types.CurrencyPair]types.ProviderAgreement{
+		atomUSDT: {MinProviders: 3, Band: sdk.MustNewDecFromStr("0.005")},
+		ojoUSDT:  {MinProviders: 3, Band: sdk.MustNewDecFromStr("0.005")},
+	}
+
+	filteredCandles, filteredPrices := FilterProviderAgreement(zerolog.Nop(), candles, prices, nil, agreements)
+
+	// all three providers agree on ATOM
+	require.Equal(t, prices[provider.ProviderBinance][atomUSDT], filteredPrices[provider.ProviderBinance][atomUSDT])
+	require.Equal(t, prices[provider.ProviderKraken][atomUSDT], filteredPrices[provider.ProviderKraken][atomUSDT])
+	require.Equal(t, candles[provider.ProviderOkx][atomUSDT], filteredCandles[provider.ProviderOkx][atomUSDT])
+
+	// only two providers agree on OJO
+	require.NotContains(t, filteredPrices[provider.ProviderBinance], ojoUSDT)
+	require.NotContains(t, filteredPrices[provider.ProviderKraken], ojoUSDT)
+	require.NotContains(t, filteredCandles[provider.ProviderOkx], ojoUSDT)
+
+	// pairs without a requirement are left untouched
+	filteredCandles, filteredPrices = FilterProviderAgreement(zerolog.Nop(), candles, prices, nil, nil)
+	require.Equal(t, candles, filteredCandles)
+	require.Equal(t, prices, filteredPrices)
+}
+
+func TestFilterRequiredProviders(t *testing.T) {
+	atomUSDT := types.CurrencyPair{Base: "ATOM", Quote: "USDT"}
+	ojoUSDT := types.CurrencyPair{Base: "OJO", Quote: "USDT"}
+	umeeUSDT := types.CurrencyPair{Base: "UMEE", Quote: "USDT"}
+	volume := sdk.MustNewDecFromStr("1994674.34000000")
+
+	prices := types.AggregatedProviderPrices{
+		provider.ProviderBinance: {
+			atomUSDT: {Price: sdk.MustNewDecFromStr("11.52"), Volume: volume},
+			ojoUSDT:  {Price: sdk.MustNewDecFromStr("0.051"), Volume: volume},
+			umeeUSDT: {Price: sdk.MustNewDecFromStr("0.003"), Volume: volume},
+		},
+		provider.ProviderKraken: {
+			atomUSDT: {Price: sdk.MustNewDecFromStr("11.55"), Volume: volume},
+			ojoUSDT:  {Price: sdk.MustNewDecFromStr("0.052"), Volume: volume},
+			umeeUSDT: {Price: sdk.MustNewDecFromStr("0.003"), Volume: volume},
+		},
+	}
+	candles := types.AggregatedProviderCandles{
+		provider.ProviderOkx: {
+			atomUSDT: {
+				{Price: sdk.MustNewDecFromStr("11.50"), Volume: volume, TimeStamp: provider.PastUnixTime(1 * time.Minute)},
+			},
+		},
+	}
+	requiredProviders := map[types.CurrencyPair][]types.ProviderName{
+		// okx prices ATOM by its candles only
+		atomUSDT: {provider.ProviderBinance, provider.ProviderOkx},
+		// okx does not price OJO, which is omitted despite two other providers
+		ojoUSDT: {provider.ProviderOkx},
+	}
+
+	filteredCandles, filteredPrices := FilterRequiredProviders(zerolog.Nop(), candles, prices, requiredProviders)
+
+	require.Equal(t, prices[provider.ProviderBinance][atomUSDT], filteredPrices[provider.ProviderBinance][atomUSDT])
+	require.Equal(t, prices[provider.ProviderKraken][atomUSDT], filteredPrices[provider.ProviderKraken][atomUSDT])
+	require.Equal(t, candles[provider.ProviderOkx][atomUSDT], filteredCandles[provider.ProviderOkx][atomUSDT])
+
+	require.NotContains(t, filteredPrices[provider.ProviderBinance], ojoUSDT)
+	require.NotContains(t, filteredPrices[provider.ProviderKraken], ojoUSDT)
+
+	// pairs without required providers are left untouched
+	require.Equal(t, prices[provider.ProviderKraken][umeeUSDT], filteredPrices[provider.ProviderKraken][umeeUSDT])
+
+	filteredCandles, filteredPrices = FilterRequiredProviders(zerolog.Nop(), candles, prices, nil)
+	require.Equal(t, candles, filteredCandles)
+	require.Equal(t, prices, filteredPrices)
+}
+
+func TestFilterProviderGroups(t *testing.T) {
+	atomUSDT := types.CurrencyPair{Base: "ATOM", Quote: "USDT"}
+	ojoUSDT := types.CurrencyPair{Base: "OJO", Quote: "USDT"}
+	umeeUSDT := types.CurrencyPair{Base: "UMEE", Quote: "USDT"}
+	volume := sdk.MustNewDecFromStr("1994674.34000000")
+
+	prices := types.AggregatedProviderPrices{
+		provider.ProviderBinance: {
+			atomUSDT: {Price: sdk.MustNewDecFromStr("11.52"), Volume: volume},
+			ojoUSDT:  {Price: sdk.MustNewDecFromStr("0.051"), Volume: volume},
+			umeeUSDT: {Price: sdk.MustNewDecFromStr("0.003"), Volume: volume},
+		},
+		provider.ProviderBinanceUS: {
+			atomUSDT: {Price: sdk.MustNewDecFromStr("11.53"), Volume: volume},
+			ojoUSDT:  {Price: sdk.MustNewDecFromStr("0.052"), Volume: volume},
+		},
+	}
+	candles := types.AggregatedProviderCandles{
+		provider.ProviderKraken: {
+			atomUSDT: {
+				{Price: sdk.MustNewDecFromStr("11.50"), Volume: volume, TimeStamp: provider.PastUnixTime(1 * time.Minute)},
+			},
+		},
+	}
+	providerGroups := types.ProviderGroups{
+		provider.ProviderBinance:   "binance",
+		provider.ProviderBinanceUS: "binance",
+	}
+	minProviderGroups := map[types.CurrencyPair]int{
+		// kraken prices ATOM by its candles only, as a second group
+		atomUSDT: 2,
+		// OJO has two providers but both share the binance group
+		ojoUSDT: 2,
+	}
+
+	filteredCandles, filteredPrices := FilterProviderGroups(
+		zerolog.Nop(),
+		candles,
+		prices,
+		providerGroups,
+		minProviderGroups,
+	)
+
+	require.Equal(t, prices[provider.ProviderBinance][atomUSDT], filteredPrices[provider.ProviderBinance][atomUSDT])
+	require.Equal(t, prices[provider.ProviderBinanceUS][atomUSDT], filteredPrices[provider.ProviderBinanceUS][atomUSDT])
+	require.Equal(t, candles[provider.ProviderKraken][atomUSDT], filteredCandles[provider.ProviderKraken][atomUSDT])
+
+	require.NotContains(t, filteredPrices[provider.ProviderBinance], ojoUSDT)
+	require.NotContains(t, filteredPrices[provider.ProviderBinanceUS], ojoUSDT)
+
+	// pairs without min provider groups are left untouched
+	require.Equal(t, prices[provider.ProviderBinance][umeeUSDT], filteredPrices[provider.ProviderBinance][umeeUSDT])
+
+	// ungrouped providers are each an independent group
+	_, filteredPrices = FilterProviderGroups(zerolog.Nop(), candles, prices, nil, minProviderGroups)
+	require.Contains(t, filteredPrices[provider.ProviderBinance], ojoUSDT)
+
+	filteredCandles, filteredPrices = FilterProviderGroups(zerolog.Nop(), candles, prices, providerGroups, nil)
+	require.Equal(t, candles, filteredCandles)
+	require.Equal(t, prices, filteredPrices)
+}
+
+func TestFilterStaleForexRates(t *testing.T) {
+	atomEUR := types.CurrencyPair{Base: "ATOM", Quote: "EUR"}
+	ojoEUR := types.CurrencyPair{Base: "OJO", Quote: "EUR"}
+	eurUSD := types.CurrencyPair{Base: "EUR", Quote: "USD"}
+	now := time.Unix(1700000000, 0)
+	candle := func(price string, age time.Duration) types.CandlePrice {
+		return types.CandlePrice{
+			Price:     sdk.MustNewDecFromStr(price),
+			Volume:    sdk.OneDec(),
+			TimeStamp: now.Add(-age).UnixMilli(),
+		}
+	}
+
+	candles := types.AggregatedProviderCandles{
+		provider.ProviderKraken: {
+			atomEUR: {candle("10.5", 10*time.Second)},
+		},
+		provider.ProviderPolygon: {
+			eurUSD: {candle("1.08", 5*time.Minute), candle("1.09", 3*time.Minute)},
+		},
+	}
+	prices := types.AggregatedProviderPrices{
+		provider.ProviderKraken: {
+			atomEUR: {Price: sdk.MustNewDecFromStr("10.5"), Volume: sdk.OneDec()},
+			ojoEUR:  {Price: sdk.MustNewDecFromStr("0.05"), Volume: sdk.OneDec()},
+		},
+	}
+
+	// the EUR rate is fresh enough for OJO only
+	maxForexAges := map[types.CurrencyPair]time.Duration{
+		atomEUR: 2 * time.Minute,
+		ojoEUR:  5 * time.Minute,
+	}
+	filteredCandles, filteredPrices := FilterStaleForexRates(zerolog.Nop(), candles, prices, maxForexAges, now)
+	require.NotContains(t, filteredCandles[provider.ProviderKraken], atomEUR)
+	require.NotContains(t, filteredPrices[provider.ProviderKraken], atomEUR)
+	require.Contains(t, filteredPrices[provider.ProviderKraken], ojoEUR)
+	require.Equal(t, candles[provider.ProviderPolygon][eurUSD], filteredCandles[provider.ProviderPolygon][eurUSD])
+
+	// a forex rate without candles is stale
+	delete(candles, provider.ProviderPolygon)
+	_, filteredPrices = FilterStaleForexRates(zerolog.Nop(), candles, prices, maxForexAges, now)
+	require.Empty(t, filteredPrices[provider.ProviderKraken])
+
+	filteredCandles, filteredPrices = FilterStaleForexRates(zerolog.Nop(), candles, prices, nil, now)
+	require.Equal(t, candles, filteredCandles)
+	require.Equal(t, prices, filteredPrices)
+}
+
+func TestOracle_GetComputedPricesStaleForexRate(t *testing.T) {
+	atomEUR := types.CurrencyPair{Base: "ATOM", Quote: "EUR"}
+	atomUSD := types.CurrencyPair{Base: "ATOM", Quote: "USD"}
+	eurUSD := types.CurrencyPair{Base: "EUR", Quote: "USD"}
+	candle := func(price string, age time.Duration) types.CandlePrice {
+		return types.CandlePrice{
+			Price:     sdk.MustNewDecFromStr(price),
+			Volume:    sdk.OneDec(),
+			TimeStamp: provider.PastUnixTime(age),
+		}
+	}
+
+	o := &Oracle{
+		logger:           zerolog.Nop(),
+		zeroVolumeWeight: sdk.ZeroDec(),
+		providerPairs: map[types.ProviderName][]types.CurrencyPair{
+			provider.ProviderKraken:  {atomEUR},
+			provider.ProviderPolygon: {eurUSD},
+		},
+	}
+
+	// the ATOM price is fresh, but the EUR rate is 3 minutes old
+	candles := types.AggregatedProviderCandles{
+		provider.ProviderKraken:  {atomEUR: {candle("10", 10*time.Second)}},
+		provider.ProviderPolygon: {eurUSD: {candle("1.1", 3*time.Minute)}},
+	}
+	prices, err := o.GetComputedPrices(candles, types.AggregatedProviderPrices{})
+	require.NoError(t, err)
+	require.Contains(t, prices, atomUSD)
+
+	o.SetMaxForexAges(map[types.CurrencyPair]time.Duration{atomEUR: 2 * time.Minute})
+	prices, err = o.GetComputedPrices(candles, types.AggregatedProviderPrices{})
+	require.NoError(t, err)
+	require.NotContains(t, prices, atomUSD)
+	require.Contains(t, prices, eurUSD)
+
+	// and priced again once the EUR rate is fresh
+	candles[provider.ProviderPolygon][eurUSD] = []types.CandlePrice{candle("1.1", time.Minute)}
+	prices, err = o.GetComputedPrices(candles, types.AggregatedProviderPrices{})
+	require.NoError(t, err)
+	require.Contains(t, prices, atomUSD)
+}
+
+func TestTrimProviders(t *testing.T) {
+	providerPrices := map[types.ProviderName]sdk.Dec{
+		provider.ProviderBinance: sdk.MustNewDecFromStr("10.00"),
+		provider.ProviderKraken:  sdk.MustNewDecFromStr("10.02"),
+		provider.ProviderOkx:     sdk.MustNewDecFromStr("9.99"),
+		provider.ProviderHuobi:   sdk.MustNewDecFromStr("10.30"),
+		provider.ProviderGate:    sdk.MustNewDecFromStr("9.80"),
+	}
+
+	// nothing is dropped within the count
+	require.Empty(t, trimProviders(providerPrices, 5))
+
+	// the median is 10.00, so huobi is furthest, then gate from the new
+	// median of 9.995
+	require.Equal(t, []types.ProviderName{provider.ProviderHuobi}, trimProviders(providerPrices, 4))
+	require.Equal(
+		t,
+		[]types.ProviderName{provider.ProviderHuobi, provider.ProviderGate},
+		trimProviders(providerPrices, 3),
+	)
+
+	// ties are dropped by name
+	require.Equal(t, []types.ProviderName{provider.ProviderBinance}, trimProviders(map[types.ProviderName]sdk.Dec{
+		provider.ProviderBinance: sdk.MustNewDecFromStr("9"),
+		provider.ProviderKraken:  sdk.MustNewDecFromStr("11"),
+	}, 1))
+}
+
+func TestFilterProviderCount(t *testing.T) {
+	atomUSDT := types.CurrencyPair{Base: "ATOM", Quote: "USDT"}
+	ojoUSDT := types.CurrencyPair{Base: "OJO", Quote: "USDT"}
+	volume := sdk.MustNewDecFromStr("1994674.34000000")
+
+	prices := types.AggregatedProviderPrices{
+		provider.ProviderBinance: {
+			atomUSDT: {Price: sdk.MustNewDecFromStr("11.52"), Volume: volume},
+			ojoUSDT:  {Price: sdk.MustNewDecFromStr("0.051"), Volume: volume},
+		},
+		provider.ProviderKraken: {
+			atomUSDT: {Price: sdk.MustNewDecFromStr("11.80"), Volume: volume},
+			ojoUSDT:  {Price: sdk.MustNewDecFromStr("0.060"), Volume: volume},
+		},
+	}
+	candles := types.AggregatedProviderCandles{
+		provider.ProviderOkx: {
+			atomUSDT: {
+				{Price: sdk.MustNewDecFromStr("11.50"), Volume: volume, TimeStamp: provider.PastUnixTime(1 * time.Minute)},
+			},
+		},
+	}
+
+	filteredCandles, filteredPrices := FilterProviderCount(
+		zerolog.Nop(),
+		candles,
+		prices,
+		nil,
+		map[types.CurrencyPair]int{atomUSDT: 2},
+	)
+
+	// kraken is furthest from the median of ATOM
+	require.NotContains(t, filteredPrices[provider.ProviderKraken], atomUSDT)
+	require.Equal(t, prices[provider.ProviderBinance][atomUSDT], filteredPrices[provider.ProviderBinance][atomUSDT])
+	require.Equal(t, candles[provider.ProviderOkx][atomUSDT], filteredCandles[provider.ProviderOkx][atomUSDT])
+
+	// pairs without max providers are left untouched
+	require.Equal(t, prices[provider.ProviderKraken][ojoUSDT], filteredPrices[provider.ProviderKraken][ojoUSDT])
+
+	filteredCandles, filteredPrices = FilterProviderCount(zerolog.Nop(), candles, prices, nil, nil)
+	require.Equal(t, candles, filteredCandles)
+	require.Equal(t, prices, filteredPrices)
+
+	// kraken is priced by its latest candle, leaving okx furthest from the
+	// median of ATOM
+	candles[provider.ProviderKraken] = types.CurrencyPairCandles{
+		atomUSDT: {
+			{Price: sdk.MustNewDecFromStr("11.53"), Volume: volume, TimeStamp: provider.PastUnixTime(1 * time.Minute)},
+		},
+	}
+	spotSources := types.SpotSources{
+		provider.ProviderKraken: {atomUSDT: types.SpotSourceCandle},
+	}
+	filteredCandles, filteredPrices = FilterProviderCount(
+		zerolog.Nop(),
+		candles,
+		prices,
+		spotSources,
+		map[types.CurrencyPair]int{atomUSDT: 2},
+	)
+	require.NotContains(t, filteredCandles[provider.ProviderOkx], atomUSDT)
+	require.Equal(t, prices[provider.ProviderKraken][atomUSDT], filteredPrices[provider.ProviderKraken][atomUSDT])
+	require.Equal(t, prices[provider.ProviderBinance][atomUSDT], filteredPrices[provider.ProviderBinance][atomUSDT])
+}
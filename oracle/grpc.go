@@ -4,8 +4,20 @@ import (
 	"context"
 	"net"
 	"strings"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
 )
 
+// GRPCDialOptions returns the options to dial the Cosmos gRPC service with.
+func GRPCDialOptions() []grpc.DialOption {
+	return []grpc.DialOption{
+		// the Cosmos SDK doesn't support any transport security mechanism
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+		grpc.WithContextDialer(dialerFunc),
+	}
+}
+
 func dialerFunc(_ context.Context, addr string) (net.Conn, error) {
 	return Connect(addr)
 }
@@ -0,0 +1,51 @@
+package oracle
+
+import (
+	"errors"
+	"os"
+
+	"github.com/cosmos/cosmos-sdk/telemetry"
+)
+
+// SetHaltFile sets the path of the kill-switch file which halts voting while
+// it exists, without stopping the oracle from pricing or serving its API. An
+// empty path disables the kill switch.
+func (o *Oracle) SetHaltFile(path string) {
+	o.haltFile = path
+}
+
+// isHalted returns true while the halt file exists, logging when voting is
+// halted or resumed and reporting the state in the vote_halted gauge. A halt
+// file which cannot be checked halts voting, as on-call may have meant to
+// create it.
+func (o *Oracle) isHalted() bool {
+	if o.haltFile == "" {
+		return false
+	}
+
+	halted := true
+	_, err := os.Stat(o.haltFile)
+	switch {
+	case err == nil:
+	case errors.Is(err, os.ErrNotExist):
+		halted = false
+	default:
+		o.logger.Err(err).Str("halt_file", o.haltFile).Msg("failed to check halt file; halting votes")
+	}
+
+	if halted != o.halted {
+		if halted {
+			o.logger.Warn().Str("halt_file", o.haltFile).Msg("halt file found; halting votes")
+		} else {
+			o.logger.Info().Str("halt_file", o.haltFile).Msg("halt file removed; resuming votes")
+		}
+		o.halted = halted
+	}
+
+	if halted {
+		telemetry.SetGauge(1, "vote_halted")
+	} else {
+		telemetry.SetGauge(0, "vote_halted")
+	}
+	return halted
+}
@@ -0,0 +1,30 @@
+package oracle
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/require"
+)
+
+func TestOracle_isHalted(t *testing.T) {
+	haltFile := filepath.Join(t.TempDir(), "halt")
+	o := &Oracle{logger: zerolog.Nop()}
+
+	// the kill switch is opt-in
+	require.False(t, o.isHalted())
+
+	o.SetHaltFile(haltFile)
+	require.False(t, o.isHalted())
+
+	// votes are halted while the file exists and resume once it is removed
+	require.NoError(t, os.WriteFile(haltFile, nil, 0o600))
+	require.True(t, o.isHalted())
+	require.True(t, o.halted)
+
+	require.NoError(t, os.Remove(haltFile))
+	require.False(t, o.isHalted())
+	require.False(t, o.halted)
+}
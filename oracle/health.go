@@ -0,0 +1,121 @@
+package oracle
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"sort"
+	"time"
+
+	"github.com/rs/zerolog"
+
+	"github.com/ojo-network/price-feeder/oracle/provider"
+	"github.com/ojo-network/price-feeder/oracle/types"
+)
+
+// healthStateUnavailable is the state of a provider which failed to be
+// initialized.
+const healthStateUnavailable = "unavailable"
+
+// providerHealthSummary defines the health of a provider reported by the
+// health summary: its connection state, the rate of messages received since
+// the previous summary and how many of its pairs delivered data in the last
+// tick.
+type providerHealthSummary struct {
+	State          string
+	MessagesPerSec float64
+	FreshPairs     int
+	Pairs          int
+}
+
+// logHealthSummaries logs the health summary of the providers every
+// healthSummaryInterval until the context is canceled.
+func (o *Oracle) logHealthSummaries(ctx context.Context) {
+	ticker := time.NewTicker(o.healthSummaryInterval)
+	defer ticker.Stop()
+
+	lastMessages := messageCounts(provider.ProviderHealths())
+	lastTime := o.now()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			now := o.now()
+			healths := provider.ProviderHealths()
+			// the provider pairs are replaced by the dynamic pairs
+			o.pricesMutex.RLock()
+			summaries := o.healthSummaries(healths, o.providerFreshPairs, lastMessages, now.Sub(lastTime))
+			o.pricesMutex.RUnlock()
+
+			o.logHealthSummary(summaries)
+			lastMessages = messageCounts(healths)
+			lastTime = now
+		}
+	}
+}
+
+// healthSummaries returns the health summary of every configured provider,
+// with the message rates computed from the message counts of the previous
+// summary the elapsed time ago. Providers missing from freshPairs failed to
+// be initialized in the last tick.
+func (o *Oracle) healthSummaries(
+	healths map[types.ProviderName]provider.ProviderHealth,
+	freshPairs map[types.ProviderName]int,
+	lastMessages map[types.ProviderName]uint64,
+	elapsed time.Duration,
+) map[types.ProviderName]providerHealthSummary {
+	summaries := make(map[types.ProviderName]providerHealthSummary, len(o.providerPairs))
+	for providerName, pairs := range o.providerPairs {
+		health := healths[providerName]
+		summary := providerHealthSummary{
+			State: health.State(),
+			Pairs: len(pairs),
+		}
+
+		fresh, ok := freshPairs[providerName]
+		if !ok {
+			summary.State = healthStateUnavailable
+		}
+		summary.FreshPairs = fresh
+
+		if elapsed > 0 && health.Messages >= lastMessages[providerName] {
+			rate := float64(health.Messages-lastMessages[providerName]) / elapsed.Seconds()
+			summary.MessagesPerSec = math.Round(rate*100) / 100
+		}
+		summaries[providerName] = summary
+	}
+	return summaries
+}
+
+// logHealthSummary logs the health summaries of the providers in a single
+// line.
+func (o *Oracle) logHealthSummary(summaries map[types.ProviderName]providerHealthSummary) {
+	providerNames := make([]types.ProviderName, 0, len(summaries))
+	for providerName := range summaries {
+		providerNames = append(providerNames, providerName)
+	}
+	sort.Slice(providerNames, func(i, j int) bool {
+		return providerNames[i] < providerNames[j]
+	})
+
+	providers := zerolog.Dict()
+	for _, providerName := range providerNames {
+		summary := summaries[providerName]
+		providers.Dict(providerName.String(), zerolog.Dict().
+			Str("state", summary.State).
+			Float64("messages_per_sec", summary.MessagesPerSec).
+			Str("fresh_pairs", fmt.Sprintf("%d/%d", summary.FreshPairs, summary.Pairs)),
+		)
+	}
+	o.logger.Info().Dict("providers", providers).Msg("provider health summary")
+}
+
+// messageCounts returns the number of messages received from each provider.
+func messageCounts(healths map[types.ProviderName]provider.ProviderHealth) map[types.ProviderName]uint64 {
+	counts := make(map[types.ProviderName]uint64, len(healths))
+	for providerName, health := range healths {
+		counts[providerName] = health.Messages
+	}
+	return counts
+}
@@ -0,0 +1,63 @@
+package oracle
+
+import (
+	"testing"
+	"time"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ojo-network/price-feeder/oracle/client"
+	"github.com/ojo-network/price-feeder/oracle/provider"
+	"github.com/ojo-network/price-feeder/oracle/types"
+)
+
+func TestOracle_healthSummaries(t *testing.T) {
+	o := New(
+		zerolog.Nop(),
+		client.OracleClient{},
+		map[types.ProviderName][]types.CurrencyPair{
+			provider.ProviderBinance: {OJOUSDT, XBTUSDT},
+			provider.ProviderKraken:  {ATOMUSD},
+			provider.ProviderOsmosis: {OSMOUSD},
+		},
+		time.Millisecond*100,
+		make(map[string]sdk.Dec),
+		make(map[types.ProviderName]provider.Endpoint),
+	)
+
+	summaries := o.healthSummaries(
+		map[types.ProviderName]provider.ProviderHealth{
+			provider.ProviderBinance: {Connections: 2, Open: 1, Messages: 250},
+			provider.ProviderKraken:  {Connections: 1, Open: 1, Messages: 40},
+		},
+		map[types.ProviderName]int{
+			provider.ProviderBinance: 1,
+			provider.ProviderKraken:  1,
+		},
+		map[types.ProviderName]uint64{
+			provider.ProviderBinance: 50,
+		},
+		time.Minute,
+	)
+
+	require.Equal(t, map[types.ProviderName]providerHealthSummary{
+		provider.ProviderBinance: {
+			State:          provider.HealthStateDegraded,
+			MessagesPerSec: 3.33,
+			FreshPairs:     1,
+			Pairs:          2,
+		},
+		provider.ProviderKraken: {
+			State:          provider.HealthStateConnected,
+			MessagesPerSec: 0.67,
+			FreshPairs:     1,
+			Pairs:          1,
+		},
+		provider.ProviderOsmosis: {
+			State: healthStateUnavailable,
+			Pairs: 1,
+		},
+	}, summaries)
+}
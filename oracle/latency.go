@@ -0,0 +1,164 @@
+package oracle
+
+import (
+	"time"
+
+	"github.com/armon/go-metrics"
+	"github.com/cosmos/cosmos-sdk/telemetry"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+
+	"github.com/ojo-network/price-feeder/oracle/types"
+)
+
+// SetLatencyThresholds sets the thresholds past which the volume of a lagging
+// provider is scaled down, sorted by ascending latency.
+func (o *Oracle) SetLatencyThresholds(latencyThresholds []types.LatencyThreshold) {
+	o.latencyThresholds = latencyThresholds
+}
+
+// providerLatencies returns how far the newest candle of each provider is
+// behind now. Providers without candles have no latency, and candles closing
+// in the future have a zero latency.
+func providerLatencies(
+	providerCandles types.AggregatedProviderCandles,
+	now time.Time,
+) map[types.ProviderName]time.Duration {
+	latencies := make(map[types.ProviderName]time.Duration, len(providerCandles))
+	for providerName, candles := range providerCandles {
+		var newest int64
+		for _, cpCandles := range candles {
+			for _, candle := range cpCandles {
+				if candle.TimeStamp > newest {
+					newest = candle.TimeStamp
+				}
+			}
+		}
+		if newest == 0 {
+			continue
+		}
+
+		latency := now.Sub(time.UnixMilli(newest))
+		if latency < 0 {
+			latency = 0
+		}
+		latencies[providerName] = latency
+
+		telemetry.SetGaugeWithLabels(
+			[]string{"provider", "latency", "seconds"},
+			float32(latency.Seconds()),
+			[]metrics.Label{{Name: "provider", Value: providerName.String()}},
+		)
+	}
+	return latencies
+}
+
+// latencyWeight returns the weight of the highest threshold the latency
+// exceeds, or one if it exceeds none. The thresholds are sorted by ascending
+// latency.
+func latencyWeight(thresholds []types.LatencyThreshold, latency time.Duration) sdk.Dec {
+	weight := sdk.OneDec()
+	for _, threshold := range thresholds {
+		if latency <= threshold.Latency {
+			break
+		}
+		weight = threshold.Weight
+	}
+	return weight
+}
+
+// latencyWeights returns the weight of every provider whose latency exceeds
+// a threshold, and logs the providers whose weight changed since the last
+// tick.
+func (o *Oracle) latencyWeights(latencies map[types.ProviderName]time.Duration) map[types.ProviderName]sdk.Dec {
+	weights := make(map[types.ProviderName]sdk.Dec)
+	for providerName, latency := range latencies {
+		weight := latencyWeight(o.latencyThresholds, latency)
+		if weight.LT(sdk.OneDec()) {
+			weights[providerName] = weight
+		}
+
+		previous, ok := o.providerLatencyWeights[providerName]
+		if !ok {
+			previous = sdk.OneDec()
+		}
+		if weight.Equal(previous) {
+			continue
+		}
+		if weight.LT(previous) {
+			o.logger.Warn().
+				Str("provider", providerName.String()).
+				Dur("latency", latency).
+				Str("weight", weight.String()).
+				Msg("downweighting provider for its latency")
+		} else {
+			o.logger.Info().
+				Str("provider", providerName.String()).
+				Dur("latency", latency).
+				Str("weight", weight.String()).
+				Msg("provider latency recovered")
+		}
+	}
+
+	o.providerLatencyWeights = weights
+	return weights
+}
+
+// WeightProviderLatency scales the volume of the candles and tickers of the
+// given providers by their weight, and excludes the providers with a zero
+// weight. The given candles and tickers are left untouched. Tickers without
+// volume keep the zero volume weight.
+func WeightProviderLatency(
+	candles types.AggregatedProviderCandles,
+	prices types.AggregatedProviderPrices,
+	weights map[types.ProviderName]sdk.Dec,
+) (types.AggregatedProviderCandles, types.AggregatedProviderPrices) {
+	if len(weights) == 0 {
+		return candles, prices
+	}
+
+	weightedCandles := make(types.AggregatedProviderCandles, len(candles))
+	for providerName, providerCandles := range candles {
+		weight, ok := weights[providerName]
+		if !ok {
+			weightedCandles[providerName] = providerCandles
+			continue
+		}
+		if !weight.IsPositive() {
+			continue
+		}
+
+		weightedCandles[providerName] = make(types.CurrencyPairCandles, len(providerCandles))
+		for cp, cpCandles := range providerCandles {
+			weighted := make([]types.CandlePrice, len(cpCandles))
+			for i, candle := range cpCandles {
+				if !candle.Volume.IsNil() {
+					candle.Volume = candle.Volume.Mul(weight)
+				}
+				weighted[i] = candle
+			}
+			weightedCandles[providerName][cp] = weighted
+		}
+	}
+
+	weightedPrices := make(types.AggregatedProviderPrices, len(prices))
+	for providerName, tickers := range prices {
+		weight, ok := weights[providerName]
+		if !ok {
+			weightedPrices[providerName] = tickers
+			continue
+		}
+		if !weight.IsPositive() {
+			continue
+		}
+
+		weightedPrices[providerName] = make(types.CurrencyPairTickers, len(tickers))
+		for cp, ticker := range tickers {
+			if !ticker.Volume.IsNil() {
+				ticker.Volume = ticker.Volume.Mul(weight)
+			}
+			weightedPrices[providerName][cp] = ticker
+		}
+	}
+
+	return weightedCandles, weightedPrices
+}
@@ -0,0 +1,159 @@
+package oracle
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ojo-network/price-feeder/oracle/client"
+	"github.com/ojo-network/price-feeder/oracle/provider"
+	"github.com/ojo-network/price-feeder/oracle/types"
+)
+
+// laggingProvider defines a provider whose candles lag the clock by lag.
+type laggingProvider struct {
+	mockProvider
+	lag *time.Duration
+}
+
+func (m laggingProvider) GetCandlePrices(_ ...types.CurrencyPair) (types.CurrencyPairCandles, error) {
+	candles := make(types.CurrencyPairCandles)
+	for pair, price := range m.prices {
+		candles[pair] = []types.CandlePrice{
+			{
+				Price:     price.Price,
+				TimeStamp: provider.PastUnixTime(*m.lag),
+				Volume:    price.Volume,
+			},
+		}
+	}
+	return candles, nil
+}
+
+var testLatencyThresholds = []types.LatencyThreshold{
+	{Latency: 30 * time.Second, Weight: sdk.MustNewDecFromStr("0.5")},
+	{Latency: 2 * time.Minute, Weight: sdk.ZeroDec()},
+}
+
+func TestLatencyWeight(t *testing.T) {
+	require.Equal(t, sdk.OneDec(), latencyWeight(nil, time.Hour))
+	require.Equal(t, sdk.OneDec(), latencyWeight(testLatencyThresholds, 0))
+	require.Equal(t, sdk.OneDec(), latencyWeight(testLatencyThresholds, 30*time.Second))
+	require.Equal(t, sdk.MustNewDecFromStr("0.5"), latencyWeight(testLatencyThresholds, time.Minute))
+	require.Equal(t, sdk.ZeroDec(), latencyWeight(testLatencyThresholds, 3*time.Minute))
+}
+
+func TestProviderLatencies(t *testing.T) {
+	now := time.Unix(1700000000, 0)
+	candles := types.AggregatedProviderCandles{
+		provider.ProviderBinance: {
+			ATOMUSD: {{TimeStamp: now.Add(-time.Minute).UnixMilli()}, {TimeStamp: now.Add(-2 * time.Minute).UnixMilli()}},
+			XBTUSD:  {{TimeStamp: now.Add(-3 * time.Minute).UnixMilli()}},
+		},
+		provider.ProviderKraken: {
+			ATOMUSD: {{TimeStamp: now.Add(time.Minute).UnixMilli()}},
+		},
+		provider.ProviderOkx: {},
+	}
+
+	require.Equal(t, map[types.ProviderName]time.Duration{
+		provider.ProviderBinance: time.Minute,
+		provider.ProviderKraken:  0,
+	}, providerLatencies(candles, now))
+}
+
+func TestWeightProviderLatency(t *testing.T) {
+	candles := types.AggregatedProviderCandles{
+		provider.ProviderBinance: {ATOMUSD: {{Price: sdk.OneDec(), Volume: sdk.NewDec(100)}}},
+		provider.ProviderKraken:  {ATOMUSD: {{Price: sdk.OneDec(), Volume: sdk.NewDec(100)}}},
+		provider.ProviderOkx:     {ATOMUSD: {{Price: sdk.OneDec(), Volume: sdk.NewDec(100)}}},
+	}
+	prices := types.AggregatedProviderPrices{
+		provider.ProviderBinance: {ATOMUSD: {Price: sdk.OneDec(), Volume: sdk.NewDec(100)}},
+		provider.ProviderKraken:  {ATOMUSD: {Price: sdk.OneDec(), Volume: sdk.NewDec(100)}},
+		provider.ProviderOkx:     {ATOMUSD: {Price: sdk.OneDec()}},
+	}
+
+	weightedCandles, weightedPrices := WeightProviderLatency(candles, prices, map[types.ProviderName]sdk.Dec{
+		provider.ProviderKraken: sdk.MustNewDecFromStr("0.5"),
+		provider.ProviderOkx:    sdk.ZeroDec(),
+	})
+	require.Equal(t, sdk.NewDec(100), weightedCandles[provider.ProviderBinance][ATOMUSD][0].Volume)
+	require.Equal(t, sdk.NewDec(50), weightedCandles[provider.ProviderKraken][ATOMUSD][0].Volume)
+	require.NotContains(t, weightedCandles, provider.ProviderOkx)
+	require.Equal(t, sdk.NewDec(100), weightedPrices[provider.ProviderBinance][ATOMUSD].Volume)
+	require.Equal(t, sdk.NewDec(50), weightedPrices[provider.ProviderKraken][ATOMUSD].Volume)
+	require.NotContains(t, weightedPrices, provider.ProviderOkx)
+
+	// the given candles and tickers are left untouched
+	require.Equal(t, sdk.NewDec(100), candles[provider.ProviderKraken][ATOMUSD][0].Volume)
+	require.Equal(t, sdk.NewDec(100), prices[provider.ProviderKraken][ATOMUSD].Volume)
+}
+
+func TestOracle_SetPricesLatency(t *testing.T) {
+	now := time.Unix(1700000000, 0)
+	provider.SetClock(provider.FixedClock(now))
+	defer provider.SetClock(provider.SystemClock{})
+
+	o := New(
+		zerolog.Nop(),
+		client.OracleClient{},
+		map[types.ProviderName][]types.CurrencyPair{
+			provider.ProviderBinance: {ATOMUSD},
+			provider.ProviderKraken:  {ATOMUSD},
+		},
+		time.Millisecond*100,
+		make(map[string]sdk.Dec),
+		make(map[types.ProviderName]provider.Endpoint),
+	)
+	o.SetLatencyThresholds(testLatencyThresholds)
+
+	binanceLag, krakenLag := 10*time.Second, 10*time.Second
+	o.priceProviders = map[types.ProviderName]provider.Provider{
+		provider.ProviderBinance: laggingProvider{
+			mockProvider: mockProvider{prices: types.CurrencyPairTickers{
+				ATOMUSD: {Price: sdk.MustNewDecFromStr("10"), Volume: sdk.MustNewDecFromStr("1000")},
+			}},
+			lag: &binanceLag,
+		},
+		provider.ProviderKraken: laggingProvider{
+			mockProvider: mockProvider{prices: types.CurrencyPairTickers{
+				ATOMUSD: {Price: sdk.MustNewDecFromStr("10.2"), Volume: sdk.MustNewDecFromStr("1000")},
+			}},
+			lag: &krakenLag,
+		},
+	}
+
+	// both providers are weighted fully while their latency is low
+	require.NoError(t, o.SetPrices(context.Background()))
+	require.Empty(t, o.providerLatencyWeights)
+	require.Equal(t, sdk.MustNewDecFromStr("10.1"), o.GetPrices()[ATOMUSD])
+
+	// kraken is downweighted as its latency grows
+	krakenLag = time.Minute
+	require.NoError(t, o.SetPrices(context.Background()))
+	require.Equal(t, map[types.ProviderName]sdk.Dec{
+		provider.ProviderKraken: sdk.MustNewDecFromStr("0.5"),
+	}, o.providerLatencyWeights)
+	price := o.GetPrices()[ATOMUSD]
+	require.True(t, price.GT(sdk.MustNewDecFromStr("10")))
+	require.True(t, price.LT(sdk.MustNewDecFromStr("10.1")))
+
+	// and excluded once it passes the last threshold
+	krakenLag = 3 * time.Minute
+	require.NoError(t, o.SetPrices(context.Background()))
+	require.Equal(t, map[types.ProviderName]sdk.Dec{
+		provider.ProviderKraken: sdk.ZeroDec(),
+	}, o.providerLatencyWeights)
+	require.Equal(t, sdk.MustNewDecFromStr("10"), o.GetPrices()[ATOMUSD])
+
+	// it recovers once its latency normalizes
+	krakenLag = 10 * time.Second
+	require.NoError(t, o.SetPrices(context.Background()))
+	require.Empty(t, o.providerLatencyWeights)
+	require.Equal(t, sdk.MustNewDecFromStr("10.1"), o.GetPrices()[ATOMUSD])
+}
@@ -4,20 +4,24 @@ import (
 	"context"
 	"crypto/rand"
 	"encoding/hex"
+	"errors"
 	"fmt"
+	"io"
 	"math"
+	mathrand "math/rand"
+	"os"
 	"sort"
 	"strings"
 	"sync"
 	"time"
 
+	"github.com/armon/go-metrics"
 	"github.com/cosmos/cosmos-sdk/telemetry"
 	sdk "github.com/cosmos/cosmos-sdk/types"
 	"github.com/rs/zerolog"
 	oracletypes "github.com/umee-network/umee/v6/x/oracle/types"
 	"golang.org/x/sync/errgroup"
 	"google.golang.org/grpc"
-	"google.golang.org/grpc/credentials/insecure"
 
 	"github.com/ojo-network/price-feeder/config"
 	"github.com/ojo-network/price-feeder/oracle/client"
@@ -70,9 +74,165 @@ type Oracle struct {
 	pricesMutex     sync.RWMutex
 	lastPriceSyncTS time.Time
 	prices          types.CurrencyPairDec
+	lastGoodPrices  map[types.CurrencyPair]lastGoodPrice
 
 	tvwapsByProvider types.PricesWithMutex
 	vwapsByProvider  types.PricesWithMutex
+
+	priceCache      *PriceCache
+	providerCandles types.AggregatedProviderCandles
+	priceDetails    []types.PriceDetail
+	warmupPrices    types.CurrencyPairDec
+	warmupCandles   types.AggregatedProviderCandles
+	warmupExpiry    time.Time
+
+	// clock tells the time the prices are aggregated and voted at, and is
+	// given to the providers created. The default clock of the provider
+	// package is used if it is not set.
+	clock provider.Clock
+
+	saltSource         io.Reader
+	priceBands         map[string]types.PriceBand
+	providerRoles      types.ProviderRoles
+	providerAgreements map[types.CurrencyPair]types.ProviderAgreement
+	requiredProviders  map[types.CurrencyPair][]types.ProviderName
+	providerGroups     types.ProviderGroups
+	minProviderGroups  map[types.CurrencyPair]int
+	maxProviders       map[types.CurrencyPair]int
+	maxForexAges       map[types.CurrencyPair]time.Duration
+	minBookDepths      map[types.CurrencyPair]sdk.Dec
+	maxSpreads         map[types.CurrencyPair]sdk.Dec
+	spotSources        types.SpotSources
+	medianTieBreaks    map[string]types.ProviderName
+	maxBufferedCandles int
+	tvwapWeightings    map[string]types.TvwapWeighting
+	referencePrices    map[string]types.ReferencePrice
+	anchorPairs        map[string]types.AnchorPair
+	conversionRoutes   map[string][]string
+	redemptionRates    map[string]string
+
+	// zeroVolumeWeight is the volume weighting tickers with a zero or missing
+	// volume in their VWAP. They are excluded if it is not positive.
+	zeroVolumeWeight sdk.Dec
+
+	// lastVoteTime is the time the last vote was confirmed on chain, or the
+	// time the oracle started if it has not voted yet.
+	lastVoteTime time.Time
+
+	// voteWarmup is the minimum time after the start before the first vote
+	// and voteWarmupMinProviders the number of providers every asset must be
+	// priced by. baseProviders holds the number of providers which priced each
+	// asset in the last tick.
+	startTime              time.Time
+	voteWarmup             time.Duration
+	voteWarmupMinProviders int
+	voteWarmupDone         bool
+	baseProviders          map[string]int
+
+	// startupPolicy defines how assets priced by fewer than their
+	// providerMins are handled until startupTimeout after the start.
+	startupPolicy  string
+	startupTimeout time.Duration
+	providerMins   map[string]int
+	startupDone    bool
+
+	// haltFile is the path of the kill-switch file halting votes while it
+	// exists, and halted whether it existed in the last tick.
+	haltFile string
+	halted   bool
+
+	// pausedPairs are the pairs omitted from the votes until they are
+	// resumed through the API, guarded by pausedMutex.
+	pausedPairs map[types.CurrencyPair]struct{}
+	pausedMutex sync.RWMutex
+
+	// prevoteFile is the path the pending prevote is stored at until it is
+	// revealed, so a restart within the voting period can still vote.
+	prevoteFile string
+
+	// revealedRates are the exchange rates revealed by the last vote, in
+	// revealedVotePeriod, until their divergence from the on-chain exchange
+	// rates is reported.
+	revealedRates      string
+	revealedVotePeriod float64
+
+	// maxVoteSize is the maximum encoded size of the vote message, which is
+	// trimmed to it by dropping the prices of the bases with the lowest
+	// votePriorities first. It is unlimited if not positive.
+	maxVoteSize    int
+	votePriorities map[string]int
+	voteExponents  map[string]int
+	voteRoundings  map[string]types.VoteRounding
+
+	// maxVoteChanges limits the move of the voted price of the bases from
+	// lastVotePrices, the aggregated prices of the last pre-vote.
+	maxVoteChanges map[string]sdk.Dec
+	lastVotePrices types.CurrencyPairDec
+
+	// voteSafetyMargin is the minimum time that must remain in the voting
+	// period to broadcast a pre-vote or vote.
+	voteSafetyMargin time.Duration
+
+	// spotOnlyBases are the assets aggregated from the spot prices of their
+	// providers only, without TWAP smoothing.
+	spotOnlyBases map[string]struct{}
+
+	// tickerWindows sets the number of recent ticker samples of a provider
+	// pair whose median is used as its ticker price, and tickerSamples holds
+	// those samples.
+	tickerWindows types.TickerWindows
+	tickerSamples map[types.ProviderName]map[types.CurrencyPair][]sdk.Dec
+
+	// latencyThresholds scale down the volume of providers whose newest
+	// candle lags behind the clock. providerLatencyWeights holds the weight
+	// of the providers downweighted in the last tick.
+	latencyThresholds      []types.LatencyThreshold
+	providerLatencyWeights map[types.ProviderName]sdk.Dec
+
+	// healthSummaryInterval is the interval the provider health summary is
+	// logged at, disabled if not positive. providerFreshPairs holds the
+	// number of pairs of each provider initialized in the last tick which
+	// delivered data.
+	healthSummaryInterval time.Duration
+	providerFreshPairs    map[types.ProviderName]int
+
+	// providerSilenceTimeout is set on the endpoint of the providers created,
+	// to reconnect their websocket connections which deliver no messages.
+	providerSilenceTimeout time.Duration
+
+	// providerReconnectCooldown is set on the endpoint of the providers
+	// created, to leave out the prices of the pairs of a websocket connection
+	// for that long after it reconnected.
+	providerReconnectCooldown time.Duration
+
+	// reconcileInterval is how often the pairs expected from each provider
+	// are reconciled with the pairs it recently received market data of,
+	// and reconcileMaxAge how long a pair may go without data before it is
+	// subscribed to again. resubscribed holds when each pair was last
+	// subscribed to again.
+	reconcileInterval time.Duration
+	reconcileMaxAge   time.Duration
+	lastReconcile     time.Time
+	resubscribed      map[types.ProviderName]map[types.CurrencyPair]time.Time
+
+	// dynamicPairs derives the pairs of the accepted denoms without a
+	// configured currency pair every refresh interval, if set. staticPairs
+	// holds the configured provider pairs they are added to.
+	dynamicPairs *types.DynamicPairs
+	staticPairs  map[types.ProviderName][]types.CurrencyPair
+	lastDynamic  time.Time
+
+	// attestor posts the signed prices of every voting period to an
+	// external collector, if set.
+	attestor *Attestor
+
+	// webhook posts alerts on significant price moves, omitted pairs and
+	// prices outside of their band.
+	webhook *Webhook
+
+	// statsdExporter exports the aggregated prices and provider health to a
+	// StatsD server, if set.
+	statsdExporter *StatsdExporter
 }
 
 func New(
@@ -92,13 +252,223 @@ func New(
 		previousPrevote: nil,
 		providerTimeout: providerTimeout,
 		deviations:      deviations,
-		paramCache:      ParamCache{},
+		paramCache:      ParamCache{ttl: paramsCacheTTL},
 		endpoints:       endpoints,
+		saltSource:      rand.Reader,
+
+		providerSilenceTimeout:    provider.DefaultSilenceTimeout,
+		providerReconnectCooldown: provider.DefaultReconnectCooldown,
+	}
+}
+
+// SetPriceCache sets the on-disk cache the oracle warms up from when started
+// and periodically writes its prices and candles to.
+func (o *Oracle) SetPriceCache(priceCache *PriceCache) {
+	o.priceCache = priceCache
+}
+
+// SetPriceBands sets the absolute USD price bands of the assets. Provider
+// prices outside of the band of their asset are not aggregated, and no vote is
+// submitted while an aggregated price is outside of its band.
+func (o *Oracle) SetPriceBands(priceBands map[string]types.PriceBand) {
+	o.priceBands = priceBands
+}
+
+// SetProviderRoles restricts providers to the ticker or candle aggregate of
+// a currency pair. The prices of a provider outside of its role are ignored.
+func (o *Oracle) SetProviderRoles(providerRoles types.ProviderRoles) {
+	o.providerRoles = providerRoles
+}
+
+// SetTvwapWeightings sets how the candles of the assets are weighted by their
+// age in their TVWAP. Assets without a weighting use the linear weighting.
+func (o *Oracle) SetTvwapWeightings(tvwapWeightings map[string]types.TvwapWeighting) {
+	o.tvwapWeightings = tvwapWeightings
+}
+
+// SetReferencePrices sets the reference prices the aggregated USD prices of
+// the assets are compared to. Reference prices are never used in the vote.
+func (o *Oracle) SetReferencePrices(referencePrices map[string]types.ReferencePrice) {
+	o.referencePrices = referencePrices
+}
+
+// SetAnchorPairs sets the stablecoin USD rates aggregated with stricter
+// parameters, which are used both to convert the pairs quoted in the
+// stablecoins and in the vote.
+func (o *Oracle) SetAnchorPairs(anchorPairs map[string]types.AnchorPair) {
+	o.anchorPairs = anchorPairs
+}
+
+// SetProviderAgreements sets the number of providers whose prices of a pair
+// must agree within a band for the pair to be used.
+func (o *Oracle) SetProviderAgreements(providerAgreements map[types.CurrencyPair]types.ProviderAgreement) {
+	o.providerAgreements = providerAgreements
+}
+
+// SetRequiredProviders sets the providers whose price of a pair must be
+// present for the pair to be used, however many other providers price it.
+func (o *Oracle) SetRequiredProviders(requiredProviders map[types.CurrencyPair][]types.ProviderName) {
+	o.requiredProviders = requiredProviders
+}
+
+// SetProviderGroups sets the independence groups of the providers sharing an
+// upstream data source, and the number of distinct groups which must price a
+// pair for it to be used.
+func (o *Oracle) SetProviderGroups(
+	providerGroups types.ProviderGroups,
+	minProviderGroups map[types.CurrencyPair]int,
+) {
+	o.providerGroups = providerGroups
+	o.minProviderGroups = minProviderGroups
+}
+
+// SetMaxProviders sets the number of providers the pairs are trimmed to before
+// they are aggregated, dropping the providers furthest from the median first.
+func (o *Oracle) SetMaxProviders(maxProviders map[types.CurrencyPair]int) {
+	o.maxProviders = maxProviders
+}
+
+// SetMaxForexAges sets the max age of the USD rate of the forex quote of the
+// pairs which are omitted while that rate is stale.
+func (o *Oracle) SetMaxForexAges(maxForexAges map[types.CurrencyPair]time.Duration) {
+	o.maxForexAges = maxForexAges
+}
+
+// SetSpotSources sets whether the spot price of a provider of a pair is its
+// ticker price or the close of its latest candle. Providers without a spot
+// source use their ticker price.
+func (o *Oracle) SetSpotSources(spotSources types.SpotSources) {
+	o.spotSources = spotSources
+}
+
+// SetMedianTieBreaks sets the provider whose price is taken as the median of
+// the providers of a base, rather than the average of the two middle prices,
+// when there is an even number of them and it is one of the two.
+func (o *Oracle) SetMedianTieBreaks(medianTieBreaks map[string]types.ProviderName) {
+	o.medianTieBreaks = medianTieBreaks
+}
+
+// SetMaxBufferedCandles sets the number of candles the providers buffer for
+// each of their pairs, past which the oldest of them are dropped. The candles
+// are only bounded by their retention if it is not set.
+func (o *Oracle) SetMaxBufferedCandles(maxBufferedCandles int) {
+	o.maxBufferedCandles = maxBufferedCandles
+}
+
+// SetZeroVolumeWeight sets the volume weighting tickers with a zero, negative
+// or missing volume in their VWAP. They are excluded by default.
+func (o *Oracle) SetZeroVolumeWeight(zeroVolumeWeight sdk.Dec) {
+	o.zeroVolumeWeight = zeroVolumeWeight
+}
+
+// SetVoteWarmup sets the warm-up after the start during which prices are
+// computed but no vote is submitted. It lasts at least duration and until
+// every asset is priced by at least minProviders providers.
+func (o *Oracle) SetVoteWarmup(duration time.Duration, minProviders int) {
+	o.voteWarmup = duration
+	o.voteWarmupMinProviders = minProviders
+}
+
+// SetSpotOnlyBases sets the assets aggregated from the spot prices of their
+// providers only. Their rate is the median of the ticker price of each
+// provider, or of its latest candle close if it reports no ticker.
+func (o *Oracle) SetSpotOnlyBases(bases map[string]struct{}) {
+	o.spotOnlyBases = bases
+}
+
+// SetMaxVoteSize sets the maximum encoded size of the vote message. A vote
+// exceeding it is trimmed by dropping the prices of the bases with the lowest
+// priority first. A size of zero disables the trimming.
+func (o *Oracle) SetMaxVoteSize(maxVoteSize int, votePriorities map[string]int) {
+	o.maxVoteSize = maxVoteSize
+	o.votePriorities = votePriorities
+}
+
+// SetVoteExponents sets the exponents the prices of the bases are scaled by
+// in the vote, for chains expecting prices of the base unit of the denoms.
+// Prices are aggregated and served in human units.
+func (o *Oracle) SetVoteExponents(voteExponents map[string]int) {
+	o.voteExponents = voteExponents
+}
+
+// SetVoteRoundings sets the decimals the vote values of the bases are rounded
+// to and how. The vote values of other bases are submitted at full precision.
+func (o *Oracle) SetVoteRoundings(voteRoundings map[string]types.VoteRounding) {
+	o.voteRoundings = voteRoundings
+}
+
+// SetAttestor sets the attestor the aggregated prices of every voting period
+// are posted to. The attestations are posted in the background and never
+// block or fail the vote.
+func (o *Oracle) SetAttestor(attestor *Attestor) {
+	o.attestor = attestor
+}
+
+// SetProviderSilenceTimeout sets the maximum duration a websocket connection
+// of a provider may go without delivering a message before it is reconnected.
+// It applies to the providers created afterwards and a zero duration disables
+// silence detection.
+func (o *Oracle) SetProviderSilenceTimeout(timeout time.Duration) {
+	o.providerSilenceTimeout = timeout
+}
+
+// SetProviderReconnectCooldown sets the duration after a websocket reconnect
+// during which the prices of the pairs of the connection are received but not
+// aggregated, as the first messages after a reconnect can be a stale snapshot.
+// It applies to the providers created afterwards and a zero duration disables
+// the cooldown.
+func (o *Oracle) SetProviderReconnectCooldown(cooldown time.Duration) {
+	o.providerReconnectCooldown = cooldown
+}
+
+// SetHealthSummaryInterval sets the interval the provider health summary is
+// logged at. A zero interval disables the summary.
+func (o *Oracle) SetHealthSummaryInterval(interval time.Duration) {
+	o.healthSummaryInterval = interval
+}
+
+// now returns the current time of the clock of the oracle.
+func (o *Oracle) now() time.Time {
+	if o.clock == nil {
+		return provider.Now()
 	}
+	return o.clock.Now()
+}
+
+// SetDeterministic makes the oracle run deterministically for testing. The
+// given clock is used to timestamp and aggregate prices and vote salts are
+// drawn from a pseudo-random source seeded with seed. It must never be used
+// to vote on a live chain, as the salts become predictable.
+func (o *Oracle) SetDeterministic(clock provider.Clock, seed int64) {
+	// the stateless helpers, ex. the TVWAP, still tell the default clock
+	provider.SetClock(clock)
+	o.clock = clock
+	o.saltSource = mathrand.New(mathrand.NewSource(seed)) //nolint:gosec
 }
 
 // Start starts the oracle process in a blocking fashion.
 func (o *Oracle) Start(ctx context.Context) error {
+	o.loadPriceCache()
+	if err := o.loadPrevoteFile(); err != nil {
+		o.logger.Err(err).Str("prevote_file", o.prevoteFile).Msg("failed to restore pending prevote")
+		o.removePrevoteFile()
+	}
+	o.startTime = o.now()
+	o.lastVoteTime = o.startTime
+
+	if o.healthSummaryInterval > 0 {
+		go o.logHealthSummaries(ctx)
+	}
+	if o.statsdExporter != nil {
+		go o.exportStatsd(ctx)
+	}
+	if o.attestor != nil {
+		go o.attestor.Start(ctx)
+	}
+	if o.webhook != nil {
+		go o.webhook.Start(ctx)
+	}
+
 	for {
 		select {
 		case <-ctx.Done():
@@ -110,11 +480,17 @@ func (o *Oracle) Start(ctx context.Context) error {
 			startTime := time.Now()
 
 			if err := o.tick(ctx); err != nil {
+				if errors.Is(err, ErrStartupProviderMins) {
+					return err
+				}
 				telemetry.IncrCounter(1, "failure", "tick")
 				o.logger.Err(err).Msg("oracle tick failed")
 			}
 
-			o.lastPriceSyncTS = time.Now()
+			o.lastPriceSyncTS = o.now()
+			o.writePriceCache()
+
+			telemetry.SetGauge(float32(o.now().Sub(o.lastVoteTime).Seconds()), "seconds_since_last_vote")
 
 			telemetry.MeasureSince(startTime, "runtime", "tick")
 			telemetry.IncrCounter(1, "new", "tick")
@@ -139,6 +515,15 @@ func (o *Oracle) GetLastPriceSyncTimestamp() time.Time {
 	return o.lastPriceSyncTS
 }
 
+// IsWarmingUp returns true while the oracle's prices are still partially
+// derived from the price cache loaded at startup.
+func (o *Oracle) IsWarmingUp() bool {
+	o.pricesMutex.RLock()
+	defer o.pricesMutex.RUnlock()
+
+	return o.warmupPrices != nil
+}
+
 // GetPrices returns a copy of the current prices fetched from the oracle's
 // set of exchange rate providers.
 func (o *Oracle) GetPrices() types.CurrencyPairDec {
@@ -177,6 +562,9 @@ func (o *Oracle) SetPrices(ctx context.Context) error {
 	providerPrices := make(types.AggregatedProviderPrices)
 	providerCandles := make(types.AggregatedProviderCandles)
 	requiredRates := make(map[types.CurrencyPair]struct{})
+	freshPairs := make(map[types.ProviderName]int)
+	bookDepths := make(map[types.ProviderName]map[types.CurrencyPair]sdk.Dec)
+	bestQuotes := make(map[types.ProviderName]map[types.CurrencyPair]provider.BestQuote)
 
 	for providerName, currencyPairs := range o.providerPairs {
 		providerName := providerName
@@ -188,6 +576,7 @@ func (o *Oracle) SetPrices(ctx context.Context) error {
 			o.logger.Error().Err(err).Msgf("failed to initialize %s provider", providerName)
 			continue
 		}
+		freshPairs[providerName] = 0
 
 		for _, pair := range currencyPairs {
 			usdPair := types.CurrencyPair{Base: pair.Base, Quote: config.DenomUSD}
@@ -196,6 +585,11 @@ func (o *Oracle) SetPrices(ctx context.Context) error {
 			}
 		}
 
+		currencyPairs = o.omitReconnectCooldownPairs(providerName, priceProvider, currencyPairs)
+		if len(currencyPairs) == 0 {
+			continue
+		}
+
 		g.Go(func() error {
 			prices := make(types.CurrencyPairTickers, 0)
 			candles := make(types.CurrencyPairCandles, 0)
@@ -235,7 +629,15 @@ func (o *Oracle) SetPrices(ctx context.Context) error {
 				success := SetProviderTickerPricesAndCandles(providerName, providerPrices, providerCandles, prices, candles, pair)
 				if !success {
 					o.logger.Err(fmt.Errorf("failed to find any ticker or candle data for %s from %s", pair, providerName)).Send()
+					continue
 				}
+				freshPairs[providerName]++
+			}
+			if depthProvider, ok := priceProvider.(provider.OrderBookDepthProvider); ok {
+				bookDepths[providerName] = depthProvider.GetOrderBookDepths(currencyPairs...)
+			}
+			if quoteProvider, ok := priceProvider.(provider.BestQuoteProvider); ok {
+				bestQuotes[providerName] = quoteProvider.GetBestQuotes(currencyPairs...)
 			}
 
 			mtx.Unlock()
@@ -246,27 +648,146 @@ func (o *Oracle) SetPrices(ctx context.Context) error {
 	if err := g.Wait(); err != nil {
 		o.logger.Error().Err(err).Msg("failed to get prices from provider")
 	}
+	o.reconcileSubscriptions(o.now())
 
-	computedPrices, err := o.GetComputedPrices(
-		providerCandles,
-		providerPrices,
+	o.pricesMutex.RLock()
+	warmupPrices, warmupCandles := o.warmupPrices, o.warmupCandles
+	warmupExpired := o.now().After(o.warmupExpiry)
+	o.pricesMutex.RUnlock()
+
+	if warmupPrices != nil && warmupExpired {
+		o.logger.Warn().Msg("price cache exceeded its max age before warm-up completed")
+		warmupPrices, warmupCandles = nil, nil
+	}
+
+	usedCache := false
+	computeCandles := providerCandles
+	if warmupCandles != nil {
+		computeCandles, usedCache = mergeWarmupCandles(providerCandles, warmupCandles)
+	}
+
+	latencyWeights := o.latencyWeights(providerLatencies(providerCandles, o.now()))
+	computeCandles, computePrices := WeightProviderLatency(
+		computeCandles,
+		o.windowTickers(providerPrices),
+		latencyWeights,
+	)
+	computeCandles, computePrices = WeightOrderBookDepth(
+		computeCandles,
+		computePrices,
+		o.bookDepthWeights(bookDepths),
 	)
+	computeCandles, computePrices = omitProviderPairs(
+		computeCandles,
+		computePrices,
+		o.spreadExclusions(bestQuotes),
+	)
+
+	detailRecorder := newPriceDetailRecorder(computeCandles, computePrices)
+	computedPrices, err := o.computePrices(computeCandles, computePrices, detailRecorder)
 	if err != nil {
 		return err
 	}
 
+	for cp := range requiredRates {
+		if _, ok := computedPrices[cp]; ok {
+			continue
+		}
+		if price, ok := warmupPrices[cp]; ok {
+			computedPrices[cp] = price
+			usedCache = true
+		}
+	}
+
+	if usedCache {
+		o.logger.Warn().Msg("prices include warm-up data from the price cache")
+		telemetry.IncrCounter(1, "warmup", "tick")
+	} else if warmupPrices != nil {
+		o.logger.Info().Msg("price cache warm-up complete")
+		warmupPrices, warmupCandles = nil, nil
+	}
+	if warmupPrices != nil {
+		telemetry.SetGauge(1, "warmup", "active")
+	} else {
+		telemetry.SetGauge(0, "warmup", "active")
+	}
+
 	for cp := range requiredRates {
 		if _, ok := computedPrices[cp]; !ok {
 			o.logger.Error().Str("asset", cp.String()).Msg("unable to report price for expected asset")
 		}
 	}
 
+	o.checkReferencePrices(ctx, computedPrices)
+	priceDetails := detailRecorder.details(o.RequiredRates(), computedPrices, o.deviations)
+
 	o.pricesMutex.Lock()
 	o.prices = computedPrices
+	o.priceDetails = priceDetails
+	o.recordLastGoodPrices(o.now(), computedPrices)
+	o.providerCandles = providerCandles
+	o.baseProviders = countBaseProviders(providerPrices, providerCandles)
+	o.providerFreshPairs = freshPairs
+	o.warmupPrices = warmupPrices
+	o.warmupCandles = warmupCandles
 	o.pricesMutex.Unlock()
 	return nil
 }
 
+// loadPriceCache warms up the oracle from the price cache if one is set and it
+// is not older than its max age.
+func (o *Oracle) loadPriceCache() {
+	if o.priceCache == nil {
+		return
+	}
+
+	prices, candles, timestamp, err := o.priceCache.Load(o.now())
+	switch {
+	case errors.Is(err, os.ErrNotExist):
+		o.logger.Info().Msg("no price cache found; starting without warm-up")
+		return
+	case errors.Is(err, ErrStalePriceCache):
+		o.logger.Warn().Time("written", timestamp).Msg("ignoring stale price cache")
+		return
+	case err != nil:
+		o.logger.Err(err).Msg("failed to load price cache")
+		return
+	}
+
+	o.pricesMutex.Lock()
+	defer o.pricesMutex.Unlock()
+
+	o.prices = prices
+	o.warmupPrices = prices
+	o.warmupCandles = candles
+	o.warmupExpiry = timestamp.Add(o.priceCache.maxAge)
+
+	o.logger.Info().
+		Time("written", timestamp).
+		Int("prices", len(prices)).
+		Msg("warming up from price cache")
+}
+
+// writePriceCache writes the latest prices and candles to the price cache if
+// a write is due. Nothing is written while warming up, so cached data never
+// outlives its max age.
+func (o *Oracle) writePriceCache() {
+	now := o.now()
+	if o.priceCache == nil || !o.priceCache.isWriteDue(now) {
+		return
+	}
+
+	o.pricesMutex.RLock()
+	defer o.pricesMutex.RUnlock()
+
+	if o.warmupPrices != nil || len(o.prices) == 0 {
+		return
+	}
+	if err := o.priceCache.Write(now, o.prices, o.providerCandles); err != nil {
+		o.logger.Err(err).Msg("failed to write price cache")
+	}
+}
+
 func (o *Oracle) RequiredRates() []types.CurrencyPair {
 	requiredRatesMap := make(map[types.CurrencyPair]struct{})
 	for _, currencyPairs := range o.providerPairs {
@@ -285,39 +806,196 @@ func (o *Oracle) RequiredRates() []types.CurrencyPair {
 	return rates
 }
 
+// conversionPairs returns the currency pairs used to convert prices to USD,
+// which are the supported conversions and the configured forex rates.
+func (o *Oracle) conversionPairs() []types.CurrencyPair {
+	pairs := config.SupportedConversionSlice()
+	for _, cp := range o.RequiredRates() {
+		if config.IsForexPair(cp) {
+			pairs = append(pairs, cp)
+		}
+	}
+	return pairs
+}
+
 func (o *Oracle) GetComputedPrices(
 	providerCandles types.AggregatedProviderCandles,
 	providerPrices types.AggregatedProviderPrices,
 ) (types.CurrencyPairDec, error) {
+	return o.computePrices(providerCandles, providerPrices, nil)
+}
+
+// computePrices computes the prices like GetComputedPrices, recording why
+// provider prices are filtered out with the recorder if it is not nil.
+func (o *Oracle) computePrices(
+	providerCandles types.AggregatedProviderCandles,
+	providerPrices types.AggregatedProviderPrices,
+	recorder *priceDetailRecorder,
+) (types.CurrencyPairDec, error) {
+	providerCandles = FilterCandleProviderRoles(providerCandles, o.providerRoles)
+	providerPrices = FilterTickerProviderRoles(providerPrices, o.providerRoles)
+	recorder.filtered(types.FilterReasonProviderRole, providerCandles, providerPrices)
+
+	providerCandles = FilterCandlePriceBands(o.logger, providerCandles, o.priceBands)
+	providerPrices = FilterTickerPriceBands(o.logger, providerPrices, o.priceBands)
+	recorder.filtered(types.FilterReasonPriceBand, providerCandles, providerPrices)
+
+	providerCandles, providerPrices = FilterProviderAgreement(
+		o.logger,
+		providerCandles,
+		providerPrices,
+		o.spotSources,
+		o.providerAgreements,
+	)
+	recorder.filtered(types.FilterReasonProviderAgreement, providerCandles, providerPrices)
+	providerCandles, providerPrices = FilterRequiredProviders(
+		o.logger,
+		providerCandles,
+		providerPrices,
+		o.requiredProviders,
+	)
+	recorder.filtered(types.FilterReasonRequiredProviders, providerCandles, providerPrices)
+	providerCandles, providerPrices = FilterProviderCount(
+		o.logger,
+		providerCandles,
+		providerPrices,
+		o.spotSources,
+		o.maxProviders,
+	)
+	recorder.filtered(types.FilterReasonMaxProviders, providerCandles, providerPrices)
+	providerCandles, providerPrices = FilterStaleForexRates(
+		o.logger,
+		providerCandles,
+		providerPrices,
+		o.maxForexAges,
+		o.now(),
+	)
+	recorder.filtered(types.FilterReasonStaleForex, providerCandles, providerPrices)
+	providerCandles, providerPrices = FilterProviderGroups(
+		o.logger,
+		providerCandles,
+		providerPrices,
+		o.providerGroups,
+		o.minProviderGroups,
+	)
+	recorder.filtered(types.FilterReasonProviderGroups, providerCandles, providerPrices)
+
+	conversionRates, err := o.calcRates(providerCandles, providerPrices, o.conversionPairs())
+	if err != nil {
+		return nil, err
+	}
 
-	conversionRates, err := CalcCurrencyPairRates(
+	anchorRates, err := CalcAnchorRates(
 		providerCandles,
 		providerPrices,
+		o.anchorPairs,
 		o.deviations,
-		config.SupportedConversionSlice(),
+		o.tvwapWeightings,
+		o.zeroVolumeWeight,
 		o.logger,
 	)
 	if err != nil {
 		return nil, err
 	}
+	applyAnchorRates(conversionRates, anchorRates, o.anchorPairs, false)
 
 	USDRates := ConvertRatesToUSD(conversionRates)
 
 	convertedCandles := ConvertAggregatedCandles(providerCandles, USDRates)
 	convertedTickers := ConvertAggregatedTickers(providerPrices, USDRates)
+	recorder.converted(types.FilterReasonNoConversionRate, convertedCandles, convertedTickers)
+
+	convertedCandles = FilterCandlePriceBands(o.logger, convertedCandles, o.priceBands)
+	convertedTickers = FilterTickerPriceBands(o.logger, convertedTickers, o.priceBands)
+	recorder.converted(types.FilterReasonPriceBand, convertedCandles, convertedTickers)
+
+	prices, err := o.calcRates(convertedCandles, convertedTickers, o.RequiredRates())
+	if err != nil {
+		return nil, err
+	}
 
-	prices, err := CalcCurrencyPairRates(
-		convertedCandles,
-		convertedTickers,
+	routedRates, err := o.calcRoutedRates(providerCandles, providerPrices, USDRates)
+	if err != nil {
+		return nil, err
+	}
+	applyRoutedRates(prices, routedRates, o.conversionRoutes)
+	applyAnchorRates(prices, anchorRates, o.anchorPairs, true)
+	o.applyRedemptionRates(prices, providerPrices)
+
+	return prices, nil
+}
+
+// calcRates computes the rates of the given currency pairs, from the spot
+// prices of the providers for the spot-only assets and with CalcCurrencyPairRates
+// for the others.
+func (o *Oracle) calcRates(
+	candles types.AggregatedProviderCandles,
+	tickers types.AggregatedProviderPrices,
+	currencyPairs []types.CurrencyPair,
+) (types.CurrencyPairDec, error) {
+	var pairs, spotPairs []types.CurrencyPair
+	for _, cp := range currencyPairs {
+		if _, ok := o.spotOnlyBases[cp.Base]; ok {
+			spotPairs = append(spotPairs, cp)
+		} else {
+			pairs = append(pairs, cp)
+		}
+	}
+
+	rates, err := CalcCurrencyPairRates(
+		candles,
+		tickers,
 		o.deviations,
-		o.RequiredRates(),
+		o.tvwapWeightings,
+		o.zeroVolumeWeight,
+		o.medianTieBreaks,
+		pairs,
 		o.logger,
 	)
 	if err != nil {
 		return nil, err
 	}
+	if len(spotPairs) == 0 {
+		return rates, nil
+	}
 
-	return prices, nil
+	spotRates, err := CalcSpotRates(
+		candles,
+		tickers,
+		o.deviations,
+		o.spotSources,
+		o.medianTieBreaks,
+		spotPairs,
+		o.logger,
+	)
+	if err != nil {
+		return nil, err
+	}
+	for cp, rate := range spotRates {
+		rates[cp] = rate
+	}
+	return rates, nil
+}
+
+// applyAnchorRates sets the rates of the anchor pairs to their anchor rate, or
+// removes them if they have none. If existingOnly is set, only the anchor pairs
+// already in rates are set.
+func applyAnchorRates(
+	rates types.CurrencyPairDec,
+	anchorRates types.CurrencyPairDec,
+	anchorPairs map[string]types.AnchorPair,
+	existingOnly bool,
+) {
+	for _, anchorPair := range anchorPairs {
+		if _, ok := rates[anchorPair.Pair]; existingOnly && !ok {
+			continue
+		}
+		if rate, ok := anchorRates[anchorPair.Pair]; ok {
+			rates[anchorPair.Pair] = rate
+		} else {
+			delete(rates, anchorPair.Pair)
+		}
+	}
 }
 
 // SetProviderTickerPricesAndCandles flattens and collects prices for
@@ -354,34 +1032,62 @@ func SetProviderTickerPricesAndCandles(
 // GetParamCache returns the last updated parameters of the x/oracle module
 // if the current ParamCache is outdated, we will query it again.
 func (o *Oracle) GetParamCache(ctx context.Context, currentBlockHeigh int64) (oracletypes.Params, error) {
-	if !o.paramCache.IsOutdated(currentBlockHeigh) {
+	now := o.now()
+	if !o.paramCache.IsOutdated(currentBlockHeigh, now) {
 		return *o.paramCache.params, nil
 	}
 
 	params, err := o.GetParams(ctx)
 	if err != nil {
-		return oracletypes.Params{}, err
+		if o.paramCache.params == nil {
+			return oracletypes.Params{}, err
+		}
+		// a failed refresh keeps voting with the cached params, which the
+		// next tick tries to refresh again
+		o.logger.Warn().Err(err).Msg("failed to refresh the oracle params; using the cached params")
+		return *o.paramCache.params, nil
 	}
 
 	o.checkAcceptList(params)
-	o.paramCache.Update(currentBlockHeigh, params)
+	if o.paramCache.params != nil {
+		o.checkParamChanges(*o.paramCache.params, params)
+	}
+	o.paramCache.Update(currentBlockHeigh, now, params)
 	return params, nil
 }
 
+// checkParamChanges logs changes of the oracle params which affect voting. A
+// changed vote period resets the tracked vote period and prevote, so the
+// submission window is computed from the new vote period instead of being
+// counted as missed votes.
+func (o *Oracle) checkParamChanges(oldParams, newParams oracletypes.Params) {
+	if !oldParams.RewardBand.Equal(newParams.RewardBand) {
+		o.logger.Info().
+			Str("previous_reward_band", oldParams.RewardBand.String()).
+			Str("reward_band", newParams.RewardBand.String()).
+			Msg("oracle reward band changed")
+	}
+
+	if oldParams.VotePeriod != newParams.VotePeriod {
+		o.logger.Warn().
+			Uint64("previous_vote_period", oldParams.VotePeriod).
+			Uint64("vote_period", newParams.VotePeriod).
+			Msg("oracle vote period changed; restarting voting")
+		telemetry.IncrCounter(1, "params", "vote_period", "changed")
+
+		o.previousVotePeriod = 0
+		o.previousPrevote = nil
+		o.removePrevoteFile()
+	}
+}
+
 // GetParams returns the current on-chain parameters of the x/oracle module.
 func (o *Oracle) GetParams(ctx context.Context) (oracletypes.Params, error) {
-	grpcConn, err := grpc.Dial(
-		o.oracleClient.GRPCEndpoint,
-		// the Cosmos SDK doesn't support any transport security mechanism
-		grpc.WithTransportCredentials(insecure.NewCredentials()),
-		grpc.WithContextDialer(dialerFunc),
-	)
+	queryClient, closeConn, err := o.oracleQueryClient()
 	if err != nil {
-		return oracletypes.Params{}, fmt.Errorf("failed to dial Cosmos gRPC service: %w", err)
+		return oracletypes.Params{}, err
 	}
-
-	defer grpcConn.Close()
-	queryClient := oracletypes.NewQueryClient(grpcConn)
+	defer closeConn()
 
 	ctx, cancel := context.WithTimeout(ctx, 15*time.Second)
 	defer cancel()
@@ -394,6 +1100,21 @@ func (o *Oracle) GetParams(ctx context.Context) (oracletypes.Params, error) {
 	return queryResponse.Params, nil
 }
 
+// oracleQueryClient returns a query client of the x/oracle module, using the
+// shared gRPC connection if one is set or dialing a new one otherwise. The
+// returned function closes the dialed connection.
+func (o *Oracle) oracleQueryClient() (oracletypes.QueryClient, func(), error) {
+	if o.oracleClient.GRPCConn != nil {
+		return oracletypes.NewQueryClient(o.oracleClient.GRPCConn.Conn()), func() {}, nil
+	}
+
+	conn, err := grpc.Dial(o.oracleClient.GRPCEndpoint, GRPCDialOptions()...)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to dial Cosmos gRPC service: %w", err)
+	}
+	return oracletypes.NewQueryClient(conn), func() { conn.Close() }, nil
+}
+
 func (o *Oracle) getOrSetProvider(ctx context.Context, providerName types.ProviderName) (provider.Provider, error) {
 	var (
 		priceProvider provider.Provider
@@ -402,16 +1123,30 @@ func (o *Oracle) getOrSetProvider(ctx context.Context, providerName types.Provid
 
 	priceProvider, ok = o.priceProviders[providerName]
 	if !ok {
+		endpoint := o.endpoints[providerName]
+		endpoint.SilenceTimeout = o.providerSilenceTimeout
+		endpoint.ReconnectCooldown = o.providerReconnectCooldown
 		newProvider, err := NewProvider(
 			ctx,
 			providerName,
 			o.logger,
-			o.endpoints[providerName],
-			o.providerPairs[providerName]...,
+			endpoint,
+			o.subscribedPairs(providerName)...,
 		)
 		if err != nil {
 			return nil, err
 		}
+		if err := setMaxCandleGap(o.logger, newProvider, o.endpoints[providerName]); err != nil {
+			return nil, err
+		}
+		if err := setTickerReorderWindow(o.logger, newProvider, o.endpoints[providerName]); err != nil {
+			return nil, err
+		}
+		if clocked, ok := newProvider.(provider.ClockedProvider); ok && o.clock != nil {
+			clocked.SetClock(o.clock)
+		}
+		o.retainCandles(newProvider, providerName)
+		o.boundCandleBuffers(newProvider, providerName)
 		newProvider.StartConnections()
 		priceProvider = newProvider
 		o.priceProviders[providerName] = newProvider
@@ -420,6 +1155,106 @@ func (o *Oracle) getOrSetProvider(ctx context.Context, providerName types.Provid
 	return priceProvider, nil
 }
 
+// setMaxCandleGap makes the provider reject the candles exceeding the max
+// candle gap of its endpoint, if it sets one.
+func setMaxCandleGap(logger zerolog.Logger, priceProvider provider.Provider, endpoint provider.Endpoint) error {
+	gap, ok, err := endpoint.CandleGap()
+	if err != nil || !ok {
+		return err
+	}
+
+	guard, ok := priceProvider.(provider.CandleGapGuard)
+	if !ok {
+		logger.Warn().Str("provider", endpoint.Name.String()).Msg("provider does not support a max candle gap")
+		return nil
+	}
+	logger.Info().
+		Str("provider", endpoint.Name.String()).
+		Dur("max_candle_gap", gap).
+		Msg("rejecting candles older than the newest candle by more than the max gap")
+	guard.SetMaxCandleGap(endpoint.Name, gap)
+	return nil
+}
+
+// setTickerReorderWindow makes the provider buffer its ticks for the ticker
+// reorder window of its endpoint, if it sets one.
+func setTickerReorderWindow(logger zerolog.Logger, priceProvider provider.Provider, endpoint provider.Endpoint) error {
+	window, ok, err := endpoint.ReorderWindow()
+	if err != nil || !ok {
+		return err
+	}
+
+	reorderer, ok := priceProvider.(provider.TickerReorderer)
+	if !ok {
+		logger.Warn().Str("provider", endpoint.Name.String()).Msg("provider does not support a ticker reorder window")
+		return nil
+	}
+	logger.Info().
+		Str("provider", endpoint.Name.String()).
+		Dur("ticker_reorder_window", window).
+		Msg("buffering ticks to use the newest by exchange time")
+	reorderer.SetTickerReorderWindow(window)
+	return nil
+}
+
+// retainCandles makes the provider keep its candles for the longest TWAP
+// window of the bases of its pairs, if any of them sets one.
+func (o *Oracle) retainCandles(priceProvider provider.Provider, providerName types.ProviderName) {
+	var window time.Duration
+	for _, pair := range o.providerPairs[providerName] {
+		if w := o.tvwapWeightings[pair.Base].Window; w > window {
+			window = w
+		}
+	}
+	if window == 0 {
+		return
+	}
+
+	retainer, ok := priceProvider.(provider.CandleRetainer)
+	if !ok {
+		o.logger.Warn().Str("provider", providerName.String()).Msg("provider does not support a twap window")
+		return
+	}
+	retainer.RetainCandles(window)
+}
+
+// boundCandleBuffers makes the provider buffer at most the max buffered
+// candles for each of its pairs, if it is set.
+func (o *Oracle) boundCandleBuffers(priceProvider provider.Provider, providerName types.ProviderName) {
+	if o.maxBufferedCandles <= 0 {
+		return
+	}
+
+	bounder, ok := priceProvider.(provider.CandleBufferBounder)
+	if !ok {
+		o.logger.Debug().Str("provider", providerName.String()).Msg("provider does not buffer candles")
+		return
+	}
+	bounder.SetMaxBufferedCandles(providerName, o.maxBufferedCandles)
+}
+
+// subscribedPairs returns the currency pairs of the provider used in the vote
+// along with the pairs it is the reference price source of.
+func (o *Oracle) subscribedPairs(providerName types.ProviderName) []types.CurrencyPair {
+	pairs := o.providerPairs[providerName]
+	for _, referencePrice := range o.referencePrices {
+		if referencePrice.Provider != providerName {
+			continue
+		}
+		subscribed := false
+		for _, pair := range pairs {
+			if pair.Base == referencePrice.Pair.Base && pair.Quote == referencePrice.Pair.Quote {
+				subscribed = true
+				break
+			}
+		}
+		if !subscribed {
+			pairs = append(append([]types.CurrencyPair{}, pairs...), referencePrice.Pair)
+		}
+	}
+	return pairs
+}
+
 func NewProvider(
 	ctx context.Context,
 	providerName types.ProviderName,
@@ -427,6 +1262,21 @@ func NewProvider(
 	endpoint provider.Endpoint,
 	providerPairs ...types.CurrencyPair,
 ) (provider.Provider, error) {
+	endpoint, err := provider.ConfigureTLS(logger, endpoint)
+	if err != nil {
+		return nil, err
+	}
+	endpoint, err = provider.ConfigureHeaders(logger, endpoint)
+	if err != nil {
+		return nil, err
+	}
+	if err := provider.SetSubscriptionBatching(logger, endpoint); err != nil {
+		return nil, err
+	}
+	if err := provider.SetMessageCompression(logger, endpoint); err != nil {
+		return nil, err
+	}
+
 	switch providerName {
 	case provider.ProviderBinance:
 		return provider.NewBinanceProvider(ctx, logger, endpoint, false, providerPairs...)
@@ -458,6 +1308,21 @@ func NewProvider(
 	case provider.ProviderMexc:
 		return provider.NewMexcProvider(ctx, logger, endpoint, providerPairs...)
 
+	case provider.ProviderLbank:
+		return provider.NewLbankProvider(ctx, logger, endpoint, providerPairs...)
+
+	case provider.ProviderBingx:
+		return provider.NewBingxProvider(ctx, logger, endpoint, providerPairs...)
+
+	case provider.ProviderXt:
+		return provider.NewXtProvider(ctx, logger, endpoint, providerPairs...)
+
+	case provider.ProviderCoincheck:
+		return provider.NewCoincheckProvider(ctx, logger, endpoint, providerPairs...)
+
+	case provider.ProviderAscendex:
+		return provider.NewAscendexProvider(ctx, logger, endpoint, providerPairs...)
+
 	case provider.ProviderCrypto:
 		return provider.NewCryptoProvider(ctx, logger, endpoint, providerPairs...)
 
@@ -470,6 +1335,30 @@ func NewProvider(
 	case provider.ProviderKujira:
 		return provider.NewKujiraProvider(ctx, logger, endpoint, providerPairs...)
 
+	case provider.ProviderInjective:
+		return provider.NewInjectiveProvider(ctx, logger, endpoint, providerPairs...)
+
+	case provider.ProviderJupiter:
+		return provider.NewJupiterProvider(ctx, logger, endpoint, providerPairs...)
+
+	case provider.ProviderChainlink:
+		return provider.NewChainlinkProvider(ctx, logger, endpoint, providerPairs...)
+
+	case provider.ProviderOsmosisTwap:
+		return provider.NewOsmosisTwapProvider(ctx, logger, endpoint, providerPairs...)
+
+	case provider.ProviderUniswapV3:
+		return provider.NewUniswapV3Provider(ctx, logger, endpoint, providerPairs...)
+
+	case provider.ProviderCurve:
+		return provider.NewCurveProvider(ctx, logger, endpoint, providerPairs...)
+
+	case provider.ProviderDydx:
+		return provider.NewDydxProvider(ctx, logger, endpoint, providerPairs...)
+
+	case provider.ProviderRedemptionRate:
+		return provider.NewRedemptionRateProvider(ctx, logger, endpoint, providerPairs...)
+
 	case provider.ProviderMock:
 		return provider.NewMockProvider(), nil
 
@@ -480,6 +1369,212 @@ func NewProvider(
 	return nil, fmt.Errorf("provider %s not found", providerName)
 }
 
+// checkPriceBands returns an error if an aggregated price is outside of the
+// price band of its asset.
+func (o *Oracle) checkPriceBands(prices types.CurrencyPairDec) error {
+	for cp, price := range prices {
+		if band, ok := o.priceBands[cp.Base]; ok && !band.Contains(price) {
+			return fmt.Errorf("refusing to vote: price %s of %s is outside of its price band", price, cp)
+		}
+	}
+	return nil
+}
+
+// omitReconnectCooldownPairs returns the pairs of the provider without those in
+// the cooldown after a reconnect of the websocket connection they are received
+// on.
+func (o *Oracle) omitReconnectCooldownPairs(
+	providerName types.ProviderName,
+	priceProvider provider.Provider,
+	currencyPairs []types.CurrencyPair,
+) []types.CurrencyPair {
+	cooldownProvider, ok := priceProvider.(provider.ReconnectCooldownProvider)
+	if !ok {
+		return currencyPairs
+	}
+
+	pairs := make([]types.CurrencyPair, 0, len(currencyPairs))
+	for _, cp := range currencyPairs {
+		if cooldownProvider.InReconnectCooldown(cp) {
+			o.logger.Debug().Msgf("%s of %s provider is in its reconnect cooldown", cp, providerName)
+			continue
+		}
+		pairs = append(pairs, cp)
+	}
+	return pairs
+}
+
+// omitOutOfBandPrices returns the prices without the pairs whose price is
+// outside of the price band of their asset, counting and logging each of them,
+// so that the other pairs are still voted.
+func (o *Oracle) omitOutOfBandPrices(prices types.CurrencyPairDec) types.CurrencyPairDec {
+	if len(o.priceBands) == 0 {
+		return prices
+	}
+
+	result := make(types.CurrencyPairDec, len(prices))
+	for cp, price := range prices {
+		if band, ok := o.priceBands[cp.Base]; ok && !band.Contains(price) {
+			telemetry.IncrCounterWithLabels(
+				[]string{"vote", "failure", "price_band"},
+				1,
+				[]metrics.Label{{Name: "pair", Value: cp.String()}},
+			)
+			o.logger.Warn().
+				Str("pair", cp.String()).
+				Str("price", price.String()).
+				Msg("omitting vote price outside of its price band")
+			continue
+		}
+		result[cp] = price
+	}
+	return result
+}
+
+// checkReferencePrices compares the aggregated USD prices to their reference
+// price, sets the divergence metric of each of them and logs a warning if the
+// divergence exceeds its threshold. It returns the percent divergences.
+func (o *Oracle) checkReferencePrices(ctx context.Context, prices types.CurrencyPairDec) types.CurrencyPairDec {
+	divergences := make(types.CurrencyPairDec, len(o.referencePrices))
+	for base, referencePrice := range o.referencePrices {
+		usdPair := types.CurrencyPair{Base: base, Quote: config.DenomUSD}
+		price, ok := prices[usdPair]
+		if !ok {
+			continue
+		}
+
+		reference, err := o.getReferencePrice(ctx, referencePrice, prices)
+		if err != nil {
+			o.logger.Debug().Err(err).Str("pair", usdPair.String()).Msg("failed to get reference price")
+			continue
+		}
+
+		// divergence = |price - reference| / reference * 100
+		divergence := price.Sub(reference).Abs().Quo(reference).MulInt64(100)
+		divergences[usdPair] = divergence
+		provider.TelemetryReferenceDivergence(referencePrice.Provider, usdPair, float32(divergence.MustFloat64()))
+
+		if divergence.GT(referencePrice.Threshold) {
+			o.logger.Warn().
+				Str("pair", usdPair.String()).
+				Str("provider", referencePrice.Provider.String()).
+				Str("price", price.String()).
+				Str("reference", reference.String()).
+				Str("divergence", divergence.String()).
+				Msg("aggregated price diverges from its reference price")
+		}
+	}
+	return divergences
+}
+
+// getReferencePrice returns the USD price of the reference, converting it
+// with the aggregated prices if the reference pair is not quoted in USD.
+func (o *Oracle) getReferencePrice(
+	ctx context.Context,
+	referencePrice types.ReferencePrice,
+	prices types.CurrencyPairDec,
+) (sdk.Dec, error) {
+	priceProvider, err := o.getOrSetProvider(ctx, referencePrice.Provider)
+	if err != nil {
+		return sdk.Dec{}, err
+	}
+
+	tickers, err := priceProvider.GetTickerPrices(referencePrice.Pair)
+	if err != nil {
+		return sdk.Dec{}, err
+	}
+	ticker, ok := tickers[referencePrice.Pair]
+	if !ok || !ticker.Price.IsPositive() {
+		return sdk.Dec{}, fmt.Errorf("no reference price for %s from %s", referencePrice.Pair, referencePrice.Provider)
+	}
+
+	if referencePrice.Pair.Quote == config.DenomUSD {
+		return ticker.Price, nil
+	}
+	rate, ok := prices[types.CurrencyPair{Base: referencePrice.Pair.Quote, Quote: config.DenomUSD}]
+	if !ok {
+		return sdk.Dec{}, fmt.Errorf("no USD price to convert the reference price of %s", referencePrice.Pair)
+	}
+	return ticker.Price.Mul(rate), nil
+}
+
+// inVoteWarmup returns true until the vote warm-up completes and reports the
+// warm-up state in the vote_warmup gauge.
+func (o *Oracle) inVoteWarmup() bool {
+	if !o.voteWarmupDone && o.isVoteWarmupComplete() {
+		o.logger.Info().Msg("vote warm-up complete")
+		o.voteWarmupDone = true
+	}
+
+	if o.voteWarmupDone {
+		telemetry.SetGauge(0, "vote_warmup")
+		return false
+	}
+	telemetry.SetGauge(1, "vote_warmup")
+	return true
+}
+
+// isVoteWarmupComplete returns true once the warm-up duration has elapsed and
+// every asset was priced by the minimum number of providers in the last tick.
+func (o *Oracle) isVoteWarmupComplete() bool {
+	if remaining := o.voteWarmup - o.now().Sub(o.startTime); remaining > 0 {
+		o.logger.Debug().Dur("remaining", remaining).Msg("vote warm-up in progress")
+		return false
+	}
+	if o.voteWarmupMinProviders <= 0 {
+		return true
+	}
+
+	o.pricesMutex.RLock()
+	defer o.pricesMutex.RUnlock()
+
+	for _, cp := range o.RequiredRates() {
+		if providers := o.baseProviders[cp.Base]; providers < o.voteWarmupMinProviders {
+			o.logger.Debug().
+				Str("asset", cp.Base).
+				Int("providers", providers).
+				Int("min_providers", o.voteWarmupMinProviders).
+				Msg("vote warm-up waiting for providers")
+			return false
+		}
+	}
+	return true
+}
+
+// countBaseProviders returns the number of providers with a ticker or candles
+// of each base asset.
+func countBaseProviders(
+	providerPrices types.AggregatedProviderPrices,
+	providerCandles types.AggregatedProviderCandles,
+) map[string]int {
+	baseProviders := make(map[string]map[types.ProviderName]struct{})
+	addProvider := func(providerName types.ProviderName, base string) {
+		if _, ok := baseProviders[base]; !ok {
+			baseProviders[base] = make(map[types.ProviderName]struct{})
+		}
+		baseProviders[base][providerName] = struct{}{}
+	}
+
+	for providerName, tickers := range providerPrices {
+		for cp := range tickers {
+			addProvider(providerName, cp.Base)
+		}
+	}
+	for providerName, candles := range providerCandles {
+		for cp, cpCandles := range candles {
+			if len(cpCandles) > 0 {
+				addProvider(providerName, cp.Base)
+			}
+		}
+	}
+
+	counts := make(map[string]int, len(baseProviders))
+	for base, providers := range baseProviders {
+		counts[base] = len(providers)
+	}
+	return counts
+}
+
 func (o *Oracle) checkAcceptList(params oracletypes.Params) {
 	for _, denom := range params.AcceptList {
 		symbol := strings.ToUpper(denom.SymbolDenom)
@@ -505,11 +1600,37 @@ func (o *Oracle) tick(ctx context.Context) error {
 	if err != nil {
 		return err
 	}
+	o.refreshDynamicPairs(oracleParams.AcceptList, o.now())
 
 	if err := o.SetPrices(ctx); err != nil {
 		return err
 	}
 
+	holdVote, err := o.checkStartupPolicy()
+	if err != nil {
+		return err
+	}
+	if holdVote {
+		o.logger.Info().
+			Str("prices", GenerateExchangeRatesString(o.GetPrices())).
+			Msg("skipping vote until every asset reaches its minimum providers")
+		return nil
+	}
+
+	if o.inVoteWarmup() {
+		o.logger.Info().
+			Str("prices", GenerateExchangeRatesString(o.GetPrices())).
+			Msg("skipping vote during warm-up")
+		return nil
+	}
+
+	if o.isHalted() {
+		o.logger.Info().
+			Str("prices", GenerateExchangeRatesString(o.GetPrices())).
+			Msg("skipping vote while halted")
+		return nil
+	}
+
 	// Get oracle vote period, next block height, current vote period, and index
 	// in the vote period.
 	oracleVotePeriod := int64(oracleParams.VotePeriod)
@@ -530,6 +1651,10 @@ func (o *Oracle) tick(ctx context.Context) error {
 		return nil
 	}
 
+	o.reportVoteDivergence(ctx, currentVotePeriod)
+	o.submitAttestation(blockHeight, int64(currentVotePeriod))
+	o.checkAlerts(int64(currentVotePeriod))
+
 	// If we're past the voting period we needed to hit, reset and submit another
 	// prevote.
 	if o.previousVotePeriod != 0 && currentVotePeriod-o.previousVotePeriod != 1 {
@@ -542,10 +1667,28 @@ func (o *Oracle) tick(ctx context.Context) error {
 
 		o.previousVotePeriod = 0
 		o.previousPrevote = nil
+		o.removePrevoteFile()
+		return nil
+	}
+
+	lastBlockReceived, blockTime := o.oracleClient.ChainHeight.GetBlockTiming()
+	remaining, ok := o.checkVoteWindow(
+		oracleVotePeriod-indexInVotePeriod,
+		lastBlockReceived,
+		blockTime,
+		o.now(),
+	)
+	if !ok {
+		o.logger.Warn().
+			Dur("remaining", remaining).
+			Dur("safety_margin", o.voteSafetyMargin).
+			Float64("current_vote_period", currentVotePeriod).
+			Msg("skipping broadcast too close to the end of the voting period")
+		telemetry.IncrCounter(1, "vote", "near_miss")
 		return nil
 	}
 
-	salt, err := GenerateSalt(32)
+	salt, err := generateSalt(o.saltSource, 32)
 	if err != nil {
 		return err
 	}
@@ -555,13 +1698,14 @@ func (o *Oracle) tick(ctx context.Context) error {
 		return err
 	}
 
-	exchangeRatesStr := GenerateExchangeRatesString(o.prices)
-	hash := oracletypes.GetAggregateVoteHash(salt, exchangeRatesStr, valAddr)
-	preVoteMsg := &oracletypes.MsgAggregateExchangeRatePrevote{
-		Hash:      hash.String(), // hash of prices from the oracle
-		Feeder:    o.oracleClient.OracleAddrString,
-		Validator: valAddr.String(),
-	}
+	// the inclusion of the broadcasted tx is confirmed until the end of the
+	// current voting period at most, so the oracle is not held past it
+	voteWindowEnd := nextBlockHeight + oracleVotePeriod - indexInVotePeriod - 1
+
+	voteBuilder := o.oracleClient.GetVoteBuilder()
+	clampedPrices := o.omitOutOfBandPrices(o.omitPausedPairs(o.clampedVotePrices()))
+	exchangeRatesStr := GenerateExchangeRatesString(o.votePrices(clampedPrices, voteBuilder, salt, valAddr))
+	hash := voteBuilder.PrevoteHash(salt, exchangeRatesStr, valAddr) // hash of prices from the oracle
 
 	isPrevoteOnlyTx := o.previousPrevote == nil
 	if isPrevoteOnlyTx {
@@ -570,11 +1714,14 @@ func (o *Oracle) tick(ctx context.Context) error {
 		//
 		// Ref : https://github.com/terra-money/oracle-feeder/blob/baef2a4a02f57a2ffeaa207932b2e03d7fb0fb25/feeder/src/vote.ts#L222
 		o.logger.Info().
-			Str("hash", hash.String()).
-			Str("validator", preVoteMsg.Validator).
-			Str("feeder", preVoteMsg.Feeder).
+			Str("hash", hash).
+			Str("validator", valAddr.String()).
+			Str("feeder", o.oracleClient.OracleAddrString).
+			Bool("warm_up", o.IsWarmingUp()).
 			Msg("broadcasting pre-vote")
-		if err := o.oracleClient.BroadcastTx(nextBlockHeight, oracleVotePeriod*2, preVoteMsg); err != nil {
+		preVoteMsg := voteBuilder.PrevoteMsg(hash, o.oracleClient.OracleAddrString, valAddr.String())
+		_, err := o.oracleClient.BroadcastTx(nextBlockHeight, oracleVotePeriod*2, voteWindowEnd, preVoteMsg)
+		if err != nil && !errors.Is(err, client.ErrTxUnconfirmed) {
 			return err
 		}
 
@@ -589,44 +1736,117 @@ func (o *Oracle) tick(ctx context.Context) error {
 			ExchangeRates:     exchangeRatesStr,
 			SubmitBlockHeight: currentHeight,
 		}
+		o.recordVotePrices(clampedPrices)
+		if err := o.writePrevoteFile(int64(o.previousVotePeriod), hash, o.previousPrevote); err != nil {
+			o.logger.Err(err).Str("prevote_file", o.prevoteFile).Msg("failed to store pending prevote")
+		}
 	} else {
 		// otherwise, we're in the next voting period and thus we vote
-		voteMsg := &oracletypes.MsgAggregateExchangeRateVote{
-			Salt:          o.previousPrevote.Salt,
-			ExchangeRates: o.previousPrevote.ExchangeRates,
-			Feeder:        o.oracleClient.OracleAddrString,
-			Validator:     valAddr.String(),
-		}
+		voteMsg := voteBuilder.VoteMsg(
+			o.previousPrevote.Salt,
+			o.previousPrevote.ExchangeRates,
+			o.oracleClient.OracleAddrString,
+			valAddr.String(),
+		)
 
 		o.logger.Info().
-			Str("exchange_rates", voteMsg.ExchangeRates).
-			Str("validator", voteMsg.Validator).
-			Str("feeder", voteMsg.Feeder).
+			Str("exchange_rates", o.previousPrevote.ExchangeRates).
+			Str("validator", valAddr.String()).
+			Str("feeder", o.oracleClient.OracleAddrString).
 			Msg("broadcasting vote")
-		if err := o.oracleClient.BroadcastTx(
+		voteHeight, err := o.oracleClient.BroadcastTx(
 			nextBlockHeight,
 			oracleVotePeriod-indexInVotePeriod,
+			voteWindowEnd,
 			voteMsg,
-		); err != nil {
+		)
+		if err != nil && !errors.Is(err, client.ErrTxUnconfirmed) {
 			return err
 		}
 
+		o.lastVoteTime = o.now()
+		if voteHeight > 0 {
+			telemetry.SetGauge(float32(voteHeight), "last_vote_height")
+		}
+
+		o.revealedRates = o.previousPrevote.ExchangeRates
+		o.revealedVotePeriod = currentVotePeriod
+
 		o.previousPrevote = nil
 		o.previousVotePeriod = 0
+		o.removePrevoteFile()
 	}
 
 	return nil
 }
 
+// submitAttestation queues the attestation of the aggregated prices of the
+// voting period, unless no attestor is set or a price is outside of its band.
+func (o *Oracle) submitAttestation(blockHeight, votePeriod int64) {
+	if o.attestor == nil {
+		return
+	}
+
+	prices := o.GetPrices()
+	if err := o.checkPriceBands(prices); err != nil {
+		o.logger.Warn().Err(err).Msg("skipping price attestation")
+		return
+	}
+
+	o.attestor.Submit(NewPriceAttestation(
+		o.oracleClient.ChainID,
+		o.oracleClient.ValidatorAddrString,
+		o.oracleClient.OracleAddrString,
+		blockHeight,
+		votePeriod,
+		o.now(),
+		prices,
+	))
+}
+
+// votePrices returns the given prices to vote, scaled by the vote exponents,
+// rounded by the vote roundings and trimmed to the max vote size. The vote
+// reveals the prevoted exchange rates with the same salt, so the size of the
+// vote message is known when prevoting.
+func (o *Oracle) votePrices(
+	prices types.CurrencyPairDec,
+	voteBuilder client.OracleVoteBuilder,
+	salt string,
+	valAddr sdk.ValAddress,
+) types.CurrencyPairDec {
+	prices = roundVotePrices(scaleVotePrices(prices, o.voteExponents), o.voteRoundings)
+	if o.maxVoteSize <= 0 {
+		return prices
+	}
+
+	prices, dropped := trimVotePrices(prices, o.votePriorities, o.maxVoteSize, func(exchangeRates string) int {
+		return voteMsgSize(voteBuilder.VoteMsg(salt, exchangeRates, o.oracleClient.OracleAddrString, valAddr.String()))
+	})
+	if len(dropped) > 0 {
+		telemetry.IncrCounter(float32(len(dropped)), "vote", "trimmed")
+		o.logger.Warn().
+			Strs("dropped", dropped).
+			Int("max_vote_size", o.maxVoteSize).
+			Msg("dropped the lowest priority prices from the vote to fit the max vote size")
+	}
+	return prices
+}
+
 // GenerateSalt generates a random salt, size length/2,  as a HEX encoded string.
 func GenerateSalt(length int) (string, error) {
+	return generateSalt(rand.Reader, length)
+}
+
+// generateSalt reads a salt, size length/2, from the given source as a HEX
+// encoded string.
+func generateSalt(source io.Reader, length int) (string, error) {
 	if length == 0 {
 		return "", fmt.Errorf("failed to generate salt: zero length")
 	}
 
 	bytes := make([]byte, length)
 
-	if _, err := rand.Read(bytes); err != nil {
+	if _, err := io.ReadFull(source, bytes); err != nil {
 		return "", err
 	}
 
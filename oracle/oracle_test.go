@@ -383,6 +383,253 @@ func TestGenerateSalt(t *testing.T) {
 	require.NotEmpty(t, salt)
 }
 
+func TestOracle_SetDeterministic(t *testing.T) {
+	t.Cleanup(func() { provider.SetClock(provider.SystemClock{}) })
+
+	newOracle := func() *Oracle {
+		o := New(
+			zerolog.Nop(),
+			client.OracleClient{},
+			map[types.ProviderName][]types.CurrencyPair{},
+			time.Millisecond*100,
+			make(map[string]sdk.Dec),
+			make(map[types.ProviderName]provider.Endpoint),
+		)
+		o.SetDeterministic(provider.FixedClock(time.Unix(1672531200, 0)), 7)
+		return o
+	}
+
+	salt1, err := generateSalt(newOracle().saltSource, 32)
+	require.NoError(t, err)
+	salt2, err := generateSalt(newOracle().saltSource, 32)
+	require.NoError(t, err)
+	require.Equal(t, salt1, salt2)
+
+	require.Equal(t, time.Unix(1672531200, 0), newOracle().now())
+	require.Equal(t, int64(1672531140000), provider.PastUnixTime(time.Minute))
+
+	// candle weights only depend on the fixed clock
+	tvwap, err := ComputeTVWAP(types.AggregatedProviderCandles{
+		provider.ProviderMock: {
+			OJOUSD: {
+				{
+					Price:     sdk.MustNewDecFromStr("3.70"),
+					Volume:    sdk.MustNewDecFromStr("100"),
+					TimeStamp: provider.PastUnixTime(2 * time.Minute),
+				},
+				{
+					Price:     sdk.MustNewDecFromStr("3.80"),
+					Volume:    sdk.MustNewDecFromStr("100"),
+					TimeStamp: provider.PastUnixTime(time.Minute),
+				},
+			},
+		},
+	})
+	require.NoError(t, err)
+	require.Equal(t, "3.799999666668888874", tvwap[OJOUSD].String())
+}
+
+func TestOracle_checkPriceBands(t *testing.T) {
+	o := New(
+		zerolog.Nop(),
+		client.OracleClient{},
+		map[types.ProviderName][]types.CurrencyPair{},
+		time.Millisecond*100,
+		make(map[string]sdk.Dec),
+		make(map[types.ProviderName]provider.Endpoint),
+	)
+	prices := types.CurrencyPairDec{
+		OJOUSD:  sdk.MustNewDecFromStr("3.72"),
+		ATOMUSD: sdk.MustNewDecFromStr("11.52"),
+	}
+	require.NoError(t, o.checkPriceBands(prices))
+
+	o.SetPriceBands(map[string]types.PriceBand{
+		"ATOM": {Min: sdk.MustNewDecFromStr("1"), Max: sdk.MustNewDecFromStr("100")},
+	})
+	require.NoError(t, o.checkPriceBands(prices))
+
+	prices[ATOMUSD] = sdk.MustNewDecFromStr("115.2")
+	require.Error(t, o.checkPriceBands(prices))
+}
+
+func TestOracle_omitOutOfBandPrices(t *testing.T) {
+	o := New(
+		zerolog.Nop(),
+		client.OracleClient{},
+		map[types.ProviderName][]types.CurrencyPair{},
+		time.Millisecond*100,
+		make(map[string]sdk.Dec),
+		make(map[types.ProviderName]provider.Endpoint),
+	)
+	prices := types.CurrencyPairDec{
+		OJOUSD:  sdk.MustNewDecFromStr("3.72"),
+		ATOMUSD: sdk.MustNewDecFromStr("115.2"),
+	}
+	require.Equal(t, prices, o.omitOutOfBandPrices(prices))
+
+	o.SetPriceBands(map[string]types.PriceBand{
+		"ATOM": {Min: sdk.MustNewDecFromStr("1"), Max: sdk.MustNewDecFromStr("100")},
+		"OJO":  {Min: sdk.MustNewDecFromStr("1"), Max: sdk.MustNewDecFromStr("10")},
+	})
+	// only the pair outside of its band is omitted
+	require.Equal(t, types.CurrencyPairDec{
+		OJOUSD: sdk.MustNewDecFromStr("3.72"),
+	}, o.omitOutOfBandPrices(prices))
+}
+
+func TestOracle_checkReferencePrices(t *testing.T) {
+	o := New(
+		zerolog.Nop(),
+		client.OracleClient{},
+		map[types.ProviderName][]types.CurrencyPair{},
+		time.Millisecond*100,
+		make(map[string]sdk.Dec),
+		make(map[types.ProviderName]provider.Endpoint),
+	)
+	o.priceProviders = map[types.ProviderName]provider.Provider{
+		provider.ProviderKraken: mockProvider{
+			prices: types.CurrencyPairTickers{
+				ATOMUSD: {Price: sdk.MustNewDecFromStr("10"), Volume: sdk.OneDec()},
+				OJOUSDT: {Price: sdk.MustNewDecFromStr("4"), Volume: sdk.OneDec()},
+			},
+		},
+	}
+	o.SetReferencePrices(map[string]types.ReferencePrice{
+		"ATOM": {Pair: ATOMUSD, Provider: provider.ProviderKraken, Threshold: sdk.MustNewDecFromStr("1")},
+		"OJO":  {Pair: OJOUSDT, Provider: provider.ProviderKraken, Threshold: sdk.MustNewDecFromStr("1")},
+	})
+
+	// the OJO reference cannot be converted to USD without a USDT price
+	divergences := o.checkReferencePrices(context.Background(), types.CurrencyPairDec{
+		ATOMUSD: sdk.MustNewDecFromStr("10.5"),
+		OJOUSD:  sdk.MustNewDecFromStr("3.72"),
+	})
+	require.Equal(t, types.CurrencyPairDec{ATOMUSD: sdk.MustNewDecFromStr("5")}, divergences)
+
+	divergences = o.checkReferencePrices(context.Background(), types.CurrencyPairDec{
+		ATOMUSD: sdk.MustNewDecFromStr("10"),
+		OJOUSD:  sdk.MustNewDecFromStr("3.9"),
+		USDTUSD: sdk.MustNewDecFromStr("1"),
+	})
+	require.Equal(t, types.CurrencyPairDec{
+		ATOMUSD: sdk.ZeroDec(),
+		OJOUSD:  sdk.MustNewDecFromStr("2.5"),
+	}, divergences)
+}
+
+func TestOracle_inVoteWarmup(t *testing.T) {
+	o := New(
+		zerolog.Nop(),
+		client.OracleClient{},
+		map[types.ProviderName][]types.CurrencyPair{
+			provider.ProviderBinance: {OJOUSDT, XBTUSDT},
+			provider.ProviderKraken:  {XBTUSD},
+		},
+		time.Millisecond*100,
+		make(map[string]sdk.Dec),
+		make(map[types.ProviderName]provider.Endpoint),
+	)
+	o.startTime = time.Now()
+	require.False(t, o.inVoteWarmup())
+
+	o = New(
+		zerolog.Nop(),
+		client.OracleClient{},
+		o.providerPairs,
+		time.Millisecond*100,
+		make(map[string]sdk.Dec),
+		make(map[types.ProviderName]provider.Endpoint),
+	)
+	o.SetVoteWarmup(time.Minute, 2)
+	o.startTime = time.Now()
+	o.baseProviders = map[string]int{"OJO": 2, "XBT": 2}
+	require.True(t, o.inVoteWarmup())
+
+	// the duration elapsed but OJO is only priced by a single provider
+	o.startTime = time.Now().Add(-time.Minute)
+	o.baseProviders = map[string]int{"OJO": 1, "XBT": 2}
+	require.True(t, o.inVoteWarmup())
+
+	o.baseProviders = map[string]int{"OJO": 2, "XBT": 2}
+	require.False(t, o.inVoteWarmup())
+
+	// the warm-up doesn't restart once complete
+	o.baseProviders = map[string]int{}
+	require.False(t, o.inVoteWarmup())
+}
+
+func TestApplyAnchorRates(t *testing.T) {
+	anchorPairs := map[string]types.AnchorPair{
+		"USDT": {Pair: USDTUSD},
+		"USDC": {Pair: USDCUSD},
+		"DAI":  {Pair: DAIUSD},
+	}
+	anchorRates := types.CurrencyPairDec{
+		USDTUSD: sdk.MustNewDecFromStr("0.999"),
+		DAIUSD:  sdk.MustNewDecFromStr("0.95"),
+	}
+
+	rates := types.CurrencyPairDec{
+		USDTUSD: sdk.MustNewDecFromStr("1.02"),
+		USDCUSD: sdk.MustNewDecFromStr("1"),
+		OJOUSD:  sdk.MustNewDecFromStr("3.72"),
+	}
+	applyAnchorRates(rates, anchorRates, anchorPairs, true)
+	require.Equal(t, types.CurrencyPairDec{
+		USDTUSD: sdk.MustNewDecFromStr("0.999"),
+		OJOUSD:  sdk.MustNewDecFromStr("3.72"),
+	}, rates)
+
+	rates = types.CurrencyPairDec{USDCUSD: sdk.MustNewDecFromStr("1")}
+	applyAnchorRates(rates, anchorRates, anchorPairs, false)
+	require.Equal(t, anchorRates, rates)
+}
+
+func TestCountBaseProviders(t *testing.T) {
+	providerPrices := types.AggregatedProviderPrices{
+		provider.ProviderBinance: {
+			XBTUSDT: {Price: sdk.MustNewDecFromStr("10"), Volume: sdk.OneDec()},
+		},
+		provider.ProviderKraken: {
+			XBTUSD: {Price: sdk.MustNewDecFromStr("10"), Volume: sdk.OneDec()},
+		},
+	}
+	providerCandles := types.AggregatedProviderCandles{
+		provider.ProviderBinance: {
+			XBTUSDT: {{Price: sdk.MustNewDecFromStr("10"), Volume: sdk.OneDec()}},
+			OJOUSDT: {{Price: sdk.MustNewDecFromStr("4"), Volume: sdk.OneDec()}},
+		},
+		provider.ProviderKraken: {
+			OJOUSD: {},
+		},
+	}
+
+	require.Equal(t, map[string]int{"XBT": 2, "OJO": 1}, countBaseProviders(providerPrices, providerCandles))
+}
+
+func TestOracle_subscribedPairs(t *testing.T) {
+	o := New(
+		zerolog.Nop(),
+		client.OracleClient{},
+		map[types.ProviderName][]types.CurrencyPair{
+			provider.ProviderBinance: {OJOUSDT},
+		},
+		time.Millisecond*100,
+		make(map[string]sdk.Dec),
+		make(map[types.ProviderName]provider.Endpoint),
+	)
+	o.SetReferencePrices(map[string]types.ReferencePrice{
+		"OJO":  {Pair: OJOUSDT, Provider: provider.ProviderBinance, Threshold: sdk.OneDec()},
+		"ATOM": {Pair: ATOMUSD, Provider: provider.ProviderKraken, Threshold: sdk.OneDec()},
+	})
+
+	require.Equal(t, []types.CurrencyPair{OJOUSDT}, o.subscribedPairs(provider.ProviderBinance))
+	require.Equal(t, []types.CurrencyPair{ATOMUSD}, o.subscribedPairs(provider.ProviderKraken))
+	require.Equal(t, []types.CurrencyPair{OJOUSDT}, o.providerPairs[provider.ProviderBinance])
+	require.Empty(t, o.providerPairs[provider.ProviderKraken])
+}
+
 func TestGenerateExchangeRatesString(t *testing.T) {
 	testCases := map[string]struct {
 		input    types.CurrencyPairDec
@@ -539,6 +786,60 @@ func (ots *OracleTestSuite) TestSuccessGetComputedPricesTickers() {
 	require.Equal(ots.T(), prices[pair], atomPrice)
 }
 
+func (ots *OracleTestSuite) TestGetComputedPricesProviderRoles() {
+	pair := types.CurrencyPair{
+		Base:  "ATOM",
+		Quote: "USD",
+	}
+	atomVolume := sdk.MustNewDecFromStr("894123.00")
+
+	providerCandles := types.AggregatedProviderCandles{
+		provider.ProviderBinance: {
+			pair: {{
+				Price:     sdk.MustNewDecFromStr("29.93"),
+				Volume:    atomVolume,
+				TimeStamp: provider.PastUnixTime(1 * time.Minute),
+			}},
+		},
+		// sparse candles of a provider only trusted for its ticker
+		provider.ProviderKraken: {
+			pair: {{
+				Price:     sdk.MustNewDecFromStr("35.00"),
+				Volume:    atomVolume,
+				TimeStamp: provider.PastUnixTime(1 * time.Minute),
+			}},
+		},
+	}
+	providerPrices := types.AggregatedProviderPrices{
+		provider.ProviderKraken: {
+			pair: {Price: sdk.MustNewDecFromStr("30.01"), Volume: atomVolume},
+		},
+	}
+
+	ots.oracle.providerPairs = map[types.ProviderName][]types.CurrencyPair{
+		provider.ProviderBinance: {pair},
+		provider.ProviderKraken:  {pair},
+	}
+
+	prices, err := ots.oracle.GetComputedPrices(providerCandles, providerPrices)
+	require.NoError(ots.T(), err)
+	require.NotEqual(ots.T(), sdk.MustNewDecFromStr("29.93"), prices[pair])
+
+	ots.oracle.SetProviderRoles(types.ProviderRoles{
+		provider.ProviderKraken: {pair: types.ProviderRoleTicker},
+	})
+	defer ots.oracle.SetProviderRoles(nil)
+
+	prices, err = ots.oracle.GetComputedPrices(providerCandles, providerPrices)
+	require.NoError(ots.T(), err)
+	require.Equal(ots.T(), sdk.MustNewDecFromStr("29.93"), prices[pair])
+
+	// the ticker of the provider is still aggregated without candles
+	prices, err = ots.oracle.GetComputedPrices(make(types.AggregatedProviderCandles), providerPrices)
+	require.NoError(ots.T(), err)
+	require.Equal(ots.T(), sdk.MustNewDecFromStr("30.01"), prices[pair])
+}
+
 func (ots *OracleTestSuite) TestGetComputedPricesCandlesConversion() {
 	btcPair := types.CurrencyPair{
 		Base:  "BTC",
@@ -815,3 +1116,64 @@ func (ots *OracleTestSuite) TestGetComputedPricesEmptyTvwap() {
 		})
 	}
 }
+
+// cooldownProvider defines a provider whose given pairs are in their
+// reconnect cooldown.
+type cooldownProvider struct {
+	mockProvider
+	cooldown map[types.CurrencyPair]bool
+}
+
+func (m cooldownProvider) InReconnectCooldown(cp types.CurrencyPair) bool {
+	return m.cooldown[cp]
+}
+
+func TestOracle_SetPricesReconnectCooldown(t *testing.T) {
+	o := New(
+		zerolog.Nop(),
+		client.OracleClient{},
+		map[types.ProviderName][]types.CurrencyPair{
+			provider.ProviderBinance: {ATOMUSD, XBTUSD},
+			provider.ProviderKraken:  {ATOMUSD},
+		},
+		time.Millisecond*100,
+		make(map[string]sdk.Dec),
+		make(map[types.ProviderName]provider.Endpoint),
+	)
+	binance := cooldownProvider{
+		mockProvider: mockProvider{
+			prices: types.CurrencyPairTickers{
+				ATOMUSD: {Price: sdk.MustNewDecFromStr("10"), Volume: sdk.MustNewDecFromStr("1000")},
+				XBTUSD:  {Price: sdk.MustNewDecFromStr("30000"), Volume: sdk.MustNewDecFromStr("10")},
+			},
+		},
+		cooldown: map[types.CurrencyPair]bool{ATOMUSD: true},
+	}
+	o.priceProviders = map[types.ProviderName]provider.Provider{
+		provider.ProviderBinance: binance,
+		provider.ProviderKraken: mockProvider{
+			prices: types.CurrencyPairTickers{
+				ATOMUSD: {Price: sdk.MustNewDecFromStr("10.2"), Volume: sdk.MustNewDecFromStr("1000")},
+			},
+		},
+	}
+
+	// the binance pair in its reconnect cooldown is left out, not the other
+	require.NoError(t, o.SetPrices(context.Background()))
+	require.Equal(t, map[types.ProviderName]int{
+		provider.ProviderBinance: 1,
+		provider.ProviderKraken:  1,
+	}, o.providerFreshPairs)
+	require.Equal(t, sdk.MustNewDecFromStr("10.2"), o.GetPrices()[ATOMUSD])
+	require.Equal(t, sdk.MustNewDecFromStr("30000"), o.GetPrices()[XBTUSD])
+
+	// and aggregated again once the cooldown elapsed
+	binance.cooldown = nil
+	o.priceProviders[provider.ProviderBinance] = binance
+	require.NoError(t, o.SetPrices(context.Background()))
+	require.Equal(t, map[types.ProviderName]int{
+		provider.ProviderBinance: 2,
+		provider.ProviderKraken:  1,
+	}, o.providerFreshPairs)
+	require.Equal(t, sdk.MustNewDecFromStr("10.1"), o.GetPrices()[ATOMUSD])
+}
@@ -0,0 +1,124 @@
+package oracle
+
+import (
+	"github.com/armon/go-metrics"
+	"github.com/cosmos/cosmos-sdk/telemetry"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+
+	"github.com/ojo-network/price-feeder/oracle/types"
+)
+
+// SetMinBookDepths sets the order book depth of the pairs, in their quote,
+// below which the weight of a provider is scaled down.
+func (o *Oracle) SetMinBookDepths(minBookDepths map[types.CurrencyPair]sdk.Dec) {
+	o.minBookDepths = minBookDepths
+}
+
+// bookDepthWeights returns the weight of every provider pair whose order book
+// depth is below the min book depth of the pair, its depth as a fraction of
+// the min book depth.
+func (o *Oracle) bookDepthWeights(
+	depths map[types.ProviderName]map[types.CurrencyPair]sdk.Dec,
+) map[types.ProviderName]map[types.CurrencyPair]sdk.Dec {
+	weights := make(map[types.ProviderName]map[types.CurrencyPair]sdk.Dec)
+	for providerName, cpDepths := range depths {
+		for cp, depth := range cpDepths {
+			telemetry.SetGaugeWithLabels(
+				[]string{"provider", "order_book", "depth"},
+				float32(depth.MustFloat64()),
+				[]metrics.Label{
+					{Name: "provider", Value: providerName.String()},
+					{Name: "pair", Value: cp.String()},
+				},
+			)
+
+			minDepth, ok := o.minBookDepths[cp]
+			if !ok || depth.GTE(minDepth) {
+				continue
+			}
+			if _, ok := weights[providerName]; !ok {
+				weights[providerName] = make(map[types.CurrencyPair]sdk.Dec)
+			}
+			weights[providerName][cp] = depth.Quo(minDepth)
+
+			o.logger.Debug().
+				Str("provider", providerName.String()).
+				Str("pair", cp.String()).
+				Str("depth", depth.String()).
+				Msg("downweighting provider for its order book depth")
+		}
+	}
+	return weights
+}
+
+// WeightOrderBookDepth scales the volume of the candles and tickers of the
+// given provider pairs by their weight, and excludes the pairs with a zero
+// weight. The given candles and tickers are left untouched. Tickers without
+// volume keep the zero volume weight.
+func WeightOrderBookDepth(
+	candles types.AggregatedProviderCandles,
+	prices types.AggregatedProviderPrices,
+	weights map[types.ProviderName]map[types.CurrencyPair]sdk.Dec,
+) (types.AggregatedProviderCandles, types.AggregatedProviderPrices) {
+	if len(weights) == 0 {
+		return candles, prices
+	}
+
+	weightedCandles := make(types.AggregatedProviderCandles, len(candles))
+	for providerName, providerCandles := range candles {
+		cpWeights, ok := weights[providerName]
+		if !ok {
+			weightedCandles[providerName] = providerCandles
+			continue
+		}
+
+		weightedCandles[providerName] = make(types.CurrencyPairCandles, len(providerCandles))
+		for cp, cpCandles := range providerCandles {
+			weight, ok := cpWeights[cp]
+			if !ok {
+				weightedCandles[providerName][cp] = cpCandles
+				continue
+			}
+			if !weight.IsPositive() {
+				continue
+			}
+
+			weighted := make([]types.CandlePrice, len(cpCandles))
+			for i, candle := range cpCandles {
+				if !candle.Volume.IsNil() {
+					candle.Volume = candle.Volume.Mul(weight)
+				}
+				weighted[i] = candle
+			}
+			weightedCandles[providerName][cp] = weighted
+		}
+	}
+
+	weightedPrices := make(types.AggregatedProviderPrices, len(prices))
+	for providerName, tickers := range prices {
+		cpWeights, ok := weights[providerName]
+		if !ok {
+			weightedPrices[providerName] = tickers
+			continue
+		}
+
+		weightedPrices[providerName] = make(types.CurrencyPairTickers, len(tickers))
+		for cp, ticker := range tickers {
+			weight, ok := cpWeights[cp]
+			if !ok {
+				weightedPrices[providerName][cp] = ticker
+				continue
+			}
+			if !weight.IsPositive() {
+				continue
+			}
+
+			if !ticker.Volume.IsNil() {
+				ticker.Volume = ticker.Volume.Mul(weight)
+			}
+			weightedPrices[providerName][cp] = ticker
+		}
+	}
+
+	return weightedCandles, weightedPrices
+}
@@ -0,0 +1,115 @@
+package oracle
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ojo-network/price-feeder/oracle/client"
+	"github.com/ojo-network/price-feeder/oracle/provider"
+	"github.com/ojo-network/price-feeder/oracle/types"
+)
+
+// depthProvider defines a provider measuring the order book depth of its
+// pairs.
+type depthProvider struct {
+	laggingProvider
+	depths map[types.CurrencyPair]sdk.Dec
+}
+
+func (m depthProvider) GetOrderBookDepths(_ ...types.CurrencyPair) map[types.CurrencyPair]sdk.Dec {
+	return m.depths
+}
+
+func TestWeightOrderBookDepth(t *testing.T) {
+	candles := types.AggregatedProviderCandles{
+		provider.ProviderGate: {
+			ATOMUSD: {{Price: sdk.OneDec(), Volume: sdk.NewDec(100)}},
+			XBTUSD:  {{Price: sdk.OneDec(), Volume: sdk.NewDec(100)}},
+		},
+		provider.ProviderHuobi:  {ATOMUSD: {{Price: sdk.OneDec(), Volume: sdk.NewDec(100)}}},
+		provider.ProviderKraken: {ATOMUSD: {{Price: sdk.OneDec(), Volume: sdk.NewDec(100)}}},
+	}
+	prices := types.AggregatedProviderPrices{
+		provider.ProviderGate: {
+			ATOMUSD: {Price: sdk.OneDec(), Volume: sdk.NewDec(100)},
+			XBTUSD:  {Price: sdk.OneDec(), Volume: sdk.NewDec(100)},
+		},
+		provider.ProviderHuobi:  {ATOMUSD: {Price: sdk.OneDec(), Volume: sdk.NewDec(100)}},
+		provider.ProviderKraken: {ATOMUSD: {Price: sdk.OneDec(), Volume: sdk.NewDec(100)}},
+	}
+
+	weightedCandles, weightedPrices := WeightOrderBookDepth(candles, prices, map[types.ProviderName]map[types.CurrencyPair]sdk.Dec{
+		provider.ProviderGate:  {ATOMUSD: sdk.MustNewDecFromStr("0.25")},
+		provider.ProviderHuobi: {ATOMUSD: sdk.ZeroDec()},
+	})
+	require.Equal(t, sdk.NewDec(25), weightedCandles[provider.ProviderGate][ATOMUSD][0].Volume)
+	require.Equal(t, sdk.NewDec(100), weightedCandles[provider.ProviderGate][XBTUSD][0].Volume)
+	require.NotContains(t, weightedCandles[provider.ProviderHuobi], ATOMUSD)
+	require.Equal(t, sdk.NewDec(100), weightedCandles[provider.ProviderKraken][ATOMUSD][0].Volume)
+	require.Equal(t, sdk.NewDec(25), weightedPrices[provider.ProviderGate][ATOMUSD].Volume)
+	require.Equal(t, sdk.NewDec(100), weightedPrices[provider.ProviderGate][XBTUSD].Volume)
+	require.NotContains(t, weightedPrices[provider.ProviderHuobi], ATOMUSD)
+	require.Equal(t, sdk.NewDec(100), weightedPrices[provider.ProviderKraken][ATOMUSD].Volume)
+
+	// the given candles and tickers are left untouched
+	require.Equal(t, sdk.NewDec(100), candles[provider.ProviderGate][ATOMUSD][0].Volume)
+	require.Equal(t, sdk.NewDec(100), prices[provider.ProviderGate][ATOMUSD].Volume)
+}
+
+func TestOracle_SetPricesOrderBookDepth(t *testing.T) {
+	now := time.Unix(1700000000, 0)
+	provider.SetClock(provider.FixedClock(now))
+	defer provider.SetClock(provider.SystemClock{})
+
+	o := New(
+		zerolog.Nop(),
+		client.OracleClient{},
+		map[types.ProviderName][]types.CurrencyPair{
+			provider.ProviderGate:  {ATOMUSD},
+			provider.ProviderHuobi: {ATOMUSD},
+		},
+		time.Millisecond*100,
+		make(map[string]sdk.Dec),
+		make(map[types.ProviderName]provider.Endpoint),
+	)
+
+	lag := 10 * time.Second
+	gate := depthProvider{
+		laggingProvider: laggingProvider{
+			mockProvider: mockProvider{prices: types.CurrencyPairTickers{
+				ATOMUSD: {Price: sdk.MustNewDecFromStr("10"), Volume: sdk.MustNewDecFromStr("1000")},
+			}},
+			lag: &lag,
+		},
+		depths: map[types.CurrencyPair]sdk.Dec{ATOMUSD: sdk.NewDec(100000)},
+	}
+	huobi := depthProvider{
+		laggingProvider: laggingProvider{
+			mockProvider: mockProvider{prices: types.CurrencyPairTickers{
+				ATOMUSD: {Price: sdk.MustNewDecFromStr("10.2"), Volume: sdk.MustNewDecFromStr("1000")},
+			}},
+			lag: &lag,
+		},
+		depths: map[types.CurrencyPair]sdk.Dec{ATOMUSD: sdk.NewDec(25000)},
+	}
+	o.priceProviders = map[types.ProviderName]provider.Provider{
+		provider.ProviderGate:  gate,
+		provider.ProviderHuobi: huobi,
+	}
+
+	// the depths are ignored without a min book depth
+	require.NoError(t, o.SetPrices(context.Background()))
+	require.Equal(t, sdk.MustNewDecFromStr("10.1"), o.GetPrices()[ATOMUSD])
+
+	// huobi is downweighted for its thin book, without being excluded
+	o.SetMinBookDepths(map[types.CurrencyPair]sdk.Dec{ATOMUSD: sdk.NewDec(50000)})
+	require.NoError(t, o.SetPrices(context.Background()))
+	price := o.GetPrices()[ATOMUSD]
+	require.True(t, price.GT(sdk.MustNewDecFromStr("10")))
+	require.True(t, price.LT(sdk.MustNewDecFromStr("10.1")))
+}
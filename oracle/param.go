@@ -1,34 +1,52 @@
 package oracle
 
-import oracletypes "github.com/umee-network/umee/v6/x/oracle/types"
+import (
+	"time"
+
+	oracletypes "github.com/umee-network/umee/v6/x/oracle/types"
+)
 
 const (
 	// paramsCacheInterval represents the amount of blocks
 	// during which we will cache the oracle params.
 	paramsCacheInterval = int64(200)
+
+	// paramsCacheTTL represents the maximum amount of time during which we
+	// will cache the oracle params, so governance changes to them are picked
+	// up while running.
+	paramsCacheTTL = time.Minute
 )
 
 // ParamCache is used to cache oracle param data for
-// an amount of blocks, defined by paramsCacheInterval.
+// an amount of blocks, defined by paramsCacheInterval,
+// and for at most its ttl if it is set.
 type ParamCache struct {
 	params           *oracletypes.Params
 	lastUpdatedBlock int64
+	lastUpdatedTime  time.Time
+	ttl              time.Duration
 }
 
 // Update retrieves the most recent oracle params and
 // updates the instance.
-func (paramCache *ParamCache) Update(currentBlockHeigh int64, params oracletypes.Params) {
+func (paramCache *ParamCache) Update(currentBlockHeigh int64, now time.Time, params oracletypes.Params) {
 	paramCache.lastUpdatedBlock = currentBlockHeigh
+	paramCache.lastUpdatedTime = now
 	paramCache.params = &params
 }
 
 // IsOutdated checks whether or not the current
-// param data was fetched in the last 200 blocks.
-func (paramCache *ParamCache) IsOutdated(currentBlockHeigh int64) bool {
+// param data was fetched in the last 200 blocks
+// and within its ttl.
+func (paramCache *ParamCache) IsOutdated(currentBlockHeigh int64, now time.Time) bool {
 	if paramCache.params == nil {
 		return true
 	}
 
+	if paramCache.ttl > 0 && now.Sub(paramCache.lastUpdatedTime) > paramCache.ttl {
+		return true
+	}
+
 	if currentBlockHeigh < paramsCacheInterval {
 		return false
 	}
@@ -1,13 +1,23 @@
 package oracle
 
 import (
+	"context"
 	"testing"
+	"time"
 
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/rs/zerolog"
 	"github.com/stretchr/testify/require"
 	oracletypes "github.com/umee-network/umee/v6/x/oracle/types"
+
+	"github.com/ojo-network/price-feeder/oracle/client"
+	"github.com/ojo-network/price-feeder/oracle/provider"
+	"github.com/ojo-network/price-feeder/oracle/types"
 )
 
 func TestParamCacheIsOutdated(t *testing.T) {
+	now := time.Now()
+
 	testCases := map[string]struct {
 		paramCache        ParamCache
 		currentBlockHeigh int64
@@ -45,6 +55,26 @@ func TestParamCacheIsOutdated(t *testing.T) {
 			currentBlockHeigh: 401,
 			expected:          true,
 		},
+		"TTL expired": {
+			paramCache: ParamCache{
+				params:           &oracletypes.Params{},
+				lastUpdatedBlock: 100,
+				lastUpdatedTime:  now.Add(-2 * time.Minute),
+				ttl:              time.Minute,
+			},
+			currentBlockHeigh: 110,
+			expected:          true,
+		},
+		"Within TTL": {
+			paramCache: ParamCache{
+				params:           &oracletypes.Params{},
+				lastUpdatedBlock: 100,
+				lastUpdatedTime:  now.Add(-30 * time.Second),
+				ttl:              time.Minute,
+			},
+			currentBlockHeigh: 110,
+			expected:          false,
+		},
 		"Limit to keep in cache": {
 			paramCache: ParamCache{
 				params:           &oracletypes.Params{},
@@ -59,7 +89,58 @@ func TestParamCacheIsOutdated(t *testing.T) {
 		tc := tc
 
 		t.Run(name, func(t *testing.T) {
-			require.Equal(t, tc.expected, tc.paramCache.IsOutdated(tc.currentBlockHeigh))
+			require.Equal(t, tc.expected, tc.paramCache.IsOutdated(tc.currentBlockHeigh, now))
 		})
 	}
 }
+
+func TestCheckParamChanges(t *testing.T) {
+	o := New(
+		zerolog.Nop(),
+		client.OracleClient{},
+		map[types.ProviderName][]types.CurrencyPair{},
+		time.Millisecond*100,
+		make(map[string]sdk.Dec),
+		make(map[types.ProviderName]provider.Endpoint),
+	)
+	params := oracletypes.DefaultParams()
+
+	o.previousVotePeriod = 42
+	o.previousPrevote = NewPreviousPrevote()
+	o.checkParamChanges(params, params)
+	require.Equal(t, float64(42), o.previousVotePeriod)
+	require.NotNil(t, o.previousPrevote)
+
+	newParams := params
+	newParams.VotePeriod = params.VotePeriod * 2
+	o.checkParamChanges(params, newParams)
+	require.Zero(t, o.previousVotePeriod)
+	require.Nil(t, o.previousPrevote)
+}
+
+func TestOracle_GetParamCacheFailedRefresh(t *testing.T) {
+	o := New(
+		zerolog.Nop(),
+		client.OracleClient{},
+		map[types.ProviderName][]types.CurrencyPair{},
+		time.Millisecond*100,
+		make(map[string]sdk.Dec),
+		make(map[types.ProviderName]provider.Endpoint),
+	)
+	// the params query fails on a canceled context
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	// nothing is cached yet
+	_, err := o.GetParamCache(ctx, 1000)
+	require.Error(t, err)
+
+	params := oracletypes.DefaultParams()
+	o.paramCache.Update(1000, o.now().Add(-2*paramsCacheTTL), params)
+	require.True(t, o.paramCache.IsOutdated(1000, o.now()))
+
+	// the expired params are used while they can't be refreshed
+	cached, err := o.GetParamCache(ctx, 1000)
+	require.NoError(t, err)
+	require.Equal(t, params, cached)
+}
@@ -0,0 +1,116 @@
+package oracle
+
+import (
+	"sort"
+
+	"github.com/armon/go-metrics"
+	"github.com/cosmos/cosmos-sdk/telemetry"
+
+	"github.com/ojo-network/price-feeder/config"
+	"github.com/ojo-network/price-feeder/oracle/types"
+)
+
+// PausePair omits the price of the pair from the votes until it is resumed,
+// without stopping the oracle from pricing it or serving it from its API.
+// Paused pairs are only held in memory, so a restart resumes them. Pairs other
+// than the USD pair of a priced base return ErrUnknownPair.
+func (o *Oracle) PausePair(cp types.CurrencyPair) error {
+	return o.setPairPaused(cp, true)
+}
+
+// ResumePair votes the price of the paused pair again.
+func (o *Oracle) ResumePair(cp types.CurrencyPair) error {
+	return o.setPairPaused(cp, false)
+}
+
+// GetPausedPairs returns the paused pairs sorted by base.
+func (o *Oracle) GetPausedPairs() []types.CurrencyPair {
+	o.pausedMutex.RLock()
+	defer o.pausedMutex.RUnlock()
+
+	pairs := make([]types.CurrencyPair, 0, len(o.pausedPairs))
+	for cp := range o.pausedPairs {
+		pairs = append(pairs, cp)
+	}
+	sort.Slice(pairs, func(i, j int) bool { return pairs[i].String() < pairs[j].String() })
+	return pairs
+}
+
+// setPairPaused pauses or resumes the pair, logging the change and reporting
+// the state in the pair_paused gauge.
+func (o *Oracle) setPairPaused(cp types.CurrencyPair, paused bool) error {
+	cp = types.CurrencyPair{Base: cp.Base, Quote: cp.Quote}
+	if !o.isVotedPair(cp) {
+		return types.ErrUnknownPair.Wrap(cp.String())
+	}
+
+	o.pausedMutex.Lock()
+	defer o.pausedMutex.Unlock()
+
+	_, wasPaused := o.pausedPairs[cp]
+	if paused {
+		if o.pausedPairs == nil {
+			o.pausedPairs = make(map[types.CurrencyPair]struct{})
+		}
+		o.pausedPairs[cp] = struct{}{}
+	} else {
+		delete(o.pausedPairs, cp)
+	}
+
+	value := float32(0)
+	if paused {
+		value = 1
+	}
+	telemetry.SetGaugeWithLabels(
+		[]string{"pair_paused"},
+		value,
+		[]metrics.Label{{Name: "pair", Value: cp.String()}},
+	)
+
+	switch {
+	case paused && !wasPaused:
+		o.logger.Warn().Str("pair", cp.String()).Msg("pair paused; omitting it from votes")
+	case !paused && wasPaused:
+		o.logger.Info().Str("pair", cp.String()).Msg("pair resumed; voting it again")
+	}
+	return nil
+}
+
+// isVotedPair returns true if the pair is the USD pair of a base priced by
+// the oracle, the pairs its votes are made of.
+func (o *Oracle) isVotedPair(cp types.CurrencyPair) bool {
+	if cp.Quote != config.DenomUSD {
+		return false
+	}
+
+	o.pricesMutex.RLock()
+	defer o.pricesMutex.RUnlock()
+
+	for _, pairs := range o.providerPairs {
+		for _, pair := range pairs {
+			if pair.Base == cp.Base {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// omitPausedPairs returns the prices without the paused pairs.
+func (o *Oracle) omitPausedPairs(prices types.CurrencyPairDec) types.CurrencyPairDec {
+	o.pausedMutex.RLock()
+	defer o.pausedMutex.RUnlock()
+
+	if len(o.pausedPairs) == 0 {
+		return prices
+	}
+
+	result := make(types.CurrencyPairDec, len(prices))
+	for cp, price := range prices {
+		if _, ok := o.pausedPairs[types.CurrencyPair{Base: cp.Base, Quote: cp.Quote}]; ok {
+			continue
+		}
+		result[cp] = price
+	}
+	return result
+}
@@ -0,0 +1,54 @@
+package oracle
+
+import (
+	"testing"
+	"time"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ojo-network/price-feeder/oracle/client"
+	"github.com/ojo-network/price-feeder/oracle/provider"
+	"github.com/ojo-network/price-feeder/oracle/types"
+)
+
+func TestOracle_PausePair(t *testing.T) {
+	o := New(
+		zerolog.Nop(),
+		client.OracleClient{},
+		map[types.ProviderName][]types.CurrencyPair{
+			provider.ProviderBinance: {OJOUSDT, {Base: "ATOM", Quote: "USDT"}},
+			provider.ProviderKraken:  {XBTUSD},
+		},
+		time.Millisecond*100,
+		make(map[string]sdk.Dec),
+		make(map[types.ProviderName]provider.Endpoint),
+	)
+
+	ojousd := types.CurrencyPair{Base: "OJO", Quote: "USD"}
+	atomusd := types.CurrencyPair{Base: "ATOM", Quote: "USD"}
+	xbtusd := types.CurrencyPair{Base: "XBT", Quote: "USD"}
+	prices := types.CurrencyPairDec{
+		ojousd:  sdk.MustNewDecFromStr("1.1"),
+		atomusd: sdk.MustNewDecFromStr("10.5"),
+		xbtusd:  sdk.MustNewDecFromStr("30000"),
+	}
+	require.Equal(t, prices, o.omitPausedPairs(prices))
+
+	// only the USD pairs of the priced bases can be paused
+	require.ErrorIs(t, o.PausePair(types.CurrencyPair{Base: "FOO", Quote: "USD"}), types.ErrUnknownPair)
+	require.ErrorIs(t, o.PausePair(OJOUSDT), types.ErrUnknownPair)
+
+	require.NoError(t, o.PausePair(ojousd))
+	require.NoError(t, o.PausePair(atomusd))
+	require.Equal(t, []types.CurrencyPair{atomusd, ojousd}, o.GetPausedPairs())
+	require.Equal(t, types.CurrencyPairDec{xbtusd: prices[xbtusd]}, o.omitPausedPairs(prices))
+
+	require.NoError(t, o.ResumePair(ojousd))
+	require.Equal(t, []types.CurrencyPair{atomusd}, o.GetPausedPairs())
+	require.Equal(t, types.CurrencyPairDec{
+		ojousd: prices[ojousd],
+		xbtusd: prices[xbtusd],
+	}, o.omitPausedPairs(prices))
+}
@@ -0,0 +1,132 @@
+package oracle
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+)
+
+// pendingPrevote defines the on-disk format of the prevote file, the prevote
+// awaiting its reveal in the next voting period.
+type pendingPrevote struct {
+	Validator         string `json:"validator"`
+	VotePeriod        int64  `json:"vote_period"`
+	Hash              string `json:"hash"`
+	Salt              string `json:"salt"`
+	ExchangeRates     string `json:"exchange_rates"`
+	SubmitBlockHeight int64  `json:"submit_block_height"`
+}
+
+// SetPrevoteFile sets the path the salt and exchange rates of the pending
+// prevote are stored at, so that a restart before the reveal can still vote.
+// An empty path keeps the pending prevote in memory only.
+func (o *Oracle) SetPrevoteFile(path string) {
+	o.prevoteFile = path
+}
+
+// writePrevoteFile stores the pending prevote submitted in the vote period.
+// The file is replaced atomically so a crash mid-write never leaves a
+// truncated prevote behind.
+func (o *Oracle) writePrevoteFile(votePeriod int64, hash string, prevote *PreviousPrevote) error {
+	if o.prevoteFile == "" {
+		return nil
+	}
+
+	bz, err := json.Marshal(pendingPrevote{
+		Validator:         o.oracleClient.ValidatorAddrString,
+		VotePeriod:        votePeriod,
+		Hash:              hash,
+		Salt:              prevote.Salt,
+		ExchangeRates:     prevote.ExchangeRates,
+		SubmitBlockHeight: prevote.SubmitBlockHeight,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to encode prevote file: %w", err)
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(o.prevoteFile), filepath.Base(o.prevoteFile)+".tmp")
+	if err != nil {
+		return fmt.Errorf("failed to create prevote file: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(bz); err != nil {
+		tmp.Close()
+		return fmt.Errorf("failed to write prevote file: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("failed to write prevote file: %w", err)
+	}
+	if err := os.Rename(tmp.Name(), o.prevoteFile); err != nil {
+		return fmt.Errorf("failed to write prevote file: %w", err)
+	}
+	return nil
+}
+
+// loadPrevoteFile restores the pending prevote stored before a restart. The
+// prevote is only restored if it was submitted for the oracle's validator
+// and its salt and exchange rates hash to the prevoted hash, so the reveal
+// matches the prevote exactly. Whether it is still in time to be revealed is
+// left to the tick, which discards it once its reveal period passed.
+func (o *Oracle) loadPrevoteFile() error {
+	if o.prevoteFile == "" {
+		return nil
+	}
+
+	bz, err := os.ReadFile(o.prevoteFile)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to read prevote file: %w", err)
+	}
+
+	var pending pendingPrevote
+	if err := json.Unmarshal(bz, &pending); err != nil {
+		return fmt.Errorf("failed to decode prevote file: %w", err)
+	}
+	if pending.Validator != o.oracleClient.ValidatorAddrString {
+		return fmt.Errorf(
+			"prevote file of validator %s does not match validator %s",
+			pending.Validator,
+			o.oracleClient.ValidatorAddrString,
+		)
+	}
+
+	valAddr, err := sdk.ValAddressFromBech32(pending.Validator)
+	if err != nil {
+		return fmt.Errorf("invalid validator of prevote file: %w", err)
+	}
+	hash := o.oracleClient.GetVoteBuilder().PrevoteHash(pending.Salt, pending.ExchangeRates, valAddr)
+	if hash != pending.Hash {
+		return fmt.Errorf("prevote file does not reveal its hash %s", pending.Hash)
+	}
+
+	o.previousVotePeriod = float64(pending.VotePeriod)
+	o.previousPrevote = &PreviousPrevote{
+		Salt:              pending.Salt,
+		ExchangeRates:     pending.ExchangeRates,
+		SubmitBlockHeight: pending.SubmitBlockHeight,
+	}
+
+	o.logger.Info().
+		Str("hash", pending.Hash).
+		Int64("vote_period", pending.VotePeriod).
+		Msg("restored pending prevote")
+	return nil
+}
+
+// removePrevoteFile removes the stored prevote once it was revealed or can no
+// longer be.
+func (o *Oracle) removePrevoteFile() {
+	if o.prevoteFile == "" {
+		return
+	}
+	if err := os.Remove(o.prevoteFile); err != nil && !errors.Is(err, os.ErrNotExist) {
+		o.logger.Err(err).Str("prevote_file", o.prevoteFile).Msg("failed to remove prevote file")
+	}
+}
@@ -0,0 +1,78 @@
+package oracle
+
+import (
+	mathrand "math/rand"
+	"os"
+	"path/filepath"
+	"testing"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ojo-network/price-feeder/oracle/client"
+)
+
+func TestOracle_prevoteFile(t *testing.T) {
+	valAddr := sdk.ValAddress([]byte("validator"))
+	prevoteFile := filepath.Join(t.TempDir(), "prevote.json")
+	newOracle := func(validator string) *Oracle {
+		o := &Oracle{
+			logger:       zerolog.Nop(),
+			oracleClient: client.OracleClient{ValidatorAddrString: validator},
+		}
+		o.SetPrevoteFile(prevoteFile)
+		return o
+	}
+
+	// nothing is restored before the first prevote
+	o := newOracle(valAddr.String())
+	require.NoError(t, o.loadPrevoteFile())
+	require.Nil(t, o.previousPrevote)
+
+	salt, err := generateSalt(mathrand.New(mathrand.NewSource(7)), 32) //nolint:gosec
+	require.NoError(t, err)
+	prevote := &PreviousPrevote{
+		Salt:              salt,
+		ExchangeRates:     "ATOM:10.000000000000000000,OJO:0.010000000000000000",
+		SubmitBlockHeight: 101,
+	}
+	hash := o.oracleClient.GetVoteBuilder().PrevoteHash(prevote.Salt, prevote.ExchangeRates, valAddr)
+	require.NoError(t, o.writePrevoteFile(20, hash, prevote))
+
+	// a restart before the reveal restores the prevote, whose reveal hashes
+	// to the prevoted hash
+	restarted := newOracle(valAddr.String())
+	require.NoError(t, restarted.loadPrevoteFile())
+	require.Equal(t, prevote, restarted.previousPrevote)
+	require.Equal(t, float64(20), restarted.previousVotePeriod)
+	require.Equal(t, hash, restarted.oracleClient.GetVoteBuilder().PrevoteHash(
+		restarted.previousPrevote.Salt,
+		restarted.previousPrevote.ExchangeRates,
+		valAddr,
+	))
+
+	// the prevote of another validator is not restored
+	other := newOracle(sdk.ValAddress([]byte("other")).String())
+	require.Error(t, other.loadPrevoteFile())
+	require.Nil(t, other.previousPrevote)
+
+	// nor is a prevote which does not reveal its hash
+	prevote.ExchangeRates = "ATOM:11.000000000000000000,OJO:0.010000000000000000"
+	require.NoError(t, o.writePrevoteFile(20, hash, prevote))
+	tampered := newOracle(valAddr.String())
+	require.Error(t, tampered.loadPrevoteFile())
+	require.Nil(t, tampered.previousPrevote)
+
+	// the revealed prevote is removed
+	o.removePrevoteFile()
+	_, err = os.Stat(prevoteFile)
+	require.ErrorIs(t, err, os.ErrNotExist)
+	o.removePrevoteFile()
+
+	// the prevote is kept in memory only without a prevote file
+	o.SetPrevoteFile("")
+	require.NoError(t, o.writePrevoteFile(20, hash, prevote))
+	_, err = os.Stat(prevoteFile)
+	require.ErrorIs(t, err, os.ErrNotExist)
+}
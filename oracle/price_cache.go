@@ -0,0 +1,186 @@
+package oracle
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+
+	"github.com/ojo-network/price-feeder/oracle/types"
+)
+
+// ErrStalePriceCache defines a sentinel error for a price cache which was
+// written longer ago than its max age.
+var ErrStalePriceCache = errors.New("price cache is older than its max age")
+
+type (
+	// PriceCache persists the last aggregated prices and the recent candles of
+	// each provider to disk, so the oracle can vote right after a restart while
+	// live provider data catches up.
+	PriceCache struct {
+		path          string
+		maxAge        time.Duration
+		writeInterval time.Duration
+		lastWrite     time.Time
+	}
+
+	// priceCacheSnapshot defines the on-disk format of the price cache.
+	priceCacheSnapshot struct {
+		Timestamp time.Time       `json:"timestamp"`
+		Prices    []cachedPrice   `json:"prices"`
+		Candles   []cachedCandles `json:"candles"`
+	}
+
+	cachedPrice struct {
+		Pair  types.CurrencyPair `json:"pair"`
+		Price sdk.Dec            `json:"price"`
+	}
+
+	cachedCandles struct {
+		Provider types.ProviderName  `json:"provider"`
+		Pair     types.CurrencyPair  `json:"pair"`
+		Candles  []types.CandlePrice `json:"candles"`
+	}
+)
+
+// NewPriceCache returns a PriceCache stored at the given path. Caches older
+// than maxAge are not loaded and the cache is written at most once per
+// writeInterval.
+func NewPriceCache(path string, maxAge, writeInterval time.Duration) *PriceCache {
+	return &PriceCache{
+		path:          path,
+		maxAge:        maxAge,
+		writeInterval: writeInterval,
+	}
+}
+
+// Write stores the given prices and candles to disk. The file is replaced
+// atomically so a crash mid-write never leaves a truncated cache behind.
+func (pc *PriceCache) Write(
+	now time.Time,
+	prices types.CurrencyPairDec,
+	candles types.AggregatedProviderCandles,
+) error {
+	snapshot := priceCacheSnapshot{
+		Timestamp: now,
+		Prices:    make([]cachedPrice, 0, len(prices)),
+		Candles:   make([]cachedCandles, 0, len(candles)),
+	}
+	for cp, price := range prices {
+		snapshot.Prices = append(snapshot.Prices, cachedPrice{Pair: cp, Price: price})
+	}
+	for providerName, pairCandles := range candles {
+		for cp, cc := range pairCandles {
+			snapshot.Candles = append(snapshot.Candles, cachedCandles{
+				Provider: providerName,
+				Pair:     cp,
+				Candles:  cc,
+			})
+		}
+	}
+
+	bz, err := json.Marshal(snapshot)
+	if err != nil {
+		return fmt.Errorf("failed to encode price cache: %w", err)
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(pc.path), filepath.Base(pc.path)+".tmp")
+	if err != nil {
+		return fmt.Errorf("failed to create price cache: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(bz); err != nil {
+		tmp.Close()
+		return fmt.Errorf("failed to write price cache: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("failed to write price cache: %w", err)
+	}
+	if err := os.Rename(tmp.Name(), pc.path); err != nil {
+		return fmt.Errorf("failed to write price cache: %w", err)
+	}
+
+	pc.lastWrite = now
+	return nil
+}
+
+// Load reads the prices and candles stored on disk along with the time they
+// were written. It returns os.ErrNotExist if no cache was written yet and
+// ErrStalePriceCache if the cache is older than its max age.
+func (pc *PriceCache) Load(now time.Time) (
+	types.CurrencyPairDec,
+	types.AggregatedProviderCandles,
+	time.Time,
+	error,
+) {
+	bz, err := os.ReadFile(pc.path)
+	if err != nil {
+		return nil, nil, time.Time{}, err
+	}
+
+	var snapshot priceCacheSnapshot
+	if err := json.Unmarshal(bz, &snapshot); err != nil {
+		return nil, nil, time.Time{}, fmt.Errorf("failed to decode price cache: %w", err)
+	}
+	if now.Sub(snapshot.Timestamp) > pc.maxAge {
+		return nil, nil, snapshot.Timestamp, ErrStalePriceCache
+	}
+
+	prices := make(types.CurrencyPairDec, len(snapshot.Prices))
+	for _, p := range snapshot.Prices {
+		prices[p.Pair] = p.Price
+	}
+
+	candles := make(types.AggregatedProviderCandles)
+	for _, c := range snapshot.Candles {
+		if _, ok := candles[c.Provider]; !ok {
+			candles[c.Provider] = make(map[types.CurrencyPair][]types.CandlePrice)
+		}
+		candles[c.Provider][c.Pair] = c.Candles
+	}
+
+	return prices, candles, snapshot.Timestamp, nil
+}
+
+// isWriteDue returns true if the cache was not written within its write
+// interval.
+func (pc *PriceCache) isWriteDue(now time.Time) bool {
+	return now.Sub(pc.lastWrite) >= pc.writeInterval
+}
+
+// mergeWarmupCandles returns the live candles with the cached candles of every
+// provider and pair that has no live candles yet, and whether any cached
+// candles were used.
+func mergeWarmupCandles(
+	live types.AggregatedProviderCandles,
+	cached types.AggregatedProviderCandles,
+) (types.AggregatedProviderCandles, bool) {
+	merged := make(types.AggregatedProviderCandles, len(live))
+	for providerName, pairCandles := range live {
+		merged[providerName] = make(map[types.CurrencyPair][]types.CandlePrice, len(pairCandles))
+		for cp, candles := range pairCandles {
+			merged[providerName][cp] = candles
+		}
+	}
+
+	used := false
+	for providerName, pairCandles := range cached {
+		for cp, candles := range pairCandles {
+			if _, ok := merged[providerName][cp]; ok {
+				continue
+			}
+			if _, ok := merged[providerName]; !ok {
+				merged[providerName] = make(map[types.CurrencyPair][]types.CandlePrice)
+			}
+			merged[providerName][cp] = candles
+			used = true
+		}
+	}
+
+	return merged, used
+}
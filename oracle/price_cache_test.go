@@ -0,0 +1,125 @@
+package oracle
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ojo-network/price-feeder/oracle/client"
+	"github.com/ojo-network/price-feeder/oracle/provider"
+	"github.com/ojo-network/price-feeder/oracle/types"
+)
+
+func TestPriceCache_WriteLoad(t *testing.T) {
+	pc := NewPriceCache(filepath.Join(t.TempDir(), "prices.json"), time.Minute, time.Second)
+	now := time.Now()
+
+	prices := types.CurrencyPairDec{
+		OJOUSD:  sdk.MustNewDecFromStr("3.72"),
+		ATOMUSD: sdk.MustNewDecFromStr("11.52"),
+	}
+	candles := types.AggregatedProviderCandles{
+		provider.ProviderBinance: {
+			OJOUSDT: {
+				{
+					Price:     sdk.MustNewDecFromStr("3.71"),
+					Volume:    sdk.MustNewDecFromStr("2749102.78"),
+					TimeStamp: provider.PastUnixTime(time.Minute),
+				},
+			},
+		},
+	}
+
+	require.True(t, pc.isWriteDue(now))
+	require.NoError(t, pc.Write(now, prices, candles))
+	require.False(t, pc.isWriteDue(now))
+
+	loadedPrices, loadedCandles, timestamp, err := pc.Load(now)
+	require.NoError(t, err)
+	require.True(t, now.Equal(timestamp))
+	require.Equal(t, prices, loadedPrices)
+	require.Equal(t, candles, loadedCandles)
+}
+
+func TestPriceCache_MaxAge(t *testing.T) {
+	pc := NewPriceCache(filepath.Join(t.TempDir(), "prices.json"), time.Minute, time.Second)
+	now := time.Now()
+
+	_, _, _, err := pc.Load(now)
+	require.ErrorIs(t, err, os.ErrNotExist)
+
+	prices := types.CurrencyPairDec{OJOUSD: sdk.MustNewDecFromStr("3.72")}
+	require.NoError(t, pc.Write(now, prices, types.AggregatedProviderCandles{}))
+
+	_, _, _, err = pc.Load(now.Add(time.Minute))
+	require.NoError(t, err)
+
+	loadedPrices, _, _, err := pc.Load(now.Add(time.Minute + time.Second))
+	require.ErrorIs(t, err, ErrStalePriceCache)
+	require.Nil(t, loadedPrices)
+}
+
+func TestOracle_PriceCacheWarmup(t *testing.T) {
+	pc := NewPriceCache(filepath.Join(t.TempDir(), "prices.json"), time.Minute, 0)
+	written := time.Now().Add(-time.Second)
+	require.NoError(t, pc.Write(
+		written,
+		types.CurrencyPairDec{OJOUSD: sdk.MustNewDecFromStr("3.72")},
+		types.AggregatedProviderCandles{},
+	))
+
+	o := New(
+		zerolog.Nop(),
+		client.OracleClient{},
+		map[types.ProviderName][]types.CurrencyPair{
+			provider.ProviderBinance: {{Base: "OJO", Quote: "USD"}},
+		},
+		time.Millisecond*100,
+		make(map[string]sdk.Dec),
+		make(map[types.ProviderName]provider.Endpoint),
+	)
+	o.SetPriceCache(pc)
+	o.loadPriceCache()
+	require.True(t, o.IsWarmingUp())
+	require.Equal(t, sdk.MustNewDecFromStr("3.72"), o.GetPrices()[OJOUSD])
+
+	// without live data the cached price is voted while warming up
+	o.priceProviders = map[types.ProviderName]provider.Provider{
+		provider.ProviderBinance: failingProvider{},
+	}
+	require.NoError(t, o.SetPrices(context.TODO()))
+	require.True(t, o.IsWarmingUp())
+	require.Equal(t, sdk.MustNewDecFromStr("3.72"), o.GetPrices()[OJOUSD])
+
+	// nothing is written back to the cache while warming up
+	o.writePriceCache()
+	_, _, timestamp, err := pc.Load(time.Now())
+	require.NoError(t, err)
+	require.True(t, written.Equal(timestamp))
+
+	// live data ends the warm-up
+	o.priceProviders = map[types.ProviderName]provider.Provider{
+		provider.ProviderBinance: mockProvider{
+			prices: types.CurrencyPairTickers{
+				{Base: "OJO", Quote: "USD"}: {
+					Price:  sdk.MustNewDecFromStr("3.80"),
+					Volume: sdk.MustNewDecFromStr("2749102.78"),
+				},
+			},
+		},
+	}
+	require.NoError(t, o.SetPrices(context.TODO()))
+	require.False(t, o.IsWarmingUp())
+	require.Equal(t, sdk.MustNewDecFromStr("3.80"), o.GetPrices()[OJOUSD])
+
+	o.writePriceCache()
+	prices, _, _, err := pc.Load(time.Now())
+	require.NoError(t, err)
+	require.Equal(t, sdk.MustNewDecFromStr("3.80"), prices[OJOUSD])
+}
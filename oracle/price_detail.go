@@ -0,0 +1,334 @@
+package oracle
+
+import (
+	"sort"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/rs/zerolog"
+
+	"github.com/ojo-network/price-feeder/config"
+	"github.com/ojo-network/price-feeder/oracle/types"
+)
+
+// The sources of a provider price in its price detail.
+const (
+	priceSourceCandles = "candles"
+	priceSourceTicker  = "ticker"
+)
+
+// priceDetailRecorder records why the provider prices are filtered out while
+// the prices are computed, to break the aggregated prices down by provider.
+// A nil recorder records nothing.
+type priceDetailRecorder struct {
+	candles types.AggregatedProviderCandles
+	tickers types.AggregatedProviderPrices
+	reasons map[types.ProviderName]map[types.CurrencyPair]string
+
+	// convertedCandles and convertedTickers are the USD prices the rates are
+	// computed from, before their deviations are filtered.
+	convertedCandles types.AggregatedProviderCandles
+	convertedTickers types.AggregatedProviderPrices
+}
+
+func newPriceDetailRecorder(
+	candles types.AggregatedProviderCandles,
+	tickers types.AggregatedProviderPrices,
+) *priceDetailRecorder {
+	return &priceDetailRecorder{
+		candles: candles,
+		tickers: tickers,
+		reasons: make(map[types.ProviderName]map[types.CurrencyPair]string),
+	}
+}
+
+// GetPriceDetails returns the breakdown of the current prices by provider.
+func (o *Oracle) GetPriceDetails() []types.PriceDetail {
+	o.pricesMutex.RLock()
+	defer o.pricesMutex.RUnlock()
+
+	details := make([]types.PriceDetail, len(o.priceDetails))
+	copy(details, o.priceDetails)
+	return details
+}
+
+// filtered records the reason of the provider pairs left without candles and
+// tickers by a filter.
+func (r *priceDetailRecorder) filtered(
+	reason string,
+	candles types.AggregatedProviderCandles,
+	tickers types.AggregatedProviderPrices,
+) {
+	if r == nil {
+		return
+	}
+	r.forEachPair(func(providerName types.ProviderName, cp types.CurrencyPair) {
+		if _, ok := candles[providerName][cp]; ok {
+			return
+		}
+		if _, ok := tickers[providerName][cp]; ok {
+			return
+		}
+		r.setReason(providerName, cp, reason)
+	})
+}
+
+// converted records the reason of the provider pairs left without USD candles
+// and tickers by their conversion to USD or a filter of the converted prices.
+// The last converted prices are the ones the rates are computed from.
+func (r *priceDetailRecorder) converted(
+	reason string,
+	candles types.AggregatedProviderCandles,
+	tickers types.AggregatedProviderPrices,
+) {
+	if r == nil {
+		return
+	}
+	r.forEachPair(func(providerName types.ProviderName, cp types.CurrencyPair) {
+		usdPair := types.CurrencyPair{Base: cp.Base, Quote: config.DenomUSD}
+		if _, ok := candles[providerName][usdPair]; ok {
+			return
+		}
+		if _, ok := tickers[providerName][usdPair]; ok {
+			return
+		}
+		r.setReason(providerName, cp, reason)
+	})
+	r.convertedCandles = candles
+	r.convertedTickers = tickers
+}
+
+// forEachPair calls fn with every provider pair which has candles or tickers
+// and was not filtered out yet.
+func (r *priceDetailRecorder) forEachPair(fn func(types.ProviderName, types.CurrencyPair)) {
+	for providerName, pairs := range r.allPairs() {
+		for _, cp := range pairs {
+			if _, ok := r.reasons[providerName][cp]; !ok {
+				fn(providerName, cp)
+			}
+		}
+	}
+}
+
+func (r *priceDetailRecorder) setReason(providerName types.ProviderName, cp types.CurrencyPair, reason string) {
+	if _, ok := r.reasons[providerName]; !ok {
+		r.reasons[providerName] = make(map[types.CurrencyPair]string)
+	}
+	r.reasons[providerName][cp] = reason
+}
+
+// details returns the breakdown of the aggregated prices of the given USD
+// pairs, sorted by base. The provider prices outside of the deviation
+// threshold of their asset are marked as filtered, and the ticker prices of
+// the assets priced from candles as unused. The weight of each included price
+// is its share of the volume of the included prices.
+func (r *priceDetailRecorder) details(
+	currencyPairs []types.CurrencyPair,
+	prices types.CurrencyPairDec,
+	deviationThresholds map[string]sdk.Dec,
+) []types.PriceDetail {
+	if r == nil {
+		return nil
+	}
+
+	deviationCandles, err := FilterCandleDeviations(zerolog.Nop(), r.convertedCandles, deviationThresholds)
+	if err != nil {
+		deviationCandles = r.convertedCandles
+	}
+	deviationTickers, err := FilterTickerDeviations(zerolog.Nop(), r.convertedTickers, deviationThresholds)
+	if err != nil {
+		deviationTickers = r.convertedTickers
+	}
+
+	entries := make(map[string][]types.ProviderPriceDetail)
+	volumes := make(map[string][]sdk.Dec)
+	for providerName, pairs := range r.allPairs() {
+		for _, cp := range pairs {
+			entry, volume := r.providerPriceDetail(providerName, cp, deviationCandles, deviationTickers)
+			entries[cp.Base] = append(entries[cp.Base], entry)
+			volumes[cp.Base] = append(volumes[cp.Base], volume)
+		}
+	}
+
+	details := make([]types.PriceDetail, 0, len(currencyPairs))
+	for _, usdPair := range currencyPairs {
+		detail := types.PriceDetail{
+			Base:      usdPair.Base,
+			Quote:     usdPair.Quote,
+			Providers: entries[usdPair.Base],
+		}
+		if detail.Providers == nil {
+			detail.Providers = []types.ProviderPriceDetail{}
+		}
+		weighProviderPrices(detail.Providers, volumes[usdPair.Base])
+
+		if price, ok := prices[usdPair]; ok {
+			detail.Price = &price
+			detail.Spread = priceSpread(detail.Providers, price)
+		}
+		sort.Slice(detail.Providers, func(i, j int) bool {
+			if detail.Providers[i].Provider != detail.Providers[j].Provider {
+				return detail.Providers[i].Provider < detail.Providers[j].Provider
+			}
+			return detail.Providers[i].Quote < detail.Providers[j].Quote
+		})
+		details = append(details, detail)
+	}
+
+	sort.Slice(details, func(i, j int) bool {
+		return details[i].Base < details[j].Base
+	})
+	return details
+}
+
+// allPairs returns the pairs of every provider with candles or tickers.
+func (r *priceDetailRecorder) allPairs() map[types.ProviderName][]types.CurrencyPair {
+	pairs := make(map[types.ProviderName][]types.CurrencyPair)
+	seen := make(map[types.ProviderName]map[types.CurrencyPair]struct{})
+	add := func(providerName types.ProviderName, cp types.CurrencyPair) {
+		if _, ok := seen[providerName][cp]; ok {
+			return
+		}
+		if _, ok := seen[providerName]; !ok {
+			seen[providerName] = make(map[types.CurrencyPair]struct{})
+		}
+		seen[providerName][cp] = struct{}{}
+		pairs[providerName] = append(pairs[providerName], cp)
+	}
+
+	for providerName, pairCandles := range r.candles {
+		for cp := range pairCandles {
+			add(providerName, cp)
+		}
+	}
+	for providerName, pairTickers := range r.tickers {
+		for cp := range pairTickers {
+			add(providerName, cp)
+		}
+	}
+	return pairs
+}
+
+// providerPriceDetail returns the price detail of the provider pair and its
+// volume, recording the deviation filter if its USD price was filtered out.
+func (r *priceDetailRecorder) providerPriceDetail(
+	providerName types.ProviderName,
+	cp types.CurrencyPair,
+	deviationCandles types.AggregatedProviderCandles,
+	deviationTickers types.AggregatedProviderPrices,
+) (types.ProviderPriceDetail, sdk.Dec) {
+	candles := r.candles[providerName][cp]
+	ticker, hasTicker := r.tickers[providerName][cp]
+	entry := types.ProviderPriceDetail{
+		Provider: providerName,
+		Quote:    cp.Quote,
+		Source:   priceSourceTicker,
+		Price:    ticker.Price,
+		Weight:   sdk.ZeroDec(),
+		Reason:   r.reasons[providerName][cp],
+	}
+	if len(candles) > 0 {
+		entry.Source = priceSourceCandles
+		entry.Price = candlesPrice(cp, candles)
+	} else if !hasTicker {
+		entry.Price = sdk.ZeroDec()
+	}
+
+	usdPair := types.CurrencyPair{Base: cp.Base, Quote: config.DenomUSD}
+	volume := sdk.ZeroDec()
+	if entry.Reason == "" {
+		if usdCandles := r.convertedCandles[providerName][usdPair]; len(usdCandles) > 0 {
+			entry.Source = priceSourceCandles
+			usdPrice := candlesPrice(usdPair, usdCandles)
+			entry.USDPrice = &usdPrice
+			for _, candle := range usdCandles {
+				volume = volume.Add(candle.Volume)
+			}
+			if _, ok := deviationCandles[providerName][usdPair]; !ok {
+				entry.Reason = types.FilterReasonDeviation
+			}
+		} else if usdTicker, ok := r.convertedTickers[providerName][usdPair]; ok {
+			entry.Source = priceSourceTicker
+			usdPrice := usdTicker.Price
+			entry.USDPrice = &usdPrice
+			volume = usdTicker.Volume
+			if _, ok := deviationTickers[providerName][usdPair]; !ok {
+				entry.Reason = types.FilterReasonDeviation
+			}
+		}
+	}
+
+	entry.Included = entry.Reason == ""
+	return entry, volume
+}
+
+// candlesPrice returns the TVWAP of the candles of the pair, or the price of
+// the newest candle if none is recent enough for a TVWAP.
+func candlesPrice(cp types.CurrencyPair, candles []types.CandlePrice) sdk.Dec {
+	tvwap, err := ComputeTVWAP(types.AggregatedProviderCandles{"": {cp: candles}})
+	if price, ok := tvwap[cp]; err == nil && ok {
+		return price
+	}
+
+	newest := candles[0]
+	for _, candle := range candles[1:] {
+		if candle.TimeStamp > newest.TimeStamp {
+			newest = candle
+		}
+	}
+	return newest.Price
+}
+
+// weighProviderPrices sets the weight of the included provider prices to
+// their share of the volume of the included prices. The ticker prices of an
+// asset priced from candles are unused and excluded.
+func weighProviderPrices(entries []types.ProviderPriceDetail, volumes []sdk.Dec) {
+	hasCandles := false
+	for _, entry := range entries {
+		if entry.Included && entry.Source == priceSourceCandles {
+			hasCandles = true
+		}
+	}
+
+	total := sdk.ZeroDec()
+	for i := range entries {
+		if entries[i].Included && hasCandles && entries[i].Source == priceSourceTicker {
+			entries[i].Included = false
+			entries[i].Reason = types.FilterReasonTickerUnused
+		}
+		if entries[i].Included {
+			total = total.Add(volumes[i])
+		}
+	}
+	if !total.IsPositive() {
+		return
+	}
+
+	for i := range entries {
+		if entries[i].Included {
+			entries[i].Weight = volumes[i].Quo(total)
+		}
+	}
+}
+
+// priceSpread returns the range of the included USD provider prices relative
+// to the aggregated price, or nil if no provider price is included.
+func priceSpread(entries []types.ProviderPriceDetail, price sdk.Dec) *sdk.Dec {
+	var lowest, highest sdk.Dec
+	for _, entry := range entries {
+		if !entry.Included || entry.USDPrice == nil {
+			continue
+		}
+		if lowest.IsNil() || entry.USDPrice.LT(lowest) {
+			lowest = *entry.USDPrice
+		}
+		if highest.IsNil() || entry.USDPrice.GT(highest) {
+			highest = *entry.USDPrice
+		}
+	}
+	if lowest.IsNil() || !price.IsPositive() {
+		return nil
+	}
+
+	spread := highest.Sub(lowest).Quo(price)
+	return &spread
+}
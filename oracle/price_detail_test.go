@@ -0,0 +1,128 @@
+package oracle
+
+import (
+	"testing"
+	"time"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ojo-network/price-feeder/oracle/provider"
+	"github.com/ojo-network/price-feeder/oracle/types"
+)
+
+func TestPriceDetailRecorder_details(t *testing.T) {
+	fooUSD := types.CurrencyPair{Base: "FOO", Quote: "USD"}
+	fooUSDT := types.CurrencyPair{Base: "FOO", Quote: "USDT"}
+	fooBTC := types.CurrencyPair{Base: "FOO", Quote: "BTC"}
+	usdtUSD := types.CurrencyPair{Base: "USDT", Quote: "USD"}
+	candles := func(price string) []types.CandlePrice {
+		return []types.CandlePrice{{
+			Price:     sdk.MustNewDecFromStr(price),
+			Volume:    sdk.OneDec(),
+			TimeStamp: provider.PastUnixTime(time.Minute),
+		}}
+	}
+
+	o := &Oracle{
+		logger:           zerolog.Nop(),
+		zeroVolumeWeight: sdk.ZeroDec(),
+		providerPairs: map[types.ProviderName][]types.CurrencyPair{
+			provider.ProviderBinance:  {fooUSDT},
+			provider.ProviderKraken:   {fooUSD, usdtUSD},
+			provider.ProviderHuobi:    {fooUSD},
+			provider.ProviderOkx:      {fooUSD},
+			provider.ProviderGate:     {fooBTC},
+			provider.ProviderCoinbase: {fooUSD},
+		},
+	}
+
+	providerCandles := types.AggregatedProviderCandles{
+		provider.ProviderBinance: {fooUSDT: candles("1.00")},
+		provider.ProviderKraken:  {fooUSD: candles("1.02"), usdtUSD: candles("1.00")},
+		provider.ProviderHuobi:   {fooUSD: candles("1.01")},
+		provider.ProviderOkx:     {fooUSD: candles("5.00")},
+		provider.ProviderGate:    {fooBTC: candles("0.00004")},
+	}
+	providerPrices := types.AggregatedProviderPrices{
+		provider.ProviderCoinbase: {
+			fooUSD: {Price: sdk.MustNewDecFromStr("1.00"), Volume: sdk.OneDec()},
+		},
+	}
+
+	recorder := newPriceDetailRecorder(providerCandles, providerPrices)
+	prices, err := o.computePrices(providerCandles, providerPrices, recorder)
+	require.NoError(t, err)
+	details := recorder.details(o.RequiredRates(), prices, o.deviations)
+	require.Len(t, details, 2)
+
+	detail := details[0]
+	require.Equal(t, "FOO", detail.Base)
+	require.Equal(t, "USD", detail.Quote)
+	require.NotNil(t, detail.Price)
+	require.Equal(t, prices[fooUSD], *detail.Price)
+	require.NotNil(t, detail.Spread)
+	require.InDelta(t, 0.02/1.01, detail.Spread.MustFloat64(), 1e-3)
+
+	byProvider := make(map[types.ProviderName]types.ProviderPriceDetail)
+	for _, entry := range detail.Providers {
+		byProvider[entry.Provider] = entry
+	}
+	require.Len(t, byProvider, 6)
+
+	for _, providerName := range []types.ProviderName{
+		provider.ProviderBinance,
+		provider.ProviderKraken,
+		provider.ProviderHuobi,
+	} {
+		entry := byProvider[providerName]
+		require.True(t, entry.Included, providerName)
+		require.Empty(t, entry.Reason, providerName)
+		require.Equal(t, priceSourceCandles, entry.Source, providerName)
+		require.NotNil(t, entry.USDPrice, providerName)
+		require.InDelta(t, 1.0/3, entry.Weight.MustFloat64(), 1e-9, providerName)
+	}
+	require.Equal(t, "USDT", byProvider[provider.ProviderBinance].Quote)
+
+	require.False(t, byProvider[provider.ProviderOkx].Included)
+	require.Equal(t, types.FilterReasonDeviation, byProvider[provider.ProviderOkx].Reason)
+	require.True(t, byProvider[provider.ProviderOkx].Weight.IsZero())
+
+	require.False(t, byProvider[provider.ProviderGate].Included)
+	require.Equal(t, types.FilterReasonNoConversionRate, byProvider[provider.ProviderGate].Reason)
+	require.Nil(t, byProvider[provider.ProviderGate].USDPrice)
+
+	require.False(t, byProvider[provider.ProviderCoinbase].Included)
+	require.Equal(t, priceSourceTicker, byProvider[provider.ProviderCoinbase].Source)
+	require.Equal(t, types.FilterReasonTickerUnused, byProvider[provider.ProviderCoinbase].Reason)
+
+	require.Equal(t, "USDT", details[1].Base)
+	require.Len(t, details[1].Providers, 1)
+	require.True(t, details[1].Providers[0].Included)
+}
+
+func TestPriceDetailRecorder_filtered(t *testing.T) {
+	fooUSD := types.CurrencyPair{Base: "FOO", Quote: "USD"}
+	candles := types.AggregatedProviderCandles{
+		provider.ProviderBinance: {fooUSD: {{Price: sdk.OneDec(), Volume: sdk.OneDec()}}},
+		provider.ProviderKraken:  {fooUSD: {{Price: sdk.OneDec(), Volume: sdk.OneDec()}}},
+	}
+
+	recorder := newPriceDetailRecorder(candles, types.AggregatedProviderPrices{})
+	recorder.filtered(
+		types.FilterReasonProviderRole,
+		types.AggregatedProviderCandles{provider.ProviderBinance: candles[provider.ProviderBinance]},
+		types.AggregatedProviderPrices{},
+	)
+	// a reason is kept once recorded
+	recorder.filtered(types.FilterReasonMaxProviders, nil, nil)
+
+	require.Equal(t, types.FilterReasonProviderRole, recorder.reasons[provider.ProviderKraken][fooUSD])
+	require.Equal(t, types.FilterReasonMaxProviders, recorder.reasons[provider.ProviderBinance][fooUSD])
+
+	// a nil recorder records nothing
+	var nilRecorder *priceDetailRecorder
+	nilRecorder.filtered(types.FilterReasonPriceBand, nil, nil)
+	require.Nil(t, nilRecorder.details([]types.CurrencyPair{fooUSD}, nil, nil))
+}
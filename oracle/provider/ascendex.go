@@ -0,0 +1,480 @@
+package provider
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/gorilla/websocket"
+	"github.com/rs/zerolog"
+
+	"github.com/ojo-network/price-feeder/oracle/types"
+)
+
+const (
+	ascendexWSHost   = "ascendex.com"
+	ascendexWSPath   = "/api/pro/v1/stream"
+	ascendexRestHost = "https://ascendex.com"
+	ascendexRestPath = "/api/pro/v1/cash/products"
+
+	ascendexBboChannel    = "bbo"
+	ascendexBarChannel    = "bar"
+	ascendexBarInterval   = "1"
+	ascendexPingMessage   = "ping"
+	ascendexSubMessage    = "sub"
+	ascendexErrorMessage  = "error"
+	ascendexNormalProduct = "Normal"
+
+	// ascendexVolumePeriod is the period the bar volumes of the ticker are
+	// summed over, as the best quotes carry no 24h volume.
+	ascendexVolumePeriod = 24 * time.Hour
+)
+
+var _ Provider = (*AscendexProvider)(nil)
+
+type (
+	// AscendexProvider defines an Oracle provider implemented by the AscendEX
+	// (formerly BitMax) public API. Its websocket carries the best quotes and
+	// the candles of a pair but no tickers, so the ticker of a pair is the mid
+	// of its best bid and ask with the volume of its candles since the
+	// provider started, up to 24h.
+	//
+	// REF: https://ascendex.github.io/ascendex-pro-api/#websocket-2
+	AscendexProvider struct {
+		wsc       *WebsocketController
+		logger    zerolog.Logger
+		mtx       sync.RWMutex
+		endpoints Endpoint
+
+		// marketsMtx guards markets, the best quotes and bar volumes of each
+		// symbol.
+		marketsMtx sync.Mutex
+		markets    map[string]*ascendexMarket
+
+		priceStore
+	}
+
+	// ascendexMarket defines the state of an AscendEX symbol built from its
+	// best quotes and bars.
+	ascendexMarket struct {
+		mid     sdk.Dec           // mid of the best bid and ask
+		volumes map[int64]sdk.Dec // volume per bar start time
+	}
+
+	// AscendexMessage defines the fields common to the AscendEX websocket
+	// messages, which are told apart by their type.
+	AscendexMessage struct {
+		Type    string `json:"m"`      // ex.: bbo, bar, ping, sub
+		Symbol  string `json:"symbol"` // Symbol of a bbo message ex.: ATOM/USDT
+		BarSym  string `json:"s"`      // Symbol of a bar message ex.: ATOM/USDT
+		ID      string `json:"id"`     // id of a subscription response
+		Channel string `json:"ch"`     // channel of a subscription response
+		Code    int    `json:"code"`   // 0 on success
+		Reason  string `json:"reason"` // reason of an error
+		Info    string `json:"info"`   // details of an error
+	}
+
+	// AscendexBboResponse defines the best bid and ask of a symbol, each sent
+	// as a price and size ex.: ["11.52", "182.47"].
+	AscendexBboResponse struct {
+		Symbol string `json:"symbol"` // Symbol ex.: ATOM/USDT
+		Data   struct {
+			TimeStamp int64          `json:"ts"`  // Time in unix epoch ms
+			Bid       []types.Number `json:"bid"` // Best bid price and size
+			Ask       []types.Number `json:"ask"` // Best ask price and size
+		} `json:"data"`
+	}
+
+	// AscendexBarResponse defines a candle of a symbol.
+	AscendexBarResponse struct {
+		Symbol string      `json:"s"` // Symbol ex.: ATOM/USDT
+		Data   AscendexBar `json:"data"`
+	}
+	AscendexBar struct {
+		Interval  string       `json:"i"`  // Interval in minutes ex.: 1
+		TimeStamp int64        `json:"ts"` // Start time in unix epoch ms ex.: 1575398940000
+		Close     types.Number `json:"c"`  // Price at close
+		Volume    types.Number `json:"v"`  // Volume during period
+	}
+
+	// AscendexTicker defines the ticker of a symbol built from its best quotes
+	// and bar volumes.
+	AscendexTicker struct {
+		Price  sdk.Dec
+		Volume sdk.Dec
+	}
+
+	// AscendexSubscriptionMsg Msg to subscribe to a channel of a symbol.
+	AscendexSubscriptionMsg struct {
+		Op      string `json:"op"` // sub
+		ID      string `json:"id"` // identify the subscription response
+		Channel string `json:"ch"` // ex.: bbo:ATOM/USDT
+	}
+
+	// AscendexOperation defines a heartbeat message sent to AscendEX.
+	AscendexOperation struct {
+		Op string `json:"op"` // ping or pong
+	}
+
+	// AscendexProductsResponse defines the response structure for the
+	// AscendEX available pairs.
+	AscendexProductsResponse struct {
+		Data []struct {
+			Symbol string `json:"symbol"` // ex.: ATOM/USDT
+			Status string `json:"status"` // ex.: Normal
+		} `json:"data"`
+	}
+)
+
+func NewAscendexProvider(
+	ctx context.Context,
+	logger zerolog.Logger,
+	endpoints Endpoint,
+	pairs ...types.CurrencyPair,
+) (*AscendexProvider, error) {
+	if endpoints.Name != ProviderAscendex {
+		endpoints = Endpoint{
+			Name:              ProviderAscendex,
+			Rest:              ascendexRestHost,
+			Websocket:         ascendexWSHost,
+			SilenceTimeout:    endpoints.SilenceTimeout,
+			ReconnectCooldown: endpoints.ReconnectCooldown,
+		}
+	}
+
+	wsURL := url.URL{
+		Scheme: "wss",
+		Host:   endpoints.Websocket,
+		Path:   ascendexWSPath,
+	}
+
+	ascendexLogger := logger.With().Str("provider", string(ProviderAscendex)).Logger()
+
+	provider := &AscendexProvider{
+		logger:     ascendexLogger,
+		endpoints:  endpoints,
+		markets:    make(map[string]*ascendexMarket),
+		priceStore: newPriceStore(ascendexLogger),
+	}
+	provider.setCurrencyPairToTickerAndCandlePair(currencyPairToAscendexPair)
+
+	confirmedPairs, err := ConfirmPairAvailability(
+		provider,
+		provider.endpoints.Name,
+		provider.logger,
+		pairs...,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	provider.setSubscribedPairs(confirmedPairs...)
+
+	provider.wsc = NewWebsocketController(
+		ctx,
+		endpoints.Name,
+		wsURL,
+		provider.getSubscriptionMsgs(confirmedPairs...),
+		provider.messageReceived,
+		defaultPingDuration,
+		websocket.TextMessage,
+		endpoints.SilenceTimeout,
+		endpoints.ReconnectCooldown,
+		&provider.priceStore,
+		endpoints.tlsConfig,
+		endpoints.header,
+		ascendexLogger,
+	)
+
+	return provider, nil
+}
+
+func (p *AscendexProvider) StartConnections() {
+	p.wsc.StartConnections()
+}
+
+// getSubscriptionMsgs returns the subscription messages to the bbo and bar
+// channels of each pair.
+func (p *AscendexProvider) getSubscriptionMsgs(cps ...types.CurrencyPair) []interface{} {
+	subscriptionMsgs := make([]interface{}, 0, len(cps)*2)
+	for _, cp := range cps {
+		symbol := currencyPairToAscendexPair(cp)
+		subscriptionMsgs = append(subscriptionMsgs, newAscendexSubscriptionMsg(ascendexBboChannel+":"+symbol))
+		subscriptionMsgs = append(subscriptionMsgs, newAscendexSubscriptionMsg(
+			ascendexBarChannel+":"+ascendexBarInterval+":"+symbol,
+		))
+	}
+	return subscriptionMsgs
+}
+
+// SubscribeCurrencyPairs sends the new subscription messages to the websocket
+// and adds them to the providers subscribedPairs array
+func (p *AscendexProvider) SubscribeCurrencyPairs(cps ...types.CurrencyPair) {
+	p.mtx.Lock()
+	defer p.mtx.Unlock()
+
+	newPairs := []types.CurrencyPair{}
+	for _, cp := range cps {
+		if _, ok := p.subscribedPairs[cp.String()]; !ok {
+			newPairs = append(newPairs, cp)
+		}
+	}
+
+	confirmedPairs, err := ConfirmPairAvailability(
+		p,
+		p.endpoints.Name,
+		p.logger,
+		newPairs...,
+	)
+	if err != nil {
+		return
+	}
+
+	newSubscriptionMsgs := p.getSubscriptionMsgs(confirmedPairs...)
+	p.wsc.AddWebsocketConnection(
+		newSubscriptionMsgs,
+		p.messageReceived,
+		defaultPingDuration,
+		websocket.TextMessage,
+	)
+	p.setSubscribedPairs(confirmedPairs...)
+}
+
+func (p *AscendexProvider) messageReceived(messageType int, conn *WebsocketConnection, bz []byte) {
+	if messageType != websocket.TextMessage {
+		return
+	}
+
+	var msg AscendexMessage
+	if err := json.Unmarshal(bz, &msg); err != nil {
+		p.logger.Error().
+			Int("length", len(bz)).
+			AnErr("err", err).
+			Msg("Error on receive message")
+		telemetryDecodeError(p.logger, ProviderAscendex, bz)
+		return
+	}
+
+	switch msg.Type {
+	case ascendexBboChannel:
+		var bboResp AscendexBboResponse
+		if err := json.Unmarshal(bz, &bboResp); err != nil {
+			p.logger.Error().Err(err).Str("symbol", msg.Symbol).Msg("failed to unmarshal bbo")
+			return
+		}
+		p.bboReceived(bboResp)
+		telemetryWebsocketMessage(ProviderAscendex, MessageTypeTicker)
+
+	case ascendexBarChannel:
+		var barResp AscendexBarResponse
+		if err := json.Unmarshal(bz, &barResp); err != nil {
+			p.logger.Error().Err(err).Str("symbol", msg.BarSym).Msg("failed to unmarshal bar")
+			return
+		}
+		p.barReceived(barResp)
+		telemetryWebsocketMessage(ProviderAscendex, MessageTypeCandle)
+
+	case ascendexPingMessage:
+		p.pongReceived(conn)
+
+	case ascendexSubMessage:
+		if msg.Code != 0 {
+			p.logger.Error().
+				Str("id", msg.ID).
+				Str("channel", msg.Channel).
+				Int("code", msg.Code).
+				Msg("failed to subscribe")
+			return
+		}
+		p.logger.Debug().Str("id", msg.ID).Str("channel", msg.Channel).Msg("subscribed")
+
+	case ascendexErrorMessage:
+		p.logger.Error().
+			Int("code", msg.Code).
+			Str("reason", msg.Reason).
+			Str("info", msg.Info).
+			Msg("received error message")
+	}
+}
+
+// bboReceived sets the mid of the best quotes of the symbol and updates its
+// ticker.
+func (p *AscendexProvider) bboReceived(bboResp AscendexBboResponse) {
+	mid, err := bboResp.mid()
+	if err != nil {
+		p.logger.Error().Err(err).Str("symbol", bboResp.Symbol).Msg("failed to get mid of best quotes")
+		return
+	}
+
+	p.marketsMtx.Lock()
+	defer p.marketsMtx.Unlock()
+
+	market := p.market(bboResp.Symbol)
+	market.mid = mid
+	p.setTicker(bboResp.Symbol, market)
+}
+
+// barReceived sets the candle of the symbol and adds its volume to the volume
+// of the ticker.
+func (p *AscendexProvider) barReceived(barResp AscendexBarResponse) {
+	p.setCandlePair(barResp.Data, barResp.Symbol)
+
+	volume, err := barResp.Data.Volume.Dec()
+	if err != nil {
+		p.logger.Error().Err(err).Str("symbol", barResp.Symbol).Msg("failed to parse bar volume")
+		return
+	}
+
+	p.marketsMtx.Lock()
+	defer p.marketsMtx.Unlock()
+
+	market := p.market(barResp.Symbol)
+	market.setVolume(barResp.Data.TimeStamp, volume)
+	p.setTicker(barResp.Symbol, market)
+}
+
+// pongReceived answers the heartbeat of AscendEX, which closes the connections
+// not answering its pings.
+func (p *AscendexProvider) pongReceived(conn *WebsocketConnection) {
+	if conn == nil {
+		return
+	}
+	if err := conn.SendJSON(AscendexOperation{Op: "pong"}); err != nil {
+		p.logger.Err(err).Msg("could not send pong message back")
+	}
+}
+
+// market returns the market of the symbol, creating it if needed. It must be
+// called with marketsMtx held.
+func (p *AscendexProvider) market(symbol string) *ascendexMarket {
+	market, ok := p.markets[symbol]
+	if !ok {
+		market = &ascendexMarket{volumes: make(map[int64]sdk.Dec)}
+		p.markets[symbol] = market
+	}
+	return market
+}
+
+// setTicker sets the ticker of the symbol once its best quotes are known. It
+// must be called with marketsMtx held.
+func (p *AscendexProvider) setTicker(symbol string, market *ascendexMarket) {
+	if market.mid.IsNil() {
+		return
+	}
+	p.setTickerPair(AscendexTicker{
+		Price:  market.mid,
+		Volume: market.volume(),
+	}, symbol)
+}
+
+// setVolume sets the volume of the bar starting at the timestamp, as bars are
+// updated until they close, and drops the volumes older than
+// ascendexVolumePeriod.
+func (m *ascendexMarket) setVolume(timestamp int64, volume sdk.Dec) {
+	m.volumes[timestamp] = volume
+
+	staleTime := PastUnixTime(ascendexVolumePeriod)
+	for ts := range m.volumes {
+		if ts <= staleTime {
+			delete(m.volumes, ts)
+		}
+	}
+}
+
+// volume returns the volume of the bars of the market.
+func (m *ascendexMarket) volume() sdk.Dec {
+	volume := sdk.ZeroDec()
+	for _, v := range m.volumes {
+		volume = volume.Add(v)
+	}
+	return volume
+}
+
+// mid returns the mid of the best bid and ask.
+func (bboResp AscendexBboResponse) mid() (sdk.Dec, error) {
+	if len(bboResp.Data.Bid) == 0 || len(bboResp.Data.Ask) == 0 {
+		return sdk.Dec{}, fmt.Errorf("missing best bid or ask")
+	}
+	bid, err := bboResp.Data.Bid[0].Dec()
+	if err != nil {
+		return sdk.Dec{}, err
+	}
+	ask, err := bboResp.Data.Ask[0].Dec()
+	if err != nil {
+		return sdk.Dec{}, err
+	}
+	if !bid.IsPositive() || !ask.IsPositive() {
+		return sdk.Dec{}, fmt.Errorf("best bid or ask is not positive")
+	}
+	return bid.Add(ask).QuoInt64(2), nil
+}
+
+func (ticker AscendexTicker) toTickerPrice() (types.TickerPrice, error) {
+	return types.TickerPrice{
+		Price:  ticker.Price,
+		Volume: ticker.Volume,
+	}, nil
+}
+
+// toCandlePrice converts the bar to a candle closing at the end of its
+// interval.
+func (bar AscendexBar) toCandlePrice() (types.CandlePrice, error) {
+	return types.NewCandlePrice(
+		bar.Close.String(),
+		bar.Volume.String(),
+		time.UnixMilli(bar.TimeStamp).Add(time.Minute).UnixMilli(),
+	)
+}
+
+// GetAvailablePairs returns all pairs to which the provider can subscribe.
+// ex.: map["ATOMUSDT" => {}, "OJOUSDC" => {}].
+func (p *AscendexProvider) GetAvailablePairs() (map[string]struct{}, error) {
+	resp, err := p.endpoints.httpClient().Get(p.endpoints.Rest + ascendexRestPath)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	var productsResp AscendexProductsResponse
+	if err := json.NewDecoder(resp.Body).Decode(&productsResp); err != nil {
+		return nil, err
+	}
+
+	availablePairs := make(map[string]struct{}, len(productsResp.Data))
+	for _, product := range productsResp.Data {
+		if product.Status != ascendexNormalProduct {
+			continue
+		}
+		availablePairs[strings.ToUpper(strings.ReplaceAll(product.Symbol, "/", ""))] = struct{}{}
+	}
+
+	return availablePairs, nil
+}
+
+// currencyPairToAscendexPair receives a currency pair and returns the AscendEX
+// symbol ex.: ATOM/USDT.
+func currencyPairToAscendexPair(cp types.CurrencyPair) string {
+	return strings.ToUpper(cp.Base + "/" + cp.Quote)
+}
+
+// newAscendexSubscriptionMsg returns a new subscription Msg to the channel,
+// identified by the channel itself.
+func newAscendexSubscriptionMsg(channel string) AscendexSubscriptionMsg {
+	return AscendexSubscriptionMsg{
+		Op:      ascendexSubMessage,
+		ID:      channel,
+		Channel: channel,
+	}
+}
+
+// newAscendexPingMsg returns a new ping message, which AscendEX answers with a
+// pong message.
+func newAscendexPingMsg() []byte {
+	bz, _ := json.Marshal(AscendexOperation{Op: ascendexPingMessage})
+	return bz
+}
@@ -0,0 +1,107 @@
+package provider
+
+import (
+	"encoding/json"
+	"strconv"
+	"testing"
+	"time"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/gorilla/websocket"
+	"github.com/ojo-network/price-feeder/oracle/types"
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAscendexProvider_messageReceived(t *testing.T) {
+	p := &AscendexProvider{
+		logger:     zerolog.Nop(),
+		markets:    make(map[string]*ascendexMarket),
+		priceStore: newPriceStore(zerolog.Nop()),
+	}
+	p.setCurrencyPairToTickerAndCandlePair(currencyPairToAscendexPair)
+	p.setSubscribedPairs(ATOMUSDT)
+
+	// connection, subscription and heartbeat messages carry no data
+	p.messageReceived(websocket.TextMessage, nil, []byte(`{"m":"connected","type":"unauth"}`))
+	p.messageReceived(websocket.TextMessage, nil, []byte(`{"m":"sub","id":"bbo:ATOM/USDT","ch":"bbo:ATOM/USDT","code":0}`))
+	p.messageReceived(websocket.TextMessage, nil, []byte(`{"m":"ping","hp":3}`))
+	prices, err := p.GetTickerPrices(ATOMUSDT)
+	require.NoError(t, err)
+	require.Empty(t, prices)
+
+	// the ticker is the mid of the best quotes, without volume until a bar
+	// is received
+	bbo := `{"m":"bbo","symbol":"ATOM/USDT","data":{"ts":1687944835188,` +
+		`"bid":["11.51","182.47"],"ask":["11.53","96.2"]}}`
+	p.messageReceived(websocket.TextMessage, nil, []byte(bbo))
+
+	prices, err = p.GetTickerPrices(ATOMUSDT)
+	require.NoError(t, err)
+	require.Len(t, prices, 1)
+	require.Equal(t, sdk.MustNewDecFromStr("11.52"), prices[ATOMUSDT].Price)
+	require.Equal(t, sdk.ZeroDec(), prices[ATOMUSDT].Volume)
+
+	barStart := time.UnixMilli(PastUnixTime(0)).Truncate(time.Minute)
+	bar := func(start time.Time, close, volume string) []byte {
+		return []byte(`{"m":"bar","s":"ATOM/USDT","data":{"i":"1","ts":` +
+			strconv.FormatInt(start.UnixMilli(), 10) + `,"o":"11.52","c":"` + close +
+			`","h":"11.54","l":"11.51","v":"` + volume + `"}}`)
+	}
+	p.messageReceived(websocket.TextMessage, nil, bar(barStart.Add(-time.Minute), "11.50", "100"))
+	p.messageReceived(websocket.TextMessage, nil, bar(barStart, "11.52", "20"))
+
+	// bars are updated until they close
+	p.messageReceived(websocket.TextMessage, nil, bar(barStart, "11.53", "82.47"))
+
+	candles, err := p.GetCandlePrices(ATOMUSDT)
+	require.NoError(t, err)
+	require.Len(t, candles[ATOMUSDT], 2)
+	require.Equal(t, sdk.MustNewDecFromStr("11.53"), candles[ATOMUSDT][0].Price)
+	require.Equal(t, sdk.MustNewDecFromStr("82.47"), candles[ATOMUSDT][0].Volume)
+	require.Equal(t, barStart.Add(time.Minute).UnixMilli(), candles[ATOMUSDT][0].TimeStamp)
+
+	// the ticker volume sums the latest volume of every bar
+	prices, err = p.GetTickerPrices(ATOMUSDT)
+	require.NoError(t, err)
+	require.Equal(t, sdk.MustNewDecFromStr("11.52"), prices[ATOMUSDT].Price)
+	require.Equal(t, sdk.MustNewDecFromStr("182.47"), prices[ATOMUSDT].Volume)
+
+	// numeric fields may also be sent as numbers
+	bbo = `{"m":"bbo","symbol":"ATOM/USDT","data":{"ts":1687944835189,"bid":[11.6,1],"ask":[11.62,1]}}`
+	p.messageReceived(websocket.TextMessage, nil, []byte(bbo))
+
+	prices, err = p.GetTickerPrices(ATOMUSDT)
+	require.NoError(t, err)
+	require.Equal(t, sdk.MustNewDecFromStr("11.61"), prices[ATOMUSDT].Price)
+
+	// a one sided book leaves the ticker unchanged
+	bbo = `{"m":"bbo","symbol":"ATOM/USDT","data":{"ts":1687944835190,"bid":[],"ask":["11.7","1"]}}`
+	p.messageReceived(websocket.TextMessage, nil, []byte(bbo))
+
+	prices, err = p.GetTickerPrices(ATOMUSDT)
+	require.NoError(t, err)
+	require.Equal(t, sdk.MustNewDecFromStr("11.61"), prices[ATOMUSDT].Price)
+}
+
+func TestAscendexCurrencyPairToAscendexPair(t *testing.T) {
+	cp := types.CurrencyPair{Base: "ATOM", Quote: "USDT"}
+	ascendexSymbol := currencyPairToAscendexPair(cp)
+	require.Equal(t, ascendexSymbol, "ATOM/USDT")
+}
+
+func TestAscendexProvider_getSubscriptionMsgs(t *testing.T) {
+	provider := &AscendexProvider{}
+	cps := []types.CurrencyPair{
+		{Base: "ATOM", Quote: "USDT"},
+	}
+	subMsgs := provider.getSubscriptionMsgs(cps...)
+
+	msg, _ := json.Marshal(subMsgs[0])
+	require.Equal(t, `{"op":"sub","id":"bbo:ATOM/USDT","ch":"bbo:ATOM/USDT"}`, string(msg))
+
+	msg, _ = json.Marshal(subMsgs[1])
+	require.Equal(t, `{"op":"sub","id":"bar:1:ATOM/USDT","ch":"bar:1:ATOM/USDT"}`, string(msg))
+
+	require.Equal(t, `{"op":"ping"}`, string(pingMessage(ProviderAscendex)))
+}
@@ -13,14 +13,15 @@ import (
 // to, and uses the given provider's GetAvailablePairs method to check that the
 // given pairs can be subscribed to. It will return an updated list of pairs that
 // can be subsribed to, and send a warning log about any pairs passed in that
-// cannot be subsribed to.
+// cannot be subsribed to. A failed fetch of the available pairs is retried and
+// falls back to the symbol cache, see SetSymbolCache.
 func ConfirmPairAvailability(
 	p Provider,
 	providerName types.ProviderName,
 	logger zerolog.Logger,
 	cps ...types.CurrencyPair,
 ) ([]types.CurrencyPair, error) {
-	availablePairs, err := p.GetAvailablePairs()
+	availablePairs, err := getAvailablePairs(p, providerName, logger)
 	if err != nil {
 		return nil, err
 	}
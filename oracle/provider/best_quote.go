@@ -0,0 +1,99 @@
+package provider
+
+import (
+	"fmt"
+	"sync"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+
+	"github.com/ojo-network/price-feeder/oracle/types"
+)
+
+type (
+	// BestQuoteProvider is implemented by the providers whose tickers carry
+	// the best bid and ask of their pairs. A wide spread between them is a
+	// sign of poor liquidity, and so of an unreliable last traded price.
+	BestQuoteProvider interface {
+		// GetBestQuotes returns the latest best bid and ask of each of the
+		// currency pairs, for the pairs whose best quote was received.
+		GetBestQuotes(...types.CurrencyPair) map[types.CurrencyPair]BestQuote
+	}
+
+	// BestQuote defines the best bid and ask of a pair.
+	BestQuote struct {
+		Bid sdk.Dec
+		Ask sdk.Dec
+	}
+
+	// bestQuoteStore caches the latest best quote of each pair of a provider,
+	// keyed by the provider symbol.
+	bestQuoteStore struct {
+		mtx    sync.RWMutex
+		quotes map[string]BestQuote
+	}
+)
+
+// NewBestQuote parses the best bid and ask of a pair. It fails if either of
+// them is not positive or the quote is crossed.
+func NewBestQuote(bid, ask types.Number) (BestQuote, error) {
+	if _, err := validQuote(bid, "bid"); err != nil {
+		return BestQuote{}, err
+	}
+	if _, err := validQuote(ask, "ask"); err != nil {
+		return BestQuote{}, err
+	}
+	bidDec, _ := bid.Dec()
+	askDec, _ := ask.Dec()
+	if bidDec.GT(askDec) {
+		return BestQuote{}, fmt.Errorf("crossed book with bid %s above ask %s", bid, ask)
+	}
+	return BestQuote{Bid: bidDec, Ask: askDec}, nil
+}
+
+// Spread returns the spread between the best bid and ask relative to their
+// mid price, ex. 0.01 for a 1% spread.
+func (q BestQuote) Spread() sdk.Dec {
+	mid := q.Bid.Add(q.Ask).QuoInt64(2)
+	return q.Ask.Sub(q.Bid).Quo(mid)
+}
+
+func newBestQuoteStore() bestQuoteStore {
+	return bestQuoteStore{
+		quotes: map[string]BestQuote{},
+	}
+}
+
+// setBestQuote stores the best quote of the symbol, or removes it if the
+// quote is invalid so that an outdated quote is not used.
+func (s *bestQuoteStore) setBestQuote(symbol string, bid, ask types.Number) {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+
+	quote, err := NewBestQuote(bid, ask)
+	if err != nil {
+		delete(s.quotes, symbol)
+		return
+	}
+	if s.quotes == nil {
+		s.quotes = map[string]BestQuote{}
+	}
+	s.quotes[symbol] = quote
+}
+
+// bestQuotes returns the best quote of each of the currency pairs whose best
+// quote was received.
+func (s *bestQuoteStore) bestQuotes(
+	toSymbol func(types.CurrencyPair) string,
+	cps ...types.CurrencyPair,
+) map[types.CurrencyPair]BestQuote {
+	s.mtx.RLock()
+	defer s.mtx.RUnlock()
+
+	quotes := make(map[types.CurrencyPair]BestQuote, len(cps))
+	for _, cp := range cps {
+		if quote, ok := s.quotes[toSymbol(cp)]; ok {
+			quotes[cp] = quote
+		}
+	}
+	return quotes
+}
@@ -0,0 +1,69 @@
+package provider
+
+import (
+	"testing"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ojo-network/price-feeder/oracle/types"
+)
+
+func TestNewBestQuote(t *testing.T) {
+	testCases := []struct {
+		name      string
+		bid       types.Number
+		ask       types.Number
+		expSpread sdk.Dec
+		expectErr bool
+	}{
+		{
+			name:      "tight spread",
+			bid:       "11794.10",
+			ask:       "11794.20",
+			expSpread: sdk.MustNewDecFromStr("0.000008478779734021"),
+		},
+		{
+			name:      "wide spread",
+			bid:       "0.95",
+			ask:       "1.05",
+			expSpread: sdk.MustNewDecFromStr("0.1"),
+		},
+		{
+			name:      "locked book",
+			bid:       "10",
+			ask:       "10",
+			expSpread: sdk.ZeroDec(),
+		},
+		{
+			name:      "crossed book",
+			bid:       "10.1",
+			ask:       "10",
+			expectErr: true,
+		},
+		{
+			name:      "missing bid",
+			bid:       "0",
+			ask:       "10",
+			expectErr: true,
+		},
+		{
+			name:      "invalid ask",
+			bid:       "10",
+			ask:       "ten",
+			expectErr: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			quote, err := NewBestQuote(tc.bid, tc.ask)
+			if tc.expectErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tc.expSpread, quote.Spread())
+		})
+	}
+}
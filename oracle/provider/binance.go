@@ -3,10 +3,11 @@ package provider
 import (
 	"context"
 	"encoding/json"
-	"net/http"
+	"fmt"
 	"net/url"
 	"strings"
 	"sync"
+	"time"
 
 	"github.com/gorilla/websocket"
 	"github.com/rs/zerolog"
@@ -18,12 +19,21 @@ const (
 	binanceWSHost     = "stream.binance.com:9443"
 	binanceUSWSHost   = "stream.binance.us:9443"
 	binanceWSPath     = "/ws/ojostream"
+	binanceStreamPath = "/stream"
 	binanceRestHost   = "https://api1.binance.com"
 	binanceRestUSHost = "https://api.binance.us"
 	binanceRestPath   = "/api/v3/ticker/price"
+
+	binanceFuturesWSHost   = "fstream.binance.com"
+	binanceMarkPriceStream = "@markPrice@1s"
+	binanceMarkPriceEvent  = "markPriceUpdate"
+	binanceCandleInterval  = "1m"
 )
 
-var _ Provider = (*BinanceProvider)(nil)
+var (
+	_ Provider          = (*BinanceProvider)(nil)
+	_ BestQuoteProvider = (*BinanceProvider)(nil)
+)
 
 type (
 	// BinanceProvider defines an Oracle provider implemented by the Binance public
@@ -37,26 +47,46 @@ type (
 		mtx       sync.RWMutex
 		endpoints Endpoint
 
+		// priceTypes holds the pairs priced by a perpetual mark or index
+		// price instead of the spot market, ex.: map["BTCUSDT"] = "mark"
+		priceTypes  map[string]PriceType
+		derivatives derivativeStore
+
+		// priceSources holds the pairs priced by their best quote instead of
+		// the last traded price, ex.: map["BTCUSDT"] = "mid"
+		priceSources map[string]PriceSource
+
+		// bestQuotes holds the latest best bid and ask of the spot pairs
+		bestQuotes bestQuoteStore
+
 		priceStore
 	}
 
 	// BinanceTicker ticker price response. https://pkg.go.dev/encoding/json#Unmarshal
 	// Unmarshal matches incoming object keys to the keys used by Marshal (either the
 	// struct field name or its tag), preferring an exact match but also accepting a
-	// case-insensitive match. C field which is Statistics close time is not used, but
-	// it avoids to implement specific UnmarshalJSON.
+	// case-insensitive match. C, B and A fields which are Statistics close time and
+	// the best bid and ask quantities are not used, but it avoids to implement
+	// specific UnmarshalJSON.
 	BinanceTicker struct {
-		Symbol    string `json:"s"` // Symbol ex.: BTCUSDT
-		LastPrice string `json:"c"` // Last price ex.: 0.0025
-		Volume    string `json:"v"` // Total traded base asset volume ex.: 1000
-		C         uint64 `json:"C"` // Statistics close time
+		Event     string       `json:"e"` // Event type ex.: 24hrTicker
+		EventTime int64        `json:"E"` // Event time in unix milliseconds
+		Symbol    string       `json:"s"` // Symbol ex.: BTCUSDT
+		LastPrice types.Number `json:"c"` // Last price ex.: 0.0025
+		Volume    types.Number `json:"v"` // Total traded base asset volume ex.: 1000
+		C         uint64       `json:"C"` // Statistics close time
+		BidPrice  types.Number `json:"b"` // Best bid price ex.: 0.0024
+		B         string       `json:"B"` // Best bid quantity
+		AskPrice  types.Number `json:"a"` // Best ask price ex.: 0.0026
+		A         string       `json:"A"` // Best ask quantity
 	}
 
 	// BinanceCandleMetadata candle metadata used to compute tvwap price.
 	BinanceCandleMetadata struct {
-		Close     string `json:"c"` // Price at close
-		TimeStamp int64  `json:"T"` // Close time in unix epoch ex.: 1645756200000
-		Volume    string `json:"v"` // Volume during period
+		Close     types.Number `json:"c"` // Price at close
+		TimeStamp int64        `json:"T"` // Close time in unix epoch ex.: 1645756200000
+		Volume    types.Number `json:"v"` // Volume during period
+		Interval  string       `json:"i"` // Interval ex.: 1m
 	}
 
 	// BinanceCandle candle binance websocket channel "kline_1m" response.
@@ -65,6 +95,24 @@ type (
 		Metadata BinanceCandleMetadata `json:"k"` // Metadata for candle
 	}
 
+	// BinanceMarkPrice mark price binance futures websocket channel
+	// "markPrice" response.
+	BinanceMarkPrice struct {
+		Event      string       `json:"e"` // Event type ex.: markPriceUpdate
+		Symbol     string       `json:"s"` // Symbol ex.: BTCUSDT
+		MarkPrice  types.Number `json:"p"` // Mark price ex.: 11794.15000000
+		IndexPrice types.Number `json:"i"` // Index price ex.: 11784.62659091
+	}
+
+	// BinanceCombinedStream defines the envelope of the payloads of the
+	// combined stream endpoint, routed to their pair by the stream name.
+	//
+	// REF: https://binance-docs.github.io/apidocs/spot/en/#websocket-market-streams
+	BinanceCombinedStream struct {
+		Stream string          `json:"stream"` // Stream name ex.: atomusdt@ticker
+		Data   json.RawMessage `json:"data"`   // Raw payload of the stream
+	}
+
 	// BinanceSubscribeMsg Msg to subscribe all the tickers channels.
 	BinanceSubscriptionMsg struct {
 		Method string   `json:"method"` // SUBSCRIBE/UNSUBSCRIBE
@@ -98,12 +146,18 @@ func NewBinanceProvider(
 				Name:      ProviderBinance,
 				Rest:      binanceRestHost,
 				Websocket: binanceWSHost,
+				// the candle intervals may be selected for the twap windows
+				CandleIntervals:   endpoints.CandleIntervals,
+				SilenceTimeout:    endpoints.SilenceTimeout,
+				ReconnectCooldown: endpoints.ReconnectCooldown,
 			}
 		} else {
 			endpoints = Endpoint{
-				Name:      ProviderBinanceUS,
-				Rest:      binanceRestUSHost,
-				Websocket: binanceUSWSHost,
+				Name:              ProviderBinanceUS,
+				Rest:              binanceRestUSHost,
+				Websocket:         binanceUSWSHost,
+				SilenceTimeout:    endpoints.SilenceTimeout,
+				ReconnectCooldown: endpoints.ReconnectCooldown,
 			}
 		}
 	}
@@ -113,13 +167,21 @@ func NewBinanceProvider(
 		Host:   endpoints.Websocket,
 		Path:   binanceWSPath,
 	}
+	if endpoints.CombinedStream {
+		wsURL.Path = binanceStreamPath
+	}
 
 	binanceLogger := logger.With().Str("provider", string(ProviderBinance)).Logger()
 
 	provider := &BinanceProvider{
-		logger:     binanceLogger,
-		endpoints:  endpoints,
-		priceStore: newPriceStore(binanceLogger),
+		logger:      binanceLogger,
+		endpoints:   endpoints,
+		priceTypes:  endpoints.pairPriceTypes(currencyPairToBinanceSymbol),
+		derivatives: newDerivativeStore(),
+		priceStore:  newPriceStore(binanceLogger),
+
+		priceSources: endpoints.pairPriceSources(currencyPairToBinanceSymbol),
+		bestQuotes:   newBestQuoteStore(),
 	}
 
 	confirmedPairs, err := ConfirmPairAvailability(
@@ -142,6 +204,11 @@ func NewBinanceProvider(
 		provider.messageReceived,
 		disabledPingDuration,
 		websocket.PingMessage,
+		endpoints.SilenceTimeout,
+		endpoints.ReconnectCooldown,
+		&provider.priceStore,
+		endpoints.tlsConfig,
+		endpoints.header,
 		binanceLogger,
 	)
 
@@ -152,14 +219,47 @@ func (p *BinanceProvider) StartConnections() {
 	p.wsc.StartConnections()
 }
 
+// getSubscriptionMsgs returns the subscription messages of the pairs, each
+// served by a connection of its own. With the combined stream, the spot
+// streams of all the pairs are subscribed to by a single message instead.
 func (p *BinanceProvider) getSubscriptionMsgs(cps ...types.CurrencyPair) []interface{} {
 	subscriptionMsgs := make([]interface{}, 0, len(p.subscribedPairs)*2)
+	combinedStreams := make([]string, 0, len(cps)*2)
 	for _, cp := range cps {
+		if _, ok := p.priceTypes[currencyPairToBinanceSymbol(cp)]; ok {
+			// the futures ticker is only used for the volume of the perpetual market
+			subscriptionMsgs = append(subscriptionMsgs, newBinanceSubscriptionMsg(
+				currencyPairToBinanceTickerPair(cp),
+				currencyPairToBinanceMarkPricePair(cp),
+			))
+			continue
+		}
+
 		binanceTickerPair := currencyPairToBinanceTickerPair(cp)
-		subscriptionMsgs = append(subscriptionMsgs, newBinanceSubscriptionMsg(binanceTickerPair))
+		if p.endpoints.CombinedStream {
+			combinedStreams = append(combinedStreams, binanceTickerPair)
+		} else {
+			subscriptionMsgs = append(subscriptionMsgs, newBinanceSubscriptionMsg(binanceTickerPair))
+		}
+
+		if _, ok := p.priceSources[currencyPairToBinanceSymbol(cp)]; ok {
+			// candles are built from trades and would outweigh the best quote
+			continue
+		}
 
-		binanceCandlePair := currencyPairToBinanceCandlePair(cp)
-		subscriptionMsgs = append(subscriptionMsgs, newBinanceSubscriptionMsg(binanceCandlePair))
+		candleIntervals := p.endpoints.candleIntervals(binanceCandleInterval)
+		binanceCandlePairs := make([]string, 0, len(candleIntervals))
+		for _, interval := range candleIntervals {
+			binanceCandlePairs = append(binanceCandlePairs, currencyPairToBinanceCandlePair(cp, interval))
+		}
+		if p.endpoints.CombinedStream {
+			combinedStreams = append(combinedStreams, binanceCandlePairs...)
+		} else {
+			subscriptionMsgs = append(subscriptionMsgs, newBinanceSubscriptionMsg(binanceCandlePairs...))
+		}
+	}
+	if len(combinedStreams) > 0 {
+		subscriptionMsgs = append(subscriptionMsgs, newBinanceSubscriptionMsg(combinedStreams...))
 	}
 	return subscriptionMsgs
 }
@@ -198,26 +298,50 @@ func (p *BinanceProvider) SubscribeCurrencyPairs(cps ...types.CurrencyPair) {
 }
 
 func (p *BinanceProvider) messageReceived(_ int, _ *WebsocketConnection, bz []byte) {
+	var combinedResp BinanceCombinedStream
+	if err := json.Unmarshal(bz, &combinedResp); err == nil && combinedResp.Stream != "" {
+		if err := p.streamReceived(combinedResp.Stream, combinedResp.Data); err != nil {
+			p.logger.Error().
+				Err(err).
+				Str("stream", combinedResp.Stream).
+				Msg("Error on receive combined stream message")
+		}
+		return
+	}
+
 	var (
 		tickerResp       BinanceTicker
 		tickerErr        error
 		candleResp       BinanceCandle
 		candleErr        error
+		markPriceResp    BinanceMarkPrice
+		markPriceErr     error
 		subscribeResp    BinanceSubscriptionResp
 		subscribeRespErr error
 	)
 
 	tickerErr = json.Unmarshal(bz, &tickerResp)
 	if len(tickerResp.LastPrice) != 0 {
-		p.setTickerPair(tickerResp, tickerResp.Symbol)
+		p.tickerReceived(tickerResp)
+		return
+	}
+
+	markPriceErr = json.Unmarshal(bz, &markPriceResp)
+	if markPriceResp.Event == binanceMarkPriceEvent {
+		price := markPriceResp.MarkPrice
+		if p.priceTypes[markPriceResp.Symbol] == PriceTypeIndex {
+			price = markPriceResp.IndexPrice
+		}
+		if ticker, ok := p.derivatives.setPrice(markPriceResp.Symbol, price.String()); ok {
+			p.setTickerPair(ticker, markPriceResp.Symbol)
+		}
 		telemetryWebsocketMessage(ProviderBinance, MessageTypeTicker)
 		return
 	}
 
 	candleErr = json.Unmarshal(bz, &candleResp)
 	if len(candleResp.Metadata.Close) != 0 {
-		p.setCandlePair(candleResp, candleResp.Symbol)
-		telemetryWebsocketMessage(ProviderBinance, MessageTypeCandle)
+		p.candleReceived(candleResp)
 		return
 	}
 
@@ -230,22 +354,103 @@ func (p *BinanceProvider) messageReceived(_ int, _ *WebsocketConnection, bz []by
 		Int("length", len(bz)).
 		AnErr("ticker", tickerErr).
 		AnErr("candle", candleErr).
+		AnErr("markPrice", markPriceErr).
 		AnErr("subscribeResp", subscribeRespErr).
 		Msg("Error on receive message")
+	telemetryDecodeError(p.logger, p.endpoints.Name, bz)
 }
 
 func (ticker BinanceTicker) toTickerPrice() (types.TickerPrice, error) {
-	return types.NewTickerPrice(ticker.LastPrice, ticker.Volume)
+	return types.NewTickerPrice(ticker.LastPrice.String(), ticker.Volume.String())
+}
+
+func (ticker BinanceTicker) exchangeTime() int64 {
+	return ticker.EventTime
 }
 
 func (candle BinanceCandle) toCandlePrice() (types.CandlePrice, error) {
-	return types.NewCandlePrice(candle.Metadata.Close, candle.Metadata.Volume, candle.Metadata.TimeStamp)
+	candlePrice, err := types.NewCandlePrice(candle.Metadata.Close.String(), candle.Metadata.Volume.String(), candle.Metadata.TimeStamp)
+	if err != nil {
+		return types.CandlePrice{}, err
+	}
+	if candle.Metadata.Interval != "" {
+		if candlePrice.Interval, err = time.ParseDuration(candle.Metadata.Interval); err != nil {
+			return types.CandlePrice{}, fmt.Errorf("failed to parse candle interval (%s): %w", candle.Metadata.Interval, err)
+		}
+	}
+	return candlePrice, nil
+}
+
+// streamReceived routes the payload of a combined stream message to the
+// handler of its stream, by the stream name.
+func (p *BinanceProvider) streamReceived(stream string, data json.RawMessage) error {
+	switch {
+	case strings.HasSuffix(stream, "@ticker"):
+		var tickerResp BinanceTicker
+		if err := json.Unmarshal(data, &tickerResp); err != nil {
+			return err
+		}
+		if len(tickerResp.LastPrice) == 0 {
+			return fmt.Errorf("ticker without a last price")
+		}
+		p.tickerReceived(tickerResp)
+
+	case strings.Contains(stream, "@kline_"):
+		var candleResp BinanceCandle
+		if err := json.Unmarshal(data, &candleResp); err != nil {
+			return err
+		}
+		if len(candleResp.Metadata.Close) == 0 {
+			return fmt.Errorf("candle without a close price")
+		}
+		p.candleReceived(candleResp)
+
+	default:
+		return fmt.Errorf("unknown stream")
+	}
+	return nil
+}
+
+// tickerReceived sets the ticker of the pair, or the volume of the perpetual
+// market of a pair priced by a derivative price.
+func (p *BinanceProvider) tickerReceived(tickerResp BinanceTicker) {
+	if _, ok := p.priceTypes[tickerResp.Symbol]; ok {
+		if ticker, ok := p.derivatives.setVolume(tickerResp.Symbol, tickerResp.Volume.String()); ok {
+			p.setTickerPair(ticker, tickerResp.Symbol)
+		}
+		telemetryWebsocketMessage(ProviderBinance, MessageTypeTicker)
+		return
+	}
+
+	p.bestQuotes.setBestQuote(tickerResp.Symbol, tickerResp.BidPrice, tickerResp.AskPrice)
+	if source, ok := p.priceSources[tickerResp.Symbol]; ok {
+		price, err := source.quotePrice(tickerResp.LastPrice, tickerResp.BidPrice, tickerResp.AskPrice)
+		if err != nil {
+			p.logger.Error().Err(err).Str("symbol", tickerResp.Symbol).Msg("failed to get best quote price")
+			return
+		}
+		tickerResp.LastPrice = price
+		p.setTickerPair(tickerResp, tickerResp.Symbol)
+	} else {
+		p.setTickerPair(tickerResp, tickerResp.Symbol)
+	}
+	telemetryWebsocketMessage(ProviderBinance, MessageTypeTicker)
+}
+
+func (p *BinanceProvider) candleReceived(candleResp BinanceCandle) {
+	p.setCandlePair(candleResp, candleResp.Symbol)
+	telemetryWebsocketMessage(ProviderBinance, MessageTypeCandle)
+}
+
+// GetBestQuotes returns the latest best bid and ask of the spot pairs.
+func (p *BinanceProvider) GetBestQuotes(cps ...types.CurrencyPair) map[types.CurrencyPair]BestQuote {
+	return p.bestQuotes.bestQuotes(currencyPairToBinanceSymbol, cps...)
 }
 
 // GetAvailablePairs returns all pairs to which the provider can subscribe.
 // ex.: map["ATOMUSDT" => {}, "OJOUSDC" => {}].
 func (p *BinanceProvider) GetAvailablePairs() (map[string]struct{}, error) {
-	resp, err := http.Get(p.endpoints.Rest + binanceRestPath)
+	resp, err := p.endpoints.httpClient().Get(p.endpoints.Rest + binanceRestPath)
 	if err != nil {
 		return nil, err
 	}
@@ -264,16 +469,28 @@ func (p *BinanceProvider) GetAvailablePairs() (map[string]struct{}, error) {
 	return availablePairs, nil
 }
 
+// currencyPairToBinanceSymbol receives a currency pair and return binance
+// symbol ATOMUSDT.
+func currencyPairToBinanceSymbol(cp types.CurrencyPair) string {
+	return strings.ToUpper(cp.String())
+}
+
+// currencyPairToBinanceMarkPricePair receives a currency pair and return
+// binance futures mark price symbol atomusdt@markPrice@1s.
+func currencyPairToBinanceMarkPricePair(cp types.CurrencyPair) string {
+	return strings.ToLower(cp.String()) + binanceMarkPriceStream
+}
+
 // currencyPairToBinanceTickerPair receives a currency pair and return binance
 // ticker symbol atomusdt@ticker.
 func currencyPairToBinanceTickerPair(cp types.CurrencyPair) string {
 	return strings.ToLower(cp.String() + "@ticker")
 }
 
-// currencyPairToBinanceCandlePair receives a currency pair and return binance
-// candle symbol atomusdt@kline_1m.
-func currencyPairToBinanceCandlePair(cp types.CurrencyPair) string {
-	return strings.ToLower(cp.String() + "@kline_1m")
+// currencyPairToBinanceCandlePair receives a currency pair and a candle
+// interval and return binance candle symbol atomusdt@kline_1m.
+func currencyPairToBinanceCandlePair(cp types.CurrencyPair, interval string) string {
+	return strings.ToLower(cp.String() + "@kline_" + interval)
 }
 
 // newBinanceSubscriptionMsg returns a new subscription Msg.
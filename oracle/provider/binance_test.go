@@ -4,6 +4,7 @@ import (
 	"context"
 	"encoding/json"
 	"testing"
+	"time"
 
 	sdk "github.com/cosmos/cosmos-sdk/types"
 	"github.com/ojo-network/price-feeder/oracle/types"
@@ -28,8 +29,8 @@ func TestBinanceProvider_GetTickerPrices(t *testing.T) {
 		tickerMap := map[string]BinanceTicker{}
 		tickerMap["ATOMUSDT"] = BinanceTicker{
 			Symbol:    "ATOMUSDT",
-			LastPrice: lastPrice,
-			Volume:    volume,
+			LastPrice: types.Number(lastPrice),
+			Volume:    types.Number(volume),
 		}
 
 		for _, ticker := range tickerMap {
@@ -51,14 +52,14 @@ func TestBinanceProvider_GetTickerPrices(t *testing.T) {
 		tickerMap := map[string]BinanceTicker{}
 		tickerMap["ATOMUSDT"] = BinanceTicker{
 			Symbol:    "ATOMUSDT",
-			LastPrice: lastPriceAtom,
-			Volume:    volume,
+			LastPrice: types.Number(lastPriceAtom),
+			Volume:    types.Number(volume),
 		}
 
 		tickerMap["LUNAUSDT"] = BinanceTicker{
 			Symbol:    "LUNAUSDT",
-			LastPrice: lastPriceLuna,
-			Volume:    volume,
+			LastPrice: types.Number(lastPriceLuna),
+			Volume:    types.Number(volume),
 		}
 
 		for _, ticker := range tickerMap {
@@ -104,3 +105,177 @@ func TestBinanceProvider_getSubscriptionMsgs(t *testing.T) {
 	msg, _ = json.Marshal(subMsgs[1])
 	require.Equal(t, "{\"method\":\"SUBSCRIBE\",\"params\":[\"atomusdt@kline_1m\"],\"id\":1}", string(msg))
 }
+
+func TestBinanceProvider_getSubscriptionMsgs_CandleIntervals(t *testing.T) {
+	provider := &BinanceProvider{
+		endpoints: Endpoint{CandleIntervals: []string{"1m", "5m"}},
+	}
+	subMsgs := provider.getSubscriptionMsgs(types.CurrencyPair{Base: "ATOM", Quote: "USDT"})
+	require.Len(t, subMsgs, 2)
+
+	msg, _ := json.Marshal(subMsgs[1])
+	require.Equal(t, "{\"method\":\"SUBSCRIBE\",\"params\":[\"atomusdt@kline_1m\",\"atomusdt@kline_5m\"],\"id\":1}", string(msg))
+}
+
+func TestBinanceCandle_toCandlePrice(t *testing.T) {
+	var candle BinanceCandle
+	require.NoError(t, json.Unmarshal([]byte(
+		`{"e":"kline","s":"ATOMUSDT","k":{"T":1687944899999,"i":"5m","c":"11.53","v":"182.47"}}`,
+	), &candle))
+
+	candlePrice, err := candle.toCandlePrice()
+	require.NoError(t, err)
+	require.Equal(t, 5*time.Minute, candlePrice.Interval)
+	require.Equal(t, int64(1687944899999), candlePrice.TimeStamp)
+}
+
+func TestBinanceProvider_getSubscriptionMsgs_MarkPrice(t *testing.T) {
+	provider := &BinanceProvider{
+		priceTypes: map[string]PriceType{"BTCUSDT": PriceTypeMark},
+		priceStore: newPriceStore(zerolog.Nop()),
+	}
+	cps := []types.CurrencyPair{
+		{Base: "BTC", Quote: "USDT"},
+	}
+
+	subMsgs := provider.getSubscriptionMsgs(cps...)
+	require.Len(t, subMsgs, 1)
+
+	msg, _ := json.Marshal(subMsgs[0])
+	require.Equal(t, "{\"method\":\"SUBSCRIBE\",\"params\":[\"btcusdt@ticker\",\"btcusdt@markPrice@1s\"],\"id\":1}", string(msg))
+}
+
+func TestBinanceProvider_messageReceived_IndexPrice(t *testing.T) {
+	p := &BinanceProvider{
+		logger:      zerolog.Nop(),
+		priceTypes:  map[string]PriceType{"BTCUSDT": PriceTypeIndex},
+		derivatives: newDerivativeStore(),
+		priceStore:  newPriceStore(zerolog.Nop()),
+	}
+
+	p.messageReceived(0, nil, []byte(`{"e":"24hrTicker","s":"BTCUSDT","c":"11800.1","v":"2500.5"}`))
+	p.messageReceived(0, nil, []byte(`{"e":"markPriceUpdate","s":"BTCUSDT","p":"11794.15","i":"11784.62"}`))
+
+	prices, err := p.GetTickerPrices(BTCUSDT)
+	require.NoError(t, err)
+	require.Len(t, prices, 1)
+	require.Equal(t, sdk.MustNewDecFromStr("11784.62"), prices[BTCUSDT].Price)
+	require.Equal(t, sdk.MustNewDecFromStr("2500.5"), prices[BTCUSDT].Volume)
+}
+
+func TestBinanceProvider_getSubscriptionMsgs_PriceSource(t *testing.T) {
+	provider := &BinanceProvider{
+		priceSources: map[string]PriceSource{"BTCUSDT": PriceSourceMid},
+		priceStore:   newPriceStore(zerolog.Nop()),
+	}
+	cps := []types.CurrencyPair{
+		{Base: "BTC", Quote: "USDT"},
+	}
+
+	subMsgs := provider.getSubscriptionMsgs(cps...)
+	require.Len(t, subMsgs, 1)
+
+	msg, _ := json.Marshal(subMsgs[0])
+	require.Equal(t, "{\"method\":\"SUBSCRIBE\",\"params\":[\"btcusdt@ticker\"],\"id\":1}", string(msg))
+}
+
+func TestBinanceProvider_messageReceived_PriceSource(t *testing.T) {
+	p := &BinanceProvider{
+		logger: zerolog.Nop(),
+		priceSources: map[string]PriceSource{
+			"BTCUSDT":  PriceSourceMid,
+			"ATOMUSDT": PriceSourceAsk,
+		},
+		priceStore: newPriceStore(zerolog.Nop()),
+	}
+
+	p.messageReceived(0, nil, []byte(`{"e":"24hrTicker","E":1672515782136,"s":"BTCUSDT","p":"0.0015",`+
+		`"P":"250.00","w":"0.0018","x":"0.0009","c":"11800.1","Q":"10","b":"11794.10","B":"10",`+
+		`"a":"11794.20","A":"100","o":"0.0010","h":"0.0025","l":"0.0010","v":"2500.5","q":"18",`+
+		`"O":0,"C":86400000,"F":0,"L":18150,"n":18151}`))
+	p.messageReceived(0, nil, []byte(`{"e":"24hrTicker","s":"ATOMUSDT","c":"1600.1","v":"100",`+
+		`"b":"1600.00","B":"3","a":"1600.20","A":"7"}`))
+
+	prices, err := p.GetTickerPrices(BTCUSDT, ATOMUSDT)
+	require.NoError(t, err)
+	require.Len(t, prices, 2)
+	require.Equal(t, sdk.MustNewDecFromStr("11794.15"), prices[BTCUSDT].Price)
+	require.Equal(t, sdk.MustNewDecFromStr("2500.5"), prices[BTCUSDT].Volume)
+	require.Equal(t, sdk.MustNewDecFromStr("1600.2"), prices[ATOMUSDT].Price)
+}
+
+func TestBinanceProvider_GetBestQuotes(t *testing.T) {
+	p := &BinanceProvider{
+		logger:      zerolog.Nop(),
+		priceTypes:  map[string]PriceType{"BTCUSDT": PriceTypeMark},
+		derivatives: newDerivativeStore(),
+		priceStore:  newPriceStore(zerolog.Nop()),
+	}
+
+	p.messageReceived(0, nil, []byte(`{"e":"24hrTicker","E":1672515782136,"s":"ATOMUSDT","p":"0.0015",`+
+		`"P":"250.00","w":"0.0018","x":"0.0009","c":"9.87","Q":"10","b":"9.86","B":"120",`+
+		`"a":"9.88","A":"75","o":"9.80","h":"10.10","l":"9.70","v":"182000.5","q":"1796000",`+
+		`"O":0,"C":86400000,"F":0,"L":18150,"n":18151}`))
+	p.messageReceived(0, nil, []byte(`{"e":"24hrTicker","s":"OJOUSDT","c":"0.0532","v":"4100.2",`+
+		`"b":"0.0510","B":"900","a":"0.0550","A":"1200"}`))
+	// the perpetual ticker of a mark priced pair carries no spot quote
+	p.messageReceived(0, nil, []byte(`{"e":"24hrTicker","s":"BTCUSDT","c":"11800.1","v":"2500.5",`+
+		`"b":"11000","a":"12000"}`))
+
+	quotes := p.GetBestQuotes(ATOMUSDT, OJOUSDT, BTCUSDT)
+	require.Len(t, quotes, 2)
+	require.Equal(t, sdk.MustNewDecFromStr("9.86"), quotes[ATOMUSDT].Bid)
+	require.Equal(t, sdk.MustNewDecFromStr("9.88"), quotes[ATOMUSDT].Ask)
+	require.Equal(t, sdk.MustNewDecFromStr("0.075471698113207547"), quotes[OJOUSDT].Spread())
+
+	// a crossed book replaces the previous quote
+	p.messageReceived(0, nil, []byte(`{"e":"24hrTicker","s":"OJOUSDT","c":"0.0532","v":"4100.2",`+
+		`"b":"0.0560","a":"0.0550"}`))
+	require.NotContains(t, p.GetBestQuotes(OJOUSDT), OJOUSDT)
+}
+
+func TestBinanceProvider_getSubscriptionMsgs_CombinedStream(t *testing.T) {
+	provider := &BinanceProvider{
+		endpoints:  Endpoint{CombinedStream: true},
+		priceTypes: map[string]PriceType{"BTCUSDT": PriceTypeMark},
+		priceStore: newPriceStore(zerolog.Nop()),
+	}
+
+	subMsgs := provider.getSubscriptionMsgs(ATOMUSDT, OJOUSDT, BTCUSDT)
+	require.Len(t, subMsgs, 2)
+
+	// the mark price is still subscribed to on the futures stream
+	msg, _ := json.Marshal(subMsgs[0])
+	require.Equal(t, "{\"method\":\"SUBSCRIBE\",\"params\":[\"btcusdt@ticker\",\"btcusdt@markPrice@1s\"],\"id\":1}", string(msg))
+
+	msg, _ = json.Marshal(subMsgs[1])
+	require.Equal(t, "{\"method\":\"SUBSCRIBE\",\"params\":[\"atomusdt@ticker\",\"atomusdt@kline_1m\","+
+		"\"ojousdt@ticker\",\"ojousdt@kline_1m\"],\"id\":1}", string(msg))
+}
+
+func TestBinanceProvider_messageReceived_CombinedStream(t *testing.T) {
+	p := &BinanceProvider{
+		logger:     zerolog.Nop(),
+		endpoints:  Endpoint{CombinedStream: true},
+		priceStore: newPriceStore(zerolog.Nop()),
+	}
+
+	p.messageReceived(0, nil, []byte(`{"stream":"atomusdt@ticker","data":{"e":"24hrTicker","E":1687944890000,`+
+		`"s":"ATOMUSDT","c":"9.87","v":"182000.5","b":"9.86","a":"9.88"}}`))
+	p.messageReceived(0, nil, []byte(`{"stream":"ojousdt@kline_1m","data":{"e":"kline","E":1687944890000,`+
+		`"s":"OJOUSDT","k":{"T":1687944899999,"i":"1m","c":"0.0532","v":"4100.2"}}}`))
+
+	prices, err := p.GetTickerPrices(ATOMUSDT)
+	require.NoError(t, err)
+	require.Equal(t, sdk.MustNewDecFromStr("9.87"), prices[ATOMUSDT].Price)
+	require.Equal(t, sdk.MustNewDecFromStr("182000.5"), prices[ATOMUSDT].Volume)
+
+	candles, err := p.GetCandlePrices(OJOUSDT)
+	require.NoError(t, err)
+	require.Len(t, candles[OJOUSDT], 1)
+	require.Equal(t, sdk.MustNewDecFromStr("0.0532"), candles[OJOUSDT][0].Price)
+	require.Equal(t, int64(1687944899999), candles[OJOUSDT][0].TimeStamp)
+
+	// the payload of an unknown stream is not routed
+	require.Error(t, p.streamReceived("atomusdt@depth", []byte(`{"s":"ATOMUSDT","c":"1"}`)))
+}
@@ -0,0 +1,314 @@
+package provider
+
+import (
+	"context"
+	"encoding/json"
+	"net/url"
+	"strings"
+	"sync"
+
+	"github.com/gorilla/websocket"
+	"github.com/ojo-network/price-feeder/oracle/types"
+	"github.com/rs/zerolog"
+)
+
+const (
+	bingxWSHost   = "open-api-ws.bingx.com"
+	bingxWSPath   = "/market"
+	bingxRestHost = "https://open-api.bingx.com"
+	bingxRestPath = "/openApi/spot/v1/common/symbols"
+
+	bingxTickerSuffix = "@ticker"
+	bingxCandleSuffix = "@kline_1min"
+)
+
+var _ Provider = (*BingxProvider)(nil)
+
+type (
+	// BingxProvider defines an Oracle provider implemented by the BingX public
+	// API.
+	//
+	// REF: https://bingx-api.github.io/docs/#/spot/socket/market.html
+	BingxProvider struct {
+		wsc       *WebsocketController
+		logger    zerolog.Logger
+		mtx       sync.RWMutex
+		endpoints Endpoint
+
+		priceStore
+	}
+
+	// BingxTickerResponse is the ticker topic response object.
+	BingxTickerResponse struct {
+		DataType string      `json:"dataType"` // ex.: BTC-USDT@ticker
+		Data     BingxTicker `json:"data"`
+	}
+	// BingxTicker is the 24h ticker. C is the statistics close time, which is
+	// not used but keeps the case-insensitive match of the decoder from
+	// setting it as the last price.
+	BingxTicker struct {
+		Symbol    string       `json:"s"` // Symbol ex.: BTC-USDT
+		LastPrice types.Number `json:"c"` // Last price ex.: 43508.9
+		Volume    types.Number `json:"v"` // Total traded base asset volume ex.: 1000
+		C         int64        `json:"C"` // Statistics close time
+	}
+
+	// BingxCandleResponse is the kline topic response object.
+	BingxCandleResponse struct {
+		DataType string          `json:"dataType"` // ex.: BTC-USDT@kline_1min
+		Data     BingxCandleData `json:"data"`
+	}
+	BingxCandleData struct {
+		Symbol string      `json:"s"` // Symbol ex.: BTC-USDT
+		Candle BingxCandle `json:"K"`
+	}
+	BingxCandle struct {
+		Close     types.Number `json:"c"` // Price at close
+		TimeStamp int64        `json:"T"` // Close time in unix epoch ex.: 1672124459999
+		Volume    types.Number `json:"v"` // Volume during period
+	}
+
+	// BingxSubscriptionMsg Msg to subscribe to one topic.
+	BingxSubscriptionMsg struct {
+		ID       string `json:"id"`       // identify the subscription response
+		ReqType  string `json:"reqType"`  // sub
+		DataType string `json:"dataType"` // topic ex.: BTC-USDT@ticker
+	}
+
+	// BingxHeartbeat is the ping message sent by the server and the pong
+	// message answering it.
+	BingxHeartbeat struct {
+		Ping string `json:"ping,omitempty"` // ping id
+		Pong string `json:"pong,omitempty"` // id of the ping answered
+		Time string `json:"time"`           // ex.: 2023-06-28T17:33:55.188+0800
+	}
+
+	// BingxPairsSummary defines the response structure for the BingX
+	// available pairs.
+	BingxPairsSummary struct {
+		Data struct {
+			Symbols []struct {
+				Symbol string `json:"symbol"` // ex.: BTC-USDT
+			} `json:"symbols"`
+		} `json:"data"`
+	}
+)
+
+func NewBingxProvider(
+	ctx context.Context,
+	logger zerolog.Logger,
+	endpoints Endpoint,
+	pairs ...types.CurrencyPair,
+) (*BingxProvider, error) {
+	if endpoints.Name != ProviderBingx {
+		endpoints = Endpoint{
+			Name:              ProviderBingx,
+			Rest:              bingxRestHost,
+			Websocket:         bingxWSHost,
+			SilenceTimeout:    endpoints.SilenceTimeout,
+			ReconnectCooldown: endpoints.ReconnectCooldown,
+		}
+	}
+
+	wsURL := url.URL{
+		Scheme: "wss",
+		Host:   endpoints.Websocket,
+		Path:   bingxWSPath,
+	}
+
+	bingxLogger := logger.With().Str("provider", string(ProviderBingx)).Logger()
+
+	provider := &BingxProvider{
+		logger:     bingxLogger,
+		endpoints:  endpoints,
+		priceStore: newPriceStore(bingxLogger),
+	}
+	provider.setCurrencyPairToTickerAndCandlePair(currencyPairToBingxPair)
+
+	confirmedPairs, err := ConfirmPairAvailability(
+		provider,
+		provider.endpoints.Name,
+		provider.logger,
+		pairs...,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	provider.setSubscribedPairs(confirmedPairs...)
+
+	provider.wsc = NewWebsocketController(
+		ctx,
+		endpoints.Name,
+		wsURL,
+		provider.getSubscriptionMsgs(confirmedPairs...),
+		provider.messageReceived,
+		disabledPingDuration,
+		websocket.PingMessage,
+		endpoints.SilenceTimeout,
+		endpoints.ReconnectCooldown,
+		&provider.priceStore,
+		endpoints.tlsConfig,
+		endpoints.header,
+		bingxLogger,
+	)
+
+	return provider, nil
+}
+
+func (p *BingxProvider) StartConnections() {
+	p.wsc.StartConnections()
+}
+
+func (p *BingxProvider) getSubscriptionMsgs(cps ...types.CurrencyPair) []interface{} {
+	subscriptionMsgs := make([]interface{}, 0, len(cps)*2)
+	for _, cp := range cps {
+		bingxPair := currencyPairToBingxPair(cp)
+		subscriptionMsgs = append(subscriptionMsgs, newBingxSubscriptionMsg(bingxPair+bingxTickerSuffix))
+		subscriptionMsgs = append(subscriptionMsgs, newBingxSubscriptionMsg(bingxPair+bingxCandleSuffix))
+	}
+	return subscriptionMsgs
+}
+
+// SubscribeCurrencyPairs sends the new subscription messages to the websocket
+// and adds them to the providers subscribedPairs array
+func (p *BingxProvider) SubscribeCurrencyPairs(cps ...types.CurrencyPair) {
+	p.mtx.Lock()
+	defer p.mtx.Unlock()
+
+	newPairs := []types.CurrencyPair{}
+	for _, cp := range cps {
+		if _, ok := p.subscribedPairs[cp.String()]; !ok {
+			newPairs = append(newPairs, cp)
+		}
+	}
+
+	confirmedPairs, err := ConfirmPairAvailability(
+		p,
+		p.endpoints.Name,
+		p.logger,
+		newPairs...,
+	)
+	if err != nil {
+		return
+	}
+
+	newSubscriptionMsgs := p.getSubscriptionMsgs(confirmedPairs...)
+	p.wsc.AddWebsocketConnection(
+		newSubscriptionMsgs,
+		p.messageReceived,
+		disabledPingDuration,
+		websocket.PingMessage,
+	)
+	p.setSubscribedPairs(confirmedPairs...)
+}
+
+// messageReceived handles the received data from the BingX websocket. All
+// market data sent by BingX is compressed with GZIP, which the websocket
+// connection decompresses before passing it on.
+func (p *BingxProvider) messageReceived(messageType int, conn *WebsocketConnection, bz []byte) {
+	if messageType != websocket.BinaryMessage {
+		return
+	}
+
+	var (
+		heartbeat  BingxHeartbeat
+		tickerResp BingxTickerResponse
+		tickerErr  error
+		candleResp BingxCandleResponse
+		candleErr  error
+	)
+
+	if err := json.Unmarshal(bz, &heartbeat); err == nil && heartbeat.Ping != "" {
+		p.pongReceived(conn, heartbeat)
+		return
+	}
+
+	tickerErr = json.Unmarshal(bz, &tickerResp)
+	if strings.HasSuffix(tickerResp.DataType, bingxTickerSuffix) && len(tickerResp.Data.LastPrice) != 0 {
+		p.setTickerPair(tickerResp.Data, tickerResp.Data.Symbol)
+		telemetryWebsocketMessage(ProviderBingx, MessageTypeTicker)
+		return
+	}
+
+	candleErr = json.Unmarshal(bz, &candleResp)
+	if strings.HasSuffix(candleResp.DataType, bingxCandleSuffix) && !candleResp.Data.Candle.Close.IsZero() {
+		p.setCandlePair(candleResp.Data.Candle, candleResp.Data.Symbol)
+		telemetryWebsocketMessage(ProviderBingx, MessageTypeCandle)
+		return
+	}
+
+	// subscription responses carry no data
+	if tickerErr == nil && tickerResp.DataType == "" {
+		return
+	}
+
+	p.logger.Error().
+		Int("length", len(bz)).
+		AnErr("ticker", tickerErr).
+		AnErr("candle", candleErr).
+		Msg("Error on receive message")
+	telemetryDecodeError(p.logger, ProviderBingx, bz)
+}
+
+// pongReceived answers a ping sent by the server. BingX closes connections
+// which do not answer its pings with a pong message carrying the same id,
+// e.g. {"ping":"2177c68e","time":"2023-06-28T17:33:55.188+0800"} is answered
+// by {"pong":"2177c68e","time":"2023-06-28T17:33:55.188+0800"}.
+func (p *BingxProvider) pongReceived(conn *WebsocketConnection, heartbeat BingxHeartbeat) {
+	if err := conn.SendJSON(BingxHeartbeat{
+		Pong: heartbeat.Ping,
+		Time: heartbeat.Time,
+	}); err != nil {
+		p.logger.Err(err).Msg("could not send pong message back")
+	}
+}
+
+func (ticker BingxTicker) toTickerPrice() (types.TickerPrice, error) {
+	return types.NewTickerPrice(ticker.LastPrice.String(), ticker.Volume.String())
+}
+
+func (candle BingxCandle) toCandlePrice() (types.CandlePrice, error) {
+	return types.NewCandlePrice(
+		candle.Close.String(),
+		candle.Volume.String(),
+		candle.TimeStamp,
+	)
+}
+
+// GetAvailablePairs returns all pairs to which the provider can subscribe.
+// ex.: map["ATOMUSDT" => {}, "OJOUSDC" => {}].
+func (p *BingxProvider) GetAvailablePairs() (map[string]struct{}, error) {
+	resp, err := p.endpoints.httpClient().Get(p.endpoints.Rest + bingxRestPath)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	var pairsSummary BingxPairsSummary
+	if err := json.NewDecoder(resp.Body).Decode(&pairsSummary); err != nil {
+		return nil, err
+	}
+
+	availablePairs := make(map[string]struct{}, len(pairsSummary.Data.Symbols))
+	for _, pair := range pairsSummary.Data.Symbols {
+		availablePairs[strings.ToUpper(strings.ReplaceAll(pair.Symbol, "-", ""))] = struct{}{}
+	}
+
+	return availablePairs, nil
+}
+
+// currencyPairToBingxPair receives a currency pair and returns the BingX
+// symbol ex.: BTC-USDT.
+func currencyPairToBingxPair(cp types.CurrencyPair) string {
+	return strings.ToUpper(cp.Base + "-" + cp.Quote)
+}
+
+// newBingxSubscriptionMsg returns a new topic subscription Msg.
+func newBingxSubscriptionMsg(dataType string) BingxSubscriptionMsg {
+	return BingxSubscriptionMsg{
+		ID:       dataType,
+		ReqType:  "sub",
+		DataType: dataType,
+	}
+}
@@ -0,0 +1,74 @@
+package provider
+
+import (
+	"encoding/json"
+	"strconv"
+	"testing"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/gorilla/websocket"
+	"github.com/ojo-network/price-feeder/oracle/types"
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBingxProvider_messageReceived(t *testing.T) {
+	p := &BingxProvider{
+		logger:     zerolog.Nop(),
+		priceStore: newPriceStore(zerolog.Nop()),
+	}
+	p.setCurrencyPairToTickerAndCandlePair(currencyPairToBingxPair)
+	p.setSubscribedPairs(ATOMUSDT)
+
+	ticker := `{"code":0,"dataType":"ATOM-USDT@ticker","data":{"e":"24hTicker","E":1687944835188,` +
+		`"s":"ATOM-USDT","p":"0.21","P":"1.85%","o":"11.31","h":"11.73","l":"11.21","c":"11.52",` +
+		`"v":"2396974.02","q":"27369412.11","O":1687858435188,"C":1687944835188}}`
+
+	// the websocket connection decompresses the frames before passing them on
+	p.messageReceived(websocket.BinaryMessage, nil, []byte(ticker))
+	prices, err := p.GetTickerPrices(ATOMUSDT)
+	require.NoError(t, err)
+	require.Len(t, prices, 1)
+	require.Equal(t, sdk.MustNewDecFromStr("11.52"), prices[ATOMUSDT].Price)
+	require.Equal(t, sdk.MustNewDecFromStr("2396974.02"), prices[ATOMUSDT].Volume)
+
+	candleTime := PastUnixTime(0)
+	candle := `{"code":0,"dataType":"ATOM-USDT@kline_1min","data":{"e":"kline","E":1687944835188,` +
+		`"s":"ATOM-USDT","K":{"t":1687944780000,"T":` + strconv.FormatInt(candleTime, 10) + `,"s":"ATOM-USDT",` +
+		`"i":"1min","o":11.52,"c":11.53,"h":11.54,"l":11.51,"v":182.47,"n":12,"q":2103.84}}}`
+	p.messageReceived(websocket.BinaryMessage, nil, []byte(candle))
+
+	candles, err := p.GetCandlePrices(ATOMUSDT)
+	require.NoError(t, err)
+	require.Len(t, candles[ATOMUSDT], 1)
+	require.Equal(t, sdk.MustNewDecFromStr("11.53"), candles[ATOMUSDT][0].Price)
+	require.Equal(t, sdk.MustNewDecFromStr("182.47"), candles[ATOMUSDT][0].Volume)
+	require.Equal(t, candleTime, candles[ATOMUSDT][0].TimeStamp)
+
+	// pings are answered and do not touch the prices
+	ping := `{"ping":"2177c68e4d0e45679965f482929b59c2","time":"2023-06-28T17:33:55.188+0800"}`
+	p.messageReceived(websocket.BinaryMessage, &WebsocketConnection{}, []byte(ping))
+	prices, err = p.GetTickerPrices(ATOMUSDT)
+	require.NoError(t, err)
+	require.Equal(t, sdk.MustNewDecFromStr("11.52"), prices[ATOMUSDT].Price)
+}
+
+func TestBingxCurrencyPairToBingxPair(t *testing.T) {
+	cp := types.CurrencyPair{Base: "ATOM", Quote: "USDT"}
+	bingxSymbol := currencyPairToBingxPair(cp)
+	require.Equal(t, bingxSymbol, "ATOM-USDT")
+}
+
+func TestBingxProvider_getSubscriptionMsgs(t *testing.T) {
+	provider := &BingxProvider{}
+	cps := []types.CurrencyPair{
+		{Base: "ATOM", Quote: "USDT"},
+	}
+	subMsgs := provider.getSubscriptionMsgs(cps...)
+
+	msg, _ := json.Marshal(subMsgs[0])
+	require.Equal(t, `{"id":"ATOM-USDT@ticker","reqType":"sub","dataType":"ATOM-USDT@ticker"}`, string(msg))
+
+	msg, _ = json.Marshal(subMsgs[1])
+	require.Equal(t, `{"id":"ATOM-USDT@kline_1min","reqType":"sub","dataType":"ATOM-USDT@kline_1min"}`, string(msg))
+}
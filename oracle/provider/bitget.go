@@ -4,7 +4,6 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
-	"net/http"
 	"net/url"
 	"strconv"
 	"strings"
@@ -74,9 +73,9 @@ type (
 		Data   []BitgetTickerData    `json:"data"`   // ticker data
 	}
 	BitgetTickerData struct {
-		InstID string `json:"instId"`     // e.x. BTCUSD
-		Price  string `json:"last"`       // last price e.x. "12.3907"
-		Volume string `json:"baseVolume"` // volume in base asset (e.x. "112247.9173")
+		InstID string       `json:"instId"`     // e.x. BTCUSD
+		Price  types.Number `json:"last"`       // last price e.x. "12.3907"
+		Volume types.Number `json:"baseVolume"` // volume in base asset (e.x. "112247.9173")
 	}
 
 	// BitgetCandleResponse is the response structure for the bitget ticker message.
@@ -114,9 +113,11 @@ func NewBitgetProvider(
 ) (*BitgetProvider, error) {
 	if endpoints.Name != ProviderBitget {
 		endpoints = Endpoint{
-			Name:      ProviderBitget,
-			Rest:      bitgetRestHost,
-			Websocket: bitgetWSHost,
+			Name:              ProviderBitget,
+			Rest:              bitgetRestHost,
+			Websocket:         bitgetWSHost,
+			SilenceTimeout:    endpoints.SilenceTimeout,
+			ReconnectCooldown: endpoints.ReconnectCooldown,
 		}
 	}
 
@@ -154,6 +155,11 @@ func NewBitgetProvider(
 		provider.messageReceived,
 		defaultPingDuration,
 		websocket.TextMessage,
+		endpoints.SilenceTimeout,
+		endpoints.ReconnectCooldown,
+		&provider.priceStore,
+		endpoints.tlsConfig,
+		endpoints.header,
 		bitgetLogger,
 	)
 	return provider, nil
@@ -255,6 +261,7 @@ func (p *BitgetProvider) messageReceived(_ int, _ *WebsocketConnection, bz []byt
 		AnErr("ticker", tickerErr).
 		AnErr("candle", candleErr).
 		Msg("Error on receive message")
+	telemetryDecodeError(p.logger, ProviderBitget, bz)
 }
 
 // ToBitgetCandle turns a BitgetCandleResponse into a more-readable
@@ -281,7 +288,7 @@ func (bcr BitgetCandleResponse) ToBitgetCandle() (BitgetCandle, error) {
 
 // GetAvailablePairs returns all pairs to which the provider can subscribe.
 func (p *BitgetProvider) GetAvailablePairs() (map[string]struct{}, error) {
-	resp, err := http.Get(p.endpoints.Rest + bitgetRestPath)
+	resp, err := p.endpoints.httpClient().Get(p.endpoints.Rest + bitgetRestPath)
 	if err != nil {
 		return nil, err
 	}
@@ -313,8 +320,8 @@ func (ticker BitgetTicker) toTickerPrice() (types.TickerPrice, error) {
 		return types.TickerPrice{}, fmt.Errorf("ticker has no data")
 	}
 	return types.NewTickerPrice(
-		ticker.Data[0].Price,
-		ticker.Data[0].Volume,
+		ticker.Data[0].Price.String(),
+		ticker.Data[0].Volume.String(),
 	)
 }
 
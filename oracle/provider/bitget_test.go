@@ -32,8 +32,8 @@ func TestBitgetProvider_GetTickerPrices(t *testing.T) {
 			Data: []BitgetTickerData{
 				{
 					InstID: instId,
-					Price:  lastPrice,
-					Volume: volume,
+					Price:  types.Number(lastPrice),
+					Volume: types.Number(volume),
 				},
 			},
 		}
@@ -62,8 +62,8 @@ func TestBitgetProvider_GetTickerPrices(t *testing.T) {
 			Data: []BitgetTickerData{
 				{
 					InstID: atomInstID,
-					Price:  atomLastPrice,
-					Volume: volume,
+					Price:  types.Number(atomLastPrice),
+					Volume: types.Number(volume),
 				},
 			},
 		}
@@ -75,8 +75,8 @@ func TestBitgetProvider_GetTickerPrices(t *testing.T) {
 			Data: []BitgetTickerData{
 				{
 					InstID: lunaInstID,
-					Price:  lunaLastPrice,
-					Volume: volume,
+					Price:  types.Number(lunaLastPrice),
+					Volume: types.Number(volume),
 				},
 			},
 		}
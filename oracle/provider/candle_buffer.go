@@ -0,0 +1,48 @@
+package provider
+
+import (
+	"sort"
+
+	"github.com/ojo-network/price-feeder/oracle/types"
+)
+
+// CandleBufferBounder is implemented by the providers which can bound the
+// number of candles buffered for each of their pairs, so that a burst of
+// candles, ex. with garbage timestamps, can't grow their memory unbounded.
+type CandleBufferBounder interface {
+	SetMaxBufferedCandles(providerName types.ProviderName, maxCandles int)
+}
+
+// SetMaxBufferedCandles makes the price store keep at most maxCandles candles
+// of each pair, dropping the oldest of them first and counting them for the
+// provider. A non-positive maxCandles leaves the candles unbounded.
+func (ps *priceStore) SetMaxBufferedCandles(providerName types.ProviderName, maxCandles int) {
+	ps.candleMtx.Lock()
+	defer ps.candleMtx.Unlock()
+
+	ps.candleBufferProvider = providerName
+	ps.maxBufferedCandles = maxCandles
+}
+
+// boundCandles returns the candles of the pair without the oldest of them past
+// the max buffered candles, logging and counting the dropped candles.
+//
+// Does not acquire lock - must be called from parent function
+func (ps *priceStore) boundCandles(candles []types.CandlePrice, currencyPair string) []types.CandlePrice {
+	if ps.maxBufferedCandles <= 0 || len(candles) <= ps.maxBufferedCandles {
+		return candles
+	}
+
+	sort.SliceStable(candles, func(i, j int) bool {
+		return candles[i].TimeStamp > candles[j].TimeStamp
+	})
+	dropped := len(candles) - ps.maxBufferedCandles
+
+	ps.logger.Debug().
+		Str("pair", currencyPair).
+		Int("dropped", dropped).
+		Int("max_buffered_candles", ps.maxBufferedCandles).
+		Msg("dropped the oldest candles of a full candle buffer")
+	telemetryCandlesDropped(ps.candleBufferProvider, currencyPair, dropped)
+	return candles[:ps.maxBufferedCandles]
+}
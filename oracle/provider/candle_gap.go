@@ -0,0 +1,74 @@
+package provider
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/ojo-network/price-feeder/oracle/types"
+)
+
+// CandleGapGuard is implemented by the providers which can reject candles
+// older than the newest candle of their pair by more than a gap, so that
+// out-of-order or garbage timestamps never reach the TVWAP.
+type CandleGapGuard interface {
+	SetMaxCandleGap(providerName types.ProviderName, gap time.Duration)
+}
+
+// CandleGap returns the max candle gap of the endpoint and whether it is set.
+// A zero gap rejects every out-of-order candle.
+func (e Endpoint) CandleGap() (time.Duration, bool, error) {
+	if e.MaxCandleGap == "" {
+		return 0, false, nil
+	}
+
+	gap, err := time.ParseDuration(e.MaxCandleGap)
+	if err != nil {
+		return 0, false, fmt.Errorf("max candle gap of %s must be a duration: %w", e.Name, err)
+	}
+	if gap < 0 {
+		return 0, false, fmt.Errorf("max candle gap of %s must not be negative", e.Name)
+	}
+	return gap, true, nil
+}
+
+// SetMaxCandleGap makes the price store reject the candles older than the
+// newest candle of their pair and interval by more than the gap, counting
+// them for the provider.
+func (ps *priceStore) SetMaxCandleGap(providerName types.ProviderName, gap time.Duration) {
+	ps.candleMtx.Lock()
+	defer ps.candleMtx.Unlock()
+
+	ps.candleGapProvider = providerName
+	ps.maxCandleGap = gap
+}
+
+// exceedsCandleGap returns true if the candle is older than the newest candle
+// of the pair with the same interval by more than the max candle gap, logging
+// and counting the rejection. It is always false without a max candle gap.
+//
+// Does not acquire lock - must be called from parent function
+func (ps *priceStore) exceedsCandleGap(candle types.CandlePrice, currencyPair string) bool {
+	if ps.candleGapProvider == "" {
+		return false
+	}
+
+	var newest int64
+	for _, c := range ps.candles[currencyPair] {
+		if c.Interval == candle.Interval && c.TimeStamp > newest {
+			newest = c.TimeStamp
+		}
+	}
+	gap := time.Duration(newest-candle.TimeStamp) * time.Millisecond
+	if gap <= ps.maxCandleGap {
+		return false
+	}
+
+	ps.logger.Warn().
+		Str("pair", currencyPair).
+		Int64("timestamp", candle.TimeStamp).
+		Int64("newest_timestamp", newest).
+		Dur("max_candle_gap", ps.maxCandleGap).
+		Msg("rejected candle older than the newest candle by more than the max gap")
+	telemetryCandleRejected(ps.candleGapProvider, currencyPair)
+	return true
+}
@@ -0,0 +1,21 @@
+package provider
+
+import "time"
+
+// CandleRetainer is implemented by the providers which can keep the candles
+// of their pairs for longer than their default candle period, ex. to cover
+// the TWAP window of their pairs.
+type CandleRetainer interface {
+	RetainCandles(period time.Duration)
+}
+
+// RetainCandles makes the price store keep the candles for at least the
+// period. A period shorter than the candle period of the store is ignored.
+func (ps *priceStore) RetainCandles(period time.Duration) {
+	ps.candleMtx.Lock()
+	defer ps.candleMtx.Unlock()
+
+	if period > ps.candlePeriod {
+		ps.candlePeriod = period
+	}
+}
@@ -0,0 +1,358 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"net/http"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/ojo-network/price-feeder/oracle/types"
+	"github.com/rs/zerolog"
+)
+
+const (
+	chainlinkRestHost          = "https://ethereum-rpc.publicnode.com"
+	chainlinkPricePollInterval = 15 * time.Second
+	chainlinkMaxRoundAge       = time.Hour
+	chainlinkMaxDecimals       = 36
+
+	// chainlinkLatestRoundData and chainlinkDecimals are the selectors of the
+	// latestRoundData() and decimals() functions of an aggregator.
+	chainlinkLatestRoundData = "0xfeaf968c"
+	chainlinkDecimals        = "0x313ce567"
+)
+
+var (
+	_ Provider = (*ChainlinkProvider)(nil)
+
+	chainlinkAddressRegex = regexp.MustCompile(`^0x[0-9a-fA-F]{40}$`)
+)
+
+type (
+	// ChainlinkProvider defines an Oracle provider which polls the latest
+	// round of Chainlink aggregator contracts through an EVM JSON-RPC
+	// endpoint. Each pair is mapped to its aggregator by the contract address
+	// set as its pair address. Rounds last updated longer ago than the
+	// pair's max round age are stale and the pair stops contributing prices
+	// until a fresh round is read. Aggregators carry no traded volume, so
+	// prices are stored as candles and tickers without volume.
+	//
+	// REF: https://docs.chain.link/data-feeds/api-reference
+	ChainlinkProvider struct {
+		ctx       context.Context
+		logger    zerolog.Logger
+		mtx       sync.RWMutex
+		endpoints Endpoint
+		client    *http.Client
+
+		// aggregators holds the configured aggregator address of each pair
+		// and decimals the decimals of each aggregator once queried.
+		aggregators map[string]string
+		decimals    map[string]uint8
+
+		priceStore
+	}
+
+	// chainlinkRound defines the answer of an aggregator's round and the
+	// time it was last updated.
+	chainlinkRound struct {
+		answer    *big.Int
+		updatedAt time.Time
+	}
+
+	// chainlinkPrice defines the price of a round at the time it was polled.
+	chainlinkPrice struct {
+		price     sdk.Dec
+		timeStamp int64
+	}
+)
+
+func NewChainlinkProvider(
+	ctx context.Context,
+	logger zerolog.Logger,
+	endpoints Endpoint,
+	pairs ...types.CurrencyPair,
+) (*ChainlinkProvider, error) {
+	if endpoints.Name != ProviderChainlink {
+		endpoints = Endpoint{
+			Name: ProviderChainlink,
+			Rest: chainlinkRestHost,
+		}
+	}
+
+	chainlinkLogger := logger.With().Str("provider", string(ProviderChainlink)).Logger()
+
+	provider := &ChainlinkProvider{
+		ctx:         ctx,
+		logger:      chainlinkLogger,
+		endpoints:   endpoints,
+		client:      endpoints.httpClient(),
+		aggregators: map[string]string{},
+		decimals:    map[string]uint8{},
+		priceStore:  newPriceStore(chainlinkLogger),
+	}
+	provider.setCurrencyPairToTickerAndCandlePair(currencyPairToChainlinkPair)
+	provider.setAggregators(pairs...)
+
+	confirmedPairs, err := ConfirmPairAvailability(
+		provider,
+		provider.endpoints.Name,
+		provider.logger,
+		pairs...,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	provider.setSubscribedPairs(confirmedPairs...)
+
+	return provider, nil
+}
+
+// StartConnections starts polling the rounds of the subscribed aggregators
+// until the provider's context is canceled.
+func (p *ChainlinkProvider) StartConnections() {
+	go func() {
+		ticker := time.NewTicker(chainlinkPricePollInterval)
+		defer ticker.Stop()
+
+		for {
+			p.pollPrices()
+
+			select {
+			case <-p.ctx.Done():
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+}
+
+// SubscribeCurrencyPairs confirms the aggregators of the new currency pairs
+// and adds them to the providers subscribedPairs array
+func (p *ChainlinkProvider) SubscribeCurrencyPairs(cps ...types.CurrencyPair) {
+	p.mtx.Lock()
+	defer p.mtx.Unlock()
+
+	p.setAggregators(cps...)
+	confirmedPairs, err := ConfirmPairAvailability(
+		p,
+		p.endpoints.Name,
+		p.logger,
+		cps...,
+	)
+	if err != nil {
+		return
+	}
+
+	p.setSubscribedPairs(confirmedPairs...)
+}
+
+// pollPrices reads the latest round of every subscribed pair. The pairs
+// whose round failed to be read or is stale stop contributing prices until a
+// fresh round is read again.
+func (p *ChainlinkProvider) pollPrices() {
+	p.mtx.Lock()
+	defer p.mtx.Unlock()
+
+	p.subscribedPairsMtx.RLock()
+	pairs := types.MapPairsToSlice(p.subscribedPairs)
+	p.subscribedPairsMtx.RUnlock()
+
+	for _, cp := range pairs {
+		symbol := currencyPairToChainlinkPair(cp)
+		price, err := p.queryPrice(cp)
+		if err != nil {
+			p.disablePair(cp, err)
+			continue
+		}
+		p.setTickerPair(price, symbol)
+		p.setCandlePair(price, symbol)
+	}
+}
+
+// disablePair removes the ticker and candles of a pair, so it stops
+// contributing prices.
+func (p *ChainlinkProvider) disablePair(cp types.CurrencyPair, err error) {
+	symbol := currencyPairToChainlinkPair(cp)
+
+	p.tickerMtx.Lock()
+	delete(p.tickers, symbol)
+	p.tickerMtx.Unlock()
+
+	p.candleMtx.Lock()
+	delete(p.candles, symbol)
+	p.candleMtx.Unlock()
+
+	TelemetryFailure(ProviderChainlink, MessageTypeTicker)
+	p.logger.Error().
+		Err(err).
+		Str("pair", cp.String()).
+		Msg("failed to read round; disabling pair until the next fresh round")
+}
+
+// queryPrice reads the latest round of a pair's aggregator and converts its
+// answer to a price, returning an error if the round is stale.
+func (p *ChainlinkProvider) queryPrice(cp types.CurrencyPair) (chainlinkPrice, error) {
+	symbol := currencyPairToChainlinkPair(cp)
+	aggregator := p.aggregators[symbol]
+
+	decimals, ok := p.decimals[aggregator]
+	if !ok {
+		var err error
+		decimals, err = p.queryDecimals(aggregator)
+		if err != nil {
+			return chainlinkPrice{}, err
+		}
+		p.decimals[aggregator] = decimals
+	}
+
+	result, err := p.call(aggregator, chainlinkLatestRoundData)
+	if err != nil {
+		return chainlinkPrice{}, err
+	}
+	round, err := decodeChainlinkRound(result)
+	if err != nil {
+		return chainlinkPrice{}, err
+	}
+
+	if age := p.now().Sub(round.updatedAt); age > p.maxRoundAge(cp) {
+		return chainlinkPrice{}, fmt.Errorf("chainlink: stale round updated %s ago", age.Truncate(time.Second))
+	}
+
+	return newChainlinkPrice(round.answer, decimals)
+}
+
+// maxRoundAge returns the age past which a round of the pair is stale.
+func (p *ChainlinkProvider) maxRoundAge(cp types.CurrencyPair) time.Duration {
+	if maxAge, ok := p.endpoints.MaxRoundAges[cp.String()]; ok && maxAge > 0 {
+		return maxAge
+	}
+	return chainlinkMaxRoundAge
+}
+
+// queryDecimals returns the decimals of an aggregator's answers.
+func (p *ChainlinkProvider) queryDecimals(aggregator string) (uint8, error) {
+	result, err := p.call(aggregator, chainlinkDecimals)
+	if err != nil {
+		return 0, err
+	}
+	if len(result) != evmWordSize {
+		return 0, fmt.Errorf("chainlink: unexpected decimals of %d bytes", len(result))
+	}
+	decimals := new(big.Int).SetBytes(result)
+	if !decimals.IsUint64() || decimals.Uint64() > chainlinkMaxDecimals {
+		return 0, fmt.Errorf("chainlink: unsupported decimals %s", decimals)
+	}
+	return uint8(decimals.Uint64()), nil
+}
+
+// call executes an eth_call of the given data on a contract at the latest
+// block and returns the decoded result.
+func (p *ChainlinkProvider) call(to string, data string) ([]byte, error) {
+	return evmCall(p.ctx, p.client, p.endpoints.Rest, ProviderChainlink, to, data)
+}
+
+// GetAvailablePairs returns all pairs to which the provider can subscribe,
+// being every pair whose aggregator answers its decimals.
+// ex.: map["ETHUSD" => {}, "LINKUSD" => {}].
+func (p *ChainlinkProvider) GetAvailablePairs() (map[string]struct{}, error) {
+	availablePairs := make(map[string]struct{}, len(p.aggregators))
+	for symbol, aggregator := range p.aggregators {
+		decimals, err := p.queryDecimals(aggregator)
+		if err != nil {
+			p.logger.Warn().
+				Err(err).
+				Str("aggregator", aggregator).
+				Msg("failed to query aggregator")
+			continue
+		}
+		p.decimals[aggregator] = decimals
+		availablePairs[symbol] = struct{}{}
+	}
+
+	return availablePairs, nil
+}
+
+// setAggregators stores the aggregator address set as the address of each
+// pair.
+func (p *ChainlinkProvider) setAggregators(cps ...types.CurrencyPair) {
+	for _, cp := range cps {
+		if !chainlinkAddressRegex.MatchString(cp.Address) {
+			p.logger.Error().
+				Str("pair", cp.String()).
+				Msg("chainlink pairs must set the address of their aggregator contract")
+			continue
+		}
+		p.aggregators[currencyPairToChainlinkPair(cp)] = cp.Address
+	}
+}
+
+func (cp chainlinkPrice) toTickerPrice() (types.TickerPrice, error) {
+	return types.TickerPrice{
+		Price:  cp.price,
+		Volume: sdk.ZeroDec(),
+	}, nil
+}
+
+func (cp chainlinkPrice) toCandlePrice() (types.CandlePrice, error) {
+	return types.CandlePrice{
+		Price:     cp.price,
+		Volume:    sdk.ZeroDec(),
+		TimeStamp: cp.timeStamp,
+	}, nil
+}
+
+// decodeChainlinkRound decodes the ABI encoded result of latestRoundData,
+// being the roundId, answer, startedAt, updatedAt and answeredInRound words.
+func decodeChainlinkRound(result []byte) (chainlinkRound, error) {
+	if len(result) != 5*evmWordSize {
+		return chainlinkRound{}, fmt.Errorf("chainlink: unexpected round of %d bytes", len(result))
+	}
+	word := func(i int) []byte {
+		return result[i*evmWordSize : (i+1)*evmWordSize]
+	}
+
+	// the answer is a signed int256, and prices are never negative
+	answer := word(1)
+	if answer[0]&0x80 != 0 {
+		return chainlinkRound{}, fmt.Errorf("chainlink: negative answer")
+	}
+
+	updatedAt := new(big.Int).SetBytes(word(3))
+	if !updatedAt.IsInt64() || updatedAt.Sign() == 0 {
+		return chainlinkRound{}, fmt.Errorf("chainlink: incomplete round")
+	}
+
+	return chainlinkRound{
+		answer:    new(big.Int).SetBytes(answer),
+		updatedAt: time.Unix(updatedAt.Int64(), 0),
+	}, nil
+}
+
+// newChainlinkPrice converts the answer of a round to a price.
+func newChainlinkPrice(answer *big.Int, decimals uint8) (chainlinkPrice, error) {
+	scale := new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(decimals)), nil)
+	price, err := types.ParseDec(new(big.Rat).SetFrac(answer, scale).FloatString(sdk.Precision))
+	if err != nil {
+		return chainlinkPrice{}, fmt.Errorf("chainlink: failed to parse answer: %w", err)
+	}
+	if !price.IsPositive() {
+		return chainlinkPrice{}, fmt.Errorf("chainlink: no price available")
+	}
+	return chainlinkPrice{
+		price:     price,
+		timeStamp: PastUnixTime(0),
+	}, nil
+}
+
+// currencyPairToChainlinkPair receives a currency pair and return the symbol
+// the provider stores its prices by, ex.: ETHUSD.
+func currencyPairToChainlinkPair(cp types.CurrencyPair) string {
+	return strings.ToUpper(cp.String())
+}
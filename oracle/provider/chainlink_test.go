@@ -0,0 +1,186 @@
+package provider
+
+import (
+	"context"
+	"encoding/hex"
+	"encoding/json"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/ojo-network/price-feeder/oracle/types"
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	chainlinkTestETHAggregator  = "0x5f4eC3Df9cbd43714FE2740f5E3616155c5b8419"
+	chainlinkTestLINKAggregator = "0x2c1d072e956AFFC0D435Cb7AC38EF18d24d9127c"
+)
+
+// chainlinkTestRound defines the latest round of a test aggregator.
+type chainlinkTestRound struct {
+	decimals  int64
+	answer    int64
+	updatedAt time.Time
+}
+
+type chainlinkTestServer struct {
+	*httptest.Server
+
+	mtx    sync.Mutex
+	rounds map[string]chainlinkTestRound
+}
+
+func newChainlinkTestServer(t *testing.T) *chainlinkTestServer {
+	ts := &chainlinkTestServer{
+		rounds: map[string]chainlinkTestRound{
+			strings.ToLower(chainlinkTestETHAggregator):  {decimals: 8, answer: 345612345678, updatedAt: time.Now()},
+			strings.ToLower(chainlinkTestLINKAggregator): {decimals: 12, answer: 14250000000000, updatedAt: time.Now()},
+		},
+	}
+	ts.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ts.mtx.Lock()
+		defer ts.mtx.Unlock()
+
+		var req struct {
+			Method string            `json:"method"`
+			Params []json.RawMessage `json:"params"`
+		}
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+		require.Equal(t, "eth_call", req.Method)
+
+		var call evmCallParams
+		require.NoError(t, json.Unmarshal(req.Params[0], &call))
+
+		round, ok := ts.rounds[strings.ToLower(call.To)]
+		if !ok {
+			_, _ = w.Write([]byte(`{"jsonrpc":"2.0","id":1,"error":{"code":-32000,"message":"execution reverted"}}`))
+			return
+		}
+
+		var words []*big.Int
+		switch call.Data {
+		case chainlinkDecimals:
+			words = []*big.Int{big.NewInt(round.decimals)}
+		case chainlinkLatestRoundData:
+			words = []*big.Int{
+				big.NewInt(1),
+				big.NewInt(round.answer),
+				big.NewInt(round.updatedAt.Unix()),
+				big.NewInt(round.updatedAt.Unix()),
+				big.NewInt(1),
+			}
+		}
+		result := make([]byte, 0, len(words)*evmWordSize)
+		for _, word := range words {
+			result = append(result, word.FillBytes(make([]byte, evmWordSize))...)
+		}
+		_, _ = w.Write([]byte(`{"jsonrpc":"2.0","id":1,"result":"0x` + hex.EncodeToString(result) + `"}`))
+	}))
+	t.Cleanup(ts.Close)
+
+	return ts
+}
+
+func TestChainlinkProvider_GetTickerPrices(t *testing.T) {
+	server := newChainlinkTestServer(t)
+
+	ethusd := types.CurrencyPair{Base: "ETH", Quote: "USD", Address: chainlinkTestETHAggregator}
+	linkusd := types.CurrencyPair{Base: "LINK", Quote: "USD", Address: chainlinkTestLINKAggregator}
+	p, err := NewChainlinkProvider(
+		context.TODO(),
+		zerolog.Nop(),
+		Endpoint{
+			Name:         ProviderChainlink,
+			Rest:         server.URL,
+			MaxRoundAges: map[string]time.Duration{"LINKUSD": 24 * time.Hour},
+		},
+		ethusd,
+		linkusd,
+		types.CurrencyPair{Base: "FOO", Quote: "USD", Address: "0x0000000000000000000000000000000000000001"},
+		types.CurrencyPair{Base: "BAR", Quote: "USD", Address: "bar"},
+	)
+	require.NoError(t, err)
+	require.Len(t, p.subscribedPairs, 2)
+
+	t.Run("valid_request_multi_ticker", func(t *testing.T) {
+		p.pollPrices()
+
+		prices, err := p.GetTickerPrices(ethusd, linkusd)
+		require.NoError(t, err)
+		require.Len(t, prices, 2)
+		require.Equal(t, sdk.MustNewDecFromStr("3456.12345678"), prices[ethusd].Price)
+		require.Equal(t, sdk.ZeroDec(), prices[ethusd].Volume)
+		require.Equal(t, sdk.MustNewDecFromStr("14.25"), prices[linkusd].Price)
+
+		candles, err := p.GetCandlePrices(ethusd, linkusd)
+		require.NoError(t, err)
+		require.Len(t, candles[linkusd], 1)
+		require.Equal(t, sdk.MustNewDecFromStr("14.25"), candles[linkusd][0].Price)
+	})
+
+	t.Run("stale_round_disables_pair", func(t *testing.T) {
+		// a round of two hours ago is stale at the default max round age,
+		// but not at the LINK max round age
+		server.mtx.Lock()
+		for aggregator, round := range server.rounds {
+			round.updatedAt = time.Now().Add(-2 * time.Hour)
+			server.rounds[aggregator] = round
+		}
+		server.mtx.Unlock()
+
+		p.pollPrices()
+
+		prices, err := p.GetTickerPrices(ethusd, linkusd)
+		require.NoError(t, err)
+		require.Len(t, prices, 1)
+		require.Contains(t, prices, linkusd)
+
+		server.mtx.Lock()
+		round := server.rounds[strings.ToLower(chainlinkTestETHAggregator)]
+		round.updatedAt = time.Now()
+		server.rounds[strings.ToLower(chainlinkTestETHAggregator)] = round
+		server.mtx.Unlock()
+
+		p.pollPrices()
+
+		prices, err = p.GetTickerPrices(ethusd, linkusd)
+		require.NoError(t, err)
+		require.Len(t, prices, 2)
+	})
+}
+
+func TestDecodeChainlinkRound(t *testing.T) {
+	word := func(b byte) []byte {
+		w := make([]byte, evmWordSize)
+		w[0] = b
+		w[evmWordSize-1] = 1
+		return w
+	}
+
+	result := append(append(append(append(word(0), word(0)...), word(0)...), word(0)...), word(0)...)
+	round, err := decodeChainlinkRound(result)
+	require.NoError(t, err)
+	require.Equal(t, big.NewInt(1), round.answer)
+	require.Equal(t, time.Unix(1, 0), round.updatedAt)
+
+	// negative answer
+	negative := append(append(append(append(word(0), word(0xff)...), word(0)...), word(0)...), word(0)...)
+	_, err = decodeChainlinkRound(negative)
+	require.Error(t, err)
+
+	// incomplete round
+	incomplete := append([]byte{}, result...)
+	incomplete[4*evmWordSize-1] = 0
+	_, err = decodeChainlinkRound(incomplete)
+	require.Error(t, err)
+
+	_, err = decodeChainlinkRound(result[:4*evmWordSize])
+	require.Error(t, err)
+}
@@ -0,0 +1,76 @@
+package provider
+
+import "time"
+
+// clock is the default clock of the stateless helpers, ex. PastUnixTime, and
+// of the providers and the oracle which are not given a clock of their own. It
+// can be overridden with SetClock, ex. to run the price-feeder
+// deterministically.
+var clock Clock = SystemClock{}
+
+type (
+	// Clock defines the source of the current time.
+	Clock interface {
+		Now() time.Time
+	}
+
+	// ClockedProvider is implemented by the providers which can be given the
+	// clock they timestamp the market data they receive with.
+	ClockedProvider interface {
+		SetClock(Clock)
+	}
+
+	// SystemClock is a Clock which tells the system time.
+	SystemClock struct{}
+
+	// FixedClock is a Clock which always tells the same time.
+	FixedClock time.Time
+)
+
+// Now returns the system time.
+func (SystemClock) Now() time.Time {
+	return time.Now()
+}
+
+// Now returns the fixed time of the clock.
+func (c FixedClock) Now() time.Time {
+	return time.Time(c)
+}
+
+// SetClock sets the default clock used to timestamp and aggregate prices.
+func SetClock(c Clock) {
+	clock = c
+}
+
+// Now returns the current time of the default clock used to timestamp and
+// aggregate prices.
+func Now() time.Time {
+	return clock.Now()
+}
+
+// SetClock sets the clock the provider timestamps the market data it receives
+// with, instead of the default clock.
+func (ps *priceStore) SetClock(c Clock) {
+	ps.clockMtx.Lock()
+	defer ps.clockMtx.Unlock()
+
+	ps.clock = c
+}
+
+// now returns the current time of the clock of the provider, or of the default
+// clock if it has none.
+func (ps *priceStore) now() time.Time {
+	ps.clockMtx.RLock()
+	defer ps.clockMtx.RUnlock()
+
+	if ps.clock == nil {
+		return Now()
+	}
+	return ps.clock.Now()
+}
+
+// pastUnixTime returns a millisecond timestamp that represents the time of the
+// clock of the provider minus t.
+func (ps *priceStore) pastUnixTime(t time.Duration) int64 {
+	return ps.now().Add(t*-1).Unix() * int64(time.Second/time.Millisecond)
+}
@@ -3,7 +3,6 @@ package provider
 import (
 	"context"
 	"encoding/json"
-	"net/http"
 	"net/url"
 	"strings"
 	"sync"
@@ -50,11 +49,11 @@ type (
 
 	// CoinbaseMatchResponse defines the response body for coinbase trades.
 	CoinbaseTradeResponse struct {
-		Type      string `json:"type"`       // "last_match" or "match"
-		ProductID string `json:"product_id"` // ex.: ATOM-USDT
-		Time      string `json:"time"`       // Time in format 2006-01-02T15:04:05.000000Z
-		Size      string `json:"size"`       // Size of the trade ex.: 10.41
-		Price     string `json:"price"`      // ex.: 14.02
+		Type      string       `json:"type"`       // "last_match" or "match"
+		ProductID string       `json:"product_id"` // ex.: ATOM-USDT
+		Time      string       `json:"time"`       // Time in format 2006-01-02T15:04:05.000000Z
+		Size      types.Number `json:"size"`       // Size of the trade ex.: 10.41
+		Price     types.Number `json:"price"`      // ex.: 14.02
 	}
 
 	// CoinbaseTrade defines the trade info we'd like to save.
@@ -67,9 +66,9 @@ type (
 
 	// CoinbaseTicker defines the ticker info we'd like to save.
 	CoinbaseTicker struct {
-		ProductID string `json:"product_id"` // ex.: ATOM-USDT
-		Price     string `json:"price"`      // ex.: 523.0
-		Volume    string `json:"volume_24h"` // 24-hour volume
+		ProductID string       `json:"product_id"` // ex.: ATOM-USDT
+		Price     types.Number `json:"price"`      // ex.: 523.0
+		Volume    types.Number `json:"volume_24h"` // 24-hour volume
 	}
 
 	// CoinbaseErrResponse defines the response body for errors.
@@ -94,9 +93,11 @@ func NewCoinbaseProvider(
 ) (*CoinbaseProvider, error) {
 	if endpoints.Name != ProviderCoinbase {
 		endpoints = Endpoint{
-			Name:      ProviderCoinbase,
-			Rest:      coinbaseRestHost,
-			Websocket: coinbaseWSHost,
+			Name:              ProviderCoinbase,
+			Rest:              coinbaseRestHost,
+			Websocket:         coinbaseWSHost,
+			SilenceTimeout:    endpoints.SilenceTimeout,
+			ReconnectCooldown: endpoints.ReconnectCooldown,
 		}
 	}
 	wsURL := url.URL{
@@ -134,6 +135,11 @@ func NewCoinbaseProvider(
 		provider.messageReceived,
 		defaultPingDuration,
 		websocket.PingMessage,
+		endpoints.SilenceTimeout,
+		endpoints.ReconnectCooldown,
+		&provider.priceStore,
+		endpoints.tlsConfig,
+		endpoints.header,
 		coinbaseLogger,
 	)
 
@@ -194,7 +200,7 @@ func (p *CoinbaseProvider) SubscribeCurrencyPairs(cps ...types.CurrencyPair) {
 
 // GetAvailablePairs returns all pairs to which the provider can subscribe.
 func (p *CoinbaseProvider) GetAvailablePairs() (map[string]struct{}, error) {
-	resp, err := http.Get(p.endpoints.Rest + coinbaseRestPath)
+	resp, err := p.endpoints.httpClient().Get(p.endpoints.Rest + coinbaseRestPath)
 	if err != nil {
 		return nil, err
 	}
@@ -221,6 +227,7 @@ func (p *CoinbaseProvider) messageReceived(_ int, _ *WebsocketConnection, bz []b
 	var coinbaseTrade CoinbaseTradeResponse
 	if err := json.Unmarshal(bz, &coinbaseTrade); err != nil {
 		p.logger.Error().Err(err).Msg("unable to unmarshal response")
+		telemetryDecodeError(p.logger, ProviderCoinbase, bz)
 		return
 	}
 
@@ -241,6 +248,7 @@ func (p *CoinbaseProvider) messageReceived(_ int, _ *WebsocketConnection, bz []b
 		var coinbaseTicker CoinbaseTicker
 		if err := json.Unmarshal(bz, &coinbaseTicker); err != nil {
 			p.logger.Error().Err(err).Msg("unable to unmarshal response")
+			telemetryDecodeError(p.logger, ProviderCoinbase, bz)
 			return
 		}
 
@@ -265,8 +273,8 @@ func (tr CoinbaseTradeResponse) timeToUnix() int64 {
 func (tr CoinbaseTradeResponse) toTrade() types.Trade {
 	return types.Trade{
 		Time:  tr.timeToUnix(),
-		Price: tr.Price,
-		Size:  tr.Size,
+		Price: tr.Price.String(),
+		Size:  tr.Size.String(),
 	}
 }
 
@@ -277,8 +285,8 @@ func (p *CoinbaseProvider) setTradePair(tradeResponse CoinbaseTradeResponse) {
 
 func (ticker CoinbaseTicker) toTickerPrice() (types.TickerPrice, error) {
 	return types.NewTickerPrice(
-		ticker.Price,
-		ticker.Volume,
+		ticker.Price.String(),
+		ticker.Volume.String(),
 	)
 }
 
@@ -26,8 +26,8 @@ func TestCoinbaseProvider_GetTickerPrices(t *testing.T) {
 
 		tickerMap := map[string]CoinbaseTicker{}
 		tickerMap["ATOM-USDT"] = CoinbaseTicker{
-			Price:  lastPrice,
-			Volume: volume,
+			Price:  types.Number(lastPrice),
+			Volume: types.Number(volume),
 		}
 
 		for pair, ticker := range tickerMap {
@@ -48,13 +48,13 @@ func TestCoinbaseProvider_GetTickerPrices(t *testing.T) {
 
 		tickerMap := map[string]CoinbaseTicker{}
 		tickerMap["ATOM-USDT"] = CoinbaseTicker{
-			Price:  lastPriceAtom,
-			Volume: volume,
+			Price:  types.Number(lastPriceAtom),
+			Volume: types.Number(volume),
 		}
 
 		tickerMap["OJO-USDT"] = CoinbaseTicker{
-			Price:  lastPriceOjo,
-			Volume: volume,
+			Price:  types.Number(lastPriceOjo),
+			Volume: types.Number(volume),
 		}
 
 		for pair, ticker := range tickerMap {
@@ -0,0 +1,499 @@
+package provider
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/gorilla/websocket"
+	"github.com/rs/zerolog"
+
+	"github.com/ojo-network/price-feeder/oracle/types"
+)
+
+const (
+	coincheckWSHost   = "ws-api.coincheck.com"
+	coincheckRestHost = "https://coincheck.com"
+	coincheckRestPath = "/api/exchange_status"
+
+	coincheckTradesChannel    = "trades"
+	coincheckOrderBookChannel = "orderbook"
+
+	// coincheckVolumePeriod is the period the traded volume of the ticker is
+	// summed over, as the websocket carries trades but no 24h volume.
+	coincheckVolumePeriod = 24 * time.Hour
+)
+
+var _ Provider = (*CoincheckProvider)(nil)
+
+type (
+	// CoincheckProvider defines an Oracle provider implemented by the
+	// Coincheck public API. Its websocket carries trades and order book
+	// updates but no tickers, so the ticker of a pair is the last trade with
+	// the volume traded since the provider started, up to 24h, and candles
+	// are built from the trades. Pairs with a best quote price source are
+	// priced from the order book instead.
+	//
+	// REF: https://coincheck.com/documents/exchange/api#websocket
+	CoincheckProvider struct {
+		wsc       *WebsocketController
+		logger    zerolog.Logger
+		mtx       sync.RWMutex
+		endpoints Endpoint
+
+		// priceSources holds the pairs priced by their best quote instead of
+		// the last trade, keyed by Coincheck symbol.
+		priceSources map[string]PriceSource
+
+		// marketsMtx guards markets, the trades and order book of each symbol.
+		marketsMtx sync.Mutex
+		markets    map[string]*coincheckMarket
+
+		priceStore
+	}
+
+	// coincheckMarket defines the state of a Coincheck symbol built from its
+	// trades and order book updates.
+	coincheckMarket struct {
+		last    types.Number        // price of the last trade
+		volumes []types.CandlePrice // traded volume per minute
+		bids    map[string]sdk.Dec  // amount per bid price
+		asks    map[string]sdk.Dec  // amount per ask price
+	}
+
+	// CoincheckTrade defines a trade of the trades channel, sent as an array
+	// ex.: ["1663318663","2357062","btc_jpy","2820896.0","5.0","sell","1193401","2078767"].
+	CoincheckTrade struct {
+		Time   int64        // Time in unix epoch ms ex.: 1663318663000
+		Symbol string       // Symbol ex.: btc_jpy
+		Rate   types.Number // Price ex.: 2820896.0
+		Amount types.Number // Traded base asset amount ex.: 5.0
+	}
+
+	// CoincheckOrderBook defines the order book update of a symbol, sent as
+	// ["btc_jpy", {"bids": [...], "asks": [...]}]. Each level is a price and
+	// the amount at that price, removed if zero.
+	CoincheckOrderBook struct {
+		Bids         [][]types.Number `json:"bids"`           // ex.: [["148634.0","0.0574"]]
+		Asks         [][]types.Number `json:"asks"`           // ex.: [["148834.0","0"]]
+		LastUpdateAt string           `json:"last_update_at"` // ex.: 1659321701
+	}
+
+	// CoincheckTicker defines the ticker of a symbol derived from its trades
+	// or order book.
+	CoincheckTicker struct {
+		Price  types.Number
+		Volume sdk.Dec
+	}
+
+	// CoincheckSubscriptionMsg Msg to subscribe to a channel of a symbol.
+	CoincheckSubscriptionMsg struct {
+		Type    string `json:"type"`    // subscribe
+		Channel string `json:"channel"` // ex.: btc_jpy-trades
+	}
+
+	// CoincheckPairsSummary defines the response structure for the Coincheck
+	// available pairs.
+	CoincheckPairsSummary struct {
+		ExchangeStatus []struct {
+			Pair   string `json:"pair"`   // ex.: btc_jpy
+			Status string `json:"status"` // ex.: available
+		} `json:"exchange_status"`
+	}
+)
+
+func NewCoincheckProvider(
+	ctx context.Context,
+	logger zerolog.Logger,
+	endpoints Endpoint,
+	pairs ...types.CurrencyPair,
+) (*CoincheckProvider, error) {
+	if endpoints.Name != ProviderCoincheck {
+		endpoints = Endpoint{
+			Name:              ProviderCoincheck,
+			Rest:              coincheckRestHost,
+			Websocket:         coincheckWSHost,
+			SilenceTimeout:    endpoints.SilenceTimeout,
+			ReconnectCooldown: endpoints.ReconnectCooldown,
+		}
+	}
+
+	wsURL := url.URL{
+		Scheme: "wss",
+		Host:   endpoints.Websocket,
+	}
+
+	coincheckLogger := logger.With().Str("provider", string(ProviderCoincheck)).Logger()
+
+	provider := &CoincheckProvider{
+		logger:       coincheckLogger,
+		endpoints:    endpoints,
+		priceSources: endpoints.pairPriceSources(currencyPairToCoincheckPair),
+		markets:      make(map[string]*coincheckMarket),
+		priceStore:   newPriceStore(coincheckLogger),
+	}
+	provider.setCurrencyPairToTickerAndCandlePair(currencyPairToCoincheckPair)
+
+	confirmedPairs, err := ConfirmPairAvailability(
+		provider,
+		provider.endpoints.Name,
+		provider.logger,
+		pairs...,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	provider.setSubscribedPairs(confirmedPairs...)
+
+	provider.wsc = NewWebsocketController(
+		ctx,
+		endpoints.Name,
+		wsURL,
+		provider.getSubscriptionMsgs(confirmedPairs...),
+		provider.messageReceived,
+		defaultPingDuration,
+		websocket.PingMessage,
+		endpoints.SilenceTimeout,
+		endpoints.ReconnectCooldown,
+		&provider.priceStore,
+		endpoints.tlsConfig,
+		endpoints.header,
+		coincheckLogger,
+	)
+
+	return provider, nil
+}
+
+func (p *CoincheckProvider) StartConnections() {
+	p.wsc.StartConnections()
+}
+
+// getSubscriptionMsgs returns a subscription message to the trades channel of
+// each pair, and to the order book channel of the pairs priced by their best
+// quote.
+func (p *CoincheckProvider) getSubscriptionMsgs(cps ...types.CurrencyPair) []interface{} {
+	subscriptionMsgs := make([]interface{}, 0, len(cps))
+	for _, cp := range cps {
+		symbol := currencyPairToCoincheckPair(cp)
+		subscriptionMsgs = append(subscriptionMsgs, newCoincheckSubscriptionMsg(symbol, coincheckTradesChannel))
+		if _, ok := p.priceSources[symbol]; ok {
+			subscriptionMsgs = append(subscriptionMsgs, newCoincheckSubscriptionMsg(symbol, coincheckOrderBookChannel))
+		}
+	}
+	return subscriptionMsgs
+}
+
+// SubscribeCurrencyPairs sends the new subscription messages to the websocket
+// and adds them to the providers subscribedPairs array
+func (p *CoincheckProvider) SubscribeCurrencyPairs(cps ...types.CurrencyPair) {
+	p.mtx.Lock()
+	defer p.mtx.Unlock()
+
+	newPairs := []types.CurrencyPair{}
+	for _, cp := range cps {
+		if _, ok := p.subscribedPairs[cp.String()]; !ok {
+			newPairs = append(newPairs, cp)
+		}
+	}
+
+	confirmedPairs, err := ConfirmPairAvailability(
+		p,
+		p.endpoints.Name,
+		p.logger,
+		newPairs...,
+	)
+	if err != nil {
+		return
+	}
+
+	newSubscriptionMsgs := p.getSubscriptionMsgs(confirmedPairs...)
+	p.wsc.AddWebsocketConnection(
+		newSubscriptionMsgs,
+		p.messageReceived,
+		defaultPingDuration,
+		websocket.PingMessage,
+	)
+	p.setSubscribedPairs(confirmedPairs...)
+}
+
+// messageReceived handles the messages of the trades channel, an array of
+// trades, and of the order book channel, an array starting with the symbol.
+func (p *CoincheckProvider) messageReceived(messageType int, _ *WebsocketConnection, bz []byte) {
+	if messageType != websocket.TextMessage {
+		return
+	}
+
+	var msg []json.RawMessage
+	if err := json.Unmarshal(bz, &msg); err != nil || len(msg) == 0 {
+		p.logger.Error().
+			Int("length", len(bz)).
+			AnErr("err", err).
+			Msg("Error on receive message")
+		telemetryDecodeError(p.logger, ProviderCoincheck, bz)
+		return
+	}
+
+	var symbol string
+	if err := json.Unmarshal(msg[0], &symbol); err == nil {
+		if len(msg) != 2 {
+			p.logger.Error().Str("symbol", symbol).Msg("invalid order book message")
+			return
+		}
+		var orderBook CoincheckOrderBook
+		if err := json.Unmarshal(msg[1], &orderBook); err != nil {
+			p.logger.Error().Err(err).Str("symbol", symbol).Msg("failed to unmarshal order book")
+			return
+		}
+		p.orderBookReceived(symbol, orderBook)
+		telemetryWebsocketMessage(ProviderCoincheck, MessageTypeTicker)
+		return
+	}
+
+	for _, raw := range msg {
+		trade, err := newCoincheckTrade(raw)
+		if err != nil {
+			p.logger.Error().Err(err).Msg("failed to parse trade")
+			continue
+		}
+		p.tradeReceived(trade)
+		telemetryWebsocketMessage(ProviderCoincheck, MessageTypeTrade)
+	}
+}
+
+// tradeReceived adds the trade to the candles and the volume of its symbol
+// and updates its ticker.
+func (p *CoincheckProvider) tradeReceived(trade CoincheckTrade) {
+	if _, ok := p.priceSources[trade.Symbol]; !ok {
+		// candles would outweigh the best quote
+		p.addTradeToCandles(types.Trade{
+			Time:  trade.Time,
+			Price: trade.Rate.String(),
+			Size:  trade.Amount.String(),
+		}, trade.Symbol)
+	}
+
+	p.marketsMtx.Lock()
+	defer p.marketsMtx.Unlock()
+
+	market := p.market(trade.Symbol)
+	market.last = trade.Rate
+	if err := market.addVolume(trade); err != nil {
+		p.logger.Error().Err(err).Str("symbol", trade.Symbol).Msg("failed to add trade volume")
+	}
+	p.setTicker(trade.Symbol, market)
+}
+
+// orderBookReceived applies the order book update to the book of the symbol
+// and updates its ticker.
+func (p *CoincheckProvider) orderBookReceived(symbol string, orderBook CoincheckOrderBook) {
+	p.marketsMtx.Lock()
+	defer p.marketsMtx.Unlock()
+
+	market := p.market(symbol)
+	if err := applyCoincheckLevels(market.bids, orderBook.Bids); err != nil {
+		p.logger.Error().Err(err).Str("symbol", symbol).Msg("failed to apply bids")
+		return
+	}
+	if err := applyCoincheckLevels(market.asks, orderBook.Asks); err != nil {
+		p.logger.Error().Err(err).Str("symbol", symbol).Msg("failed to apply asks")
+		return
+	}
+	p.setTicker(symbol, market)
+}
+
+// market returns the market of the symbol, creating it if needed. It must be
+// called with marketsMtx held.
+func (p *CoincheckProvider) market(symbol string) *coincheckMarket {
+	market, ok := p.markets[symbol]
+	if !ok {
+		market = &coincheckMarket{
+			bids: make(map[string]sdk.Dec),
+			asks: make(map[string]sdk.Dec),
+		}
+		p.markets[symbol] = market
+	}
+	return market
+}
+
+// setTicker sets the ticker of the symbol from the last trade, or from the
+// best quotes of the order book if the symbol has a price source. It must be
+// called with marketsMtx held.
+func (p *CoincheckProvider) setTicker(symbol string, market *coincheckMarket) {
+	price := market.last
+	if source, ok := p.priceSources[symbol]; ok {
+		bid, ask := market.bestQuotes()
+		quote, err := source.quotePrice(market.last, bid, ask)
+		if err != nil {
+			p.logger.Debug().Err(err).Str("symbol", symbol).Msg("failed to get best quote price")
+			return
+		}
+		price = quote
+	}
+	if len(price) == 0 {
+		return
+	}
+
+	p.setTickerPair(CoincheckTicker{
+		Price:  price,
+		Volume: market.volume(),
+	}, symbol)
+}
+
+// addVolume adds the amount of the trade to the volume of its minute and
+// drops the volumes older than coincheckVolumePeriod.
+func (m *coincheckMarket) addVolume(trade CoincheckTrade) error {
+	amount, err := trade.Amount.Dec()
+	if err != nil {
+		return err
+	}
+
+	minute := time.UnixMilli(trade.Time).Truncate(time.Minute).UnixMilli()
+	staleTime := PastUnixTime(coincheckVolumePeriod)
+
+	volumes := make([]types.CandlePrice, 0, len(m.volumes)+1)
+	found := false
+	for _, v := range m.volumes {
+		if v.TimeStamp <= staleTime {
+			continue
+		}
+		if v.TimeStamp == minute {
+			v.Volume = v.Volume.Add(amount)
+			found = true
+		}
+		volumes = append(volumes, v)
+	}
+	if !found && minute > staleTime {
+		volumes = append(volumes, types.CandlePrice{Volume: amount, TimeStamp: minute})
+	}
+	m.volumes = volumes
+	return nil
+}
+
+// volume returns the volume traded over the last coincheckVolumePeriod.
+func (m *coincheckMarket) volume() sdk.Dec {
+	staleTime := PastUnixTime(coincheckVolumePeriod)
+	volume := sdk.ZeroDec()
+	for _, v := range m.volumes {
+		if v.TimeStamp > staleTime {
+			volume = volume.Add(v.Volume)
+		}
+	}
+	return volume
+}
+
+// bestQuotes returns the highest bid and the lowest ask of the order book,
+// empty if a side has no levels. The book is built from the updates received
+// since subscribing.
+func (m *coincheckMarket) bestQuotes() (bid, ask types.Number) {
+	var bestBid, bestAsk sdk.Dec
+	for price := range m.bids {
+		if dec, err := sdk.NewDecFromStr(price); err == nil && (bestBid.IsNil() || dec.GT(bestBid)) {
+			bestBid = dec
+			bid = types.Number(price)
+		}
+	}
+	for price := range m.asks {
+		if dec, err := sdk.NewDecFromStr(price); err == nil && (bestAsk.IsNil() || dec.LT(bestAsk)) {
+			bestAsk = dec
+			ask = types.Number(price)
+		}
+	}
+	return bid, ask
+}
+
+// applyCoincheckLevels sets the amount of each price level of the book side,
+// removing the levels with a zero amount.
+func applyCoincheckLevels(side map[string]sdk.Dec, levels [][]types.Number) error {
+	for _, level := range levels {
+		if len(level) != 2 {
+			return fmt.Errorf("invalid order book level %v", level)
+		}
+		amount, err := level[1].Dec()
+		if err != nil {
+			return err
+		}
+		if amount.IsZero() {
+			delete(side, level[0].String())
+			continue
+		}
+		side[level[0].String()] = amount
+	}
+	return nil
+}
+
+// newCoincheckTrade parses a trade of the trades channel.
+func newCoincheckTrade(raw json.RawMessage) (CoincheckTrade, error) {
+	var fields []string
+	if err := json.Unmarshal(raw, &fields); err != nil {
+		return CoincheckTrade{}, err
+	}
+	if len(fields) < 5 {
+		return CoincheckTrade{}, fmt.Errorf("invalid trade %v", fields)
+	}
+
+	timestamp, err := strconv.ParseInt(fields[0], 10, 64)
+	if err != nil {
+		return CoincheckTrade{}, fmt.Errorf("invalid trade timestamp %s: %w", fields[0], err)
+	}
+	trade := CoincheckTrade{
+		Time:   SecondsToMilli(timestamp),
+		Symbol: fields[2],
+		Rate:   types.Number(fields[3]),
+		Amount: types.Number(fields[4]),
+	}
+	if _, err := trade.Rate.Dec(); err != nil {
+		return CoincheckTrade{}, err
+	}
+	if _, err := trade.Amount.Dec(); err != nil {
+		return CoincheckTrade{}, err
+	}
+	return trade, nil
+}
+
+func (ticker CoincheckTicker) toTickerPrice() (types.TickerPrice, error) {
+	return types.NewTickerPrice(ticker.Price.String(), ticker.Volume.String())
+}
+
+// GetAvailablePairs returns all pairs to which the provider can subscribe.
+// ex.: map["BTCJPY" => {}, "ETHJPY" => {}].
+func (p *CoincheckProvider) GetAvailablePairs() (map[string]struct{}, error) {
+	resp, err := p.endpoints.httpClient().Get(p.endpoints.Rest + coincheckRestPath)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	var pairsSummary CoincheckPairsSummary
+	if err := json.NewDecoder(resp.Body).Decode(&pairsSummary); err != nil {
+		return nil, err
+	}
+
+	availablePairs := make(map[string]struct{}, len(pairsSummary.ExchangeStatus))
+	for _, pair := range pairsSummary.ExchangeStatus {
+		availablePairs[strings.ToUpper(strings.ReplaceAll(pair.Pair, "_", ""))] = struct{}{}
+	}
+
+	return availablePairs, nil
+}
+
+// currencyPairToCoincheckPair receives a currency pair and returns the
+// Coincheck symbol ex.: btc_jpy.
+func currencyPairToCoincheckPair(cp types.CurrencyPair) string {
+	return strings.ToLower(cp.Base + "_" + cp.Quote)
+}
+
+// newCoincheckSubscriptionMsg returns a new subscription Msg to the channel
+// of a symbol.
+func newCoincheckSubscriptionMsg(symbol, channel string) CoincheckSubscriptionMsg {
+	return CoincheckSubscriptionMsg{
+		Type:    "subscribe",
+		Channel: symbol + "-" + channel,
+	}
+}
@@ -0,0 +1,126 @@
+package provider
+
+import (
+	"encoding/json"
+	"strconv"
+	"testing"
+	"time"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/gorilla/websocket"
+	"github.com/ojo-network/price-feeder/oracle/types"
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCoincheckProvider_messageReceived(t *testing.T) {
+	btcjpy := types.CurrencyPair{Base: "BTC", Quote: "JPY"}
+	p := &CoincheckProvider{
+		logger:     zerolog.Nop(),
+		markets:    make(map[string]*coincheckMarket),
+		priceStore: newPriceStore(zerolog.Nop()),
+	}
+	p.setCurrencyPairToTickerAndCandlePair(currencyPairToCoincheckPair)
+	p.setSubscribedPairs(btcjpy)
+
+	tradeTime := Now().Add(-time.Second).Unix()
+	trades := `[["` + strconv.FormatInt(tradeTime, 10) + `","2357062","btc_jpy","9800000.0","0.5","sell","1193401","2078767"],` +
+		`["` + strconv.FormatInt(tradeTime, 10) + `","2357063","btc_jpy","9810000.0","0.25","buy","1193402","2078768"]]`
+	p.messageReceived(websocket.TextMessage, nil, []byte(trades))
+
+	prices, err := p.GetTickerPrices(btcjpy)
+	require.NoError(t, err)
+	require.Len(t, prices, 1)
+	require.Equal(t, sdk.MustNewDecFromStr("9810000"), prices[btcjpy].Price)
+	require.Equal(t, sdk.MustNewDecFromStr("0.75"), prices[btcjpy].Volume)
+
+	candles, err := p.GetCandlePrices(btcjpy)
+	require.NoError(t, err)
+	require.Len(t, candles[btcjpy], 1)
+	require.Equal(t, sdk.MustNewDecFromStr("9810000"), candles[btcjpy][0].Price)
+	require.Equal(t, sdk.MustNewDecFromStr("0.75"), candles[btcjpy][0].Volume)
+	require.Equal(
+		t,
+		time.Unix(tradeTime, 0).Truncate(time.Minute).Add(time.Minute).UnixMilli(),
+		candles[btcjpy][0].TimeStamp,
+	)
+
+	// trades with an invalid rate are skipped
+	p.messageReceived(websocket.TextMessage, nil, []byte(`[["1663318663","1","btc_jpy","abc","0.5","sell"]]`))
+	prices, err = p.GetTickerPrices(btcjpy)
+	require.NoError(t, err)
+	require.Equal(t, sdk.MustNewDecFromStr("9810000"), prices[btcjpy].Price)
+}
+
+func TestCoincheckProvider_orderBookMid(t *testing.T) {
+	btcjpy := types.CurrencyPair{Base: "BTC", Quote: "JPY"}
+	p := &CoincheckProvider{
+		logger:       zerolog.Nop(),
+		priceSources: map[string]PriceSource{"btc_jpy": PriceSourceMid},
+		markets:      make(map[string]*coincheckMarket),
+		priceStore:   newPriceStore(zerolog.Nop()),
+	}
+	p.setCurrencyPairToTickerAndCandlePair(currencyPairToCoincheckPair)
+	p.setSubscribedPairs(btcjpy)
+
+	// a single side of the book is not enough for the mid
+	p.messageReceived(websocket.TextMessage, nil, []byte(
+		`["btc_jpy",{"bids":[["9790000.0","0.1"],["9800000.0","0.2"]],"asks":[],"last_update_at":"1659321701"}]`,
+	))
+	prices, err := p.GetTickerPrices(btcjpy)
+	require.NoError(t, err)
+	require.Empty(t, prices)
+
+	p.messageReceived(websocket.TextMessage, nil, []byte(
+		`["btc_jpy",{"bids":[],"asks":[["9820000.0","0.1"],["9830000.0","0.3"]],"last_update_at":"1659321702"}]`,
+	))
+	prices, err = p.GetTickerPrices(btcjpy)
+	require.NoError(t, err)
+	require.Equal(t, sdk.MustNewDecFromStr("9810000"), prices[btcjpy].Price)
+
+	// levels with a zero amount are removed
+	p.messageReceived(websocket.TextMessage, nil, []byte(
+		`["btc_jpy",{"bids":[["9800000.0","0"]],"asks":[],"last_update_at":"1659321703"}]`,
+	))
+	prices, err = p.GetTickerPrices(btcjpy)
+	require.NoError(t, err)
+	require.Equal(t, sdk.MustNewDecFromStr("9805000"), prices[btcjpy].Price)
+
+	// trades of pairs priced by their best quote build no candles
+	trades := `[["` + strconv.FormatInt(Now().Unix(), 10) + `","1","btc_jpy","9900000.0","0.5","sell","1","2"]]`
+	p.messageReceived(websocket.TextMessage, nil, []byte(trades))
+	prices, err = p.GetTickerPrices(btcjpy)
+	require.NoError(t, err)
+	require.Equal(t, sdk.MustNewDecFromStr("9805000"), prices[btcjpy].Price)
+	require.Equal(t, sdk.MustNewDecFromStr("0.5"), prices[btcjpy].Volume)
+	candles, err := p.GetCandlePrices(btcjpy)
+	require.NoError(t, err)
+	require.Empty(t, candles)
+}
+
+func TestCoincheckCurrencyPairToCoincheckPair(t *testing.T) {
+	cp := types.CurrencyPair{Base: "BTC", Quote: "JPY"}
+	require.Equal(t, "btc_jpy", currencyPairToCoincheckPair(cp))
+}
+
+func TestCoincheckProvider_getSubscriptionMsgs(t *testing.T) {
+	provider := &CoincheckProvider{
+		priceSources: map[string]PriceSource{"eth_jpy": PriceSourceMid},
+	}
+	cps := []types.CurrencyPair{
+		{Base: "BTC", Quote: "JPY"},
+		{Base: "ETH", Quote: "JPY"},
+	}
+	subMsgs := provider.getSubscriptionMsgs(cps...)
+
+	msgs := make([]string, len(subMsgs))
+	for i, subMsg := range subMsgs {
+		msg, _ := json.Marshal(subMsg)
+		msgs[i] = string(msg)
+	}
+	require.Equal(t, []string{
+		`{"type":"subscribe","channel":"btc_jpy-trades"}`,
+		`{"type":"subscribe","channel":"eth_jpy-trades"}`,
+		`{"type":"subscribe","channel":"eth_jpy-orderbook"}`,
+	}, msgs)
+}
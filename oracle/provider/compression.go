@@ -0,0 +1,132 @@
+package provider
+
+import (
+	"bytes"
+	"compress/flate"
+	"compress/gzip"
+	"fmt"
+	"io"
+	"sync"
+
+	"github.com/klauspost/compress/zstd"
+	"github.com/rs/zerolog"
+
+	"github.com/ojo-network/price-feeder/oracle/types"
+)
+
+// CompressionNone, CompressionGzip, CompressionDeflate and CompressionZstd
+// are the compressions of the binary websocket messages of a provider.
+// Deflate is the raw DEFLATE format, without a zlib or gzip header.
+const (
+	CompressionNone    = "none"
+	CompressionGzip    = "gzip"
+	CompressionDeflate = "deflate"
+	CompressionZstd    = "zstd"
+)
+
+var (
+	// defaultCompressions are the compressions of the providers which always
+	// compress their websocket market data.
+	defaultCompressions = map[types.ProviderName]string{
+		ProviderHuobi: CompressionGzip,
+		ProviderBingx: CompressionGzip,
+	}
+
+	// providerCompressions holds the compressions of the providers whose
+	// endpoints set one.
+	providerCompressions    = map[types.ProviderName]string{}
+	providerCompressionsMtx sync.RWMutex
+
+	// zstdDecoder decodes the zstd messages of every connection, which is
+	// safe as DecodeAll may be called concurrently.
+	zstdDecoder, _ = zstd.NewReader(nil)
+)
+
+// MessageCompression returns the compression of the endpoint's websocket
+// messages, which is that of its provider if it sets none.
+func (e Endpoint) MessageCompression() (string, error) {
+	switch e.Compression {
+	case "":
+		if compression, ok := defaultCompressions[e.Name]; ok {
+			return compression, nil
+		}
+		return CompressionNone, nil
+	case CompressionNone, CompressionGzip, CompressionDeflate, CompressionZstd:
+		return e.Compression, nil
+	default:
+		return "", fmt.Errorf(
+			"unsupported compression %s of %s, expected one of %s, %s, %s or %s",
+			e.Compression,
+			e.Name,
+			CompressionGzip,
+			CompressionDeflate,
+			CompressionZstd,
+			CompressionNone,
+		)
+	}
+}
+
+// SetMessageCompression sets the compression of the websocket messages of
+// the endpoint's provider. It applies to the websocket controllers created
+// afterwards.
+func SetMessageCompression(logger zerolog.Logger, endpoint Endpoint) error {
+	compression, err := endpoint.MessageCompression()
+	if err != nil {
+		return err
+	}
+
+	providerCompressionsMtx.Lock()
+	defer providerCompressionsMtx.Unlock()
+
+	if endpoint.Compression == "" {
+		delete(providerCompressions, endpoint.Name)
+		return nil
+	}
+
+	logger.Info().
+		Str("provider", endpoint.Name.String()).
+		Str("compression", compression).
+		Msg("decompressing websocket messages")
+	providerCompressions[endpoint.Name] = compression
+	return nil
+}
+
+// messageCompression returns the compression of the websocket messages of
+// the provider, which is its default compression if none is set.
+func messageCompression(providerName types.ProviderName) string {
+	providerCompressionsMtx.RLock()
+	defer providerCompressionsMtx.RUnlock()
+
+	if compression, ok := providerCompressions[providerName]; ok {
+		return compression
+	}
+	if compression, ok := defaultCompressions[providerName]; ok {
+		return compression
+	}
+	return CompressionNone
+}
+
+// decompressMessage returns the uncompressed content of a websocket message
+// with the given compression.
+func decompressMessage(compression string, bz []byte) ([]byte, error) {
+	switch compression {
+	case CompressionGzip:
+		r, err := gzip.NewReader(bytes.NewReader(bz))
+		if err != nil {
+			return nil, err
+		}
+		defer r.Close()
+		return io.ReadAll(r)
+
+	case CompressionDeflate:
+		r := flate.NewReader(bytes.NewReader(bz))
+		defer r.Close()
+		return io.ReadAll(r)
+
+	case CompressionZstd:
+		return zstdDecoder.DecodeAll(bz, nil)
+
+	default:
+		return bz, nil
+	}
+}
@@ -0,0 +1,112 @@
+package provider
+
+import (
+	"context"
+	"encoding/hex"
+	"net/url"
+	"testing"
+
+	"github.com/gorilla/websocket"
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/require"
+)
+
+// compressedTicker is the content of the recorded compressed frames.
+const compressedTicker = `{"ch":"market.atomusdt.ticker","tick":{"lastPrice":11.52,"vol":2396974.02}}`
+
+// compressedFrames are recorded websocket frames of compressedTicker.
+var compressedFrames = map[string]string{
+	CompressionGzip: "1f8b08000000000002ffaa564ace50b252ca4d2cca4e2dd14b2cc9cf2d2d4e29d12bc94cce4e2d52d251" +
+		"023194acaa9572128b4b028a32935395ac0c0df54c8d7494caf27394ac8c8c2dcd2ccd4df40c8c6a6b010300fac03bc24b000000",
+	CompressionDeflate: "aa564ace50b252ca4d2cca4e2dd14b2cc9cf2d2d4e29d12bc94cce4e2d52d251023194acaa9572128b4b02" +
+		"8a32935395ac0c0df54c8d7494caf27394ac8c8c2dcd2ccd4df40c8c6a6b010300",
+	CompressionZstd: "28b52ffd04005902007b226368223a226d61726b65742e61746f6d757364742e7469636b6572222c2274" +
+		"69636b223a7b226c6173745072696365223a31312e35322c22766f6c223a323339363937342e30327d7d7514c752",
+}
+
+func TestDecompressMessage(t *testing.T) {
+	for compression, frame := range compressedFrames {
+		t.Run(compression, func(t *testing.T) {
+			bz, err := hex.DecodeString(frame)
+			require.NoError(t, err)
+
+			msg, err := decompressMessage(compression, bz)
+			require.NoError(t, err)
+			require.Equal(t, compressedTicker, string(msg))
+
+			_, err = decompressMessage(compression, []byte(compressedTicker))
+			require.Error(t, err)
+		})
+	}
+
+	msg, err := decompressMessage(CompressionNone, []byte(compressedTicker))
+	require.NoError(t, err)
+	require.Equal(t, compressedTicker, string(msg))
+}
+
+func TestEndpoint_MessageCompression(t *testing.T) {
+	compression, err := Endpoint{Name: ProviderBinance}.MessageCompression()
+	require.NoError(t, err)
+	require.Equal(t, CompressionNone, compression)
+
+	compression, err = Endpoint{Name: ProviderHuobi}.MessageCompression()
+	require.NoError(t, err)
+	require.Equal(t, CompressionGzip, compression)
+
+	compression, err = Endpoint{Name: ProviderHuobi, Compression: CompressionNone}.MessageCompression()
+	require.NoError(t, err)
+	require.Equal(t, CompressionNone, compression)
+
+	compression, err = Endpoint{Name: ProviderOkx, Compression: CompressionDeflate}.MessageCompression()
+	require.NoError(t, err)
+	require.Equal(t, CompressionDeflate, compression)
+
+	_, err = Endpoint{Name: ProviderOkx, Compression: "brotli"}.MessageCompression()
+	require.Error(t, err)
+}
+
+func TestWebsocketConnection_readSuccessCompressed(t *testing.T) {
+	require.NoError(t, SetMessageCompression(zerolog.Nop(), Endpoint{
+		Name:        ProviderMock,
+		Compression: CompressionZstd,
+	}))
+	defer func() {
+		require.NoError(t, SetMessageCompression(zerolog.Nop(), Endpoint{Name: ProviderMock}))
+	}()
+
+	var received []string
+	wsc := NewWebsocketController(
+		context.Background(),
+		ProviderMock,
+		url.URL{Scheme: "ws", Host: "localhost"},
+		[]interface{}{""},
+		func(_ int, _ *WebsocketConnection, bz []byte) {
+			received = append(received, string(bz))
+		},
+		disabledPingDuration,
+		websocket.PingMessage,
+		DefaultSilenceTimeout,
+		0,
+		nil,
+		nil,
+		nil,
+		zerolog.Nop(),
+	)
+	conn := wsc.connections[0]
+	require.Equal(t, CompressionZstd, conn.compression)
+
+	bz, err := hex.DecodeString(compressedFrames[CompressionZstd])
+	require.NoError(t, err)
+	conn.readSuccess(websocket.BinaryMessage, bz)
+	decodeErrors := ProviderHealths()[ProviderMock].DecodeErrors
+
+	// text frames are not compressed and frames which fail to decompress are
+	// dropped
+	conn.readSuccess(websocket.TextMessage, []byte(`{"pong":1}`))
+	conn.readSuccess(websocket.BinaryMessage, []byte(compressedTicker))
+
+	require.Equal(t, []string{compressedTicker, `{"pong":1}`}, received)
+
+	// the frame which failed to decompress is counted as a decode error
+	require.Equal(t, decodeErrors+1, ProviderHealths()[ProviderMock].DecodeErrors)
+}
@@ -3,7 +3,6 @@ package provider
 import (
 	"context"
 	"encoding/json"
-	"net/http"
 	"net/url"
 	"strings"
 	"sync"
@@ -37,14 +36,14 @@ type (
 	}
 
 	CrescentTicker struct {
-		Price  string `json:"Price"`
-		Volume string `json:"Volume"`
+		Price  types.Number `json:"Price"`
+		Volume types.Number `json:"Volume"`
 	}
 
 	CrescentCandle struct {
-		Close   string `json:"Close"`
-		Volume  string `json:"Volume"`
-		EndTime int64  `json:"EndTime"`
+		Close   types.Number `json:"Close"`
+		Volume  types.Number `json:"Volume"`
+		EndTime int64        `json:"EndTime"`
 	}
 
 	// CrescentPairsSummary defines the response structure for an Crescent pairs
@@ -68,9 +67,11 @@ func NewCrescentProvider(
 ) (*CrescentProvider, error) {
 	if endpoints.Name != ProviderCrescent {
 		endpoints = Endpoint{
-			Name:      ProviderCrescent,
-			Rest:      crescentV2RestHost,
-			Websocket: crescentV2WSHost,
+			Name:              ProviderCrescent,
+			Rest:              crescentV2RestHost,
+			Websocket:         crescentV2WSHost,
+			SilenceTimeout:    endpoints.SilenceTimeout,
+			ReconnectCooldown: endpoints.ReconnectCooldown,
 		}
 	}
 
@@ -110,6 +111,11 @@ func NewCrescentProvider(
 		provider.messageReceived,
 		defaultPingDuration,
 		websocket.PingMessage,
+		endpoints.SilenceTimeout,
+		endpoints.ReconnectCooldown,
+		&provider.priceStore,
+		endpoints.tlsConfig,
+		endpoints.header,
 		crescentV2Logger,
 	)
 
@@ -160,6 +166,7 @@ func (p *CrescentProvider) messageReceived(_ int, _ *WebsocketConnection, bz []b
 			Int("length", len(bz)).
 			AnErr("message", messageErr).
 			Msg("Error on receive message")
+		telemetryDecodeError(p.logger, ProviderCrescent, bz)
 	}
 
 	// Check the response for currency pairs that the provider is subscribed
@@ -177,6 +184,7 @@ func (p *CrescentProvider) messageReceived(_ int, _ *WebsocketConnection, bz []b
 						Int("length", len(bz)).
 						AnErr("ticker", tickerErr).
 						Msg("Error on receive message")
+					telemetryDecodeError(p.logger, ProviderCrescent, bz)
 					continue
 				}
 				p.setTickerPair(
@@ -199,6 +207,7 @@ func (p *CrescentProvider) messageReceived(_ int, _ *WebsocketConnection, bz []b
 						Int("length", len(bz)).
 						AnErr("candle", candleErr).
 						Msg("Error on receive message")
+					telemetryDecodeError(p.logger, ProviderCrescent, bz)
 					continue
 				}
 				for _, singleCandle := range candleResp {
@@ -216,15 +225,15 @@ func (p *CrescentProvider) messageReceived(_ int, _ *WebsocketConnection, bz []b
 
 func (ct CrescentTicker) toTickerPrice() (types.TickerPrice, error) {
 	return types.NewTickerPrice(
-		ct.Price,
-		ct.Volume,
+		ct.Price.String(),
+		ct.Volume.String(),
 	)
 }
 
 func (cc CrescentCandle) toCandlePrice() (types.CandlePrice, error) {
 	return types.NewCandlePrice(
-		cc.Close,
-		cc.Volume,
+		cc.Close.String(),
+		cc.Volume.String(),
 		cc.EndTime,
 	)
 }
@@ -239,7 +248,7 @@ func (p *CrescentProvider) setSubscribedPairs(cps ...types.CurrencyPair) {
 // GetAvailablePairs returns all pairs to which the provider can subscribe.
 // ex.: map["ATOMUSDT" => {}, "OJOUSDC" => {}].
 func (p *CrescentProvider) GetAvailablePairs() (map[string]struct{}, error) {
-	resp, err := http.Get(p.endpoints.Rest + crescentV2RestPath)
+	resp, err := p.endpoints.httpClient().Get(p.endpoints.Rest + crescentV2RestPath)
 	if err != nil {
 		return nil, err
 	}
@@ -88,8 +88,8 @@ func TestCrescentProvider_GetCandlePrices(t *testing.T) {
 		time := int64(1000000)
 
 		candle := CrescentCandle{
-			Volume:  volume,
-			Close:   price,
+			Volume:  types.Number(volume),
+			Close:   types.Number(price),
 			EndTime: time,
 		}
 
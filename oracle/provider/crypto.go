@@ -3,7 +3,6 @@ package provider
 import (
 	"context"
 	"encoding/json"
-	"net/http"
 	"net/url"
 	"strings"
 	"sync"
@@ -57,9 +56,9 @@ type (
 		Data           []CryptoTicker `json:"data"`            // ticker data
 	}
 	CryptoTicker struct {
-		InstrumentName string `json:"i"` // Instrument Name, e.g. BTC_USDT, ETH_CRO, etc.
-		Volume         string `json:"v"` // The total 24h traded volume
-		LatestTrade    string `json:"a"` // The price of the latest trade, null if there weren't any trades
+		InstrumentName string       `json:"i"` // Instrument Name, e.g. BTC_USDT, ETH_CRO, etc.
+		Volume         types.Number `json:"v"` // The total 24h traded volume
+		LatestTrade    types.Number `json:"a"` // The price of the latest trade, null if there weren't any trades
 	}
 
 	CryptoCandleResponse struct {
@@ -71,9 +70,9 @@ type (
 		Data           []CryptoCandle `json:"data"`            // candlestick data
 	}
 	CryptoCandle struct {
-		Close     string `json:"c"` // Price at close
-		Volume    string `json:"v"` // Volume during interval
-		Timestamp int64  `json:"t"` // End time of candlestick (Unix timestamp)
+		Close     types.Number `json:"c"` // Price at close
+		Volume    types.Number `json:"v"` // Volume during interval
+		Timestamp int64        `json:"t"` // End time of candlestick (Unix timestamp)
 	}
 
 	CryptoSubscriptionMsg struct {
@@ -111,9 +110,11 @@ func NewCryptoProvider(
 ) (*CryptoProvider, error) {
 	if endpoints.Name != ProviderCrypto {
 		endpoints = Endpoint{
-			Name:      ProviderCrypto,
-			Rest:      cryptoRestHost,
-			Websocket: cryptoWSHost,
+			Name:              ProviderCrypto,
+			Rest:              cryptoRestHost,
+			Websocket:         cryptoWSHost,
+			SilenceTimeout:    endpoints.SilenceTimeout,
+			ReconnectCooldown: endpoints.ReconnectCooldown,
 		}
 	}
 
@@ -153,6 +154,11 @@ func NewCryptoProvider(
 		provider.messageReceived,
 		disabledPingDuration,
 		websocket.PingMessage,
+		endpoints.SilenceTimeout,
+		endpoints.ReconnectCooldown,
+		&provider.priceStore,
+		endpoints.tlsConfig,
+		endpoints.header,
 		cryptoLogger,
 	)
 
@@ -263,6 +269,7 @@ func (p *CryptoProvider) messageReceived(messageType int, conn *WebsocketConnect
 		AnErr("ticker", tickerErr).
 		AnErr("candle", candleErr).
 		Msg("Error on receive message")
+	telemetryDecodeError(p.logger, ProviderCrypto, bz)
 }
 
 // pongReceived return a heartbeat message when a "ping" is received and reset the
@@ -283,11 +290,11 @@ func (p *CryptoProvider) pong(conn *WebsocketConnection, heartbeatResp CryptoHea
 }
 
 func (ct CryptoTicker) toTickerPrice() (types.TickerPrice, error) {
-	return types.NewTickerPrice(ct.LatestTrade, ct.Volume)
+	return types.NewTickerPrice(ct.LatestTrade.String(), ct.Volume.String())
 }
 
 func (ct CryptoCandle) toCandlePrice() (types.CandlePrice, error) {
-	return types.NewCandlePrice(ct.Close, ct.Volume, ct.Timestamp)
+	return types.NewCandlePrice(ct.Close.String(), ct.Volume.String(), ct.Timestamp)
 }
 
 // setSubscribedPairs sets N currency pairs to the map of subscribed pairs.
@@ -300,7 +307,7 @@ func (p *CryptoProvider) setSubscribedPairs(cps ...types.CurrencyPair) {
 // GetAvailablePairs returns all pairs to which the provider can subscribe.
 // ex.: map["ATOMUSDT" => {}, "OJOUSDC" => {}].
 func (p *CryptoProvider) GetAvailablePairs() (map[string]struct{}, error) {
-	resp, err := http.Get(p.endpoints.Rest + cryptoRestPath)
+	resp, err := p.endpoints.httpClient().Get(p.endpoints.Rest + cryptoRestPath)
 	if err != nil {
 		return nil, err
 	}
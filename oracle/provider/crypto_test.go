@@ -88,8 +88,8 @@ func TestCryptoProvider_GetCandlePrices(t *testing.T) {
 		timeStamp := int64(1000000)
 
 		candle := CryptoCandle{
-			Volume:    volume,
-			Close:     price,
+			Volume:    types.Number(volume),
+			Close:     types.Number(price),
 			Timestamp: timeStamp,
 		}
 
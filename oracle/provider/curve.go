@@ -0,0 +1,475 @@
+package provider
+
+import (
+	"context"
+	"encoding/hex"
+	"fmt"
+	"math/big"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/ojo-network/price-feeder/oracle/types"
+	"github.com/rs/zerolog"
+)
+
+const (
+	curveRestHost          = "https://ethereum-rpc.publicnode.com"
+	curvePricePollInterval = 15 * time.Second
+	curveMaxDecimals       = 36
+
+	// curveGetDy, curvePriceOracle, curvePriceOracleIndexed and curveCoins are
+	// the selectors of the get_dy(int128,int128,uint256), price_oracle(),
+	// price_oracle(uint256) and coins(uint256) functions of a pool, and
+	// curveDecimals the selector of the decimals() function of its coins.
+	curveGetDy              = "0x5e0d443f"
+	curvePriceOracle        = "0x86fc88d3"
+	curvePriceOracleIndexed = "0x68727653"
+	curveCoins              = "0xc6610657"
+	curveDecimals           = "0x313ce567"
+
+	// curveNativeCoin is the address pools use for the native coin of the
+	// chain, which has no decimals() function.
+	curveNativeCoin         = "0xeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeee"
+	curveNativeCoinDecimals = 18
+
+	// curveOracleDecimals are the decimals of the prices of a pool's oracle,
+	// whatever the decimals of its coins.
+	curveOracleDecimals = 18
+)
+
+// curveOracle defines the kind of price oracle a pool provides: none, a
+// single price_oracle() pricing coin 1 in coin 0, or a price_oracle(i)
+// pricing every coin i+1 in coin 0.
+type curveOracle int
+
+const (
+	curveOracleNone curveOracle = iota
+	curveOracleSingle
+	curveOracleIndexed
+)
+
+var _ Provider = (*CurveProvider)(nil)
+
+type (
+	// CurveProvider defines an Oracle provider which polls the exchange rate
+	// between two coins of Curve stableswap pools through an EVM JSON-RPC
+	// endpoint. Each pair is mapped to a pool and the indices of its base and
+	// quote coins by the pools set from the curve section of the config. A
+	// pool with a price oracle is priced by its EMA price, which is far
+	// harder to manipulate than its spot rate, and otherwise by the amount of
+	// quote coin get_dy returns for one base coin, fees included. The kind of
+	// oracle and the decimals of the coins are read from the chain once. A
+	// pair whose rate fails to be read stops contributing prices until it is
+	// read again. Pools carry no traded volume, so prices are stored as
+	// candles and tickers without volume.
+	//
+	// REF: https://docs.curve.fi/stableswap-exchange/stableswap-ng/pools/overview/
+	CurveProvider struct {
+		ctx       context.Context
+		logger    zerolog.Logger
+		mtx       sync.RWMutex
+		endpoints Endpoint
+		client    *http.Client
+
+		// coins holds the oracle of each pair's pool and the decimals of its
+		// coins once queried.
+		coins map[string]curvePoolCoins
+
+		priceStore
+	}
+
+	// CurvePool defines the Curve pool a pair is priced by and the indices of
+	// its base and quote coins in the pool.
+	CurvePool struct {
+		Address    string
+		BaseIndex  int
+		QuoteIndex int
+	}
+
+	// curvePoolCoins defines the price oracle of a pool and the decimals of the
+	// base and quote coins of a pair in the pool.
+	curvePoolCoins struct {
+		oracle        curveOracle
+		baseDecimals  uint8
+		quoteDecimals uint8
+	}
+
+	// curvePrice defines the rate of a pool at the time it was polled.
+	curvePrice struct {
+		price     sdk.Dec
+		timeStamp int64
+	}
+)
+
+func NewCurveProvider(
+	ctx context.Context,
+	logger zerolog.Logger,
+	endpoints Endpoint,
+	pairs ...types.CurrencyPair,
+) (*CurveProvider, error) {
+	if endpoints.Name != ProviderCurve {
+		endpoints = Endpoint{
+			Name: ProviderCurve,
+			Rest: curveRestHost,
+		}
+	}
+
+	curveLogger := logger.With().Str("provider", string(ProviderCurve)).Logger()
+
+	provider := &CurveProvider{
+		ctx:        ctx,
+		logger:     curveLogger,
+		endpoints:  endpoints,
+		client:     endpoints.httpClient(),
+		coins:      map[string]curvePoolCoins{},
+		priceStore: newPriceStore(curveLogger),
+	}
+	provider.setCurrencyPairToTickerAndCandlePair(currencyPairToCurvePair)
+
+	confirmedPairs, err := ConfirmPairAvailability(
+		provider,
+		provider.endpoints.Name,
+		provider.logger,
+		pairs...,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	provider.setSubscribedPairs(confirmedPairs...)
+
+	return provider, nil
+}
+
+// StartConnections starts polling the rates of the pools of the subscribed
+// pairs until the provider's context is canceled.
+func (p *CurveProvider) StartConnections() {
+	go func() {
+		ticker := time.NewTicker(curvePricePollInterval)
+		defer ticker.Stop()
+
+		for {
+			p.pollPrices()
+
+			select {
+			case <-p.ctx.Done():
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+}
+
+// SubscribeCurrencyPairs confirms the pools of the new currency pairs and
+// adds them to the providers subscribedPairs array
+func (p *CurveProvider) SubscribeCurrencyPairs(cps ...types.CurrencyPair) {
+	p.mtx.Lock()
+	defer p.mtx.Unlock()
+
+	confirmedPairs, err := ConfirmPairAvailability(
+		p,
+		p.endpoints.Name,
+		p.logger,
+		cps...,
+	)
+	if err != nil {
+		return
+	}
+
+	p.setSubscribedPairs(confirmedPairs...)
+}
+
+// pollPrices reads the rate of the pool of every subscribed pair. The pairs
+// whose rate failed to be read stop contributing prices until it is read
+// again.
+func (p *CurveProvider) pollPrices() {
+	p.mtx.Lock()
+	defer p.mtx.Unlock()
+
+	p.subscribedPairsMtx.RLock()
+	pairs := types.MapPairsToSlice(p.subscribedPairs)
+	p.subscribedPairsMtx.RUnlock()
+
+	for _, cp := range pairs {
+		symbol := currencyPairToCurvePair(cp)
+		price, err := p.queryPrice(symbol)
+		if err != nil {
+			p.disablePair(cp, err)
+			continue
+		}
+		p.setTickerPair(price, symbol)
+		p.setCandlePair(price, symbol)
+	}
+}
+
+// disablePair removes the ticker and candles of a pair, so it stops
+// contributing prices.
+func (p *CurveProvider) disablePair(cp types.CurrencyPair, err error) {
+	symbol := currencyPairToCurvePair(cp)
+
+	p.tickerMtx.Lock()
+	delete(p.tickers, symbol)
+	p.tickerMtx.Unlock()
+
+	p.candleMtx.Lock()
+	delete(p.candles, symbol)
+	p.candleMtx.Unlock()
+
+	TelemetryFailure(ProviderCurve, MessageTypeTicker)
+	p.logger.Error().
+		Err(err).
+		Str("pair", cp.String()).
+		Msg("failed to read pool rate; disabling pair until the next successful read")
+}
+
+// queryPrice reads the rate of the pool of a pair, from its price oracle if
+// the pool provides one for both coins and from get_dy otherwise.
+func (p *CurveProvider) queryPrice(symbol string) (curvePrice, error) {
+	pool, ok := p.endpoints.CurvePools[symbol]
+	if !ok {
+		return curvePrice{}, fmt.Errorf("curve: no pool configured for %s", symbol)
+	}
+
+	coins, ok := p.coins[symbol]
+	if !ok {
+		var err error
+		coins, err = p.queryCoins(pool)
+		if err != nil {
+			return curvePrice{}, err
+		}
+		p.coins[symbol] = coins
+	}
+
+	var (
+		rate *big.Rat
+		err  error
+	)
+	if coins.hasOracle(pool) {
+		rate, err = p.queryOracleRate(pool, coins.oracle)
+	} else {
+		rate, err = p.queryDyRate(pool, coins)
+	}
+	if err != nil {
+		return curvePrice{}, err
+	}
+
+	return newCurvePrice(rate)
+}
+
+// queryOracleRate returns the price of the base coin in the quote coin from
+// the oracle prices of both coins in coin 0.
+func (p *CurveProvider) queryOracleRate(pool CurvePool, oracle curveOracle) (*big.Rat, error) {
+	basePrice, err := p.queryOraclePrice(pool.Address, oracle, pool.BaseIndex)
+	if err != nil {
+		return nil, err
+	}
+	quotePrice, err := p.queryOraclePrice(pool.Address, oracle, pool.QuoteIndex)
+	if err != nil {
+		return nil, err
+	}
+	if quotePrice.Sign() == 0 {
+		return nil, fmt.Errorf("curve: no oracle price of coin %d", pool.QuoteIndex)
+	}
+	return new(big.Rat).Quo(basePrice, quotePrice), nil
+}
+
+// queryOraclePrice returns the oracle price of a coin of a pool in coin 0.
+func (p *CurveProvider) queryOraclePrice(address string, oracle curveOracle, index int) (*big.Rat, error) {
+	if index == 0 {
+		return big.NewRat(1, 1), nil
+	}
+
+	data := curvePriceOracle
+	if oracle == curveOracleIndexed {
+		data = encodeCurveCall(curvePriceOracleIndexed, big.NewInt(int64(index-1)))
+	}
+	result, err := p.call(address, data)
+	if err != nil {
+		return nil, err
+	}
+	price, err := decodeCurveUint(result)
+	if err != nil {
+		return nil, err
+	}
+	return new(big.Rat).SetFrac(price, curvePow10(curveOracleDecimals)), nil
+}
+
+// queryDyRate returns the amount of quote coin get_dy returns for one base
+// coin.
+func (p *CurveProvider) queryDyRate(pool CurvePool, coins curvePoolCoins) (*big.Rat, error) {
+	result, err := p.call(pool.Address, encodeCurveCall(
+		curveGetDy,
+		big.NewInt(int64(pool.BaseIndex)),
+		big.NewInt(int64(pool.QuoteIndex)),
+		curvePow10(coins.baseDecimals),
+	))
+	if err != nil {
+		return nil, err
+	}
+	dy, err := decodeCurveUint(result)
+	if err != nil {
+		return nil, err
+	}
+	return new(big.Rat).SetFrac(dy, curvePow10(coins.quoteDecimals)), nil
+}
+
+// queryCoins returns the price oracle of a pool and the decimals of its base
+// and quote coins.
+func (p *CurveProvider) queryCoins(pool CurvePool) (curvePoolCoins, error) {
+	var (
+		coins curvePoolCoins
+		err   error
+	)
+	if coins.baseDecimals, err = p.queryCoinDecimals(pool.Address, pool.BaseIndex); err != nil {
+		return curvePoolCoins{}, err
+	}
+	if coins.quoteDecimals, err = p.queryCoinDecimals(pool.Address, pool.QuoteIndex); err != nil {
+		return curvePoolCoins{}, err
+	}
+
+	// pools without an oracle revert its calls
+	switch {
+	case p.probe(pool.Address, encodeCurveCall(curvePriceOracleIndexed, big.NewInt(0))):
+		coins.oracle = curveOracleIndexed
+	case p.probe(pool.Address, curvePriceOracle):
+		coins.oracle = curveOracleSingle
+	default:
+		coins.oracle = curveOracleNone
+	}
+	return coins, nil
+}
+
+// probe returns whether a call to a contract returns a single word.
+func (p *CurveProvider) probe(to string, data string) bool {
+	result, err := p.call(to, data)
+	return err == nil && len(result) == evmWordSize
+}
+
+// queryCoinDecimals returns the decimals of the coin at an index of a pool.
+func (p *CurveProvider) queryCoinDecimals(address string, index int) (uint8, error) {
+	result, err := p.call(address, encodeCurveCall(curveCoins, big.NewInt(int64(index))))
+	if err != nil {
+		return 0, err
+	}
+	if len(result) != evmWordSize {
+		return 0, fmt.Errorf("curve: unexpected coin of %d bytes", len(result))
+	}
+	coin := "0x" + hex.EncodeToString(result[evmWordSize-20:])
+	if coin == curveNativeCoin {
+		return curveNativeCoinDecimals, nil
+	}
+
+	result, err = p.call(coin, curveDecimals)
+	if err != nil {
+		return 0, err
+	}
+	decimals, err := decodeCurveUint(result)
+	if err != nil {
+		return 0, err
+	}
+	if !decimals.IsUint64() || decimals.Uint64() > curveMaxDecimals {
+		return 0, fmt.Errorf("curve: unsupported decimals %s of coin %s", decimals, coin)
+	}
+	return uint8(decimals.Uint64()), nil
+}
+
+// call executes an eth_call of the given data on a contract at the latest
+// block and returns the decoded result.
+func (p *CurveProvider) call(to string, data string) ([]byte, error) {
+	return evmCall(p.ctx, p.client, p.endpoints.Rest, ProviderCurve, to, data)
+}
+
+// GetAvailablePairs returns all pairs to which the provider can subscribe,
+// being every pair whose pool returns its coins.
+// ex.: map["USDCUSDT" => {}, "STETHETH" => {}].
+func (p *CurveProvider) GetAvailablePairs() (map[string]struct{}, error) {
+	availablePairs := make(map[string]struct{}, len(p.endpoints.CurvePools))
+	for symbol, pool := range p.endpoints.CurvePools {
+		coins, err := p.queryCoins(pool)
+		if err != nil {
+			p.logger.Warn().
+				Err(err).
+				Str("pool", pool.Address).
+				Msg("failed to query pool")
+			continue
+		}
+		p.coins[symbol] = coins
+		availablePairs[symbol] = struct{}{}
+	}
+
+	return availablePairs, nil
+}
+
+// hasOracle returns whether the oracle of a pool prices both coins of a pair.
+func (c curvePoolCoins) hasOracle(pool CurvePool) bool {
+	switch c.oracle {
+	case curveOracleIndexed:
+		return true
+	case curveOracleSingle:
+		return pool.BaseIndex <= 1 && pool.QuoteIndex <= 1
+	default:
+		return false
+	}
+}
+
+func (cp curvePrice) toTickerPrice() (types.TickerPrice, error) {
+	return types.TickerPrice{
+		Price:  cp.price,
+		Volume: sdk.ZeroDec(),
+	}, nil
+}
+
+func (cp curvePrice) toCandlePrice() (types.CandlePrice, error) {
+	return types.CandlePrice{
+		Price:     cp.price,
+		Volume:    sdk.ZeroDec(),
+		TimeStamp: cp.timeStamp,
+	}, nil
+}
+
+// newCurvePrice converts the rate of a pool to a price.
+func newCurvePrice(rate *big.Rat) (curvePrice, error) {
+	price, err := types.ParseDec(rate.FloatString(sdk.Precision))
+	if err != nil {
+		return curvePrice{}, fmt.Errorf("curve: failed to parse rate: %w", err)
+	}
+	if !price.IsPositive() {
+		return curvePrice{}, fmt.Errorf("curve: no price available")
+	}
+	return curvePrice{
+		price:     price,
+		timeStamp: PastUnixTime(0),
+	}, nil
+}
+
+// encodeCurveCall encodes the call of a function taking unsigned integer or
+// non-negative int128 arguments.
+func encodeCurveCall(selector string, args ...*big.Int) string {
+	data := make([]byte, 0, len(args)*evmWordSize)
+	for _, arg := range args {
+		data = append(data, arg.FillBytes(make([]byte, evmWordSize))...)
+	}
+	return selector + hex.EncodeToString(data)
+}
+
+// decodeCurveUint decodes an ABI encoded unsigned integer.
+func decodeCurveUint(result []byte) (*big.Int, error) {
+	if len(result) != evmWordSize {
+		return nil, fmt.Errorf("curve: unexpected integer of %d bytes", len(result))
+	}
+	return new(big.Int).SetBytes(result), nil
+}
+
+// curvePow10 returns 10 to the power of decimals.
+func curvePow10(decimals uint8) *big.Int {
+	return new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(decimals)), nil)
+}
+
+// currencyPairToCurvePair receives a currency pair and return the symbol the
+// provider stores its prices by, ex.: USDCUSDT.
+func currencyPairToCurvePair(cp types.CurrencyPair) string {
+	return strings.ToUpper(cp.String())
+}
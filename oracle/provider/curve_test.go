@@ -0,0 +1,211 @@
+package provider
+
+import (
+	"context"
+	"encoding/hex"
+	"encoding/json"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/ojo-network/price-feeder/oracle/types"
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	curveTest3Pool = "0xbEbc44782C7dB0a1A60Cb6fe97d0b483032FF1C7"
+	curveTestSTETH = "0x21E27a5E5513D6e65C4f830167390997aA84843a"
+	curveTestDAI   = "0x6B175474E89094C44Da98b954EedeAC495271d0F"
+	curveTestUSDC  = "0xA0b86991c6218b36c1d19D4a2e9Eb0cE3606eB48"
+	curveTestUSDT  = "0xdAC17F958D2ee523a2206206994597C13D831ec7"
+	curveTestStETH = "0xae7ab96520DE3A18E5e111B5EaAb095312D7fE84"
+)
+
+// curveTestPool defines the state of a test pool.
+type curveTestPool struct {
+	coins []string
+	// dy are the amounts get_dy returns by its encoded arguments.
+	dy map[string]*big.Int
+	// oracle is the price of coin 1 in coin 0 of a pool with an indexed
+	// oracle, or nil if the pool has no oracle.
+	oracle *big.Int
+}
+
+type curveTestServer struct {
+	*httptest.Server
+
+	mtx   sync.Mutex
+	pools map[string]curveTestPool
+}
+
+func newCurveTestServer(t *testing.T) *curveTestServer {
+	ts := &curveTestServer{
+		pools: map[string]curveTestPool{
+			strings.ToLower(curveTest3Pool): {
+				coins: []string{curveTestDAI, curveTestUSDC, curveTestUSDT},
+				dy: map[string]*big.Int{
+					encodeCurveCall(curveGetDy, big.NewInt(1), big.NewInt(2), big.NewInt(1000000)): big.NewInt(999500),
+				},
+			},
+			strings.ToLower(curveTestSTETH): {
+				coins:  []string{curveNativeCoin, curveTestStETH},
+				oracle: big.NewInt(999500000000000000),
+			},
+		},
+	}
+	decimals := map[string]int64{
+		strings.ToLower(curveTestDAI):   18,
+		strings.ToLower(curveTestUSDC):  6,
+		strings.ToLower(curveTestUSDT):  6,
+		strings.ToLower(curveTestStETH): 18,
+	}
+
+	ts.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ts.mtx.Lock()
+		defer ts.mtx.Unlock()
+
+		var req struct {
+			Method string            `json:"method"`
+			Params []json.RawMessage `json:"params"`
+		}
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+		require.Equal(t, "eth_call", req.Method)
+
+		var call evmCallParams
+		require.NoError(t, json.Unmarshal(req.Params[0], &call))
+
+		var word *big.Int
+		if pool, ok := ts.pools[strings.ToLower(call.To)]; ok {
+			for i, coin := range pool.coins {
+				if call.Data == encodeCurveCall(curveCoins, big.NewInt(int64(i))) {
+					word = testAddressWord(t, coin)
+				}
+			}
+			if pool.oracle != nil && call.Data == encodeCurveCall(curvePriceOracleIndexed, big.NewInt(0)) {
+				word = pool.oracle
+			}
+			if dy, ok := pool.dy[call.Data]; ok {
+				word = dy
+			}
+		} else if d, ok := decimals[strings.ToLower(call.To)]; ok && call.Data == curveDecimals {
+			word = big.NewInt(d)
+		}
+		if word == nil {
+			_, _ = w.Write([]byte(`{"jsonrpc":"2.0","id":1,"error":{"code":-32000,"message":"execution reverted"}}`))
+			return
+		}
+
+		result := hex.EncodeToString(word.FillBytes(make([]byte, evmWordSize)))
+		_, _ = w.Write([]byte(`{"jsonrpc":"2.0","id":1,"result":"0x` + result + `"}`))
+	}))
+	t.Cleanup(ts.Close)
+
+	return ts
+}
+
+func TestCurveProvider_GetTickerPrices(t *testing.T) {
+	server := newCurveTestServer(t)
+
+	usdcusdt := types.CurrencyPair{Base: "USDC", Quote: "USDT"}
+	stetheth := types.CurrencyPair{Base: "STETH", Quote: "ETH"}
+	ethsteth := types.CurrencyPair{Base: "ETH", Quote: "STETH"}
+	p, err := NewCurveProvider(
+		context.TODO(),
+		zerolog.Nop(),
+		Endpoint{
+			Name: ProviderCurve,
+			Rest: server.URL,
+			CurvePools: map[string]CurvePool{
+				"USDCUSDT": {Address: curveTest3Pool, BaseIndex: 1, QuoteIndex: 2},
+				"STETHETH": {Address: curveTestSTETH, BaseIndex: 1, QuoteIndex: 0},
+				"ETHSTETH": {Address: curveTestSTETH, BaseIndex: 0, QuoteIndex: 1},
+				// the pool holds no fourth coin
+				"DAIFOO": {Address: curveTest3Pool, BaseIndex: 0, QuoteIndex: 3},
+			},
+		},
+		usdcusdt,
+		stetheth,
+		ethsteth,
+		types.CurrencyPair{Base: "DAI", Quote: "FOO"},
+	)
+	require.NoError(t, err)
+	require.Len(t, p.subscribedPairs, 3)
+	require.Equal(t, curvePoolCoins{oracle: curveOracleNone, baseDecimals: 6, quoteDecimals: 6}, p.coins["USDCUSDT"])
+	require.Equal(t, curvePoolCoins{oracle: curveOracleIndexed, baseDecimals: 18, quoteDecimals: 18}, p.coins["STETHETH"])
+
+	t.Run("valid_request_multi_ticker", func(t *testing.T) {
+		p.pollPrices()
+
+		prices, err := p.GetTickerPrices(usdcusdt, stetheth, ethsteth)
+		require.NoError(t, err)
+		require.Len(t, prices, 3)
+
+		// the 3pool has no oracle and is priced by get_dy
+		require.Equal(t, sdk.MustNewDecFromStr("0.9995"), prices[usdcusdt].Price)
+		require.Equal(t, sdk.ZeroDec(), prices[usdcusdt].Volume)
+
+		// the stETH pool is priced by its oracle either way
+		require.Equal(t, sdk.MustNewDecFromStr("0.9995"), prices[stetheth].Price)
+		require.Equal(t, sdk.MustNewDecFromStr("1.000500250125062531"), prices[ethsteth].Price)
+
+		candles, err := p.GetCandlePrices(usdcusdt)
+		require.NoError(t, err)
+		require.Len(t, candles[usdcusdt], 1)
+		require.Equal(t, sdk.MustNewDecFromStr("0.9995"), candles[usdcusdt][0].Price)
+	})
+
+	t.Run("rpc_failure_disables_pair", func(t *testing.T) {
+		server.mtx.Lock()
+		pool := server.pools[strings.ToLower(curveTest3Pool)]
+		dy := pool.dy
+		pool.dy = nil
+		server.pools[strings.ToLower(curveTest3Pool)] = pool
+		server.mtx.Unlock()
+
+		p.pollPrices()
+
+		prices, err := p.GetTickerPrices(usdcusdt, stetheth, ethsteth)
+		require.NoError(t, err)
+		require.Len(t, prices, 2)
+		require.NotContains(t, prices, usdcusdt)
+
+		server.mtx.Lock()
+		pool.dy = dy
+		server.pools[strings.ToLower(curveTest3Pool)] = pool
+		server.mtx.Unlock()
+
+		p.pollPrices()
+
+		prices, err = p.GetTickerPrices(usdcusdt, stetheth, ethsteth)
+		require.NoError(t, err)
+		require.Len(t, prices, 3)
+	})
+}
+
+func TestCurvePoolCoins_hasOracle(t *testing.T) {
+	pool := CurvePool{BaseIndex: 1, QuoteIndex: 0}
+	require.False(t, curvePoolCoins{oracle: curveOracleNone}.hasOracle(pool))
+	require.True(t, curvePoolCoins{oracle: curveOracleSingle}.hasOracle(pool))
+	require.True(t, curvePoolCoins{oracle: curveOracleIndexed}.hasOracle(pool))
+
+	// a single oracle only prices coin 1 in coin 0
+	pool = CurvePool{BaseIndex: 2, QuoteIndex: 0}
+	require.False(t, curvePoolCoins{oracle: curveOracleSingle}.hasOracle(pool))
+	require.True(t, curvePoolCoins{oracle: curveOracleIndexed}.hasOracle(pool))
+}
+
+func TestEncodeCurveCall(t *testing.T) {
+	require.Equal(
+		t,
+		"0x5e0d443f"+
+			"0000000000000000000000000000000000000000000000000000000000000001"+
+			"0000000000000000000000000000000000000000000000000000000000000002"+
+			"00000000000000000000000000000000000000000000000000000000000f4240",
+		encodeCurveCall(curveGetDy, big.NewInt(1), big.NewInt(2), big.NewInt(1000000)),
+	)
+}
@@ -0,0 +1,311 @@
+package provider
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/ojo-network/price-feeder/oracle/types"
+	"github.com/rs/zerolog"
+)
+
+const (
+	dydxRestHost          = "https://indexer.dydx.trade"
+	dydxMarketsPath       = "/v4/perpetualMarkets"
+	dydxPricePollInterval = 10 * time.Second
+
+	// dydxMarketActive is the status of a market which is trading.
+	dydxMarketActive = "ACTIVE"
+)
+
+var _ Provider = (*DydxProvider)(nil)
+
+type (
+	// DydxProvider defines an Oracle provider which polls the oracle prices of
+	// dYdX v4 perpetual markets from its indexer. Each pair is mapped to its
+	// market ticker, ex. BTC-USD, by the markets set from the dydx section of
+	// the config. The markets are queried together every poll interval, and a
+	// pair whose market is missing, not active or without a price stops
+	// contributing prices until it is priced again, while the other pairs are
+	// unaffected. Tickers carry the 24h volume of the market in the base
+	// asset, and candles carry no volume.
+	//
+	// REF: https://docs.dydx.exchange/api_integration-indexer/indexer_api
+	DydxProvider struct {
+		ctx       context.Context
+		logger    zerolog.Logger
+		mtx       sync.RWMutex
+		endpoints Endpoint
+		client    *http.Client
+
+		priceStore
+	}
+
+	// DydxMarketsResponse defines the response structure of the dYdX indexer
+	// perpetual markets query.
+	DydxMarketsResponse struct {
+		Markets map[string]DydxMarket `json:"markets"`
+	}
+
+	// DydxMarket defines the response structure of a perpetual market. The
+	// 24h volume is quoted in USD.
+	DydxMarket struct {
+		Ticker      string `json:"ticker"`
+		Status      string `json:"status"`
+		OraclePrice string `json:"oraclePrice"`
+		Volume24H   string `json:"volume24H"`
+	}
+
+	// dydxPrice defines the oracle price and 24h base volume of a market at
+	// the time it was polled.
+	dydxPrice struct {
+		price     sdk.Dec
+		volume    sdk.Dec
+		timeStamp int64
+	}
+)
+
+func NewDydxProvider(
+	ctx context.Context,
+	logger zerolog.Logger,
+	endpoints Endpoint,
+	pairs ...types.CurrencyPair,
+) (*DydxProvider, error) {
+	if endpoints.Name != ProviderDydx {
+		endpoints = Endpoint{
+			Name: ProviderDydx,
+			Rest: dydxRestHost,
+		}
+	}
+
+	dydxLogger := logger.With().Str("provider", string(ProviderDydx)).Logger()
+
+	provider := &DydxProvider{
+		ctx:        ctx,
+		logger:     dydxLogger,
+		endpoints:  endpoints,
+		client:     endpoints.httpClient(),
+		priceStore: newPriceStore(dydxLogger),
+	}
+	provider.setCurrencyPairToTickerAndCandlePair(currencyPairToDydxPair)
+
+	confirmedPairs, err := ConfirmPairAvailability(
+		provider,
+		provider.endpoints.Name,
+		provider.logger,
+		pairs...,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	provider.setSubscribedPairs(confirmedPairs...)
+
+	return provider, nil
+}
+
+// StartConnections starts polling the markets of the subscribed pairs every
+// poll interval until the provider's context is canceled.
+func (p *DydxProvider) StartConnections() {
+	go func() {
+		ticker := time.NewTicker(p.pollInterval())
+		defer ticker.Stop()
+
+		for {
+			p.pollPrices()
+
+			select {
+			case <-p.ctx.Done():
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+}
+
+// SubscribeCurrencyPairs confirms the markets of the new currency pairs and
+// adds them to the providers subscribedPairs array
+func (p *DydxProvider) SubscribeCurrencyPairs(cps ...types.CurrencyPair) {
+	p.mtx.Lock()
+	defer p.mtx.Unlock()
+
+	confirmedPairs, err := ConfirmPairAvailability(
+		p,
+		p.endpoints.Name,
+		p.logger,
+		cps...,
+	)
+	if err != nil {
+		return
+	}
+
+	p.setSubscribedPairs(confirmedPairs...)
+}
+
+// pollPrices queries the markets and prices every subscribed pair from its
+// market. The pairs without a price, or all of them if the query failed, stop
+// contributing prices until they are priced again.
+func (p *DydxProvider) pollPrices() {
+	p.mtx.Lock()
+	defer p.mtx.Unlock()
+
+	p.subscribedPairsMtx.RLock()
+	pairs := types.MapPairsToSlice(p.subscribedPairs)
+	p.subscribedPairsMtx.RUnlock()
+
+	resp, err := p.getMarkets()
+	if err != nil {
+		for _, cp := range pairs {
+			p.disablePair(cp, err)
+		}
+		return
+	}
+
+	for _, cp := range pairs {
+		symbol := currencyPairToDydxPair(cp)
+		price, err := p.marketPrice(resp, symbol)
+		if err != nil {
+			p.disablePair(cp, err)
+			continue
+		}
+		p.setTickerPair(price, symbol)
+		p.setCandlePair(price, symbol)
+	}
+}
+
+// disablePair removes the ticker and candles of a pair, so it stops
+// contributing prices.
+func (p *DydxProvider) disablePair(cp types.CurrencyPair, err error) {
+	symbol := currencyPairToDydxPair(cp)
+
+	p.tickerMtx.Lock()
+	delete(p.tickers, symbol)
+	p.tickerMtx.Unlock()
+
+	p.candleMtx.Lock()
+	delete(p.candles, symbol)
+	p.candleMtx.Unlock()
+
+	TelemetryFailure(ProviderDydx, MessageTypeTicker)
+	p.logger.Error().
+		Err(err).
+		Str("pair", cp.String()).
+		Msg("failed to query market price; disabling pair until the next successful query")
+}
+
+// marketPrice returns the price of the market of a pair, failing if no market
+// is configured for it or if it is not active.
+func (p *DydxProvider) marketPrice(resp DydxMarketsResponse, symbol string) (dydxPrice, error) {
+	ticker, ok := p.endpoints.DydxMarkets[symbol]
+	if !ok {
+		return dydxPrice{}, fmt.Errorf("dydx: no market configured for %s", symbol)
+	}
+	market, ok := resp.Markets[ticker]
+	if !ok {
+		return dydxPrice{}, fmt.Errorf("dydx: market %s not found", ticker)
+	}
+	if market.Status != dydxMarketActive {
+		return dydxPrice{}, fmt.Errorf("dydx: market %s is %s", ticker, strings.ToLower(market.Status))
+	}
+	return newDydxPrice(market)
+}
+
+// getMarkets queries the perpetual markets from the indexer.
+func (p *DydxProvider) getMarkets() (DydxMarketsResponse, error) {
+	req, err := http.NewRequestWithContext(p.ctx, http.MethodGet, p.endpoints.Rest+dydxMarketsPath, nil)
+	if err != nil {
+		return DydxMarketsResponse{}, err
+	}
+
+	httpResp, err := p.client.Do(req)
+	if err != nil {
+		return DydxMarketsResponse{}, err
+	}
+	defer httpResp.Body.Close()
+
+	if httpResp.StatusCode != http.StatusOK {
+		return DydxMarketsResponse{}, fmt.Errorf("dydx: unexpected status %s querying markets", httpResp.Status)
+	}
+
+	var resp DydxMarketsResponse
+	if err := json.NewDecoder(httpResp.Body).Decode(&resp); err != nil {
+		return DydxMarketsResponse{}, fmt.Errorf("dydx: failed to decode markets: %w", err)
+	}
+	return resp, nil
+}
+
+// pollInterval returns the interval the markets are polled at.
+func (p *DydxProvider) pollInterval() time.Duration {
+	if p.endpoints.PollInterval > 0 {
+		return p.endpoints.PollInterval
+	}
+	return dydxPricePollInterval
+}
+
+// GetAvailablePairs returns all pairs to which the provider can subscribe,
+// being every pair whose configured market is active.
+// ex.: map["BTCUSD" => {}, "ETHUSD" => {}].
+func (p *DydxProvider) GetAvailablePairs() (map[string]struct{}, error) {
+	resp, err := p.getMarkets()
+	if err != nil {
+		return nil, err
+	}
+
+	availablePairs := make(map[string]struct{}, len(p.endpoints.DydxMarkets))
+	for symbol, ticker := range p.endpoints.DydxMarkets {
+		if market, ok := resp.Markets[ticker]; ok && market.Status == dydxMarketActive {
+			availablePairs[symbol] = struct{}{}
+		}
+	}
+
+	return availablePairs, nil
+}
+
+func (dp dydxPrice) toTickerPrice() (types.TickerPrice, error) {
+	return types.TickerPrice{
+		Price:  dp.price,
+		Volume: dp.volume,
+	}, nil
+}
+
+func (dp dydxPrice) toCandlePrice() (types.CandlePrice, error) {
+	return types.CandlePrice{
+		Price:     dp.price,
+		Volume:    sdk.ZeroDec(),
+		TimeStamp: dp.timeStamp,
+	}, nil
+}
+
+// newDydxPrice parses the oracle price of a market, and converts its 24h USD
+// volume to the base asset.
+func newDydxPrice(market DydxMarket) (dydxPrice, error) {
+	price, err := sdk.NewDecFromStr(market.OraclePrice)
+	if err != nil {
+		return dydxPrice{}, fmt.Errorf("dydx: failed to parse oracle price of %s: %w", market.Ticker, err)
+	}
+	if !price.IsPositive() {
+		return dydxPrice{}, fmt.Errorf("dydx: no oracle price of %s", market.Ticker)
+	}
+
+	volume := sdk.ZeroDec()
+	if quoteVolume, err := sdk.NewDecFromStr(market.Volume24H); err == nil && quoteVolume.IsPositive() {
+		volume = quoteVolume.Quo(price)
+	}
+
+	return dydxPrice{
+		price:     price,
+		volume:    volume,
+		timeStamp: PastUnixTime(0),
+	}, nil
+}
+
+// currencyPairToDydxPair receives a currency pair and return the symbol the
+// provider stores its prices by, ex.: BTCUSD.
+func currencyPairToDydxPair(cp types.CurrencyPair) string {
+	return strings.ToUpper(cp.String())
+}
@@ -0,0 +1,151 @@
+package provider
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/ojo-network/price-feeder/oracle/types"
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/require"
+)
+
+type dydxTestServer struct {
+	*httptest.Server
+
+	mtx     sync.Mutex
+	status  int
+	markets map[string]DydxMarket
+}
+
+func newDydxTestServer(t *testing.T) *dydxTestServer {
+	ts := &dydxTestServer{
+		status: http.StatusOK,
+		markets: map[string]DydxMarket{
+			"BTC-USD":  {Ticker: "BTC-USD", Status: "ACTIVE", OraclePrice: "65000.5", Volume24H: "130001000"},
+			"ETH-USD":  {Ticker: "ETH-USD", Status: "ACTIVE", OraclePrice: "3200", Volume24H: "0"},
+			"LUNA-USD": {Ticker: "LUNA-USD", Status: "FINAL_SETTLEMENT", OraclePrice: "0.5", Volume24H: "0"},
+		},
+	}
+
+	ts.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ts.mtx.Lock()
+		defer ts.mtx.Unlock()
+
+		require.Equal(t, dydxMarketsPath, r.URL.Path)
+		if ts.status != http.StatusOK {
+			w.WriteHeader(ts.status)
+			return
+		}
+		require.NoError(t, json.NewEncoder(w).Encode(DydxMarketsResponse{Markets: ts.markets}))
+	}))
+	t.Cleanup(ts.Close)
+	return ts
+}
+
+func TestDydxProvider_GetTickerPrices(t *testing.T) {
+	server := newDydxTestServer(t)
+
+	btcusd := types.CurrencyPair{Base: "BTC", Quote: "USD"}
+	ethusd := types.CurrencyPair{Base: "ETH", Quote: "USD"}
+	p, err := NewDydxProvider(
+		context.TODO(),
+		zerolog.Nop(),
+		Endpoint{
+			Name: ProviderDydx,
+			Rest: server.URL,
+			DydxMarkets: map[string]string{
+				"BTCUSD":  "BTC-USD",
+				"ETHUSD":  "ETH-USD",
+				"LUNAUSD": "LUNA-USD",
+			},
+			PollInterval: time.Minute,
+		},
+		btcusd,
+		ethusd,
+		// the market is settled, and the pair has no market configured
+		types.CurrencyPair{Base: "LUNA", Quote: "USD"},
+		types.CurrencyPair{Base: "ATOM", Quote: "USD"},
+	)
+	require.NoError(t, err)
+	require.Len(t, p.subscribedPairs, 2)
+	require.Equal(t, time.Minute, p.pollInterval())
+
+	t.Run("valid_request_multi_ticker", func(t *testing.T) {
+		p.pollPrices()
+
+		prices, err := p.GetTickerPrices(btcusd, ethusd)
+		require.NoError(t, err)
+		require.Len(t, prices, 2)
+
+		// the 24h volume is converted to the base asset
+		require.Equal(t, sdk.MustNewDecFromStr("65000.5"), prices[btcusd].Price)
+		require.Equal(t, sdk.MustNewDecFromStr("2000"), prices[btcusd].Volume)
+		require.Equal(t, sdk.MustNewDecFromStr("3200"), prices[ethusd].Price)
+		require.Equal(t, sdk.ZeroDec(), prices[ethusd].Volume)
+
+		candles, err := p.GetCandlePrices(btcusd)
+		require.NoError(t, err)
+		require.Len(t, candles[btcusd], 1)
+		require.Equal(t, sdk.MustNewDecFromStr("65000.5"), candles[btcusd][0].Price)
+	})
+
+	t.Run("missing_market_disables_pair", func(t *testing.T) {
+		server.mtx.Lock()
+		market := server.markets["ETH-USD"]
+		delete(server.markets, "ETH-USD")
+		server.mtx.Unlock()
+
+		p.pollPrices()
+
+		prices, err := p.GetTickerPrices(btcusd, ethusd)
+		require.NoError(t, err)
+		require.Len(t, prices, 1)
+		require.NotContains(t, prices, ethusd)
+
+		server.mtx.Lock()
+		server.markets["ETH-USD"] = market
+		server.mtx.Unlock()
+
+		p.pollPrices()
+
+		prices, err = p.GetTickerPrices(btcusd, ethusd)
+		require.NoError(t, err)
+		require.Len(t, prices, 2)
+	})
+
+	t.Run("api_failure_disables_pairs", func(t *testing.T) {
+		server.mtx.Lock()
+		server.status = http.StatusServiceUnavailable
+		server.mtx.Unlock()
+
+		p.pollPrices()
+
+		prices, err := p.GetTickerPrices(btcusd, ethusd)
+		require.NoError(t, err)
+		require.Empty(t, prices)
+
+		server.mtx.Lock()
+		server.status = http.StatusOK
+		server.mtx.Unlock()
+	})
+}
+
+func TestNewDydxPrice(t *testing.T) {
+	_, err := newDydxPrice(DydxMarket{Ticker: "BTC-USD", OraclePrice: "0"})
+	require.Error(t, err)
+
+	_, err = newDydxPrice(DydxMarket{Ticker: "BTC-USD", OraclePrice: "n/a"})
+	require.Error(t, err)
+
+	// an invalid volume is ignored
+	price, err := newDydxPrice(DydxMarket{Ticker: "BTC-USD", OraclePrice: "100", Volume24H: "n/a"})
+	require.NoError(t, err)
+	require.Equal(t, sdk.NewDec(100), price.price)
+	require.Equal(t, sdk.ZeroDec(), price.volume)
+}
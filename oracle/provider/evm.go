@@ -0,0 +1,95 @@
+package provider
+
+import (
+	"bytes"
+	"context"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/ojo-network/price-feeder/oracle/types"
+)
+
+// evmWordSize is the size of an ABI encoded word.
+const evmWordSize = 32
+
+type (
+	// EVMRPCRequest defines the request structure of a JSON-RPC call.
+	EVMRPCRequest struct {
+		JSONRPC string        `json:"jsonrpc"`
+		ID      int           `json:"id"`
+		Method  string        `json:"method"`
+		Params  []interface{} `json:"params"`
+	}
+
+	// EVMRPCResponse defines the response structure of a JSON-RPC call.
+	EVMRPCResponse struct {
+		Result string       `json:"result"`
+		Error  *EVMRPCError `json:"error"`
+	}
+
+	// EVMRPCError defines the error of a failed JSON-RPC call.
+	EVMRPCError struct {
+		Code    int    `json:"code"`
+		Message string `json:"message"`
+	}
+
+	// evmCallParams defines the parameters of an eth_call.
+	evmCallParams struct {
+		To   string `json:"to"`
+		Data string `json:"data"`
+	}
+)
+
+// evmCall executes an eth_call of the given data on a contract at the latest
+// block through an EVM JSON-RPC endpoint and returns the decoded result.
+func evmCall(
+	ctx context.Context,
+	client *http.Client,
+	rpc string,
+	providerName types.ProviderName,
+	to string,
+	data string,
+) ([]byte, error) {
+	bz, err := json.Marshal(EVMRPCRequest{
+		JSONRPC: "2.0",
+		ID:      1,
+		Method:  "eth_call",
+		Params:  []interface{}{evmCallParams{To: to, Data: data}, "latest"},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, rpc, bytes.NewReader(bz))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	httpResp, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer httpResp.Body.Close()
+
+	if httpResp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("%s: unexpected status %s calling %s", providerName, httpResp.Status, to)
+	}
+
+	var resp EVMRPCResponse
+	if err := json.NewDecoder(httpResp.Body).Decode(&resp); err != nil {
+		return nil, fmt.Errorf("%s: failed to decode call result: %w", providerName, err)
+	}
+	if resp.Error != nil {
+		return nil, fmt.Errorf("%s: call to %s failed: %s (%d)", providerName, to, resp.Error.Message, resp.Error.Code)
+	}
+
+	result, err := hex.DecodeString(strings.TrimPrefix(resp.Result, "0x"))
+	if err != nil {
+		return nil, fmt.Errorf("%s: failed to decode call result: %w", providerName, err)
+	}
+	return result, nil
+}
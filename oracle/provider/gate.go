@@ -4,12 +4,12 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
-	"net/http"
 	"net/url"
 	"strings"
 	"sync"
 	"time"
 
+	sdk "github.com/cosmos/cosmos-sdk/types"
 	"github.com/gorilla/websocket"
 	"github.com/rs/zerolog"
 
@@ -22,9 +22,16 @@ const (
 	gatePingCheck = time.Second * 28 // should be < 30
 	gateRestHost  = "https://api.gateio.ws"
 	gateRestPath  = "/api/v4/spot/currency_pairs"
+
+	// gateDepthLimit is the number of price levels of each side of the order
+	// books subscribed to.
+	gateDepthLimit = 30
 )
 
-var _ Provider = (*GateProvider)(nil)
+var (
+	_ Provider               = (*GateProvider)(nil)
+	_ OrderBookDepthProvider = (*GateProvider)(nil)
+)
 
 type (
 	// GateProvider defines an Oracle provider implemented by the Gate public
@@ -38,20 +45,24 @@ type (
 		mtx            sync.RWMutex
 		endpoints      Endpoint
 
+		// orderBooks holds the order books of the pairs if their depth is
+		// measured, nil otherwise.
+		orderBooks *orderBookStore
+
 		priceStore
 	}
 
 	GateTicker struct {
-		Last   string `json:"last"`       // Last traded price ex.: 43508.9
-		Vol    string `json:"baseVolume"` // Trading volume ex.: 11159.87127845
-		Symbol string `json:"symbol"`     // Symbol ex.: ATOM_UDST
+		Last   types.Number `json:"last"`       // Last traded price ex.: 43508.9
+		Vol    types.Number `json:"baseVolume"` // Trading volume ex.: 11159.87127845
+		Symbol string       `json:"symbol"`     // Symbol ex.: ATOM_UDST
 	}
 
 	GateCandle struct {
-		Close     string // Closing price
-		TimeStamp int64  // Unix timestamp
-		Volume    string // Total candle volume
-		Symbol    string // Total symbol
+		Close     types.Number // Closing price
+		TimeStamp int64        // Unix timestamp
+		Volume    types.Number // Total candle volume
+		Symbol    string       // Total symbol
 	}
 
 	// GateTickerSubscriptionMsg Msg to subscribe all the tickers channels.
@@ -83,6 +94,30 @@ type (
 		Params [][]interface{} `json:"params"`
 	}
 
+	// GateDepthSubscriptionMsg Msg to subscribe to an order book channel.
+	GateDepthSubscriptionMsg struct {
+		Method string        `json:"method"` // depth.subscribe
+		Params []interface{} `json:"params"` // ex.: ["BOT_USDT", 30, "0"]
+		ID     uint16        `json:"id"`     // identify messages going back and forth
+	}
+
+	// GateDepthResponse defines the response body for gate order books. The
+	// Params are whether the update is a full snapshot, the book and the
+	// symbol.
+	//
+	// REF: https://www.gate.io/docs/websocket/index.html
+	GateDepthResponse struct {
+		Method string            `json:"method"`
+		Params []json.RawMessage `json:"params"`
+	}
+
+	// GateDepth defines the price levels of a gate order book update, as
+	// [price, amount] pairs.
+	GateDepth struct {
+		Asks [][]types.Number `json:"asks"`
+		Bids [][]types.Number `json:"bids"`
+	}
+
 	// GateEvent defines the response body for gate subscription statuses.
 	GateEvent struct {
 		ID     int             `json:"id"`     // subscription id, ex.: 123
@@ -109,9 +144,11 @@ func NewGateProvider(
 ) (*GateProvider, error) {
 	if endpoints.Name != ProviderGate {
 		endpoints = Endpoint{
-			Name:      ProviderGate,
-			Rest:      gateRestHost,
-			Websocket: gateWSHost,
+			Name:              ProviderGate,
+			Rest:              gateRestHost,
+			Websocket:         gateWSHost,
+			SilenceTimeout:    endpoints.SilenceTimeout,
+			ReconnectCooldown: endpoints.ReconnectCooldown,
 		}
 	}
 
@@ -131,6 +168,14 @@ func NewGateProvider(
 	}
 	provider.setCurrencyPairToTickerAndCandlePair(currencyPairToGatePair)
 
+	depthBand, ok, err := endpoints.OrderBookDepthBand()
+	if err != nil {
+		return nil, err
+	}
+	if ok {
+		provider.orderBooks = newOrderBookStore(depthBand)
+	}
+
 	confirmedPairs, err := ConfirmPairAvailability(
 		provider,
 		provider.endpoints.Name,
@@ -151,6 +196,11 @@ func NewGateProvider(
 		provider.messageReceived,
 		defaultPingDuration,
 		websocket.PingMessage,
+		endpoints.SilenceTimeout,
+		endpoints.ReconnectCooldown,
+		&provider.priceStore,
+		endpoints.tlsConfig,
+		endpoints.header,
 		gateLogger,
 	)
 
@@ -167,6 +217,9 @@ func (p *GateProvider) getSubscriptionMsgs(cps ...types.CurrencyPair) []interfac
 		gatePair := currencyPairToGatePair(cp)
 		subscriptionMsgs = append(subscriptionMsgs, newGateTickerSubscription(gatePair))
 		subscriptionMsgs = append(subscriptionMsgs, newGateCandleSubscription(gatePair))
+		if p.orderBooks != nil {
+			subscriptionMsgs = append(subscriptionMsgs, newGateDepthSubscription(gatePair))
+		}
 	}
 	return subscriptionMsgs
 }
@@ -210,6 +263,7 @@ func (p *GateProvider) messageReceived(_ int, _ *WebsocketConnection, bz []byte)
 		gateErr   error
 		tickerErr error
 		candleErr error
+		depthErr  error
 	)
 
 	gateErr = json.Unmarshal(bz, &gateEvent)
@@ -234,12 +288,19 @@ func (p *GateProvider) messageReceived(_ int, _ *WebsocketConnection, bz []byte)
 		return
 	}
 
+	depthErr = p.messageReceivedDepth(bz)
+	if depthErr == nil {
+		return
+	}
+
 	p.logger.Error().
 		Int("length", len(bz)).
 		AnErr("ticker", tickerErr).
 		AnErr("candle", candleErr).
+		AnErr("depth", depthErr).
 		AnErr("event", gateErr).
 		Msg("Error on receive message")
+	telemetryDecodeError(p.logger, ProviderGate, bz)
 }
 
 // messageReceivedTickerPrice handles the ticker price msg.
@@ -300,15 +361,15 @@ func (candle *GateCandle) UnmarshalParams(params [][]interface{}) error {
 	}
 	candle.TimeStamp = time
 
-	close, ok := tmp[1].(string)
-	if !ok {
-		return fmt.Errorf("close field must be a string")
+	close, err := types.NewNumber(tmp[1])
+	if err != nil {
+		return fmt.Errorf("invalid close field: %w", err)
 	}
 	candle.Close = close
 
-	volume, ok := tmp[5].(string)
-	if !ok {
-		return fmt.Errorf("volume field must be a string")
+	volume, err := types.NewNumber(tmp[5])
+	if err != nil {
+		return fmt.Errorf("invalid volume field: %w", err)
 	}
 	candle.Volume = volume
 
@@ -345,6 +406,67 @@ func (p *GateProvider) messageReceivedCandle(bz []byte) error {
 	return nil
 }
 
+// messageReceivedDepth handles the order book msg, a full snapshot of the book
+// or an update of its changed levels.
+//
+// REF: https://www.gate.io/docs/websocket/index.html
+func (p *GateProvider) messageReceivedDepth(bz []byte) error {
+	var depthMessage GateDepthResponse
+	if err := json.Unmarshal(bz, &depthMessage); err != nil {
+		return err
+	}
+
+	if depthMessage.Method != "depth.update" {
+		return fmt.Errorf("message is not a depth update")
+	}
+	if p.orderBooks == nil {
+		return nil
+	}
+	if len(depthMessage.Params) != 3 {
+		return fmt.Errorf("wrong number of fields in depth update")
+	}
+
+	var (
+		clean  bool
+		depth  GateDepth
+		symbol string
+	)
+	if err := json.Unmarshal(depthMessage.Params[0], &clean); err != nil {
+		return fmt.Errorf("invalid clean field: %w", err)
+	}
+	if err := json.Unmarshal(depthMessage.Params[1], &depth); err != nil {
+		return fmt.Errorf("invalid depth field: %w", err)
+	}
+	if err := json.Unmarshal(depthMessage.Params[2], &symbol); err != nil {
+		return fmt.Errorf("symbol field must be a string: %w", err)
+	}
+
+	bids, err := parseOrderBookLevels(depth.Bids)
+	if err != nil {
+		return err
+	}
+	asks, err := parseOrderBookLevels(depth.Asks)
+	if err != nil {
+		return err
+	}
+
+	if clean {
+		p.orderBooks.setBook(symbol, bids, asks)
+	} else {
+		p.orderBooks.updateBook(symbol, bids, asks)
+	}
+	return nil
+}
+
+// GetOrderBookDepths returns the depth of the order books of the pairs, or
+// nil if the order books are not subscribed to.
+func (p *GateProvider) GetOrderBookDepths(cps ...types.CurrencyPair) map[types.CurrencyPair]sdk.Dec {
+	if p.orderBooks == nil {
+		return nil
+	}
+	return p.orderBooks.depths(currencyPairToGatePair, cps...)
+}
+
 // setSubscribedPairs sets N currency pairs to the map of subscribed pairs.
 func (p *GateProvider) setSubscribedPairs(cps ...types.CurrencyPair) {
 	for _, cp := range cps {
@@ -354,7 +476,7 @@ func (p *GateProvider) setSubscribedPairs(cps ...types.CurrencyPair) {
 
 // GetAvailablePairs returns all pairs to which the provider can subscribe.
 func (p *GateProvider) GetAvailablePairs() (map[string]struct{}, error) {
-	resp, err := http.Get(p.endpoints.Rest + gateRestPath)
+	resp, err := p.endpoints.httpClient().Get(p.endpoints.Rest + gateRestPath)
 	if err != nil {
 		return nil, err
 	}
@@ -378,13 +500,13 @@ func (p *GateProvider) GetAvailablePairs() (map[string]struct{}, error) {
 }
 
 func (ticker GateTicker) toTickerPrice() (types.TickerPrice, error) {
-	return types.NewTickerPrice(ticker.Last, ticker.Vol)
+	return types.NewTickerPrice(ticker.Last.String(), ticker.Vol.String())
 }
 
 func (candle GateCandle) toCandlePrice() (types.CandlePrice, error) {
 	return types.NewCandlePrice(
-		candle.Close,
-		candle.Volume,
+		candle.Close.String(),
+		candle.Volume.String(),
 		candle.TimeStamp,
 	)
 }
@@ -416,3 +538,17 @@ func newGateCandleSubscription(gatePair string) GateCandleSubscriptionMsg {
 		ID:     2,
 	}
 }
+
+// newGateDepthSubscription returns a new subscription topic for order books.
+func newGateDepthSubscription(gatePair string) GateDepthSubscriptionMsg {
+	params := []interface{}{
+		gatePair,       // currency pair ex. "ATOM_USDT"
+		gateDepthLimit, // number of price levels
+		"0",            // price precision, merged levels disabled
+	}
+	return GateDepthSubscriptionMsg{
+		Method: "depth.subscribe",
+		Params: params,
+		ID:     3,
+	}
+}
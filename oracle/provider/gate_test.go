@@ -6,6 +6,7 @@ import (
 	"testing"
 
 	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/gorilla/websocket"
 	"github.com/ojo-network/price-feeder/oracle/types"
 	"github.com/rs/zerolog"
 	"github.com/stretchr/testify/require"
@@ -27,8 +28,8 @@ func TestGateProvider_GetTickerPrices(t *testing.T) {
 		tickerMap := map[string]GateTicker{}
 		tickerMap["ATOM_USDT"] = GateTicker{
 			Symbol: "ATOM_USDT",
-			Last:   lastPrice,
-			Vol:    volume,
+			Last:   types.Number(lastPrice),
+			Vol:    types.Number(volume),
 		}
 
 		for _, ticker := range tickerMap {
@@ -50,14 +51,14 @@ func TestGateProvider_GetTickerPrices(t *testing.T) {
 		tickerMap := map[string]GateTicker{}
 		tickerMap["ATOM_USDT"] = GateTicker{
 			Symbol: "ATOM_USDT",
-			Last:   lastPriceAtom,
-			Vol:    volume,
+			Last:   types.Number(lastPriceAtom),
+			Vol:    types.Number(volume),
 		}
 
 		tickerMap["OJO_USDT"] = GateTicker{
 			Symbol: "OJO_USDT",
-			Last:   lastPriceOJO,
-			Vol:    volume,
+			Last:   types.Number(lastPriceOJO),
+			Vol:    types.Number(volume),
 		}
 
 		for _, ticker := range tickerMap {
@@ -101,3 +102,58 @@ func TestGateProvider_getSubscriptionMsgs(t *testing.T) {
 	msg, _ = json.Marshal(subMsgs[1])
 	require.Equal(t, "{\"method\":\"kline.subscribe\",\"params\":[\"ATOM_USDT\",60],\"id\":2}", string(msg))
 }
+
+func TestGateProvider_GetOrderBookDepths(t *testing.T) {
+	p := &GateProvider{
+		logger:     zerolog.Nop(),
+		orderBooks: newOrderBookStore(sdk.MustNewDecFromStr("0.01")),
+		priceStore: newPriceStore(zerolog.Nop()),
+	}
+
+	// recorded snapshot of the ATOM_USDT book, trimmed to a few levels
+	snapshot := `{"method":"depth.update","params":[true,{"asks":[["9.873","80.1"],["9.875","250"],` +
+		`["9.890","700"],["10.100","4000"]],"bids":[["9.871","120.5"],["9.870","301.2"],["9.860","1000"],` +
+		`["9.700","5000"]]},"ATOM_USDT"],"id":null}`
+	p.messageReceived(websocket.TextMessage, nil, []byte(snapshot))
+
+	depths := p.GetOrderBookDepths(ATOMUSDT, OJOUSDT)
+	require.Len(t, depths, 1)
+	require.Equal(t, sdk.MustNewDecFromStr("24204.8768"), depths[ATOMUSDT])
+
+	update := `{"method":"depth.update","params":[false,{"asks":[["9.873","100"]],"bids":[["9.860","0"]]},` +
+		`"ATOM_USDT"],"id":null}`
+	p.messageReceived(websocket.TextMessage, nil, []byte(update))
+
+	depths = p.GetOrderBookDepths(ATOMUSDT)
+	require.Equal(t, sdk.MustNewDecFromStr("14541.3495"), depths[ATOMUSDT])
+
+	// the depth is not measured unless configured
+	require.Nil(t, (&GateProvider{}).GetOrderBookDepths(ATOMUSDT))
+}
+
+func TestGateProvider_getDepthSubscriptionMsgs(t *testing.T) {
+	provider := &GateProvider{orderBooks: newOrderBookStore(sdk.MustNewDecFromStr("0.01"))}
+	subMsgs := provider.getSubscriptionMsgs(ATOMUSDT)
+	require.Len(t, subMsgs, 3)
+
+	msg, _ := json.Marshal(subMsgs[2])
+	require.Equal(t, "{\"method\":\"depth.subscribe\",\"params\":[\"ATOM_USDT\",30,\"0\"],\"id\":3}", string(msg))
+}
+
+func TestGateProvider_messageReceivedDecodeError(t *testing.T) {
+	p := &GateProvider{
+		logger:     zerolog.Nop(),
+		priceStore: newPriceStore(zerolog.Nop()),
+	}
+	decodeErrors := ProviderHealths()[ProviderGate].DecodeErrors
+
+	// a malformed frame is counted and dropped
+	require.NotPanics(t, func() {
+		p.messageReceived(websocket.TextMessage, nil, []byte(`{"method":"ticker.update","params":[`))
+	})
+	require.Equal(t, decodeErrors+1, ProviderHealths()[ProviderGate].DecodeErrors)
+
+	// a subscription response is not a decode error
+	p.messageReceived(websocket.TextMessage, nil, []byte(`{"error":null,"result":{"status":"success"},"id":1}`))
+	require.Equal(t, decodeErrors+1, ProviderHealths()[ProviderGate].DecodeErrors)
+}
@@ -0,0 +1,123 @@
+package provider
+
+import (
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+
+	"github.com/rs/zerolog"
+	"golang.org/x/net/http/httpguts"
+)
+
+// redactedHeaderValue replaces the values of secret-looking headers in logs.
+const redactedHeaderValue = "[REDACTED]"
+
+var (
+	// reservedHeaders are set by the websocket handshake or the HTTP client
+	// and cannot be overridden.
+	reservedHeaders = map[string]struct{}{
+		"Connection":               {},
+		"Content-Length":           {},
+		"Host":                     {},
+		"Sec-Websocket-Extensions": {},
+		"Sec-Websocket-Key":        {},
+		"Sec-Websocket-Version":    {},
+		"Transfer-Encoding":        {},
+		"Upgrade":                  {},
+	}
+
+	// secretHeaderParts mark a header whose value is redacted in logs if its
+	// name contains any of them.
+	secretHeaderParts = []string{
+		"auth", "token", "key", "secret", "password", "passphrase",
+		"signature", "cookie", "session", "credential",
+	}
+)
+
+// HTTPHeader returns the custom headers sent on the endpoint's websocket
+// handshake and REST requests, or nil if none are set. It fails on an invalid
+// or reserved header name or an invalid value. Errors never contain values.
+func (e Endpoint) HTTPHeader() (http.Header, error) {
+	if len(e.Headers) == 0 {
+		return nil, nil
+	}
+
+	header := make(http.Header, len(e.Headers))
+	for name, value := range e.Headers {
+		if !httpguts.ValidHeaderFieldName(name) {
+			return nil, fmt.Errorf("invalid header name %q of %s", name, e.Name)
+		}
+		canonicalName := http.CanonicalHeaderKey(name)
+		if _, ok := reservedHeaders[canonicalName]; ok {
+			return nil, fmt.Errorf("header %s of %s is reserved", canonicalName, e.Name)
+		}
+		if _, ok := header[canonicalName]; ok {
+			return nil, fmt.Errorf("duplicate header %s of %s", canonicalName, e.Name)
+		}
+		if value == "" || !httpguts.ValidHeaderFieldValue(value) {
+			return nil, fmt.Errorf("invalid value of header %s of %s", canonicalName, e.Name)
+		}
+		header.Set(canonicalName, value)
+	}
+	return header, nil
+}
+
+// ConfigureHeaders returns the endpoint with the custom headers its provider
+// sends on its websocket handshake and REST requests.
+func ConfigureHeaders(logger zerolog.Logger, endpoint Endpoint) (Endpoint, error) {
+	header, err := endpoint.HTTPHeader()
+	if err != nil {
+		return Endpoint{}, err
+	}
+
+	endpoint.header = header
+	if header != nil {
+		logger.Info().
+			Str("provider", endpoint.Name.String()).
+			Strs("headers", RedactHeader(header)).
+			Msg("sending custom headers to the provider")
+	}
+	return endpoint, nil
+}
+
+// RedactHeader returns the sorted "<name>: <value>" lines of the header with
+// the values of secret-looking headers redacted, to be logged.
+func RedactHeader(header http.Header) []string {
+	lines := make([]string, 0, len(header))
+	for name, values := range header {
+		value := strings.Join(values, ", ")
+		if isSecretHeader(name) {
+			value = redactedHeaderValue
+		}
+		lines = append(lines, name+": "+value)
+	}
+	sort.Strings(lines)
+	return lines
+}
+
+// isSecretHeader returns true if the header name suggests a secret value.
+func isSecretHeader(name string) bool {
+	name = strings.ToLower(name)
+	for _, part := range secretHeaderParts {
+		if strings.Contains(name, part) {
+			return true
+		}
+	}
+	return false
+}
+
+// headerTransport adds custom headers to the requests of the wrapped
+// transport.
+type headerTransport struct {
+	header http.Header
+	base   http.RoundTripper
+}
+
+func (t headerTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	req = req.Clone(req.Context())
+	for name, values := range t.header {
+		req.Header[name] = values
+	}
+	return t.base.RoundTrip(req)
+}
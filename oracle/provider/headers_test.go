@@ -0,0 +1,114 @@
+package provider
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gorilla/websocket"
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEndpoint_HTTPHeader(t *testing.T) {
+	header, err := Endpoint{Name: ProviderBinance}.HTTPHeader()
+	require.NoError(t, err)
+	require.Nil(t, header)
+
+	header, err = Endpoint{
+		Name:    ProviderBinance,
+		Headers: map[string]string{"x-api-version": "2", "x-partner-id": "ojo"},
+	}.HTTPHeader()
+	require.NoError(t, err)
+	require.Equal(t, http.Header{
+		"X-Api-Version": {"2"},
+		"X-Partner-Id":  {"ojo"},
+	}, header)
+
+	testCases := map[string]map[string]string{
+		"invalid name":      {"x partner": "ojo"},
+		"reserved name":     {"sec-websocket-key": "abc"},
+		"empty value":       {"x-partner-id": ""},
+		"invalid value":     {"x-partner-id": "ojo\r\nx-injected: 1"},
+		"duplicate name":    {"X-Partner-Id": "ojo", "x-partner-id": "ojo"},
+		"reserved host":     {"host": "example.com"},
+		"reserved upgrade":  {"upgrade": "h2c"},
+		"reserved encoding": {"transfer-encoding": "chunked"},
+	}
+	for name, headers := range testCases {
+		t.Run(name, func(t *testing.T) {
+			_, err := Endpoint{Name: ProviderBinance, Headers: headers}.HTTPHeader()
+			require.Error(t, err)
+			for _, value := range headers {
+				if value != "" {
+					require.NotContains(t, err.Error(), value)
+				}
+			}
+		})
+	}
+}
+
+func TestRedactHeader(t *testing.T) {
+	header := http.Header{
+		"X-Api-Version":  {"2"},
+		"Authorization":  {"Bearer abc"},
+		"X-Api-Key":      {"abc"},
+		"X-Access-Token": {"abc"},
+	}
+	require.Equal(t, []string{
+		"Authorization: [REDACTED]",
+		"X-Access-Token: [REDACTED]",
+		"X-Api-Key: [REDACTED]",
+		"X-Api-Version: 2",
+	}, RedactHeader(header))
+}
+
+func TestConfigureHeaders(t *testing.T) {
+	upgrader := websocket.Upgrader{}
+	received := make(chan http.Header, 2)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		received <- r.Header.Clone()
+		if !websocket.IsWebSocketUpgrade(r) {
+			_, _ = w.Write([]byte("ok"))
+			return
+		}
+		conn, err := upgrader.Upgrade(w, r, nil)
+		if err != nil {
+			return
+		}
+		conn.Close()
+	}))
+	t.Cleanup(server.Close)
+
+	endpoint, err := ConfigureHeaders(zerolog.Nop(), Endpoint{
+		Name:    ProviderMock,
+		Headers: map[string]string{"authorization": "Bearer abc", "x-partner-id": "ojo"},
+	})
+	require.NoError(t, err)
+
+	resp, err := endpoint.httpClient().Get(server.URL)
+	require.NoError(t, err)
+	resp.Body.Close()
+	header := <-received
+	require.Equal(t, "Bearer abc", header.Get("Authorization"))
+	require.Equal(t, "ojo", header.Get("X-Partner-Id"))
+
+	wsURL := "ws://" + strings.TrimPrefix(server.URL, "http://")
+	conn, resp, err := websocketDialer(nil).Dial(wsURL, endpoint.header)
+	require.NoError(t, err)
+	resp.Body.Close()
+	conn.Close()
+	header = <-received
+	require.Equal(t, "Bearer abc", header.Get("Authorization"))
+	require.Equal(t, "ojo", header.Get("X-Partner-Id"))
+
+	// endpoints without headers don't send them
+	endpoint, err = ConfigureHeaders(zerolog.Nop(), Endpoint{Name: ProviderMock})
+	require.NoError(t, err)
+	resp, err = endpoint.httpClient().Get(server.URL)
+	require.NoError(t, err)
+	resp.Body.Close()
+	header = <-received
+	require.Empty(t, header.Get("X-Partner-Id"))
+}
@@ -0,0 +1,84 @@
+package provider
+
+import (
+	"sync"
+
+	"github.com/ojo-network/price-feeder/oracle/types"
+)
+
+const (
+	// HealthStateConnected, HealthStateDegraded and HealthStateDisconnected
+	// are the states of a provider with all, some or none of its websocket
+	// connections open. HealthStatePolling is the state of a provider
+	// without websocket connections, which polls its REST endpoint.
+	HealthStateConnected    = "connected"
+	HealthStateDegraded     = "degraded"
+	HealthStateDisconnected = "disconnected"
+	HealthStatePolling      = "polling"
+)
+
+var (
+	// providerHealth holds the connection and message state of the providers
+	// which the provider metrics are reported from.
+	providerHealth    = map[types.ProviderName]*ProviderHealth{}
+	providerHealthMtx sync.Mutex
+)
+
+// ProviderHealth defines the connection and message state of a provider.
+type ProviderHealth struct {
+	// Connections is the number of websocket connections of the provider
+	// and Open the number of them currently connected.
+	Connections int
+	Open        int
+
+	// Messages is the number of messages received from the provider and
+	// DecodeErrors the number of them which failed to decode.
+	Messages     uint64
+	DecodeErrors uint64
+
+	// DroppedCandles is the number of candles dropped from the full candle
+	// buffers of the provider.
+	DroppedCandles uint64
+}
+
+// State returns the connection state of the provider.
+func (h ProviderHealth) State() string {
+	switch {
+	case h.Connections == 0:
+		return HealthStatePolling
+	case h.Open >= h.Connections:
+		return HealthStateConnected
+	case h.Open > 0:
+		return HealthStateDegraded
+	default:
+		return HealthStateDisconnected
+	}
+}
+
+// ProviderHealths returns the health of every provider with connections or
+// messages.
+func ProviderHealths() map[types.ProviderName]ProviderHealth {
+	providerHealthMtx.Lock()
+	defer providerHealthMtx.Unlock()
+
+	healths := make(map[types.ProviderName]ProviderHealth, len(providerHealth))
+	for n, h := range providerHealth {
+		healths[n] = *h
+	}
+	return healths
+}
+
+// updateProviderHealth applies update to the health of the provider and
+// returns the updated health.
+func updateProviderHealth(n types.ProviderName, update func(*ProviderHealth)) ProviderHealth {
+	providerHealthMtx.Lock()
+	defer providerHealthMtx.Unlock()
+
+	h, ok := providerHealth[n]
+	if !ok {
+		h = &ProviderHealth{}
+		providerHealth[n] = h
+	}
+	update(h)
+	return *h
+}
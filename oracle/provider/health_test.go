@@ -0,0 +1,44 @@
+package provider
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/ojo-network/price-feeder/oracle/types"
+)
+
+func TestProviderHealth_State(t *testing.T) {
+	testCases := []struct {
+		health ProviderHealth
+		state  string
+	}{
+		{ProviderHealth{}, HealthStatePolling},
+		{ProviderHealth{Messages: 10}, HealthStatePolling},
+		{ProviderHealth{Connections: 2, Open: 2}, HealthStateConnected},
+		{ProviderHealth{Connections: 2, Open: 1}, HealthStateDegraded},
+		{ProviderHealth{Connections: 2}, HealthStateDisconnected},
+	}
+
+	for _, tc := range testCases {
+		require.Equal(t, tc.state, tc.health.State())
+	}
+}
+
+func TestProviderHealths(t *testing.T) {
+	providerName := types.ProviderName("health-test")
+
+	telemetryWebsocketConnections(providerName, 2, 0)
+	require.Equal(t, HealthStateDisconnected, ProviderHealths()[providerName].State())
+
+	telemetryWebsocketConnections(providerName, 0, 1)
+	require.Equal(t, HealthStateDegraded, ProviderHealths()[providerName].State())
+
+	telemetryWebsocketConnections(providerName, 0, 1)
+	telemetryProviderMessage(providerName)
+	telemetryProviderMessage(providerName)
+
+	health := ProviderHealths()[providerName]
+	require.Equal(t, HealthStateConnected, health.State())
+	require.Equal(t, uint64(2), health.Messages)
+}
@@ -2,17 +2,15 @@ package provider
 
 import (
 	"bytes"
-	"compress/gzip"
 	"context"
 	"encoding/json"
-	"io"
-	"net/http"
+	"fmt"
 	"net/url"
-	"strconv"
 	"strings"
 	"sync"
 	"time"
 
+	sdk "github.com/cosmos/cosmos-sdk/types"
 	"github.com/gorilla/websocket"
 	"github.com/ojo-network/price-feeder/oracle/types"
 	"github.com/rs/zerolog"
@@ -26,7 +24,10 @@ const (
 	huobiRestPath      = "/market/tickers"
 )
 
-var _ Provider = (*HuobiProvider)(nil)
+var (
+	_ Provider               = (*HuobiProvider)(nil)
+	_ OrderBookDepthProvider = (*HuobiProvider)(nil)
+)
 
 type (
 	// HuobiProvider defines an Oracle provider implemented by the Huobi public
@@ -40,6 +41,10 @@ type (
 		mtx       sync.RWMutex
 		endpoints Endpoint
 
+		// orderBooks holds the order books of the pairs if their depth is
+		// measured, nil otherwise.
+		orderBooks *orderBookStore
+
 		priceStore
 	}
 
@@ -53,8 +58,8 @@ type (
 	// HuobiTick defines the response type for the last 24h market summary and the last
 	// traded price for a given ticker/symbol.
 	HuobiTick struct {
-		Vol       float64 `json:"vol"`       // Accumulated trading value of last 24 hours
-		LastPrice float64 `json:"lastPrice"` // Last traded price
+		Vol       types.Number `json:"vol"`       // Accumulated trading value of last 24 hours
+		LastPrice types.Number `json:"lastPrice"` // Last traded price
 	}
 
 	// HuobiCandle defines the response type for the channel and the tick object for a
@@ -66,9 +71,23 @@ type (
 
 	// HuobiCandleTick defines the response type for the candle.
 	HuobiCandleTick struct {
-		Close     float64 `json:"close"` // Closing price during this period
-		TimeStamp int64   `json:"id"`    // TimeStamp for this as an ID
-		Volume    float64 `json:"vol"`   // Volume during this period
+		Close     types.Number `json:"close"` // Closing price during this period
+		TimeStamp int64        `json:"id"`    // TimeStamp for this as an ID
+		Volume    types.Number `json:"vol"`   // Volume during this period
+	}
+
+	// HuobiDepth defines the response type for the channel and the tick object
+	// of an order book snapshot.
+	HuobiDepth struct {
+		CH   string         `json:"ch"` // Channel name. Format：market.$symbol.depth.step0
+		Tick HuobiDepthTick `json:"tick"`
+	}
+
+	// HuobiDepthTick defines the price levels of an order book snapshot, as
+	// [price, amount] pairs.
+	HuobiDepthTick struct {
+		Bids [][]types.Number `json:"bids"`
+		Asks [][]types.Number `json:"asks"`
 	}
 
 	// HuobiSubscriptionMsg Msg to subscribe to one ticker channel at time.
@@ -102,9 +121,11 @@ func NewHuobiProvider(
 ) (*HuobiProvider, error) {
 	if endpoints.Name != ProviderHuobi {
 		endpoints = Endpoint{
-			Name:      ProviderHuobi,
-			Rest:      huobiRestHost,
-			Websocket: huobiWSHost,
+			Name:              ProviderHuobi,
+			Rest:              huobiRestHost,
+			Websocket:         huobiWSHost,
+			SilenceTimeout:    endpoints.SilenceTimeout,
+			ReconnectCooldown: endpoints.ReconnectCooldown,
 		}
 	}
 
@@ -124,6 +145,14 @@ func NewHuobiProvider(
 	provider.currencyPairToTickerPair = currencyPairToHuobiTickerPair
 	provider.curencyPairToCandlePair = currencyPairToHuobiCandlePair
 
+	depthBand, ok, err := endpoints.OrderBookDepthBand()
+	if err != nil {
+		return nil, err
+	}
+	if ok {
+		provider.orderBooks = newOrderBookStore(depthBand)
+	}
+
 	confirmedPairs, err := ConfirmPairAvailability(
 		provider,
 		provider.endpoints.Name,
@@ -144,6 +173,11 @@ func NewHuobiProvider(
 		provider.messageReceived,
 		disabledPingDuration,
 		websocket.PingMessage,
+		endpoints.SilenceTimeout,
+		endpoints.ReconnectCooldown,
+		&provider.priceStore,
+		endpoints.tlsConfig,
+		endpoints.header,
 		huobiLogger,
 	)
 
@@ -159,6 +193,9 @@ func (p *HuobiProvider) getSubscriptionMsgs(cps ...types.CurrencyPair) []interfa
 	for _, cp := range cps {
 		subscriptionMsgs = append(subscriptionMsgs, newHuobiTickerSubscriptionMsg(cp))
 		subscriptionMsgs = append(subscriptionMsgs, newHuobiCandleSubscriptionMsg(cp))
+		if p.orderBooks != nil {
+			subscriptionMsgs = append(subscriptionMsgs, newHuobiDepthSubscriptionMsg(cp))
+		}
 	}
 	return subscriptionMsgs
 }
@@ -197,19 +234,13 @@ func (p *HuobiProvider) SubscribeCurrencyPairs(cps ...types.CurrencyPair) {
 }
 
 // messageReceived handles the received data from the Huobi websocket. All return
-// data of websocket Market APIs are compressed with GZIP so they need to be
-// decompressed.
+// data of websocket Market APIs are compressed with GZIP, which the websocket
+// connection decompresses before passing it on.
 func (p *HuobiProvider) messageReceived(messageType int, conn *WebsocketConnection, bz []byte) {
 	if messageType != websocket.BinaryMessage {
 		return
 	}
 
-	bz, err := decompressGzip(bz)
-	if err != nil {
-		p.logger.Err(err).Msg("failed to decompress gziped message")
-		return
-	}
-
 	if bytes.Contains(bz, ping) {
 		p.pongReceived(conn, bz)
 		return
@@ -220,25 +251,31 @@ func (p *HuobiProvider) messageReceived(messageType int, conn *WebsocketConnecti
 		tickerErr     error
 		candleResp    HuobiCandle
 		candleErr     error
+		depthErr      error
 		subscribeResp HuobiSubscriptionResp
 	)
 
 	// sometimes the message received is not a ticker or a candle response.
 	tickerErr = json.Unmarshal(bz, &tickerResp)
-	if tickerResp.Tick.LastPrice != 0 {
+	if !tickerResp.Tick.LastPrice.IsZero() {
 		p.setTickerPair(tickerResp, tickerResp.CH)
 		telemetryWebsocketMessage(ProviderHuobi, MessageTypeTicker)
 		return
 	}
 
 	candleErr = json.Unmarshal(bz, &candleResp)
-	if candleResp.Tick.Close != 0 {
+	if !candleResp.Tick.Close.IsZero() {
 		p.setCandlePair(candleResp, candleResp.CH)
 		telemetryWebsocketMessage(ProviderHuobi, MessageTypeCandle)
 		return
 	}
 
-	err = json.Unmarshal(bz, &subscribeResp)
+	depthErr = p.messageReceivedDepth(bz)
+	if depthErr == nil {
+		return
+	}
+
+	err := json.Unmarshal(bz, &subscribeResp)
 	if subscribeResp.Status == "ok" {
 		return
 	}
@@ -247,8 +284,49 @@ func (p *HuobiProvider) messageReceived(messageType int, conn *WebsocketConnecti
 		Int("length", len(bz)).
 		AnErr("ticker", tickerErr).
 		AnErr("candle", candleErr).
+		AnErr("depth", depthErr).
 		AnErr("subscribeResp", err).
 		Msg("Error on receive message")
+	telemetryDecodeError(p.logger, ProviderHuobi, bz)
+}
+
+// messageReceivedDepth handles the order book msg, a full snapshot of the top
+// levels of the book.
+//
+// REF: https://huobiapi.github.io/docs/spot/v1/en/#market-depth
+func (p *HuobiProvider) messageReceivedDepth(bz []byte) error {
+	var depthResp HuobiDepth
+	if err := json.Unmarshal(bz, &depthResp); err != nil {
+		return err
+	}
+
+	if !strings.HasSuffix(depthResp.CH, ".depth.step0") {
+		return fmt.Errorf("message is not a depth snapshot")
+	}
+	if p.orderBooks == nil {
+		return nil
+	}
+
+	bids, err := parseOrderBookLevels(depthResp.Tick.Bids)
+	if err != nil {
+		return err
+	}
+	asks, err := parseOrderBookLevels(depthResp.Tick.Asks)
+	if err != nil {
+		return err
+	}
+
+	p.orderBooks.setBook(depthResp.CH, bids, asks)
+	return nil
+}
+
+// GetOrderBookDepths returns the depth of the order books of the pairs, or
+// nil if the order books are not subscribed to.
+func (p *HuobiProvider) GetOrderBookDepths(cps ...types.CurrencyPair) map[types.CurrencyPair]sdk.Dec {
+	if p.orderBooks == nil {
+		return nil
+	}
+	return p.orderBooks.depths(currencyPairToHuobiDepthPair, cps...)
 }
 
 // pongReceived return a heartbeat message when a "ping" is received and reset the
@@ -283,7 +361,7 @@ func (p *HuobiProvider) setSubscribedPairs(cps ...types.CurrencyPair) {
 
 // GetAvailablePairs returns all pairs to which the provider can subscribe.
 func (p *HuobiProvider) GetAvailablePairs() (map[string]struct{}, error) {
-	resp, err := http.Get(p.endpoints.Rest + huobiRestPath)
+	resp, err := p.endpoints.httpClient().Get(p.endpoints.Rest + huobiRestPath)
 	if err != nil {
 		return nil, err
 	}
@@ -302,29 +380,18 @@ func (p *HuobiProvider) GetAvailablePairs() (map[string]struct{}, error) {
 	return availablePairs, nil
 }
 
-// decompressGzip uncompress gzip compressed messages. All data returned from the
-// websocket Market APIs is compressed with GZIP, so it needs to be unzipped.
-func decompressGzip(bz []byte) ([]byte, error) {
-	r, err := gzip.NewReader(bytes.NewReader(bz))
-	if err != nil {
-		return nil, err
-	}
-
-	return io.ReadAll(r)
-}
-
 // toTickerPrice converts current HuobiTicker to TickerPrice.
 func (ticker HuobiTicker) toTickerPrice() (types.TickerPrice, error) {
 	return types.NewTickerPrice(
-		strconv.FormatFloat(ticker.Tick.LastPrice, 'f', -1, 64),
-		strconv.FormatFloat(ticker.Tick.Vol, 'f', -1, 64),
+		ticker.Tick.LastPrice.String(),
+		ticker.Tick.Vol.String(),
 	)
 }
 
 func (candle HuobiCandle) toCandlePrice() (types.CandlePrice, error) {
 	return types.NewCandlePrice(
-		strconv.FormatFloat(candle.Tick.Close, 'f', -1, 64),
-		strconv.FormatFloat(candle.Tick.Volume, 'f', -1, 64),
+		candle.Tick.Close.String(),
+		candle.Tick.Volume.String(),
 		candle.Tick.TimeStamp,
 	)
 }
@@ -354,3 +421,16 @@ func newHuobiCandleSubscriptionMsg(cp types.CurrencyPair) HuobiSubscriptionMsg {
 func currencyPairToHuobiCandlePair(cp types.CurrencyPair) string {
 	return strings.ToLower("market." + cp.String() + ".kline.1min")
 }
+
+// newHuobiDepthSubscriptionMsg returns a new order book subscription Msg.
+func newHuobiDepthSubscriptionMsg(cp types.CurrencyPair) HuobiSubscriptionMsg {
+	return HuobiSubscriptionMsg{
+		Sub: currencyPairToHuobiDepthPair(cp),
+	}
+}
+
+// currencyPairToHuobiDepthPair returns the channel name in the following format:
+// "market.$symbol.depth.step0".
+func currencyPairToHuobiDepthPair(cp types.CurrencyPair) string {
+	return strings.ToLower("market." + cp.String() + ".depth.step0")
+}
@@ -5,7 +5,8 @@ import (
 	"encoding/json"
 	"testing"
 
-	"github.com/ojo-network/ojo/util/decmath"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/gorilla/websocket"
 	"github.com/ojo-network/price-feeder/oracle/types"
 	"github.com/rs/zerolog"
 	"github.com/stretchr/testify/require"
@@ -21,8 +22,8 @@ func TestHuobiProvider_GetTickerPrices(t *testing.T) {
 	require.NoError(t, err)
 
 	t.Run("valid_request_single_ticker", func(t *testing.T) {
-		lastPrice := 34.69000000
-		volume := 2396974.02000000
+		lastPrice := types.Number("34.69000000")
+		volume := types.Number("2396974.02000000")
 
 		tickerMap := map[string]HuobiTicker{}
 		tickerMap["market.atomusdt.ticker"] = HuobiTicker{
@@ -40,16 +41,16 @@ func TestHuobiProvider_GetTickerPrices(t *testing.T) {
 		prices, err := p.GetTickerPrices(ATOMUSDT)
 		require.NoError(t, err)
 		require.Len(t, prices, 1)
-		dec, _ := decmath.NewDecFromFloat(lastPrice)
+		dec := sdk.MustNewDecFromStr(lastPrice.String())
 		require.Equal(t, dec, prices[ATOMUSDT].Price)
-		dec, _ = decmath.NewDecFromFloat(volume)
+		dec = sdk.MustNewDecFromStr(volume.String())
 		require.Equal(t, dec, prices[ATOMUSDT].Volume)
 	})
 
 	t.Run("valid_request_multi_ticker", func(t *testing.T) {
-		lastPriceAtom := 34.69000000
-		lastPriceLuna := 41.35000000
-		volume := 2396974.02000000
+		lastPriceAtom := types.Number("34.69000000")
+		lastPriceLuna := types.Number("41.35000000")
+		volume := types.Number("2396974.02000000")
 
 		tickerMap := map[string]HuobiTicker{}
 		tickerMap["market.atomusdt.ticker"] = HuobiTicker{
@@ -78,14 +79,14 @@ func TestHuobiProvider_GetTickerPrices(t *testing.T) {
 		)
 		require.NoError(t, err)
 		require.Len(t, prices, 2)
-		dec, _ := decmath.NewDecFromFloat(lastPriceAtom)
+		dec := sdk.MustNewDecFromStr(lastPriceAtom.String())
 		require.Equal(t, dec, prices[ATOMUSDT].Price)
 
-		dec, _ = decmath.NewDecFromFloat(volume)
+		dec = sdk.MustNewDecFromStr(volume.String())
 		require.Equal(t, dec, prices[ATOMUSDT].Volume)
-		dec, _ = decmath.NewDecFromFloat(lastPriceLuna)
+		dec = sdk.MustNewDecFromStr(lastPriceLuna.String())
 		require.Equal(t, dec, prices[LUNAUSDT].Price)
-		dec, _ = decmath.NewDecFromFloat(volume)
+		dec = sdk.MustNewDecFromStr(volume.String())
 		require.Equal(t, dec, prices[LUNAUSDT].Volume)
 	})
 
@@ -114,3 +115,33 @@ func TestHuobiProvider_getSubscriptionMsgs(t *testing.T) {
 	msg, _ = json.Marshal(subMsgs[1])
 	require.Equal(t, "{\"sub\":\"market.atomusdt.kline.1min\"}", string(msg))
 }
+
+func TestHuobiProvider_GetOrderBookDepths(t *testing.T) {
+	p := &HuobiProvider{
+		logger:     zerolog.Nop(),
+		orderBooks: newOrderBookStore(sdk.MustNewDecFromStr("0.01")),
+		priceStore: newPriceStore(zerolog.Nop()),
+	}
+
+	// recorded snapshot of the atomusdt book, trimmed to a few levels
+	snapshot := `{"ch":"market.atomusdt.depth.step0","ts":1681387200123,"tick":{"bids":[[9.871,120.5],` +
+		`[9.87,301.2],[9.86,1000],[9.7,5000]],"asks":[[9.873,80.1],[9.875,250],[9.89,700],[10.1,4000]],` +
+		`"version":165502137401,"ts":1681387200000}}`
+	p.messageReceived(websocket.BinaryMessage, nil, []byte(snapshot))
+
+	depths := p.GetOrderBookDepths(ATOMUSDT, OJOUSDT)
+	require.Len(t, depths, 1)
+	require.Equal(t, sdk.MustNewDecFromStr("24204.8768"), depths[ATOMUSDT])
+
+	// the depth is not measured unless configured
+	require.Nil(t, (&HuobiProvider{}).GetOrderBookDepths(ATOMUSDT))
+}
+
+func TestHuobiProvider_getDepthSubscriptionMsgs(t *testing.T) {
+	provider := &HuobiProvider{orderBooks: newOrderBookStore(sdk.MustNewDecFromStr("0.01"))}
+	subMsgs := provider.getSubscriptionMsgs(ATOMUSDT)
+	require.Len(t, subMsgs, 3)
+
+	msg, _ := json.Marshal(subMsgs[2])
+	require.Equal(t, "{\"sub\":\"market.atomusdt.depth.step0\"}", string(msg))
+}
@@ -0,0 +1,387 @@
+package provider
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/rs/zerolog"
+
+	"github.com/ojo-network/price-feeder/oracle/types"
+)
+
+const (
+	injectiveRestHost           = "https://sentry.lcd.injective.network"
+	injectiveSpotMarketsPath    = "/injective/exchange/v1beta1/spot/markets"
+	injectiveDerivMarketsPath   = "/injective/exchange/v1beta1/derivative/markets"
+	injectiveMidPriceAndTOBPath = "/mid_price_and_tob"
+	injectiveActiveMarketStatus = "Active"
+	injectivePricePollInterval  = 5 * time.Second
+)
+
+var _ Provider = (*InjectiveProvider)(nil)
+
+type (
+	// InjectiveProvider defines an Oracle provider which polls the prices of
+	// markets of Injective's on-chain exchange module through its LCD. Each
+	// pair is mapped to a market by the market ID set as its pair address.
+	//
+	// Spot pairs are priced by the mid price of the spot market's order book
+	// and pairs with the mark price type by the mark price of the perpetual
+	// market. The exchange module does not expose traded volume, so prices are
+	// stored as candles and tickers without volume.
+	//
+	// REF: https://api.injective.exchange/#chain-exchange
+	InjectiveProvider struct {
+		ctx        context.Context
+		logger     zerolog.Logger
+		mtx        sync.RWMutex
+		endpoints  Endpoint
+		client     *http.Client
+		priceTypes map[string]PriceType
+
+		// marketIDs holds the configured market ID of each pair and markets
+		// the market of each pair which was confirmed to be active.
+		marketIDs map[string]string
+		markets   map[string]injectiveMarket
+
+		priceStore
+	}
+
+	// injectiveMarket defines a subscribed Injective market and the decimals
+	// needed to convert its chain prices to human readable prices.
+	injectiveMarket struct {
+		id            string
+		baseDecimals  uint32
+		quoteDecimals uint32
+	}
+
+	// InjectiveSpotMarketsResponse defines the response structure of the
+	// Injective spot markets query.
+	InjectiveSpotMarketsResponse struct {
+		Markets []InjectiveSpotMarket `json:"markets"`
+	}
+
+	// InjectiveSpotMarket defines the response structure of an Injective spot
+	// market.
+	InjectiveSpotMarket struct {
+		MarketID      string `json:"market_id"`
+		Ticker        string `json:"ticker"`
+		Status        string `json:"status"`
+		BaseDecimals  uint32 `json:"base_decimals"`
+		QuoteDecimals uint32 `json:"quote_decimals"`
+	}
+
+	// InjectiveDerivativeMarketsResponse defines the response structure of the
+	// Injective derivative markets query.
+	InjectiveDerivativeMarketsResponse struct {
+		Markets []InjectiveFullDerivativeMarket `json:"markets"`
+	}
+
+	// InjectiveDerivativeMarketResponse defines the response structure of the
+	// Injective derivative market query.
+	InjectiveDerivativeMarketResponse struct {
+		Market InjectiveFullDerivativeMarket `json:"market"`
+	}
+
+	// InjectiveFullDerivativeMarket defines the response structure of an
+	// Injective derivative market along with its mark price.
+	InjectiveFullDerivativeMarket struct {
+		Market    InjectiveDerivativeMarket `json:"market"`
+		MarkPrice string                    `json:"mark_price"`
+	}
+
+	// InjectiveDerivativeMarket defines the response structure of an Injective
+	// derivative market.
+	InjectiveDerivativeMarket struct {
+		MarketID      string `json:"market_id"`
+		Ticker        string `json:"ticker"`
+		Status        string `json:"status"`
+		QuoteDecimals uint32 `json:"quote_decimals"`
+	}
+
+	// InjectiveMidPriceAndTOB defines the response structure of the Injective
+	// spot market mid price and top of book query.
+	InjectiveMidPriceAndTOB struct {
+		MidPrice      string `json:"mid_price"`
+		BestBuyPrice  string `json:"best_buy_price"`
+		BestSellPrice string `json:"best_sell_price"`
+	}
+
+	// injectivePrice defines a human readable price of an Injective market at
+	// the time it was polled.
+	injectivePrice struct {
+		price     sdk.Dec
+		timeStamp int64
+	}
+)
+
+func NewInjectiveProvider(
+	ctx context.Context,
+	logger zerolog.Logger,
+	endpoints Endpoint,
+	pairs ...types.CurrencyPair,
+) (*InjectiveProvider, error) {
+	if endpoints.Name != ProviderInjective {
+		endpoints = Endpoint{
+			Name: ProviderInjective,
+			Rest: injectiveRestHost,
+		}
+	}
+
+	injectiveLogger := logger.With().Str("provider", string(ProviderInjective)).Logger()
+
+	provider := &InjectiveProvider{
+		ctx:        ctx,
+		logger:     injectiveLogger,
+		endpoints:  endpoints,
+		client:     endpoints.httpClient(),
+		priceTypes: endpoints.pairPriceTypes(currencyPairToInjectivePair),
+		marketIDs:  map[string]string{},
+		markets:    map[string]injectiveMarket{},
+		priceStore: newPriceStore(injectiveLogger),
+	}
+	provider.setCurrencyPairToTickerAndCandlePair(currencyPairToInjectivePair)
+	provider.setMarketIDs(pairs...)
+
+	confirmedPairs, err := ConfirmPairAvailability(
+		provider,
+		provider.endpoints.Name,
+		provider.logger,
+		pairs...,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	provider.setSubscribedPairs(confirmedPairs...)
+
+	return provider, nil
+}
+
+// StartConnections starts polling the prices of the subscribed markets until
+// the provider's context is canceled.
+func (p *InjectiveProvider) StartConnections() {
+	go func() {
+		ticker := time.NewTicker(injectivePricePollInterval)
+		defer ticker.Stop()
+
+		for {
+			p.pollPrices()
+
+			select {
+			case <-p.ctx.Done():
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+}
+
+// SubscribeCurrencyPairs confirms the markets of the new currency pairs and
+// adds them to the providers subscribedPairs array
+func (p *InjectiveProvider) SubscribeCurrencyPairs(cps ...types.CurrencyPair) {
+	p.mtx.Lock()
+	defer p.mtx.Unlock()
+
+	p.setMarketIDs(cps...)
+	confirmedPairs, err := ConfirmPairAvailability(
+		p,
+		p.endpoints.Name,
+		p.logger,
+		cps...,
+	)
+	if err != nil {
+		return
+	}
+
+	p.setSubscribedPairs(confirmedPairs...)
+}
+
+// pollPrices queries the price of every subscribed pair. A pair whose query
+// fails stops contributing prices until it is queried successfully again.
+func (p *InjectiveProvider) pollPrices() {
+	p.mtx.RLock()
+	defer p.mtx.RUnlock()
+
+	p.subscribedPairsMtx.RLock()
+	pairs := types.MapPairsToSlice(p.subscribedPairs)
+	p.subscribedPairsMtx.RUnlock()
+
+	for _, cp := range pairs {
+		symbol := currencyPairToInjectivePair(cp)
+
+		price, err := p.queryPrice(symbol)
+		if err != nil {
+			p.removePair(symbol)
+			TelemetryFailure(ProviderInjective, MessageTypeTicker)
+			p.logger.Error().
+				Err(err).
+				Str("pair", cp.String()).
+				Msg("failed to query price; disabling pair until the next successful query")
+			continue
+		}
+
+		p.setTickerPair(price, symbol)
+		p.setCandlePair(price, symbol)
+	}
+}
+
+// queryPrice returns the human readable price of the market of the given
+// pair.
+func (p *InjectiveProvider) queryPrice(symbol string) (injectivePrice, error) {
+	market, ok := p.markets[symbol]
+	if !ok {
+		return injectivePrice{}, fmt.Errorf("no market found for %s", symbol)
+	}
+
+	if p.priceTypes[symbol] == PriceTypeMark {
+		var resp InjectiveDerivativeMarketResponse
+		if err := p.get(injectiveDerivMarketsPath+"/"+market.id, &resp); err != nil {
+			return injectivePrice{}, err
+		}
+		return newInjectivePrice(resp.Market.MarkPrice, -int64(market.quoteDecimals))
+	}
+
+	var resp InjectiveMidPriceAndTOB
+	if err := p.get(injectiveSpotMarketsPath+"/"+market.id+injectiveMidPriceAndTOBPath, &resp); err != nil {
+		return injectivePrice{}, err
+	}
+	return newInjectivePrice(resp.MidPrice, int64(market.baseDecimals)-int64(market.quoteDecimals))
+}
+
+// get queries the given LCD path and decodes its JSON response into resp.
+func (p *InjectiveProvider) get(path string, resp interface{}) error {
+	httpResp, err := p.client.Get(p.endpoints.Rest + path)
+	if err != nil {
+		return err
+	}
+	defer httpResp.Body.Close()
+
+	if httpResp.StatusCode != http.StatusOK {
+		return fmt.Errorf("injective: unexpected status %s querying %s", httpResp.Status, path)
+	}
+	return json.NewDecoder(httpResp.Body).Decode(resp)
+}
+
+// GetAvailablePairs returns all pairs to which the provider can subscribe,
+// being every pair whose market ID belongs to an active market, and stores
+// the markets of these pairs.
+// ex.: map["INJUSDT" => {}, "ATOMUSDT" => {}].
+func (p *InjectiveProvider) GetAvailablePairs() (map[string]struct{}, error) {
+	var spotResp InjectiveSpotMarketsResponse
+	if err := p.get(injectiveSpotMarketsPath, &spotResp); err != nil {
+		return nil, err
+	}
+	var derivResp InjectiveDerivativeMarketsResponse
+	if err := p.get(injectiveDerivMarketsPath, &derivResp); err != nil {
+		return nil, err
+	}
+
+	spotMarkets := make(map[string]injectiveMarket, len(spotResp.Markets))
+	for _, m := range spotResp.Markets {
+		if m.Status != injectiveActiveMarketStatus {
+			continue
+		}
+		spotMarkets[strings.ToLower(m.MarketID)] = injectiveMarket{
+			id:            m.MarketID,
+			baseDecimals:  m.BaseDecimals,
+			quoteDecimals: m.QuoteDecimals,
+		}
+	}
+	derivMarkets := make(map[string]injectiveMarket, len(derivResp.Markets))
+	for _, m := range derivResp.Markets {
+		if m.Market.Status != injectiveActiveMarketStatus {
+			continue
+		}
+		derivMarkets[strings.ToLower(m.Market.MarketID)] = injectiveMarket{
+			id:            m.Market.MarketID,
+			quoteDecimals: m.Market.QuoteDecimals,
+		}
+	}
+
+	availablePairs := make(map[string]struct{}, len(p.marketIDs))
+	for symbol, marketID := range p.marketIDs {
+		markets := spotMarkets
+		if p.priceTypes[symbol] == PriceTypeMark {
+			markets = derivMarkets
+		}
+		market, ok := markets[strings.ToLower(marketID)]
+		if !ok {
+			continue
+		}
+
+		p.markets[symbol] = market
+		availablePairs[symbol] = struct{}{}
+	}
+
+	return availablePairs, nil
+}
+
+// setMarketIDs stores the market ID set as the address of each pair.
+func (p *InjectiveProvider) setMarketIDs(cps ...types.CurrencyPair) {
+	for _, cp := range cps {
+		p.marketIDs[currencyPairToInjectivePair(cp)] = cp.Address
+	}
+}
+
+// removePair removes the ticker and candles of a pair, so it stops
+// contributing prices.
+func (p *InjectiveProvider) removePair(symbol string) {
+	p.tickerMtx.Lock()
+	delete(p.tickers, symbol)
+	p.tickerMtx.Unlock()
+
+	p.candleMtx.Lock()
+	delete(p.candles, symbol)
+	p.candleMtx.Unlock()
+}
+
+func (ip injectivePrice) toTickerPrice() (types.TickerPrice, error) {
+	return types.TickerPrice{
+		Price:  ip.price,
+		Volume: sdk.ZeroDec(),
+	}, nil
+}
+
+func (ip injectivePrice) toCandlePrice() (types.CandlePrice, error) {
+	return types.CandlePrice{
+		Price:     ip.price,
+		Volume:    sdk.ZeroDec(),
+		TimeStamp: ip.timeStamp,
+	}, nil
+}
+
+// newInjectivePrice converts a chain price to a human readable price by
+// scaling it with the given power of ten.
+func newInjectivePrice(chainPrice string, exponent int64) (injectivePrice, error) {
+	price, err := sdk.NewDecFromStr(chainPrice)
+	if err != nil {
+		return injectivePrice{}, fmt.Errorf("injective: failed to parse price: %w", err)
+	}
+	if !price.IsPositive() {
+		return injectivePrice{}, fmt.Errorf("injective: no price available")
+	}
+
+	if exponent < 0 {
+		price = price.Quo(sdk.NewDec(10).Power(uint64(-exponent)))
+	} else {
+		price = price.Mul(sdk.NewDec(10).Power(uint64(exponent)))
+	}
+
+	return injectivePrice{
+		price:     price,
+		timeStamp: PastUnixTime(0),
+	}, nil
+}
+
+// currencyPairToInjectivePair receives a currency pair and return the symbol
+// the provider stores its prices by, ex.: ATOMUSDT.
+func currencyPairToInjectivePair(cp types.CurrencyPair) string {
+	return strings.ToUpper(cp.String())
+}
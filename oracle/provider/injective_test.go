@@ -0,0 +1,112 @@
+package provider
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ojo-network/price-feeder/oracle/types"
+)
+
+const (
+	injectiveTestSpotMarketID  = "0xa508cb32923323679f29a032c70342c147c17d0145625922b0ef22e955c844c0"
+	injectiveTestDerivMarketID = "0x9b9980167ecc3645ff1a5517886652d94a0825e54a77d2057cbbe3ebee015963"
+)
+
+func newInjectiveTestServer(t *testing.T, failing map[string]bool) *httptest.Server {
+	responses := map[string]string{
+		injectiveSpotMarketsPath: `{"markets":[{"ticker":"INJ/USDT","market_id":"` + injectiveTestSpotMarketID +
+			`","status":"Active","base_decimals":18,"quote_decimals":6}]}`,
+		injectiveDerivMarketsPath: `{"markets":[{"market":{"ticker":"ATOM/USDT PERP","market_id":"` + injectiveTestDerivMarketID +
+			`","status":"Active","quote_decimals":6},"mark_price":"11520000.000000000000000000"}]}`,
+		injectiveSpotMarketsPath + "/" + injectiveTestSpotMarketID + injectiveMidPriceAndTOBPath: `{"mid_price":` +
+			`"0.000000000034690000","best_buy_price":"0.000000000034680000","best_sell_price":"0.000000000034700000"}`,
+		injectiveDerivMarketsPath + "/" + injectiveTestDerivMarketID: `{"market":{"market":{"ticker":"ATOM/USDT PERP",` +
+			`"market_id":"` + injectiveTestDerivMarketID + `","status":"Active","quote_decimals":6},` +
+			`"mark_price":"11520000.000000000000000000"}}`,
+	}
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		resp, ok := responses[r.URL.Path]
+		if !ok || failing[r.URL.Path] {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		_, _ = w.Write([]byte(resp))
+	}))
+	t.Cleanup(server.Close)
+
+	return server
+}
+
+func TestInjectiveProvider_GetTickerPrices(t *testing.T) {
+	failing := map[string]bool{}
+	server := newInjectiveTestServer(t, failing)
+
+	injusdt := types.CurrencyPair{Base: "INJ", Quote: "USDT", Address: injectiveTestSpotMarketID}
+	atomusdt := types.CurrencyPair{Base: "ATOM", Quote: "USDT", Address: injectiveTestDerivMarketID}
+	p, err := NewInjectiveProvider(
+		context.TODO(),
+		zerolog.Nop(),
+		Endpoint{
+			Name: ProviderInjective,
+			Rest: server.URL,
+			PriceTypes: []PairPriceType{
+				{Base: "ATOM", Quote: "USDT", Type: PriceTypeMark},
+			},
+		},
+		injusdt,
+		atomusdt,
+		types.CurrencyPair{Base: "FOO", Quote: "USDT", Address: "0x0"},
+	)
+	require.NoError(t, err)
+	require.Len(t, p.subscribedPairs, 2)
+
+	t.Run("valid_request_multi_ticker", func(t *testing.T) {
+		p.pollPrices()
+
+		prices, err := p.GetTickerPrices(injusdt, atomusdt)
+		require.NoError(t, err)
+		require.Len(t, prices, 2)
+		require.Equal(t, sdk.MustNewDecFromStr("34.69"), prices[injusdt].Price)
+		require.Equal(t, sdk.ZeroDec(), prices[injusdt].Volume)
+		require.Equal(t, sdk.MustNewDecFromStr("11.52"), prices[atomusdt].Price)
+
+		candles, err := p.GetCandlePrices(injusdt, atomusdt)
+		require.NoError(t, err)
+		require.Len(t, candles, 2)
+		require.Equal(t, sdk.MustNewDecFromStr("34.69"), candles[injusdt][0].Price)
+	})
+
+	t.Run("failing_query_disables_pair", func(t *testing.T) {
+		failing[injectiveSpotMarketsPath+"/"+injectiveTestSpotMarketID+injectiveMidPriceAndTOBPath] = true
+		p.pollPrices()
+
+		prices, err := p.GetTickerPrices(injusdt, atomusdt)
+		require.NoError(t, err)
+		require.Len(t, prices, 1)
+		require.Equal(t, sdk.MustNewDecFromStr("11.52"), prices[atomusdt].Price)
+
+		candles, err := p.GetCandlePrices(injusdt)
+		require.NoError(t, err)
+		require.Empty(t, candles)
+	})
+}
+
+func TestNewInjectivePrice(t *testing.T) {
+	price, err := newInjectivePrice("0.000000000034690000", 12)
+	require.NoError(t, err)
+	require.Equal(t, sdk.MustNewDecFromStr("34.69"), price.price)
+
+	price, err = newInjectivePrice("11520000", -6)
+	require.NoError(t, err)
+	require.Equal(t, sdk.MustNewDecFromStr("11.52"), price.price)
+
+	_, err = newInjectivePrice("0", 12)
+	require.Error(t, err)
+}
@@ -0,0 +1,391 @@
+package provider
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/ojo-network/price-feeder/oracle/types"
+	"github.com/rs/zerolog"
+)
+
+const (
+	jupiterRestHost          = "https://api.jup.ag"
+	jupiterPricePath         = "/price/v2"
+	jupiterAPIKeyHeader      = "x-api-key"
+	jupiterQuote             = "USD"
+	jupiterMaxIDs            = 100
+	jupiterPricePollInterval = 10 * time.Second
+)
+
+var _ Provider = (*JupiterProvider)(nil)
+
+type (
+	// JupiterProvider defines an Oracle provider which polls the USD prices of
+	// Solana tokens from Jupiter's price API, which aggregates the Solana
+	// DEXes such as Raydium and Orca. Each pair is mapped to its token by the
+	// mint address set as its pair address, and must be quoted in USD. The
+	// price API does not expose traded volume, so prices are stored as candles
+	// and tickers without volume.
+	//
+	// REF: https://station.jup.ag/docs/apis/price-api-v2
+	JupiterProvider struct {
+		ctx       context.Context
+		logger    zerolog.Logger
+		mtx       sync.RWMutex
+		endpoints Endpoint
+		client    *http.Client
+
+		// mints holds the configured mint address of each pair and
+		// retryAfter the time until which polling is paused after being rate
+		// limited.
+		mints      map[string]string
+		retryAfter time.Time
+
+		priceStore
+	}
+
+	// JupiterPriceResponse defines the response structure of the Jupiter
+	// price query. Tokens without a price map to null.
+	JupiterPriceResponse struct {
+		Data map[string]*JupiterPrice `json:"data"`
+	}
+
+	// JupiterPrice defines the response structure of the price of a token.
+	JupiterPrice struct {
+		ID    string `json:"id"`
+		Type  string `json:"type"`
+		Price string `json:"price"`
+	}
+
+	// jupiterRateLimitError is returned by a query rejected by the rate
+	// limit of the price API.
+	jupiterRateLimitError struct {
+		retryAfter time.Duration
+	}
+
+	// jupiterPrice defines the price of a token at the time it was polled.
+	jupiterPrice struct {
+		price     sdk.Dec
+		timeStamp int64
+	}
+)
+
+func NewJupiterProvider(
+	ctx context.Context,
+	logger zerolog.Logger,
+	endpoints Endpoint,
+	pairs ...types.CurrencyPair,
+) (*JupiterProvider, error) {
+	if endpoints.Name != ProviderJupiter {
+		endpoints = Endpoint{
+			Name: ProviderJupiter,
+			Rest: jupiterRestHost,
+		}
+	}
+
+	jupiterLogger := logger.With().Str("provider", string(ProviderJupiter)).Logger()
+
+	provider := &JupiterProvider{
+		ctx:        ctx,
+		logger:     jupiterLogger,
+		endpoints:  endpoints,
+		client:     endpoints.httpClient(),
+		mints:      map[string]string{},
+		priceStore: newPriceStore(jupiterLogger),
+	}
+	provider.setCurrencyPairToTickerAndCandlePair(currencyPairToJupiterPair)
+	provider.setMints(pairs...)
+
+	confirmedPairs, err := ConfirmPairAvailability(
+		provider,
+		provider.endpoints.Name,
+		provider.logger,
+		pairs...,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	provider.setSubscribedPairs(confirmedPairs...)
+
+	return provider, nil
+}
+
+// StartConnections starts polling the prices of the subscribed tokens until
+// the provider's context is canceled.
+func (p *JupiterProvider) StartConnections() {
+	go func() {
+		ticker := time.NewTicker(jupiterPricePollInterval)
+		defer ticker.Stop()
+
+		for {
+			p.pollPrices()
+
+			select {
+			case <-p.ctx.Done():
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+}
+
+// SubscribeCurrencyPairs confirms the tokens of the new currency pairs and
+// adds them to the providers subscribedPairs array
+func (p *JupiterProvider) SubscribeCurrencyPairs(cps ...types.CurrencyPair) {
+	p.mtx.Lock()
+	defer p.mtx.Unlock()
+
+	p.setMints(cps...)
+	confirmedPairs, err := ConfirmPairAvailability(
+		p,
+		p.endpoints.Name,
+		p.logger,
+		cps...,
+	)
+	if err != nil {
+		return
+	}
+
+	p.setSubscribedPairs(confirmedPairs...)
+}
+
+// pollPrices queries the prices of the subscribed pairs in batches. The pairs
+// of a failed batch, or without a price, stop contributing prices until they
+// are queried successfully again. Polling pauses while rate limited.
+func (p *JupiterProvider) pollPrices() {
+	p.mtx.Lock()
+	defer p.mtx.Unlock()
+
+	if time.Now().Before(p.retryAfter) {
+		return
+	}
+
+	p.subscribedPairsMtx.RLock()
+	pairs := types.MapPairsToSlice(p.subscribedPairs)
+	p.subscribedPairsMtx.RUnlock()
+
+	for start := 0; start < len(pairs); start += jupiterMaxIDs {
+		end := start + jupiterMaxIDs
+		if end > len(pairs) {
+			end = len(pairs)
+		}
+		batch := pairs[start:end]
+
+		prices, err := p.queryPrices(batch)
+		if err != nil {
+			var rateLimitErr jupiterRateLimitError
+			if errors.As(err, &rateLimitErr) {
+				p.retryAfter = time.Now().Add(rateLimitErr.retryAfter)
+			}
+			for _, cp := range batch {
+				p.disablePair(cp, err)
+			}
+			continue
+		}
+
+		for _, cp := range batch {
+			symbol := currencyPairToJupiterPair(cp)
+			price, ok := prices[symbol]
+			if !ok {
+				p.disablePair(cp, fmt.Errorf("jupiter: no price available"))
+				continue
+			}
+			p.setTickerPair(price, symbol)
+			p.setCandlePair(price, symbol)
+		}
+	}
+}
+
+// disablePair removes the ticker and candles of a pair, so it stops
+// contributing prices.
+func (p *JupiterProvider) disablePair(cp types.CurrencyPair, err error) {
+	symbol := currencyPairToJupiterPair(cp)
+
+	p.tickerMtx.Lock()
+	delete(p.tickers, symbol)
+	p.tickerMtx.Unlock()
+
+	p.candleMtx.Lock()
+	delete(p.candles, symbol)
+	p.candleMtx.Unlock()
+
+	TelemetryFailure(ProviderJupiter, MessageTypeTicker)
+	p.logger.Error().
+		Err(err).
+		Str("pair", cp.String()).
+		Msg("failed to query price; disabling pair until the next successful query")
+}
+
+// queryPrices returns the prices of the pairs with a price by their symbol.
+func (p *JupiterProvider) queryPrices(cps []types.CurrencyPair) (map[string]jupiterPrice, error) {
+	mintSymbols := make(map[string]string, len(cps))
+	mints := make([]string, 0, len(cps))
+	for _, cp := range cps {
+		symbol := currencyPairToJupiterPair(cp)
+		mint := p.mints[symbol]
+		mintSymbols[mint] = symbol
+		mints = append(mints, mint)
+	}
+
+	resp, err := p.get(mints)
+	if err != nil {
+		return nil, err
+	}
+
+	prices := make(map[string]jupiterPrice, len(resp.Data))
+	for mint, symbol := range mintSymbols {
+		data, ok := resp.Data[mint]
+		if !ok || data == nil {
+			continue
+		}
+		price, err := newJupiterPrice(data.Price)
+		if err != nil {
+			p.logger.Debug().Err(err).Str("mint", mint).Msg("skipping invalid price")
+			continue
+		}
+		prices[symbol] = price
+	}
+	return prices, nil
+}
+
+// get queries the USD prices of the given mints.
+func (p *JupiterProvider) get(mints []string) (JupiterPriceResponse, error) {
+	req, err := http.NewRequestWithContext(
+		p.ctx,
+		http.MethodGet,
+		p.endpoints.Rest+jupiterPricePath+"?ids="+strings.Join(mints, ","),
+		nil,
+	)
+	if err != nil {
+		return JupiterPriceResponse{}, err
+	}
+	if p.endpoints.APIKey != "" {
+		req.Header.Set(jupiterAPIKeyHeader, p.endpoints.APIKey)
+	}
+
+	httpResp, err := p.client.Do(req)
+	if err != nil {
+		return JupiterPriceResponse{}, err
+	}
+	defer httpResp.Body.Close()
+
+	switch httpResp.StatusCode {
+	case http.StatusOK:
+	case http.StatusTooManyRequests:
+		return JupiterPriceResponse{}, jupiterRateLimitError{
+			retryAfter: parseRetryAfter(httpResp.Header.Get("Retry-After"), jupiterPricePollInterval),
+		}
+	default:
+		return JupiterPriceResponse{}, fmt.Errorf("jupiter: unexpected status %s querying prices", httpResp.Status)
+	}
+
+	var resp JupiterPriceResponse
+	if err := json.NewDecoder(httpResp.Body).Decode(&resp); err != nil {
+		return JupiterPriceResponse{}, fmt.Errorf("jupiter: failed to decode prices: %w", err)
+	}
+	return resp, nil
+}
+
+// GetAvailablePairs returns all pairs to which the provider can subscribe,
+// being every USD pair whose mint address currently has a price.
+// ex.: map["BONKUSD" => {}, "JUPUSD" => {}].
+func (p *JupiterProvider) GetAvailablePairs() (map[string]struct{}, error) {
+	mints := make([]string, 0, len(p.mints))
+	mintSymbols := make(map[string]string, len(p.mints))
+	for symbol, mint := range p.mints {
+		mints = append(mints, mint)
+		mintSymbols[mint] = symbol
+	}
+
+	availablePairs := make(map[string]struct{}, len(mints))
+	for start := 0; start < len(mints); start += jupiterMaxIDs {
+		end := start + jupiterMaxIDs
+		if end > len(mints) {
+			end = len(mints)
+		}
+
+		resp, err := p.get(mints[start:end])
+		if err != nil {
+			return nil, err
+		}
+		for mint, data := range resp.Data {
+			if symbol, ok := mintSymbols[mint]; ok && data != nil {
+				availablePairs[symbol] = struct{}{}
+			}
+		}
+	}
+
+	return availablePairs, nil
+}
+
+// setMints stores the mint address set as the address of each USD pair.
+func (p *JupiterProvider) setMints(cps ...types.CurrencyPair) {
+	for _, cp := range cps {
+		if cp.Quote != jupiterQuote || cp.Address == "" {
+			p.logger.Error().
+				Str("pair", cp.String()).
+				Msg("jupiter pairs must be quoted in USD and set the mint address of their base")
+			continue
+		}
+		p.mints[currencyPairToJupiterPair(cp)] = cp.Address
+	}
+}
+
+func (e jupiterRateLimitError) Error() string {
+	return fmt.Sprintf("jupiter: rate limited; retrying after %s", e.retryAfter)
+}
+
+func (jp jupiterPrice) toTickerPrice() (types.TickerPrice, error) {
+	return types.TickerPrice{
+		Price:  jp.price,
+		Volume: sdk.ZeroDec(),
+	}, nil
+}
+
+func (jp jupiterPrice) toCandlePrice() (types.CandlePrice, error) {
+	return types.CandlePrice{
+		Price:     jp.price,
+		Volume:    sdk.ZeroDec(),
+		TimeStamp: jp.timeStamp,
+	}, nil
+}
+
+// newJupiterPrice parses a price of the price API.
+func newJupiterPrice(price string) (jupiterPrice, error) {
+	dec, err := sdk.NewDecFromStr(price)
+	if err != nil {
+		return jupiterPrice{}, fmt.Errorf("jupiter: failed to parse price: %w", err)
+	}
+	if !dec.IsPositive() {
+		return jupiterPrice{}, fmt.Errorf("jupiter: no price available")
+	}
+	return jupiterPrice{
+		price:     dec,
+		timeStamp: PastUnixTime(0),
+	}, nil
+}
+
+// parseRetryAfter parses the seconds of a Retry-After header, or returns the
+// fallback if it is missing or not a positive number of seconds.
+func parseRetryAfter(header string, fallback time.Duration) time.Duration {
+	seconds, err := strconv.Atoi(header)
+	if err != nil || seconds <= 0 {
+		return fallback
+	}
+	return time.Duration(seconds) * time.Second
+}
+
+// currencyPairToJupiterPair receives a currency pair and return the symbol
+// the provider stores its prices by, ex.: BONKUSD.
+func currencyPairToJupiterPair(cp types.CurrencyPair) string {
+	return strings.ToUpper(cp.String())
+}
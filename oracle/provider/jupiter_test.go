@@ -0,0 +1,184 @@
+package provider
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/ojo-network/price-feeder/oracle/types"
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	jupiterTestBonkMint = "DezXAZ8z7PnrnRJjz3wXBoRgixCa6xjnB7YaB1pPB263"
+	jupiterTestJupMint  = "JUPyiwrYJFskUPiHa7hkeR8VUtAeFoSYbKedZNsDvCN"
+)
+
+type jupiterTestServer struct {
+	*httptest.Server
+
+	mtx         sync.Mutex
+	prices      map[string]string
+	rateLimited bool
+	apiKey      string
+}
+
+func newJupiterTestServer(t *testing.T) *jupiterTestServer {
+	ts := &jupiterTestServer{
+		prices: map[string]string{
+			jupiterTestBonkMint: "0.00002145",
+			jupiterTestJupMint:  "0.8125",
+		},
+	}
+	ts.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ts.mtx.Lock()
+		defer ts.mtx.Unlock()
+
+		ts.apiKey = r.Header.Get(jupiterAPIKeyHeader)
+		if r.URL.Path != jupiterPricePath {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		if ts.rateLimited {
+			w.Header().Set("Retry-After", "30")
+			w.WriteHeader(http.StatusTooManyRequests)
+			return
+		}
+
+		data := make([]string, 0)
+		for _, mint := range strings.Split(r.URL.Query().Get("ids"), ",") {
+			price, ok := ts.prices[mint]
+			if !ok {
+				data = append(data, `"`+mint+`":null`)
+				continue
+			}
+			data = append(data, `"`+mint+`":{"id":"`+mint+`","type":"derivedPrice","price":"`+price+`"}`)
+		}
+		_, _ = w.Write([]byte(`{"data":{` + strings.Join(data, ",") + `}}`))
+	}))
+	t.Cleanup(ts.Close)
+
+	return ts
+}
+
+func TestJupiterProvider_GetTickerPrices(t *testing.T) {
+	server := newJupiterTestServer(t)
+
+	bonkusd := types.CurrencyPair{Base: "BONK", Quote: "USD", Address: jupiterTestBonkMint}
+	jupusd := types.CurrencyPair{Base: "JUP", Quote: "USD", Address: jupiterTestJupMint}
+	p, err := NewJupiterProvider(
+		context.TODO(),
+		zerolog.Nop(),
+		Endpoint{
+			Name:   ProviderJupiter,
+			Rest:   server.URL,
+			APIKey: "test-key",
+		},
+		bonkusd,
+		jupusd,
+		types.CurrencyPair{Base: "FOO", Quote: "USD", Address: "FooMint"},
+		types.CurrencyPair{Base: "BONK", Quote: "USDT", Address: jupiterTestBonkMint},
+	)
+	require.NoError(t, err)
+	require.Len(t, p.subscribedPairs, 2)
+	require.Equal(t, "test-key", server.apiKey)
+
+	t.Run("valid_request_multi_ticker", func(t *testing.T) {
+		p.pollPrices()
+
+		prices, err := p.GetTickerPrices(bonkusd, jupusd)
+		require.NoError(t, err)
+		require.Len(t, prices, 2)
+		require.Equal(t, sdk.MustNewDecFromStr("0.00002145"), prices[bonkusd].Price)
+		require.Equal(t, sdk.ZeroDec(), prices[bonkusd].Volume)
+		require.Equal(t, sdk.MustNewDecFromStr("0.8125"), prices[jupusd].Price)
+
+		candles, err := p.GetCandlePrices(bonkusd, jupusd)
+		require.NoError(t, err)
+		require.Len(t, candles[jupusd], 1)
+		require.Equal(t, sdk.MustNewDecFromStr("0.8125"), candles[jupusd][0].Price)
+	})
+
+	t.Run("missing_price_disables_pair", func(t *testing.T) {
+		server.mtx.Lock()
+		delete(server.prices, jupiterTestJupMint)
+		server.mtx.Unlock()
+
+		p.pollPrices()
+
+		prices, err := p.GetTickerPrices(bonkusd, jupusd)
+		require.NoError(t, err)
+		require.Len(t, prices, 1)
+		require.Contains(t, prices, bonkusd)
+
+		server.mtx.Lock()
+		server.prices[jupiterTestJupMint] = "0.8125"
+		server.mtx.Unlock()
+
+		p.pollPrices()
+
+		prices, err = p.GetTickerPrices(bonkusd, jupusd)
+		require.NoError(t, err)
+		require.Len(t, prices, 2)
+	})
+
+	t.Run("rate_limit_pauses_polling", func(t *testing.T) {
+		server.mtx.Lock()
+		server.rateLimited = true
+		server.mtx.Unlock()
+
+		p.pollPrices()
+
+		prices, err := p.GetTickerPrices(bonkusd, jupusd)
+		require.NoError(t, err)
+		require.Empty(t, prices)
+		require.WithinDuration(t, time.Now().Add(30*time.Second), p.retryAfter, 5*time.Second)
+
+		server.mtx.Lock()
+		server.rateLimited = false
+		server.mtx.Unlock()
+
+		// polling stays paused until the retry time passes
+		p.pollPrices()
+		prices, err = p.GetTickerPrices(bonkusd, jupusd)
+		require.NoError(t, err)
+		require.Empty(t, prices)
+
+		p.retryAfter = time.Time{}
+		p.pollPrices()
+		prices, err = p.GetTickerPrices(bonkusd, jupusd)
+		require.NoError(t, err)
+		require.Len(t, prices, 2)
+	})
+}
+
+func TestJupiterProvider_GetAvailablePairs(t *testing.T) {
+	server := newJupiterTestServer(t)
+
+	p, err := NewJupiterProvider(
+		context.TODO(),
+		zerolog.Nop(),
+		Endpoint{Name: ProviderJupiter, Rest: server.URL},
+		types.CurrencyPair{Base: "BONK", Quote: "USD", Address: jupiterTestBonkMint},
+		types.CurrencyPair{Base: "FOO", Quote: "USD", Address: "FooMint"},
+	)
+	require.NoError(t, err)
+	require.Empty(t, server.apiKey)
+
+	pairs, err := p.GetAvailablePairs()
+	require.NoError(t, err)
+	require.Equal(t, map[string]struct{}{"BONKUSD": {}}, pairs)
+}
+
+func TestParseRetryAfter(t *testing.T) {
+	require.Equal(t, 30*time.Second, parseRetryAfter("30", time.Second))
+	require.Equal(t, time.Second, parseRetryAfter("", time.Second))
+	require.Equal(t, time.Second, parseRetryAfter("0", time.Second))
+	require.Equal(t, time.Second, parseRetryAfter("Wed, 21 Oct 2015 07:28:00 GMT", time.Second))
+}
@@ -4,7 +4,6 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
-	"net/http"
 	"net/url"
 	"strconv"
 	"strings"
@@ -22,8 +21,21 @@ const (
 	KrakenRestPath                = "/0/public/AssetPairs"
 	krakenEventSystemStatus       = "systemStatus"
 	krakenEventSubscriptionStatus = "subscriptionStatus"
+
+	krakenCandleInterval = "1m"
+	krakenCandleChannel  = "ohlc"
 )
 
+// krakenCandleIntervals maps the supported candle intervals to the Kraken ohlc
+// intervals, in minutes.
+var krakenCandleIntervals = map[string]int{
+	"1m":  1,
+	"5m":  5,
+	"15m": 15,
+	"30m": 30,
+	"1h":  60,
+}
+
 var _ Provider = (*KrakenProvider)(nil)
 
 type (
@@ -43,17 +55,18 @@ type (
 	// KrakenTicker ticker price response from Kraken ticker channel.
 	// REF: https://docs.kraken.com/websockets/#message-ticker
 	KrakenTicker struct {
-		C []string `json:"c"` // Close with Price in the first position
-		V []string `json:"v"` // Volume with the value over last 24 hours in the second position
+		C []types.Number `json:"c"` // Close with Price in the first position
+		V []types.Number `json:"v"` // Volume with the value over last 24 hours in the second position
 	}
 
 	// KrakenCandle candle response from Kraken candle channel.
 	// REF: https://docs.kraken.com/websockets/#message-ohlc
 	KrakenCandle struct {
-		Close     string // Close price during this period
-		TimeStamp int64  // Linux epoch timestamp
-		Volume    string // Volume during this period
-		Symbol    string // Symbol for this candle
+		Close     types.Number  // Close price during this period
+		TimeStamp int64         // End time of the interval in unix epoch seconds
+		Volume    types.Number  // Volume during this period
+		Symbol    string        // Symbol for this candle
+		Interval  time.Duration // Interval of the subscribed channel ex.: 5m for ohlc-5
 	}
 
 	// KrakenSubscriptionMsg Msg to subscribe to all the pairs at once.
@@ -65,7 +78,8 @@ type (
 
 	// KrakenSubscriptionChannel Msg with the channel name to be subscribed.
 	KrakenSubscriptionChannel struct {
-		Name string `json:"name"` // channel to be subscribed ex.: ticker
+		Name     string `json:"name"`               // channel to be subscribed ex.: ticker
+		Interval int    `json:"interval,omitempty"` // ohlc interval in minutes ex.: 5
 	}
 
 	// KrakenEvent wraps the possible events from the provider.
@@ -103,6 +117,10 @@ func NewKrakenProvider(
 			Name:      ProviderKraken,
 			Rest:      KrakenRestHost,
 			Websocket: krakenWSHost,
+			// the candle intervals may be selected for the twap windows
+			CandleIntervals:   endpoints.CandleIntervals,
+			SilenceTimeout:    endpoints.SilenceTimeout,
+			ReconnectCooldown: endpoints.ReconnectCooldown,
 		}
 	}
 
@@ -139,6 +157,11 @@ func NewKrakenProvider(
 		provider.messageReceived,
 		time.Duration(0),
 		websocket.PingMessage,
+		endpoints.SilenceTimeout,
+		endpoints.ReconnectCooldown,
+		&provider.priceStore,
+		endpoints.tlsConfig,
+		endpoints.header,
 		krakenLogger,
 	)
 
@@ -154,7 +177,9 @@ func (p *KrakenProvider) getSubscriptionMsgs(cps ...types.CurrencyPair) []interf
 	for _, cp := range cps {
 		krakenPair := currencyPairToKrakenPair(cp)
 		subscriptionMsgs = append(subscriptionMsgs, newKrakenTickerSubscriptionMsg(krakenPair))
-		subscriptionMsgs = append(subscriptionMsgs, newKrakenCandleSubscriptionMsg(krakenPair))
+		for _, interval := range p.endpoints.candleIntervals(krakenCandleInterval) {
+			subscriptionMsgs = append(subscriptionMsgs, newKrakenCandleSubscriptionMsg(krakenCandleIntervals[interval], krakenPair))
+		}
 	}
 	return subscriptionMsgs
 }
@@ -192,12 +217,19 @@ func (p *KrakenProvider) SubscribeCurrencyPairs(cps ...types.CurrencyPair) {
 	p.setSubscribedPairs(confirmedPairs...)
 }
 
+// toCandlePrice converts the candle, stamped with the end of its interval.
 func (candle KrakenCandle) toCandlePrice() (types.CandlePrice, error) {
-	return types.NewCandlePrice(
-		candle.Close,
-		candle.Volume,
-		candle.TimeStamp,
+	candlePrice, err := types.NewCandlePrice(
+		candle.Close.String(),
+		candle.Volume.String(),
+		// convert seconds -> milli
+		SecondsToMilli(candle.TimeStamp),
 	)
+	if err != nil {
+		return types.CandlePrice{}, err
+	}
+	candlePrice.Interval = candle.Interval
+	return candlePrice, nil
 }
 
 // messageReceived handles any message sent by the provider.
@@ -241,6 +273,7 @@ func (p *KrakenProvider) messageReceived(messageType int, _ *WebsocketConnection
 		AnErr("candle", candleErr).
 		AnErr("event", krakenErr).
 		Msg("Error on receive message")
+	telemetryDecodeError(p.logger, ProviderKraken, bz)
 }
 
 // messageReceivedTickerPrice handles the ticker price msg.
@@ -307,15 +340,15 @@ func (candle *KrakenCandle) UnmarshalJSON(buf []byte) error {
 	}
 	candle.TimeStamp = int64(timeFloat)
 
-	close, ok := tmp[5].(string)
-	if !ok {
-		return fmt.Errorf("close field must be a string")
+	close, err := types.NewNumber(tmp[5])
+	if err != nil {
+		return fmt.Errorf("invalid close field: %w", err)
 	}
 	candle.Close = close
 
-	volume, ok := tmp[7].(string)
-	if !ok {
-		return fmt.Errorf("volume field must be a string")
+	volume, err := types.NewNumber(tmp[7])
+	if err != nil {
+		return fmt.Errorf("invalid volume field: %w", err)
 	}
 	candle.Volume = volume
 
@@ -336,9 +369,13 @@ func (p *KrakenProvider) messageReceivedCandle(bz []byte) error {
 	}
 
 	channelName, ok := candleMessage[2].(string)
-	if !ok || channelName != "ohlc-1" {
+	if !ok || !strings.HasPrefix(channelName, krakenCandleChannel+"-") {
 		return fmt.Errorf("received an unexpected channel name")
 	}
+	interval, err := krakenCandleIntervalDuration(channelName)
+	if err != nil {
+		return err
+	}
 
 	tickerBz, err := json.Marshal(candleMessage[1])
 	if err != nil {
@@ -358,12 +395,23 @@ func (p *KrakenProvider) messageReceivedCandle(bz []byte) error {
 	krakenPair = normalizeKrakenBTCPair(krakenPair)
 	currencyPairSymbol := krakenPairToCurrencyPairSymbol(krakenPair)
 	krakenCandle.Symbol = currencyPairSymbol
+	krakenCandle.Interval = interval
 
 	telemetryWebsocketMessage(ProviderKraken, MessageTypeCandle)
 	p.setCandlePair(krakenCandle, currencyPairSymbol)
 	return nil
 }
 
+// krakenCandleIntervalDuration returns the duration of the interval of the
+// Kraken ohlc channel, ex. 5m for ohlc-5.
+func krakenCandleIntervalDuration(channelName string) (time.Duration, error) {
+	minutes, err := strconv.Atoi(strings.TrimPrefix(channelName, krakenCandleChannel+"-"))
+	if err != nil || minutes <= 0 {
+		return 0, fmt.Errorf("unsupported kraken candle channel: %s", channelName)
+	}
+	return time.Duration(minutes) * time.Minute, nil
+}
+
 // messageReceivedSubscriptionStatus handle the subscription status message
 // sent by the provider.
 func (p *KrakenProvider) messageReceivedSubscriptionStatus(bz []byte) {
@@ -404,7 +452,7 @@ func (p *KrakenProvider) removeSubscribedTickers(tickerSymbols ...string) {
 
 // GetAvailablePairs returns all pairs to which the provider can subscribe.
 func (p *KrakenProvider) GetAvailablePairs() (map[string]struct{}, error) {
-	resp, err := http.Get(p.endpoints.Rest + KrakenRestPath)
+	resp, err := p.endpoints.httpClient().Get(p.endpoints.Rest + KrakenRestPath)
 	if err != nil {
 		return nil, err
 	}
@@ -439,7 +487,7 @@ func (ticker KrakenTicker) toTickerPrice() (types.TickerPrice, error) {
 	}
 	// ticker.C has the Price in the first position.
 	// ticker.V has the totla	Value over last 24 hours in the second position.
-	return types.NewTickerPrice(ticker.C[0], ticker.V[1])
+	return types.NewTickerPrice(ticker.C[0].String(), ticker.V[1].String())
 }
 
 // newKrakenTickerSubscriptionMsg returns a new subscription Msg.
@@ -453,13 +501,15 @@ func newKrakenTickerSubscriptionMsg(pairs ...string) KrakenSubscriptionMsg {
 	}
 }
 
-// newKrakenSubscriptionMsg returns a new subscription Msg.
-func newKrakenCandleSubscriptionMsg(pairs ...string) KrakenSubscriptionMsg {
+// newKrakenCandleSubscriptionMsg returns a new ohlc subscription Msg at the
+// given interval in minutes.
+func newKrakenCandleSubscriptionMsg(interval int, pairs ...string) KrakenSubscriptionMsg {
 	return KrakenSubscriptionMsg{
 		Event: "subscribe",
 		Pair:  pairs,
 		Subscription: KrakenSubscriptionChannel{
-			Name: "ohlc",
+			Name:     krakenCandleChannel,
+			Interval: interval,
 		},
 	}
 }
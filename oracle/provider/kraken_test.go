@@ -3,9 +3,12 @@ package provider
 import (
 	"context"
 	"encoding/json"
+	"fmt"
 	"testing"
+	"time"
 
 	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/gorilla/websocket"
 	"github.com/ojo-network/price-feeder/oracle/types"
 	"github.com/rs/zerolog"
 	"github.com/stretchr/testify/require"
@@ -105,5 +108,46 @@ func TestKrakenProvider_getSubscriptionMsgs(t *testing.T) {
 	require.Equal(t, "{\"event\":\"subscribe\",\"pair\":[\"ATOM/USDT\"],\"subscription\":{\"name\":\"ticker\"}}", string(msg))
 
 	msg, _ = json.Marshal(subMsgs[1])
-	require.Equal(t, "{\"event\":\"subscribe\",\"pair\":[\"ATOM/USDT\"],\"subscription\":{\"name\":\"ohlc\"}}", string(msg))
+	require.Equal(t, "{\"event\":\"subscribe\",\"pair\":[\"ATOM/USDT\"],\"subscription\":{\"name\":\"ohlc\",\"interval\":1}}", string(msg))
+}
+
+func TestKrakenProvider_getSubscriptionMsgs_CandleIntervals(t *testing.T) {
+	provider := &KrakenProvider{endpoints: Endpoint{CandleIntervals: []string{"5m", "15m"}}}
+	subMsgs := provider.getSubscriptionMsgs(types.CurrencyPair{Base: "ATOM", Quote: "USDT"})
+	require.Len(t, subMsgs, 3)
+
+	msg, _ := json.Marshal(subMsgs[1])
+	require.Equal(t, "{\"event\":\"subscribe\",\"pair\":[\"ATOM/USDT\"],\"subscription\":{\"name\":\"ohlc\",\"interval\":5}}", string(msg))
+
+	msg, _ = json.Marshal(subMsgs[2])
+	require.Equal(t, "{\"event\":\"subscribe\",\"pair\":[\"ATOM/USDT\"],\"subscription\":{\"name\":\"ohlc\",\"interval\":15}}", string(msg))
+}
+
+func TestKrakenProvider_messageReceivedCandle(t *testing.T) {
+	p := &KrakenProvider{
+		logger:     zerolog.Nop(),
+		priceStore: newPriceStore(zerolog.Nop()),
+	}
+	p.setSubscribedPairs(BTCUSDT)
+
+	endTime := time.Now().Add(time.Minute).Unix()
+	frame := fmt.Sprintf(
+		`[42,["%d.748456","%d.000000","34567.1","34600.0","34500.0","34589.9","34560.2","12.34567890",118],"ohlc-5","XBT/USDT"]`,
+		endTime-120,
+		endTime,
+	)
+	p.messageReceived(websocket.TextMessage, nil, []byte(frame))
+
+	candles, err := p.GetCandlePrices(BTCUSDT)
+	require.NoError(t, err)
+	require.Len(t, candles[BTCUSDT], 1)
+	candle := candles[BTCUSDT][0]
+	require.Equal(t, sdk.MustNewDecFromStr("34589.9"), candle.Price)
+	require.Equal(t, sdk.MustNewDecFromStr("12.3456789"), candle.Volume)
+	require.Equal(t, 5*time.Minute, candle.Interval)
+	require.Equal(t, SecondsToMilli(endTime), candle.TimeStamp)
+
+	// candles of an unknown channel are ignored
+	err = p.messageReceivedCandle([]byte(`[42,["1","2","3","4","5","6","7","8",9],"ohlc-x","XBT/USDT"]`))
+	require.ErrorContains(t, err, "unsupported kraken candle channel: ohlc-x")
 }
@@ -4,12 +4,10 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
-	"net/http"
 	"net/url"
 	"strings"
 	"sync"
 
-	sdk "github.com/cosmos/cosmos-sdk/types"
 	"github.com/gorilla/websocket"
 	"github.com/ojo-network/price-feeder/oracle/types"
 	"github.com/rs/zerolog"
@@ -41,14 +39,14 @@ type (
 	}
 
 	KujiraTicker struct {
-		Price  string `json:"Price"`
-		Volume string `json:"Volume"`
+		Price  types.Number `json:"Price"`
+		Volume types.Number `json:"Volume"`
 	}
 
 	KujiraCandle struct {
-		Close   string `json:"Close"`
-		Volume  string `json:"Volume"`
-		EndTime int64  `json:"EndTime"`
+		Close   types.Number `json:"Close"`
+		Volume  types.Number `json:"Volume"`
+		EndTime int64        `json:"EndTime"`
 	}
 
 	// KujiraPairsSummary defines the response structure for an Kujira pairs
@@ -72,9 +70,11 @@ func NewKujiraProvider(
 ) (*KujiraProvider, error) {
 	if endpoints.Name != ProviderKujira {
 		endpoints = Endpoint{
-			Name:      ProviderKujira,
-			Rest:      kujiraRestHost,
-			Websocket: kujiraWSHost,
+			Name:              ProviderKujira,
+			Rest:              kujiraRestHost,
+			Websocket:         kujiraWSHost,
+			SilenceTimeout:    endpoints.SilenceTimeout,
+			ReconnectCooldown: endpoints.ReconnectCooldown,
 		}
 	}
 
@@ -114,6 +114,11 @@ func NewKujiraProvider(
 		provider.messageReceived,
 		defaultPingDuration,
 		websocket.PingMessage,
+		endpoints.SilenceTimeout,
+		endpoints.ReconnectCooldown,
+		&provider.priceStore,
+		endpoints.tlsConfig,
+		endpoints.header,
 		kujiraLogger,
 	)
 
@@ -164,6 +169,7 @@ func (p *KujiraProvider) messageReceived(_ int, _ *WebsocketConnection, bz []byt
 			Int("length", len(bz)).
 			AnErr("message", messageErr).
 			Msg("Error on receive message")
+		telemetryDecodeError(p.logger, ProviderKujira, bz)
 	}
 
 	// Check the response for currency pairs that the provider is subscribed
@@ -181,6 +187,7 @@ func (p *KujiraProvider) messageReceived(_ int, _ *WebsocketConnection, bz []byt
 						Int("length", len(bz)).
 						AnErr("ticker", tickerErr).
 						Msg("Error on receive message")
+					telemetryDecodeError(p.logger, ProviderKujira, bz)
 					continue
 				}
 				p.setTickerPair(
@@ -203,6 +210,7 @@ func (p *KujiraProvider) messageReceived(_ int, _ *WebsocketConnection, bz []byt
 						Int("length", len(bz)).
 						AnErr("candle", candleErr).
 						Msg("Error on receive message")
+					telemetryDecodeError(p.logger, ProviderKujira, bz)
 					continue
 				}
 				for _, singleCandle := range candleResp {
@@ -219,11 +227,11 @@ func (p *KujiraProvider) messageReceived(_ int, _ *WebsocketConnection, bz []byt
 }
 
 func (o KujiraTicker) toTickerPrice() (types.TickerPrice, error) {
-	price, err := sdk.NewDecFromStr(o.Price)
+	price, err := o.Price.Dec()
 	if err != nil {
 		return types.TickerPrice{}, fmt.Errorf("kujira: failed to parse ticker price: %w", err)
 	}
-	volume, err := sdk.NewDecFromStr(o.Volume)
+	volume, err := o.Volume.Dec()
 	if err != nil {
 		return types.TickerPrice{}, fmt.Errorf("kujira: failed to parse ticker volume: %w", err)
 	}
@@ -236,11 +244,11 @@ func (o KujiraTicker) toTickerPrice() (types.TickerPrice, error) {
 }
 
 func (o KujiraCandle) toCandlePrice() (types.CandlePrice, error) {
-	close, err := sdk.NewDecFromStr(o.Close)
+	close, err := o.Close.Dec()
 	if err != nil {
 		return types.CandlePrice{}, fmt.Errorf("kujira: failed to parse candle price: %w", err)
 	}
-	volume, err := sdk.NewDecFromStr(o.Volume)
+	volume, err := o.Volume.Dec()
 	if err != nil {
 		return types.CandlePrice{}, fmt.Errorf("kujira: failed to parse candle volume: %w", err)
 	}
@@ -262,7 +270,7 @@ func (p *KujiraProvider) setSubscribedPairs(cps ...types.CurrencyPair) {
 // GetAvailablePairs returns all pairs to which the provider can subscribe.
 // ex.: map["ATOMUSDT" => {}, "OJOUSDC" => {}].
 func (p *KujiraProvider) GetAvailablePairs() (map[string]struct{}, error) {
-	resp, err := http.Get(p.endpoints.Rest + kujiraRestPath)
+	resp, err := p.endpoints.httpClient().Get(p.endpoints.Rest + kujiraRestPath)
 	if err != nil {
 		return nil, err
 	}
@@ -88,8 +88,8 @@ func TestKujiraProvider_GetCandlePrices(t *testing.T) {
 		time := int64(1000000)
 
 		candle := KujiraCandle{
-			Volume:  volume,
-			Close:   price,
+			Volume:  types.Number(volume),
+			Close:   types.Number(price),
 			EndTime: time,
 		}
 
@@ -0,0 +1,363 @@
+package provider
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gorilla/websocket"
+	"github.com/ojo-network/price-feeder/oracle/types"
+	"github.com/rs/zerolog"
+)
+
+const (
+	lbankWSHost   = "www.lbkex.net"
+	lbankWSPath   = "/ws/V2/"
+	lbankRestHost = "https://api.lbkex.com"
+	lbankRestPath = "/v2/currencyPairs.do"
+
+	lbankTickChannel = "tick"
+	lbankKbarChannel = "kbar"
+	lbankKbarPeriod  = "1min"
+	lbankKbarLength  = time.Minute // length of the lbankKbarPeriod kbars
+	lbankPingAction  = "ping"
+	lbankPongAction  = "pong"
+	lbankTimeLayout  = "2006-01-02T15:04:05.000"
+)
+
+var (
+	_ Provider = (*LbankProvider)(nil)
+
+	// lbankLocation is the time zone of the timestamps sent by LBank.
+	lbankLocation = time.FixedZone("CST", 8*60*60)
+)
+
+type (
+	// LbankProvider defines an Oracle provider implemented by the LBank public
+	// API.
+	//
+	// REF: https://www.lbank.com/en-US/docs/index.html#websocket-api
+	LbankProvider struct {
+		wsc       *WebsocketController
+		logger    zerolog.Logger
+		mtx       sync.RWMutex
+		endpoints Endpoint
+
+		priceStore
+	}
+
+	// LbankTickerResponse is the tick channel response object.
+	LbankTickerResponse struct {
+		Type string      `json:"type"` // ex.: tick
+		Pair string      `json:"pair"` // ex.: atom_usdt
+		Tick LbankTicker `json:"tick"`
+	}
+	LbankTicker struct {
+		Latest types.Number `json:"latest"` // Last price ex.: 11.52
+		Volume types.Number `json:"vol"`    // Total traded base asset volume ex.: 1000
+	}
+
+	// LbankCandleResponse is the kbar channel response object.
+	LbankCandleResponse struct {
+		Type string      `json:"type"` // ex.: kbar
+		Pair string      `json:"pair"` // ex.: atom_usdt
+		Kbar LbankCandle `json:"kbar"`
+	}
+	LbankCandle struct {
+		Close     types.Number `json:"c"` // Price at close
+		TimeStamp string       `json:"t"` // Open time ex.: 2023-06-28T17:33:00.000
+		Volume    types.Number `json:"v"` // Volume during period
+	}
+
+	// LbankSubscriptionMsg Msg to subscribe to the tick or kbar channel of a
+	// pair.
+	LbankSubscriptionMsg struct {
+		Action    string `json:"action"`         // subscribe
+		Subscribe string `json:"subscribe"`      // tick or kbar
+		Kbar      string `json:"kbar,omitempty"` // kbar period ex.: 1min
+		Pair      string `json:"pair"`           // ex.: atom_usdt
+	}
+
+	// LbankHeartbeat is the ping and pong message exchanged by the client and
+	// the server to keep the connection alive.
+	LbankHeartbeat struct {
+		Action string `json:"action"`         // ping or pong
+		Ping   string `json:"ping,omitempty"` // ping id
+		Pong   string `json:"pong,omitempty"` // id of the ping answered
+	}
+
+	// LbankPairsSummary defines the response structure for the LBank available
+	// pairs.
+	LbankPairsSummary struct {
+		Data []string `json:"data"` // ex.: ["atom_usdt"]
+	}
+)
+
+func NewLbankProvider(
+	ctx context.Context,
+	logger zerolog.Logger,
+	endpoints Endpoint,
+	pairs ...types.CurrencyPair,
+) (*LbankProvider, error) {
+	if endpoints.Name != ProviderLbank {
+		endpoints = Endpoint{
+			Name:              ProviderLbank,
+			Rest:              lbankRestHost,
+			Websocket:         lbankWSHost,
+			SilenceTimeout:    endpoints.SilenceTimeout,
+			ReconnectCooldown: endpoints.ReconnectCooldown,
+		}
+	}
+
+	wsURL := url.URL{
+		Scheme: "wss",
+		Host:   endpoints.Websocket,
+		Path:   lbankWSPath,
+	}
+
+	lbankLogger := logger.With().Str("provider", "lbank").Logger()
+
+	provider := &LbankProvider{
+		logger:     lbankLogger,
+		endpoints:  endpoints,
+		priceStore: newPriceStore(lbankLogger),
+	}
+	provider.setCurrencyPairToTickerAndCandlePair(currencyPairToLbankPair)
+
+	confirmedPairs, err := ConfirmPairAvailability(
+		provider,
+		provider.endpoints.Name,
+		provider.logger,
+		pairs...,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	provider.setSubscribedPairs(confirmedPairs...)
+
+	provider.wsc = NewWebsocketController(
+		ctx,
+		endpoints.Name,
+		wsURL,
+		provider.getSubscriptionMsgs(confirmedPairs...),
+		provider.messageReceived,
+		defaultPingDuration,
+		websocket.TextMessage,
+		endpoints.SilenceTimeout,
+		endpoints.ReconnectCooldown,
+		&provider.priceStore,
+		endpoints.tlsConfig,
+		endpoints.header,
+		lbankLogger,
+	)
+
+	return provider, nil
+}
+
+func (p *LbankProvider) StartConnections() {
+	p.wsc.StartConnections()
+}
+
+func (p *LbankProvider) getSubscriptionMsgs(cps ...types.CurrencyPair) []interface{} {
+	subscriptionMsgs := make([]interface{}, 0, len(cps)*2)
+	for _, cp := range cps {
+		lbankPair := currencyPairToLbankPair(cp)
+		subscriptionMsgs = append(subscriptionMsgs, newLbankTickerSubscriptionMsg(lbankPair))
+		subscriptionMsgs = append(subscriptionMsgs, newLbankCandleSubscriptionMsg(lbankPair))
+	}
+	return subscriptionMsgs
+}
+
+// SubscribeCurrencyPairs sends the new subscription messages to the websocket
+// and adds them to the providers subscribedPairs array
+func (p *LbankProvider) SubscribeCurrencyPairs(cps ...types.CurrencyPair) {
+	p.mtx.Lock()
+	defer p.mtx.Unlock()
+
+	newPairs := []types.CurrencyPair{}
+	for _, cp := range cps {
+		if _, ok := p.subscribedPairs[cp.String()]; !ok {
+			newPairs = append(newPairs, cp)
+		}
+	}
+
+	confirmedPairs, err := ConfirmPairAvailability(
+		p,
+		p.endpoints.Name,
+		p.logger,
+		newPairs...,
+	)
+	if err != nil {
+		return
+	}
+
+	newSubscriptionMsgs := p.getSubscriptionMsgs(confirmedPairs...)
+	p.wsc.AddWebsocketConnection(
+		newSubscriptionMsgs,
+		p.messageReceived,
+		defaultPingDuration,
+		websocket.TextMessage,
+	)
+	p.setSubscribedPairs(confirmedPairs...)
+}
+
+func (p *LbankProvider) messageReceived(messageType int, conn *WebsocketConnection, bz []byte) {
+	if messageType != websocket.TextMessage {
+		return
+	}
+
+	var (
+		heartbeat  LbankHeartbeat
+		tickerResp LbankTickerResponse
+		tickerErr  error
+		candleResp LbankCandleResponse
+		candleErr  error
+	)
+
+	if err := json.Unmarshal(bz, &heartbeat); err == nil {
+		switch heartbeat.Action {
+		case lbankPingAction:
+			p.pongReceived(conn, heartbeat)
+			return
+		case lbankPongAction:
+			return
+		}
+	}
+
+	tickerErr = json.Unmarshal(bz, &tickerResp)
+	if tickerResp.Type == lbankTickChannel && !tickerResp.Tick.Latest.IsZero() {
+		p.setTickerPair(tickerResp.Tick, tickerResp.Pair)
+		telemetryWebsocketMessage(ProviderLbank, MessageTypeTicker)
+		return
+	}
+
+	candleErr = json.Unmarshal(bz, &candleResp)
+	if candleResp.Type == lbankKbarChannel && !candleResp.Kbar.Close.IsZero() {
+		p.setCandlePair(candleResp.Kbar, candleResp.Pair)
+		telemetryWebsocketMessage(ProviderLbank, MessageTypeCandle)
+		return
+	}
+
+	if tickerErr != nil || candleErr != nil {
+		p.logger.Error().
+			Int("length", len(bz)).
+			AnErr("ticker", tickerErr).
+			AnErr("candle", candleErr).
+			Msg("Error on receive message")
+		telemetryDecodeError(p.logger, ProviderLbank, bz)
+	}
+}
+
+// pongReceived answers a ping sent by the server. LBank closes connections
+// which do not answer its pings within a minute with a pong message carrying
+// the same id, e.g. {"action":"ping","ping":"0ca8f854"} is answered by
+// {"action":"pong","pong":"0ca8f854"}.
+func (p *LbankProvider) pongReceived(conn *WebsocketConnection, heartbeat LbankHeartbeat) {
+	if err := conn.SendJSON(LbankHeartbeat{
+		Action: lbankPongAction,
+		Pong:   heartbeat.Ping,
+	}); err != nil {
+		p.logger.Err(err).Msg("could not send pong message back")
+	}
+}
+
+func (lt LbankTicker) toTickerPrice() (types.TickerPrice, error) {
+	price, err := lt.Latest.Dec()
+	if err != nil {
+		return types.TickerPrice{}, err
+	}
+	volume, err := lt.Volume.Dec()
+	if err != nil {
+		return types.TickerPrice{}, err
+	}
+
+	return types.TickerPrice{
+		Price:  price,
+		Volume: volume,
+	}, nil
+}
+
+func (lc LbankCandle) toCandlePrice() (types.CandlePrice, error) {
+	close, err := lc.Close.Dec()
+	if err != nil {
+		return types.CandlePrice{}, err
+	}
+	volume, err := lc.Volume.Dec()
+	if err != nil {
+		return types.CandlePrice{}, err
+	}
+	timeStamp, err := time.ParseInLocation(lbankTimeLayout, lc.TimeStamp, lbankLocation)
+	if err != nil {
+		return types.CandlePrice{}, fmt.Errorf("failed to parse lbank candle time %s: %w", lc.TimeStamp, err)
+	}
+
+	// the kbars are stamped with their open time, while the candles are
+	// stamped with their close time
+	return types.CandlePrice{
+		Price:     close,
+		Volume:    volume,
+		TimeStamp: timeStamp.Add(lbankKbarLength).UnixMilli(),
+	}, nil
+}
+
+// GetAvailablePairs returns all pairs to which the provider can subscribe.
+// ex.: map["ATOMUSDT" => {}, "OJOUSDC" => {}].
+func (p *LbankProvider) GetAvailablePairs() (map[string]struct{}, error) {
+	resp, err := p.endpoints.httpClient().Get(p.endpoints.Rest + lbankRestPath)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	var pairsSummary LbankPairsSummary
+	if err := json.NewDecoder(resp.Body).Decode(&pairsSummary); err != nil {
+		return nil, err
+	}
+
+	availablePairs := make(map[string]struct{}, len(pairsSummary.Data))
+	for _, pair := range pairsSummary.Data {
+		availablePairs[strings.ToUpper(strings.ReplaceAll(pair, "_", ""))] = struct{}{}
+	}
+
+	return availablePairs, nil
+}
+
+// currencyPairToLbankPair receives a currency pair and returns the lbank
+// pair symbol ex.: atom_usdt.
+func currencyPairToLbankPair(cp types.CurrencyPair) string {
+	return strings.ToLower(cp.Base + "_" + cp.Quote)
+}
+
+// newLbankTickerSubscriptionMsg returns a new tick subscription Msg.
+func newLbankTickerSubscriptionMsg(pair string) LbankSubscriptionMsg {
+	return LbankSubscriptionMsg{
+		Action:    "subscribe",
+		Subscribe: lbankTickChannel,
+		Pair:      pair,
+	}
+}
+
+// newLbankCandleSubscriptionMsg returns a new kbar subscription Msg.
+func newLbankCandleSubscriptionMsg(pair string) LbankSubscriptionMsg {
+	return LbankSubscriptionMsg{
+		Action:    "subscribe",
+		Subscribe: lbankKbarChannel,
+		Kbar:      lbankKbarPeriod,
+		Pair:      pair,
+	}
+}
+
+// newLbankPingMsg returns a new ping message. LBank answers it with a pong
+// message carrying the same id.
+func newLbankPingMsg() []byte {
+	bz, _ := json.Marshal(LbankHeartbeat{
+		Action: lbankPingAction,
+		Ping:   fmt.Sprintf("%d", time.Now().UnixNano()),
+	})
+	return bz
+}
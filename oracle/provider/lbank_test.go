@@ -0,0 +1,92 @@
+package provider
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/gorilla/websocket"
+	"github.com/ojo-network/price-feeder/oracle/types"
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLbankProvider_messageReceived(t *testing.T) {
+	p := &LbankProvider{
+		logger:     zerolog.Nop(),
+		priceStore: newPriceStore(zerolog.Nop()),
+	}
+	p.setCurrencyPairToTickerAndCandlePair(currencyPairToLbankPair)
+	p.setSubscribedPairs(ATOMUSDT)
+
+	tick := `{"tick":{"to_cny":79.12,"high":11.73,"vol":2396974.02,"low":11.21,"change":1.85,` +
+		`"usd":11.52,"to_usd":11.52,"dir":"buy","turnover":27369412.11,"latest":11.52,"cny":79.12},` +
+		`"type":"tick","pair":"atom_usdt","SERVER":"V2","TS":"2023-06-28T17:33:55.188"}`
+	p.messageReceived(websocket.TextMessage, nil, []byte(tick))
+
+	prices, err := p.GetTickerPrices(ATOMUSDT)
+	require.NoError(t, err)
+	require.Len(t, prices, 1)
+	require.Equal(t, sdk.MustNewDecFromStr("11.52"), prices[ATOMUSDT].Price)
+	require.Equal(t, sdk.MustNewDecFromStr("2396974.02"), prices[ATOMUSDT].Volume)
+
+	candleTime := time.Now().Truncate(time.Minute)
+	kbar := `{"kbar":{"a":2103.84,"c":11.53,"t":"` + candleTime.In(lbankLocation).Format(lbankTimeLayout) +
+		`","v":182.47,"h":11.54,"slot":"1min","l":11.51,"n":12,"o":11.52},` +
+		`"type":"kbar","pair":"atom_usdt","SERVER":"V2","TS":"2023-06-28T17:33:10.024"}`
+	p.messageReceived(websocket.TextMessage, nil, []byte(kbar))
+
+	candles, err := p.GetCandlePrices(ATOMUSDT)
+	require.NoError(t, err)
+	require.Len(t, candles[ATOMUSDT], 1)
+	require.Equal(t, sdk.MustNewDecFromStr("11.53"), candles[ATOMUSDT][0].Price)
+	require.Equal(t, sdk.MustNewDecFromStr("182.47"), candles[ATOMUSDT][0].Volume)
+	// the candle is stamped with the close time of the kbar
+	require.Equal(t, candleTime.Add(time.Minute).UnixMilli(), candles[ATOMUSDT][0].TimeStamp)
+
+	// numeric fields may also be sent as strings
+	tick = `{"tick":{"vol":"2396974.02","latest":"11.61"},"type":"tick","pair":"atom_usdt"}`
+	p.messageReceived(websocket.TextMessage, nil, []byte(tick))
+
+	prices, err = p.GetTickerPrices(ATOMUSDT)
+	require.NoError(t, err)
+	require.Equal(t, sdk.MustNewDecFromStr("11.61"), prices[ATOMUSDT].Price)
+
+	// an unparseable field is rejected instead of being stored as zero
+	tick = `{"tick":{"vol":2396974.02,"latest":"n/a"},"type":"tick","pair":"atom_usdt"}`
+	p.messageReceived(websocket.TextMessage, nil, []byte(tick))
+
+	prices, err = p.GetTickerPrices(ATOMUSDT)
+	require.NoError(t, err)
+	require.Equal(t, sdk.MustNewDecFromStr("11.61"), prices[ATOMUSDT].Price)
+}
+
+func TestLbankCurrencyPairToLbankPair(t *testing.T) {
+	cp := types.CurrencyPair{Base: "ATOM", Quote: "USDT"}
+	lbankSymbol := currencyPairToLbankPair(cp)
+	require.Equal(t, lbankSymbol, "atom_usdt")
+}
+
+func TestLbankProvider_getSubscriptionMsgs(t *testing.T) {
+	provider := &LbankProvider{}
+	cps := []types.CurrencyPair{
+		{Base: "ATOM", Quote: "USDT"},
+	}
+	subMsgs := provider.getSubscriptionMsgs(cps...)
+
+	msg, _ := json.Marshal(subMsgs[0])
+	require.Equal(t, `{"action":"subscribe","subscribe":"tick","pair":"atom_usdt"}`, string(msg))
+
+	msg, _ = json.Marshal(subMsgs[1])
+	require.Equal(t, `{"action":"subscribe","subscribe":"kbar","kbar":"1min","pair":"atom_usdt"}`, string(msg))
+}
+
+func TestLbankProvider_pingMessage(t *testing.T) {
+	var heartbeat LbankHeartbeat
+	require.NoError(t, json.Unmarshal(pingMessage(ProviderLbank), &heartbeat))
+	require.Equal(t, lbankPingAction, heartbeat.Action)
+	require.NotEmpty(t, heartbeat.Ping)
+
+	require.Equal(t, ping, pingMessage(ProviderMexc))
+}
@@ -3,16 +3,15 @@ package provider
 import (
 	"context"
 	"encoding/json"
-	"net/http"
+	"fmt"
 	"net/url"
 	"strings"
 	"sync"
+	"time"
 
 	"github.com/gorilla/websocket"
 	"github.com/ojo-network/price-feeder/oracle/types"
 	"github.com/rs/zerolog"
-
-	"github.com/ojo-network/ojo/util/decmath"
 )
 
 const (
@@ -20,8 +19,21 @@ const (
 	mexcWSPath   = "/raw/ws"
 	mexcRestHost = "https://www.mexc.com"
 	mexcRestPath = "/open/api/v2/market/ticker"
+
+	mexcCandleInterval = "1m"
+	mexcCandleChannel  = "push.kline"
 )
 
+// mexcCandleIntervals maps the supported candle intervals to the Mexc kline
+// intervals.
+var mexcCandleIntervals = map[string]string{
+	"1m":  "Min1",
+	"5m":  "Min5",
+	"15m": "Min15",
+	"30m": "Min30",
+	"1h":  "Min60",
+}
+
 var _ Provider = (*MexcProvider)(nil)
 
 type (
@@ -45,19 +57,23 @@ type (
 		Symbol map[string]MexcTicker `json:"data"` // e.x. ATOM_USDT
 	}
 	MexcTicker struct {
-		LastPrice float64 `json:"p"` // Last price ex.: 0.0025
-		Volume    float64 `json:"v"` // Total traded base asset volume ex.: 1000
+		LastPrice types.Number `json:"p"` // Last price ex.: 0.0025
+		Volume    types.Number `json:"v"` // Total traded base asset volume ex.: 1000
 	}
 
-	// MexcCandle is the candle websocket response object.
+	// MexcCandleResponse is the candle websocket response object. The raw
+	// websocket pushes JSON frames, unlike the protobuf frames of the newer
+	// Mexc websocket, so no protobuf decoding is needed.
 	MexcCandleResponse struct {
-		Symbol   string     `json:"symbol"` // Symbol ex.: ATOM_USDT
-		Metadata MexcCandle `json:"data"`   // Metadata for candle
+		Channel  string     `json:"channel"` // Channel ex.: push.kline
+		Symbol   string     `json:"symbol"`  // Symbol ex.: ATOM_USDT
+		Metadata MexcCandle `json:"data"`    // Metadata for candle
 	}
 	MexcCandle struct {
-		Close     float64 `json:"c"` // Price at close
-		TimeStamp int64   `json:"t"` // Close time in unix epoch ex.: 1645756200000
-		Volume    float64 `json:"v"` // Volume during period
+		Close     types.Number `json:"c"`        // Price at close
+		TimeStamp int64        `json:"t"`        // Open time in unix epoch seconds ex.: 1645756200
+		Volume    types.Number `json:"q"`        // Traded base asset volume during period
+		Interval  string       `json:"interval"` // Interval ex.: Min1
 	}
 
 	// MexcCandleSubscription Msg to subscribe all the candle channels.
@@ -95,6 +111,10 @@ func NewMexcProvider(
 			Name:      ProviderMexc,
 			Rest:      mexcRestHost,
 			Websocket: mexcWSHost,
+			// the candle intervals may be selected for the twap windows
+			CandleIntervals:   endpoints.CandleIntervals,
+			SilenceTimeout:    endpoints.SilenceTimeout,
+			ReconnectCooldown: endpoints.ReconnectCooldown,
 		}
 	}
 
@@ -133,6 +153,11 @@ func NewMexcProvider(
 		provider.messageReceived,
 		defaultPingDuration,
 		websocket.PingMessage,
+		endpoints.SilenceTimeout,
+		endpoints.ReconnectCooldown,
+		&provider.priceStore,
+		endpoints.tlsConfig,
+		endpoints.header,
 		mexcLogger,
 	)
 
@@ -147,7 +172,9 @@ func (p *MexcProvider) getSubscriptionMsgs(cps ...types.CurrencyPair) []interfac
 	subscriptionMsgs := make([]interface{}, 0, len(cps)+1)
 	for _, cp := range cps {
 		mexcPair := currencyPairToMexcPair(cp)
-		subscriptionMsgs = append(subscriptionMsgs, newMexcCandleSubscriptionMsg(mexcPair))
+		for _, interval := range p.endpoints.candleIntervals(mexcCandleInterval) {
+			subscriptionMsgs = append(subscriptionMsgs, newMexcCandleSubscriptionMsg(mexcPair, mexcCandleIntervals[interval]))
+		}
 	}
 	subscriptionMsgs = append(subscriptionMsgs, newMexcTickerSubscriptionMsg())
 	return subscriptionMsgs
@@ -194,10 +221,24 @@ func (p *MexcProvider) messageReceived(_ int, _ *WebsocketConnection, bz []byte)
 		candleErr  error
 	)
 
+	candleErr = json.Unmarshal(bz, &candleResp)
+	if candleResp.Channel == mexcCandleChannel {
+		if candleErr == nil && !candleResp.Metadata.Close.IsZero() {
+			p.setCandlePair(candleResp.Metadata, candleResp.Symbol)
+			telemetryWebsocketMessage(ProviderMexc, MessageTypeCandle)
+			return
+		}
+		p.logger.Error().
+			Int("length", len(bz)).
+			AnErr("candle", candleErr).
+			Msg("mexc: Error on receive candle")
+		return
+	}
+
 	tickerErr = json.Unmarshal(bz, &tickerResp)
 	for _, cp := range p.subscribedPairs {
 		mexcPair := currencyPairToMexcPair(cp)
-		if tickerResp.Symbol[mexcPair].LastPrice != 0 {
+		if !tickerResp.Symbol[mexcPair].LastPrice.IsZero() {
 			p.setTickerPair(
 				tickerResp.Symbol[mexcPair],
 				mexcPair,
@@ -207,28 +248,22 @@ func (p *MexcProvider) messageReceived(_ int, _ *WebsocketConnection, bz []byte)
 		}
 	}
 
-	candleErr = json.Unmarshal(bz, &candleResp)
-	if candleResp.Metadata.Close != 0 {
-		p.setCandlePair(candleResp.Metadata, candleResp.Symbol)
-		telemetryWebsocketMessage(ProviderMexc, MessageTypeCandle)
-		return
-	}
-
 	if tickerErr != nil || candleErr != nil {
 		p.logger.Error().
 			Int("length", len(bz)).
 			AnErr("ticker", tickerErr).
 			AnErr("candle", candleErr).
 			Msg("mexc: Error on receive message")
+		telemetryDecodeError(p.logger, ProviderMexc, bz)
 	}
 }
 
 func (mt MexcTicker) toTickerPrice() (types.TickerPrice, error) {
-	price, err := decmath.NewDecFromFloat(mt.LastPrice)
+	price, err := mt.LastPrice.Dec()
 	if err != nil {
 		return types.TickerPrice{}, err
 	}
-	volume, err := decmath.NewDecFromFloat(mt.Volume)
+	volume, err := mt.Volume.Dec()
 	if err != nil {
 		return types.TickerPrice{}, err
 	}
@@ -240,12 +275,18 @@ func (mt MexcTicker) toTickerPrice() (types.TickerPrice, error) {
 	return ticker, nil
 }
 
+// toCandlePrice converts the candle, stamped with the close of its interval
+// like the candles of the other providers.
 func (mc MexcCandle) toCandlePrice() (types.CandlePrice, error) {
-	close, err := decmath.NewDecFromFloat(mc.Close)
+	close, err := mc.Close.Dec()
+	if err != nil {
+		return types.CandlePrice{}, err
+	}
+	volume, err := mc.Volume.Dec()
 	if err != nil {
 		return types.CandlePrice{}, err
 	}
-	volume, err := decmath.NewDecFromFloat(mc.Volume)
+	interval, err := mexcCandleIntervalDuration(mc.Interval)
 	if err != nil {
 		return types.CandlePrice{}, err
 	}
@@ -253,11 +294,22 @@ func (mc MexcCandle) toCandlePrice() (types.CandlePrice, error) {
 		Price:  close,
 		Volume: volume,
 		// convert seconds -> milli
-		TimeStamp: SecondsToMilli(mc.TimeStamp),
+		TimeStamp: SecondsToMilli(mc.TimeStamp) + interval.Milliseconds(),
+		Interval:  interval,
 	}
 	return candle, nil
 }
 
+// mexcCandleIntervalDuration returns the duration of the Mexc kline interval.
+func mexcCandleIntervalDuration(mexcInterval string) (time.Duration, error) {
+	for interval, name := range mexcCandleIntervals {
+		if name == mexcInterval {
+			return time.ParseDuration(interval)
+		}
+	}
+	return 0, fmt.Errorf("unsupported mexc candle interval: %s", mexcInterval)
+}
+
 // setSubscribedPairs sets N currency pairs to the map of subscribed pairs.
 func (p *MexcProvider) setSubscribedPairs(cps ...types.CurrencyPair) {
 	for _, cp := range cps {
@@ -268,7 +320,7 @@ func (p *MexcProvider) setSubscribedPairs(cps ...types.CurrencyPair) {
 // GetAvailablePairs returns all pairs to which the provider can subscribe.
 // ex.: map["ATOMUSDT" => {}, "OJOUSDC" => {}].
 func (p *MexcProvider) GetAvailablePairs() (map[string]struct{}, error) {
-	resp, err := http.Get(p.endpoints.Rest + mexcRestPath)
+	resp, err := p.endpoints.httpClient().Get(p.endpoints.Rest + mexcRestPath)
 	if err != nil {
 		return nil, err
 	}
@@ -294,11 +346,11 @@ func currencyPairToMexcPair(cp types.CurrencyPair) string {
 }
 
 // newMexcCandleSubscriptionMsg returns a new candle subscription Msg.
-func newMexcCandleSubscriptionMsg(param string) MexcCandleSubscription {
+func newMexcCandleSubscriptionMsg(param, interval string) MexcCandleSubscription {
 	return MexcCandleSubscription{
 		OP:       "sub.kline",
 		Symbol:   param,
-		Interval: "Min1",
+		Interval: interval,
 	}
 }
 
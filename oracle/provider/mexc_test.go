@@ -3,9 +3,12 @@ package provider
 import (
 	"context"
 	"encoding/json"
+	"fmt"
 	"testing"
+	"time"
 
 	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/gorilla/websocket"
 	"github.com/ojo-network/price-feeder/oracle/types"
 	"github.com/rs/zerolog"
 	"github.com/stretchr/testify/require"
@@ -93,3 +96,69 @@ func TestMexcProvider_getSubscriptionMsgs(t *testing.T) {
 	msg, _ = json.Marshal(subMsgs[1])
 	require.Equal(t, "{\"op\":\"sub.overview\"}", string(msg))
 }
+
+func TestMexcProvider_getSubscriptionMsgs_CandleIntervals(t *testing.T) {
+	provider := &MexcProvider{endpoints: Endpoint{CandleIntervals: []string{"1m", "1h"}}}
+	subMsgs := provider.getSubscriptionMsgs(types.CurrencyPair{Base: "ATOM", Quote: "USDT"})
+	require.Len(t, subMsgs, 3)
+
+	msg, _ := json.Marshal(subMsgs[0])
+	require.Equal(t, "{\"op\":\"sub.kline\",\"symbol\":\"ATOM_USDT\",\"interval\":\"Min1\"}", string(msg))
+
+	msg, _ = json.Marshal(subMsgs[1])
+	require.Equal(t, "{\"op\":\"sub.kline\",\"symbol\":\"ATOM_USDT\",\"interval\":\"Min60\"}", string(msg))
+}
+
+func TestMexcProvider_messageReceived(t *testing.T) {
+	p := &MexcProvider{
+		logger:     zerolog.Nop(),
+		priceStore: newPriceStore(zerolog.Nop()),
+	}
+	p.setCurrencyPairToTickerAndCandlePair(currencyPairToMexcPair)
+	p.setSubscribedPairs(ATOMUSDT)
+
+	t.Run("candle", func(t *testing.T) {
+		openTime := time.Now().Add(-time.Minute).Unix()
+		frame := fmt.Sprintf(
+			`{"channel":"push.kline","data":{"a":"2213.4717","c":"10.542","h":"10.547","interval":"Min1",`+
+				`"l":"10.538","o":"10.541","q":"210.02","symbol":"ATOM_USDT","t":%d},"symbol":"ATOM_USDT"}`,
+			openTime,
+		)
+		p.messageReceived(websocket.TextMessage, nil, []byte(frame))
+
+		candles, err := p.GetCandlePrices(ATOMUSDT)
+		require.NoError(t, err)
+		require.Len(t, candles[ATOMUSDT], 1)
+		candle := candles[ATOMUSDT][0]
+		require.Equal(t, sdk.MustNewDecFromStr("10.542"), candle.Price)
+		require.Equal(t, sdk.MustNewDecFromStr("210.02"), candle.Volume)
+		require.Equal(t, time.Minute, candle.Interval)
+		require.Equal(t, SecondsToMilli(openTime)+time.Minute.Milliseconds(), candle.TimeStamp)
+
+		tickers, err := p.GetTickerPrices(ATOMUSDT)
+		require.NoError(t, err)
+		require.Empty(t, tickers)
+	})
+
+	t.Run("ticker", func(t *testing.T) {
+		frame := `{"channel":"push.overview","data":{"ATOM_USDT":{"p":"10.543","v":"183209.1"}}}`
+		p.messageReceived(websocket.TextMessage, nil, []byte(frame))
+
+		tickers, err := p.GetTickerPrices(ATOMUSDT)
+		require.NoError(t, err)
+		require.Equal(t, sdk.MustNewDecFromStr("10.543"), tickers[ATOMUSDT].Price)
+		require.Equal(t, sdk.MustNewDecFromStr("183209.1"), tickers[ATOMUSDT].Volume)
+	})
+}
+
+func TestMexcCandle_toCandlePrice(t *testing.T) {
+	candle := MexcCandle{Close: "10.542", TimeStamp: 1645756200, Volume: "210.02", Interval: "Min5"}
+	candlePrice, err := candle.toCandlePrice()
+	require.NoError(t, err)
+	require.Equal(t, int64(1645756500000), candlePrice.TimeStamp)
+	require.Equal(t, 5*time.Minute, candlePrice.Interval)
+
+	candle.Interval = "Week1"
+	_, err = candle.toCandlePrice()
+	require.Error(t, err)
+}
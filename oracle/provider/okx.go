@@ -3,7 +3,6 @@ package provider
 import (
 	"context"
 	"encoding/json"
-	"net/http"
 	"net/url"
 	"strconv"
 	"strings"
@@ -21,9 +20,14 @@ const (
 	okxWSPathBusiness = "/ws/v5/business"
 	okxRestHost       = "https://www.okx.com"
 	okxRestPath       = "/api/v5/market/tickers?instType=SPOT"
+	okxRestIndexPath  = "/api/v5/market/index-tickers?quoteCcy="
+	okxSwapSuffix     = "-SWAP"
 )
 
-var _ Provider = (*OkxProvider)(nil)
+var (
+	_ Provider          = (*OkxProvider)(nil)
+	_ BestQuoteProvider = (*OkxProvider)(nil)
+)
 
 type (
 	// OkxProvider defines an Oracle provider implemented by the Okx public
@@ -36,6 +40,18 @@ type (
 		mtx       sync.RWMutex
 		endpoints Endpoint
 
+		// priceTypes holds the pairs priced by a perpetual mark or index
+		// price instead of the spot market, ex.: map["BTC-USDT"] = "mark"
+		priceTypes  map[string]PriceType
+		derivatives derivativeStore
+
+		// priceSources holds the pairs priced by their best quote instead of
+		// the last traded price, ex.: map["BTC-USDT"] = "mid"
+		priceSources map[string]PriceSource
+
+		// bestQuotes holds the latest best bid and ask of the spot pairs
+		bestQuotes bestQuoteStore
+
 		priceStore
 	}
 
@@ -47,8 +63,26 @@ type (
 	// OkxTickerPair defines a ticker pair of Okx.
 	OkxTickerPair struct {
 		OkxInstID
-		Last   string `json:"last"`   // Last traded price ex.: 43508.9
-		Vol24h string `json:"vol24h"` // 24h trading volume ex.: 11159.87127845
+		Last      types.Number `json:"last"`      // Last traded price ex.: 43508.9
+		Vol24h    types.Number `json:"vol24h"`    // 24h trading volume ex.: 11159.87127845
+		VolCcy24h types.Number `json:"volCcy24h"` // 24h trading volume in base currency for derivatives
+		BidPx     types.Number `json:"bidPx"`     // Best bid price ex.: 43508.8
+		AskPx     types.Number `json:"askPx"`     // Best ask price ex.: 43509.0
+		TimeStamp int64        `json:"ts,string"` // Ticker time in unix milliseconds
+	}
+
+	// OkxDerivativePrice defines a mark or index price of Okx.
+	OkxDerivativePrice struct {
+		OkxInstID
+		MarkPx types.Number `json:"markPx"` // Mark price ex.: 43508.9
+		IdxPx  types.Number `json:"idxPx"`  // Index price ex.: 43508.9
+	}
+
+	// OkxDerivativePriceResponse defines the response structure of a Okx
+	// mark price or index ticker request.
+	OkxDerivativePriceResponse struct {
+		Data []OkxDerivativePrice `json:"data"`
+		ID   OkxID                `json:"arg"`
 	}
 
 	// OkxInst defines the structure containing ID information for the OkxResponses.
@@ -65,10 +99,10 @@ type (
 
 	// OkxCandlePair defines a candle for Okx.
 	OkxCandlePair struct {
-		Close     string `json:"c"`      // Close price for this time period
-		TimeStamp int64  `json:"ts"`     // Linux epoch timestamp
-		Volume    string `json:"vol"`    // Volume for this time period
-		InstID    string `json:"instId"` // Instrument ID ex.: BTC-USDT
+		Close     types.Number `json:"c"`      // Close price for this time period
+		TimeStamp int64        `json:"ts"`     // Linux epoch timestamp
+		Volume    types.Number `json:"vol"`    // Volume for this time period
+		InstID    string       `json:"instId"` // Instrument ID ex.: BTC-USDT
 	}
 
 	// OkxCandleResponse defines the response structure of a Okx candle request.
@@ -104,9 +138,11 @@ func NewOkxProvider(
 ) (*OkxProvider, error) {
 	if endpoints.Name != ProviderOkx {
 		endpoints = Endpoint{
-			Name:      ProviderOkx,
-			Rest:      okxRestHost,
-			Websocket: okxWSHost,
+			Name:              ProviderOkx,
+			Rest:              okxRestHost,
+			Websocket:         okxWSHost,
+			SilenceTimeout:    endpoints.SilenceTimeout,
+			ReconnectCooldown: endpoints.ReconnectCooldown,
 		}
 	}
 
@@ -119,9 +155,14 @@ func NewOkxProvider(
 	okxLogger := logger.With().Str("provider", string(ProviderOkx)).Logger()
 
 	provider := &OkxProvider{
-		logger:     okxLogger,
-		endpoints:  endpoints,
-		priceStore: newPriceStore(okxLogger),
+		logger:      okxLogger,
+		endpoints:   endpoints,
+		priceTypes:  endpoints.pairPriceTypes(currencyPairToOkxPair),
+		derivatives: newDerivativeStore(),
+		priceStore:  newPriceStore(okxLogger),
+
+		priceSources: endpoints.pairPriceSources(currencyPairToOkxPair),
+		bestQuotes:   newBestQuoteStore(),
 	}
 	provider.setCurrencyPairToTickerAndCandlePair(currencyPairToOkxPair)
 
@@ -145,6 +186,11 @@ func NewOkxProvider(
 		provider.messageReceived,
 		defaultPingDuration,
 		websocket.PingMessage,
+		endpoints.SilenceTimeout,
+		endpoints.ReconnectCooldown,
+		&provider.priceStore,
+		endpoints.tlsConfig,
+		endpoints.header,
 		okxLogger,
 	)
 
@@ -159,11 +205,32 @@ func (p *OkxProvider) getSubscriptionMsgs(cps ...types.CurrencyPair) []interface
 	subscriptionMsgs := make([]interface{}, 0, len(cps)*2)
 	for _, cp := range cps {
 		okxPair := currencyPairToOkxPair(cp)
-		okxTopic := newOkxCandleSubscriptionTopic(okxPair)
-		subscriptionMsgs = append(subscriptionMsgs, newOkxSubscriptionMsg(okxTopic))
 
-		okxTopic = newOkxTickerSubscriptionTopic(okxPair)
-		subscriptionMsgs = append(subscriptionMsgs, newOkxSubscriptionMsg(okxTopic))
+		switch p.priceTypes[okxPair] {
+		case PriceTypeMark:
+			okxTopic := newOkxMarkPriceSubscriptionTopic(okxPair + okxSwapSuffix)
+			subscriptionMsgs = append(subscriptionMsgs, newOkxSubscriptionMsg(okxTopic))
+
+			okxTopic = newOkxTickerSubscriptionTopic(okxPair + okxSwapSuffix)
+			subscriptionMsgs = append(subscriptionMsgs, newOkxSubscriptionMsg(okxTopic))
+
+		case PriceTypeIndex:
+			okxTopic := newOkxIndexTickerSubscriptionTopic(okxPair)
+			subscriptionMsgs = append(subscriptionMsgs, newOkxSubscriptionMsg(okxTopic))
+
+			okxTopic = newOkxTickerSubscriptionTopic(okxPair + okxSwapSuffix)
+			subscriptionMsgs = append(subscriptionMsgs, newOkxSubscriptionMsg(okxTopic))
+
+		default:
+			// candles are built from trades and would outweigh the best quote
+			if _, ok := p.priceSources[okxPair]; !ok {
+				okxTopic := newOkxCandleSubscriptionTopic(okxPair)
+				subscriptionMsgs = append(subscriptionMsgs, newOkxSubscriptionMsg(okxTopic))
+			}
+
+			okxTopic := newOkxTickerSubscriptionTopic(okxPair)
+			subscriptionMsgs = append(subscriptionMsgs, newOkxSubscriptionMsg(okxTopic))
+		}
 	}
 	return subscriptionMsgs
 }
@@ -203,22 +270,57 @@ func (p *OkxProvider) SubscribeCurrencyPairs(cps ...types.CurrencyPair) {
 
 func (p *OkxProvider) messageReceived(_ int, _ *WebsocketConnection, bz []byte) {
 	var (
-		tickerResp OkxTickerResponse
-		tickerErr  error
-		candleResp OkxCandleResponse
-		candleErr  error
+		tickerResp     OkxTickerResponse
+		tickerErr      error
+		candleResp     OkxCandleResponse
+		candleErr      error
+		derivativeResp OkxDerivativePriceResponse
+		derivativeErr  error
 	)
 
 	// sometimes the message received is not a ticker or a candle response.
 	tickerErr = json.Unmarshal(bz, &tickerResp)
 	if tickerResp.ID.Channel == "tickers" {
 		for _, tickerPair := range tickerResp.Data {
+			if strings.HasSuffix(tickerPair.InstID, okxSwapSuffix) {
+				okxPair := strings.TrimSuffix(tickerPair.InstID, okxSwapSuffix)
+				if ticker, ok := p.derivatives.setVolume(okxPair, tickerPair.VolCcy24h.String()); ok {
+					p.setTickerPair(ticker, okxPair)
+				}
+				telemetryWebsocketMessage(ProviderOkx, MessageTypeTicker)
+				continue
+			}
+			p.bestQuotes.setBestQuote(tickerPair.InstID, tickerPair.BidPx, tickerPair.AskPx)
+			if source, ok := p.priceSources[tickerPair.InstID]; ok {
+				price, err := source.quotePrice(tickerPair.Last, tickerPair.BidPx, tickerPair.AskPx)
+				if err != nil {
+					p.logger.Error().Err(err).Str("instId", tickerPair.InstID).Msg("failed to get best quote price")
+					continue
+				}
+				tickerPair.Last = price
+			}
 			p.setTickerPair(tickerPair, tickerPair.InstID)
 			telemetryWebsocketMessage(ProviderOkx, MessageTypeTicker)
 		}
 		return
 	}
 
+	derivativeErr = json.Unmarshal(bz, &derivativeResp)
+	if derivativeResp.ID.Channel == "mark-price" || derivativeResp.ID.Channel == "index-tickers" {
+		for _, derivativePrice := range derivativeResp.Data {
+			okxPair := strings.TrimSuffix(derivativePrice.InstID, okxSwapSuffix)
+			price := derivativePrice.MarkPx
+			if derivativeResp.ID.Channel == "index-tickers" {
+				price = derivativePrice.IdxPx
+			}
+			if ticker, ok := p.derivatives.setPrice(okxPair, price.String()); ok {
+				p.setTickerPair(ticker, okxPair)
+			}
+			telemetryWebsocketMessage(ProviderOkx, MessageTypeTicker)
+		}
+		return
+	}
+
 	candleErr = json.Unmarshal(bz, &candleResp)
 	if candleResp.ID.Channel == "candle1m" {
 		currencyPairString := candleResp.ID.InstID
@@ -229,9 +331,9 @@ func (p *OkxProvider) messageReceived(_ int, _ *WebsocketConnection, bz []byte)
 				return
 			}
 			candle := OkxCandlePair{
-				Close:     pairData[4],
+				Close:     types.Number(pairData[4]),
 				InstID:    currencyPairString,
-				Volume:    pairData[5],
+				Volume:    types.Number(pairData[5]),
 				TimeStamp: ts,
 			}
 			p.setCandlePair(candle, currencyPairString)
@@ -244,27 +346,44 @@ func (p *OkxProvider) messageReceived(_ int, _ *WebsocketConnection, bz []byte)
 		Int("length", len(bz)).
 		AnErr("ticker", tickerErr).
 		AnErr("candle", candleErr).
+		AnErr("derivative", derivativeErr).
 		Msg("Error on receive message")
+	telemetryDecodeError(p.logger, ProviderOkx, bz)
 }
 
-// GetAvailablePairs return all available pairs symbol to subscribe.
+// GetBestQuotes returns the latest best bid and ask of the spot pairs.
+func (p *OkxProvider) GetBestQuotes(cps ...types.CurrencyPair) map[types.CurrencyPair]BestQuote {
+	return p.bestQuotes.bestQuotes(currencyPairToOkxPair, cps...)
+}
+
+// GetAvailablePairs return all available pairs symbol to subscribe. The
+// indices quoted in the quotes of the index priced pairs are available too,
+// as indices such as BTC-USD have no spot market.
 func (p *OkxProvider) GetAvailablePairs() (map[string]struct{}, error) {
-	resp, err := http.Get(p.endpoints.Rest + okxRestPath)
+	instIDs, err := p.getInstIDs(okxRestPath)
 	if err != nil {
 		return nil, err
 	}
-	defer resp.Body.Close()
 
-	var pairsSummary struct {
-		Data []OkxInstID `json:"data"`
+	indexQuotes := make(map[string]struct{})
+	for okxPair, priceType := range p.priceTypes {
+		if priceType != PriceTypeIndex {
+			continue
+		}
+		if splitInstID := strings.Split(okxPair, "-"); len(splitInstID) == 2 {
+			indexQuotes[splitInstID[1]] = struct{}{}
+		}
 	}
-
-	if err := json.NewDecoder(resp.Body).Decode(&pairsSummary); err != nil {
-		return nil, err
+	for quote := range indexQuotes {
+		indexIDs, err := p.getInstIDs(okxRestIndexPath + quote)
+		if err != nil {
+			return nil, err
+		}
+		instIDs = append(instIDs, indexIDs...)
 	}
 
-	availablePairs := make(map[string]struct{}, len(pairsSummary.Data))
-	for _, pair := range pairsSummary.Data {
+	availablePairs := make(map[string]struct{}, len(instIDs))
+	for _, pair := range instIDs {
 		splitInstID := strings.Split(pair.InstID, "-")
 		if len(splitInstID) != 2 {
 			continue
@@ -280,12 +399,31 @@ func (p *OkxProvider) GetAvailablePairs() (map[string]struct{}, error) {
 	return availablePairs, nil
 }
 
+// getInstIDs returns the instrument IDs listed by the given REST path.
+func (p *OkxProvider) getInstIDs(path string) ([]OkxInstID, error) {
+	resp, err := p.endpoints.httpClient().Get(p.endpoints.Rest + path)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	var pairsSummary OkxPairsSummary
+	if err := json.NewDecoder(resp.Body).Decode(&pairsSummary); err != nil {
+		return nil, err
+	}
+	return pairsSummary.Data, nil
+}
+
 func (ticker OkxTickerPair) toTickerPrice() (types.TickerPrice, error) {
-	return types.NewTickerPrice(ticker.Last, ticker.Vol24h)
+	return types.NewTickerPrice(ticker.Last.String(), ticker.Vol24h.String())
+}
+
+func (ticker OkxTickerPair) exchangeTime() int64 {
+	return ticker.TimeStamp
 }
 
 func (candle OkxCandlePair) toCandlePrice() (types.CandlePrice, error) {
-	return types.NewCandlePrice(candle.Close, candle.Volume, candle.TimeStamp)
+	return types.NewCandlePrice(candle.Close.String(), candle.Volume.String(), candle.TimeStamp)
 }
 
 // currencyPairToOkxPair returns the expected pair instrument ID for Okx
@@ -310,6 +448,24 @@ func newOkxCandleSubscriptionTopic(instID string) OkxSubscriptionTopic {
 	}
 }
 
+// newOkxMarkPriceSubscriptionTopic returns a new mark price subscription
+// topic for a perpetual swap instrument ex.: BTC-USDT-SWAP.
+func newOkxMarkPriceSubscriptionTopic(instID string) OkxSubscriptionTopic {
+	return OkxSubscriptionTopic{
+		Channel: "mark-price",
+		InstID:  instID,
+	}
+}
+
+// newOkxIndexTickerSubscriptionTopic returns a new index ticker
+// subscription topic.
+func newOkxIndexTickerSubscriptionTopic(instID string) OkxSubscriptionTopic {
+	return OkxSubscriptionTopic{
+		Channel: "index-tickers",
+		InstID:  instID,
+	}
+}
+
 // newOkxSubscriptionMsg returns a new subscription Msg for Okx.
 func newOkxSubscriptionMsg(args ...OkxSubscriptionTopic) OkxSubscriptionMsg {
 	return OkxSubscriptionMsg{
@@ -3,6 +3,8 @@ package provider
 import (
 	"context"
 	"encoding/json"
+	"net/http"
+	"net/http/httptest"
 	"testing"
 
 	sdk "github.com/cosmos/cosmos-sdk/types"
@@ -29,8 +31,8 @@ func TestOkxProvider_GetTickerPrices(t *testing.T) {
 			OkxInstID: OkxInstID{
 				InstID: "ATOM-USDT",
 			},
-			Last:   lastPrice,
-			Vol24h: volume,
+			Last:   types.Number(lastPrice),
+			Vol24h: types.Number(volume),
 		}
 
 		for _, okxTicker := range syncMap {
@@ -54,16 +56,16 @@ func TestOkxProvider_GetTickerPrices(t *testing.T) {
 			OkxInstID: OkxInstID{
 				InstID: "ATOM-USDT",
 			},
-			Last:   lastPriceAtom,
-			Vol24h: volume,
+			Last:   types.Number(lastPriceAtom),
+			Vol24h: types.Number(volume),
 		}
 
 		syncMap["LUNA-USDT"] = OkxTickerPair{
 			OkxInstID: OkxInstID{
 				InstID: "LUNA-USDT",
 			},
-			Last:   lastPriceLuna,
-			Vol24h: volume,
+			Last:   types.Number(lastPriceLuna),
+			Vol24h: types.Number(volume),
 		}
 
 		for _, okxTicker := range syncMap {
@@ -107,3 +109,163 @@ func TestOkxProvider_getSubscriptionMsgs(t *testing.T) {
 	msg, _ = json.Marshal(subMsgs[1])
 	require.Equal(t, "{\"op\":\"subscribe\",\"args\":[{\"channel\":\"tickers\",\"instId\":\"ATOM-USDT\"}]}", string(msg))
 }
+
+func TestOkxProvider_getSubscriptionMsgs_MarkPrice(t *testing.T) {
+	provider := &OkxProvider{
+		priceTypes: map[string]PriceType{"BTC-USDT": PriceTypeMark},
+	}
+	cps := []types.CurrencyPair{
+		{Base: "BTC", Quote: "USDT"},
+	}
+	subMsgs := provider.getSubscriptionMsgs(cps...)
+	require.Len(t, subMsgs, 2)
+
+	msg, _ := json.Marshal(subMsgs[0])
+	require.Equal(t, "{\"op\":\"subscribe\",\"args\":[{\"channel\":\"mark-price\",\"instId\":\"BTC-USDT-SWAP\"}]}", string(msg))
+
+	msg, _ = json.Marshal(subMsgs[1])
+	require.Equal(t, "{\"op\":\"subscribe\",\"args\":[{\"channel\":\"tickers\",\"instId\":\"BTC-USDT-SWAP\"}]}", string(msg))
+}
+
+func TestOkxProvider_messageReceived_MarkPrice(t *testing.T) {
+	p := &OkxProvider{
+		logger:      zerolog.Nop(),
+		priceTypes:  map[string]PriceType{"BTC-USDT": PriceTypeMark},
+		derivatives: newDerivativeStore(),
+		priceStore:  newPriceStore(zerolog.Nop()),
+	}
+	p.setCurrencyPairToTickerAndCandlePair(currencyPairToOkxPair)
+
+	p.messageReceived(0, nil, []byte(`{"arg":{"channel":"mark-price","instId":"BTC-USDT-SWAP"},`+
+		`"data":[{"instType":"SWAP","instId":"BTC-USDT-SWAP","markPx":"43508.9","ts":"1597026383085"}]}`))
+
+	// the mark price is not used until the perpetual market volume is known
+	prices, err := p.GetTickerPrices(BTCUSDT)
+	require.NoError(t, err)
+	require.Empty(t, prices)
+
+	p.messageReceived(0, nil, []byte(`{"arg":{"channel":"tickers","instId":"BTC-USDT-SWAP"},`+
+		`"data":[{"instId":"BTC-USDT-SWAP","last":"43600.1","vol24h":"1500","volCcy24h":"15"}]}`))
+
+	prices, err = p.GetTickerPrices(BTCUSDT)
+	require.NoError(t, err)
+	require.Len(t, prices, 1)
+	require.Equal(t, sdk.MustNewDecFromStr("43508.9"), prices[BTCUSDT].Price)
+	require.Equal(t, sdk.MustNewDecFromStr("15"), prices[BTCUSDT].Volume)
+}
+
+func TestOkxProvider_messageReceived_IndexPrice(t *testing.T) {
+	btcusd := types.CurrencyPair{Base: "BTC", Quote: "USD"}
+	p := &OkxProvider{
+		logger:      zerolog.Nop(),
+		priceTypes:  map[string]PriceType{"BTC-USD": PriceTypeIndex},
+		derivatives: newDerivativeStore(),
+		priceStore:  newPriceStore(zerolog.Nop()),
+	}
+	p.setCurrencyPairToTickerAndCandlePair(currencyPairToOkxPair)
+
+	subMsgs := p.getSubscriptionMsgs(btcusd)
+	require.Len(t, subMsgs, 2)
+	msg, _ := json.Marshal(subMsgs[0])
+	require.Equal(t, "{\"op\":\"subscribe\",\"args\":[{\"channel\":\"index-tickers\",\"instId\":\"BTC-USD\"}]}", string(msg))
+	msg, _ = json.Marshal(subMsgs[1])
+	require.Equal(t, "{\"op\":\"subscribe\",\"args\":[{\"channel\":\"tickers\",\"instId\":\"BTC-USD-SWAP\"}]}", string(msg))
+
+	p.messageReceived(0, nil, []byte(`{"arg":{"channel":"index-tickers","instId":"BTC-USD"},"data":[{`+
+		`"instId":"BTC-USD","idxPx":"43512.4","high24h":"44000","low24h":"42500","open24h":"43000",`+
+		`"sodUtc0":"43100","sodUtc8":"43200","ts":"1597026383085"}]}`))
+	p.messageReceived(0, nil, []byte(`{"arg":{"channel":"tickers","instId":"BTC-USD-SWAP"},`+
+		`"data":[{"instId":"BTC-USD-SWAP","last":"43600.1","vol24h":"150000","volCcy24h":"34.5"}]}`))
+
+	// the index is weighted by the base volume of the perpetual market
+	prices, err := p.GetTickerPrices(btcusd)
+	require.NoError(t, err)
+	require.Len(t, prices, 1)
+	require.Equal(t, sdk.MustNewDecFromStr("43512.4"), prices[btcusd].Price)
+	require.Equal(t, sdk.MustNewDecFromStr("34.5"), prices[btcusd].Volume)
+}
+
+func TestOkxProvider_GetAvailablePairs_IndexPrice(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/api/v5/market/tickers":
+			_, _ = w.Write([]byte(`{"data":[{"instId":"BTC-USDT"},{"instId":"ATOM-USDT"}]}`))
+		case "/api/v5/market/index-tickers":
+			require.Equal(t, "USD", r.URL.Query().Get("quoteCcy"))
+			_, _ = w.Write([]byte(`{"data":[{"instId":"BTC-USD","idxPx":"43512.4"}]}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	p := &OkxProvider{
+		endpoints:  Endpoint{Name: ProviderOkx, Rest: server.URL},
+		priceTypes: map[string]PriceType{"BTC-USD": PriceTypeIndex},
+	}
+	pairs, err := p.GetAvailablePairs()
+	require.NoError(t, err)
+	require.Equal(t, map[string]struct{}{"BTCUSDT": {}, "ATOMUSDT": {}, "BTCUSD": {}}, pairs)
+
+	// the indices are not listed without an index priced pair
+	p.priceTypes = nil
+	pairs, err = p.GetAvailablePairs()
+	require.NoError(t, err)
+	require.NotContains(t, pairs, "BTCUSD")
+}
+
+func TestOkxProvider_messageReceived_PriceSource(t *testing.T) {
+	p := &OkxProvider{
+		logger:       zerolog.Nop(),
+		priceSources: map[string]PriceSource{"BTC-USDT": PriceSourceMid},
+		priceStore:   newPriceStore(zerolog.Nop()),
+	}
+	p.setCurrencyPairToTickerAndCandlePair(currencyPairToOkxPair)
+
+	subMsgs := p.getSubscriptionMsgs(BTCUSDT)
+	require.Len(t, subMsgs, 1)
+	msg, _ := json.Marshal(subMsgs[0])
+	require.Equal(t, "{\"op\":\"subscribe\",\"args\":[{\"channel\":\"tickers\",\"instId\":\"BTC-USDT\"}]}", string(msg))
+
+	p.messageReceived(0, nil, []byte(`{"arg":{"channel":"tickers","instId":"BTC-USDT"},"data":[{"instType":"SPOT",`+
+		`"instId":"BTC-USDT","last":"43600.1","lastSz":"0.1","askPx":"43509.0","askSz":"2","bidPx":"43508.8",`+
+		`"bidSz":"1","open24h":"43000","high24h":"44000","low24h":"42500","volCcy24h":"652000000",`+
+		`"vol24h":"15000","ts":"1597026383085"}]}`))
+
+	prices, err := p.GetTickerPrices(BTCUSDT)
+	require.NoError(t, err)
+	require.Len(t, prices, 1)
+	require.Equal(t, sdk.MustNewDecFromStr("43508.9"), prices[BTCUSDT].Price)
+	require.Equal(t, sdk.MustNewDecFromStr("15000"), prices[BTCUSDT].Volume)
+
+	// a crossed book is not used
+	p.messageReceived(0, nil, []byte(`{"arg":{"channel":"tickers","instId":"BTC-USDT"},"data":[{"instId":"BTC-USDT",`+
+		`"last":"43600.1","askPx":"43500.0","bidPx":"43510.0","vol24h":"15000"}]}`))
+
+	prices, err = p.GetTickerPrices(BTCUSDT)
+	require.NoError(t, err)
+	require.Equal(t, sdk.MustNewDecFromStr("43508.9"), prices[BTCUSDT].Price)
+}
+
+func TestOkxProvider_GetBestQuotes(t *testing.T) {
+	p := &OkxProvider{
+		logger:     zerolog.Nop(),
+		priceStore: newPriceStore(zerolog.Nop()),
+	}
+	p.setCurrencyPairToTickerAndCandlePair(currencyPairToOkxPair)
+
+	p.messageReceived(0, nil, []byte(`{"arg":{"channel":"tickers","instId":"ATOM-USDT"},"data":[{"instType":"SPOT",`+
+		`"instId":"ATOM-USDT","last":"9.871","lastSz":"12.5","askPx":"9.874","askSz":"310","bidPx":"9.869",`+
+		`"bidSz":"95","open24h":"9.8","high24h":"10.1","low24h":"9.7","volCcy24h":"1796000",`+
+		`"vol24h":"182000","ts":"1597026383085"}]}`))
+
+	quotes := p.GetBestQuotes(ATOMUSDT, BTCUSDT)
+	require.Len(t, quotes, 1)
+	require.Equal(t, sdk.MustNewDecFromStr("9.869"), quotes[ATOMUSDT].Bid)
+	require.Equal(t, sdk.MustNewDecFromStr("9.874"), quotes[ATOMUSDT].Ask)
+
+	// a ticker without a bid removes the quote
+	p.messageReceived(0, nil, []byte(`{"arg":{"channel":"tickers","instId":"ATOM-USDT"},"data":[{"instId":"ATOM-USDT",`+
+		`"last":"9.871","askPx":"9.874","bidPx":"","vol24h":"182000"}]}`))
+	require.Empty(t, p.GetBestQuotes(ATOMUSDT))
+}
@@ -0,0 +1,199 @@
+package provider
+
+import (
+	"fmt"
+	"sync"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+
+	"github.com/ojo-network/price-feeder/oracle/types"
+)
+
+type (
+	// OrderBookDepthProvider is implemented by the providers which can
+	// subscribe to the order books of their pairs and measure their depth, the
+	// liquidity within a band around the mid price. A thin book is a sign of
+	// unreliable pricing.
+	OrderBookDepthProvider interface {
+		// GetOrderBookDepths returns the depth of the order book of each of
+		// the currency pairs, in their quote, for the pairs whose book was
+		// received.
+		GetOrderBookDepths(...types.CurrencyPair) map[types.CurrencyPair]sdk.Dec
+	}
+
+	// OrderBookLevel defines a price level of one side of an order book.
+	OrderBookLevel struct {
+		Price  sdk.Dec
+		Amount sdk.Dec
+	}
+
+	// orderBookStore holds the order books of the pairs of a provider, keyed
+	// by the provider symbol, and measures their depth within its band.
+	orderBookStore struct {
+		mtx  sync.RWMutex
+		band sdk.Dec
+
+		// bids and asks hold the amount of each price level of the books,
+		// keyed by the price.
+		bids map[string]map[string]OrderBookLevel
+		asks map[string]map[string]OrderBookLevel
+	}
+)
+
+// OrderBookDepthBand returns the band around the mid price, as a fraction of
+// it, within which the depth of the order books of the endpoint's pairs is
+// measured, and whether the order books are subscribed to.
+func (e Endpoint) OrderBookDepthBand() (sdk.Dec, bool, error) {
+	if e.OrderBookDepth == "" {
+		return sdk.Dec{}, false, nil
+	}
+
+	band, err := sdk.NewDecFromStr(e.OrderBookDepth)
+	if err != nil {
+		return sdk.Dec{}, false, fmt.Errorf("order book depth of %s must be a decimal: %w", e.Name, err)
+	}
+	if !band.IsPositive() || band.GTE(sdk.OneDec()) {
+		return sdk.Dec{}, false, fmt.Errorf("order book depth of %s must be greater than 0 and less than 1", e.Name)
+	}
+	return band, true, nil
+}
+
+// OrderBookDepth returns the value, in the quote, of the bids and asks within
+// the band around the mid price of the order book, a fraction of the mid
+// price. It fails if a side of the book is empty or the book is crossed.
+func OrderBookDepth(bids, asks []OrderBookLevel, band sdk.Dec) (sdk.Dec, error) {
+	var bestBid, bestAsk sdk.Dec
+	for _, level := range bids {
+		if bestBid.IsNil() || level.Price.GT(bestBid) {
+			bestBid = level.Price
+		}
+	}
+	for _, level := range asks {
+		if bestAsk.IsNil() || level.Price.LT(bestAsk) {
+			bestAsk = level.Price
+		}
+	}
+	if bestBid.IsNil() || bestAsk.IsNil() {
+		return sdk.Dec{}, fmt.Errorf("order book has an empty side")
+	}
+	if bestBid.GT(bestAsk) {
+		return sdk.Dec{}, fmt.Errorf("crossed order book with bid %s above ask %s", bestBid, bestAsk)
+	}
+
+	mid := bestBid.Add(bestAsk).QuoInt64(2)
+	lowest := mid.Sub(mid.Mul(band))
+	highest := mid.Add(mid.Mul(band))
+
+	depth := sdk.ZeroDec()
+	for _, level := range bids {
+		if level.Price.GTE(lowest) {
+			depth = depth.Add(level.Price.Mul(level.Amount))
+		}
+	}
+	for _, level := range asks {
+		if level.Price.LTE(highest) {
+			depth = depth.Add(level.Price.Mul(level.Amount))
+		}
+	}
+	return depth, nil
+}
+
+func newOrderBookStore(band sdk.Dec) *orderBookStore {
+	return &orderBookStore{
+		band: band,
+		bids: make(map[string]map[string]OrderBookLevel),
+		asks: make(map[string]map[string]OrderBookLevel),
+	}
+}
+
+// setBook replaces the order book of the symbol with the snapshot.
+func (s *orderBookStore) setBook(symbol string, bids, asks []OrderBookLevel) {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+
+	s.bids[symbol] = make(map[string]OrderBookLevel, len(bids))
+	s.asks[symbol] = make(map[string]OrderBookLevel, len(asks))
+	applyOrderBookLevels(s.bids[symbol], bids)
+	applyOrderBookLevels(s.asks[symbol], asks)
+}
+
+// updateBook applies the update to the order book of the symbol, removing the
+// levels updated to a zero amount. Updates of a symbol without a snapshot are
+// dropped.
+func (s *orderBookStore) updateBook(symbol string, bids, asks []OrderBookLevel) {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+
+	if _, ok := s.bids[symbol]; !ok {
+		return
+	}
+	applyOrderBookLevels(s.bids[symbol], bids)
+	applyOrderBookLevels(s.asks[symbol], asks)
+}
+
+// depths returns the depth of the order book of each of the currency pairs
+// whose book is usable.
+func (s *orderBookStore) depths(
+	toSymbol func(types.CurrencyPair) string,
+	cps ...types.CurrencyPair,
+) map[types.CurrencyPair]sdk.Dec {
+	s.mtx.RLock()
+	defer s.mtx.RUnlock()
+
+	depths := make(map[types.CurrencyPair]sdk.Dec, len(cps))
+	for _, cp := range cps {
+		symbol := toSymbol(cp)
+		bids, ok := s.bids[symbol]
+		if !ok {
+			continue
+		}
+
+		depth, err := OrderBookDepth(orderBookLevels(bids), orderBookLevels(s.asks[symbol]), s.band)
+		if err != nil {
+			continue
+		}
+		depths[cp] = depth
+	}
+	return depths
+}
+
+// applyOrderBookLevels sets the amount of each price level of the book side,
+// removing the levels with a zero amount.
+func applyOrderBookLevels(side map[string]OrderBookLevel, levels []OrderBookLevel) {
+	for _, level := range levels {
+		price := level.Price.String()
+		if level.Amount.IsZero() {
+			delete(side, price)
+			continue
+		}
+		side[price] = level
+	}
+}
+
+func orderBookLevels(side map[string]OrderBookLevel) []OrderBookLevel {
+	levels := make([]OrderBookLevel, 0, len(side))
+	for _, level := range side {
+		levels = append(levels, level)
+	}
+	return levels
+}
+
+// parseOrderBookLevels parses the [price, amount] levels of a book side.
+func parseOrderBookLevels(levels [][]types.Number) ([]OrderBookLevel, error) {
+	parsed := make([]OrderBookLevel, 0, len(levels))
+	for _, level := range levels {
+		if len(level) < 2 {
+			return nil, fmt.Errorf("invalid order book level %v", level)
+		}
+		price, err := level[0].Dec()
+		if err != nil {
+			return nil, fmt.Errorf("invalid order book price: %w", err)
+		}
+		amount, err := level[1].Dec()
+		if err != nil {
+			return nil, fmt.Errorf("invalid order book amount: %w", err)
+		}
+		parsed = append(parsed, OrderBookLevel{Price: price, Amount: amount})
+	}
+	return parsed, nil
+}
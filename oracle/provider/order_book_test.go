@@ -0,0 +1,92 @@
+package provider
+
+import (
+	"testing"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/stretchr/testify/require"
+)
+
+func TestOrderBookDepth(t *testing.T) {
+	level := func(price, amount string) OrderBookLevel {
+		return OrderBookLevel{Price: sdk.MustNewDecFromStr(price), Amount: sdk.MustNewDecFromStr(amount)}
+	}
+	band := sdk.MustNewDecFromStr("0.05")
+
+	testCases := []struct {
+		name      string
+		bids      []OrderBookLevel
+		asks      []OrderBookLevel
+		expDepth  sdk.Dec
+		expectErr bool
+	}{
+		{
+			name:     "levels within band",
+			bids:     []OrderBookLevel{level("99", "1"), level("98", "2")},
+			asks:     []OrderBookLevel{level("101", "1"), level("102", "3")},
+			expDepth: sdk.MustNewDecFromStr("702"),
+		},
+		{
+			name:     "levels outside band",
+			bids:     []OrderBookLevel{level("99", "1"), level("90", "100")},
+			asks:     []OrderBookLevel{level("110", "100"), level("101", "1")},
+			expDepth: sdk.MustNewDecFromStr("200"),
+		},
+		{
+			name:      "empty side",
+			bids:      []OrderBookLevel{level("99", "1")},
+			expectErr: true,
+		},
+		{
+			name:      "crossed book",
+			bids:      []OrderBookLevel{level("102", "1")},
+			asks:      []OrderBookLevel{level("101", "1")},
+			expectErr: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			depth, err := OrderBookDepth(tc.bids, tc.asks, band)
+			if tc.expectErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tc.expDepth, depth)
+		})
+	}
+}
+
+func TestEndpoint_OrderBookDepthBand(t *testing.T) {
+	_, ok, err := Endpoint{}.OrderBookDepthBand()
+	require.NoError(t, err)
+	require.False(t, ok)
+
+	band, ok, err := Endpoint{OrderBookDepth: "0.02"}.OrderBookDepthBand()
+	require.NoError(t, err)
+	require.True(t, ok)
+	require.Equal(t, sdk.MustNewDecFromStr("0.02"), band)
+
+	for _, depth := range []string{"abc", "0", "-0.1", "1"} {
+		_, _, err = Endpoint{OrderBookDepth: depth}.OrderBookDepthBand()
+		require.Error(t, err, depth)
+	}
+}
+
+func TestOrderBookStore_updateBook(t *testing.T) {
+	store := newOrderBookStore(sdk.MustNewDecFromStr("0.05"))
+	bids := []OrderBookLevel{{Price: sdk.NewDec(99), Amount: sdk.OneDec()}}
+	asks := []OrderBookLevel{{Price: sdk.NewDec(101), Amount: sdk.OneDec()}}
+
+	// updates before a snapshot are dropped
+	store.updateBook("ATOM_USDT", bids, asks)
+	require.Empty(t, store.depths(currencyPairToGatePair, ATOMUSDT))
+
+	store.setBook("ATOM_USDT", bids, asks)
+	require.Equal(t, sdk.NewDec(200), store.depths(currencyPairToGatePair, ATOMUSDT)[ATOMUSDT])
+
+	// removing a side makes the book unusable
+	store.updateBook("ATOM_USDT", nil, []OrderBookLevel{{Price: sdk.NewDec(101), Amount: sdk.ZeroDec()}})
+	require.Empty(t, store.depths(currencyPairToGatePair, ATOMUSDT))
+}
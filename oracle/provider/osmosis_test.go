@@ -88,8 +88,8 @@ func TestOsmosisProvider_GetCandlePrices(t *testing.T) {
 		time := int64(1000000)
 
 		candle := OsmosisCandle{
-			Volume:  volume,
-			Close:   price,
+			Volume:  types.Number(volume),
+			Close:   types.Number(price),
 			EndTime: time,
 		}
 
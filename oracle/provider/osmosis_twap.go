@@ -0,0 +1,298 @@
+package provider
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/ojo-network/price-feeder/oracle/types"
+	"github.com/rs/zerolog"
+)
+
+const (
+	osmosisTwapRestHost          = "https://lcd.osmosis.zone"
+	osmosisTwapRestPath          = "/osmosis/twap/v1beta1/ArithmeticTwapToNow"
+	osmosisTwapPricePollInterval = 30 * time.Second
+	osmosisTwapWindow            = 30 * time.Minute
+)
+
+var _ Provider = (*OsmosisTwapProvider)(nil)
+
+type (
+	// OsmosisTwapProvider defines an Oracle provider which polls the
+	// arithmetic TWAP of Osmosis pools from the x/twap module of an Osmosis
+	// node's REST endpoint. The TWAP is accumulated on chain over every block
+	// of its window, which makes it far harder to manipulate than the spot
+	// price of a pool. Each pair is mapped to its pool by the pools set from
+	// the osmosis_twap section of the config. A pair whose TWAP fails to be
+	// queried stops contributing prices until it is queried again. Pools
+	// carry no traded volume, so prices are stored as candles and tickers
+	// without volume.
+	//
+	// REF: https://github.com/osmosis-labs/osmosis/tree/main/x/twap
+	OsmosisTwapProvider struct {
+		ctx       context.Context
+		logger    zerolog.Logger
+		mtx       sync.RWMutex
+		endpoints Endpoint
+		client    *http.Client
+
+		priceStore
+	}
+
+	// TwapPool defines the Osmosis pool a pair is priced by, the denoms of
+	// its base and quote assets in the pool, their decimals and the window
+	// the TWAP is computed over.
+	TwapPool struct {
+		PoolID        uint64
+		BaseDenom     string
+		QuoteDenom    string
+		BaseDecimals  int
+		QuoteDecimals int
+		Window        time.Duration
+	}
+
+	// OsmosisTwapResponse defines the response structure of an arithmetic
+	// TWAP query.
+	OsmosisTwapResponse struct {
+		ArithmeticTwap string `json:"arithmetic_twap"`
+	}
+
+	// osmosisTwapPrice defines the TWAP of a pool at the time it was polled.
+	osmosisTwapPrice struct {
+		price     sdk.Dec
+		timeStamp int64
+	}
+)
+
+func NewOsmosisTwapProvider(
+	ctx context.Context,
+	logger zerolog.Logger,
+	endpoints Endpoint,
+	pairs ...types.CurrencyPair,
+) (*OsmosisTwapProvider, error) {
+	if endpoints.Name != ProviderOsmosisTwap {
+		endpoints = Endpoint{
+			Name: ProviderOsmosisTwap,
+			Rest: osmosisTwapRestHost,
+		}
+	}
+
+	osmosisTwapLogger := logger.With().Str("provider", string(ProviderOsmosisTwap)).Logger()
+
+	provider := &OsmosisTwapProvider{
+		ctx:        ctx,
+		logger:     osmosisTwapLogger,
+		endpoints:  endpoints,
+		client:     endpoints.httpClient(),
+		priceStore: newPriceStore(osmosisTwapLogger),
+	}
+	provider.setCurrencyPairToTickerAndCandlePair(currencyPairToOsmosisTwapPair)
+
+	confirmedPairs, err := ConfirmPairAvailability(
+		provider,
+		provider.endpoints.Name,
+		provider.logger,
+		pairs...,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	provider.setSubscribedPairs(confirmedPairs...)
+
+	return provider, nil
+}
+
+// StartConnections starts polling the TWAP of the subscribed pairs until the
+// provider's context is canceled.
+func (p *OsmosisTwapProvider) StartConnections() {
+	go func() {
+		ticker := time.NewTicker(osmosisTwapPricePollInterval)
+		defer ticker.Stop()
+
+		for {
+			p.pollPrices()
+
+			select {
+			case <-p.ctx.Done():
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+}
+
+// SubscribeCurrencyPairs confirms the pools of the new currency pairs and
+// adds them to the providers subscribedPairs array
+func (p *OsmosisTwapProvider) SubscribeCurrencyPairs(cps ...types.CurrencyPair) {
+	p.mtx.Lock()
+	defer p.mtx.Unlock()
+
+	confirmedPairs, err := ConfirmPairAvailability(
+		p,
+		p.endpoints.Name,
+		p.logger,
+		cps...,
+	)
+	if err != nil {
+		return
+	}
+
+	p.setSubscribedPairs(confirmedPairs...)
+}
+
+// pollPrices queries the TWAP of every subscribed pair. The pairs whose TWAP
+// failed to be queried stop contributing prices until it is queried again.
+func (p *OsmosisTwapProvider) pollPrices() {
+	p.mtx.Lock()
+	defer p.mtx.Unlock()
+
+	p.subscribedPairsMtx.RLock()
+	pairs := types.MapPairsToSlice(p.subscribedPairs)
+	p.subscribedPairsMtx.RUnlock()
+
+	for _, cp := range pairs {
+		symbol := currencyPairToOsmosisTwapPair(cp)
+		price, err := p.queryPrice(symbol)
+		if err != nil {
+			p.disablePair(cp, err)
+			continue
+		}
+		p.setTickerPair(price, symbol)
+		p.setCandlePair(price, symbol)
+	}
+}
+
+// disablePair removes the ticker and candles of a pair, so it stops
+// contributing prices.
+func (p *OsmosisTwapProvider) disablePair(cp types.CurrencyPair, err error) {
+	symbol := currencyPairToOsmosisTwapPair(cp)
+
+	p.tickerMtx.Lock()
+	delete(p.tickers, symbol)
+	p.tickerMtx.Unlock()
+
+	p.candleMtx.Lock()
+	delete(p.candles, symbol)
+	p.candleMtx.Unlock()
+
+	TelemetryFailure(ProviderOsmosisTwap, MessageTypeTicker)
+	p.logger.Error().
+		Err(err).
+		Str("pair", cp.String()).
+		Msg("failed to query twap; disabling pair until the next successful query")
+}
+
+// queryPrice queries the arithmetic TWAP of the pool of a pair from the
+// start of its window to now.
+func (p *OsmosisTwapProvider) queryPrice(symbol string) (osmosisTwapPrice, error) {
+	pool, ok := p.endpoints.TwapPools[symbol]
+	if !ok {
+		return osmosisTwapPrice{}, fmt.Errorf("osmosis-twap: no pool configured for %s", symbol)
+	}
+	window := pool.Window
+	if window <= 0 {
+		window = osmosisTwapWindow
+	}
+
+	query := url.Values{}
+	query.Set("pool_id", strconv.FormatUint(pool.PoolID, 10))
+	query.Set("base_asset", pool.BaseDenom)
+	query.Set("quote_asset", pool.QuoteDenom)
+	query.Set("start_time", p.now().Add(-window).UTC().Format(time.RFC3339))
+
+	req, err := http.NewRequestWithContext(
+		p.ctx,
+		http.MethodGet,
+		p.endpoints.Rest+osmosisTwapRestPath+"?"+query.Encode(),
+		nil,
+	)
+	if err != nil {
+		return osmosisTwapPrice{}, err
+	}
+
+	httpResp, err := p.client.Do(req)
+	if err != nil {
+		return osmosisTwapPrice{}, err
+	}
+	defer httpResp.Body.Close()
+
+	if httpResp.StatusCode != http.StatusOK {
+		return osmosisTwapPrice{}, fmt.Errorf(
+			"osmosis-twap: unexpected status %s querying pool %d", httpResp.Status, pool.PoolID,
+		)
+	}
+
+	var resp OsmosisTwapResponse
+	if err := json.NewDecoder(httpResp.Body).Decode(&resp); err != nil {
+		return osmosisTwapPrice{}, fmt.Errorf("osmosis-twap: failed to decode twap: %w", err)
+	}
+
+	return newOsmosisTwapPrice(resp.ArithmeticTwap, pool)
+}
+
+// GetAvailablePairs returns all pairs to which the provider can subscribe,
+// being every pair with a configured pool.
+// ex.: map["OSMOUSDC" => {}, "ATOMUSDC" => {}].
+func (p *OsmosisTwapProvider) GetAvailablePairs() (map[string]struct{}, error) {
+	availablePairs := make(map[string]struct{}, len(p.endpoints.TwapPools))
+	for symbol := range p.endpoints.TwapPools {
+		availablePairs[symbol] = struct{}{}
+	}
+
+	return availablePairs, nil
+}
+
+func (tp osmosisTwapPrice) toTickerPrice() (types.TickerPrice, error) {
+	return types.TickerPrice{
+		Price:  tp.price,
+		Volume: sdk.ZeroDec(),
+	}, nil
+}
+
+func (tp osmosisTwapPrice) toCandlePrice() (types.CandlePrice, error) {
+	return types.CandlePrice{
+		Price:     tp.price,
+		Volume:    sdk.ZeroDec(),
+		TimeStamp: tp.timeStamp,
+	}, nil
+}
+
+// newOsmosisTwapPrice converts the TWAP of a pool, being the amount of quote
+// denom per base denom, to the price of the base asset by scaling it by the
+// decimals of the assets.
+func newOsmosisTwapPrice(twap string, pool TwapPool) (osmosisTwapPrice, error) {
+	price, err := types.ParseDec(twap)
+	if err != nil {
+		return osmosisTwapPrice{}, fmt.Errorf("osmosis-twap: failed to parse twap: %w", err)
+	}
+
+	switch exponent := pool.BaseDecimals - pool.QuoteDecimals; {
+	case exponent > 0:
+		price = price.Mul(sdk.NewDec(10).Power(uint64(exponent)))
+	case exponent < 0:
+		price = price.Quo(sdk.NewDec(10).Power(uint64(-exponent)))
+	}
+	if !price.IsPositive() {
+		return osmosisTwapPrice{}, fmt.Errorf("osmosis-twap: no price available")
+	}
+
+	return osmosisTwapPrice{
+		price:     price,
+		timeStamp: PastUnixTime(0),
+	}, nil
+}
+
+// currencyPairToOsmosisTwapPair receives a currency pair and return the
+// symbol the provider stores its prices by, ex.: OSMOUSDC.
+func currencyPairToOsmosisTwapPair(cp types.CurrencyPair) string {
+	return strings.ToUpper(cp.String())
+}
@@ -0,0 +1,180 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/ojo-network/price-feeder/oracle/types"
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/require"
+)
+
+type osmosisTwapTestServer struct {
+	*httptest.Server
+
+	mtx sync.Mutex
+	// twaps holds the TWAP of each pool, and windows the window of the last
+	// query of each pool.
+	twaps   map[string]string
+	windows map[string]time.Duration
+}
+
+func newOsmosisTwapTestServer(t *testing.T) *osmosisTwapTestServer {
+	ts := &osmosisTwapTestServer{
+		twaps: map[string]string{
+			"1":    "0.085000000000000000",
+			"1464": "0.000000000471200000",
+		},
+		windows: map[string]time.Duration{},
+	}
+	ts.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ts.mtx.Lock()
+		defer ts.mtx.Unlock()
+
+		require.Equal(t, osmosisTwapRestPath, r.URL.Path)
+		query := r.URL.Query()
+		require.NotEmpty(t, query.Get("base_asset"))
+		require.NotEmpty(t, query.Get("quote_asset"))
+
+		startTime, err := time.Parse(time.RFC3339, query.Get("start_time"))
+		require.NoError(t, err)
+		poolID := query.Get("pool_id")
+		ts.windows[poolID] = time.Since(startTime).Round(time.Minute)
+
+		twap, ok := ts.twaps[poolID]
+		if !ok {
+			w.WriteHeader(http.StatusBadRequest)
+			_, _ = w.Write([]byte(`{"code":3,"message":"pool not found"}`))
+			return
+		}
+		_, _ = fmt.Fprintf(w, `{"arithmetic_twap":%q}`, twap)
+	}))
+	t.Cleanup(ts.Close)
+
+	return ts
+}
+
+func TestOsmosisTwapProvider_GetTickerPrices(t *testing.T) {
+	server := newOsmosisTwapTestServer(t)
+
+	atomosmo := types.CurrencyPair{Base: "ATOM", Quote: "OSMO"}
+	ethusdc := types.CurrencyPair{Base: "ETH", Quote: "USDC"}
+	p, err := NewOsmosisTwapProvider(
+		context.TODO(),
+		zerolog.Nop(),
+		Endpoint{
+			Name: ProviderOsmosisTwap,
+			Rest: server.URL,
+			TwapPools: map[string]TwapPool{
+				"ATOMOSMO": {PoolID: 1, BaseDenom: "ibc/27394FB0", QuoteDenom: "uosmo", Window: time.Hour},
+				"ETHUSDC": {
+					PoolID:        1464,
+					BaseDenom:     "ibc/EA1D43981D",
+					QuoteDenom:    "ibc/498A0751C7",
+					BaseDecimals:  18,
+					QuoteDecimals: 6,
+				},
+			},
+		},
+		atomosmo,
+		ethusdc,
+		types.CurrencyPair{Base: "FOO", Quote: "USDC"},
+	)
+	require.NoError(t, err)
+	require.Len(t, p.subscribedPairs, 2)
+
+	t.Run("valid_request_multi_ticker", func(t *testing.T) {
+		p.pollPrices()
+
+		prices, err := p.GetTickerPrices(atomosmo, ethusdc)
+		require.NoError(t, err)
+		require.Len(t, prices, 2)
+		require.Equal(t, sdk.MustNewDecFromStr("0.085"), prices[atomosmo].Price)
+		require.Equal(t, sdk.ZeroDec(), prices[atomosmo].Volume)
+		require.Equal(t, sdk.MustNewDecFromStr("471.2"), prices[ethusdc].Price)
+
+		candles, err := p.GetCandlePrices(atomosmo, ethusdc)
+		require.NoError(t, err)
+		require.Len(t, candles[ethusdc], 1)
+		require.Equal(t, sdk.MustNewDecFromStr("471.2"), candles[ethusdc][0].Price)
+
+		server.mtx.Lock()
+		require.Equal(t, time.Hour, server.windows["1"])
+		require.Equal(t, osmosisTwapWindow, server.windows["1464"])
+		server.mtx.Unlock()
+	})
+
+	t.Run("failed_query_disables_pair", func(t *testing.T) {
+		server.mtx.Lock()
+		delete(server.twaps, "1464")
+		server.mtx.Unlock()
+
+		p.pollPrices()
+
+		prices, err := p.GetTickerPrices(atomosmo, ethusdc)
+		require.NoError(t, err)
+		require.Len(t, prices, 1)
+		require.Contains(t, prices, atomosmo)
+
+		candles, err := p.GetCandlePrices(atomosmo, ethusdc)
+		require.NoError(t, err)
+		require.NotContains(t, candles, ethusdc)
+	})
+}
+
+func TestNewOsmosisTwapPrice(t *testing.T) {
+	testCases := []struct {
+		name     string
+		twap     string
+		pool     TwapPool
+		expected string
+		err      bool
+	}{
+		{
+			name:     "same decimals",
+			twap:     "12.5",
+			pool:     TwapPool{BaseDecimals: 6, QuoteDecimals: 6},
+			expected: "12.5",
+		},
+		{
+			name:     "more base decimals",
+			twap:     "0.0000000000025",
+			pool:     TwapPool{BaseDecimals: 18, QuoteDecimals: 6},
+			expected: "2.5",
+		},
+		{
+			name:     "more quote decimals",
+			twap:     "2500000000000",
+			pool:     TwapPool{BaseDecimals: 6, QuoteDecimals: 18},
+			expected: "2.5",
+		},
+		{
+			name: "zero twap",
+			twap: "0",
+			err:  true,
+		},
+		{
+			name: "invalid twap",
+			twap: "foo",
+			err:  true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			price, err := newOsmosisTwapPrice(tc.twap, tc.pool)
+			if tc.err {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, sdk.MustNewDecFromStr(tc.expected), price.price)
+		})
+	}
+}
@@ -3,8 +3,6 @@ package provider
 import (
 	"context"
 	"encoding/json"
-	"fmt"
-	"net/http"
 	"net/url"
 	"strings"
 	"sync"
@@ -52,11 +50,11 @@ type (
 
 	// Real-time per-minute forex aggregates for a given forex pair.
 	PolygonAggregatesResponse struct {
-		EV        string  `json:"ev"`   // Event type
-		Pair      string  `json:"pair"` // ex.: USD/EUR
-		Close     float64 `json:"c"`    // Rate at close
-		Volume    float64 `json:"v"`    // Volume during 1 minute interval
-		Timestamp int64   `json:"e"`    // Endtime of candle (Unix milliseconds)
+		EV        string       `json:"ev"`   // Event type
+		Pair      string       `json:"pair"` // ex.: USD/EUR
+		Close     types.Number `json:"c"`    // Rate at close
+		Volume    types.Number `json:"v"`    // Volume during 1 minute interval
+		Timestamp int64        `json:"e"`    // Endtime of candle (Unix milliseconds)
 	}
 
 	PolygonSubscriptionMsg struct {
@@ -81,9 +79,11 @@ func NewPolygonProvider(
 ) (*PolygonProvider, error) {
 	if endpoints.Name != ProviderPolygon {
 		endpoints = Endpoint{
-			Name:      ProviderPolygon,
-			Rest:      polygonRestHost,
-			Websocket: polygonWSHost,
+			Name:              ProviderPolygon,
+			Rest:              polygonRestHost,
+			Websocket:         polygonWSHost,
+			SilenceTimeout:    endpoints.SilenceTimeout,
+			ReconnectCooldown: endpoints.ReconnectCooldown,
 		}
 	}
 
@@ -122,6 +122,11 @@ func NewPolygonProvider(
 		provider.messageReceived,
 		disabledPingDuration,
 		websocket.PingMessage,
+		endpoints.SilenceTimeout,
+		endpoints.ReconnectCooldown,
+		&provider.priceStore,
+		endpoints.tlsConfig,
+		endpoints.header,
 		polygonLogger,
 	)
 
@@ -186,7 +191,7 @@ func (p *PolygonProvider) SubscribeCurrencyPairs(cps ...types.CurrencyPair) {
 // GetAvailablePairs return all available pairs symbol to susbscribe.
 func (p *PolygonProvider) GetAvailablePairs() (map[string]struct{}, error) {
 	// request for first 1000 tickers (request limit)
-	resp, err := http.Get(p.endpoints.Rest + polygonRestPath + p.endpoints.APIKey + polygonOrderOne + polygonLimitOne)
+	resp, err := p.endpoints.httpClient().Get(p.endpoints.Rest + polygonRestPath + p.endpoints.APIKey + polygonOrderOne + polygonLimitOne)
 	if err != nil {
 		return nil, err
 	}
@@ -197,7 +202,7 @@ func (p *PolygonProvider) GetAvailablePairs() (map[string]struct{}, error) {
 	defer resp.Body.Close()
 
 	// request for rest of the tickers
-	resp, err = http.Get(p.endpoints.Rest + polygonRestPath + p.endpoints.APIKey + polygonOrderTwo + polygonLimitTwo)
+	resp, err = p.endpoints.httpClient().Get(p.endpoints.Rest + polygonRestPath + p.endpoints.APIKey + polygonOrderTwo + polygonLimitTwo)
 	if err != nil {
 		return nil, err
 	}
@@ -256,19 +261,20 @@ func (p *PolygonProvider) messageReceived(messageType int, _ *WebsocketConnectio
 		AnErr("status", statusErr).
 		AnErr("aggregates", aggregatesErr).
 		Msg("Error on receive message")
+	telemetryDecodeError(p.logger, ProviderPolygon, bz)
 }
 
 func (par PolygonAggregatesResponse) toTickerPrice() (types.TickerPrice, error) {
 	return types.NewTickerPrice(
-		fmt.Sprintf("%f", par.Close),
-		fmt.Sprintf("%f", par.Volume),
+		par.Close.String(),
+		par.Volume.String(),
 	)
 }
 
 func (par PolygonAggregatesResponse) toCandlePrice() (types.CandlePrice, error) {
 	return types.NewCandlePrice(
-		fmt.Sprintf("%f", par.Close),
-		fmt.Sprintf("%f", par.Volume),
+		par.Close.String(),
+		par.Volume.String(),
 		par.Timestamp,
 	)
 }
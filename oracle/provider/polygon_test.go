@@ -2,7 +2,6 @@ package provider
 
 import (
 	"context"
-	"fmt"
 	"testing"
 
 	sdk "github.com/cosmos/cosmos-sdk/types"
@@ -84,8 +83,8 @@ func TestPolygonProvider_GetCandlePrices(t *testing.T) {
 	require.NoError(t, err)
 
 	t.Run("valid_request_single_candle", func(t *testing.T) {
-		price := 1.190000000000000000
-		volume := 2396974.000000000000000000
+		price := types.Number("1.190000000000000000")
+		volume := types.Number("2396974.000000000000000000")
 		timeStamp := int64(1000000000)
 
 		data := PolygonAggregatesResponse{
@@ -101,8 +100,8 @@ func TestPolygonProvider_GetCandlePrices(t *testing.T) {
 		prices, err := p.GetCandlePrices(types.CurrencyPair{Base: "EUR", Quote: "USD"})
 		require.NoError(t, err)
 		require.Len(t, prices, 1)
-		priceDec, _ := sdk.NewDecFromStr(fmt.Sprintf("%f", price))
-		volumeDec, _ := sdk.NewDecFromStr(fmt.Sprintf("%f", volume))
+		priceDec, _ := price.Dec()
+		volumeDec, _ := volume.Dec()
 
 		require.Equal(t, priceDec, prices[EURUSD][0].Price)
 		require.Equal(t, volumeDec, prices[EURUSD][0].Volume)
@@ -0,0 +1,100 @@
+package provider
+
+import (
+	"fmt"
+
+	"github.com/ojo-network/price-feeder/oracle/types"
+)
+
+const (
+	// PriceSourceLast uses the last traded price of a pair.
+	PriceSourceLast PriceSource = "last"
+
+	// PriceSourceMid uses the average of the best bid and the best ask of a
+	// pair.
+	PriceSourceMid PriceSource = "mid"
+
+	// PriceSourceBid uses the best bid of a pair.
+	PriceSourceBid PriceSource = "bid"
+
+	// PriceSourceAsk uses the best ask of a pair.
+	PriceSourceAsk PriceSource = "ask"
+)
+
+type (
+	// PriceSource defines which price of a provider's ticker is used to price
+	// a currency pair.
+	PriceSource string
+
+	// PairPriceSource defines an override of the price source a provider uses
+	// for a given currency pair.
+	PairPriceSource struct {
+		Base   string      `toml:"base" mapstructure:"base"`
+		Quote  string      `toml:"quote" mapstructure:"quote"`
+		Source PriceSource `toml:"source" mapstructure:"source"`
+	}
+)
+
+// IsValid returns true if the price source is supported.
+func (ps PriceSource) IsValid() bool {
+	switch ps {
+	case PriceSourceLast, PriceSourceMid, PriceSourceBid, PriceSourceAsk:
+		return true
+	}
+	return false
+}
+
+// CurrencyPair returns the currency pair the price source applies to.
+func (pps PairPriceSource) CurrencyPair() types.CurrencyPair {
+	return types.CurrencyPair{Base: pps.Base, Quote: pps.Quote}
+}
+
+// pairPriceSources returns the best quote price sources configured on the
+// endpoint keyed by the provider specific symbol of each pair.
+func (e Endpoint) pairPriceSources(toSymbol func(types.CurrencyPair) string) map[string]PriceSource {
+	priceSources := make(map[string]PriceSource, len(e.PriceSources))
+	for _, pps := range e.PriceSources {
+		if pps.Source == PriceSourceLast {
+			continue
+		}
+		priceSources[toSymbol(pps.CurrencyPair())] = pps.Source
+	}
+	return priceSources
+}
+
+// quotePrice returns the price of the price source given the last traded
+// price and the best bid and ask of a pair.
+func (ps PriceSource) quotePrice(last, bid, ask types.Number) (types.Number, error) {
+	switch ps {
+	case PriceSourceBid:
+		return validQuote(bid, "bid")
+	case PriceSourceAsk:
+		return validQuote(ask, "ask")
+	case PriceSourceMid:
+		if _, err := validQuote(bid, "bid"); err != nil {
+			return "", err
+		}
+		if _, err := validQuote(ask, "ask"); err != nil {
+			return "", err
+		}
+		bidDec, _ := bid.Dec()
+		askDec, _ := ask.Dec()
+		if bidDec.GT(askDec) {
+			return "", fmt.Errorf("crossed book with bid %s above ask %s", bid, ask)
+		}
+		return types.Number(bidDec.Add(askDec).QuoInt64(2).String()), nil
+	}
+	return last, nil
+}
+
+// validQuote returns the quote if it is a positive decimal.
+func validQuote(quote types.Number, side string) (types.Number, error) {
+	dec, err := quote.Dec()
+	if err != nil {
+		return "", fmt.Errorf("failed to parse %s %q: %w", side, quote, err)
+	}
+	if !dec.IsPositive() {
+		return "", fmt.Errorf("invalid %s %s", side, quote)
+	}
+	return quote, nil
+}
@@ -0,0 +1,40 @@
+package provider
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/ojo-network/price-feeder/oracle/types"
+)
+
+func TestPriceSource_quotePrice(t *testing.T) {
+	testCases := []struct {
+		name     string
+		source   PriceSource
+		bid      types.Number
+		ask      types.Number
+		expected types.Number
+		err      bool
+	}{
+		{"last", PriceSourceLast, "", "", "10.5", false},
+		{"mid", PriceSourceMid, "10.1", "10.4", "10.250000000000000000", false},
+		{"bid", PriceSourceBid, "10.1", "10.4", "10.1", false},
+		{"ask", PriceSourceAsk, "10.1", "10.4", "10.4", false},
+		{"missing ask", PriceSourceMid, "10.1", "", "", true},
+		{"zero bid", PriceSourceBid, "0", "10.4", "", true},
+		{"crossed book", PriceSourceMid, "10.4", "10.1", "", true},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			price, err := tc.source.quotePrice("10.5", tc.bid, tc.ask)
+			if tc.err {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tc.expected, price)
+		})
+	}
+}
@@ -1,6 +1,7 @@
 package provider
 
 import (
+	"errors"
 	"sort"
 	"sync"
 	"time"
@@ -23,9 +24,44 @@ type priceStore struct {
 	subscribedPairs map[string]types.CurrencyPair
 	candlePeriod    time.Duration
 
+	// maxCandleGap is how much older than the newest candle of its pair a
+	// candle may be, if candleGapProvider, the provider the rejected candles
+	// are counted for, is set.
+	candleGapProvider types.ProviderName
+	maxCandleGap      time.Duration
+
+	// maxBufferedCandles bounds the candles kept for each pair, the oldest
+	// of them being dropped and counted for candleBufferProvider past it.
+	// The candles are only bounded by the candle period if it is not set.
+	candleBufferProvider types.ProviderName
+	maxBufferedCandles   int
+
+	// tickerReorderWindow is how long the timestamped ticks of a pair are
+	// buffered before the newest of them is used, with tickerTimes holding
+	// the exchange timestamp of the ticks used. Ticks are used as they
+	// arrive if it is not set.
+	tickerReorderWindow time.Duration
+	tickerTimes         map[string]int64
+	bufferedTickers     map[string]bufferedTicker
+
+	// lastReceived holds the time market data of each provider specific
+	// pair was last received.
+	lastReceived map[string]time.Time
+
+	// clock timestamps the market data received, the default clock being
+	// used if it is not set.
+	clock Clock
+
+	// reconnectCooldowns holds when the reconnect cooldown of each pair
+	// ends, the pairs whose connection never reconnected being omitted.
+	reconnectCooldowns map[string]time.Time
+
 	subscribedPairsMtx sync.RWMutex
 	tickerMtx          sync.RWMutex
 	candleMtx          sync.RWMutex
+	lastReceivedMtx    sync.RWMutex
+	clockMtx           sync.RWMutex
+	reconnectMtx       sync.RWMutex
 
 	// currencyPairToTickerPair translates CurrencyPair the provider specific string map index
 	currencyPairToTickerPair func(types.CurrencyPair) string
@@ -57,6 +93,7 @@ func newPriceStore(logger zerolog.Logger) priceStore {
 		tickers:                  map[string]types.TickerPrice{},
 		candles:                  map[string][]types.CandlePrice{},
 		subscribedPairs:          map[string]types.CurrencyPair{},
+		lastReceived:             map[string]time.Time{},
 		candlePeriod:             defaultCandlePeriod,
 		logger:                   logger,
 		currencyPairToTickerPair: defaultCurrencyPairTranslation,
@@ -92,6 +129,49 @@ func (ps *priceStore) addSubscribedPairs(cps ...types.CurrencyPair) []types.Curr
 	return newPairs
 }
 
+// UnsubscribeCurrencyPairs removes the currency pairs from the subscribed
+// pairs, so that subscribing to them again sends new subscriptions.
+func (ps *priceStore) UnsubscribeCurrencyPairs(cps ...types.CurrencyPair) {
+	ps.subscribedPairsMtx.Lock()
+	defer ps.subscribedPairsMtx.Unlock()
+
+	for _, cp := range cps {
+		delete(ps.subscribedPairs, cp.String())
+	}
+}
+
+// LastReceived returns the time the market data of each of the currency pairs
+// was last received. Pairs which never received any are omitted.
+func (ps *priceStore) LastReceived(cps ...types.CurrencyPair) map[types.CurrencyPair]time.Time {
+	ps.lastReceivedMtx.RLock()
+	defer ps.lastReceivedMtx.RUnlock()
+
+	lastReceived := make(map[types.CurrencyPair]time.Time, len(cps))
+	for _, cp := range cps {
+		tickerTime := ps.lastReceived[ps.currencyPairToTickerPair(cp)]
+		candleTime := ps.lastReceived[ps.curencyPairToCandlePair(cp)]
+		if candleTime.After(tickerTime) {
+			tickerTime = candleTime
+		}
+		if !tickerTime.IsZero() {
+			lastReceived[cp] = tickerTime
+		}
+	}
+	return lastReceived
+}
+
+// markReceived records that market data of the provider specific pair was
+// received.
+func (ps *priceStore) markReceived(currencyPair string) {
+	ps.lastReceivedMtx.Lock()
+	defer ps.lastReceivedMtx.Unlock()
+
+	if ps.lastReceived == nil {
+		ps.lastReceived = map[string]time.Time{}
+	}
+	ps.lastReceived[currencyPair] = ps.now()
+}
+
 // isSubscribed returns true if the provider is subscribed to the currency pair.
 func (ps *priceStore) isSubscribed(currencyPair string) bool {
 	ps.subscribedPairsMtx.RLock()
@@ -106,6 +186,10 @@ func (ps *priceStore) isSubscribed(currencyPair string) bool {
 // GetTickerPrices returns the tickerPrices based on the provided pairs. Logs a
 // warning for each currency pair that is not available.
 func (ps *priceStore) GetTickerPrices(pairs ...types.CurrencyPair) (types.CurrencyPairTickers, error) {
+	ps.tickerMtx.Lock()
+	ps.releaseBufferedTickers(ps.now())
+	ps.tickerMtx.Unlock()
+
 	ps.tickerMtx.RLock()
 	defer ps.tickerMtx.RUnlock()
 
@@ -151,7 +235,11 @@ func (ps *priceStore) setTickerPair(ticker providerTicker, currencyPair string)
 
 	oracleTicker, err := ticker.toTickerPrice()
 	if err != nil {
-		ps.logger.Error().Err(err).Msg("failed to convert providerTicker to TickerPrice")
+		ps.logConversionError(err, "failed to convert providerTicker to TickerPrice")
+		return
+	}
+	ps.markReceived(currencyPair)
+	if ps.bufferTicker(ticker, oracleTicker, currencyPair, ps.now()) {
 		return
 	}
 	ps.tickers[currencyPair] = oracleTicker
@@ -165,24 +253,49 @@ func (ps *priceStore) setCandlePair(candle providerCandle, currencyPair string)
 
 	oracleCandle, err := candle.toCandlePrice()
 	if err != nil {
-		ps.logger.Error().Err(err).Msg("failed to convert providerCandle to CandlePrice")
+		ps.logConversionError(err, "failed to convert providerCandle to CandlePrice")
 		return
 	}
 
 	ps.appendAndFilterCandles(oracleCandle, currencyPair)
 }
 
+// logConversionError logs the failed conversion of a provider price, with a
+// distinct message when a numeric field of the provider could not be parsed.
+func (ps *priceStore) logConversionError(err error, msg string) {
+	if errors.Is(err, types.ErrInvalidNumber) {
+		ps.logger.Error().Err(err).Msg("received unparseable numeric field from provider")
+		return
+	}
+	ps.logger.Error().Err(err).Msg(msg)
+}
+
+// appendAndFilterCandles adds the candle to the candles of the currency pair,
+// replacing the candle of the same timestamp and interval, and prunes the
+// stale candles. Replacing keeps a single candle per period however many
+// connections or polls of the provider deliver it, so it is never counted
+// twice, and the last one received is kept as the freshest. A candle
+// exceeding the max candle gap is rejected.
+//
 // Does not acquire lock - must be called from parent function
 func (ps *priceStore) appendAndFilterCandles(newCandle types.CandlePrice, currencyPair string) {
-	staleTime := PastUnixTime(ps.candlePeriod)
+	if ps.exceedsCandleGap(newCandle, currencyPair) {
+		return
+	}
+
+	staleTime := ps.pastUnixTime(ps.candlePeriod)
 	newCandles := []types.CandlePrice{newCandle}
 
 	for _, c := range ps.candles[currencyPair] {
+		if c.TimeStamp == newCandle.TimeStamp && c.Interval == newCandle.Interval {
+			continue
+		}
 		if staleTime < c.TimeStamp {
 			newCandles = append(newCandles, c)
 		}
 	}
-	ps.candles[currencyPair] = newCandles
+	ps.candles[currencyPair] = ps.boundCandles(newCandles, currencyPair)
+	ps.markReceived(currencyPair)
 }
 
 // All candles are in one min intervals where each candle starts exactly on the minute
@@ -190,12 +303,13 @@ func (ps *priceStore) addTradeToCandles(trade types.Trade, currencyPair string)
 	ps.candleMtx.Lock()
 	defer ps.candleMtx.Unlock()
 
-	tradeCandleStamp := time.Unix(trade.Time, 0).Truncate(time.Minute).Unix() + 60
+	tradeCandleStamp := time.UnixMilli(trade.Time).Truncate(time.Minute).Add(time.Minute).UnixMilli()
 	newCandle, err := types.NewCandlePrice(trade.Price, trade.Size, tradeCandleStamp)
 	if err != nil {
 		ps.logger.Error().Err(err).Msg("failed to parse trade values")
 		return
 	}
+	ps.markReceived(currencyPair)
 
 	if len(ps.candles[currencyPair]) == 0 {
 		ps.candles[currencyPair] = []types.CandlePrice{newCandle}
@@ -208,11 +322,11 @@ func (ps *priceStore) addTradeToCandles(trade types.Trade, currencyPair string)
 	})
 
 	// Try to find an existing candle that matches the trade
-	for _, c := range ps.candles[currencyPair] {
+	for i, c := range ps.candles[currencyPair] {
 		if c.TimeStamp == tradeCandleStamp {
 			// If the timestamps are equal add the volume to the candle and set the price to the newest trade
-			c.Price = newCandle.Price
-			c.Volume = c.Volume.Add(newCandle.Volume)
+			ps.candles[currencyPair][i].Price = newCandle.Price
+			ps.candles[currencyPair][i].Volume = c.Volume.Add(newCandle.Volume)
 			return
 		} else if c.TimeStamp < tradeCandleStamp {
 			// If we hit a candle that is older than the trade create a new candle
@@ -0,0 +1,164 @@
+package provider
+
+import (
+	"strconv"
+	"testing"
+	"time"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/gorilla/websocket"
+	"github.com/ojo-network/price-feeder/oracle/types"
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPriceStore_multipleConnections(t *testing.T) {
+	p := &AscendexProvider{
+		logger:     zerolog.Nop(),
+		markets:    make(map[string]*ascendexMarket),
+		priceStore: newPriceStore(zerolog.Nop()),
+	}
+	p.setCurrencyPairToTickerAndCandlePair(currencyPairToAscendexPair)
+	p.setSubscribedPairs(ATOMUSDT)
+
+	// the provider is sharded across two connections, which both deliver the
+	// pair while they overlap during a reconnect
+	connections := []*WebsocketConnection{{}, {}}
+
+	barStart := time.UnixMilli(PastUnixTime(0)).Truncate(time.Minute)
+	bar := func(close, volume string) []byte {
+		return []byte(`{"m":"bar","s":"ATOM/USDT","data":{"i":"1","ts":` +
+			strconv.FormatInt(barStart.UnixMilli(), 10) + `,"o":"11.52","c":"` + close +
+			`","h":"11.54","l":"11.51","v":"` + volume + `"}}`)
+	}
+	bbo := []byte(`{"m":"bbo","symbol":"ATOM/USDT","data":{"ts":1687944835188,` +
+		`"bid":["11.51","1"],"ask":["11.53","1"]}}`)
+
+	p.messageReceived(websocket.TextMessage, connections[0], bar("11.52", "20"))
+	p.messageReceived(websocket.TextMessage, connections[1], bar("11.52", "20"))
+	p.messageReceived(websocket.TextMessage, connections[0], bbo)
+	p.messageReceived(websocket.TextMessage, connections[1], bbo)
+
+	// the pair contributes a single candle and ticker
+	candles, err := p.GetCandlePrices(ATOMUSDT)
+	require.NoError(t, err)
+	require.Len(t, candles[ATOMUSDT], 1)
+	require.Equal(t, sdk.MustNewDecFromStr("20"), candles[ATOMUSDT][0].Volume)
+
+	prices, err := p.GetTickerPrices(ATOMUSDT)
+	require.NoError(t, err)
+	require.Len(t, prices, 1)
+	require.Equal(t, sdk.MustNewDecFromStr("20"), prices[ATOMUSDT].Volume)
+
+	// the freshest update of the candle is kept
+	p.messageReceived(websocket.TextMessage, connections[1], bar("11.53", "25"))
+
+	candles, err = p.GetCandlePrices(ATOMUSDT)
+	require.NoError(t, err)
+	require.Len(t, candles[ATOMUSDT], 1)
+	require.Equal(t, sdk.MustNewDecFromStr("11.53"), candles[ATOMUSDT][0].Price)
+	require.Equal(t, sdk.MustNewDecFromStr("25"), candles[ATOMUSDT][0].Volume)
+}
+
+func TestPriceStore_maxCandleGap(t *testing.T) {
+	ps := newPriceStore(zerolog.Nop())
+	newest := time.UnixMilli(PastUnixTime(0)).Truncate(time.Minute)
+	candle := func(timestamp time.Time, interval time.Duration) types.CandlePrice {
+		return types.CandlePrice{
+			Price:     sdk.OneDec(),
+			Volume:    sdk.OneDec(),
+			TimeStamp: timestamp.UnixMilli(),
+			Interval:  interval,
+		}
+	}
+	timestamps := func() []int64 {
+		var ts []int64
+		for _, c := range ps.candles["ATOMUSDT"] {
+			ts = append(ts, c.TimeStamp)
+		}
+		return ts
+	}
+
+	// without a max gap out-of-order candles are buffered
+	ps.appendAndFilterCandles(candle(newest, time.Minute), "ATOMUSDT")
+	ps.appendAndFilterCandles(candle(newest.Add(-2*time.Minute), time.Minute), "ATOMUSDT")
+	require.Len(t, ps.candles["ATOMUSDT"], 2)
+
+	ps.candles = map[string][]types.CandlePrice{}
+	ps.SetMaxCandleGap(ProviderBinance, time.Minute)
+
+	ps.appendAndFilterCandles(candle(newest, time.Minute), "ATOMUSDT")
+
+	// a candle within the gap of the newest one is buffered, and so is an
+	// update of the newest one
+	ps.appendAndFilterCandles(candle(newest.Add(-time.Minute), time.Minute), "ATOMUSDT")
+	ps.appendAndFilterCandles(candle(newest, time.Minute), "ATOMUSDT")
+	require.ElementsMatch(t, []int64{newest.UnixMilli(), newest.Add(-time.Minute).UnixMilli()}, timestamps())
+
+	// a candle older than the newest one by more than the gap is rejected
+	ps.appendAndFilterCandles(candle(newest.Add(-2*time.Minute), time.Minute), "ATOMUSDT")
+	require.Len(t, ps.candles["ATOMUSDT"], 2)
+
+	// the gap is relative to the newest candle of the same interval
+	ps.appendAndFilterCandles(candle(newest.Add(-3*time.Minute), 5*time.Minute), "ATOMUSDT")
+	require.Len(t, ps.candles["ATOMUSDT"], 3)
+
+	// a zero gap rejects every out-of-order candle
+	ps.SetMaxCandleGap(ProviderBinance, 0)
+	ps.appendAndFilterCandles(candle(newest.Add(-time.Minute), time.Minute), "ATOMUSDT")
+	ps.appendAndFilterCandles(candle(newest.Add(time.Minute), time.Minute), "ATOMUSDT")
+	require.Contains(t, timestamps(), newest.Add(time.Minute).UnixMilli())
+	require.Len(t, ps.candles["ATOMUSDT"], 4)
+}
+
+func TestPriceStore_maxBufferedCandles(t *testing.T) {
+	ps := newPriceStore(zerolog.Nop())
+	ps.SetMaxBufferedCandles(ProviderXt, 10)
+	droppedBefore := ProviderHealths()[ProviderXt].DroppedCandles
+
+	// a flood of candles with distinct timestamps within the candle period
+	// keeps the newest of them only
+	newest := time.UnixMilli(PastUnixTime(0))
+	for i := 999; i >= 0; i-- {
+		ps.appendAndFilterCandles(types.CandlePrice{
+			Price:     sdk.OneDec(),
+			Volume:    sdk.OneDec(),
+			TimeStamp: newest.Add(-time.Duration(i) * time.Millisecond).UnixMilli(),
+		}, "ATOMUSDT")
+		require.LessOrEqual(t, len(ps.candles["ATOMUSDT"]), 10)
+	}
+
+	candles := ps.candles["ATOMUSDT"]
+	require.Len(t, candles, 10)
+	for _, c := range candles {
+		require.GreaterOrEqual(t, c.TimeStamp, newest.Add(-9*time.Millisecond).UnixMilli())
+	}
+	require.Equal(t, uint64(990), ProviderHealths()[ProviderXt].DroppedCandles-droppedBefore)
+
+	// a candle older than the buffered ones is dropped on arrival
+	ps.appendAndFilterCandles(types.CandlePrice{
+		Price:     sdk.OneDec(),
+		Volume:    sdk.OneDec(),
+		TimeStamp: newest.Add(-time.Second).UnixMilli(),
+	}, "ATOMUSDT")
+	require.Len(t, ps.candles["ATOMUSDT"], 10)
+	for _, c := range ps.candles["ATOMUSDT"] {
+		require.NotEqual(t, newest.Add(-time.Second).UnixMilli(), c.TimeStamp)
+	}
+	require.Equal(t, uint64(991), ProviderHealths()[ProviderXt].DroppedCandles-droppedBefore)
+}
+
+func TestPriceStore_SetClock(t *testing.T) {
+	now := time.Unix(1672531200, 0)
+	ps := newPriceStore(zerolog.Nop())
+	ps.setSubscribedPairs(ATOMUSDT)
+
+	// the default clock is used until the provider is given its own
+	ps.markReceived(ATOMUSDT.String())
+	require.NotEqual(t, now, ps.LastReceived(ATOMUSDT)[ATOMUSDT])
+
+	ps.SetClock(FixedClock(now))
+	ps.markReceived(ATOMUSDT.String())
+	require.Equal(t, now, ps.LastReceived(ATOMUSDT)[ATOMUSDT])
+	require.Equal(t, now.Add(-time.Minute).UnixMilli(), ps.pastUnixTime(time.Minute))
+}
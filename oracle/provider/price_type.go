@@ -0,0 +1,115 @@
+package provider
+
+import (
+	"sync"
+
+	"github.com/ojo-network/price-feeder/oracle/types"
+)
+
+const (
+	// PriceTypeSpot uses the spot market ticker and candles of a pair.
+	PriceTypeSpot PriceType = "spot"
+
+	// PriceTypeMark uses the mark price of the pair's perpetual market.
+	PriceTypeMark PriceType = "mark"
+
+	// PriceTypeIndex uses the exchange's index price of the pair.
+	PriceTypeIndex PriceType = "index"
+)
+
+type (
+	// PriceType defines which market data a provider uses to price a
+	// currency pair.
+	PriceType string
+
+	// PairPriceType defines an override of the price type a provider uses
+	// for a given currency pair.
+	PairPriceType struct {
+		Base  string    `toml:"base" mapstructure:"base"`
+		Quote string    `toml:"quote" mapstructure:"quote"`
+		Type  PriceType `toml:"type" mapstructure:"type"`
+	}
+
+	// derivativeTicker combines a perpetual mark or index price with the
+	// traded volume of the perpetual market.
+	derivativeTicker struct {
+		price  string
+		volume string
+	}
+
+	// derivativeStore caches the latest derivative price and volume of each
+	// pair until both have been received from a provider.
+	derivativeStore struct {
+		mtx     sync.Mutex
+		prices  map[string]string
+		volumes map[string]string
+	}
+)
+
+// IsValid returns true if the price type is supported.
+func (pt PriceType) IsValid() bool {
+	switch pt {
+	case PriceTypeSpot, PriceTypeMark, PriceTypeIndex:
+		return true
+	}
+	return false
+}
+
+// CurrencyPair returns the currency pair the price type applies to.
+func (ppt PairPriceType) CurrencyPair() types.CurrencyPair {
+	return types.CurrencyPair{Base: ppt.Base, Quote: ppt.Quote}
+}
+
+// pairPriceTypes returns the non-spot price types configured on the endpoint
+// keyed by the provider specific symbol of each pair.
+func (e Endpoint) pairPriceTypes(toSymbol func(types.CurrencyPair) string) map[string]PriceType {
+	priceTypes := make(map[string]PriceType, len(e.PriceTypes))
+	for _, ppt := range e.PriceTypes {
+		if ppt.Type == PriceTypeSpot {
+			continue
+		}
+		priceTypes[toSymbol(ppt.CurrencyPair())] = ppt.Type
+	}
+	return priceTypes
+}
+
+func (dt derivativeTicker) toTickerPrice() (types.TickerPrice, error) {
+	return types.NewTickerPrice(dt.price, dt.volume)
+}
+
+func newDerivativeStore() derivativeStore {
+	return derivativeStore{
+		prices:  map[string]string{},
+		volumes: map[string]string{},
+	}
+}
+
+// setPrice stores the latest derivative price of a pair and returns the
+// combined ticker if the volume of the pair is known.
+func (ds *derivativeStore) setPrice(symbol, price string) (derivativeTicker, bool) {
+	ds.mtx.Lock()
+	defer ds.mtx.Unlock()
+
+	ds.prices[symbol] = price
+	return ds.ticker(symbol)
+}
+
+// setVolume stores the latest perpetual market volume of a pair and returns
+// the combined ticker if the derivative price of the pair is known.
+func (ds *derivativeStore) setVolume(symbol, volume string) (derivativeTicker, bool) {
+	ds.mtx.Lock()
+	defer ds.mtx.Unlock()
+
+	ds.volumes[symbol] = volume
+	return ds.ticker(symbol)
+}
+
+// Does not acquire lock - must be called from parent function
+func (ds *derivativeStore) ticker(symbol string) (derivativeTicker, bool) {
+	price, priceOk := ds.prices[symbol]
+	volume, volumeOk := ds.volumes[symbol]
+	if !priceOk || !volumeOk {
+		return derivativeTicker{}, false
+	}
+	return derivativeTicker{price: price, volume: volume}, true
+}
@@ -1,6 +1,8 @@
 package provider
 
 import (
+	"crypto/tls"
+	"net/http"
 	"time"
 
 	"github.com/ojo-network/price-feeder/oracle/types"
@@ -9,28 +11,50 @@ import (
 const (
 	defaultTimeout = 10 * time.Second
 
-	ProviderKraken     types.ProviderName = "kraken"
-	ProviderBinance    types.ProviderName = "binance"
-	ProviderBinanceUS  types.ProviderName = "binanceus"
-	ProviderOsmosis    types.ProviderName = "osmosis"
-	ProviderHuobi      types.ProviderName = "huobi"
-	ProviderOkx        types.ProviderName = "okx"
-	ProviderGate       types.ProviderName = "gate"
-	ProviderCoinbase   types.ProviderName = "coinbase"
-	ProviderBitget     types.ProviderName = "bitget"
-	ProviderMexc       types.ProviderName = "mexc"
-	ProviderCrypto     types.ProviderName = "crypto"
-	ProviderPolygon    types.ProviderName = "polygon"
-	ProviderCrescent   types.ProviderName = "crescent"
-	ProviderEthUniswap types.ProviderName = "eth-uniswap"
-	ProviderKujira     types.ProviderName = "kujira"
-	ProviderMock       types.ProviderName = "mock"
+	ProviderKraken         types.ProviderName = "kraken"
+	ProviderBinance        types.ProviderName = "binance"
+	ProviderBinanceUS      types.ProviderName = "binanceus"
+	ProviderOsmosis        types.ProviderName = "osmosis"
+	ProviderHuobi          types.ProviderName = "huobi"
+	ProviderOkx            types.ProviderName = "okx"
+	ProviderGate           types.ProviderName = "gate"
+	ProviderCoinbase       types.ProviderName = "coinbase"
+	ProviderBitget         types.ProviderName = "bitget"
+	ProviderMexc           types.ProviderName = "mexc"
+	ProviderCrypto         types.ProviderName = "crypto"
+	ProviderPolygon        types.ProviderName = "polygon"
+	ProviderCrescent       types.ProviderName = "crescent"
+	ProviderEthUniswap     types.ProviderName = "eth-uniswap"
+	ProviderKujira         types.ProviderName = "kujira"
+	ProviderInjective      types.ProviderName = "injective"
+	ProviderLbank          types.ProviderName = "lbank"
+	ProviderBingx          types.ProviderName = "bingx"
+	ProviderXt             types.ProviderName = "xt"
+	ProviderCoincheck      types.ProviderName = "coincheck"
+	ProviderJupiter        types.ProviderName = "jupiter"
+	ProviderChainlink      types.ProviderName = "chainlink"
+	ProviderOsmosisTwap    types.ProviderName = "osmosis-twap"
+	ProviderUniswapV3      types.ProviderName = "uniswap-v3"
+	ProviderCurve          types.ProviderName = "curve"
+	ProviderDydx           types.ProviderName = "dydx"
+	ProviderRedemptionRate types.ProviderName = "redemption-rate"
+	ProviderAscendex       types.ProviderName = "ascendex"
+	ProviderMock           types.ProviderName = "mock"
 )
 
 var (
 	ping = []byte("ping")
 )
 
+// candleIntervals returns the candle intervals configured on the endpoint, or
+// the default interval of the provider if none is set.
+func (e Endpoint) candleIntervals(defaultInterval string) []string {
+	if len(e.CandleIntervals) == 0 {
+		return []string{defaultInterval}
+	}
+	return e.CandleIntervals
+}
+
 type (
 	// Provider defines an interface an exchange price provider must implement.
 	Provider interface {
@@ -51,6 +75,19 @@ type (
 		StartConnections()
 	}
 
+	// SubscriptionTracker is implemented by the providers which track when
+	// the market data of their pairs was last received and can drop the
+	// subscriptions of pairs, so that they are subscribed to again.
+	SubscriptionTracker interface {
+		// LastReceived returns the time the market data of each of the
+		// currency pairs was last received.
+		LastReceived(...types.CurrencyPair) map[types.CurrencyPair]time.Time
+
+		// UnsubscribeCurrencyPairs removes the currency pairs from the
+		// providers subscribed pairs.
+		UnsubscribeCurrencyPairs(...types.CurrencyPair)
+	}
+
 	// Endpoint defines an override setting in our config for the
 	// hardcoded rest and websocket api endpoints.
 	Endpoint struct {
@@ -65,13 +102,124 @@ type (
 
 		// APIKey for API Key protected endpoints
 		APIKey string `toml:"apikey"`
+
+		// PriceTypes overrides the market data used to price the given pairs,
+		// ex. the perpetual mark price instead of the spot ticker
+		PriceTypes []PairPriceType `toml:"price_types" mapstructure:"price_types"`
+
+		// PriceSources overrides which price of the ticker is used for the
+		// given pairs, ex. the mid of the best bid and ask instead of the last
+		// traded price
+		PriceSources []PairPriceSource `toml:"price_sources" mapstructure:"price_sources"`
+
+		// CandleIntervals are the candle intervals subscribed to for every
+		// pair, ex. ["1m", "5m"]. The TVWAP prefers the finest interval and
+		// fills its gaps with the coarser ones
+		CandleIntervals []string `toml:"candle_intervals" mapstructure:"candle_intervals"`
+
+		// CACert is the path to a PEM CA bundle trusted for the provider's
+		// websocket and REST connections instead of the system roots
+		CACert string `toml:"ca_cert" mapstructure:"ca_cert"`
+
+		// InsecureSkipVerify disables the verification of the provider's TLS
+		// certificates
+		InsecureSkipVerify bool `toml:"insecure_skip_verify" mapstructure:"insecure_skip_verify"`
+
+		// Headers are custom HTTP headers sent on the provider's websocket
+		// handshake and REST requests, ex. an API version or partner id
+		Headers map[string]string `toml:"headers" mapstructure:"headers"`
+
+		// SubscriptionBatchSize makes the provider's websocket subscriptions
+		// share a connection and be sent that many at a time, waiting
+		// SubscriptionBatchDelay, ex. "250ms", between the batches
+		SubscriptionBatchSize  int    `toml:"subscription_batch_size" mapstructure:"subscription_batch_size"`
+		SubscriptionBatchDelay string `toml:"subscription_batch_delay" mapstructure:"subscription_batch_delay"`
+
+		// Compression is the compression of the provider's binary websocket
+		// messages, one of "gzip", "deflate", "zstd" or "none". It defaults to
+		// the compression the provider is known to use
+		Compression string `toml:"compression" mapstructure:"compression"`
+
+		// MaxCandleGap rejects the candles older than the newest candle of
+		// their pair by more than the gap, ex. "2m", and "0s" every
+		// out-of-order candle
+		MaxCandleGap string `toml:"max_candle_gap" mapstructure:"max_candle_gap"`
+
+		// TickerReorderWindow buffers the ticks of a pair for that window,
+		// ex. "500ms", so that the newest tick by exchange timestamp is used
+		// even if an older one arrives later. Ticks are used as they arrive
+		// by default
+		TickerReorderWindow string `toml:"ticker_reorder_window" mapstructure:"ticker_reorder_window"`
+
+		// CombinedStream multiplexes the streams of all the provider's pairs
+		// over a single connection to its combined stream endpoint, which
+		// wraps every payload with the name of its stream. Only supported by
+		// Binance
+		CombinedStream bool `toml:"combined_stream" mapstructure:"combined_stream"`
+
+		// OrderBookDepth subscribes to the order books of the provider's pairs
+		// and measures their depth within that fraction of the mid price, ex.
+		// "0.02" for 2%, to weight thin books down
+		OrderBookDepth string `toml:"order_book_depth" mapstructure:"order_book_depth"`
+
+		// MaxRoundAges are the ages past which the on-chain rounds of the
+		// given pairs are stale, ex. {"ETHUSD": 1h}. They are set from the
+		// chainlink section of the config
+		MaxRoundAges map[string]time.Duration `toml:"-" mapstructure:"-"`
+
+		// SilenceTimeout is the maximum duration a websocket connection may
+		// go without delivering a message before it is reconnected. It is set
+		// from provider_silence_timeout and zero disables silence detection
+		SilenceTimeout time.Duration `toml:"-" mapstructure:"-"`
+
+		// ReconnectCooldown is the duration after a websocket reconnect
+		// during which the prices of the pairs of the connection are not
+		// aggregated. It is set from reconnect_cooldown and zero disables it
+		ReconnectCooldown time.Duration `toml:"-" mapstructure:"-"`
+
+		// TwapPools are the Osmosis pools the TWAP of the given pairs is
+		// queried from, ex. {"OSMOUSDC": {PoolID: 1464, ...}}. They are set
+		// from the osmosis_twap section of the config
+		TwapPools map[string]TwapPool `toml:"-" mapstructure:"-"`
+
+		// UniswapV3Pools are the Uniswap v3 pools the price of the given pairs
+		// is read from, ex. {"WETHUSDC": {Address: "0x88e6...", ...}}. They
+		// are set from the uniswap_v3 section of the config
+		UniswapV3Pools map[string]UniswapV3Pool `toml:"-" mapstructure:"-"`
+
+		// CurvePools are the Curve pools the rate of the given pairs is read
+		// from, ex. {"USDCUSDT": {Address: "0xbebc...", ...}}. They are set
+		// from the curve section of the config
+		CurvePools map[string]CurvePool `toml:"-" mapstructure:"-"`
+
+		// DydxMarkets are the dYdX markets the given pairs are priced by, ex.
+		// {"BTCUSD": "BTC-USD"}, and PollInterval how often they are polled.
+		// They are set from the dydx section of the config
+		DydxMarkets  map[string]string `toml:"-" mapstructure:"-"`
+		PollInterval time.Duration     `toml:"-" mapstructure:"-"`
+
+		// RedemptionRates are the REST queries the redemption rate of the
+		// given pairs is read from, ex. {"STATOMATOM": {Path: "/Stride-Labs/...",
+		// ...}}. They are set from the redemption_rate section of the config,
+		// as is PollInterval
+		RedemptionRates map[string]RedemptionRateQuery `toml:"-" mapstructure:"-"`
+
+		// tlsConfig and client are the TLS configuration and the HTTP client
+		// using it loaded from CACert and InsecureSkipVerify by ConfigureTLS,
+		// nil if the provider uses the system roots
+		tlsConfig *tls.Config
+		client    *http.Client
+
+		// header holds the custom headers parsed from Headers by
+		// ConfigureHeaders, nil if the provider sends none
+		header http.Header
 	}
 )
 
 // PastUnixTime returns a millisecond timestamp that represents the unix time
 // minus t.
 func PastUnixTime(t time.Duration) int64 {
-	return time.Now().Add(t*-1).Unix() * int64(time.Second/time.Millisecond)
+	return Now().Add(t*-1).Unix() * int64(time.Second/time.Millisecond)
 }
 
 // SecondsToMilli converts seconds to milliseconds for our unix timestamps.
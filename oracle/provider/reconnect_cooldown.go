@@ -0,0 +1,79 @@
+package provider
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/ojo-network/price-feeder/oracle/types"
+)
+
+// DefaultReconnectCooldown is the duration after a websocket reconnect during
+// which the prices of the pairs of the connection are not trusted.
+const DefaultReconnectCooldown = 5 * time.Second
+
+type (
+	// ReconnectCooldownProvider defines a provider whose pairs are in a
+	// cooldown after the websocket connection they are received on
+	// reconnected, as the first messages after a reconnect can be a stale
+	// snapshot. Their prices are received but should not be aggregated yet.
+	ReconnectCooldownProvider interface {
+		InReconnectCooldown(cp types.CurrencyPair) bool
+	}
+
+	// reconnectRecorder records the reconnects of the websocket connections
+	// of a provider.
+	reconnectRecorder interface {
+		recordReconnect(subscriptionMsgs []interface{}, cooldown time.Duration)
+	}
+)
+
+// recordReconnect starts or restarts the reconnect cooldown of the subscribed
+// pairs whose symbol appears in the subscription messages of the reconnected
+// connection, or of every subscribed pair if none does. A zero cooldown
+// disables it.
+func (ps *priceStore) recordReconnect(subscriptionMsgs []interface{}, cooldown time.Duration) {
+	if cooldown <= 0 {
+		return
+	}
+
+	msgs := strings.ToLower(fmt.Sprintf("%v", subscriptionMsgs))
+	mentions := func(symbol string) bool {
+		return symbol != "" && strings.Contains(msgs, strings.ToLower(symbol))
+	}
+
+	ps.subscribedPairsMtx.RLock()
+	var pairs, allPairs []string
+	for key, cp := range ps.subscribedPairs {
+		allPairs = append(allPairs, key)
+		if mentions(ps.currencyPairToTickerPair(cp)) || mentions(ps.curencyPairToCandlePair(cp)) {
+			pairs = append(pairs, key)
+		}
+	}
+	ps.subscribedPairsMtx.RUnlock()
+	if len(pairs) == 0 {
+		pairs = allPairs
+	}
+
+	cooldownEnd := ps.now().Add(cooldown)
+	ps.reconnectMtx.Lock()
+	defer ps.reconnectMtx.Unlock()
+
+	if ps.reconnectCooldowns == nil {
+		ps.reconnectCooldowns = make(map[string]time.Time)
+	}
+	for _, pair := range pairs {
+		ps.reconnectCooldowns[pair] = cooldownEnd
+	}
+}
+
+// InReconnectCooldown returns true if the websocket connection the pair is
+// received on reconnected within the reconnect cooldown, in which case its
+// prices should not be aggregated yet.
+func (ps *priceStore) InReconnectCooldown(cp types.CurrencyPair) bool {
+	ps.reconnectMtx.RLock()
+	defer ps.reconnectMtx.RUnlock()
+
+	cooldownEnd, ok := ps.reconnectCooldowns[cp.String()]
+	return ok && ps.now().Before(cooldownEnd)
+}
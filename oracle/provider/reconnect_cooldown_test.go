@@ -0,0 +1,44 @@
+package provider
+
+import (
+	"testing"
+	"time"
+
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ojo-network/price-feeder/oracle/types"
+)
+
+func TestPriceStore_InReconnectCooldown(t *testing.T) {
+	now := time.Unix(1700000000, 0)
+	atomUSDT := types.CurrencyPair{Base: "ATOM", Quote: "USDT"}
+	osmoUSDT := types.CurrencyPair{Base: "OSMO", Quote: "USDT"}
+
+	ps := newPriceStore(zerolog.Nop())
+	ps.SetClock(FixedClock(now))
+	ps.setSubscribedPairs(atomUSDT, osmoUSDT)
+	require.False(t, ps.InReconnectCooldown(atomUSDT))
+
+	// only the pairs of the reconnected connection are in the cooldown
+	ps.recordReconnect([]interface{}{"atomusdt@ticker"}, DefaultReconnectCooldown)
+	require.True(t, ps.InReconnectCooldown(atomUSDT))
+	require.False(t, ps.InReconnectCooldown(osmoUSDT))
+
+	ps.SetClock(FixedClock(now.Add(DefaultReconnectCooldown - time.Millisecond)))
+	require.True(t, ps.InReconnectCooldown(atomUSDT))
+
+	ps.SetClock(FixedClock(now.Add(DefaultReconnectCooldown)))
+	require.False(t, ps.InReconnectCooldown(atomUSDT))
+
+	// a reconnect restarts the cooldown, and one whose messages name no
+	// subscribed pair applies to all of them
+	ps.recordReconnect([]interface{}{"ticker"}, DefaultReconnectCooldown)
+	require.True(t, ps.InReconnectCooldown(atomUSDT))
+	require.True(t, ps.InReconnectCooldown(osmoUSDT))
+
+	// a zero cooldown disables it
+	ps.SetClock(FixedClock(now.Add(time.Minute)))
+	ps.recordReconnect([]interface{}{"atomusdt@ticker"}, 0)
+	require.False(t, ps.InReconnectCooldown(atomUSDT))
+}
@@ -0,0 +1,333 @@
+package provider
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/rs/zerolog"
+
+	"github.com/ojo-network/price-feeder/oracle/types"
+)
+
+const (
+	redemptionRatePollInterval = time.Minute
+	redemptionRateMaxAge       = 10 * time.Minute
+)
+
+var _ Provider = (*RedemptionRateProvider)(nil)
+
+type (
+	// RedemptionRateProvider defines an Oracle provider which polls the
+	// redemption rate of liquid staking assets, ex. stATOM, from a REST query
+	// of their chain, such as the gRPC gateway of a module or the smart query
+	// of a contract. Each pair is the liquid staking asset quoted in its
+	// underlying asset, ex. STATOM/ATOM, mapped to its query by the queries set
+	// from the redemption_rate section of the config. A failed query keeps the
+	// last rate of its pair until it is older than the max age of the query,
+	// after which the pair stops contributing prices until it is queried
+	// again. Redemption rates carry no traded volume, so they are stored as
+	// candles and tickers without volume.
+	RedemptionRateProvider struct {
+		ctx       context.Context
+		logger    zerolog.Logger
+		mtx       sync.RWMutex
+		endpoints Endpoint
+		client    *http.Client
+
+		// updatedAt holds the time each pair's rate was last queried.
+		updatedAt map[string]time.Time
+
+		priceStore
+	}
+
+	// RedemptionRateQuery defines the REST query of the redemption rate of a
+	// pair, being the Field of the JSON response of the Path of the Rest
+	// endpoint of its chain, and the age past which the rate is stale.
+	RedemptionRateQuery struct {
+		Rest   string
+		Path   string
+		Field  string
+		MaxAge time.Duration
+	}
+
+	// redemptionRatePrice defines the redemption rate of a pair at the time
+	// it was polled.
+	redemptionRatePrice struct {
+		price     sdk.Dec
+		timeStamp int64
+	}
+)
+
+func NewRedemptionRateProvider(
+	ctx context.Context,
+	logger zerolog.Logger,
+	endpoints Endpoint,
+	pairs ...types.CurrencyPair,
+) (*RedemptionRateProvider, error) {
+	if endpoints.Name != ProviderRedemptionRate {
+		endpoints = Endpoint{
+			Name: ProviderRedemptionRate,
+		}
+	}
+
+	redemptionRateLogger := logger.With().Str("provider", string(ProviderRedemptionRate)).Logger()
+
+	provider := &RedemptionRateProvider{
+		ctx:        ctx,
+		logger:     redemptionRateLogger,
+		endpoints:  endpoints,
+		client:     endpoints.httpClient(),
+		updatedAt:  map[string]time.Time{},
+		priceStore: newPriceStore(redemptionRateLogger),
+	}
+	provider.setCurrencyPairToTickerAndCandlePair(currencyPairToRedemptionRatePair)
+
+	confirmedPairs, err := ConfirmPairAvailability(
+		provider,
+		provider.endpoints.Name,
+		provider.logger,
+		pairs...,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	provider.setSubscribedPairs(confirmedPairs...)
+
+	return provider, nil
+}
+
+// StartConnections starts polling the redemption rates of the subscribed
+// pairs every poll interval until the provider's context is canceled.
+func (p *RedemptionRateProvider) StartConnections() {
+	go func() {
+		ticker := time.NewTicker(p.pollInterval())
+		defer ticker.Stop()
+
+		for {
+			p.pollPrices()
+
+			select {
+			case <-p.ctx.Done():
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+}
+
+// SubscribeCurrencyPairs confirms the queries of the new currency pairs and
+// adds them to the providers subscribedPairs array
+func (p *RedemptionRateProvider) SubscribeCurrencyPairs(cps ...types.CurrencyPair) {
+	p.mtx.Lock()
+	defer p.mtx.Unlock()
+
+	confirmedPairs, err := ConfirmPairAvailability(
+		p,
+		p.endpoints.Name,
+		p.logger,
+		cps...,
+	)
+	if err != nil {
+		return
+	}
+
+	p.setSubscribedPairs(confirmedPairs...)
+}
+
+// pollPrices queries the redemption rate of every subscribed pair. The pairs
+// whose rate failed to be queried for longer than their max age stop
+// contributing prices until it is queried again.
+func (p *RedemptionRateProvider) pollPrices() {
+	p.subscribedPairsMtx.RLock()
+	pairs := types.MapPairsToSlice(p.subscribedPairs)
+	p.subscribedPairsMtx.RUnlock()
+
+	// the rates are queried without holding the lock, so a slow endpoint
+	// doesn't block the readers of the stored rates
+	prices := make(map[types.CurrencyPair]redemptionRatePrice, len(pairs))
+	errs := make(map[types.CurrencyPair]error)
+	for _, cp := range pairs {
+		price, err := p.queryPrice(currencyPairToRedemptionRatePair(cp))
+		if err != nil {
+			errs[cp] = err
+			continue
+		}
+		prices[cp] = price
+	}
+
+	p.mtx.Lock()
+	defer p.mtx.Unlock()
+
+	for cp, err := range errs {
+		p.handleFailure(cp, err)
+	}
+	for cp, price := range prices {
+		symbol := currencyPairToRedemptionRatePair(cp)
+		p.updatedAt[symbol] = p.now()
+		p.setTickerPair(price, symbol)
+		p.setCandlePair(price, symbol)
+	}
+}
+
+// handleFailure keeps the last rate of a pair whose query failed until it is
+// older than the max age of the query, and then removes its ticker and
+// candles, so it stops contributing prices.
+func (p *RedemptionRateProvider) handleFailure(cp types.CurrencyPair, err error) {
+	symbol := currencyPairToRedemptionRatePair(cp)
+	TelemetryFailure(ProviderRedemptionRate, MessageTypeTicker)
+
+	maxAge := p.maxAge(symbol)
+	if updatedAt, ok := p.updatedAt[symbol]; ok && p.now().Sub(updatedAt) <= maxAge {
+		p.logger.Warn().
+			Err(err).
+			Str("pair", cp.String()).
+			Time("updated_at", updatedAt).
+			Msg("failed to query redemption rate; keeping the last rate")
+		return
+	}
+
+	p.tickerMtx.Lock()
+	delete(p.tickers, symbol)
+	p.tickerMtx.Unlock()
+
+	p.candleMtx.Lock()
+	delete(p.candles, symbol)
+	p.candleMtx.Unlock()
+
+	p.logger.Error().
+		Err(err).
+		Str("pair", cp.String()).
+		Dur("max_age", maxAge).
+		Msg("redemption rate is stale; disabling pair until the next successful query")
+}
+
+// queryPrice queries the redemption rate of a pair from the REST endpoint of
+// its chain.
+func (p *RedemptionRateProvider) queryPrice(symbol string) (redemptionRatePrice, error) {
+	query, ok := p.endpoints.RedemptionRates[symbol]
+	if !ok {
+		return redemptionRatePrice{}, fmt.Errorf("redemption-rate: no query configured for %s", symbol)
+	}
+
+	req, err := http.NewRequestWithContext(p.ctx, http.MethodGet, query.Rest+query.Path, nil)
+	if err != nil {
+		return redemptionRatePrice{}, err
+	}
+
+	httpResp, err := p.client.Do(req)
+	if err != nil {
+		return redemptionRatePrice{}, err
+	}
+	defer httpResp.Body.Close()
+
+	if httpResp.StatusCode != http.StatusOK {
+		return redemptionRatePrice{}, fmt.Errorf(
+			"redemption-rate: unexpected status %s querying %s", httpResp.Status, symbol,
+		)
+	}
+
+	decoder := json.NewDecoder(httpResp.Body)
+	decoder.UseNumber()
+
+	var resp interface{}
+	if err := decoder.Decode(&resp); err != nil {
+		return redemptionRatePrice{}, fmt.Errorf("redemption-rate: failed to decode response: %w", err)
+	}
+
+	return newRedemptionRatePrice(resp, query.Field)
+}
+
+// GetAvailablePairs returns all pairs to which the provider can subscribe,
+// being every pair with a configured query.
+// ex.: map["STATOMATOM" => {}, "STOSMOOSMO" => {}].
+func (p *RedemptionRateProvider) GetAvailablePairs() (map[string]struct{}, error) {
+	availablePairs := make(map[string]struct{}, len(p.endpoints.RedemptionRates))
+	for symbol := range p.endpoints.RedemptionRates {
+		availablePairs[symbol] = struct{}{}
+	}
+
+	return availablePairs, nil
+}
+
+// pollInterval returns the interval the redemption rates are polled at.
+func (p *RedemptionRateProvider) pollInterval() time.Duration {
+	if p.endpoints.PollInterval > 0 {
+		return p.endpoints.PollInterval
+	}
+	return redemptionRatePollInterval
+}
+
+// maxAge returns the age past which the redemption rate of a pair is stale.
+func (p *RedemptionRateProvider) maxAge(symbol string) time.Duration {
+	if query, ok := p.endpoints.RedemptionRates[symbol]; ok && query.MaxAge > 0 {
+		return query.MaxAge
+	}
+	return redemptionRateMaxAge
+}
+
+func (rp redemptionRatePrice) toTickerPrice() (types.TickerPrice, error) {
+	return types.TickerPrice{
+		Price:  rp.price,
+		Volume: sdk.ZeroDec(),
+	}, nil
+}
+
+func (rp redemptionRatePrice) toCandlePrice() (types.CandlePrice, error) {
+	return types.CandlePrice{
+		Price:     rp.price,
+		Volume:    sdk.ZeroDec(),
+		TimeStamp: rp.timeStamp,
+	}, nil
+}
+
+// newRedemptionRatePrice reads the redemption rate at the dot separated field
+// of a decoded JSON response, ex. "host_zone.redemption_rate". The rate may
+// be a JSON string or number.
+func newRedemptionRatePrice(resp interface{}, field string) (redemptionRatePrice, error) {
+	value := resp
+	for _, key := range strings.Split(field, ".") {
+		object, ok := value.(map[string]interface{})
+		if !ok {
+			return redemptionRatePrice{}, fmt.Errorf("redemption-rate: field %s not found", field)
+		}
+		if value, ok = object[key]; !ok {
+			return redemptionRatePrice{}, fmt.Errorf("redemption-rate: field %s not found", field)
+		}
+	}
+
+	var rate string
+	switch v := value.(type) {
+	case string:
+		rate = v
+	case json.Number:
+		rate = v.String()
+	default:
+		return redemptionRatePrice{}, fmt.Errorf("redemption-rate: field %s is not a number", field)
+	}
+
+	price, err := types.ParseDec(rate)
+	if err != nil {
+		return redemptionRatePrice{}, fmt.Errorf("redemption-rate: failed to parse rate: %w", err)
+	}
+	if !price.IsPositive() {
+		return redemptionRatePrice{}, fmt.Errorf("redemption-rate: no rate available")
+	}
+
+	return redemptionRatePrice{
+		price:     price,
+		timeStamp: PastUnixTime(0),
+	}, nil
+}
+
+// currencyPairToRedemptionRatePair receives a currency pair and return the
+// symbol the provider stores its prices by, ex.: STATOMATOM.
+func currencyPairToRedemptionRatePair(cp types.CurrencyPair) string {
+	return strings.ToUpper(cp.String())
+}
@@ -0,0 +1,191 @@
+package provider
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ojo-network/price-feeder/oracle/types"
+)
+
+type redemptionRateTestServer struct {
+	*httptest.Server
+
+	mtx sync.Mutex
+	// responses holds the response of each query path.
+	responses map[string]string
+}
+
+func newRedemptionRateTestServer(t *testing.T) *redemptionRateTestServer {
+	ts := &redemptionRateTestServer{
+		responses: map[string]string{
+			"/Stride-Labs/stride/stakeibc/host_zone/cosmoshub-4":       `{"host_zone":{"chain_id":"cosmoshub-4","redemption_rate":"1.250000000000000000"}}`,
+			"/cosmwasm/wasm/v1/contract/terra1/smart/eyJzdGF0ZSI6e319": `{"data":{"exchange_rate":1.1}}`,
+		},
+	}
+	ts.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ts.mtx.Lock()
+		defer ts.mtx.Unlock()
+
+		resp, ok := ts.responses[r.URL.Path]
+		if !ok {
+			w.WriteHeader(http.StatusNotFound)
+			_, _ = w.Write([]byte(`{"code":5,"message":"not found"}`))
+			return
+		}
+		_, _ = w.Write([]byte(resp))
+	}))
+	t.Cleanup(ts.Close)
+
+	return ts
+}
+
+func TestRedemptionRateProvider_GetTickerPrices(t *testing.T) {
+	server := newRedemptionRateTestServer(t)
+
+	statomATOM := types.CurrencyPair{Base: "STATOM", Quote: "ATOM"}
+	ampLUNALUNA := types.CurrencyPair{Base: "AMPLUNA", Quote: "LUNA"}
+	p, err := NewRedemptionRateProvider(
+		context.TODO(),
+		zerolog.Nop(),
+		Endpoint{
+			Name: ProviderRedemptionRate,
+			RedemptionRates: map[string]RedemptionRateQuery{
+				"STATOMATOM": {
+					Rest:   server.URL,
+					Path:   "/Stride-Labs/stride/stakeibc/host_zone/cosmoshub-4",
+					Field:  "host_zone.redemption_rate",
+					MaxAge: 10 * time.Minute,
+				},
+				"AMPLUNALUNA": {
+					Rest:  server.URL,
+					Path:  "/cosmwasm/wasm/v1/contract/terra1/smart/eyJzdGF0ZSI6e319",
+					Field: "data.exchange_rate",
+				},
+			},
+		},
+		statomATOM,
+		ampLUNALUNA,
+		types.CurrencyPair{Base: "STOSMO", Quote: "OSMO"},
+	)
+	require.NoError(t, err)
+	require.Len(t, p.subscribedPairs, 2)
+
+	t.Run("valid_request_multi_ticker", func(t *testing.T) {
+		p.pollPrices()
+
+		prices, err := p.GetTickerPrices(statomATOM, ampLUNALUNA)
+		require.NoError(t, err)
+		require.Len(t, prices, 2)
+		require.Equal(t, sdk.MustNewDecFromStr("1.25"), prices[statomATOM].Price)
+		require.Equal(t, sdk.ZeroDec(), prices[statomATOM].Volume)
+		require.Equal(t, sdk.MustNewDecFromStr("1.1"), prices[ampLUNALUNA].Price)
+
+		candles, err := p.GetCandlePrices(statomATOM, ampLUNALUNA)
+		require.NoError(t, err)
+		require.Len(t, candles[statomATOM], 1)
+		require.Equal(t, sdk.MustNewDecFromStr("1.25"), candles[statomATOM][0].Price)
+	})
+
+	t.Run("failed_query_keeps_fresh_rate", func(t *testing.T) {
+		server.mtx.Lock()
+		delete(server.responses, "/Stride-Labs/stride/stakeibc/host_zone/cosmoshub-4")
+		server.mtx.Unlock()
+
+		p.pollPrices()
+
+		prices, err := p.GetTickerPrices(statomATOM, ampLUNALUNA)
+		require.NoError(t, err)
+		require.Len(t, prices, 2)
+		require.Equal(t, sdk.MustNewDecFromStr("1.25"), prices[statomATOM].Price)
+	})
+
+	t.Run("stale_rate_disables_pair", func(t *testing.T) {
+		SetClock(FixedClock(time.Now().Add(11 * time.Minute)))
+		defer SetClock(SystemClock{})
+
+		p.pollPrices()
+
+		prices, err := p.GetTickerPrices(statomATOM, ampLUNALUNA)
+		require.NoError(t, err)
+		require.Len(t, prices, 1)
+		require.Contains(t, prices, ampLUNALUNA)
+
+		candles, err := p.GetCandlePrices(statomATOM, ampLUNALUNA)
+		require.NoError(t, err)
+		require.NotContains(t, candles, statomATOM)
+	})
+}
+
+func TestNewRedemptionRatePrice(t *testing.T) {
+	testCases := []struct {
+		name     string
+		resp     string
+		field    string
+		expected string
+		err      bool
+	}{
+		{
+			name:     "string rate",
+			resp:     `{"host_zone":{"redemption_rate":"1.25"}}`,
+			field:    "host_zone.redemption_rate",
+			expected: "1.25",
+		},
+		{
+			name:     "number rate",
+			resp:     `{"data":{"exchange_rate":1.1}}`,
+			field:    "data.exchange_rate",
+			expected: "1.1",
+		},
+		{
+			name:  "missing field",
+			resp:  `{"host_zone":{}}`,
+			field: "host_zone.redemption_rate",
+			err:   true,
+		},
+		{
+			name:  "field of a non object",
+			resp:  `{"host_zone":"cosmoshub-4"}`,
+			field: "host_zone.redemption_rate",
+			err:   true,
+		},
+		{
+			name:  "non numeric rate",
+			resp:  `{"host_zone":{"redemption_rate":{"amount":"1.25"}}}`,
+			field: "host_zone.redemption_rate",
+			err:   true,
+		},
+		{
+			name:  "zero rate",
+			resp:  `{"host_zone":{"redemption_rate":"0"}}`,
+			field: "host_zone.redemption_rate",
+			err:   true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			decoder := json.NewDecoder(strings.NewReader(tc.resp))
+			decoder.UseNumber()
+			var resp interface{}
+			require.NoError(t, decoder.Decode(&resp))
+
+			price, err := newRedemptionRatePrice(resp, tc.field)
+			if tc.err {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, sdk.MustNewDecFromStr(tc.expected), price.price)
+		})
+	}
+}
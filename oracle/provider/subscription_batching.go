@@ -0,0 +1,101 @@
+package provider
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/rs/zerolog"
+
+	"github.com/ojo-network/price-feeder/oracle/types"
+)
+
+// defaultSubscriptionBatchDelay is the delay between the subscription batches
+// of an endpoint which sets a batch size but no delay.
+const defaultSubscriptionBatchDelay = 250 * time.Millisecond
+
+var (
+	// providerSubscriptionBatching holds the subscription batching of the
+	// providers whose endpoints set a batch size.
+	providerSubscriptionBatching    = map[types.ProviderName]SubscriptionBatching{}
+	providerSubscriptionBatchingMtx sync.RWMutex
+)
+
+// SubscriptionBatching defines how a provider paces its websocket
+// subscriptions. The subscription messages of the provider share a websocket
+// connection and are sent Size at a time, waiting Delay between the batches,
+// so exchanges which drop bursts of subscriptions accept all of them.
+type SubscriptionBatching struct {
+	Size  int
+	Delay time.Duration
+}
+
+// SubscriptionBatching returns the subscription batching of the endpoint,
+// which is disabled if it sets no batch size.
+func (e Endpoint) SubscriptionBatching() (SubscriptionBatching, error) {
+	if e.SubscriptionBatchSize < 0 {
+		return SubscriptionBatching{}, fmt.Errorf("subscription batch size of %s must not be negative", e.Name)
+	}
+	if e.SubscriptionBatchSize == 0 {
+		if e.SubscriptionBatchDelay != "" {
+			return SubscriptionBatching{}, fmt.Errorf("subscription batch delay of %s requires a batch size", e.Name)
+		}
+		return SubscriptionBatching{}, nil
+	}
+
+	delay := defaultSubscriptionBatchDelay
+	if e.SubscriptionBatchDelay != "" {
+		var err error
+		delay, err = time.ParseDuration(e.SubscriptionBatchDelay)
+		if err != nil {
+			return SubscriptionBatching{}, fmt.Errorf("subscription batch delay of %s must be a duration: %w", e.Name, err)
+		}
+		if delay < 0 {
+			return SubscriptionBatching{}, fmt.Errorf("subscription batch delay of %s must not be negative", e.Name)
+		}
+	}
+
+	return SubscriptionBatching{
+		Size:  e.SubscriptionBatchSize,
+		Delay: delay,
+	}, nil
+}
+
+// Enabled returns true if subscriptions are sent in batches.
+func (b SubscriptionBatching) Enabled() bool {
+	return b.Size > 0
+}
+
+// SetSubscriptionBatching sets the subscription batching of the endpoint's
+// provider. It applies to the websocket controllers created afterwards.
+func SetSubscriptionBatching(logger zerolog.Logger, endpoint Endpoint) error {
+	batching, err := endpoint.SubscriptionBatching()
+	if err != nil {
+		return err
+	}
+
+	providerSubscriptionBatchingMtx.Lock()
+	defer providerSubscriptionBatchingMtx.Unlock()
+
+	if !batching.Enabled() {
+		delete(providerSubscriptionBatching, endpoint.Name)
+		return nil
+	}
+
+	logger.Info().
+		Str("provider", endpoint.Name.String()).
+		Int("batch_size", batching.Size).
+		Dur("batch_delay", batching.Delay).
+		Msg("sending websocket subscriptions in batches")
+	providerSubscriptionBatching[endpoint.Name] = batching
+	return nil
+}
+
+// subscriptionBatching returns the subscription batching of the provider,
+// which is disabled if none is set.
+func subscriptionBatching(providerName types.ProviderName) SubscriptionBatching {
+	providerSubscriptionBatchingMtx.RLock()
+	defer providerSubscriptionBatchingMtx.RUnlock()
+
+	return providerSubscriptionBatching[providerName]
+}
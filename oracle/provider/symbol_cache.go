@@ -0,0 +1,207 @@
+package provider
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/rs/zerolog"
+
+	"github.com/ojo-network/price-feeder/oracle/types"
+)
+
+var (
+	// symbolCachePath is the file the available pairs of the providers are
+	// cached to, or empty if they are not cached.
+	symbolCachePath string
+	symbolCacheTTL  time.Duration
+
+	// symbolFetchRetries is the number of times fetching the available pairs
+	// of a provider is retried, waiting symbolRetryDelay before the first
+	// retry and twice as long before each next one.
+	symbolFetchRetries int
+	symbolRetryDelay   = time.Second
+
+	symbolCacheMtx sync.Mutex
+)
+
+type (
+	// symbolCache defines the on-disk cache of the available pairs of the
+	// providers.
+	symbolCache struct {
+		Providers map[types.ProviderName]cachedSymbols `json:"providers"`
+	}
+
+	// cachedSymbols defines the available pairs of a provider and when they
+	// were fetched.
+	cachedSymbols struct {
+		FetchedAt time.Time `json:"fetched_at"`
+		Pairs     []string  `json:"pairs"`
+	}
+)
+
+// SetSymbolCache sets the file the available pairs of the providers are cached
+// to, how long a cached list can stand in for the live one, and how many
+// times a failed fetch is retried. The pairs are not cached if the path is
+// empty.
+func SetSymbolCache(path string, ttl time.Duration, maxRetries int) {
+	symbolCacheMtx.Lock()
+	defer symbolCacheMtx.Unlock()
+
+	symbolCachePath = path
+	symbolCacheTTL = ttl
+	symbolFetchRetries = maxRetries
+}
+
+// getAvailablePairs fetches the available pairs of the provider, retrying a
+// failed fetch. The fetched pairs are cached, and if every fetch fails, the
+// cached pairs are returned instead as long as they are not older than the
+// TTL of the cache.
+func getAvailablePairs(
+	p Provider,
+	providerName types.ProviderName,
+	logger zerolog.Logger,
+) (map[string]struct{}, error) {
+	symbolCacheMtx.Lock()
+	retries, delay := symbolFetchRetries, symbolRetryDelay
+	symbolCacheMtx.Unlock()
+
+	var err error
+	for attempt := 0; ; attempt++ {
+		var availablePairs map[string]struct{}
+		availablePairs, err = p.GetAvailablePairs()
+		if err == nil {
+			if err := storeSymbols(providerName, availablePairs); err != nil {
+				logger.Warn().Err(err).Msg("failed to cache available pairs")
+			}
+			return availablePairs, nil
+		}
+		if attempt >= retries {
+			break
+		}
+
+		logger.Warn().Err(err).Int("attempt", attempt+1).Msg("failed to fetch available pairs; retrying")
+		time.Sleep(delay << attempt)
+	}
+
+	cached, fetchedAt, ok := loadSymbols(logger, providerName)
+	if !ok {
+		return nil, err
+	}
+
+	logger.Warn().
+		Err(err).
+		Time("fetched_at", fetchedAt).
+		Msg("failed to fetch available pairs; using cached pairs")
+	return cached, nil
+}
+
+// loadSymbols returns the cached available pairs of the provider and when they
+// were fetched, if they are cached and not older than the TTL of the cache.
+func loadSymbols(
+	logger zerolog.Logger,
+	providerName types.ProviderName,
+) (map[string]struct{}, time.Time, bool) {
+	symbolCacheMtx.Lock()
+	defer symbolCacheMtx.Unlock()
+
+	if symbolCachePath == "" {
+		return nil, time.Time{}, false
+	}
+
+	cache, err := readSymbolCache()
+	if err != nil {
+		logger.Warn().Err(err).Msg("failed to read symbol cache")
+		return nil, time.Time{}, false
+	}
+
+	symbols, ok := cache.Providers[providerName]
+	if !ok {
+		return nil, time.Time{}, false
+	}
+	if age := time.Since(symbols.FetchedAt); age > symbolCacheTTL {
+		logger.Warn().Dur("age", age).Msg("cached available pairs are older than the symbol cache TTL")
+		return nil, time.Time{}, false
+	}
+
+	availablePairs := make(map[string]struct{}, len(symbols.Pairs))
+	for _, pair := range symbols.Pairs {
+		availablePairs[pair] = struct{}{}
+	}
+	return availablePairs, symbols.FetchedAt, true
+}
+
+// storeSymbols caches the available pairs of the provider, keeping the cached
+// pairs of the other providers.
+func storeSymbols(providerName types.ProviderName, availablePairs map[string]struct{}) error {
+	symbolCacheMtx.Lock()
+	defer symbolCacheMtx.Unlock()
+
+	if symbolCachePath == "" {
+		return nil
+	}
+
+	// an unreadable cache is overwritten rather than blocking the new list
+	cache, err := readSymbolCache()
+	if err != nil {
+		cache = symbolCache{}
+	}
+	if cache.Providers == nil {
+		cache.Providers = make(map[types.ProviderName]cachedSymbols)
+	}
+
+	pairs := make([]string, 0, len(availablePairs))
+	for pair := range availablePairs {
+		pairs = append(pairs, pair)
+	}
+	sort.Strings(pairs)
+	cache.Providers[providerName] = cachedSymbols{
+		FetchedAt: time.Now(),
+		Pairs:     pairs,
+	}
+
+	bz, err := json.Marshal(cache)
+	if err != nil {
+		return err
+	}
+
+	// write to a temporary file first so a crash never leaves a partial cache
+	tmp, err := os.CreateTemp(filepath.Dir(symbolCachePath), filepath.Base(symbolCachePath)+".tmp")
+	if err != nil {
+		return fmt.Errorf("failed to create symbol cache: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(bz); err != nil {
+		tmp.Close()
+		return fmt.Errorf("failed to write symbol cache: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("failed to write symbol cache: %w", err)
+	}
+	return os.Rename(tmp.Name(), symbolCachePath)
+}
+
+// readSymbolCache reads the symbol cache, which is empty if it does not exist.
+//
+// Does not acquire lock - must be called from parent function
+func readSymbolCache() (symbolCache, error) {
+	bz, err := os.ReadFile(symbolCachePath)
+	if errors.Is(err, os.ErrNotExist) {
+		return symbolCache{}, nil
+	}
+	if err != nil {
+		return symbolCache{}, err
+	}
+
+	var cache symbolCache
+	if err := json.Unmarshal(bz, &cache); err != nil {
+		return symbolCache{}, fmt.Errorf("failed to decode symbol cache: %w", err)
+	}
+	return cache, nil
+}
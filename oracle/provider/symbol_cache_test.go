@@ -0,0 +1,96 @@
+package provider
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ojo-network/price-feeder/oracle/types"
+)
+
+// symbolsProvider is a provider whose available pairs fail to be fetched
+// while err is set.
+type symbolsProvider struct {
+	MockProvider
+	pairs   map[string]struct{}
+	err     error
+	fetches int
+}
+
+func (p *symbolsProvider) GetAvailablePairs() (map[string]struct{}, error) {
+	p.fetches++
+	if p.err != nil {
+		return nil, p.err
+	}
+	return p.pairs, nil
+}
+
+func TestConfirmPairAvailability_symbolCache(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "symbols.json")
+	SetSymbolCache(path, time.Hour, 2)
+	symbolRetryDelay = 0
+	defer func() {
+		SetSymbolCache("", 0, 0)
+		symbolRetryDelay = time.Second
+	}()
+
+	providerName := types.ProviderName("symbol-cache-test")
+	atomUSDT := types.CurrencyPair{Base: "ATOM", Quote: "USDT"}
+	ojoUSDT := types.CurrencyPair{Base: "OJO", Quote: "USDT"}
+	p := &symbolsProvider{pairs: map[string]struct{}{"ATOMUSDT": {}}}
+
+	// the live pairs are cached
+	pairs, err := ConfirmPairAvailability(p, providerName, zerolog.Nop(), atomUSDT, ojoUSDT)
+	require.NoError(t, err)
+	require.Equal(t, []types.CurrencyPair{atomUSDT}, pairs)
+	require.Equal(t, 1, p.fetches)
+	require.FileExists(t, path)
+
+	// a failed fetch is retried, then falls back to the cached pairs
+	p.err = errors.New("service unavailable")
+	p.fetches = 0
+	pairs, err = ConfirmPairAvailability(p, providerName, zerolog.Nop(), atomUSDT, ojoUSDT)
+	require.NoError(t, err)
+	require.Equal(t, []types.CurrencyPair{atomUSDT}, pairs)
+	require.Equal(t, 3, p.fetches)
+
+	// a provider without cached pairs still fails
+	_, err = ConfirmPairAvailability(p, "uncached", zerolog.Nop(), atomUSDT)
+	require.ErrorIs(t, err, p.err)
+
+	// and so does one whose cached pairs are older than the TTL
+	SetSymbolCache(path, 0, 0)
+	_, err = ConfirmPairAvailability(p, providerName, zerolog.Nop(), atomUSDT)
+	require.ErrorIs(t, err, p.err)
+}
+
+func TestConfirmPairAvailability_corruptSymbolCache(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "symbols.json")
+	require.NoError(t, os.WriteFile(path, []byte("{"), 0o600))
+	SetSymbolCache(path, time.Hour, 0)
+	defer SetSymbolCache("", 0, 0)
+
+	providerName := types.ProviderName("symbol-cache-test")
+	atomUSDT := types.CurrencyPair{Base: "ATOM", Quote: "USDT"}
+	p := &symbolsProvider{err: errors.New("service unavailable")}
+
+	// a corrupt cache is ignored
+	_, err := ConfirmPairAvailability(p, providerName, zerolog.Nop(), atomUSDT)
+	require.ErrorIs(t, err, p.err)
+
+	// and overwritten by the next live pairs
+	p.err = nil
+	p.pairs = map[string]struct{}{"ATOMUSDT": {}}
+	_, err = ConfirmPairAvailability(p, providerName, zerolog.Nop(), atomUSDT)
+	require.NoError(t, err)
+
+	p.err = errors.New("service unavailable")
+	pairs, err := ConfirmPairAvailability(p, providerName, zerolog.Nop(), atomUSDT)
+	require.NoError(t, err)
+	require.Equal(t, []types.CurrencyPair{atomUSDT}, pairs)
+}
@@ -4,12 +4,17 @@ import (
 	"github.com/armon/go-metrics"
 	"github.com/cosmos/cosmos-sdk/telemetry"
 	"github.com/ojo-network/price-feeder/oracle/types"
+	"github.com/rs/zerolog"
 )
 
 const (
 	MessageTypeCandle = MessageType("candle")
 	MessageTypeTicker = MessageType("ticker")
 	MessageTypeTrade  = MessageType("trade")
+
+	// maxLoggedFrameLength bounds the length of a frame logged when it fails
+	// to decode.
+	maxLoggedFrameLength = 512
 )
 
 type (
@@ -84,6 +89,133 @@ func telemetryWebsocketMessage(n types.ProviderName, mt MessageType) {
 	)
 }
 
+// telemetryProviderMessage gives an standard way to add
+// `price_feeder_provider_messages_total{provider="x"}` metric.
+func telemetryProviderMessage(n types.ProviderName) {
+	updateProviderHealth(n, func(h *ProviderHealth) {
+		h.Messages++
+	})
+	telemetry.IncrCounterWithLabels(
+		[]string{
+			"provider",
+			"messages",
+			"total",
+		},
+		1,
+		[]metrics.Label{
+			providerLabel(n),
+		},
+	)
+}
+
+// telemetryDecodeError gives an standard way to add
+// `price_feeder_provider_decode_errors_total{provider="x"}` metric, counting a
+// frame of the provider which failed to decode, and logs the frame, truncated,
+// at debug level.
+func telemetryDecodeError(logger zerolog.Logger, n types.ProviderName, bz []byte) {
+	updateProviderHealth(n, func(h *ProviderHealth) {
+		h.DecodeErrors++
+	})
+	telemetry.IncrCounterWithLabels(
+		[]string{
+			"provider",
+			"decode_errors",
+			"total",
+		},
+		1,
+		[]metrics.Label{
+			providerLabel(n),
+		},
+	)
+
+	frame := bz
+	if len(frame) > maxLoggedFrameLength {
+		frame = frame[:maxLoggedFrameLength]
+	}
+	logger.Debug().
+		Str("provider", n.String()).
+		Int("length", len(bz)).
+		Bytes("frame", frame).
+		Msg("failed to decode frame")
+}
+
+// telemetryWebsocketConnections gives an standard way to set the
+// `price_feeder_websocket_connections_open{provider="x"}` metric, after adding
+// the given number of connections of the provider and of them open.
+func telemetryWebsocketConnections(n types.ProviderName, connections, open int) {
+	health := updateProviderHealth(n, func(h *ProviderHealth) {
+		h.Connections += connections
+		h.Open += open
+	})
+	telemetry.SetGaugeWithLabels(
+		[]string{
+			"websocket",
+			"connections",
+			"open",
+		},
+		float32(health.Open),
+		[]metrics.Label{
+			providerLabel(n),
+		},
+	)
+}
+
+// telemetryProviderSilence gives an standard way to add
+// `price_feeder_provider_silence_total{provider="x"}` metric.
+func telemetryProviderSilence(n types.ProviderName) {
+	telemetry.IncrCounterWithLabels(
+		[]string{
+			"provider",
+			"silence",
+			"total",
+		},
+		1,
+		[]metrics.Label{
+			providerLabel(n),
+		},
+	)
+}
+
+// telemetryCandleRejected gives an standard way to add
+// `price_feeder_provider_candle_rejected{provider="x", pair="x"}` metric,
+// where pair is the provider specific pair.
+func telemetryCandleRejected(n types.ProviderName, pair string) {
+	telemetry.IncrCounterWithLabels(
+		[]string{
+			"provider",
+			"candle",
+			"rejected",
+		},
+		1,
+		[]metrics.Label{
+			providerLabel(n),
+			{Name: "pair", Value: pair},
+		},
+	)
+}
+
+// telemetryCandlesDropped gives an standard way to add
+// `price_feeder_provider_candle_dropped{provider="x", pair="x"}` metric,
+// counting the candles dropped from the full candle buffer of the provider
+// specific pair.
+func telemetryCandlesDropped(n types.ProviderName, pair string, dropped int) {
+	updateProviderHealth(n, func(h *ProviderHealth) {
+		h.DroppedCandles += uint64(dropped)
+	})
+	telemetry.IncrCounterWithLabels(
+		[]string{
+			"provider",
+			"candle",
+			"dropped",
+		},
+		float32(dropped),
+		[]metrics.Label{
+			providerLabel(n),
+			{Name: "pair", Value: pair},
+		},
+	)
+}
+
 // TelemetryFailure gives an standard way to add
 // `price_feeder_failure_provider{type="x", provider="x"}` metric.
 func TelemetryFailure(n types.ProviderName, mt MessageType) {
@@ -98,3 +230,134 @@ func TelemetryFailure(n types.ProviderName, mt MessageType) {
 		},
 	)
 }
+
+// TelemetryPriceBandRejected gives an standard way to add
+// `price_feeder_price_band_rejected{provider="x", pair="x"}` metric.
+func TelemetryPriceBandRejected(n types.ProviderName, cp types.CurrencyPair) {
+	telemetry.IncrCounterWithLabels(
+		[]string{
+			"price_band",
+			"rejected",
+		},
+		1,
+		[]metrics.Label{
+			providerLabel(n),
+			{
+				Name:  "pair",
+				Value: cp.String(),
+			},
+		},
+	)
+}
+
+// TelemetryReferenceDivergence gives an standard way to add
+// `price_feeder_reference_price_divergence{provider="x", pair="x"}` metric.
+func TelemetryReferenceDivergence(n types.ProviderName, cp types.CurrencyPair, divergence float32) {
+	telemetry.SetGaugeWithLabels(
+		[]string{
+			"reference_price",
+			"divergence",
+		},
+		divergence,
+		[]metrics.Label{
+			providerLabel(n),
+			{
+				Name:  "pair",
+				Value: cp.String(),
+			},
+		},
+	)
+}
+
+// TelemetryAnchorDepeg gives an standard way to add
+// `price_feeder_anchor_pair_depeg{pair="x"}` metric.
+func TelemetryAnchorDepeg(cp types.CurrencyPair, depeg float32) {
+	telemetry.SetGaugeWithLabels(
+		[]string{
+			"anchor_pair",
+			"depeg",
+		},
+		depeg,
+		[]metrics.Label{
+			{
+				Name:  "pair",
+				Value: cp.String(),
+			},
+		},
+	)
+}
+
+// TelemetryProviderGroups gives an standard way to add
+// `price_feeder_provider_groups{pair="x"}` metric.
+func TelemetryProviderGroups(cp types.CurrencyPair, groups int) {
+	telemetry.SetGaugeWithLabels(
+		[]string{
+			"provider_groups",
+		},
+		float32(groups),
+		[]metrics.Label{
+			{
+				Name:  "pair",
+				Value: cp.String(),
+			},
+		},
+	)
+}
+
+// TelemetryAgreementUnmet gives an standard way to add
+// `price_feeder_provider_agreement_unmet{pair="x"}` metric.
+func TelemetryAgreementUnmet(cp types.CurrencyPair) {
+	telemetry.IncrCounterWithLabels(
+		[]string{
+			"provider_agreement",
+			"unmet",
+		},
+		1,
+		[]metrics.Label{
+			{
+				Name:  "pair",
+				Value: cp.String(),
+			},
+		},
+	)
+}
+
+// TelemetryRequiredProviderMissing gives an standard way to add
+// `price_feeder_required_provider_missing{provider="x",pair="x"}` metric.
+func TelemetryRequiredProviderMissing(n types.ProviderName, cp types.CurrencyPair) {
+	telemetry.IncrCounterWithLabels(
+		[]string{
+			"required_provider",
+			"missing",
+		},
+		1,
+		[]metrics.Label{
+			{
+				Name:  "provider",
+				Value: n.String(),
+			},
+			{
+				Name:  "pair",
+				Value: cp.String(),
+			},
+		},
+	)
+}
+
+// TelemetryStaleForexRate gives an standard way to add
+// `price_feeder_forex_rate_stale{pair="x"}` metric.
+func TelemetryStaleForexRate(cp types.CurrencyPair) {
+	telemetry.IncrCounterWithLabels(
+		[]string{
+			"forex_rate",
+			"stale",
+		},
+		1,
+		[]metrics.Label{
+			{
+				Name:  "pair",
+				Value: cp.String(),
+			},
+		},
+	)
+}
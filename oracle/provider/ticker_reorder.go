@@ -0,0 +1,120 @@
+package provider
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/ojo-network/price-feeder/oracle/types"
+)
+
+type (
+	// TickerReorderer is implemented by the providers which can buffer the
+	// ticks of their pairs for a window, so that the newest tick by exchange
+	// timestamp is used rather than the last one to arrive.
+	TickerReorderer interface {
+		SetTickerReorderWindow(window time.Duration)
+	}
+
+	// timestampedTicker is implemented by the provider tickers carrying the
+	// exchange timestamp of the tick, in unix milliseconds.
+	timestampedTicker interface {
+		exchangeTime() int64
+	}
+
+	// bufferedTicker defines the newest tick of a pair received since its
+	// buffer was opened.
+	bufferedTicker struct {
+		ticker    types.TickerPrice
+		timeStamp int64
+		opened    time.Time
+	}
+)
+
+// ReorderWindow returns the ticker reorder window of the endpoint and whether
+// it is set.
+func (e Endpoint) ReorderWindow() (time.Duration, bool, error) {
+	if e.TickerReorderWindow == "" {
+		return 0, false, nil
+	}
+
+	window, err := time.ParseDuration(e.TickerReorderWindow)
+	if err != nil {
+		return 0, false, fmt.Errorf("ticker reorder window of %s must be a duration: %w", e.Name, err)
+	}
+	if window <= 0 {
+		return 0, false, fmt.Errorf("ticker reorder window of %s must be positive", e.Name)
+	}
+	return window, true, nil
+}
+
+// SetTickerReorderWindow makes the price store buffer the timestamped ticks
+// of a pair for the window before using the newest of them, and drop the
+// ticks older than the newest one used.
+func (ps *priceStore) SetTickerReorderWindow(window time.Duration) {
+	ps.tickerMtx.Lock()
+	defer ps.tickerMtx.Unlock()
+
+	ps.tickerReorderWindow = window
+	ps.tickerTimes = map[string]int64{}
+	ps.bufferedTickers = map[string]bufferedTicker{}
+}
+
+// bufferTicker buffers the tick of the currency pair if it carries its
+// exchange timestamp and a reorder window is set, dropping it if it is older
+// than the newest tick received. It returns false if the tick is not
+// buffered and should be used as is.
+//
+// Does not acquire lock - must be called from parent function
+func (ps *priceStore) bufferTicker(
+	ticker providerTicker,
+	tickerPrice types.TickerPrice,
+	currencyPair string,
+	now time.Time,
+) bool {
+	if ps.tickerReorderWindow <= 0 {
+		return false
+	}
+	timestamped, ok := ticker.(timestampedTicker)
+	if !ok || timestamped.exchangeTime() <= 0 {
+		return false
+	}
+	ps.releaseBufferedTickers(now)
+
+	timeStamp := timestamped.exchangeTime()
+	newest := ps.tickerTimes[currencyPair]
+	buffered, ok := ps.bufferedTickers[currencyPair]
+	if ok && buffered.timeStamp > newest {
+		newest = buffered.timeStamp
+	}
+	if timeStamp < newest {
+		ps.logger.Debug().
+			Str("pair", currencyPair).
+			Int64("timestamp", timeStamp).
+			Int64("newest_timestamp", newest).
+			Msg("dropped tick older than the newest tick")
+		return true
+	}
+
+	if !ok {
+		buffered.opened = now
+	}
+	buffered.ticker = tickerPrice
+	buffered.timeStamp = timeStamp
+	ps.bufferedTickers[currencyPair] = buffered
+	return true
+}
+
+// releaseBufferedTickers sets the buffered ticks of the pairs whose buffer
+// was opened at least the reorder window ago.
+//
+// Does not acquire lock - must be called from parent function
+func (ps *priceStore) releaseBufferedTickers(now time.Time) {
+	for currencyPair, buffered := range ps.bufferedTickers {
+		if now.Sub(buffered.opened) < ps.tickerReorderWindow {
+			continue
+		}
+		ps.tickers[currencyPair] = buffered.ticker
+		ps.tickerTimes[currencyPair] = buffered.timeStamp
+		delete(ps.bufferedTickers, currencyPair)
+	}
+}
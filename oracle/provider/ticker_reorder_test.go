@@ -0,0 +1,89 @@
+package provider
+
+import (
+	"strconv"
+	"testing"
+	"time"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/gorilla/websocket"
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEndpoint_ReorderWindow(t *testing.T) {
+	_, ok, err := Endpoint{}.ReorderWindow()
+	require.NoError(t, err)
+	require.False(t, ok)
+
+	window, ok, err := Endpoint{TickerReorderWindow: "500ms"}.ReorderWindow()
+	require.NoError(t, err)
+	require.True(t, ok)
+	require.Equal(t, 500*time.Millisecond, window)
+
+	for _, window := range []string{"500", "0s", "-1s"} {
+		_, _, err = Endpoint{TickerReorderWindow: window}.ReorderWindow()
+		require.Error(t, err, window)
+	}
+}
+
+func TestPriceStore_tickerReorderWindow(t *testing.T) {
+	now := time.Unix(1700000000, 0)
+	SetClock(FixedClock(now))
+	defer SetClock(SystemClock{})
+
+	p := &BinanceProvider{
+		logger:     zerolog.Nop(),
+		priceStore: newPriceStore(zerolog.Nop()),
+	}
+	p.SetTickerReorderWindow(time.Second)
+
+	tick := func(eventTime int64, price string) []byte {
+		return []byte(`{"e":"24hrTicker","E":` + strconv.FormatInt(eventTime, 10) +
+			`,"s":"ATOMUSDT","c":"` + price + `","v":"1000"}`)
+	}
+	tickerPrice := func() sdk.Dec {
+		prices, err := p.GetTickerPrices(ATOMUSDT)
+		require.NoError(t, err)
+		return prices[ATOMUSDT].Price
+	}
+
+	// ticks are held for the window, and the newest by exchange time wins
+	// even if it arrived first
+	p.messageReceived(websocket.TextMessage, nil, tick(1700000000300, "10.3"))
+	p.messageReceived(websocket.TextMessage, nil, tick(1700000000100, "10.1"))
+	p.messageReceived(websocket.TextMessage, nil, tick(1700000000200, "10.2"))
+	prices, err := p.GetTickerPrices(ATOMUSDT)
+	require.NoError(t, err)
+	require.Empty(t, prices)
+
+	SetClock(FixedClock(now.Add(time.Second)))
+	require.Equal(t, sdk.MustNewDecFromStr("10.3"), tickerPrice())
+
+	// a tick older than the one used arriving after the window is dropped
+	p.messageReceived(websocket.TextMessage, nil, tick(1700000000250, "10.25"))
+	SetClock(FixedClock(now.Add(2 * time.Second)))
+	require.Equal(t, sdk.MustNewDecFromStr("10.3"), tickerPrice())
+
+	// while a newer one replaces it
+	p.messageReceived(websocket.TextMessage, nil, tick(1700000001500, "10.5"))
+	SetClock(FixedClock(now.Add(3 * time.Second)))
+	require.Equal(t, sdk.MustNewDecFromStr("10.5"), tickerPrice())
+}
+
+func TestPriceStore_noTickerReorderWindow(t *testing.T) {
+	p := &BinanceProvider{
+		logger:     zerolog.Nop(),
+		priceStore: newPriceStore(zerolog.Nop()),
+	}
+
+	// ticks are used as they arrive by default
+	p.messageReceived(websocket.TextMessage, nil,
+		[]byte(`{"e":"24hrTicker","E":1700000000300,"s":"ATOMUSDT","c":"10.3","v":"1000"}`))
+	p.messageReceived(websocket.TextMessage, nil,
+		[]byte(`{"e":"24hrTicker","E":1700000000100,"s":"ATOMUSDT","c":"10.1","v":"1000"}`))
+
+	prices, err := p.GetTickerPrices(ATOMUSDT)
+	require.NoError(t, err)
+	require.Equal(t, sdk.MustNewDecFromStr("10.1"), prices[ATOMUSDT].Price)
+}
@@ -0,0 +1,100 @@
+package provider
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"net/http"
+	"os"
+
+	"github.com/gorilla/websocket"
+	"github.com/rs/zerolog"
+)
+
+// TLSConfig returns the TLS configuration of the endpoint's websocket and REST
+// connections, or nil if the endpoint uses the system roots. It fails if the
+// CA certificate file cannot be loaded.
+func (e Endpoint) TLSConfig() (*tls.Config, error) {
+	if e.CACert == "" && !e.InsecureSkipVerify {
+		return nil, nil
+	}
+
+	tlsConfig := &tls.Config{
+		MinVersion:         tls.VersionTLS12,
+		InsecureSkipVerify: e.InsecureSkipVerify, //nolint:gosec
+	}
+
+	if e.CACert != "" {
+		pem, err := os.ReadFile(e.CACert)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read CA certificate of %s: %w", e.Name, err)
+		}
+		rootCAs := x509.NewCertPool()
+		if !rootCAs.AppendCertsFromPEM(pem) {
+			return nil, fmt.Errorf("no valid PEM certificate in CA certificate %s of %s", e.CACert, e.Name)
+		}
+		tlsConfig.RootCAs = rootCAs
+	}
+
+	return tlsConfig, nil
+}
+
+// ConfigureTLS returns the endpoint with the TLS configuration, and the HTTP
+// client using it, its provider dials its websocket and REST endpoints with.
+func ConfigureTLS(logger zerolog.Logger, endpoint Endpoint) (Endpoint, error) {
+	tlsConfig, err := endpoint.TLSConfig()
+	if err != nil {
+		return Endpoint{}, err
+	}
+
+	endpoint.tlsConfig = tlsConfig
+	endpoint.client = nil
+	if tlsConfig == nil {
+		return endpoint, nil
+	}
+
+	if tlsConfig.InsecureSkipVerify {
+		logger.Warn().
+			Str("provider", endpoint.Name.String()).
+			Msg("TLS certificate verification is disabled; connections to the provider can be intercepted")
+	}
+
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.TLSClientConfig = tlsConfig
+	endpoint.client = &http.Client{Transport: transport, Timeout: defaultTimeout}
+	return endpoint, nil
+}
+
+// httpClient returns the client the provider sends its REST requests with,
+// which adds the provider's custom headers to every request.
+func (e Endpoint) httpClient() *http.Client {
+	client := e.client
+	if client == nil {
+		client = &http.Client{Timeout: defaultTimeout}
+	}
+
+	if e.header == nil {
+		return client
+	}
+
+	transport := client.Transport
+	if transport == nil {
+		transport = http.DefaultTransport
+	}
+	return &http.Client{
+		Transport: headerTransport{header: e.header, base: transport},
+		Timeout:   client.Timeout,
+	}
+}
+
+// websocketDialer returns the dialer connecting to a websocket with the given
+// TLS configuration, or with the system roots if nil.
+func websocketDialer(tlsConfig *tls.Config) *websocket.Dialer {
+	if tlsConfig == nil {
+		return websocket.DefaultDialer
+	}
+
+	dialer := *websocket.DefaultDialer
+	dialer.TLSClientConfig = tlsConfig
+	return &dialer
+}
@@ -0,0 +1,83 @@
+package provider
+
+import (
+	"encoding/pem"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/gorilla/websocket"
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEndpoint_TLSConfig(t *testing.T) {
+	tlsConfig, err := Endpoint{Name: ProviderBinance}.TLSConfig()
+	require.NoError(t, err)
+	require.Nil(t, tlsConfig)
+
+	tlsConfig, err = Endpoint{Name: ProviderBinance, InsecureSkipVerify: true}.TLSConfig()
+	require.NoError(t, err)
+	require.True(t, tlsConfig.InsecureSkipVerify)
+	require.Nil(t, tlsConfig.RootCAs)
+
+	_, err = Endpoint{Name: ProviderBinance, CACert: filepath.Join(t.TempDir(), "missing.pem")}.TLSConfig()
+	require.Error(t, err)
+
+	invalidCert := filepath.Join(t.TempDir(), "invalid.pem")
+	require.NoError(t, os.WriteFile(invalidCert, []byte("not a certificate"), 0o600))
+	_, err = Endpoint{Name: ProviderBinance, CACert: invalidCert}.TLSConfig()
+	require.Error(t, err)
+}
+
+func TestConfigureTLS_CustomCA(t *testing.T) {
+	upgrader := websocket.Upgrader{}
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !websocket.IsWebSocketUpgrade(r) {
+			_, _ = w.Write([]byte("ok"))
+			return
+		}
+		conn, err := upgrader.Upgrade(w, r, nil)
+		if err != nil {
+			return
+		}
+		conn.Close()
+	}))
+	t.Cleanup(server.Close)
+
+	caCert := filepath.Join(t.TempDir(), "ca.pem")
+	require.NoError(t, os.WriteFile(caCert, pem.EncodeToMemory(&pem.Block{
+		Type:  "CERTIFICATE",
+		Bytes: server.Certificate().Raw,
+	}), 0o600))
+	wsURL := "wss://" + strings.TrimPrefix(server.URL, "https://")
+
+	// the system roots do not trust the server
+	endpoint, err := ConfigureTLS(zerolog.Nop(), Endpoint{Name: ProviderMock})
+	require.NoError(t, err)
+	require.Equal(t, defaultTimeout, endpoint.httpClient().Timeout)
+	_, err = endpoint.httpClient().Get(server.URL)
+	require.Error(t, err)
+	_, _, err = websocketDialer(endpoint.tlsConfig).Dial(wsURL, nil)
+	require.Error(t, err)
+
+	endpoint, err = ConfigureTLS(zerolog.Nop(), Endpoint{Name: ProviderMock, CACert: caCert})
+	require.NoError(t, err)
+	require.Equal(t, defaultTimeout, endpoint.httpClient().Timeout)
+
+	resp, err := endpoint.httpClient().Get(server.URL)
+	require.NoError(t, err)
+	resp.Body.Close()
+
+	conn, resp, err := websocketDialer(endpoint.tlsConfig).Dial(wsURL, nil)
+	require.NoError(t, err)
+	resp.Body.Close()
+	conn.Close()
+
+	// other endpoints keep using the system roots
+	_, err = Endpoint{Name: ProviderBinance}.httpClient().Get(server.URL)
+	require.Error(t, err)
+}
@@ -4,12 +4,10 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
-	"net/http"
 	"net/url"
 	"strings"
 	"sync"
 
-	sdk "github.com/cosmos/cosmos-sdk/types"
 	"github.com/gorilla/websocket"
 	"github.com/ojo-network/price-feeder/oracle/types"
 	"github.com/rs/zerolog"
@@ -42,14 +40,14 @@ type (
 	}
 
 	UniswapTicker struct {
-		Price  string `json:"Price"`
-		Volume string `json:"Volume"`
+		Price  types.Number `json:"Price"`
+		Volume types.Number `json:"Volume"`
 	}
 
 	UniswapCandle struct {
-		Close   string `json:"Close"`
-		Volume  string `json:"Volume"`
-		EndTime int64  `json:"EndTime"`
+		Close   types.Number `json:"Close"`
+		Volume  types.Number `json:"Volume"`
+		EndTime int64        `json:"EndTime"`
 	}
 
 	// UniswapPairsSummary defines the response structure for an Uniswap pairs
@@ -73,9 +71,11 @@ func NewUniswapProvider(
 ) (*UniswapProvider, error) {
 	if endpoints.Name != ProviderEthUniswap {
 		endpoints = Endpoint{
-			Name:      ProviderEthUniswap,
-			Rest:      uniswapRestHost,
-			Websocket: uniswapWSHost,
+			Name:              ProviderEthUniswap,
+			Rest:              uniswapRestHost,
+			Websocket:         uniswapWSHost,
+			SilenceTimeout:    endpoints.SilenceTimeout,
+			ReconnectCooldown: endpoints.ReconnectCooldown,
 		}
 	}
 
@@ -115,6 +115,11 @@ func NewUniswapProvider(
 		provider.messageReceived,
 		defaultPingDuration,
 		websocket.PingMessage,
+		endpoints.SilenceTimeout,
+		endpoints.ReconnectCooldown,
+		&provider.priceStore,
+		endpoints.tlsConfig,
+		endpoints.header,
 		uniswapLogger,
 	)
 
@@ -165,6 +170,7 @@ func (p *UniswapProvider) messageReceived(_ int, _ *WebsocketConnection, bz []by
 			Int("length", len(bz)).
 			AnErr("message", messageErr).
 			Msg("Error on receive message")
+		telemetryDecodeError(p.logger, ProviderEthUniswap, bz)
 	}
 
 	// Check the response for currency pairs that the provider is subscribed
@@ -182,6 +188,7 @@ func (p *UniswapProvider) messageReceived(_ int, _ *WebsocketConnection, bz []by
 						Int("length", len(bz)).
 						AnErr("ticker", tickerErr).
 						Msg("Error on receive message")
+					telemetryDecodeError(p.logger, ProviderEthUniswap, bz)
 					continue
 				}
 				p.setTickerPair(
@@ -204,6 +211,7 @@ func (p *UniswapProvider) messageReceived(_ int, _ *WebsocketConnection, bz []by
 						Int("length", len(bz)).
 						AnErr("candle", candleErr).
 						Msg("Error on receive message")
+					telemetryDecodeError(p.logger, ProviderEthUniswap, bz)
 					continue
 				}
 				for _, singleCandle := range candleResp {
@@ -220,11 +228,11 @@ func (p *UniswapProvider) messageReceived(_ int, _ *WebsocketConnection, bz []by
 }
 
 func (o UniswapTicker) toTickerPrice() (types.TickerPrice, error) {
-	price, err := sdk.NewDecFromStr(o.Price)
+	price, err := o.Price.Dec()
 	if err != nil {
 		return types.TickerPrice{}, fmt.Errorf("uniswap: failed to parse ticker price: %w", err)
 	}
-	volume, err := sdk.NewDecFromStr(o.Volume)
+	volume, err := o.Volume.Dec()
 	if err != nil {
 		return types.TickerPrice{}, fmt.Errorf("uniswap: failed to parse ticker volume: %w", err)
 	}
@@ -237,11 +245,11 @@ func (o UniswapTicker) toTickerPrice() (types.TickerPrice, error) {
 }
 
 func (o UniswapCandle) toCandlePrice() (types.CandlePrice, error) {
-	close, err := sdk.NewDecFromStr(o.Close)
+	close, err := o.Close.Dec()
 	if err != nil {
 		return types.CandlePrice{}, fmt.Errorf("uniswap: failed to parse candle price: %w", err)
 	}
-	volume, err := sdk.NewDecFromStr(o.Volume)
+	volume, err := o.Volume.Dec()
 	if err != nil {
 		return types.CandlePrice{}, fmt.Errorf("uniswap: failed to parse candle volume: %w", err)
 	}
@@ -263,7 +271,7 @@ func (p *UniswapProvider) setSubscribedPairs(cps ...types.CurrencyPair) {
 // GetAvailablePairs returns all pairs to which the provider can subscribe.
 // ex.: map["ATOMUSDT" => {}, "OJOUSDC" => {}].
 func (p *UniswapProvider) GetAvailablePairs() (map[string]struct{}, error) {
-	resp, err := http.Get(p.endpoints.Rest + uniswapRestPath)
+	resp, err := p.endpoints.httpClient().Get(p.endpoints.Rest + uniswapRestPath)
 	if err != nil {
 		return nil, err
 	}
@@ -89,8 +89,8 @@ func TestUniswapProvider_GetCandlePrices(t *testing.T) {
 		time := int64(1000000)
 
 		candle := UniswapCandle{
-			Volume:  volume,
-			Close:   price,
+			Volume:  types.Number(volume),
+			Close:   types.Number(price),
 			EndTime: time,
 		}
 
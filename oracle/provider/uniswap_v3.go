@@ -0,0 +1,537 @@
+package provider
+
+import (
+	"context"
+	"encoding/hex"
+	"fmt"
+	"math/big"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/ojo-network/price-feeder/oracle/types"
+	"github.com/rs/zerolog"
+)
+
+const (
+	uniswapV3RestHost          = "https://ethereum-rpc.publicnode.com"
+	uniswapV3PricePollInterval = 15 * time.Second
+	uniswapV3MaxDecimals       = 36
+
+	// uniswapV3Slot0, uniswapV3Observe, uniswapV3Token0, uniswapV3Token1 and
+	// uniswapV3Fee are the selectors of the slot0(), observe(uint32[]),
+	// token0(), token1() and fee() functions of a pool, and uniswapV3Decimals
+	// the selector of the decimals() function of its tokens.
+	uniswapV3Slot0    = "0x3850c7bd"
+	uniswapV3Observe  = "0x883bdbfd"
+	uniswapV3Token0   = "0x0dfe1681"
+	uniswapV3Token1   = "0xd21220a7"
+	uniswapV3Fee      = "0xddca3f43"
+	uniswapV3Decimals = "0x313ce567"
+
+	// uniswapV3TickBase is the price ratio between two adjacent ticks.
+	uniswapV3TickBase = "1.0001"
+
+	// uniswapV3FloatPrec is the precision the price of a tick is computed
+	// with, enough to keep every significant digit of a Dec.
+	uniswapV3FloatPrec = 256
+)
+
+var _ Provider = (*UniswapV3Provider)(nil)
+
+type (
+	// UniswapV3Provider defines an Oracle provider which polls the price of
+	// Uniswap v3 pools through an EVM JSON-RPC endpoint. Each pair is mapped
+	// to its pool by the pools set from the uniswap_v3 section of the config.
+	// A pool with a TWAP window is priced by the average tick of its oracle
+	// observations over the window, which is far harder to manipulate than
+	// its spot price, and otherwise by the spot price of its slot0. The order
+	// and decimals of the pool's tokens are read from the chain once. A pair
+	// whose price fails to be read stops contributing prices until it is read
+	// again. Pools carry no traded volume, so prices are stored as candles
+	// and tickers without volume.
+	//
+	// REF: https://docs.uniswap.org/contracts/v3/reference/core/UniswapV3Pool
+	UniswapV3Provider struct {
+		ctx       context.Context
+		logger    zerolog.Logger
+		mtx       sync.RWMutex
+		endpoints Endpoint
+		client    *http.Client
+
+		// tokens holds the order and decimals of the tokens of each pair's
+		// pool once queried.
+		tokens map[string]uniswapV3Tokens
+
+		priceStore
+	}
+
+	// UniswapV3Pool defines the Uniswap v3 pool a pair is priced by, the
+	// address of its base token, its fee tier in hundredths of a bip and the
+	// window its TWAP is computed over, or zero to use its spot price.
+	UniswapV3Pool struct {
+		Address    string
+		BaseToken  string
+		FeeTier    uint32
+		TwapWindow time.Duration
+	}
+
+	// uniswapV3Tokens defines whether the base asset of a pair is the token0
+	// of its pool, and the decimals of the pool's tokens.
+	uniswapV3Tokens struct {
+		baseIsToken0 bool
+		decimals0    uint8
+		decimals1    uint8
+	}
+
+	// uniswapV3Price defines the price of a pool at the time it was polled.
+	uniswapV3Price struct {
+		price     sdk.Dec
+		timeStamp int64
+	}
+)
+
+func NewUniswapV3Provider(
+	ctx context.Context,
+	logger zerolog.Logger,
+	endpoints Endpoint,
+	pairs ...types.CurrencyPair,
+) (*UniswapV3Provider, error) {
+	if endpoints.Name != ProviderUniswapV3 {
+		endpoints = Endpoint{
+			Name: ProviderUniswapV3,
+			Rest: uniswapV3RestHost,
+		}
+	}
+
+	uniswapV3Logger := logger.With().Str("provider", string(ProviderUniswapV3)).Logger()
+
+	provider := &UniswapV3Provider{
+		ctx:        ctx,
+		logger:     uniswapV3Logger,
+		endpoints:  endpoints,
+		client:     endpoints.httpClient(),
+		tokens:     map[string]uniswapV3Tokens{},
+		priceStore: newPriceStore(uniswapV3Logger),
+	}
+	provider.setCurrencyPairToTickerAndCandlePair(currencyPairToUniswapV3Pair)
+
+	confirmedPairs, err := ConfirmPairAvailability(
+		provider,
+		provider.endpoints.Name,
+		provider.logger,
+		pairs...,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	provider.setSubscribedPairs(confirmedPairs...)
+
+	return provider, nil
+}
+
+// StartConnections starts polling the price of the pools of the subscribed
+// pairs until the provider's context is canceled.
+func (p *UniswapV3Provider) StartConnections() {
+	go func() {
+		ticker := time.NewTicker(uniswapV3PricePollInterval)
+		defer ticker.Stop()
+
+		for {
+			p.pollPrices()
+
+			select {
+			case <-p.ctx.Done():
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+}
+
+// SubscribeCurrencyPairs confirms the pools of the new currency pairs and
+// adds them to the providers subscribedPairs array
+func (p *UniswapV3Provider) SubscribeCurrencyPairs(cps ...types.CurrencyPair) {
+	p.mtx.Lock()
+	defer p.mtx.Unlock()
+
+	confirmedPairs, err := ConfirmPairAvailability(
+		p,
+		p.endpoints.Name,
+		p.logger,
+		cps...,
+	)
+	if err != nil {
+		return
+	}
+
+	p.setSubscribedPairs(confirmedPairs...)
+}
+
+// pollPrices reads the price of the pool of every subscribed pair. The pairs
+// whose price failed to be read stop contributing prices until it is read
+// again.
+func (p *UniswapV3Provider) pollPrices() {
+	p.mtx.Lock()
+	defer p.mtx.Unlock()
+
+	p.subscribedPairsMtx.RLock()
+	pairs := types.MapPairsToSlice(p.subscribedPairs)
+	p.subscribedPairsMtx.RUnlock()
+
+	for _, cp := range pairs {
+		symbol := currencyPairToUniswapV3Pair(cp)
+		price, err := p.queryPrice(symbol)
+		if err != nil {
+			p.disablePair(cp, err)
+			continue
+		}
+		p.setTickerPair(price, symbol)
+		p.setCandlePair(price, symbol)
+	}
+}
+
+// disablePair removes the ticker and candles of a pair, so it stops
+// contributing prices.
+func (p *UniswapV3Provider) disablePair(cp types.CurrencyPair, err error) {
+	symbol := currencyPairToUniswapV3Pair(cp)
+
+	p.tickerMtx.Lock()
+	delete(p.tickers, symbol)
+	p.tickerMtx.Unlock()
+
+	p.candleMtx.Lock()
+	delete(p.candles, symbol)
+	p.candleMtx.Unlock()
+
+	TelemetryFailure(ProviderUniswapV3, MessageTypeTicker)
+	p.logger.Error().
+		Err(err).
+		Str("pair", cp.String()).
+		Msg("failed to read pool price; disabling pair until the next successful read")
+}
+
+// queryPrice reads the price of the pool of a pair, from the TWAP of its
+// oracle observations if the pool has a TWAP window and from its spot price
+// otherwise.
+func (p *UniswapV3Provider) queryPrice(symbol string) (uniswapV3Price, error) {
+	pool, ok := p.endpoints.UniswapV3Pools[symbol]
+	if !ok {
+		return uniswapV3Price{}, fmt.Errorf("uniswap-v3: no pool configured for %s", symbol)
+	}
+
+	tokens, ok := p.tokens[symbol]
+	if !ok {
+		var err error
+		tokens, err = p.queryTokens(pool)
+		if err != nil {
+			return uniswapV3Price{}, err
+		}
+		p.tokens[symbol] = tokens
+	}
+
+	var (
+		ratio *big.Rat
+		err   error
+	)
+	if pool.TwapWindow > 0 {
+		ratio, err = p.queryTwapRatio(pool)
+	} else {
+		ratio, err = p.querySpotRatio(pool)
+	}
+	if err != nil {
+		return uniswapV3Price{}, err
+	}
+
+	return newUniswapV3Price(ratio, tokens)
+}
+
+// querySpotRatio returns the spot price of token0 in token1 base units from
+// the square root price of the pool's slot0.
+func (p *UniswapV3Provider) querySpotRatio(pool UniswapV3Pool) (*big.Rat, error) {
+	result, err := p.call(pool.Address, uniswapV3Slot0)
+	if err != nil {
+		return nil, err
+	}
+	if len(result) < evmWordSize {
+		return nil, fmt.Errorf("uniswap-v3: unexpected slot0 of %d bytes", len(result))
+	}
+
+	sqrtPriceX96 := new(big.Int).SetBytes(result[:evmWordSize])
+	if sqrtPriceX96.Sign() == 0 {
+		return nil, fmt.Errorf("uniswap-v3: pool %s is not initialized", pool.Address)
+	}
+	return sqrtPriceX96ToRatio(sqrtPriceX96), nil
+}
+
+// queryTwapRatio returns the price of token0 in token1 base units at the
+// average tick of the pool over its TWAP window, read from the tick
+// cumulatives of its oracle observations at the start and end of the window.
+func (p *UniswapV3Provider) queryTwapRatio(pool UniswapV3Pool) (*big.Rat, error) {
+	window := uint64(pool.TwapWindow / time.Second)
+	if window == 0 {
+		return nil, fmt.Errorf("uniswap-v3: twap window must be at least a second")
+	}
+
+	result, err := p.call(pool.Address, encodeUniswapV3Observe(window))
+	if err != nil {
+		return nil, fmt.Errorf("%w; the pool may not keep observations for the whole twap window", err)
+	}
+	tickCumulatives, err := decodeUniswapV3TickCumulatives(result)
+	if err != nil {
+		return nil, err
+	}
+
+	// the tick cumulatives are read from the start to the end of the window,
+	// and the average tick is rounded towards negative infinity like the
+	// pool's oracle library does
+	delta := new(big.Int).Sub(tickCumulatives[1], tickCumulatives[0])
+	tick := new(big.Int).Div(delta, new(big.Int).SetUint64(window))
+	if !tick.IsInt64() {
+		return nil, fmt.Errorf("uniswap-v3: invalid average tick %s", tick)
+	}
+	return tickToRatio(tick.Int64()), nil
+}
+
+// queryTokens returns the order and decimals of the tokens of a pool,
+// returning an error if the pool's fee doesn't match its fee tier or its
+// base token is neither of its tokens.
+func (p *UniswapV3Provider) queryTokens(pool UniswapV3Pool) (uniswapV3Tokens, error) {
+	result, err := p.call(pool.Address, uniswapV3Fee)
+	if err != nil {
+		return uniswapV3Tokens{}, err
+	}
+	fee, err := decodeUniswapV3Uint(result)
+	if err != nil {
+		return uniswapV3Tokens{}, err
+	}
+	if fee.Cmp(new(big.Int).SetUint64(uint64(pool.FeeTier))) != 0 {
+		return uniswapV3Tokens{}, fmt.Errorf(
+			"uniswap-v3: pool %s has fee tier %s, not %d", pool.Address, fee, pool.FeeTier,
+		)
+	}
+
+	token0, err := p.queryAddress(pool.Address, uniswapV3Token0)
+	if err != nil {
+		return uniswapV3Tokens{}, err
+	}
+	token1, err := p.queryAddress(pool.Address, uniswapV3Token1)
+	if err != nil {
+		return uniswapV3Tokens{}, err
+	}
+
+	var tokens uniswapV3Tokens
+	switch {
+	case strings.EqualFold(token0, pool.BaseToken):
+		tokens.baseIsToken0 = true
+	case strings.EqualFold(token1, pool.BaseToken):
+		tokens.baseIsToken0 = false
+	default:
+		return uniswapV3Tokens{}, fmt.Errorf(
+			"uniswap-v3: base token %s is not a token of pool %s", pool.BaseToken, pool.Address,
+		)
+	}
+
+	if tokens.decimals0, err = p.queryDecimals(token0); err != nil {
+		return uniswapV3Tokens{}, err
+	}
+	if tokens.decimals1, err = p.queryDecimals(token1); err != nil {
+		return uniswapV3Tokens{}, err
+	}
+	return tokens, nil
+}
+
+// queryAddress returns the address returned by a function of a contract.
+func (p *UniswapV3Provider) queryAddress(to string, data string) (string, error) {
+	result, err := p.call(to, data)
+	if err != nil {
+		return "", err
+	}
+	if len(result) != evmWordSize {
+		return "", fmt.Errorf("uniswap-v3: unexpected address of %d bytes", len(result))
+	}
+	return "0x" + hex.EncodeToString(result[evmWordSize-20:]), nil
+}
+
+// queryDecimals returns the decimals of a token.
+func (p *UniswapV3Provider) queryDecimals(token string) (uint8, error) {
+	result, err := p.call(token, uniswapV3Decimals)
+	if err != nil {
+		return 0, err
+	}
+	decimals, err := decodeUniswapV3Uint(result)
+	if err != nil {
+		return 0, err
+	}
+	if !decimals.IsUint64() || decimals.Uint64() > uniswapV3MaxDecimals {
+		return 0, fmt.Errorf("uniswap-v3: unsupported decimals %s of token %s", decimals, token)
+	}
+	return uint8(decimals.Uint64()), nil
+}
+
+// call executes an eth_call of the given data on a contract at the latest
+// block and returns the decoded result.
+func (p *UniswapV3Provider) call(to string, data string) ([]byte, error) {
+	return evmCall(p.ctx, p.client, p.endpoints.Rest, ProviderUniswapV3, to, data)
+}
+
+// GetAvailablePairs returns all pairs to which the provider can subscribe,
+// being every pair whose pool matches its fee tier and base token.
+// ex.: map["WETHUSDC" => {}, "UNIWETH" => {}].
+func (p *UniswapV3Provider) GetAvailablePairs() (map[string]struct{}, error) {
+	availablePairs := make(map[string]struct{}, len(p.endpoints.UniswapV3Pools))
+	for symbol, pool := range p.endpoints.UniswapV3Pools {
+		tokens, err := p.queryTokens(pool)
+		if err != nil {
+			p.logger.Warn().
+				Err(err).
+				Str("pool", pool.Address).
+				Msg("failed to query pool")
+			continue
+		}
+		p.tokens[symbol] = tokens
+		availablePairs[symbol] = struct{}{}
+	}
+
+	return availablePairs, nil
+}
+
+func (up uniswapV3Price) toTickerPrice() (types.TickerPrice, error) {
+	return types.TickerPrice{
+		Price:  up.price,
+		Volume: sdk.ZeroDec(),
+	}, nil
+}
+
+func (up uniswapV3Price) toCandlePrice() (types.CandlePrice, error) {
+	return types.CandlePrice{
+		Price:     up.price,
+		Volume:    sdk.ZeroDec(),
+		TimeStamp: up.timeStamp,
+	}, nil
+}
+
+// newUniswapV3Price converts the price of token0 in token1 base units to the
+// price of the base asset by scaling it by the decimals of the tokens, and
+// inverting it when the base asset is token1.
+func newUniswapV3Price(ratio *big.Rat, tokens uniswapV3Tokens) (uniswapV3Price, error) {
+	scale := new(big.Rat).SetFrac(
+		new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(tokens.decimals0)), nil),
+		new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(tokens.decimals1)), nil),
+	)
+	ratio = new(big.Rat).Mul(ratio, scale)
+	if !tokens.baseIsToken0 {
+		if ratio.Sign() == 0 {
+			return uniswapV3Price{}, fmt.Errorf("uniswap-v3: no price available")
+		}
+		ratio.Inv(ratio)
+	}
+
+	price, err := types.ParseDec(ratio.FloatString(sdk.Precision))
+	if err != nil {
+		return uniswapV3Price{}, fmt.Errorf("uniswap-v3: failed to parse price: %w", err)
+	}
+	if !price.IsPositive() {
+		return uniswapV3Price{}, fmt.Errorf("uniswap-v3: no price available")
+	}
+	return uniswapV3Price{
+		price:     price,
+		timeStamp: PastUnixTime(0),
+	}, nil
+}
+
+// sqrtPriceX96ToRatio returns the price of token0 in token1 base units of a
+// square root price in Q64.96 fixed point.
+func sqrtPriceX96ToRatio(sqrtPriceX96 *big.Int) *big.Rat {
+	return new(big.Rat).SetFrac(
+		new(big.Int).Mul(sqrtPriceX96, sqrtPriceX96),
+		new(big.Int).Lsh(big.NewInt(1), 192),
+	)
+}
+
+// tickToRatio returns the price of token0 in token1 base units at a tick,
+// being 1.0001^tick.
+func tickToRatio(tick int64) *big.Rat {
+	base, _, _ := big.ParseFloat(uniswapV3TickBase, 10, uniswapV3FloatPrec, big.ToNearestEven)
+	exponent := tick
+	if exponent < 0 {
+		exponent = -exponent
+	}
+
+	result := new(big.Float).SetPrec(uniswapV3FloatPrec).SetInt64(1)
+	for ; exponent > 0; exponent >>= 1 {
+		if exponent&1 == 1 {
+			result.Mul(result, base)
+		}
+		base = new(big.Float).SetPrec(uniswapV3FloatPrec).Mul(base, base)
+	}
+	if tick < 0 {
+		result.Quo(new(big.Float).SetPrec(uniswapV3FloatPrec).SetInt64(1), result)
+	}
+
+	ratio, _ := result.Rat(nil)
+	return ratio
+}
+
+// encodeUniswapV3Observe encodes the call of observe with the seconds ago of
+// the start and end of a window, being the window and zero.
+func encodeUniswapV3Observe(window uint64) string {
+	words := []*big.Int{
+		big.NewInt(evmWordSize), // offset of the secondsAgos array
+		big.NewInt(2),           // length of the secondsAgos array
+		new(big.Int).SetUint64(window),
+		big.NewInt(0),
+	}
+	data := make([]byte, 0, len(words)*evmWordSize)
+	for _, word := range words {
+		data = append(data, word.FillBytes(make([]byte, evmWordSize))...)
+	}
+	return uniswapV3Observe + hex.EncodeToString(data)
+}
+
+// decodeUniswapV3TickCumulatives decodes the tick cumulatives of the ABI
+// encoded result of observe, being the offsets of the tickCumulatives and
+// secondsPerLiquidityCumulativeX128s arrays followed by the arrays.
+func decodeUniswapV3TickCumulatives(result []byte) ([2]*big.Int, error) {
+	var tickCumulatives [2]*big.Int
+	if len(result) < evmWordSize {
+		return tickCumulatives, fmt.Errorf("uniswap-v3: unexpected observations of %d bytes", len(result))
+	}
+
+	offset := new(big.Int).SetBytes(result[:evmWordSize])
+	if !offset.IsUint64() || offset.Uint64()+3*evmWordSize > uint64(len(result)) {
+		return tickCumulatives, fmt.Errorf("uniswap-v3: invalid observations offset %s", offset)
+	}
+	array := result[offset.Uint64():]
+	if length := new(big.Int).SetBytes(array[:evmWordSize]); length.Cmp(big.NewInt(2)) != 0 {
+		return tickCumulatives, fmt.Errorf("uniswap-v3: unexpected %s tick cumulatives", length)
+	}
+
+	for i := range tickCumulatives {
+		word := array[(i+1)*evmWordSize : (i+2)*evmWordSize]
+		// the tick cumulatives are signed int56 sign-extended to a word
+		value := new(big.Int).SetBytes(word)
+		if word[0]&0x80 != 0 {
+			value.Sub(value, new(big.Int).Lsh(big.NewInt(1), 8*evmWordSize))
+		}
+		tickCumulatives[i] = value
+	}
+	return tickCumulatives, nil
+}
+
+// decodeUniswapV3Uint decodes an ABI encoded unsigned integer.
+func decodeUniswapV3Uint(result []byte) (*big.Int, error) {
+	if len(result) != evmWordSize {
+		return nil, fmt.Errorf("uniswap-v3: unexpected integer of %d bytes", len(result))
+	}
+	return new(big.Int).SetBytes(result), nil
+}
+
+// currencyPairToUniswapV3Pair receives a currency pair and return the symbol
+// the provider stores its prices by, ex.: WETHUSDC.
+func currencyPairToUniswapV3Pair(cp types.CurrencyPair) string {
+	return strings.ToUpper(cp.String())
+}
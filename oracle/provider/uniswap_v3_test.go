@@ -0,0 +1,334 @@
+package provider
+
+import (
+	"context"
+	"encoding/hex"
+	"encoding/json"
+	"math"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/ojo-network/price-feeder/oracle/types"
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	uniswapV3TestWETHUSDCPool = "0x88e6A0c2dDD26FEEb64F039a2c41296FcB3f5640"
+	uniswapV3TestUNIWETHPool  = "0x1d42064Fc4Beb5F8aAF85F4617AE8b3b5B8Bd801"
+	uniswapV3TestUSDC         = "0xA0b86991c6218b36c1d19D4a2e9Eb0cE3606eB48"
+	uniswapV3TestWETH         = "0xC02aaA39b223FE8D0A0e5C4F27eAD9083C756Cc2"
+	uniswapV3TestUNI          = "0x1f9840a85d5aF5bf1D1762F925BDADdC4201F984"
+
+	// uniswapV3TestTwapWindow is the TWAP window of the UNI/WETH pool, over
+	// which its average tick is -69082 after rounding down.
+	uniswapV3TestTwapWindow = 1800
+	uniswapV3TestTwapTick   = -69082
+)
+
+// uniswapV3TestPool defines the state of a test pool.
+type uniswapV3TestPool struct {
+	token0       string
+	token1       string
+	fee          int64
+	sqrtPriceX96 *big.Int
+	// tickCumulatives are the cumulatives at the start and end of the TWAP
+	// window, or nil if the pool has no observations that old.
+	tickCumulatives []int64
+}
+
+type uniswapV3TestServer struct {
+	*httptest.Server
+
+	mtx   sync.Mutex
+	pools map[string]uniswapV3TestPool
+}
+
+func newUniswapV3TestServer(t *testing.T) *uniswapV3TestServer {
+	// 2500 USDC per WETH is 4e8 wei per USDC base unit, the square of 20000
+	sqrtPriceX96 := new(big.Int).Lsh(big.NewInt(20000), 96)
+	tickCumulative := int64(-1000000)
+
+	ts := &uniswapV3TestServer{
+		pools: map[string]uniswapV3TestPool{
+			strings.ToLower(uniswapV3TestWETHUSDCPool): {
+				token0:       uniswapV3TestUSDC,
+				token1:       uniswapV3TestWETH,
+				fee:          500,
+				sqrtPriceX96: sqrtPriceX96,
+			},
+			strings.ToLower(uniswapV3TestUNIWETHPool): {
+				token0:       uniswapV3TestUNI,
+				token1:       uniswapV3TestWETH,
+				fee:          3000,
+				sqrtPriceX96: sqrtPriceX96,
+				tickCumulatives: []int64{
+					tickCumulative,
+					tickCumulative + (uniswapV3TestTwapTick+1)*uniswapV3TestTwapWindow - 1,
+				},
+			},
+		},
+	}
+	decimals := map[string]int64{
+		strings.ToLower(uniswapV3TestUSDC): 6,
+		strings.ToLower(uniswapV3TestWETH): 18,
+		strings.ToLower(uniswapV3TestUNI):  18,
+	}
+
+	ts.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ts.mtx.Lock()
+		defer ts.mtx.Unlock()
+
+		var req struct {
+			Method string            `json:"method"`
+			Params []json.RawMessage `json:"params"`
+		}
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+		require.Equal(t, "eth_call", req.Method)
+
+		var call evmCallParams
+		require.NoError(t, json.Unmarshal(req.Params[0], &call))
+
+		revert := func() {
+			_, _ = w.Write([]byte(`{"jsonrpc":"2.0","id":1,"error":{"code":-32000,"message":"execution reverted: OLD"}}`))
+		}
+
+		var words []*big.Int
+		if pool, ok := ts.pools[strings.ToLower(call.To)]; ok {
+			switch call.Data {
+			case uniswapV3Fee:
+				words = []*big.Int{big.NewInt(pool.fee)}
+			case uniswapV3Token0:
+				words = []*big.Int{testAddressWord(t, pool.token0)}
+			case uniswapV3Token1:
+				words = []*big.Int{testAddressWord(t, pool.token1)}
+			case uniswapV3Slot0:
+				words = []*big.Int{pool.sqrtPriceX96, big.NewInt(0), big.NewInt(0), big.NewInt(0), big.NewInt(0), big.NewInt(0), big.NewInt(1)}
+			case encodeUniswapV3Observe(uniswapV3TestTwapWindow):
+				if pool.tickCumulatives == nil {
+					revert()
+					return
+				}
+				words = []*big.Int{
+					big.NewInt(2 * evmWordSize),
+					big.NewInt(5 * evmWordSize),
+					big.NewInt(2),
+					testSignedWord(pool.tickCumulatives[0]),
+					testSignedWord(pool.tickCumulatives[1]),
+					big.NewInt(2),
+					big.NewInt(0),
+					big.NewInt(0),
+				}
+			}
+		} else if d, ok := decimals[strings.ToLower(call.To)]; ok && call.Data == uniswapV3Decimals {
+			words = []*big.Int{big.NewInt(d)}
+		}
+		if words == nil {
+			revert()
+			return
+		}
+
+		result := make([]byte, 0, len(words)*evmWordSize)
+		for _, word := range words {
+			result = append(result, word.FillBytes(make([]byte, evmWordSize))...)
+		}
+		_, _ = w.Write([]byte(`{"jsonrpc":"2.0","id":1,"result":"0x` + hex.EncodeToString(result) + `"}`))
+	}))
+	t.Cleanup(ts.Close)
+
+	return ts
+}
+
+// testAddressWord returns the ABI encoded word of an address.
+func testAddressWord(t *testing.T, address string) *big.Int {
+	bz, err := hex.DecodeString(strings.TrimPrefix(address, "0x"))
+	require.NoError(t, err)
+	return new(big.Int).SetBytes(bz)
+}
+
+// testSignedWord returns the two's complement word of a signed integer.
+func testSignedWord(value int64) *big.Int {
+	word := big.NewInt(value)
+	if value < 0 {
+		word.Add(word, new(big.Int).Lsh(big.NewInt(1), 8*evmWordSize))
+	}
+	return word
+}
+
+func TestUniswapV3Provider_GetTickerPrices(t *testing.T) {
+	server := newUniswapV3TestServer(t)
+
+	wethusdc := types.CurrencyPair{Base: "WETH", Quote: "USDC"}
+	uniweth := types.CurrencyPair{Base: "UNI", Quote: "WETH"}
+	p, err := NewUniswapV3Provider(
+		context.TODO(),
+		zerolog.Nop(),
+		Endpoint{
+			Name: ProviderUniswapV3,
+			Rest: server.URL,
+			UniswapV3Pools: map[string]UniswapV3Pool{
+				"WETHUSDC": {Address: uniswapV3TestWETHUSDCPool, BaseToken: uniswapV3TestWETH, FeeTier: 500},
+				"UNIWETH": {
+					Address:    uniswapV3TestUNIWETHPool,
+					BaseToken:  uniswapV3TestUNI,
+					FeeTier:    3000,
+					TwapWindow: uniswapV3TestTwapWindow * time.Second,
+				},
+				// the fee tier doesn't match the pool's
+				"USDCWETH": {Address: uniswapV3TestWETHUSDCPool, BaseToken: uniswapV3TestUSDC, FeeTier: 3000},
+				// the base token isn't a token of the pool
+				"USDCUNI": {Address: uniswapV3TestUNIWETHPool, BaseToken: uniswapV3TestUSDC, FeeTier: 3000},
+			},
+		},
+		wethusdc,
+		uniweth,
+		types.CurrencyPair{Base: "USDC", Quote: "WETH"},
+		types.CurrencyPair{Base: "USDC", Quote: "UNI"},
+		types.CurrencyPair{Base: "FOO", Quote: "USDC"},
+	)
+	require.NoError(t, err)
+	require.Len(t, p.subscribedPairs, 2)
+	require.Equal(t, uniswapV3Tokens{baseIsToken0: false, decimals0: 6, decimals1: 18}, p.tokens["WETHUSDC"])
+	require.Equal(t, uniswapV3Tokens{baseIsToken0: true, decimals0: 18, decimals1: 18}, p.tokens["UNIWETH"])
+
+	t.Run("valid_request_multi_ticker", func(t *testing.T) {
+		p.pollPrices()
+
+		prices, err := p.GetTickerPrices(wethusdc, uniweth)
+		require.NoError(t, err)
+		require.Len(t, prices, 2)
+
+		// the spot price of the pool is inverted as WETH is its token1
+		require.Equal(t, sdk.MustNewDecFromStr("2500"), prices[wethusdc].Price)
+		require.Equal(t, sdk.ZeroDec(), prices[wethusdc].Volume)
+
+		// the UNI/WETH pool is priced by its TWAP, not its spot price
+		twap, err := prices[uniweth].Price.Float64()
+		require.NoError(t, err)
+		require.InEpsilon(t, math.Pow(1.0001, uniswapV3TestTwapTick), twap, 1e-9)
+
+		candles, err := p.GetCandlePrices(wethusdc, uniweth)
+		require.NoError(t, err)
+		require.Len(t, candles[wethusdc], 1)
+		require.Equal(t, sdk.MustNewDecFromStr("2500"), candles[wethusdc][0].Price)
+	})
+
+	t.Run("missing_observations_disable_pair", func(t *testing.T) {
+		server.mtx.Lock()
+		pool := server.pools[strings.ToLower(uniswapV3TestUNIWETHPool)]
+		tickCumulatives := pool.tickCumulatives
+		pool.tickCumulatives = nil
+		server.pools[strings.ToLower(uniswapV3TestUNIWETHPool)] = pool
+		server.mtx.Unlock()
+
+		p.pollPrices()
+
+		prices, err := p.GetTickerPrices(wethusdc, uniweth)
+		require.NoError(t, err)
+		require.Len(t, prices, 1)
+		require.Contains(t, prices, wethusdc)
+
+		server.mtx.Lock()
+		pool.tickCumulatives = tickCumulatives
+		server.pools[strings.ToLower(uniswapV3TestUNIWETHPool)] = pool
+		server.mtx.Unlock()
+
+		p.pollPrices()
+
+		prices, err = p.GetTickerPrices(wethusdc, uniweth)
+		require.NoError(t, err)
+		require.Len(t, prices, 2)
+	})
+}
+
+func TestNewUniswapV3Price(t *testing.T) {
+	ratio := big.NewRat(4, 10000)
+
+	price, err := newUniswapV3Price(ratio, uniswapV3Tokens{baseIsToken0: true, decimals0: 18, decimals1: 18})
+	require.NoError(t, err)
+	require.Equal(t, sdk.MustNewDecFromStr("0.0004"), price.price)
+
+	price, err = newUniswapV3Price(ratio, uniswapV3Tokens{baseIsToken0: true, decimals0: 18, decimals1: 6})
+	require.NoError(t, err)
+	require.Equal(t, sdk.MustNewDecFromStr("400000000"), price.price)
+
+	price, err = newUniswapV3Price(ratio, uniswapV3Tokens{baseIsToken0: false, decimals0: 18, decimals1: 18})
+	require.NoError(t, err)
+	require.Equal(t, sdk.MustNewDecFromStr("2500"), price.price)
+
+	// prices below the precision of a Dec are unavailable
+	_, err = newUniswapV3Price(ratio, uniswapV3Tokens{baseIsToken0: true, decimals0: 0, decimals1: 18})
+	require.Error(t, err)
+
+	_, err = newUniswapV3Price(new(big.Rat), uniswapV3Tokens{baseIsToken0: false})
+	require.Error(t, err)
+}
+
+func TestTickToRatio(t *testing.T) {
+	require.Equal(t, big.NewRat(1, 1), tickToRatio(0))
+	require.Equal(t, "1.000100000000000000", tickToRatio(1).FloatString(18))
+	require.Equal(t, "0.999900009999000100", tickToRatio(-1).FloatString(18))
+
+	for _, tick := range []int64{-887272, -69082, 69082, 887272} {
+		ratio, _ := tickToRatio(tick).Float64()
+		require.InEpsilon(t, math.Pow(1.0001, float64(tick)), ratio, 1e-9)
+	}
+}
+
+func TestSqrtPriceX96ToRatio(t *testing.T) {
+	sqrtPriceX96 := new(big.Int).Lsh(big.NewInt(20000), 96)
+	require.Equal(t, big.NewRat(400000000, 1), sqrtPriceX96ToRatio(sqrtPriceX96))
+}
+
+func TestEncodeUniswapV3Observe(t *testing.T) {
+	require.Equal(
+		t,
+		"0x883bdbfd"+
+			"0000000000000000000000000000000000000000000000000000000000000020"+
+			"0000000000000000000000000000000000000000000000000000000000000002"+
+			"0000000000000000000000000000000000000000000000000000000000000708"+
+			"0000000000000000000000000000000000000000000000000000000000000000",
+		encodeUniswapV3Observe(1800),
+	)
+}
+
+func TestDecodeUniswapV3TickCumulatives(t *testing.T) {
+	encode := func(words ...*big.Int) []byte {
+		result := make([]byte, 0, len(words)*evmWordSize)
+		for _, word := range words {
+			result = append(result, word.FillBytes(make([]byte, evmWordSize))...)
+		}
+		return result
+	}
+
+	result := encode(
+		big.NewInt(2*evmWordSize),
+		big.NewInt(5*evmWordSize),
+		big.NewInt(2),
+		testSignedWord(-5),
+		testSignedWord(7),
+		big.NewInt(2),
+		big.NewInt(0),
+		big.NewInt(0),
+	)
+	tickCumulatives, err := decodeUniswapV3TickCumulatives(result)
+	require.NoError(t, err)
+	require.Equal(t, [2]*big.Int{big.NewInt(-5), big.NewInt(7)}, tickCumulatives)
+
+	// out of bounds offset
+	_, err = decodeUniswapV3TickCumulatives(encode(big.NewInt(10 * evmWordSize)))
+	require.Error(t, err)
+
+	// unexpected length
+	_, err = decodeUniswapV3TickCumulatives(encode(big.NewInt(evmWordSize), big.NewInt(3), big.NewInt(0), big.NewInt(0)))
+	require.Error(t, err)
+
+	_, err = decodeUniswapV3TickCumulatives(nil)
+	require.Error(t, err)
+}
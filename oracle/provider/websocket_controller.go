@@ -2,8 +2,12 @@ package provider
 
 import (
 	"context"
+	"crypto/tls"
+	"errors"
 	"fmt"
 	"math"
+	"net"
+	"net/http"
 	"net/url"
 	"strings"
 	"sync"
@@ -21,6 +25,12 @@ const (
 	disabledPingDuration      = time.Duration(0)
 	startingReconnectDuration = 5 * time.Second
 	maxRetryMultiplier        = 25 // max retry duration: 52m5s
+	disabledSilenceTimeout    = time.Duration(0)
+
+	// DefaultSilenceTimeout is the maximum duration a websocket connection
+	// may go without delivering a message before it is reconnected, unless
+	// overridden by the endpoint of the provider.
+	DefaultSilenceTimeout = 5 * time.Minute
 )
 
 type (
@@ -32,25 +42,39 @@ type (
 		websocketCancelFunc context.CancelFunc
 		providerName        types.ProviderName
 		websocketURL        url.URL
-		subscriptionMsg     interface{}
+		subscriptionMsgs    []interface{}
+		batching            SubscriptionBatching
+		compression         string
 		messageHandler      MessageHandler
 		pingDuration        time.Duration
 		pingMessageType     uint
+		silenceTimeout      time.Duration
+		reconnectCooldown   time.Duration
+		reconnects          reconnectRecorder
+		tlsConfig           *tls.Config
+		header              http.Header
 		logger              zerolog.Logger
 
 		mtx              sync.Mutex
 		client           *websocket.Conn
 		reconnectCounter uint
+		lastMessageTS    time.Time
+		connected        bool
 	}
 
 	// WebsocketController defines a provider agnostic websocket handler
 	// that manages reconnecting, subscribing, and receiving messages.
 	WebsocketController struct {
-		parentCtx    context.Context
-		providerName types.ProviderName
-		websocketURL url.URL
-		logger       zerolog.Logger
-		connections  []*WebsocketConnection
+		parentCtx         context.Context
+		providerName      types.ProviderName
+		websocketURL      url.URL
+		silenceTimeout    time.Duration
+		reconnectCooldown time.Duration
+		reconnects        reconnectRecorder
+		tlsConfig         *tls.Config
+		header            http.Header
+		logger            zerolog.Logger
+		connections       []*WebsocketConnection
 	}
 )
 
@@ -62,38 +86,27 @@ func NewWebsocketController(
 	messageHandler MessageHandler,
 	pingDuration time.Duration,
 	pingMessageType uint,
+	silenceTimeout time.Duration,
+	reconnectCooldown time.Duration,
+	reconnects reconnectRecorder,
+	tlsConfig *tls.Config,
+	header http.Header,
 	logger zerolog.Logger,
 ) *WebsocketController {
-	connections := make([]*WebsocketConnection, 0)
-
-	for _, subMsg := range subscriptionMsgs {
-		wsURL := websocketURL
-
-		// Use a different URL for okx candle subscriptions
-		if providerName == ProviderOkx && strings.Contains(fmt.Sprintf("%v", subMsg), "candle") {
-			wsURL = url.URL{Scheme: "wss", Host: okxWSHost, Path: okxWSPathBusiness}
-		}
-
-		connection := &WebsocketConnection{
-			parentCtx:       ctx,
-			providerName:    providerName,
-			websocketURL:    wsURL,
-			subscriptionMsg: subMsg,
-			messageHandler:  messageHandler,
-			pingDuration:    pingDuration,
-			pingMessageType: pingMessageType,
-			logger:          logger,
-		}
-		connections = append(connections, connection)
+	wsc := &WebsocketController{
+		parentCtx:         ctx,
+		providerName:      providerName,
+		websocketURL:      websocketURL,
+		silenceTimeout:    silenceTimeout,
+		reconnectCooldown: reconnectCooldown,
+		reconnects:        reconnects,
+		tlsConfig:         tlsConfig,
+		header:            header,
+		logger:            logger,
 	}
+	wsc.connections = wsc.newConnections(subscriptionMsgs, messageHandler, pingDuration, pingMessageType)
 
-	return &WebsocketController{
-		parentCtx:    ctx,
-		providerName: providerName,
-		websocketURL: websocketURL,
-		logger:       logger,
-		connections:  connections,
-	}
+	return wsc
 }
 
 func (wsc *WebsocketController) StartConnections() {
@@ -110,26 +123,92 @@ func (wsc *WebsocketController) AddWebsocketConnection(
 	pingDuration time.Duration,
 	pingMessageType uint,
 ) {
+	for _, conn := range wsc.newConnections(msgs, messageHandler, pingDuration, pingMessageType) {
+		wsc.connections = append(wsc.connections, conn)
+		go conn.start()
+	}
+}
+
+// newConnections returns the websocket connections sending the subscription
+// messages. Each message has a connection of its own, unless the provider
+// sends its subscriptions in batches, in which case the messages to the same
+// URL share a connection.
+func (wsc *WebsocketController) newConnections(
+	msgs []interface{},
+	messageHandler MessageHandler,
+	pingDuration time.Duration,
+	pingMessageType uint,
+) []*WebsocketConnection {
+	batching := subscriptionBatching(wsc.providerName)
+	compression := messageCompression(wsc.providerName)
+	connections := make([]*WebsocketConnection, 0, len(msgs))
+	connectionsByURL := make(map[string]*WebsocketConnection)
+
 	for _, msg := range msgs {
+		msgURL := subscriptionURL(wsc.providerName, wsc.websocketURL, msg)
+		if conn, ok := connectionsByURL[msgURL.String()]; ok {
+			conn.subscriptionMsgs = append(conn.subscriptionMsgs, msg)
+			continue
+		}
+
 		conn := &WebsocketConnection{
-			parentCtx:       wsc.parentCtx,
-			providerName:    wsc.providerName,
-			websocketURL:    wsc.websocketURL,
-			subscriptionMsg: msg,
-			messageHandler:  messageHandler,
-			pingDuration:    pingDuration,
-			pingMessageType: pingMessageType,
-			logger:          wsc.logger,
+			parentCtx:         wsc.parentCtx,
+			providerName:      wsc.providerName,
+			websocketURL:      msgURL,
+			subscriptionMsgs:  []interface{}{msg},
+			batching:          batching,
+			compression:       compression,
+			messageHandler:    messageHandler,
+			pingDuration:      pingDuration,
+			pingMessageType:   pingMessageType,
+			silenceTimeout:    wsc.silenceTimeout,
+			reconnectCooldown: wsc.reconnectCooldown,
+			reconnects:        wsc.reconnects,
+			tlsConfig:         wsc.tlsConfig,
+			header:            wsc.header,
+			logger:            wsc.logger,
+		}
+		connections = append(connections, conn)
+		telemetryWebsocketConnections(wsc.providerName, 1, 0)
+		if batching.Enabled() {
+			connectionsByURL[msgURL.String()] = conn
 		}
-		wsc.connections = append(wsc.connections, conn)
-		go conn.start()
 	}
+	return connections
+}
+
+// subscriptionURL returns the websocket URL a subscription message should be
+// sent to. Some providers serve specific channels on a different URL.
+func subscriptionURL(providerName types.ProviderName, websocketURL url.URL, subMsg interface{}) url.URL {
+	// Use a different URL for okx candle subscriptions
+	if providerName == ProviderOkx && strings.Contains(fmt.Sprintf("%v", subMsg), "candle") {
+		return url.URL{Scheme: "wss", Host: okxWSHost, Path: okxWSPathBusiness}
+	}
+
+	// Use the futures URL for binance mark price subscriptions
+	if providerName == ProviderBinance && strings.Contains(fmt.Sprintf("%v", subMsg), binanceMarkPriceStream) {
+		return url.URL{Scheme: "wss", Host: binanceFuturesWSHost, Path: binanceWSPath}
+	}
+
+	return websocketURL
+}
+
+// pingMessage returns the message sent to the websocket to keep the connection
+// alive. Some providers require pings in a specific format.
+func pingMessage(providerName types.ProviderName) []byte {
+	switch providerName {
+	case ProviderLbank:
+		return newLbankPingMsg()
+	case ProviderAscendex:
+		return newAscendexPingMsg()
+	}
+	return ping
 }
 
 // start will continuously loop and attempt connecting to the websocket
 // until a successful connection is made. It then starts the ping
-// service and read listener in new go routines and sends a subscription
-// message using the passed in subscription message.
+// service and read listener in new go routines and sends the subscription
+// messages of the connection.
 func (conn *WebsocketConnection) start() {
 	connectTicker := time.NewTicker(time.Millisecond)
 	defer connectTicker.Stop()
@@ -146,10 +225,10 @@ func (conn *WebsocketConnection) start() {
 			}
 		}
 
-		go conn.readWebSocket()
-		go conn.pingLoop()
+		go conn.readWebSocket(conn.websocketCtx)
+		go conn.pingLoop(conn.websocketCtx)
 
-		if err := conn.subscribe(conn.subscriptionMsg); err != nil {
+		if err := conn.subscribe(conn.subscriptionMsgs); err != nil {
 			conn.logger.Err(err).Send()
 			conn.close()
 			continue
@@ -164,7 +243,10 @@ func (conn *WebsocketConnection) connect() error {
 	defer conn.mtx.Unlock()
 
 	conn.logger.Debug().Msg("connecting to websocket")
-	connection, resp, err := websocket.DefaultDialer.Dial(conn.websocketURL.String(), nil)
+	connection, resp, err := websocketDialer(conn.tlsConfig).Dial(
+		conn.websocketURL.String(),
+		conn.header,
+	)
 	if err != nil {
 		return fmt.Errorf(types.ErrWebsocketDial.Error(), conn.providerName, err)
 	}
@@ -173,6 +255,14 @@ func (conn *WebsocketConnection) connect() error {
 	conn.websocketCtx, conn.websocketCancelFunc = context.WithCancel(conn.parentCtx)
 	conn.client.SetPingHandler(conn.pingHandler)
 	conn.reconnectCounter = 0
+	conn.lastMessageTS = time.Now()
+	telemetryWebsocketConnections(conn.providerName, 0, 1)
+
+	// the first messages after a reconnect can be a stale snapshot
+	if conn.connected && conn.reconnects != nil {
+		conn.reconnects.recordReconnect(conn.subscriptionMsgs, conn.reconnectCooldown)
+	}
+	conn.connected = true
 	return nil
 }
 
@@ -184,12 +274,24 @@ func (conn *WebsocketConnection) iterateRetryCounter() time.Duration {
 	return startingReconnectDuration * time.Duration(multiplier)
 }
 
-// subscribe sends the WebsocketConnections subscription message to the websocket.
-func (conn *WebsocketConnection) subscribe(msg interface{}) error {
-	telemetryWebsocketSubscribeCurrencyPairs(conn.providerName, 1)
-	conn.logger.Debug().Interface("msg", msg).Msg("sending subscription message")
-	if err := conn.SendJSON(msg); err != nil {
-		return fmt.Errorf(types.ErrWebsocketSend.Error(), conn.providerName, err)
+// subscribe sends the WebsocketConnections subscription messages to the
+// websocket. With subscription batching the messages are sent in batches,
+// waiting the batch delay between them.
+func (conn *WebsocketConnection) subscribe(msgs []interface{}) error {
+	telemetryWebsocketSubscribeCurrencyPairs(conn.providerName, len(msgs))
+	for i, msg := range msgs {
+		if conn.batching.Enabled() && i > 0 && i%conn.batching.Size == 0 {
+			select {
+			case <-conn.websocketCtx.Done():
+				return conn.websocketCtx.Err()
+			case <-time.After(conn.batching.Delay):
+			}
+		}
+
+		conn.logger.Debug().Interface("msg", msg).Msg("sending subscription message")
+		if err := conn.SendJSON(msg); err != nil {
+			return fmt.Errorf(types.ErrWebsocketSend.Error(), conn.providerName, err)
+		}
 	}
 	return nil
 }
@@ -210,7 +312,7 @@ func (conn *WebsocketConnection) SendJSON(msg interface{}) error {
 }
 
 // ping sends a ping to the server every defaultPingDuration
-func (conn *WebsocketConnection) pingLoop() {
+func (conn *WebsocketConnection) pingLoop(ctx context.Context) {
 	if conn.pingDuration == disabledPingDuration {
 		return // disable ping loop if disabledPingDuration
 	}
@@ -223,7 +325,7 @@ func (conn *WebsocketConnection) pingLoop() {
 			return
 		}
 		select {
-		case <-conn.websocketCtx.Done():
+		case <-ctx.Done():
 			return
 		case <-pingTicker.C:
 			continue
@@ -238,7 +340,7 @@ func (conn *WebsocketConnection) ping() error {
 	if conn.client == nil {
 		return fmt.Errorf("unable to ping closed connection")
 	}
-	err := conn.client.WriteMessage(int(conn.pingMessageType), ping)
+	err := conn.client.WriteMessage(int(conn.pingMessageType), pingMessage(conn.providerName))
 	if err != nil {
 		conn.logger.Err(fmt.Errorf(types.ErrWebsocketSend.Error(), conn.providerName, err)).Send()
 	}
@@ -250,19 +352,34 @@ func (conn *WebsocketConnection) ping() error {
 // terminates and starts the reconnect process.
 // Some providers (Binance) will only allow a valid connection for 24 hours
 // so we manually disconnect and reconnect every 23 hours (defaultMaxConnectionTime)
-func (conn *WebsocketConnection) readWebSocket() {
+// A connection which delivers no messages within the silence timeout is
+// reconnected as well, even if it still answers pings.
+func (conn *WebsocketConnection) readWebSocket(ctx context.Context) {
 	reconnectTicker := time.NewTicker(defaultMaxConnectionTime)
 	defer reconnectTicker.Stop()
 
 	for {
 		select {
-		case <-conn.websocketCtx.Done():
+		case <-ctx.Done():
 			conn.close()
 			return
 		case <-time.After(defaultReadNewWSMessage):
+			if conn.silenceTimeout != disabledSilenceTimeout {
+				if err := conn.client.SetReadDeadline(conn.lastMessageTS.Add(conn.silenceTimeout)); err != nil {
+					conn.logger.Err(fmt.Errorf(types.ErrWebsocketRead.Error(), conn.providerName, err)).Send()
+				}
+			}
 			messageType, bz, err := conn.client.ReadMessage()
 			if err != nil {
-				conn.logger.Err(fmt.Errorf(types.ErrWebsocketRead.Error(), conn.providerName, err)).Send()
+				var netErr net.Error
+				if errors.As(err, &netErr) && netErr.Timeout() {
+					telemetryProviderSilence(conn.providerName)
+					conn.logger.Warn().
+						Dur("silence_timeout", conn.silenceTimeout).
+						Msg("no messages received from websocket; reconnecting")
+				} else {
+					conn.logger.Err(fmt.Errorf(types.ErrWebsocketRead.Error(), conn.providerName, err)).Send()
+				}
 				conn.reconnect()
 				return
 			}
@@ -278,11 +395,24 @@ func (conn *WebsocketConnection) readSuccess(messageType int, bz []byte) {
 	if len(bz) == 0 {
 		return
 	}
+	if messageType == websocket.BinaryMessage && conn.compression != CompressionNone {
+		decompressed, err := decompressMessage(conn.compression, bz)
+		if err != nil {
+			conn.logger.Err(err).
+				Str("compression", conn.compression).
+				Msg("failed to decompress websocket message")
+			telemetryDecodeError(conn.logger, conn.providerName, bz)
+			return
+		}
+		bz = decompressed
+	}
 	// mexc and bitget do not send a valid pong response code so check for it here
 	if string(bz) == "pong" {
 		return
 	}
 
+	conn.lastMessageTS = time.Now()
+	telemetryProviderMessage(conn.providerName)
 	conn.messageHandler(messageType, conn, bz)
 }
 
@@ -300,6 +430,7 @@ func (conn *WebsocketConnection) close() {
 		conn.logger.Err(fmt.Errorf(types.ErrWebsocketClose.Error(), conn.providerName, err)).Send()
 	}
 	conn.client = nil
+	telemetryWebsocketConnections(conn.providerName, 0, -1)
 }
 
 // reconnect closes the current websocket and starts a new connection process
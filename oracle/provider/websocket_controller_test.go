@@ -1,10 +1,19 @@
 package provider
 
 import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sync/atomic"
 	"testing"
+	"time"
 
 	"github.com/gorilla/websocket"
+	"github.com/rs/zerolog"
 	"github.com/stretchr/testify/require"
+
+	"github.com/ojo-network/price-feeder/oracle/types"
 )
 
 type TestProvider struct {
@@ -60,3 +69,156 @@ func TestWebsocketController_readSuccess(t *testing.T) {
 		})
 	}
 }
+
+func TestWebsocketController_silenceReconnect(t *testing.T) {
+	var connections int32
+	upgrader := websocket.Upgrader{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		c, err := upgrader.Upgrade(w, r, nil)
+		if err != nil {
+			return
+		}
+		defer c.Close()
+		atomic.AddInt32(&connections, 1)
+
+		// answer pings but never deliver any data
+		for {
+			if _, _, err := c.ReadMessage(); err != nil {
+				return
+			}
+		}
+	}))
+	defer server.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	wsURL, err := url.Parse(server.URL)
+	require.NoError(t, err)
+	wsURL.Scheme = "ws"
+
+	cp := types.CurrencyPair{Base: "ATOM", Quote: "USDT"}
+	store := newPriceStore(zerolog.Nop())
+	store.setSubscribedPairs(cp)
+
+	provider := TestProvider{}
+	wsc := NewWebsocketController(
+		ctx,
+		ProviderMock,
+		*wsURL,
+		[]interface{}{"ATOMUSDT"},
+		provider.messageHandler,
+		10*time.Millisecond,
+		websocket.PingMessage,
+		100*time.Millisecond,
+		DefaultReconnectCooldown,
+		&store,
+		nil,
+		nil,
+		zerolog.Nop(),
+	)
+	wsc.StartConnections()
+
+	require.Eventually(t, func() bool {
+		return atomic.LoadInt32(&connections) >= 2
+	}, 5*time.Second, 10*time.Millisecond)
+	require.False(t, provider.handlerCalled)
+
+	// the pair of the reconnected connection is in its reconnect cooldown
+	require.Eventually(t, func() bool {
+		return store.InReconnectCooldown(cp)
+	}, 5*time.Second, 10*time.Millisecond)
+}
+
+func TestWebsocketController_subscriptionBatching(t *testing.T) {
+	require.NoError(t, SetSubscriptionBatching(zerolog.Nop(), Endpoint{
+		Name:                   ProviderMock,
+		SubscriptionBatchSize:  2,
+		SubscriptionBatchDelay: "100ms",
+	}))
+	defer func() {
+		require.NoError(t, SetSubscriptionBatching(zerolog.Nop(), Endpoint{Name: ProviderMock}))
+	}()
+
+	msgs := []interface{}{"a", "b", "c", "d", "e"}
+	received := make(chan []time.Time, 2)
+
+	upgrader := websocket.Upgrader{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		c, err := upgrader.Upgrade(w, r, nil)
+		if err != nil {
+			return
+		}
+		defer c.Close()
+
+		// receive every subscription, then drop the connection to check
+		// they are paced again after reconnecting
+		times := make([]time.Time, 0, len(msgs))
+		for len(times) < len(msgs) {
+			if _, _, err := c.ReadMessage(); err != nil {
+				return
+			}
+			times = append(times, time.Now())
+		}
+		received <- times
+	}))
+	defer server.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	wsURL, err := url.Parse(server.URL)
+	require.NoError(t, err)
+	wsURL.Scheme = "ws"
+
+	provider := TestProvider{}
+	wsc := NewWebsocketController(
+		ctx,
+		ProviderMock,
+		*wsURL,
+		msgs,
+		provider.messageHandler,
+		disabledPingDuration,
+		websocket.PingMessage,
+		DefaultSilenceTimeout,
+		0,
+		nil,
+		nil,
+		nil,
+		zerolog.Nop(),
+	)
+	require.Len(t, wsc.connections, 1)
+	wsc.StartConnections()
+
+	for i := 0; i < 2; i++ {
+		select {
+		case times := <-received:
+			// messages of a batch are sent at once, batches 100ms apart
+			require.Less(t, times[1].Sub(times[0]), 50*time.Millisecond)
+			require.GreaterOrEqual(t, times[2].Sub(times[1]), 90*time.Millisecond)
+			require.Less(t, times[3].Sub(times[2]), 50*time.Millisecond)
+			require.GreaterOrEqual(t, times[4].Sub(times[3]), 90*time.Millisecond)
+		case <-time.After(5 * time.Second):
+			t.Fatal("subscriptions not received")
+		}
+	}
+}
+
+func TestEndpoint_SubscriptionBatching(t *testing.T) {
+	batching, err := Endpoint{Name: ProviderMock}.SubscriptionBatching()
+	require.NoError(t, err)
+	require.False(t, batching.Enabled())
+
+	batching, err = Endpoint{Name: ProviderMock, SubscriptionBatchSize: 10}.SubscriptionBatching()
+	require.NoError(t, err)
+	require.Equal(t, SubscriptionBatching{Size: 10, Delay: defaultSubscriptionBatchDelay}, batching)
+
+	_, err = Endpoint{Name: ProviderMock, SubscriptionBatchSize: -1}.SubscriptionBatching()
+	require.Error(t, err)
+
+	_, err = Endpoint{Name: ProviderMock, SubscriptionBatchDelay: "1s"}.SubscriptionBatching()
+	require.Error(t, err)
+
+	_, err = Endpoint{Name: ProviderMock, SubscriptionBatchSize: 10, SubscriptionBatchDelay: "1"}.SubscriptionBatching()
+	require.Error(t, err)
+}
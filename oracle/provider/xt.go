@@ -0,0 +1,330 @@
+package provider
+
+import (
+	"context"
+	"encoding/json"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gorilla/websocket"
+	"github.com/ojo-network/price-feeder/oracle/types"
+	"github.com/rs/zerolog"
+)
+
+const (
+	xtWSHost   = "stream.xt.com"
+	xtWSPath   = "/public"
+	xtRestHost = "https://sapi.xt.com"
+	xtRestPath = "/v4/public/symbol"
+
+	xtTickerTopic  = "ticker"
+	xtCandleTopic  = "kline"
+	xtCandleRange  = "1m"
+	xtCandleLength = time.Minute // length of the xtCandleRange candles
+)
+
+var _ Provider = (*XtProvider)(nil)
+
+type (
+	// XtProvider defines an Oracle provider implemented by the XT.com public
+	// API.
+	//
+	// REF: https://doc.xt.com/#websocket_publicbaseInfo
+	XtProvider struct {
+		wsc       *WebsocketController
+		logger    zerolog.Logger
+		mtx       sync.RWMutex
+		endpoints Endpoint
+
+		// subscriptionsMtx guards subscriptions, the topics of each
+		// subscription message keyed by its id.
+		subscriptionsMtx sync.Mutex
+		subscriptions    map[string][]string
+
+		priceStore
+	}
+
+	// XtTickerResponse is the ticker topic response object.
+	XtTickerResponse struct {
+		Topic string   `json:"topic"` // ticker
+		Event string   `json:"event"` // ex.: ticker@btc_usdt
+		Data  XtTicker `json:"data"`
+	}
+	XtTicker struct {
+		Symbol    string       `json:"s"` // Symbol ex.: btc_usdt
+		LastPrice types.Number `json:"c"` // Last price ex.: 43508.9
+		Volume    types.Number `json:"q"` // Total traded base asset volume ex.: 1000
+	}
+
+	// XtCandleResponse is the kline topic response object.
+	XtCandleResponse struct {
+		Topic string   `json:"topic"` // kline
+		Event string   `json:"event"` // ex.: kline@btc_usdt,1m
+		Data  XtCandle `json:"data"`
+	}
+	XtCandle struct {
+		Symbol    string       `json:"s"` // Symbol ex.: btc_usdt
+		Close     types.Number `json:"c"` // Price at close
+		TimeStamp int64        `json:"t"` // Open time in unix epoch ex.: 1656043200000
+		Volume    types.Number `json:"q"` // Volume during period
+	}
+
+	// XtSubscriptionMsg Msg to subscribe to the topics of a pair.
+	XtSubscriptionMsg struct {
+		Method string   `json:"method"` // subscribe
+		Params []string `json:"params"` // topics ex.: ["ticker@btc_usdt","kline@btc_usdt,1m"]
+		ID     string   `json:"id"`     // identify the subscription response
+	}
+
+	// XtSubscriptionResponse is the response to a subscription message
+	// carrying its id.
+	XtSubscriptionResponse struct {
+		ID   string `json:"id"`   // id of the subscription message
+		Code *int   `json:"code"` // 0 on success
+		Msg  string `json:"msg"`  // ex.: success
+	}
+
+	// XtPairsSummary defines the response structure for the XT.com available
+	// pairs.
+	XtPairsSummary struct {
+		Result struct {
+			Symbols []struct {
+				Symbol string `json:"symbol"` // ex.: btc_usdt
+			} `json:"symbols"`
+		} `json:"result"`
+	}
+)
+
+func NewXtProvider(
+	ctx context.Context,
+	logger zerolog.Logger,
+	endpoints Endpoint,
+	pairs ...types.CurrencyPair,
+) (*XtProvider, error) {
+	if endpoints.Name != ProviderXt {
+		endpoints = Endpoint{
+			Name:              ProviderXt,
+			Rest:              xtRestHost,
+			Websocket:         xtWSHost,
+			SilenceTimeout:    endpoints.SilenceTimeout,
+			ReconnectCooldown: endpoints.ReconnectCooldown,
+		}
+	}
+
+	wsURL := url.URL{
+		Scheme: "wss",
+		Host:   endpoints.Websocket,
+		Path:   xtWSPath,
+	}
+
+	xtLogger := logger.With().Str("provider", string(ProviderXt)).Logger()
+
+	provider := &XtProvider{
+		logger:        xtLogger,
+		endpoints:     endpoints,
+		subscriptions: make(map[string][]string),
+		priceStore:    newPriceStore(xtLogger),
+	}
+	provider.setCurrencyPairToTickerAndCandlePair(currencyPairToXtPair)
+
+	confirmedPairs, err := ConfirmPairAvailability(
+		provider,
+		provider.endpoints.Name,
+		provider.logger,
+		pairs...,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	provider.setSubscribedPairs(confirmedPairs...)
+
+	provider.wsc = NewWebsocketController(
+		ctx,
+		endpoints.Name,
+		wsURL,
+		provider.getSubscriptionMsgs(confirmedPairs...),
+		provider.messageReceived,
+		defaultPingDuration,
+		websocket.TextMessage,
+		endpoints.SilenceTimeout,
+		endpoints.ReconnectCooldown,
+		&provider.priceStore,
+		endpoints.tlsConfig,
+		endpoints.header,
+		xtLogger,
+	)
+
+	return provider, nil
+}
+
+func (p *XtProvider) StartConnections() {
+	p.wsc.StartConnections()
+}
+
+// getSubscriptionMsgs returns a subscription message to the ticker and kline
+// topics of each pair, recording the topics of each message by its id.
+func (p *XtProvider) getSubscriptionMsgs(cps ...types.CurrencyPair) []interface{} {
+	p.subscriptionsMtx.Lock()
+	defer p.subscriptionsMtx.Unlock()
+
+	subscriptionMsgs := make([]interface{}, 0, len(cps))
+	for _, cp := range cps {
+		msg := newXtSubscriptionMsg(currencyPairToXtPair(cp))
+		if p.subscriptions != nil {
+			p.subscriptions[msg.ID] = msg.Params
+		}
+		subscriptionMsgs = append(subscriptionMsgs, msg)
+	}
+	return subscriptionMsgs
+}
+
+// SubscribeCurrencyPairs sends the new subscription messages to the websocket
+// and adds them to the providers subscribedPairs array
+func (p *XtProvider) SubscribeCurrencyPairs(cps ...types.CurrencyPair) {
+	p.mtx.Lock()
+	defer p.mtx.Unlock()
+
+	newPairs := []types.CurrencyPair{}
+	for _, cp := range cps {
+		if _, ok := p.subscribedPairs[cp.String()]; !ok {
+			newPairs = append(newPairs, cp)
+		}
+	}
+
+	confirmedPairs, err := ConfirmPairAvailability(
+		p,
+		p.endpoints.Name,
+		p.logger,
+		newPairs...,
+	)
+	if err != nil {
+		return
+	}
+
+	newSubscriptionMsgs := p.getSubscriptionMsgs(confirmedPairs...)
+	p.wsc.AddWebsocketConnection(
+		newSubscriptionMsgs,
+		p.messageReceived,
+		defaultPingDuration,
+		websocket.TextMessage,
+	)
+	p.setSubscribedPairs(confirmedPairs...)
+}
+
+func (p *XtProvider) messageReceived(messageType int, _ *WebsocketConnection, bz []byte) {
+	if messageType != websocket.TextMessage {
+		return
+	}
+
+	var (
+		subscriptionResp XtSubscriptionResponse
+		tickerResp       XtTickerResponse
+		tickerErr        error
+		candleResp       XtCandleResponse
+		candleErr        error
+	)
+
+	tickerErr = json.Unmarshal(bz, &tickerResp)
+	if tickerResp.Topic == xtTickerTopic && len(tickerResp.Data.LastPrice) != 0 {
+		p.setTickerPair(tickerResp.Data, tickerResp.Data.Symbol)
+		telemetryWebsocketMessage(ProviderXt, MessageTypeTicker)
+		return
+	}
+
+	candleErr = json.Unmarshal(bz, &candleResp)
+	if candleResp.Topic == xtCandleTopic && len(candleResp.Data.Close) != 0 {
+		p.setCandlePair(candleResp.Data, candleResp.Data.Symbol)
+		telemetryWebsocketMessage(ProviderXt, MessageTypeCandle)
+		return
+	}
+
+	if err := json.Unmarshal(bz, &subscriptionResp); err == nil && subscriptionResp.Code != nil {
+		p.subscriptionReceived(subscriptionResp)
+		return
+	}
+
+	p.logger.Error().
+		Int("length", len(bz)).
+		AnErr("ticker", tickerErr).
+		AnErr("candle", candleErr).
+		Msg("Error on receive message")
+	telemetryDecodeError(p.logger, ProviderXt, bz)
+}
+
+// subscriptionReceived logs the result of a subscription message, matched
+// with its topics by the id of the response.
+func (p *XtProvider) subscriptionReceived(resp XtSubscriptionResponse) {
+	p.subscriptionsMtx.Lock()
+	topics := p.subscriptions[resp.ID]
+	delete(p.subscriptions, resp.ID)
+	p.subscriptionsMtx.Unlock()
+
+	if *resp.Code != 0 {
+		p.logger.Error().
+			Str("id", resp.ID).
+			Strs("topics", topics).
+			Int("code", *resp.Code).
+			Str("msg", resp.Msg).
+			Msg("failed to subscribe")
+		return
+	}
+	p.logger.Debug().Str("id", resp.ID).Strs("topics", topics).Msg("subscribed")
+}
+
+func (ticker XtTicker) toTickerPrice() (types.TickerPrice, error) {
+	return types.NewTickerPrice(ticker.LastPrice.String(), ticker.Volume.String())
+}
+
+// toCandlePrice converts the candle, stamped with its open time, to a candle
+// price stamped with its close time.
+func (candle XtCandle) toCandlePrice() (types.CandlePrice, error) {
+	return types.NewCandlePrice(
+		candle.Close.String(),
+		candle.Volume.String(),
+		candle.TimeStamp+xtCandleLength.Milliseconds(),
+	)
+}
+
+// GetAvailablePairs returns all pairs to which the provider can subscribe.
+// ex.: map["ATOMUSDT" => {}, "OJOUSDC" => {}].
+func (p *XtProvider) GetAvailablePairs() (map[string]struct{}, error) {
+	resp, err := p.endpoints.httpClient().Get(p.endpoints.Rest + xtRestPath)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	var pairsSummary XtPairsSummary
+	if err := json.NewDecoder(resp.Body).Decode(&pairsSummary); err != nil {
+		return nil, err
+	}
+
+	availablePairs := make(map[string]struct{}, len(pairsSummary.Result.Symbols))
+	for _, pair := range pairsSummary.Result.Symbols {
+		availablePairs[strings.ToUpper(strings.ReplaceAll(pair.Symbol, "_", ""))] = struct{}{}
+	}
+
+	return availablePairs, nil
+}
+
+// currencyPairToXtPair receives a currency pair and returns the XT.com symbol
+// ex.: btc_usdt.
+func currencyPairToXtPair(cp types.CurrencyPair) string {
+	return strings.ToLower(cp.Base + "_" + cp.Quote)
+}
+
+// newXtSubscriptionMsg returns a new subscription Msg to the ticker and kline
+// topics of a symbol.
+func newXtSubscriptionMsg(symbol string) XtSubscriptionMsg {
+	return XtSubscriptionMsg{
+		Method: "subscribe",
+		Params: []string{
+			xtTickerTopic + "@" + symbol,
+			xtCandleTopic + "@" + symbol + "," + xtCandleRange,
+		},
+		ID: symbol,
+	}
+}
@@ -0,0 +1,84 @@
+package provider
+
+import (
+	"encoding/json"
+	"strconv"
+	"testing"
+	"time"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/gorilla/websocket"
+	"github.com/ojo-network/price-feeder/oracle/types"
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/require"
+)
+
+func TestXtProvider_messageReceived(t *testing.T) {
+	p := &XtProvider{
+		logger:        zerolog.Nop(),
+		subscriptions: make(map[string][]string),
+		priceStore:    newPriceStore(zerolog.Nop()),
+	}
+	p.setCurrencyPairToTickerAndCandlePair(currencyPairToXtPair)
+	p.setSubscribedPairs(ATOMUSDT)
+	p.getSubscriptionMsgs(ATOMUSDT)
+	require.Len(t, p.subscriptions, 1)
+
+	// subscription responses are matched by id and carry no data
+	p.messageReceived(websocket.TextMessage, nil, []byte(`{"id":"atom_usdt","code":0,"msg":"success"}`))
+	require.Empty(t, p.subscriptions)
+
+	ticker := `{"topic":"ticker","event":"ticker@atom_usdt","data":{"s":"atom_usdt","t":1687944835188,` +
+		`"cv":"0.21","cr":"0.0185","o":"11.31","c":"11.52","h":"11.73","l":"11.21","q":"2396974.02","v":"27369412.11"}}`
+	p.messageReceived(websocket.TextMessage, nil, []byte(ticker))
+
+	prices, err := p.GetTickerPrices(ATOMUSDT)
+	require.NoError(t, err)
+	require.Len(t, prices, 1)
+	require.Equal(t, sdk.MustNewDecFromStr("11.52"), prices[ATOMUSDT].Price)
+	require.Equal(t, sdk.MustNewDecFromStr("2396974.02"), prices[ATOMUSDT].Volume)
+
+	candleTime := PastUnixTime(0)
+	candle := `{"topic":"kline","event":"kline@atom_usdt,1m","data":{"s":"atom_usdt","t":` +
+		strconv.FormatInt(candleTime, 10) + `,"i":"1m","o":"11.52","c":"11.53","h":"11.54","l":"11.51",` +
+		`"q":"182.47","v":"2103.84"}}`
+	p.messageReceived(websocket.TextMessage, nil, []byte(candle))
+
+	candles, err := p.GetCandlePrices(ATOMUSDT)
+	require.NoError(t, err)
+	require.Len(t, candles[ATOMUSDT], 1)
+	require.Equal(t, sdk.MustNewDecFromStr("11.53"), candles[ATOMUSDT][0].Price)
+	require.Equal(t, sdk.MustNewDecFromStr("182.47"), candles[ATOMUSDT][0].Volume)
+	// the candle is stamped with its close time
+	require.Equal(t, candleTime+time.Minute.Milliseconds(), candles[ATOMUSDT][0].TimeStamp)
+
+	// numeric fields may also be sent as numbers
+	ticker = `{"topic":"ticker","event":"ticker@atom_usdt","data":{"s":"atom_usdt","t":1687944835188,` +
+		`"c":11.61,"q":2396974.02}}`
+	p.messageReceived(websocket.TextMessage, nil, []byte(ticker))
+
+	prices, err = p.GetTickerPrices(ATOMUSDT)
+	require.NoError(t, err)
+	require.Equal(t, sdk.MustNewDecFromStr("11.61"), prices[ATOMUSDT].Price)
+	require.Equal(t, sdk.MustNewDecFromStr("2396974.02"), prices[ATOMUSDT].Volume)
+}
+
+func TestXtCurrencyPairToXtPair(t *testing.T) {
+	cp := types.CurrencyPair{Base: "ATOM", Quote: "USDT"}
+	xtSymbol := currencyPairToXtPair(cp)
+	require.Equal(t, xtSymbol, "atom_usdt")
+}
+
+func TestXtProvider_getSubscriptionMsgs(t *testing.T) {
+	provider := &XtProvider{
+		subscriptions: make(map[string][]string),
+	}
+	cps := []types.CurrencyPair{
+		{Base: "ATOM", Quote: "USDT"},
+	}
+	subMsgs := provider.getSubscriptionMsgs(cps...)
+
+	msg, _ := json.Marshal(subMsgs[0])
+	require.Equal(t, `{"method":"subscribe","params":["ticker@atom_usdt","kline@atom_usdt,1m"],"id":"atom_usdt"}`, string(msg))
+	require.Equal(t, []string{"ticker@atom_usdt", "kline@atom_usdt,1m"}, provider.subscriptions["atom_usdt"])
+}
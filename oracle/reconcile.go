@@ -0,0 +1,111 @@
+package oracle
+
+import (
+	"sort"
+	"time"
+
+	"github.com/armon/go-metrics"
+	"github.com/cosmos/cosmos-sdk/telemetry"
+
+	"github.com/ojo-network/price-feeder/oracle/provider"
+	"github.com/ojo-network/price-feeder/oracle/types"
+)
+
+// SetSubscriptionReconcile sets how often the pairs expected from each
+// provider are reconciled with the pairs it recently received market data
+// of. Pairs without market data for maxAge are subscribed to again. A zero
+// interval disables the reconciliation.
+func (o *Oracle) SetSubscriptionReconcile(interval, maxAge time.Duration) {
+	o.reconcileInterval = interval
+	o.reconcileMaxAge = maxAge
+}
+
+// reconcileSubscriptions subscribes again to the pairs of the providers which
+// received no market data of them for the max age, at most once per
+// reconcile interval. It waits for the max age after the start, so that
+// every subscription had the time to deliver data, and a re-subscribed pair
+// is given that long again before it is re-subscribed once more.
+func (o *Oracle) reconcileSubscriptions(now time.Time) {
+	if o.reconcileInterval <= 0 || now.Sub(o.lastReconcile) < o.reconcileInterval {
+		return
+	}
+	o.lastReconcile = now
+
+	if now.Sub(o.startTime) < o.reconcileMaxAge {
+		return
+	}
+	if o.resubscribed == nil {
+		o.resubscribed = make(map[types.ProviderName]map[types.CurrencyPair]time.Time)
+	}
+
+	for providerName, priceProvider := range o.priceProviders {
+		tracker, ok := priceProvider.(provider.SubscriptionTracker)
+		if !ok {
+			continue
+		}
+
+		missing := o.missingSubscriptions(providerName, tracker, now)
+		missing = o.omitReconnectCooldownPairs(providerName, priceProvider, missing)
+		telemetry.SetGaugeWithLabels(
+			[]string{"subscription", "missing_pairs"},
+			float32(len(missing)),
+			[]metrics.Label{{Name: "provider", Value: providerName.String()}},
+		)
+		if len(missing) == 0 {
+			continue
+		}
+
+		if _, ok := o.resubscribed[providerName]; !ok {
+			o.resubscribed[providerName] = make(map[types.CurrencyPair]time.Time)
+		}
+		missingPairs := make([]string, len(missing))
+		for i, cp := range missing {
+			o.resubscribed[providerName][cp] = now
+			missingPairs[i] = cp.String()
+		}
+
+		telemetry.IncrCounterWithLabels(
+			[]string{"subscription", "resubscribe"},
+			float32(len(missing)),
+			[]metrics.Label{{Name: "provider", Value: providerName.String()}},
+		)
+		o.logger.Warn().
+			Str("provider", providerName.String()).
+			Strs("pairs", missingPairs).
+			Dur("max_age", o.reconcileMaxAge).
+			Msg("no market data received for subscribed pairs; subscribing again")
+
+		// subscribing confirms the availability of the pairs, which must not
+		// hold up the tick
+		tracker.UnsubscribeCurrencyPairs(missing...)
+		go priceProvider.SubscribeCurrencyPairs(missing...)
+	}
+}
+
+// missingSubscriptions returns the pairs of the provider which received no
+// market data for the reconcile max age, except those re-subscribed within
+// it, sorted by pair.
+func (o *Oracle) missingSubscriptions(
+	providerName types.ProviderName,
+	tracker provider.SubscriptionTracker,
+	now time.Time,
+) []types.CurrencyPair {
+	pairs := o.subscribedPairs(providerName)
+	lastReceived := tracker.LastReceived(pairs...)
+
+	var missing []types.CurrencyPair
+	for _, cp := range pairs {
+		if received, ok := lastReceived[cp]; ok && now.Sub(received) < o.reconcileMaxAge {
+			continue
+		}
+		if resubscribed, ok := o.resubscribed[providerName][cp]; ok && now.Sub(resubscribed) < o.reconcileMaxAge {
+			continue
+		}
+		missing = append(missing, cp)
+	}
+
+	sort.Slice(missing, func(i, j int) bool {
+		return missing[i].String() < missing[j].String()
+	})
+	return missing
+}
@@ -0,0 +1,119 @@
+package oracle
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ojo-network/price-feeder/oracle/provider"
+	"github.com/ojo-network/price-feeder/oracle/types"
+)
+
+// trackedProvider is a provider tracking the market data it received, whose
+// subscriptions are sent to subscribed.
+type trackedProvider struct {
+	mockProvider
+
+	mtx          sync.Mutex
+	lastReceived map[types.CurrencyPair]time.Time
+	unsubscribed []types.CurrencyPair
+	subscribed   chan []types.CurrencyPair
+}
+
+func (p *trackedProvider) LastReceived(cps ...types.CurrencyPair) map[types.CurrencyPair]time.Time {
+	p.mtx.Lock()
+	defer p.mtx.Unlock()
+
+	lastReceived := make(map[types.CurrencyPair]time.Time, len(cps))
+	for _, cp := range cps {
+		if received, ok := p.lastReceived[cp]; ok {
+			lastReceived[cp] = received
+		}
+	}
+	return lastReceived
+}
+
+func (p *trackedProvider) UnsubscribeCurrencyPairs(cps ...types.CurrencyPair) {
+	p.mtx.Lock()
+	defer p.mtx.Unlock()
+
+	p.unsubscribed = append(p.unsubscribed, cps...)
+}
+
+func (p *trackedProvider) SubscribeCurrencyPairs(cps ...types.CurrencyPair) {
+	p.subscribed <- cps
+}
+
+func TestOracle_reconcileSubscriptions(t *testing.T) {
+	atomUSDT := types.CurrencyPair{Base: "ATOM", Quote: "USDT"}
+	ojoUSDT := types.CurrencyPair{Base: "OJO", Quote: "USDT"}
+
+	start := time.Now()
+	p := &trackedProvider{
+		lastReceived: map[types.CurrencyPair]time.Time{
+			atomUSDT: start,
+			ojoUSDT:  start,
+		},
+		subscribed: make(chan []types.CurrencyPair, 1),
+	}
+	o := &Oracle{
+		logger:    zerolog.Nop(),
+		startTime: start,
+		providerPairs: map[types.ProviderName][]types.CurrencyPair{
+			provider.ProviderBinance: {atomUSDT, ojoUSDT},
+		},
+		priceProviders: map[types.ProviderName]provider.Provider{
+			provider.ProviderBinance: p,
+		},
+	}
+
+	// the reconciliation is opt-in
+	o.reconcileSubscriptions(start.Add(time.Hour))
+	require.Empty(t, p.subscribed)
+
+	o.SetSubscriptionReconcile(time.Minute, 5*time.Minute)
+
+	// every pair gets the max age after the start to deliver data
+	o.lastReconcile = time.Time{}
+	o.reconcileSubscriptions(start.Add(time.Minute))
+	require.Empty(t, p.subscribed)
+
+	// the OJO subscription silently dropped after a reconnect while ATOM
+	// kept delivering
+	p.mtx.Lock()
+	p.lastReceived[atomUSDT] = start.Add(9 * time.Minute)
+	p.mtx.Unlock()
+
+	o.reconcileSubscriptions(start.Add(10 * time.Minute))
+	select {
+	case subscribed := <-p.subscribed:
+		require.Equal(t, []types.CurrencyPair{ojoUSDT}, subscribed)
+	case <-time.After(5 * time.Second):
+		t.Fatal("dropped subscription was not re-subscribed")
+	}
+	p.mtx.Lock()
+	require.Equal(t, []types.CurrencyPair{ojoUSDT}, p.unsubscribed)
+	p.mtx.Unlock()
+
+	// the re-subscribed pair is given the max age to deliver data again
+	p.mtx.Lock()
+	p.lastReceived[atomUSDT] = start.Add(14 * time.Minute)
+	p.mtx.Unlock()
+	o.reconcileSubscriptions(start.Add(12 * time.Minute))
+	require.Empty(t, p.subscribed)
+
+	// and the reconciliation runs at most once per interval
+	o.reconcileSubscriptions(start.Add(12*time.Minute + 30*time.Second))
+	require.Equal(t, start.Add(12*time.Minute), o.lastReconcile)
+
+	o.reconcileSubscriptions(start.Add(16 * time.Minute))
+	select {
+	case subscribed := <-p.subscribed:
+		require.Equal(t, []types.CurrencyPair{ojoUSDT}, subscribed)
+	case <-time.After(5 * time.Second):
+		t.Fatal("still missing subscription was not re-subscribed")
+	}
+}
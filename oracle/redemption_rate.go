@@ -0,0 +1,47 @@
+package oracle
+
+import (
+	"github.com/ojo-network/price-feeder/config"
+	"github.com/ojo-network/price-feeder/oracle/provider"
+	"github.com/ojo-network/price-feeder/oracle/types"
+)
+
+// SetRedemptionRates sets the underlying asset of every liquid staking asset,
+// by the liquid staking asset, whose USD rate is its redemption rate times the
+// USD rate of its underlying asset.
+func (o *Oracle) SetRedemptionRates(underlyings map[string]string) {
+	o.redemptionRates = underlyings
+}
+
+// applyRedemptionRates sets the USD rates of the liquid staking assets to
+// their redemption rate, polled by the redemption-rate provider, times the
+// aggregated USD rate of their underlying asset. The assets whose redemption
+// rate is stale or whose underlying asset has no rate are removed.
+func (o *Oracle) applyRedemptionRates(
+	rates types.CurrencyPairDec,
+	tickers types.AggregatedProviderPrices,
+) {
+	for base, underlying := range o.redemptionRates {
+		usdPair := types.CurrencyPair{Base: base, Quote: config.DenomUSD}
+		underlyingRate, ok := rates[types.CurrencyPair{Base: underlying, Quote: config.DenomUSD}]
+		if !ok {
+			o.logger.Warn().
+				Str("asset", usdPair.String()).
+				Str("underlying", underlying).
+				Msg("underlying asset has no rate; excluding redemption rate priced asset")
+			delete(rates, usdPair)
+			continue
+		}
+
+		ticker, ok := tickers[provider.ProviderRedemptionRate][types.CurrencyPair{Base: base, Quote: underlying}]
+		if !ok {
+			o.logger.Warn().
+				Str("asset", usdPair.String()).
+				Msg("no fresh redemption rate; excluding redemption rate priced asset")
+			delete(rates, usdPair)
+			continue
+		}
+
+		rates[usdPair] = ticker.Price.Mul(underlyingRate)
+	}
+}
@@ -0,0 +1,47 @@
+package oracle
+
+import (
+	"testing"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ojo-network/price-feeder/oracle/provider"
+	"github.com/ojo-network/price-feeder/oracle/types"
+)
+
+func TestOracle_applyRedemptionRates(t *testing.T) {
+	atomUSD := types.CurrencyPair{Base: "ATOM", Quote: "USD"}
+	statomUSD := types.CurrencyPair{Base: "STATOM", Quote: "USD"}
+	statomATOM := types.CurrencyPair{Base: "STATOM", Quote: "ATOM"}
+
+	o := &Oracle{logger: zerolog.Nop()}
+	o.SetRedemptionRates(map[string]string{"STATOM": "ATOM"})
+
+	tickers := types.AggregatedProviderPrices{
+		provider.ProviderRedemptionRate: {
+			statomATOM: {Price: sdk.MustNewDecFromStr("1.25"), Volume: sdk.ZeroDec()},
+		},
+	}
+
+	// the redemption rate times the USD rate of the underlying asset replaces
+	// the market rate of the asset
+	rates := types.CurrencyPairDec{
+		atomUSD:   sdk.MustNewDecFromStr("10"),
+		statomUSD: sdk.MustNewDecFromStr("11"),
+	}
+	o.applyRedemptionRates(rates, tickers)
+	require.Equal(t, sdk.MustNewDecFromStr("12.5"), rates[statomUSD])
+	require.Equal(t, sdk.MustNewDecFromStr("10"), rates[atomUSD])
+
+	// the asset is excluded once the underlying asset has no rate
+	rates = types.CurrencyPairDec{statomUSD: sdk.MustNewDecFromStr("11")}
+	o.applyRedemptionRates(rates, tickers)
+	require.Empty(t, rates)
+
+	// or once its redemption rate is stale
+	rates = types.CurrencyPairDec{atomUSD: sdk.MustNewDecFromStr("10")}
+	o.applyRedemptionRates(rates, types.AggregatedProviderPrices{})
+	require.Equal(t, types.CurrencyPairDec{atomUSD: sdk.MustNewDecFromStr("10")}, rates)
+}
@@ -0,0 +1,58 @@
+package oracle
+
+import (
+	"github.com/armon/go-metrics"
+	"github.com/cosmos/cosmos-sdk/telemetry"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+
+	"github.com/ojo-network/price-feeder/oracle/provider"
+	"github.com/ojo-network/price-feeder/oracle/types"
+)
+
+// SetMaxSpreads sets the spread between the best bid and ask of the pairs,
+// as a fraction of their mid price, above which a provider's price of the
+// pair is excluded.
+func (o *Oracle) SetMaxSpreads(maxSpreads map[types.CurrencyPair]sdk.Dec) {
+	o.maxSpreads = maxSpreads
+}
+
+// spreadExclusions returns the provider pairs whose best bid/ask spread is
+// above the max spread of the pair, whose candles and tickers are omitted.
+// The pairs without a best quote are left untouched.
+func (o *Oracle) spreadExclusions(
+	quotes map[types.ProviderName]map[types.CurrencyPair]provider.BestQuote,
+) map[types.ProviderName]map[types.CurrencyPair]bool {
+	exclusions := make(map[types.ProviderName]map[types.CurrencyPair]bool)
+	for providerName, cpQuotes := range quotes {
+		for cp, quote := range cpQuotes {
+			labels := []metrics.Label{
+				{Name: "provider", Value: providerName.String()},
+				{Name: "pair", Value: cp.String()},
+			}
+			spread := quote.Spread()
+			telemetry.SetGaugeWithLabels(
+				[]string{"provider", "spread"},
+				float32(spread.MustFloat64()),
+				labels,
+			)
+
+			maxSpread, ok := o.maxSpreads[cp]
+			if !ok || spread.LTE(maxSpread) {
+				continue
+			}
+			if _, ok := exclusions[providerName]; !ok {
+				exclusions[providerName] = make(map[types.CurrencyPair]bool)
+			}
+			exclusions[providerName][cp] = true
+
+			telemetry.IncrCounterWithLabels([]string{"provider", "spread", "excluded"}, 1, labels)
+			o.logger.Warn().
+				Str("provider", providerName.String()).
+				Str("pair", cp.String()).
+				Str("spread", spread.String()).
+				Str("max_spread", maxSpread.String()).
+				Msg("excluding provider price for its bid/ask spread")
+		}
+	}
+	return exclusions
+}
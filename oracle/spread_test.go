@@ -0,0 +1,99 @@
+package oracle
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ojo-network/price-feeder/oracle/client"
+	"github.com/ojo-network/price-feeder/oracle/provider"
+	"github.com/ojo-network/price-feeder/oracle/types"
+)
+
+// quoteProvider defines a provider streaming the best quote of its pairs.
+type quoteProvider struct {
+	laggingProvider
+	quotes map[types.CurrencyPair]provider.BestQuote
+}
+
+func (m quoteProvider) GetBestQuotes(_ ...types.CurrencyPair) map[types.CurrencyPair]provider.BestQuote {
+	return m.quotes
+}
+
+func TestOracle_spreadExclusions(t *testing.T) {
+	quote := func(bid, ask string) provider.BestQuote {
+		return provider.BestQuote{Bid: sdk.MustNewDecFromStr(bid), Ask: sdk.MustNewDecFromStr(ask)}
+	}
+
+	o := &Oracle{logger: zerolog.Nop()}
+	quotes := map[types.ProviderName]map[types.CurrencyPair]provider.BestQuote{
+		provider.ProviderBinance: {
+			ATOMUSD: quote("9.86", "9.88"),
+			XBTUSD:  quote("11000", "12000"),
+		},
+		provider.ProviderOkx: {ATOMUSD: quote("9.5", "10.5")},
+	}
+
+	// nothing is excluded without a max spread
+	require.Empty(t, o.spreadExclusions(quotes))
+
+	o.SetMaxSpreads(map[types.CurrencyPair]sdk.Dec{ATOMUSD: sdk.MustNewDecFromStr("0.01")})
+	require.Equal(t, map[types.ProviderName]map[types.CurrencyPair]bool{
+		provider.ProviderOkx: {ATOMUSD: true},
+	}, o.spreadExclusions(quotes))
+}
+
+func TestOracle_SetPricesMaxSpread(t *testing.T) {
+	o := New(
+		zerolog.Nop(),
+		client.OracleClient{},
+		map[types.ProviderName][]types.CurrencyPair{
+			provider.ProviderBinance: {ATOMUSD},
+			provider.ProviderOkx:     {ATOMUSD},
+		},
+		time.Millisecond*100,
+		make(map[string]sdk.Dec),
+		make(map[types.ProviderName]provider.Endpoint),
+	)
+
+	lag := 10 * time.Second
+	binance := quoteProvider{
+		laggingProvider: laggingProvider{
+			mockProvider: mockProvider{prices: types.CurrencyPairTickers{
+				ATOMUSD: {Price: sdk.MustNewDecFromStr("10"), Volume: sdk.MustNewDecFromStr("1000")},
+			}},
+			lag: &lag,
+		},
+		quotes: map[types.CurrencyPair]provider.BestQuote{
+			ATOMUSD: {Bid: sdk.MustNewDecFromStr("9.99"), Ask: sdk.MustNewDecFromStr("10.01")},
+		},
+	}
+	okx := quoteProvider{
+		laggingProvider: laggingProvider{
+			mockProvider: mockProvider{prices: types.CurrencyPairTickers{
+				ATOMUSD: {Price: sdk.MustNewDecFromStr("10.2"), Volume: sdk.MustNewDecFromStr("1000")},
+			}},
+			lag: &lag,
+		},
+		quotes: map[types.CurrencyPair]provider.BestQuote{
+			ATOMUSD: {Bid: sdk.MustNewDecFromStr("9.7"), Ask: sdk.MustNewDecFromStr("10.7")},
+		},
+	}
+	o.priceProviders = map[types.ProviderName]provider.Provider{
+		provider.ProviderBinance: binance,
+		provider.ProviderOkx:     okx,
+	}
+
+	// the spreads are ignored without a max spread
+	require.NoError(t, o.SetPrices(context.Background()))
+	require.Equal(t, sdk.MustNewDecFromStr("10.1"), o.GetPrices()[ATOMUSD])
+
+	// okx is excluded for its wide spread
+	o.SetMaxSpreads(map[types.CurrencyPair]sdk.Dec{ATOMUSD: sdk.MustNewDecFromStr("0.05")})
+	require.NoError(t, o.SetPrices(context.Background()))
+	require.Equal(t, sdk.MustNewDecFromStr("10"), o.GetPrices()[ATOMUSD])
+}
@@ -0,0 +1,63 @@
+package oracle
+
+import (
+	"time"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+
+	"github.com/ojo-network/price-feeder/oracle/types"
+)
+
+// lastGoodPrice is the last aggregated price of a pair and when it was
+// aggregated.
+type lastGoodPrice struct {
+	price     sdk.Dec
+	timestamp time.Time
+}
+
+// recordLastGoodPrices records the aggregated prices as the last good price of
+// their pair. The caller must hold the prices lock.
+func (o *Oracle) recordLastGoodPrices(now time.Time, prices types.CurrencyPairDec) {
+	if o.lastGoodPrices == nil {
+		o.lastGoodPrices = make(map[types.CurrencyPair]lastGoodPrice, len(prices))
+	}
+	for cp, price := range prices {
+		o.lastGoodPrices[cp] = lastGoodPrice{price: price, timestamp: now}
+	}
+}
+
+// GetStalePrices returns the last good price of the pairs missing from the
+// current prices, if it was aggregated at most maxAge ago or if maxAge is zero.
+// They are only served by the API in safe mode and never voted.
+func (o *Oracle) GetStalePrices(maxAge time.Duration) map[types.CurrencyPair]types.StalePrice {
+	o.pricesMutex.RLock()
+	defer o.pricesMutex.RUnlock()
+
+	return stalePrices(o.now(), o.prices, o.lastGoodPrices, maxAge)
+}
+
+// stalePrices returns the last good prices of the pairs without a current
+// price, dropping those older than maxAge unless it is zero.
+func stalePrices(
+	now time.Time,
+	prices types.CurrencyPairDec,
+	lastGoodPrices map[types.CurrencyPair]lastGoodPrice,
+	maxAge time.Duration,
+) map[types.CurrencyPair]types.StalePrice {
+	stale := make(map[types.CurrencyPair]types.StalePrice)
+	for cp, lastGood := range lastGoodPrices {
+		if _, ok := prices[cp]; ok {
+			continue
+		}
+		age := now.Sub(lastGood.timestamp)
+		if maxAge > 0 && age > maxAge {
+			continue
+		}
+		stale[cp] = types.StalePrice{
+			Price:      lastGood.price,
+			Stale:      true,
+			AgeSeconds: int64(age.Seconds()),
+		}
+	}
+	return stale
+}
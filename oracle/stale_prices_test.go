@@ -0,0 +1,57 @@
+package oracle
+
+import (
+	"testing"
+	"time"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ojo-network/price-feeder/oracle/types"
+)
+
+func TestStalePrices(t *testing.T) {
+	atom := types.CurrencyPair{Base: "ATOM", Quote: "USD"}
+	ojo := types.CurrencyPair{Base: "OJO", Quote: "USD"}
+	umee := types.CurrencyPair{Base: "UMEE", Quote: "USD"}
+	now := time.Unix(1700000000, 0)
+
+	lastGoodPrices := map[types.CurrencyPair]lastGoodPrice{
+		atom: {price: sdk.MustNewDecFromStr("10"), timestamp: now},
+		ojo:  {price: sdk.MustNewDecFromStr("0.05"), timestamp: now.Add(-90 * time.Second)},
+		umee: {price: sdk.MustNewDecFromStr("0.01"), timestamp: now.Add(-2 * time.Hour)},
+	}
+	prices := types.CurrencyPairDec{atom: sdk.MustNewDecFromStr("10")}
+
+	// pairs with a fresh price are not stale
+	require.Equal(t, map[types.CurrencyPair]types.StalePrice{
+		ojo:  {Price: sdk.MustNewDecFromStr("0.05"), Stale: true, AgeSeconds: 90},
+		umee: {Price: sdk.MustNewDecFromStr("0.01"), Stale: true, AgeSeconds: 7200},
+	}, stalePrices(now, prices, lastGoodPrices, 0))
+
+	// prices older than the max age are dropped
+	require.Equal(t, map[types.CurrencyPair]types.StalePrice{
+		ojo: {Price: sdk.MustNewDecFromStr("0.05"), Stale: true, AgeSeconds: 90},
+	}, stalePrices(now, prices, lastGoodPrices, time.Hour))
+}
+
+func TestOracle_GetStalePrices(t *testing.T) {
+	atom := types.CurrencyPair{Base: "ATOM", Quote: "USD"}
+	ojo := types.CurrencyPair{Base: "OJO", Quote: "USD"}
+
+	o := &Oracle{}
+	o.recordLastGoodPrices(time.Now(), types.CurrencyPairDec{
+		atom: sdk.MustNewDecFromStr("10"),
+		ojo:  sdk.MustNewDecFromStr("0.05"),
+	})
+	o.prices = types.CurrencyPairDec{atom: sdk.MustNewDecFromStr("11")}
+	o.recordLastGoodPrices(time.Now(), o.prices)
+
+	// the stale prices are served by the API only, the voted prices are
+	// unchanged
+	stale := o.GetStalePrices(0)
+	require.Len(t, stale, 1)
+	require.Equal(t, sdk.MustNewDecFromStr("0.05"), stale[ojo].Price)
+	require.True(t, stale[ojo].Stale)
+	require.Equal(t, types.CurrencyPairDec{atom: sdk.MustNewDecFromStr("11")}, o.GetPrices())
+}
@@ -0,0 +1,45 @@
+package oracle
+
+import (
+	"context"
+
+	"github.com/ojo-network/price-feeder/config"
+	"github.com/ojo-network/price-feeder/oracle/client"
+)
+
+// Startup connects the chain client created by newClient and dials the
+// providers in the given startup order. In the chain-first order, an error
+// of the chain client, ex. a broken RPC, keyring or account, aborts the
+// startup before any provider is dialed.
+func (o *Oracle) Startup(
+	ctx context.Context,
+	order string,
+	newClient func(context.Context) (client.OracleClient, error),
+) error {
+	if order == config.StartupOrderProvidersFirst {
+		o.ConnectProviders(ctx)
+	}
+
+	oracleClient, err := newClient(ctx)
+	if err != nil {
+		return err
+	}
+	o.oracleClient = oracleClient
+	o.logger.Info().Str("startup_order", order).Msg("connected chain client")
+
+	if order != config.StartupOrderProvidersFirst {
+		o.ConnectProviders(ctx)
+	}
+	return nil
+}
+
+// ConnectProviders dials the providers of the oracle ahead of its first tick.
+// A provider which fails to initialize is logged and dialed again on the next
+// tick.
+func (o *Oracle) ConnectProviders(ctx context.Context) {
+	for providerName := range o.providerPairs {
+		if _, err := o.getOrSetProvider(ctx, providerName); err != nil {
+			o.logger.Error().Err(err).Msgf("failed to initialize %s provider", providerName)
+		}
+	}
+}
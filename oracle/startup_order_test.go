@@ -0,0 +1,53 @@
+package oracle
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ojo-network/price-feeder/config"
+	"github.com/ojo-network/price-feeder/oracle/client"
+	"github.com/ojo-network/price-feeder/oracle/provider"
+	"github.com/ojo-network/price-feeder/oracle/types"
+)
+
+func TestOracle_Startup(t *testing.T) {
+	o := New(
+		zerolog.Nop(),
+		client.OracleClient{},
+		map[types.ProviderName][]types.CurrencyPair{
+			provider.ProviderBinance: {OJOUSDT},
+			provider.ProviderKraken:  {XBTUSD},
+		},
+		time.Millisecond*100,
+		make(map[string]sdk.Dec),
+		make(map[types.ProviderName]provider.Endpoint),
+	)
+
+	feeder := sdk.AccAddress(make([]byte, 20)).String()
+	err := o.Startup(context.Background(), config.StartupOrderChainFirst, func(ctx context.Context) (client.OracleClient, error) {
+		return client.NewOracleClient(
+			ctx,
+			zerolog.Nop(),
+			"ojo-testnet",
+			"unknown-backend",
+			t.TempDir(),
+			"",
+			"tcp://localhost:26657",
+			time.Second,
+			feeder,
+			"",
+			"localhost:9090",
+			1,
+			0,
+		)
+	})
+	require.ErrorContains(t, err, "unknown-backend")
+
+	// the keyring failure aborts the startup before the providers are dialed
+	require.Empty(t, o.priceProviders)
+}
@@ -0,0 +1,83 @@
+package oracle
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/ojo-network/price-feeder/config"
+)
+
+// ErrStartupProviderMins defines a sentinel error for assets still priced by
+// fewer than their minimum providers once the startup timeout elapsed under
+// the fail startup policy. It stops the oracle.
+var ErrStartupProviderMins = errors.New("assets below their minimum providers after startup")
+
+// SetStartupPolicy sets how the oracle handles assets priced by fewer than
+// their minimum providers after the start. The policy is resolved once every
+// asset reaches its minimum or the timeout elapses. Assets without a minimum
+// are not checked.
+func (o *Oracle) SetStartupPolicy(policy string, timeout time.Duration, providerMins map[string]int) {
+	o.startupPolicy = policy
+	o.startupTimeout = timeout
+	o.providerMins = providerMins
+}
+
+// checkStartupPolicy returns true while votes are held back by the startup
+// policy. It returns ErrStartupProviderMins if the fail policy times out.
+func (o *Oracle) checkStartupPolicy() (bool, error) {
+	if o.startupDone || len(o.providerMins) == 0 {
+		return false, nil
+	}
+
+	missing := o.assetsBelowProviderMins()
+	if len(missing) == 0 {
+		o.logger.Info().Msg("every asset reached its minimum providers")
+		o.startupDone = true
+		return false, nil
+	}
+
+	timedOut := o.now().Sub(o.startTime) >= o.startupTimeout
+	switch o.startupPolicy {
+	case config.StartupPolicyFail:
+		if timedOut {
+			return false, fmt.Errorf("%w: %s", ErrStartupProviderMins, strings.Join(missing, ", "))
+		}
+		return true, nil
+
+	case config.StartupPolicyWait:
+		if !timedOut {
+			return true, nil
+		}
+
+	default:
+		if !timedOut {
+			return false, nil
+		}
+	}
+
+	o.logger.Warn().
+		Strs("assets", missing).
+		Dur("startup_timeout", o.startupTimeout).
+		Msg("assets below their minimum providers after startup")
+	o.startupDone = true
+	return false, nil
+}
+
+// assetsBelowProviderMins returns the sorted assets priced by fewer than
+// their minimum providers in the last tick.
+func (o *Oracle) assetsBelowProviderMins() []string {
+	o.pricesMutex.RLock()
+	defer o.pricesMutex.RUnlock()
+
+	missing := []string{}
+	for base, minProviders := range o.providerMins {
+		if o.baseProviders[base] < minProviders {
+			missing = append(missing, base)
+		}
+	}
+	sort.Strings(missing)
+	return missing
+}
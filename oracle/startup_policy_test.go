@@ -0,0 +1,97 @@
+package oracle
+
+import (
+	"testing"
+	"time"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ojo-network/price-feeder/config"
+	"github.com/ojo-network/price-feeder/oracle/client"
+	"github.com/ojo-network/price-feeder/oracle/provider"
+	"github.com/ojo-network/price-feeder/oracle/types"
+)
+
+func TestOracle_checkStartupPolicy(t *testing.T) {
+	newOracle := func(policy string) *Oracle {
+		o := New(
+			zerolog.Nop(),
+			client.OracleClient{},
+			map[types.ProviderName][]types.CurrencyPair{
+				provider.ProviderBinance: {OJOUSDT, XBTUSDT},
+				provider.ProviderKraken:  {XBTUSD},
+			},
+			time.Millisecond*100,
+			make(map[string]sdk.Dec),
+			make(map[types.ProviderName]provider.Endpoint),
+		)
+		o.SetStartupPolicy(policy, time.Minute, map[string]int{"OJO": 1, "XBT": 2})
+		o.startTime = time.Now()
+		o.baseProviders = map[string]int{"OJO": 1, "XBT": 1}
+		return o
+	}
+
+	t.Run("warn", func(t *testing.T) {
+		o := newOracle(config.StartupPolicyWarn)
+		hold, err := o.checkStartupPolicy()
+		require.NoError(t, err)
+		require.False(t, hold)
+
+		o.startTime = time.Now().Add(-time.Minute)
+		hold, err = o.checkStartupPolicy()
+		require.NoError(t, err)
+		require.False(t, hold)
+		require.True(t, o.startupDone)
+	})
+
+	t.Run("fail", func(t *testing.T) {
+		o := newOracle(config.StartupPolicyFail)
+		hold, err := o.checkStartupPolicy()
+		require.NoError(t, err)
+		require.True(t, hold)
+
+		o.startTime = time.Now().Add(-time.Minute)
+		_, err = o.checkStartupPolicy()
+		require.ErrorIs(t, err, ErrStartupProviderMins)
+		require.ErrorContains(t, err, "XBT")
+	})
+
+	t.Run("fail resolved before the timeout", func(t *testing.T) {
+		o := newOracle(config.StartupPolicyFail)
+		o.baseProviders = map[string]int{"OJO": 1, "XBT": 2}
+		hold, err := o.checkStartupPolicy()
+		require.NoError(t, err)
+		require.False(t, hold)
+
+		// the policy isn't checked again once every minimum was reached
+		o.baseProviders = map[string]int{}
+		o.startTime = time.Now().Add(-time.Minute)
+		hold, err = o.checkStartupPolicy()
+		require.NoError(t, err)
+		require.False(t, hold)
+	})
+
+	t.Run("wait-with-timeout", func(t *testing.T) {
+		o := newOracle(config.StartupPolicyWait)
+		hold, err := o.checkStartupPolicy()
+		require.NoError(t, err)
+		require.True(t, hold)
+
+		o.startTime = time.Now().Add(-time.Minute)
+		hold, err = o.checkStartupPolicy()
+		require.NoError(t, err)
+		require.False(t, hold)
+		require.True(t, o.startupDone)
+	})
+
+	t.Run("no provider minimums", func(t *testing.T) {
+		o := newOracle(config.StartupPolicyFail)
+		o.SetStartupPolicy(config.StartupPolicyFail, time.Minute, nil)
+		o.startTime = time.Now().Add(-time.Minute)
+		hold, err := o.checkStartupPolicy()
+		require.NoError(t, err)
+		require.False(t, hold)
+	})
+}
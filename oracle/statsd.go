@@ -0,0 +1,111 @@
+package oracle
+
+import (
+	"context"
+	"time"
+
+	"github.com/armon/go-metrics"
+
+	"github.com/ojo-network/price-feeder/oracle/provider"
+	"github.com/ojo-network/price-feeder/oracle/types"
+)
+
+// StatsdExporter exports the aggregated prices and the provider health of the
+// oracle to a StatsD server, such as the Datadog agent, every flush interval.
+// It exports the values the oracle already computed, independently of the
+// telemetry sinks, so it works alongside or instead of Prometheus. Labels are
+// appended to the metric names, ex. price_feeder.price.ATOM.USD.
+type StatsdExporter struct {
+	sink     metrics.MetricSink
+	prefix   string
+	interval time.Duration
+}
+
+// NewStatsdExporter returns a StatsdExporter sending its metrics over UDP to
+// the StatsD server at address, with their names prefixed by prefix.
+func NewStatsdExporter(address, prefix string, interval time.Duration) (*StatsdExporter, error) {
+	sink, err := metrics.NewStatsdSink(address)
+	if err != nil {
+		return nil, err
+	}
+	return &StatsdExporter{
+		sink:     sink,
+		prefix:   prefix,
+		interval: interval,
+	}, nil
+}
+
+// SetStatsdExporter sets the exporter the aggregated prices and provider
+// health are exported to. A nil exporter disables the export.
+func (o *Oracle) SetStatsdExporter(exporter *StatsdExporter) {
+	o.statsdExporter = exporter
+}
+
+// exportStatsd exports the prices and provider health every flush interval of
+// the exporter until the context is canceled.
+func (o *Oracle) exportStatsd(ctx context.Context) {
+	ticker := time.NewTicker(o.statsdExporter.interval)
+	defer ticker.Stop()
+
+	lastMessages := messageCounts(provider.ProviderHealths())
+	lastTime := o.now()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			now := o.now()
+			healths := provider.ProviderHealths()
+			o.pricesMutex.RLock()
+			freshPairs := o.providerFreshPairs
+			o.pricesMutex.RUnlock()
+
+			o.statsdExporter.export(
+				o.GetPrices(),
+				o.healthSummaries(healths, freshPairs, lastMessages, now.Sub(lastTime)),
+			)
+			lastMessages = messageCounts(healths)
+			lastTime = now
+		}
+	}
+}
+
+// export sends the aggregated price of every pair and the health summary of
+// every provider.
+func (e *StatsdExporter) export(
+	prices types.CurrencyPairDec,
+	summaries map[types.ProviderName]providerHealthSummary,
+) {
+	for cp, price := range prices {
+		value, err := price.Float64()
+		if err != nil {
+			continue
+		}
+		e.sink.SetGaugeWithLabels(
+			[]string{e.prefix, "price"},
+			float32(value),
+			[]metrics.Label{{Name: "base", Value: cp.Base}, {Name: "quote", Value: cp.Quote}},
+		)
+	}
+
+	for providerName, summary := range summaries {
+		labels := []metrics.Label{{Name: "provider", Value: providerName.String()}}
+
+		var up float32
+		if summary.State != healthStateUnavailable && summary.State != provider.HealthStateDisconnected {
+			up = 1
+		}
+		e.sink.SetGaugeWithLabels([]string{e.prefix, "provider", "up"}, up, labels)
+		e.sink.SetGaugeWithLabels(
+			[]string{e.prefix, "provider", "messages_per_sec"},
+			float32(summary.MessagesPerSec),
+			labels,
+		)
+		e.sink.SetGaugeWithLabels(
+			[]string{e.prefix, "provider", "fresh_pairs"},
+			float32(summary.FreshPairs),
+			labels,
+		)
+		e.sink.SetGaugeWithLabels([]string{e.prefix, "provider", "pairs"}, float32(summary.Pairs), labels)
+	}
+}
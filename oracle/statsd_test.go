@@ -0,0 +1,57 @@
+package oracle
+
+import (
+	"net"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ojo-network/price-feeder/oracle/provider"
+	"github.com/ojo-network/price-feeder/oracle/types"
+)
+
+func TestStatsdExporter_export(t *testing.T) {
+	conn, err := net.ListenPacket("udp", "127.0.0.1:0")
+	require.NoError(t, err)
+	defer conn.Close()
+
+	exporter, err := NewStatsdExporter(conn.LocalAddr().String(), "price_feeder", time.Second)
+	require.NoError(t, err)
+
+	exporter.export(
+		types.CurrencyPairDec{
+			{Base: "ATOM", Quote: "USD"}: sdk.MustNewDecFromStr("10.5"),
+		},
+		map[types.ProviderName]providerHealthSummary{
+			provider.ProviderBinance: {State: provider.HealthStateConnected, MessagesPerSec: 2.5, FreshPairs: 1, Pairs: 2},
+			provider.ProviderKraken:  {State: healthStateUnavailable, Pairs: 1},
+		},
+	)
+
+	// the sink flushes its metrics in the background
+	var lines []string
+	buf := make([]byte, 1500)
+	require.NoError(t, conn.SetReadDeadline(time.Now().Add(5*time.Second)))
+	for len(lines) < 9 {
+		n, _, err := conn.ReadFrom(buf)
+		require.NoError(t, err)
+		lines = append(lines, strings.Split(strings.TrimSpace(string(buf[:n])), "\n")...)
+	}
+	sort.Strings(lines)
+
+	require.Equal(t, []string{
+		"price_feeder.price.ATOM.USD:10.500000|g",
+		"price_feeder.provider.fresh_pairs.binance:1.000000|g",
+		"price_feeder.provider.fresh_pairs.kraken:0.000000|g",
+		"price_feeder.provider.messages_per_sec.binance:2.500000|g",
+		"price_feeder.provider.messages_per_sec.kraken:0.000000|g",
+		"price_feeder.provider.pairs.binance:2.000000|g",
+		"price_feeder.provider.pairs.kraken:1.000000|g",
+		"price_feeder.provider.up.binance:1.000000|g",
+		"price_feeder.provider.up.kraken:0.000000|g",
+	}, lines)
+}
@@ -0,0 +1,61 @@
+package oracle
+
+import (
+	sdk "github.com/cosmos/cosmos-sdk/types"
+
+	"github.com/ojo-network/price-feeder/oracle/types"
+)
+
+// SetTickerWindows sets the number of recent ticker samples of the providers
+// whose median is used as their ticker price, smoothing out single bad ticks
+// of providers without candles.
+func (o *Oracle) SetTickerWindows(tickerWindows types.TickerWindows) {
+	o.tickerWindows = tickerWindows
+}
+
+// windowTickers records the ticker prices of the providers with a ticker
+// window and returns the tickers with the price of each replaced by the median
+// of its window. A pair missing from a tick keeps its previous samples. The
+// given tickers are left untouched.
+func (o *Oracle) windowTickers(prices types.AggregatedProviderPrices) types.AggregatedProviderPrices {
+	if len(o.tickerWindows) == 0 {
+		return prices
+	}
+	if o.tickerSamples == nil {
+		o.tickerSamples = make(map[types.ProviderName]map[types.CurrencyPair][]sdk.Dec)
+	}
+
+	windowedPrices := make(types.AggregatedProviderPrices, len(prices))
+	for providerName, tickers := range prices {
+		windows, ok := o.tickerWindows[providerName]
+		if !ok {
+			windowedPrices[providerName] = tickers
+			continue
+		}
+		if _, ok := o.tickerSamples[providerName]; !ok {
+			o.tickerSamples[providerName] = make(map[types.CurrencyPair][]sdk.Dec)
+		}
+
+		windowedPrices[providerName] = make(types.CurrencyPairTickers, len(tickers))
+		for cp, ticker := range tickers {
+			size, ok := windows[types.CurrencyPair{Base: cp.Base, Quote: cp.Quote}]
+			if ok {
+				samples := addTickerSample(o.tickerSamples[providerName][cp], ticker.Price, size)
+				o.tickerSamples[providerName][cp] = samples
+				ticker.Price = median(samples)
+			}
+			windowedPrices[providerName][cp] = ticker
+		}
+	}
+	return windowedPrices
+}
+
+// addTickerSample appends the price to the samples and drops the oldest
+// samples past the window size.
+func addTickerSample(samples []sdk.Dec, price sdk.Dec, size int) []sdk.Dec {
+	samples = append(samples, price)
+	if len(samples) > size {
+		samples = append([]sdk.Dec(nil), samples[len(samples)-size:]...)
+	}
+	return samples
+}
@@ -0,0 +1,60 @@
+package oracle
+
+import (
+	"testing"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ojo-network/price-feeder/oracle/provider"
+	"github.com/ojo-network/price-feeder/oracle/types"
+)
+
+func TestAddTickerSample(t *testing.T) {
+	var samples []sdk.Dec
+	for i := int64(1); i <= 4; i++ {
+		samples = addTickerSample(samples, sdk.NewDec(i), 3)
+	}
+	require.Equal(t, []sdk.Dec{sdk.NewDec(2), sdk.NewDec(3), sdk.NewDec(4)}, samples)
+}
+
+func TestOracle_windowTickers(t *testing.T) {
+	o := &Oracle{}
+	o.SetTickerWindows(types.TickerWindows{
+		provider.ProviderCoinbase: {ATOMUSD: 5},
+	})
+
+	tickers := func(coinbasePrice, krakenPrice string) types.AggregatedProviderPrices {
+		return types.AggregatedProviderPrices{
+			provider.ProviderCoinbase: {
+				ATOMUSD: {Price: sdk.MustNewDecFromStr(coinbasePrice), Volume: sdk.NewDec(100)},
+			},
+			provider.ProviderKraken: {
+				ATOMUSD: {Price: sdk.MustNewDecFromStr(krakenPrice), Volume: sdk.NewDec(100)},
+			},
+		}
+	}
+
+	for _, price := range []string{"10", "10.1", "9.9", "10"} {
+		windowed := o.windowTickers(tickers(price, price))
+		require.Equal(t, sdk.MustNewDecFromStr(price), windowed[provider.ProviderKraken][ATOMUSD].Price)
+	}
+
+	// a single spike barely moves the windowed price, while providers without
+	// a window pass it through
+	spike := tickers("50", "50")
+	windowed := o.windowTickers(spike)
+	require.Equal(t, sdk.MustNewDecFromStr("10"), windowed[provider.ProviderCoinbase][ATOMUSD].Price)
+	require.Equal(t, sdk.NewDec(100), windowed[provider.ProviderCoinbase][ATOMUSD].Volume)
+	require.Equal(t, sdk.MustNewDecFromStr("50"), windowed[provider.ProviderKraken][ATOMUSD].Price)
+
+	// the given tickers are left untouched
+	require.Equal(t, sdk.MustNewDecFromStr("50"), spike[provider.ProviderCoinbase][ATOMUSD].Price)
+
+	// while a sustained move is followed within half the window
+	for _, price := range []string{"10.2", "10.2", "10.2"} {
+		windowed = o.windowTickers(tickers(price, price))
+	}
+	require.Equal(t, sdk.MustNewDecFromStr("10.2"), windowed[provider.ProviderCoinbase][ATOMUSD].Price)
+	require.Len(t, o.tickerSamples[provider.ProviderCoinbase][ATOMUSD], 5)
+}
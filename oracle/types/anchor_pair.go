@@ -0,0 +1,35 @@
+package types
+
+import (
+	sdk "github.com/cosmos/cosmos-sdk/types"
+)
+
+// AnchorPair defines the stricter aggregation of a stablecoin's USD rate, which
+// anchors the conversion of every pair quoted in the stablecoin. Its rate is
+// only reported if at least MinProviders providers price it within Deviation
+// standard deviations, the default deviation threshold if zero. A positive
+// Band clamps the rate to [1 - Band, 1 + Band].
+type AnchorPair struct {
+	Pair         CurrencyPair
+	MinProviders int
+	Deviation    sdk.Dec
+	Band         sdk.Dec
+}
+
+// Clamp returns the rate clamped to the band of the anchor pair and whether it
+// was outside of the band.
+func (a AnchorPair) Clamp(rate sdk.Dec) (sdk.Dec, bool) {
+	if a.Band.IsNil() || !a.Band.IsPositive() {
+		return rate, false
+	}
+
+	lower, upper := sdk.OneDec().Sub(a.Band), sdk.OneDec().Add(a.Band)
+	switch {
+	case rate.LT(lower):
+		return lower, true
+	case rate.GT(upper):
+		return upper, true
+	default:
+		return rate, false
+	}
+}
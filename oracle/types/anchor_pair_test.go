@@ -0,0 +1,29 @@
+package types
+
+import (
+	"testing"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAnchorPair_Clamp(t *testing.T) {
+	anchorPair := AnchorPair{Band: sdk.MustNewDecFromStr("0.02")}
+
+	rate, clamped := anchorPair.Clamp(sdk.MustNewDecFromStr("1.01"))
+	require.False(t, clamped)
+	require.Equal(t, sdk.MustNewDecFromStr("1.01"), rate)
+
+	rate, clamped = anchorPair.Clamp(sdk.MustNewDecFromStr("0.9"))
+	require.True(t, clamped)
+	require.Equal(t, sdk.MustNewDecFromStr("0.98"), rate)
+
+	rate, clamped = anchorPair.Clamp(sdk.MustNewDecFromStr("1.5"))
+	require.True(t, clamped)
+	require.Equal(t, sdk.MustNewDecFromStr("1.02"), rate)
+
+	// no band, no clamping
+	rate, clamped = AnchorPair{}.Clamp(sdk.MustNewDecFromStr("0.5"))
+	require.False(t, clamped)
+	require.Equal(t, sdk.MustNewDecFromStr("0.5"), rate)
+}
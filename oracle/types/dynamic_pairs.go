@@ -0,0 +1,22 @@
+package types
+
+import "time"
+
+// DynamicPairs defines the currency pairs derived from the denoms accepted by
+// the x/oracle module. Each accepted denom without a configured currency pair
+// is priced by Providers against its quote in Quotes, by base, or else Quote.
+// The pairs are derived again every RefreshInterval.
+type DynamicPairs struct {
+	Providers       []ProviderName
+	Quote           string
+	Quotes          map[string]string
+	RefreshInterval time.Duration
+}
+
+// QuoteOf returns the quote the base is priced against.
+func (dp DynamicPairs) QuoteOf(base string) string {
+	if quote, ok := dp.Quotes[base]; ok {
+		return quote
+	}
+	return dp.Quote
+}
@@ -19,4 +19,7 @@ var (
 	ErrWebsocketClose = errors.Register(ModuleName, 9, "error closing %s websocket: %w")
 	ErrWebsocketSend  = errors.Register(ModuleName, 10, "error sending to %s websocket: %w")
 	ErrWebsocketRead  = errors.Register(ModuleName, 11, "error reading from %s websocket: %w")
+
+	ErrInvalidNumber = errors.Register(ModuleName, 12, "invalid numeric value")
+	ErrUnknownPair   = errors.Register(ModuleName, 13, "unknown pair")
 )
@@ -0,0 +1,14 @@
+package types
+
+import (
+	"time"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+)
+
+// LatencyThreshold defines the weight the volume of a provider is scaled by
+// once its latency exceeds Latency. A zero weight excludes the provider.
+type LatencyThreshold struct {
+	Latency time.Duration
+	Weight  sdk.Dec
+}
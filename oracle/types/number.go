@@ -0,0 +1,120 @@
+package types
+
+import (
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"regexp"
+	"strconv"
+	"strings"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+)
+
+// maxNumberExponent bounds the exponent accepted by ParseDec, well beyond the
+// range of sdk.Dec, so a malformed value cannot expand into a huge number.
+const maxNumberExponent = 100
+
+// numberRegex matches the decimal and exponent notations used by providers.
+var numberRegex = regexp.MustCompile(`^[+-]?(\d+\.?\d*|\.\d+)([eE]([+-]?\d+))?$`)
+
+// Number defines a numeric field of a provider message. Providers encode
+// prices and volumes either as JSON numbers or as strings, and some have
+// switched between the two, so Number accepts both and keeps the raw value to
+// be parsed as a decimal without the loss of precision of a float64. A null
+// value decodes to an empty Number, which fails to parse.
+type Number string
+
+// UnmarshalJSON decodes a JSON number or string, returning ErrInvalidNumber
+// if the value is not a number.
+func (n *Number) UnmarshalJSON(bz []byte) error {
+	raw := string(bz)
+	if raw == "null" {
+		*n = ""
+		return nil
+	}
+
+	if strings.HasPrefix(raw, `"`) {
+		if err := json.Unmarshal(bz, &raw); err != nil {
+			return err
+		}
+		if raw == "" {
+			*n = ""
+			return nil
+		}
+	}
+
+	if _, err := ParseDec(raw); err != nil {
+		return err
+	}
+	*n = Number(raw)
+	return nil
+}
+
+// NewNumber returns the Number of a field decoded into an interface, which
+// holds a float64 for a JSON number and a string for a JSON string.
+func NewNumber(value interface{}) (Number, error) {
+	var raw string
+	switch v := value.(type) {
+	case string:
+		raw = v
+	case float64:
+		raw = strconv.FormatFloat(v, 'f', -1, 64)
+	case json.Number:
+		raw = v.String()
+	default:
+		return "", fmt.Errorf("%w: unexpected type %T", ErrInvalidNumber, value)
+	}
+
+	if _, err := ParseDec(raw); err != nil {
+		return "", err
+	}
+	return Number(raw), nil
+}
+
+// String returns the raw value of the Number.
+func (n Number) String() string {
+	return string(n)
+}
+
+// Dec parses the Number as a decimal.
+func (n Number) Dec() (sdk.Dec, error) {
+	return ParseDec(string(n))
+}
+
+// IsZero returns true if the Number is empty or parses to zero.
+func (n Number) IsZero() bool {
+	dec, err := n.Dec()
+	return err != nil || dec.IsZero()
+}
+
+// ParseDec parses a decimal in plain or exponent notation, such as "0.0025" or
+// "2.5e-3". Digits beyond the precision of sdk.Dec are rounded instead of
+// rejected. It returns ErrInvalidNumber if the value is not a number.
+func ParseDec(value string) (sdk.Dec, error) {
+	value = strings.TrimSpace(value)
+	if dec, err := sdk.NewDecFromStr(value); err == nil {
+		return dec, nil
+	}
+
+	matches := numberRegex.FindStringSubmatch(value)
+	if matches == nil {
+		return sdk.Dec{}, fmt.Errorf("%w: %q", ErrInvalidNumber, value)
+	}
+	if exponent := matches[3]; exponent != "" {
+		exp, err := strconv.Atoi(exponent)
+		if err != nil || exp > maxNumberExponent || exp < -maxNumberExponent {
+			return sdk.Dec{}, fmt.Errorf("%w: exponent out of range %q", ErrInvalidNumber, value)
+		}
+	}
+
+	rat, ok := new(big.Rat).SetString(value)
+	if !ok {
+		return sdk.Dec{}, fmt.Errorf("%w: %q", ErrInvalidNumber, value)
+	}
+	dec, err := sdk.NewDecFromStr(rat.FloatString(sdk.Precision))
+	if err != nil {
+		return sdk.Dec{}, fmt.Errorf("%w: %q: %s", ErrInvalidNumber, value, err)
+	}
+	return dec, nil
+}
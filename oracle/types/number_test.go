@@ -0,0 +1,85 @@
+package types
+
+import (
+	"encoding/json"
+	"testing"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNumber_UnmarshalJSON(t *testing.T) {
+	type message struct {
+		Price Number `json:"price"`
+	}
+
+	testCases := []struct {
+		name     string
+		json     string
+		expected string
+		err      bool
+	}{
+		{"number", `{"price":34.69}`, "34.69", false},
+		{"string", `{"price":"34.69"}`, "34.69", false},
+		{"exponent number", `{"price":2.5e-7}`, "0.000000250000000000", false},
+		{"exponent string", `{"price":"2.5E-7"}`, "0.000000250000000000", false},
+		{"integer", `{"price":35}`, "35.000000000000000000", false},
+		{"null", `{"price":null}`, "", false},
+		{"empty string", `{"price":""}`, "", false},
+		{"invalid string", `{"price":"n/a"}`, "", true},
+		{"bool", `{"price":true}`, "", true},
+		{"exponent out of range", `{"price":"1e1000000"}`, "", true},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			var msg message
+			err := json.Unmarshal([]byte(tc.json), &msg)
+			if tc.err {
+				require.ErrorIs(t, err, ErrInvalidNumber)
+				return
+			}
+			require.NoError(t, err)
+
+			dec, err := msg.Price.Dec()
+			if tc.expected == "" {
+				require.Error(t, err)
+				require.True(t, msg.Price.IsZero())
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, sdk.MustNewDecFromStr(tc.expected), dec)
+		})
+	}
+}
+
+func TestNewNumber(t *testing.T) {
+	n, err := NewNumber("34.69")
+	require.NoError(t, err)
+	require.Equal(t, Number("34.69"), n)
+
+	n, err = NewNumber(34.69)
+	require.NoError(t, err)
+	require.Equal(t, Number("34.69"), n)
+
+	_, err = NewNumber("n/a")
+	require.ErrorIs(t, err, ErrInvalidNumber)
+
+	_, err = NewNumber(nil)
+	require.ErrorIs(t, err, ErrInvalidNumber)
+}
+
+func TestParseDec(t *testing.T) {
+	dec, err := ParseDec("0.0000000000000000012345")
+	require.NoError(t, err)
+	require.Equal(t, sdk.MustNewDecFromStr("0.000000000000000001"), dec)
+
+	dec, err = ParseDec("-1.5e2")
+	require.NoError(t, err)
+	require.Equal(t, sdk.NewDec(-150), dec)
+
+	for _, value := range []string{"", "abc", "1/3", "0x10", "NaN", "Inf", "1e"} {
+		_, err := ParseDec(value)
+		require.ErrorIs(t, err, ErrInvalidNumber, value)
+	}
+}
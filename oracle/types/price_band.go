@@ -0,0 +1,23 @@
+package types
+
+import (
+	sdk "github.com/cosmos/cosmos-sdk/types"
+)
+
+// PriceBand defines the absolute range of USD prices considered sane for an
+// asset. A nil bound leaves that side of the band open.
+type PriceBand struct {
+	Min sdk.Dec
+	Max sdk.Dec
+}
+
+// Contains returns true if the price is within the band.
+func (b PriceBand) Contains(price sdk.Dec) bool {
+	if !b.Min.IsNil() && price.LT(b.Min) {
+		return false
+	}
+	if !b.Max.IsNil() && price.GT(b.Max) {
+		return false
+	}
+	return true
+}
@@ -0,0 +1,48 @@
+package types
+
+import (
+	sdk "github.com/cosmos/cosmos-sdk/types"
+)
+
+// The reasons a provider price is left out of the aggregated USD price of its
+// asset.
+const (
+	FilterReasonProviderRole      = "provider_role"
+	FilterReasonPriceBand         = "price_band"
+	FilterReasonProviderAgreement = "provider_agreement"
+	FilterReasonRequiredProviders = "required_providers"
+	FilterReasonProviderGroups    = "provider_groups"
+	FilterReasonMaxProviders      = "max_providers"
+	FilterReasonStaleForex        = "stale_forex"
+	FilterReasonNoConversionRate  = "no_conversion_rate"
+	FilterReasonDeviation         = "deviation"
+	FilterReasonTickerUnused      = "ticker_unused"
+)
+
+type (
+	// PriceDetail defines the breakdown of the aggregated USD price of an
+	// asset: the price of each of its providers and whether it was included.
+	// Spread is the range of the included provider prices relative to the
+	// aggregated price.
+	PriceDetail struct {
+		Base      string                `json:"base"`
+		Quote     string                `json:"quote"`
+		Price     *sdk.Dec              `json:"price,omitempty"`
+		Spread    *sdk.Dec              `json:"spread,omitempty"`
+		Providers []ProviderPriceDetail `json:"providers"`
+	}
+
+	// ProviderPriceDetail defines the price of an asset from one provider
+	// pair, in its quote and converted to USD, and its share of the volume of
+	// the included prices. Reason is set if the price was filtered out.
+	ProviderPriceDetail struct {
+		Provider ProviderName `json:"provider"`
+		Quote    string       `json:"quote"`
+		Source   string       `json:"source"`
+		Price    sdk.Dec      `json:"price"`
+		USDPrice *sdk.Dec     `json:"usd_price,omitempty"`
+		Weight   sdk.Dec      `json:"weight"`
+		Included bool         `json:"included"`
+		Reason   string       `json:"reason,omitempty"`
+	}
+)
@@ -36,6 +36,14 @@ type (
 	// AggregatedProviderCandles defines a type alias for a map
 	// of provider -> currency pair -> []types.CandlePrice
 	AggregatedProviderCandles map[ProviderName]CurrencyPairCandles
+
+	// StalePrice defines the last aggregated price of a pair which has no
+	// fresh price, along with how long ago it was aggregated.
+	StalePrice struct {
+		Price      sdk.Dec `json:"price"`
+		Stale      bool    `json:"stale"`
+		AgeSeconds int64   `json:"age_seconds"`
+	}
 )
 
 // SetPrices sets the PricesWithMutex.prices value surrounded by a write lock
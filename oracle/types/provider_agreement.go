@@ -0,0 +1,42 @@
+package types
+
+import (
+	"sort"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+)
+
+// ProviderAgreement defines the number of providers whose prices of a pair
+// must agree for the pair to be trusted. Prices agree if the highest of them
+// is within Band, a fraction of the price, of the lowest.
+type ProviderAgreement struct {
+	MinProviders int
+	Band         sdk.Dec
+}
+
+// Agreeing returns the largest number of the prices which agree with each
+// other.
+func (a ProviderAgreement) Agreeing(prices []sdk.Dec) int {
+	sorted := make([]sdk.Dec, len(prices))
+	copy(sorted, prices)
+	sort.Slice(sorted, func(i, j int) bool {
+		return sorted[i].LT(sorted[j])
+	})
+
+	agreeing := 0
+	low := 0
+	for high := range sorted {
+		for low < high && sorted[high].Sub(sorted[low]).GT(sorted[low].Mul(a.Band)) {
+			low++
+		}
+		if n := high - low + 1; n > agreeing {
+			agreeing = n
+		}
+	}
+	return agreeing
+}
+
+// Met returns true if at least MinProviders of the prices agree.
+func (a ProviderAgreement) Met(prices []sdk.Dec) bool {
+	return a.Agreeing(prices) >= a.MinProviders
+}
@@ -0,0 +1,40 @@
+package types
+
+import (
+	"testing"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/stretchr/testify/require"
+)
+
+func TestProviderAgreement_Agreeing(t *testing.T) {
+	agreement := ProviderAgreement{MinProviders: 3, Band: sdk.MustNewDecFromStr("0.005")}
+	decs := func(prices ...string) []sdk.Dec {
+		res := make([]sdk.Dec, len(prices))
+		for i, price := range prices {
+			res[i] = sdk.MustNewDecFromStr(price)
+		}
+		return res
+	}
+
+	testCases := []struct {
+		name     string
+		prices   []sdk.Dec
+		agreeing int
+	}{
+		{"no prices", nil, 0},
+		{"single price", decs("10"), 1},
+		{"all agree", decs("10", "10.02", "10.04"), 3},
+		{"band edge", decs("10", "10.05"), 2},
+		{"outlier", decs("10", "10.02", "11"), 2},
+		{"two clusters", decs("10", "10.01", "12", "12.01", "12.02"), 3},
+		{"all disagree", decs("10", "11", "12"), 1},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			require.Equal(t, tc.agreeing, agreement.Agreeing(tc.prices))
+			require.Equal(t, tc.agreeing >= 3, agreement.Met(tc.prices))
+		})
+	}
+}
@@ -0,0 +1,15 @@
+package types
+
+// ProviderGroups defines the independence group of the providers sharing an
+// upstream data source, by provider. A provider without a group is
+// independent of every other provider.
+type ProviderGroups map[ProviderName]string
+
+// Group returns the independence group of the provider, which is the provider
+// itself if it is not in a group.
+func (g ProviderGroups) Group(providerName ProviderName) string {
+	if group, ok := g[providerName]; ok {
+		return group
+	}
+	return providerName.String()
+}
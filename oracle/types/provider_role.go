@@ -0,0 +1,37 @@
+package types
+
+const (
+	// ProviderRoleTicker uses the tickers of a provider in the VWAP
+	// aggregate of a pair.
+	ProviderRoleTicker ProviderRole = 1 << iota
+
+	// ProviderRoleCandle uses the candles of a provider in the TVWAP
+	// aggregate of a pair.
+	ProviderRoleCandle
+
+	// ProviderRoleAll uses both the tickers and the candles of a provider.
+	ProviderRoleAll = ProviderRoleTicker | ProviderRoleCandle
+)
+
+type (
+	// ProviderRole defines which aggregates the prices of a provider
+	// contribute to.
+	ProviderRole uint8
+
+	// ProviderRoles defines the roles of the providers restricted to a single
+	// aggregate for a given currency pair. Any other provider has every role.
+	ProviderRoles map[ProviderName]map[CurrencyPair]ProviderRole
+)
+
+// Has returns true if the role includes the given role.
+func (r ProviderRole) Has(role ProviderRole) bool {
+	return r&role == role
+}
+
+// Role returns the role of the provider for the given currency pair.
+func (pr ProviderRoles) Role(providerName ProviderName, cp CurrencyPair) ProviderRole {
+	if role, ok := pr[providerName][CurrencyPair{Base: cp.Base, Quote: cp.Quote}]; ok {
+		return role
+	}
+	return ProviderRoleAll
+}
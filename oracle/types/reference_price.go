@@ -0,0 +1,14 @@
+package types
+
+import (
+	sdk "github.com/cosmos/cosmos-sdk/types"
+)
+
+// ReferencePrice defines a trusted benchmark the aggregated USD price of an
+// asset is compared to. The reference is never used in the vote. Threshold is
+// the percent divergence from the reference past which a warning is logged.
+type ReferencePrice struct {
+	Pair      CurrencyPair
+	Provider  ProviderName
+	Threshold sdk.Dec
+}
@@ -0,0 +1,57 @@
+package types
+
+const (
+	// SpotSourceTicker uses the ticker price of a provider as its spot price
+	// of a pair.
+	SpotSourceTicker SpotSource = "ticker"
+
+	// SpotSourceCandle uses the close of the latest candle of a provider as
+	// its spot price of a pair.
+	SpotSourceCandle SpotSource = "candle"
+)
+
+type (
+	// SpotSource defines which value of a provider is its spot price of a
+	// pair, the single price it contributes to the spot median. Either value
+	// is used if the provider reports only the other.
+	SpotSource string
+
+	// SpotSources defines the spot sources of the providers of the currency
+	// pairs which are not priced by their ticker. Any other provider uses
+	// SpotSourceTicker.
+	SpotSources map[ProviderName]map[CurrencyPair]SpotSource
+)
+
+// Source returns the spot source of the provider for the given currency pair.
+func (ss SpotSources) Source(providerName ProviderName, cp CurrencyPair) SpotSource {
+	if source, ok := ss[providerName][CurrencyPair{Base: cp.Base, Quote: cp.Quote}]; ok {
+		return source
+	}
+	return SpotSourceTicker
+}
+
+// SpotPrice returns the spot price of the provider for the pair given its
+// tickers and candles, from its spot source, and false if it has no price of
+// the pair.
+func (ss SpotSources) SpotPrice(
+	providerName ProviderName,
+	cp CurrencyPair,
+	tickers CurrencyPairTickers,
+	candles CurrencyPairCandles,
+) (TickerPrice, bool) {
+	ticker, hasTicker := tickers[cp]
+	if hasTicker && (ss.Source(providerName, cp) == SpotSourceTicker || len(candles[cp]) == 0) {
+		return ticker, true
+	}
+	if len(candles[cp]) == 0 {
+		return TickerPrice{}, false
+	}
+
+	latest := candles[cp][0]
+	for _, candle := range candles[cp][1:] {
+		if candle.TimeStamp > latest.TimeStamp {
+			latest = candle
+		}
+	}
+	return TickerPrice{Price: latest.Price, Volume: latest.Volume}, true
+}
@@ -14,12 +14,12 @@ type TickerPrice struct {
 
 // NewTickerPrice parses the lastPrice and volume to a decimal and returns a TickerPrice
 func NewTickerPrice(lastPrice, volume string) (TickerPrice, error) {
-	price, err := sdk.NewDecFromStr(lastPrice)
+	price, err := ParseDec(lastPrice)
 	if err != nil {
 		return TickerPrice{}, fmt.Errorf("failed to parse ticker price (%s): %w", lastPrice, err)
 	}
 
-	volumeDec, err := sdk.NewDecFromStr(volume)
+	volumeDec, err := ParseDec(volume)
 	if err != nil {
 		return TickerPrice{}, fmt.Errorf("failed to parse ticker volume (%s): %w", volume, err)
 	}
@@ -0,0 +1,5 @@
+package types
+
+// TickerWindows defines the number of recent ticker samples of a provider for
+// a given currency pair whose median is used as the provider's ticker price.
+type TickerWindows map[ProviderName]map[CurrencyPair]int
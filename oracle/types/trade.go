@@ -1,7 +1,7 @@
 package types
 
 type Trade struct {
-	Time  int64 // time in unix epoch
+	Time  int64 // time in unix epoch ms
 	Size  string
 	Price string
 }
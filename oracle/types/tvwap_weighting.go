@@ -0,0 +1,75 @@
+package types
+
+import (
+	"time"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+)
+
+const (
+	// TvwapWeightingUniform weights every candle within the TVWAP period by
+	// its volume only.
+	TvwapWeightingUniform TvwapWeightingMode = "uniform"
+
+	// TvwapWeightingLinear weights the candles by their volume and a weight
+	// decaying linearly with their age. This is the default.
+	TvwapWeightingLinear TvwapWeightingMode = "linear"
+
+	// TvwapWeightingExponential weights the candles by their volume and a
+	// weight halving every half-life of their age.
+	TvwapWeightingExponential TvwapWeightingMode = "exponential"
+
+	// TvwapCombinationVolume combines the candles of every provider into a
+	// single TVWAP, so each provider weighs by the volume of its candles. This
+	// is the default.
+	TvwapCombinationVolume TvwapCombination = "volume"
+
+	// TvwapCombinationEqual averages the TVWAP of each provider with the same
+	// weight.
+	TvwapCombinationEqual TvwapCombination = "equal"
+
+	// TvwapCombinationWeighted averages the TVWAP of each provider by its
+	// configured weight.
+	TvwapCombinationWeighted TvwapCombination = "weighted"
+)
+
+type (
+	// TvwapWeightingMode defines how the candles of an asset are weighted by
+	// their age in its TVWAP.
+	TvwapWeightingMode string
+
+	// TvwapCombination defines how the TVWAPs of the providers of an asset are
+	// combined into its TVWAP.
+	TvwapCombination string
+
+	// TvwapWeighting defines the time weighting of the candles of an asset and
+	// how its providers are combined. HalfLife only applies to the exponential
+	// mode and ProviderWeights to the weighted combination, in which providers
+	// without a weight are left out. Window, if set, replaces the default
+	// period of the candles aggregated into the TVWAP.
+	TvwapWeighting struct {
+		Mode            TvwapWeightingMode
+		HalfLife        time.Duration
+		Combination     TvwapCombination
+		ProviderWeights map[ProviderName]sdk.Dec
+		Window          time.Duration
+	}
+)
+
+// IsValid returns true if the TVWAP weighting mode is supported.
+func (m TvwapWeightingMode) IsValid() bool {
+	switch m {
+	case TvwapWeightingUniform, TvwapWeightingLinear, TvwapWeightingExponential:
+		return true
+	}
+	return false
+}
+
+// IsValid returns true if the TVWAP combination is supported.
+func (c TvwapCombination) IsValid() bool {
+	switch c {
+	case TvwapCombinationVolume, TvwapCombinationEqual, TvwapCombinationWeighted:
+		return true
+	}
+	return false
+}
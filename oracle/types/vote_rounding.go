@@ -0,0 +1,69 @@
+package types
+
+import (
+	"math/big"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+)
+
+const (
+	// RoundHalfUp rounds to the nearest value, away from zero on ties.
+	RoundHalfUp RoundingMode = "half-up"
+
+	// RoundHalfEven rounds to the nearest value, to the even value on ties.
+	RoundHalfEven RoundingMode = "half-even"
+
+	// RoundDown rounds towards zero, so a price is never overstated.
+	RoundDown RoundingMode = "down"
+
+	// RoundUp rounds away from zero, so a price is never understated.
+	RoundUp RoundingMode = "up"
+)
+
+type (
+	// RoundingMode defines how a vote value is rounded to its decimals.
+	RoundingMode string
+
+	// VoteRounding defines the number of decimals a vote value is rounded to
+	// and how.
+	VoteRounding struct {
+		Decimals int
+		Mode     RoundingMode
+	}
+)
+
+// RoundingModes are the supported rounding modes.
+var RoundingModes = []RoundingMode{RoundHalfUp, RoundHalfEven, RoundDown, RoundUp}
+
+// Round returns the value rounded to the decimals with the rounding mode. A
+// value without more decimals is returned unchanged.
+func (vr VoteRounding) Round(value sdk.Dec) sdk.Dec {
+	if vr.Decimals >= sdk.Precision {
+		return value
+	}
+
+	unit := new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(sdk.Precision-vr.Decimals)), nil)
+	quo, rem := new(big.Int).QuoRem(value.BigInt(), unit, new(big.Int))
+	if rem.Sign() == 0 {
+		return value
+	}
+
+	// twice the remainder compared to the unit tells whether it is past half
+	half := new(big.Int).Mul(new(big.Int).Abs(rem), big.NewInt(2)).Cmp(unit)
+
+	awayFromZero := false
+	switch vr.Mode {
+	case RoundHalfUp:
+		awayFromZero = half >= 0
+	case RoundHalfEven:
+		awayFromZero = half > 0 || (half == 0 && quo.Bit(0) == 1)
+	case RoundUp:
+		awayFromZero = true
+	case RoundDown:
+	}
+	if awayFromZero {
+		quo.Add(quo, big.NewInt(int64(rem.Sign())))
+	}
+
+	return sdk.NewDecFromBigIntWithPrec(quo.Mul(quo, unit), sdk.Precision)
+}
@@ -0,0 +1,108 @@
+package types
+
+import (
+	"testing"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/stretchr/testify/require"
+)
+
+func TestVoteRounding_Round(t *testing.T) {
+	testCases := []struct {
+		name     string
+		value    string
+		decimals int
+		expected map[RoundingMode]string
+	}{
+		{
+			"tie",
+			"1.2345",
+			3,
+			map[RoundingMode]string{
+				RoundHalfUp:   "1.235",
+				RoundHalfEven: "1.234",
+				RoundDown:     "1.234",
+				RoundUp:       "1.235",
+			},
+		},
+		{
+			"tie to an odd digit",
+			"1.2355",
+			3,
+			map[RoundingMode]string{
+				RoundHalfUp:   "1.236",
+				RoundHalfEven: "1.236",
+				RoundDown:     "1.235",
+				RoundUp:       "1.236",
+			},
+		},
+		{
+			"below the tie",
+			"1.234499999999999999",
+			3,
+			map[RoundingMode]string{
+				RoundHalfUp:   "1.234",
+				RoundHalfEven: "1.234",
+				RoundDown:     "1.234",
+				RoundUp:       "1.235",
+			},
+		},
+		{
+			"past the tie",
+			"1.234500000000000001",
+			3,
+			map[RoundingMode]string{
+				RoundHalfUp:   "1.235",
+				RoundHalfEven: "1.235",
+				RoundDown:     "1.234",
+				RoundUp:       "1.235",
+			},
+		},
+		{
+			"no decimals",
+			"1230000.5",
+			0,
+			map[RoundingMode]string{
+				RoundHalfUp:   "1230001",
+				RoundHalfEven: "1230000",
+				RoundDown:     "1230000",
+				RoundUp:       "1230001",
+			},
+		},
+		{
+			"exact value",
+			"1.234",
+			3,
+			map[RoundingMode]string{
+				RoundHalfUp:   "1.234",
+				RoundHalfEven: "1.234",
+				RoundDown:     "1.234",
+				RoundUp:       "1.234",
+			},
+		},
+		{
+			"full precision",
+			"1.000000000000000001",
+			sdk.Precision,
+			map[RoundingMode]string{
+				RoundHalfUp:   "1.000000000000000001",
+				RoundHalfEven: "1.000000000000000001",
+				RoundDown:     "1.000000000000000001",
+				RoundUp:       "1.000000000000000001",
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		for _, mode := range RoundingModes {
+			t.Run(tc.name+"/"+string(mode), func(t *testing.T) {
+				rounding := VoteRounding{Decimals: tc.decimals, Mode: mode}
+				require.Equal(
+					t,
+					sdk.MustNewDecFromStr(tc.expected[mode]),
+					rounding.Round(sdk.MustNewDecFromStr(tc.value)),
+				)
+			})
+		}
+	}
+}
@@ -2,11 +2,14 @@ package oracle
 
 import (
 	"fmt"
+	"math"
 	"sort"
+	"strconv"
 	"time"
 
 	sdk "github.com/cosmos/cosmos-sdk/types"
 
+	"github.com/ojo-network/price-feeder/config"
 	"github.com/ojo-network/price-feeder/oracle/provider"
 	"github.com/ojo-network/price-feeder/oracle/types"
 )
@@ -18,7 +21,7 @@ var (
 
 const (
 	// tvwapCandlePeriod represents the time period we use for tvwap in minutes
-	tvwapCandlePeriod = 10 * time.Minute
+	tvwapCandlePeriod = config.DefaultTwapWindow
 )
 
 // compute VWAP for each base by dividing the Σ {P * V} by Σ {V}
@@ -40,10 +43,22 @@ func vwap(weightedPrices, volumeSum types.CurrencyPairDec) types.CurrencyPairDec
 
 // ComputeVWAP computes the volume weighted average price for all price points
 // for each ticker/exchange pair. The provided prices argument reflects a mapping
-// of provider => {<base> => <TickerPrice>, ...}.
+// of provider => {<base> => <TickerPrice>, ...}. Tickers with a zero, negative
+// or missing volume are excluded.
 //
 // Ref: https://en.wikipedia.org/wiki/Volume-weighted_average_price
 func ComputeVWAP(prices types.AggregatedProviderPrices) types.CurrencyPairDec {
+	return ComputeZeroVolumeVWAP(prices, sdk.ZeroDec())
+}
+
+// ComputeZeroVolumeVWAP computes the VWAP like ComputeVWAP, weighting the
+// tickers with a zero, negative or missing volume by zeroVolumeWeight instead
+// of excluding them. A nil or non-positive zeroVolumeWeight excludes them. A
+// pair whose tickers all have a zero weight has no VWAP.
+func ComputeZeroVolumeVWAP(
+	prices types.AggregatedProviderPrices,
+	zeroVolumeWeight sdk.Dec,
+) types.CurrencyPairDec {
 	var (
 		weightedPrices = make(types.CurrencyPairDec)
 		volumeSum      = make(types.CurrencyPairDec)
@@ -51,6 +66,16 @@ func ComputeVWAP(prices types.AggregatedProviderPrices) types.CurrencyPairDec {
 
 	for _, providerPrices := range prices {
 		for base, tp := range providerPrices {
+			if tp.Price.IsNil() {
+				continue
+			}
+			if tp.Volume.IsNil() || !tp.Volume.IsPositive() {
+				if zeroVolumeWeight.IsNil() || !zeroVolumeWeight.IsPositive() {
+					continue
+				}
+				tp.Volume = zeroVolumeWeight
+			}
+
 			if _, ok := weightedPrices[base]; !ok {
 				weightedPrices[base] = sdk.ZeroDec()
 			}
@@ -76,26 +101,37 @@ func ComputeVWAP(prices types.AggregatedProviderPrices) types.CurrencyPairDec {
 //
 // Ref : https://en.wikipedia.org/wiki/Time-weighted_average_price
 func ComputeTVWAP(prices types.AggregatedProviderCandles) (types.CurrencyPairDec, error) {
+	return ComputeWeightedTVWAP(prices, nil)
+}
+
+// ComputeWeightedTVWAP computes the TVWAP like ComputeTVWAP, weighting the
+// candles of each base by their age according to its TVWAP weighting, and
+// combining the TVWAPs of its providers by its TVWAP combination. Bases
+// without a weighting use the linear weighting and the volume combination,
+// and bases without a TWAP window the default TVWAP period.
+func ComputeWeightedTVWAP(
+	prices types.AggregatedProviderCandles,
+	weightings map[string]types.TvwapWeighting,
+) (types.CurrencyPairDec, error) {
 	var (
-		weightedPrices = make(types.CurrencyPairDec)
-		volumeSum      = make(types.CurrencyPairDec)
+		weightedPrices = make(types.CurrencyPairDecByProvider)
+		volumeSum      = make(types.CurrencyPairDecByProvider)
 		now            = provider.PastUnixTime(0)
 		timePeriod     = provider.PastUnixTime(tvwapCandlePeriod)
 	)
 
-	for _, providerPrices := range prices {
+	for providerName, providerPrices := range prices {
+		weightedPrices[providerName] = make(types.CurrencyPairDec)
+		volumeSum[providerName] = make(types.CurrencyPairDec)
+
 		for base := range providerPrices {
-			cp := providerPrices[base]
+			cp := FillCandleGaps(providerPrices[base])
 			if len(cp) == 0 {
 				continue
 			}
 
-			if _, ok := weightedPrices[base]; !ok {
-				weightedPrices[base] = sdk.ZeroDec()
-			}
-			if _, ok := volumeSum[base]; !ok {
-				volumeSum[base] = sdk.ZeroDec()
-			}
+			weightedPrices[providerName][base] = sdk.ZeroDec()
+			volumeSum[providerName][base] = sdk.ZeroDec()
 
 			// Sort by timestamp old -> new
 			sort.SliceStable(cp, func(i, j int) bool {
@@ -109,30 +145,105 @@ func ComputeTVWAP(prices types.AggregatedProviderCandles) (types.CurrencyPairDec
 			// weightUnit = (1 - minimumTimeWeight) / period
 			weightUnit := sdk.OneDec().Sub(minimumTimeWeight).Quo(period)
 
+			periodStart := timePeriod
+			if window := weightings[base.Base].Window; window > 0 {
+				periodStart = provider.PastUnixTime(window)
+			}
+
 			// get weighted prices, and sum of volumes
 			for _, candle := range cp {
-				// we only want candles within the last timePeriod
-				if timePeriod < candle.TimeStamp && candle.TimeStamp <= now {
+				// we only want candles within the last timePeriod, or the
+				// TWAP window of the base
+				if periodStart < candle.TimeStamp && candle.TimeStamp <= now {
 					// timeDiff = now - candle.TimeStamp
 					timeDiff := sdk.NewDec(now - candle.TimeStamp)
 					// set minimum candle volume for low-trading assets
-					if candle.Volume.Equal(sdk.ZeroDec()) {
+					if candle.Volume.IsNil() || !candle.Volume.IsPositive() {
 						candle.Volume = minimumCandleVolume
 					}
 
-					// volume = candle.Volume * (weightUnit * (period - timeDiff) + minimumTimeWeight)
-					volume := candle.Volume.Mul(
-						weightUnit.Mul(period.Sub(timeDiff).Add(minimumTimeWeight)),
-					)
-					volumeSum[base] = volumeSum[base].Add(volume)
-					weightedPrices[base] = weightedPrices[base].Add(candle.Price.Mul(volume))
+					var volume sdk.Dec
+					switch weighting := weightings[base.Base]; weighting.Mode {
+					case types.TvwapWeightingUniform:
+						volume = candle.Volume
+
+					case types.TvwapWeightingExponential:
+						// volume = candle.Volume * 0.5 ^ (timeDiff / halfLife)
+						volume = candle.Volume.Mul(exponentialTimeWeight(now-candle.TimeStamp, weighting.HalfLife))
+
+					default:
+						// volume = candle.Volume * (weightUnit * (period - timeDiff) + minimumTimeWeight)
+						volume = candle.Volume.Mul(
+							weightUnit.Mul(period.Sub(timeDiff).Add(minimumTimeWeight)),
+						)
+					}
+					volumeSum[providerName][base] = volumeSum[providerName][base].Add(volume)
+					weightedPrices[providerName][base] = weightedPrices[providerName][base].Add(candle.Price.Mul(volume))
+				}
+			}
+		}
+	}
+
+	return combineTVWAPs(weightedPrices, volumeSum, weightings), nil
+}
+
+// combineTVWAPs combines the volume weighted prices and volume sums of each
+// provider into the TVWAP of each base, according to its TVWAP combination.
+func combineTVWAPs(
+	weightedPrices types.CurrencyPairDecByProvider,
+	volumeSum types.CurrencyPairDecByProvider,
+	weightings map[string]types.TvwapWeighting,
+) types.CurrencyPairDec {
+	var (
+		combinedPrices = make(types.CurrencyPairDec)
+		weightSum      = make(types.CurrencyPairDec)
+	)
+
+	for providerName, providerPrices := range weightedPrices {
+		for base, weightedPrice := range providerPrices {
+			providerVolume := volumeSum[providerName][base]
+
+			var price, weight sdk.Dec
+			switch weighting := weightings[base.Base]; weighting.Combination {
+			case types.TvwapCombinationEqual, types.TvwapCombinationWeighted:
+				if providerVolume.IsZero() {
+					continue
 				}
+				weight = sdk.OneDec()
+				if weighting.Combination == types.TvwapCombinationWeighted {
+					providerWeight, ok := weighting.ProviderWeights[providerName]
+					if !ok {
+						continue
+					}
+					weight = providerWeight
+				}
+				// price = weight * providerTVWAP
+				price = weightedPrice.Quo(providerVolume).Mul(weight)
+
+			default:
+				price, weight = weightedPrice, providerVolume
 			}
 
+			if _, ok := combinedPrices[base]; !ok {
+				combinedPrices[base] = sdk.ZeroDec()
+				weightSum[base] = sdk.ZeroDec()
+			}
+			combinedPrices[base] = combinedPrices[base].Add(price)
+			weightSum[base] = weightSum[base].Add(weight)
 		}
 	}
 
-	return vwap(weightedPrices, volumeSum), nil
+	return vwap(combinedPrices, weightSum)
+}
+
+// exponentialTimeWeight returns the weight of a candle of the given age in
+// milliseconds, halving every halfLife.
+func exponentialTimeWeight(age int64, halfLife time.Duration) sdk.Dec {
+	if halfLife <= 0 {
+		return sdk.OneDec()
+	}
+	weight := math.Pow(0.5, float64(age)/float64(halfLife.Milliseconds()))
+	return sdk.MustNewDecFromStr(strconv.FormatFloat(weight, 'f', sdk.Precision, 64))
 }
 
 // StandardDeviation returns maps of the standard deviations and means of assets.
@@ -177,8 +288,11 @@ func StandardDeviation(
 		means[base] = sum.QuoInt64(numPrices)
 		varianceSum := sdk.ZeroDec()
 
+		// Squaring the deviations of tiny prices would round them to zero at
+		// the precision of sdk.Dec, so they are scaled up beforehand.
+		scale := deviationScale(means[base])
 		for _, price := range priceSlice[base] {
-			deviation := price.Sub(means[base])
+			deviation := price.Sub(means[base]).Mul(scale)
 			varianceSum = varianceSum.Add(deviation.Mul(deviation))
 		}
 
@@ -189,12 +303,69 @@ func StandardDeviation(
 			return make(types.CurrencyPairDec), make(types.CurrencyPairDec), err
 		}
 
-		deviations[base] = standardDeviation
+		deviations[base] = standardDeviation.Quo(scale)
 	}
 
 	return deviations, means, nil
 }
 
+// deviationScale returns the smallest power of ten which scales the given
+// mean to at least one.
+func deviationScale(mean sdk.Dec) sdk.Dec {
+	scale := sdk.OneDec()
+	if !mean.IsPositive() {
+		return scale
+	}
+	for mean.Mul(scale).LT(sdk.OneDec()) {
+		scale = scale.MulInt64(10)
+	}
+	return scale
+}
+
+// median returns the median of the given rates, averaging the two middle rates
+// if there is an even number of them.
+func median(rates []sdk.Dec) sdk.Dec {
+	sorted := make([]sdk.Dec, len(rates))
+	copy(sorted, rates)
+	sort.Slice(sorted, func(i, j int) bool {
+		return sorted[i].LT(sorted[j])
+	})
+
+	mid := len(sorted) / 2
+	if len(sorted)%2 == 0 {
+		return sorted[mid-1].Add(sorted[mid]).QuoInt64(2)
+	}
+	return sorted[mid]
+}
+
+// providerMedian returns the median of the rates of the providers. If there is
+// an even number of them and the tie break provider has one of the two middle
+// rates, its rate is returned rather than their average.
+func providerMedian(providerRates map[types.ProviderName]sdk.Dec, tieBreak types.ProviderName) sdk.Dec {
+	providerNames := make([]types.ProviderName, 0, len(providerRates))
+	for providerName := range providerRates {
+		providerNames = append(providerNames, providerName)
+	}
+	sort.Slice(providerNames, func(i, j int) bool {
+		ri, rj := providerRates[providerNames[i]], providerRates[providerNames[j]]
+		if !ri.Equal(rj) {
+			return ri.LT(rj)
+		}
+		return providerNames[i] < providerNames[j]
+	})
+
+	mid := len(providerNames) / 2
+	if len(providerNames)%2 != 0 {
+		return providerRates[providerNames[mid]]
+	}
+	for _, providerName := range providerNames[mid-1 : mid+1] {
+		if providerName == tieBreak {
+			return providerRates[providerName]
+		}
+	}
+	return providerRates[providerNames[mid-1]].Add(providerRates[providerNames[mid]]).QuoInt64(2)
+}
+
 // ComputeTvwapsByProvider computes the tvwap prices from candles for each provider separately and returns them
 // in a map separated by provider name
 func ComputeTvwapsByProvider(prices types.AggregatedProviderCandles) (types.CurrencyPairDecByProvider, error) {
@@ -222,3 +393,64 @@ func ComputeVwapsByProvider(prices types.AggregatedProviderPrices) types.Currenc
 	}
 	return vwaps
 }
+
+// FillCandleGaps merges the candles of a pair subscribed to at several
+// intervals. The candles of the finest interval are kept, and each coarser
+// candle only fills the fine candles missing within its time span: its volume
+// is scaled by the share of the started fine candles it spans which are
+// missing. Candles are returned as is if they share a single interval or any
+// of them has an unknown interval.
+func FillCandleGaps(candles []types.CandlePrice) []types.CandlePrice {
+	var fineInterval, coarseInterval time.Duration
+	for _, candle := range candles {
+		if candle.Interval <= 0 {
+			return candles
+		}
+		if fineInterval == 0 || candle.Interval < fineInterval {
+			fineInterval = candle.Interval
+		}
+		if candle.Interval > coarseInterval {
+			coarseInterval = candle.Interval
+		}
+	}
+	if fineInterval == coarseInterval {
+		return candles
+	}
+
+	fineTimeStamps := make(map[int64]struct{})
+	for _, candle := range candles {
+		if candle.Interval == fineInterval {
+			fineTimeStamps[candle.TimeStamp] = struct{}{}
+		}
+	}
+
+	var (
+		now    = provider.PastUnixTime(0)
+		fineMs = fineInterval.Milliseconds()
+		filled = make([]types.CandlePrice, 0, len(candles))
+	)
+	for _, candle := range candles {
+		if candle.Interval == fineInterval {
+			filled = append(filled, candle)
+			continue
+		}
+
+		// the fine candles spanned by the coarse candle which have started,
+		// identified by their close timestamps
+		var started, missing int64
+		start := candle.TimeStamp - candle.Interval.Milliseconds()
+		for closeTS := start + fineMs; closeTS <= candle.TimeStamp && closeTS-fineMs <= now; closeTS += fineMs {
+			started++
+			if _, ok := fineTimeStamps[closeTS]; !ok {
+				missing++
+			}
+		}
+		if started == 0 || missing == 0 {
+			continue
+		}
+
+		candle.Volume = candle.Volume.MulInt64(missing).QuoInt64(started)
+		filled = append(filled, candle)
+	}
+	return filled
+}
@@ -85,6 +85,165 @@ func TestComputeVWAP(t *testing.T) {
 	}
 }
 
+func TestComputeZeroVolumeVWAP(t *testing.T) {
+	prices := types.AggregatedProviderPrices{
+		provider.ProviderBinance: {
+			ATOMUSD: types.TickerPrice{
+				Price:  sdk.MustNewDecFromStr("10"),
+				Volume: sdk.MustNewDecFromStr("300"),
+			},
+			OJOUSD: types.TickerPrice{
+				Price:  sdk.MustNewDecFromStr("1"),
+				Volume: sdk.ZeroDec(),
+			},
+		},
+		provider.ProviderKraken: {
+			ATOMUSD: types.TickerPrice{
+				Price:  sdk.MustNewDecFromStr("12"),
+				Volume: sdk.MustNewDecFromStr("100"),
+			},
+			OJOUSD: types.TickerPrice{
+				Price: sdk.MustNewDecFromStr("2"),
+			},
+		},
+		provider.ProviderOkx: {
+			ATOMUSD: types.TickerPrice{
+				Price:  sdk.MustNewDecFromStr("100"),
+				Volume: sdk.ZeroDec(),
+			},
+			LUNAUSD: types.TickerPrice{
+				Price:  sdk.MustNewDecFromStr("64"),
+				Volume: sdk.MustNewDecFromStr("-5"),
+			},
+		},
+	}
+
+	testCases := map[string]struct {
+		zeroVolumeWeight sdk.Dec
+		expected         types.CurrencyPairDec
+	}{
+		"excluded by default": {
+			zeroVolumeWeight: sdk.Dec{},
+			expected: types.CurrencyPairDec{
+				ATOMUSD: sdk.MustNewDecFromStr("10.5"),
+			},
+		},
+		"excluded with a zero weight": {
+			zeroVolumeWeight: sdk.ZeroDec(),
+			expected: types.CurrencyPairDec{
+				ATOMUSD: sdk.MustNewDecFromStr("10.5"),
+			},
+		},
+		"minimum weight": {
+			zeroVolumeWeight: sdk.MustNewDecFromStr("100"),
+			expected: types.CurrencyPairDec{
+				// (10 * 300 + 12 * 100 + 100 * 100) / 500
+				ATOMUSD: sdk.MustNewDecFromStr("28.4"),
+				// providers without volume are averaged
+				OJOUSD:  sdk.MustNewDecFromStr("1.5"),
+				LUNAUSD: sdk.MustNewDecFromStr("64"),
+			},
+		},
+	}
+
+	for name, tc := range testCases {
+		tc := tc
+
+		t.Run(name, func(t *testing.T) {
+			vwap := oracle.ComputeZeroVolumeVWAP(prices, tc.zeroVolumeWeight)
+			require.Equal(t, tc.expected, vwap)
+		})
+	}
+
+	require.Equal(t, oracle.ComputeZeroVolumeVWAP(prices, sdk.Dec{}), oracle.ComputeVWAP(prices))
+}
+
+func TestFillCandleGaps(t *testing.T) {
+	now := time.Unix(1687944840, 0)
+	provider.SetClock(provider.FixedClock(now))
+	defer provider.SetClock(provider.SystemClock{})
+
+	// candleAt returns a candle of the interval closing at the end of the
+	// minute starting minutes after the open of the 5m candle ending now
+	open := now.Add(-5 * time.Minute).UnixMilli()
+	candleAt := func(minutes int64, interval time.Duration, price, volume string) types.CandlePrice {
+		return types.CandlePrice{
+			Price:     sdk.MustNewDecFromStr(price),
+			Volume:    sdk.MustNewDecFromStr(volume),
+			TimeStamp: open + minutes*time.Minute.Milliseconds() + interval.Milliseconds() - 1,
+			Interval:  interval,
+		}
+	}
+	fine := func(minutes int64) types.CandlePrice {
+		return candleAt(minutes, time.Minute, "10", "100")
+	}
+	coarse := candleAt(0, 5*time.Minute, "20", "500")
+
+	testCases := map[string]struct {
+		candles  []types.CandlePrice
+		expected []types.CandlePrice
+	}{
+		"single interval": {
+			candles:  []types.CandlePrice{fine(0), fine(1)},
+			expected: []types.CandlePrice{fine(0), fine(1)},
+		},
+		"unknown interval": {
+			candles: []types.CandlePrice{
+				{Price: sdk.OneDec(), Volume: sdk.OneDec(), TimeStamp: open},
+				coarse,
+			},
+			expected: []types.CandlePrice{
+				{Price: sdk.OneDec(), Volume: sdk.OneDec(), TimeStamp: open},
+				coarse,
+			},
+		},
+		"no holes": {
+			candles:  []types.CandlePrice{fine(0), fine(1), fine(2), fine(3), fine(4), coarse},
+			expected: []types.CandlePrice{fine(0), fine(1), fine(2), fine(3), fine(4)},
+		},
+		"repeated fine updates": {
+			candles:  []types.CandlePrice{fine(0), fine(1), fine(1), fine(2), fine(3), fine(4), fine(4), coarse},
+			expected: []types.CandlePrice{fine(0), fine(1), fine(1), fine(2), fine(3), fine(4), fine(4)},
+		},
+		"no fine candles": {
+			candles:  []types.CandlePrice{coarse},
+			expected: []types.CandlePrice{coarse},
+		},
+		"fine candles with holes": {
+			candles: []types.CandlePrice{fine(0), fine(1), fine(3), coarse},
+			expected: []types.CandlePrice{
+				fine(0), fine(1), fine(3),
+				// the coarse candle fills 2 of the 5 fine candles
+				candleAt(0, 5*time.Minute, "20", "200"),
+			},
+		},
+		"coarse candle in progress": {
+			candles: []types.CandlePrice{
+				fine(3), fine(4), fine(5), candleAt(3, 5*time.Minute, "20", "500"),
+			},
+			// only the 3 fine candles started within the coarse candle are
+			// expected, and none is missing
+			expected: []types.CandlePrice{fine(3), fine(4), fine(5)},
+		},
+		"coarse candle in progress with holes": {
+			candles: []types.CandlePrice{
+				fine(4), candleAt(3, 5*time.Minute, "20", "300"),
+			},
+			expected: []types.CandlePrice{
+				fine(4), candleAt(3, 5*time.Minute, "20", "200"),
+			},
+		},
+	}
+
+	for name, tc := range testCases {
+		tc := tc
+
+		t.Run(name, func(t *testing.T) {
+			require.Equal(t, tc.expected, oracle.FillCandleGaps(tc.candles))
+		})
+	}
+}
+
 func TestComputeTVWAP(t *testing.T) {
 	testCases := map[string]struct {
 		candles  types.AggregatedProviderCandles
@@ -275,6 +434,98 @@ func TestComputeTVWAP(t *testing.T) {
 	}
 }
 
+func TestComputeWeightedTVWAP(t *testing.T) {
+	provider.SetClock(provider.FixedClock(time.Unix(1687944835, 0)))
+	defer provider.SetClock(provider.SystemClock{})
+
+	volume := sdk.MustNewDecFromStr("1000")
+	candles := types.AggregatedProviderCandles{
+		provider.ProviderBinance: {
+			ATOMUSD: []types.CandlePrice{
+				{Price: sdk.MustNewDecFromStr("10"), Volume: volume, TimeStamp: provider.PastUnixTime(8 * time.Minute)},
+				{Price: sdk.MustNewDecFromStr("20"), Volume: volume, TimeStamp: provider.PastUnixTime(1 * time.Minute)},
+			},
+		},
+	}
+	tvwap := func(weighting types.TvwapWeighting) sdk.Dec {
+		prices, err := oracle.ComputeWeightedTVWAP(candles, map[string]types.TvwapWeighting{"ATOM": weighting})
+		require.NoError(t, err)
+		return prices[ATOMUSD]
+	}
+
+	defaultTvwap, err := oracle.ComputeTVWAP(candles)
+	require.NoError(t, err)
+
+	uniform := tvwap(types.TvwapWeighting{Mode: types.TvwapWeightingUniform})
+	linear := tvwap(types.TvwapWeighting{Mode: types.TvwapWeightingLinear})
+	exponential := tvwap(types.TvwapWeighting{Mode: types.TvwapWeightingExponential, HalfLife: time.Minute})
+	slowExponential := tvwap(types.TvwapWeighting{Mode: types.TvwapWeightingExponential, HalfLife: 24 * time.Hour})
+
+	// the linear weighting is the default
+	require.Equal(t, defaultTvwap[ATOMUSD], linear)
+	require.Equal(t, sdk.MustNewDecFromStr("15"), uniform)
+
+	// the time weighted modes favor the most recent candle
+	require.True(t, linear.GT(uniform), "linear %s <= uniform %s", linear, uniform)
+	require.True(t, exponential.GT(uniform), "exponential %s <= uniform %s", exponential, uniform)
+
+	// weights of 2^-8 and 2^-1: (10 * 2^-8 + 20 * 2^-1) / (2^-8 + 2^-1) = 2570 / 129
+	require.InDelta(t, 2570.0/129.0, exponential.MustFloat64(), 0.000001)
+
+	// a long half-life is close to the uniform weighting
+	require.InDelta(t, 15, slowExponential.MustFloat64(), 0.01)
+
+	// a twap window shorter than the default period leaves out the older candle
+	require.Equal(t, sdk.MustNewDecFromStr("20"), tvwap(types.TvwapWeighting{Window: 5 * time.Minute}))
+}
+
+func TestComputeWeightedTVWAP_Combination(t *testing.T) {
+	provider.SetClock(provider.FixedClock(time.Unix(1687944835, 0)))
+	defer provider.SetClock(provider.SystemClock{})
+
+	candles := types.AggregatedProviderCandles{
+		provider.ProviderBinance: {
+			ATOMUSD: []types.CandlePrice{{
+				Price:     sdk.MustNewDecFromStr("10"),
+				Volume:    sdk.MustNewDecFromStr("9000"),
+				TimeStamp: provider.PastUnixTime(1 * time.Minute),
+			}},
+		},
+		provider.ProviderKraken: {
+			ATOMUSD: []types.CandlePrice{{
+				Price:     sdk.MustNewDecFromStr("20"),
+				Volume:    sdk.MustNewDecFromStr("1000"),
+				TimeStamp: provider.PastUnixTime(1 * time.Minute),
+			}},
+		},
+	}
+	tvwap := func(combination types.TvwapCombination, weights map[types.ProviderName]sdk.Dec) sdk.Dec {
+		prices, err := oracle.ComputeWeightedTVWAP(candles, map[string]types.TvwapWeighting{
+			"ATOM": {Mode: types.TvwapWeightingUniform, Combination: combination, ProviderWeights: weights},
+		})
+		require.NoError(t, err)
+		return prices[ATOMUSD]
+	}
+
+	// the high volume provider dominates the volume combination, the default
+	require.Equal(t, sdk.MustNewDecFromStr("11"), tvwap("", nil))
+	require.Equal(t, sdk.MustNewDecFromStr("11"), tvwap(types.TvwapCombinationVolume, nil))
+
+	// the TVWAPs of the same candles weigh the same in the equal combination
+	require.Equal(t, sdk.MustNewDecFromStr("15"), tvwap(types.TvwapCombinationEqual, nil))
+
+	// (10 * 1 + 20 * 3) / (1 + 3)
+	require.Equal(t, sdk.MustNewDecFromStr("17.5"), tvwap(types.TvwapCombinationWeighted, map[types.ProviderName]sdk.Dec{
+		provider.ProviderBinance: sdk.OneDec(),
+		provider.ProviderKraken:  sdk.NewDec(3),
+	}))
+
+	// providers without a weight are left out of the weighted combination
+	require.Equal(t, sdk.MustNewDecFromStr("20"), tvwap(types.TvwapCombinationWeighted, map[types.ProviderName]sdk.Dec{
+		provider.ProviderKraken: sdk.NewDec(3),
+	}))
+}
+
 func TestStandardDeviation(t *testing.T) {
 	type deviation struct {
 		mean      sdk.Dec
@@ -369,6 +620,25 @@ func TestStandardDeviation(t *testing.T) {
 				},
 			},
 		},
+		"tiny prices": {
+			prices: types.CurrencyPairDecByProvider{
+				provider.ProviderBinance: {
+					OJOUSD: sdk.MustNewDecFromStr("0.0000012300"),
+				},
+				provider.ProviderKraken: {
+					OJOUSD: sdk.MustNewDecFromStr("0.0000012301"),
+				},
+				provider.ProviderOsmosis: {
+					OJOUSD: sdk.MustNewDecFromStr("0.0000012302"),
+				},
+			},
+			expected: map[types.CurrencyPair]deviation{
+				OJOUSD: {
+					mean:      sdk.MustNewDecFromStr("0.0000012301"),
+					deviation: sdk.MustNewDecFromStr("0.000000000081649658"),
+				},
+			},
+		},
 	}
 
 	for name, tc := range testCases {
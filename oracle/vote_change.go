@@ -0,0 +1,94 @@
+package oracle
+
+import (
+	"sort"
+
+	"github.com/armon/go-metrics"
+	"github.com/cosmos/cosmos-sdk/telemetry"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+
+	"github.com/ojo-network/price-feeder/oracle/types"
+)
+
+// SetMaxVoteChanges sets the fraction of the previously voted price the voted
+// price of the bases may move by per voting period, ex. 0.05 for 5%. The
+// voted prices of other bases are not limited.
+func (o *Oracle) SetMaxVoteChanges(maxVoteChanges map[string]sdk.Dec) {
+	o.maxVoteChanges = maxVoteChanges
+}
+
+// clampedVotePrices returns the aggregated prices clamped to the max vote
+// changes of their bases from the last voted prices, counting and logging
+// every clamped pair.
+func (o *Oracle) clampedVotePrices() types.CurrencyPairDec {
+	prices, clamped := clampVotePrices(o.prices, o.lastVotePrices, o.maxVoteChanges)
+	for _, cp := range clamped {
+		telemetry.IncrCounterWithLabels(
+			[]string{"vote", "clamped"},
+			1,
+			[]metrics.Label{{Name: "pair", Value: cp.String()}},
+		)
+		o.logger.Warn().
+			Str("pair", cp.String()).
+			Str("price", o.prices[cp].String()).
+			Str("last_vote_price", o.lastVotePrices[cp].String()).
+			Str("vote_price", prices[cp].String()).
+			Msg("clamped the vote price to the max vote change")
+	}
+	return prices
+}
+
+// recordVotePrices stores the voted prices as the last voted price of their
+// pairs. The last voted price of a pair missing from the vote is kept, so its
+// price is still clamped once it returns.
+func (o *Oracle) recordVotePrices(prices types.CurrencyPairDec) {
+	if o.lastVotePrices == nil {
+		o.lastVotePrices = make(types.CurrencyPairDec, len(prices))
+	}
+	for cp, price := range prices {
+		o.lastVotePrices[cp] = price
+	}
+}
+
+// clampVotePrices returns the prices with the price of every base with a max
+// vote change clamped within that fraction of its last voted price, and the
+// clamped pairs. Pairs without a last voted price are not clamped, so the
+// first vote of a pair after a start moves freely. It applies to the
+// aggregated prices, so it runs before they are scaled and rounded.
+func clampVotePrices(
+	prices types.CurrencyPairDec,
+	lastPrices types.CurrencyPairDec,
+	maxChanges map[string]sdk.Dec,
+) (types.CurrencyPairDec, []types.CurrencyPair) {
+	if len(maxChanges) == 0 || len(lastPrices) == 0 {
+		return prices, nil
+	}
+
+	var clamped []types.CurrencyPair
+	result := make(types.CurrencyPairDec, len(prices))
+	for cp, price := range prices {
+		result[cp] = price
+
+		maxChange, ok := maxChanges[cp.Base]
+		if !ok {
+			continue
+		}
+		lastPrice, ok := lastPrices[cp]
+		if !ok || !lastPrice.IsPositive() {
+			continue
+		}
+
+		maxDelta := lastPrice.Mul(maxChange)
+		switch {
+		case price.GT(lastPrice.Add(maxDelta)):
+			result[cp] = lastPrice.Add(maxDelta)
+		case price.LT(lastPrice.Sub(maxDelta)):
+			result[cp] = lastPrice.Sub(maxDelta)
+		default:
+			continue
+		}
+		clamped = append(clamped, cp)
+	}
+	sort.Slice(clamped, func(i, j int) bool { return clamped[i].String() < clamped[j].String() })
+	return result, clamped
+}
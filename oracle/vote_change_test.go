@@ -0,0 +1,103 @@
+package oracle
+
+import (
+	"testing"
+	"time"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ojo-network/price-feeder/oracle/client"
+	"github.com/ojo-network/price-feeder/oracle/provider"
+	"github.com/ojo-network/price-feeder/oracle/types"
+)
+
+func TestClampVotePrices(t *testing.T) {
+	atom := types.CurrencyPair{Base: "ATOM", Quote: "USD"}
+	ojo := types.CurrencyPair{Base: "OJO", Quote: "USD"}
+	umee := types.CurrencyPair{Base: "UMEE", Quote: "USD"}
+	maxChanges := map[string]sdk.Dec{
+		"ATOM": sdk.MustNewDecFromStr("0.05"),
+		"OJO":  sdk.MustNewDecFromStr("0.05"),
+		"UMEE": sdk.MustNewDecFromStr("0.05"),
+	}
+
+	// nothing is clamped without a last vote
+	prices := types.CurrencyPairDec{atom: sdk.MustNewDecFromStr("20")}
+	clamped, pairs := clampVotePrices(prices, nil, maxChanges)
+	require.Equal(t, prices, clamped)
+	require.Empty(t, pairs)
+
+	lastPrices := types.CurrencyPairDec{
+		atom: sdk.MustNewDecFromStr("10"),
+		ojo:  sdk.MustNewDecFromStr("10"),
+		umee: sdk.MustNewDecFromStr("10"),
+	}
+	prices = types.CurrencyPairDec{
+		// a step change up and down is clamped to 5%
+		atom: sdk.MustNewDecFromStr("20"),
+		ojo:  sdk.MustNewDecFromStr("5"),
+		// a change within 5% is voted as is
+		umee: sdk.MustNewDecFromStr("10.4"),
+	}
+	clamped, pairs = clampVotePrices(prices, lastPrices, maxChanges)
+	require.Equal(t, []types.CurrencyPair{atom, ojo}, pairs)
+	require.Equal(t, sdk.MustNewDecFromStr("10.5"), clamped[atom])
+	require.Equal(t, sdk.MustNewDecFromStr("9.5"), clamped[ojo])
+	require.Equal(t, sdk.MustNewDecFromStr("10.4"), clamped[umee])
+
+	// the clamped price is the reference of the next period, so a step change
+	// is followed gradually
+	clamped, pairs = clampVotePrices(prices, clamped, maxChanges)
+	require.Equal(t, []types.CurrencyPair{atom, ojo}, pairs)
+	require.Equal(t, sdk.MustNewDecFromStr("11.025"), clamped[atom])
+	require.Equal(t, sdk.MustNewDecFromStr("9.025"), clamped[ojo])
+
+	// bases without a max vote change are not clamped
+	clamped, pairs = clampVotePrices(prices, lastPrices, map[string]sdk.Dec{"UMEE": sdk.MustNewDecFromStr("0.01")})
+	require.Equal(t, []types.CurrencyPair{umee}, pairs)
+	require.Equal(t, sdk.MustNewDecFromStr("20"), clamped[atom])
+	require.Equal(t, sdk.MustNewDecFromStr("10.1"), clamped[umee])
+
+	// the input prices are left unclamped
+	require.Equal(t, sdk.MustNewDecFromStr("20"), prices[atom])
+}
+
+func TestOracle_recordVotePrices(t *testing.T) {
+	atom := types.CurrencyPair{Base: "ATOM", Quote: "USD"}
+	ojo := types.CurrencyPair{Base: "OJO", Quote: "USD"}
+	o := New(
+		zerolog.Nop(),
+		client.OracleClient{},
+		map[types.ProviderName][]types.CurrencyPair{},
+		time.Millisecond*100,
+		make(map[string]sdk.Dec),
+		make(map[types.ProviderName]provider.Endpoint),
+	)
+	o.SetMaxVoteChanges(map[string]sdk.Dec{
+		"ATOM": sdk.MustNewDecFromStr("0.05"),
+		"OJO":  sdk.MustNewDecFromStr("0.05"),
+	})
+
+	o.prices = types.CurrencyPairDec{
+		atom: sdk.MustNewDecFromStr("10"),
+		ojo:  sdk.MustNewDecFromStr("10"),
+	}
+	o.recordVotePrices(o.clampedVotePrices())
+
+	// ATOM goes missing for a voting period
+	o.prices = types.CurrencyPairDec{ojo: sdk.MustNewDecFromStr("10.2")}
+	o.recordVotePrices(o.clampedVotePrices())
+	require.Equal(t, sdk.MustNewDecFromStr("10"), o.lastVotePrices[atom])
+	require.Equal(t, sdk.MustNewDecFromStr("10.2"), o.lastVotePrices[ojo])
+
+	// once it returns, its price is still clamped to its last voted price
+	o.prices = types.CurrencyPairDec{
+		atom: sdk.MustNewDecFromStr("20"),
+		ojo:  sdk.MustNewDecFromStr("10.2"),
+	}
+	clamped := o.clampedVotePrices()
+	require.Equal(t, sdk.MustNewDecFromStr("10.5"), clamped[atom])
+	require.Equal(t, sdk.MustNewDecFromStr("10.2"), clamped[ojo])
+}
@@ -0,0 +1,94 @@
+package oracle
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/armon/go-metrics"
+	"github.com/cosmos/cosmos-sdk/telemetry"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	oracletypes "github.com/umee-network/umee/v6/x/oracle/types"
+)
+
+// GetExchangeRates returns the current on-chain exchange rates of the x/oracle
+// module, the median of the votes of the last tallied voting period.
+func (o *Oracle) GetExchangeRates(ctx context.Context) (sdk.DecCoins, error) {
+	queryClient, closeConn, err := o.oracleQueryClient()
+	if err != nil {
+		return nil, err
+	}
+	defer closeConn()
+
+	ctx, cancel := context.WithTimeout(ctx, 15*time.Second)
+	defer cancel()
+
+	queryResponse, err := queryClient.ExchangeRates(ctx, &oracletypes.QueryExchangeRates{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get x/oracle exchange rates: %w", err)
+	}
+
+	return queryResponse.ExchangeRates, nil
+}
+
+// reportVoteDivergence reports the divergence of the exchange rates revealed
+// by the last vote from the on-chain exchange rates once its voting period was
+// tallied, i.e. in any later voting period. The query runs in the background
+// so it never holds up the tick.
+func (o *Oracle) reportVoteDivergence(ctx context.Context, currentVotePeriod float64) {
+	if o.revealedRates == "" || currentVotePeriod <= o.revealedVotePeriod {
+		return
+	}
+
+	revealedRates := o.revealedRates
+	o.revealedRates = ""
+
+	go func() {
+		voted, err := oracletypes.ParseExchangeRateTuples(revealedRates)
+		if err != nil {
+			o.logger.Err(err).Msg("failed to parse voted exchange rates")
+			return
+		}
+
+		onChain, err := o.GetExchangeRates(ctx)
+		if err != nil {
+			o.logger.Err(err).Msg("failed to get on-chain exchange rates")
+			return
+		}
+
+		for denom, divergence := range voteDivergences(voted, onChain) {
+			telemetry.SetGaugeWithLabels(
+				[]string{"vote", "median_divergence"},
+				float32(divergence.MustFloat64()),
+				[]metrics.Label{{Name: "denom", Value: denom}},
+			)
+			o.logger.Debug().
+				Str("denom", denom).
+				Str("divergence", divergence.String()).
+				Msg("voted exchange rate divergence from on-chain median")
+		}
+	}()
+}
+
+// voteDivergences returns the relative divergence of each voted exchange rate
+// from the on-chain exchange rate of its denom, (voted - on-chain) / on-chain,
+// by upper case denom. Denoms without a positive on-chain exchange rate are
+// omitted.
+func voteDivergences(voted oracletypes.ExchangeRateTuples, onChain sdk.DecCoins) map[string]sdk.Dec {
+	medians := make(map[string]sdk.Dec, len(onChain))
+	for _, rate := range onChain {
+		medians[strings.ToUpper(rate.Denom)] = rate.Amount
+	}
+
+	divergences := make(map[string]sdk.Dec, len(voted))
+	for _, tuple := range voted {
+		denom := strings.ToUpper(tuple.Denom)
+		median, ok := medians[denom]
+		if !ok || !median.IsPositive() {
+			continue
+		}
+		divergences[denom] = tuple.ExchangeRate.Sub(median).Quo(median)
+	}
+	return divergences
+}
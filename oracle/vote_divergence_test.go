@@ -0,0 +1,28 @@
+package oracle
+
+import (
+	"testing"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/stretchr/testify/require"
+	oracletypes "github.com/umee-network/umee/v6/x/oracle/types"
+)
+
+func TestVoteDivergences(t *testing.T) {
+	voted, err := oracletypes.ParseExchangeRateTuples(
+		"ATOM:10.200000000000000000,OJO:0.009000000000000000,UMEE:0.010000000000000000",
+	)
+	require.NoError(t, err)
+
+	onChain := sdk.NewDecCoins(
+		sdk.NewDecCoinFromDec("ATOM", sdk.MustNewDecFromStr("10")),
+		sdk.NewDecCoinFromDec("ojo", sdk.MustNewDecFromStr("0.01")),
+	)
+
+	// the voted exchange rates diverge relative to the on-chain median, and
+	// denoms without an on-chain exchange rate are omitted
+	require.Equal(t, map[string]sdk.Dec{
+		"ATOM": sdk.MustNewDecFromStr("0.02"),
+		"OJO":  sdk.MustNewDecFromStr("-0.1"),
+	}, voteDivergences(voted, onChain))
+}
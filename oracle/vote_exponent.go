@@ -0,0 +1,27 @@
+package oracle
+
+import (
+	sdk "github.com/cosmos/cosmos-sdk/types"
+
+	"github.com/ojo-network/price-feeder/oracle/types"
+)
+
+// scaleVotePrices returns the prices with the price of every base with a vote
+// exponent multiplied by 10^exponent, so the vote is submitted in the unit the
+// chain expects, ex. micro-units for an exponent of 6.
+func scaleVotePrices(prices types.CurrencyPairDec, exponents map[string]int) types.CurrencyPairDec {
+	if len(exponents) == 0 {
+		return prices
+	}
+
+	scaled := make(types.CurrencyPairDec, len(prices))
+	for cp, price := range prices {
+		exponent, ok := exponents[cp.Base]
+		if !ok || exponent == 0 {
+			scaled[cp] = price
+			continue
+		}
+		scaled[cp] = price.Mul(sdk.NewDec(10).Power(uint64(exponent)))
+	}
+	return scaled
+}
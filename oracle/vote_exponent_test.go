@@ -0,0 +1,54 @@
+package oracle
+
+import (
+	"testing"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ojo-network/price-feeder/oracle/types"
+)
+
+func TestScaleVotePrices(t *testing.T) {
+	prices := types.CurrencyPairDec{
+		{Base: "ATOM", Quote: "USD"}: sdk.MustNewDecFromStr("1.23"),
+		{Base: "OJO", Quote: "USD"}:  sdk.MustNewDecFromStr("1.23"),
+	}
+
+	testCases := []struct {
+		name          string
+		exponents     map[string]int
+		exchangeRates string
+	}{
+		{
+			"no exponents",
+			nil,
+			"ATOM:1.230000000000000000,OJO:1.230000000000000000",
+		},
+		{
+			"micro-units",
+			map[string]int{"ATOM": 6},
+			"ATOM:1230000.000000000000000000,OJO:1.230000000000000000",
+		},
+		{
+			"nano-units",
+			map[string]int{"ATOM": 9, "OJO": 6},
+			"ATOM:1230000000.000000000000000000,OJO:1230000.000000000000000000",
+		},
+		{
+			"max exponent",
+			map[string]int{"OJO": sdk.Precision},
+			"ATOM:1.230000000000000000,OJO:1230000000000000000.000000000000000000",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			scaled := scaleVotePrices(prices, tc.exponents)
+			require.Equal(t, tc.exchangeRates, GenerateExchangeRatesString(scaled))
+		})
+	}
+
+	// the input prices are left in human units
+	require.Equal(t, sdk.MustNewDecFromStr("1.23"), prices[types.CurrencyPair{Base: "ATOM", Quote: "USD"}])
+}
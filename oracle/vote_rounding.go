@@ -0,0 +1,25 @@
+package oracle
+
+import (
+	"github.com/ojo-network/price-feeder/oracle/types"
+)
+
+// roundVotePrices returns the prices with the price of every base with a vote
+// rounding rounded to its decimals. It applies to the vote values, so it runs
+// after the prices are scaled by the vote exponents.
+func roundVotePrices(prices types.CurrencyPairDec, roundings map[string]types.VoteRounding) types.CurrencyPairDec {
+	if len(roundings) == 0 {
+		return prices
+	}
+
+	rounded := make(types.CurrencyPairDec, len(prices))
+	for cp, price := range prices {
+		rounding, ok := roundings[cp.Base]
+		if !ok {
+			rounded[cp] = price
+			continue
+		}
+		rounded[cp] = rounding.Round(price)
+	}
+	return rounded
+}
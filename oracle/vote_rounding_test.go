@@ -0,0 +1,32 @@
+package oracle
+
+import (
+	"testing"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ojo-network/price-feeder/oracle/types"
+)
+
+func TestRoundVotePrices(t *testing.T) {
+	prices := types.CurrencyPairDec{
+		{Base: "ATOM", Quote: "USD"}: sdk.MustNewDecFromStr("1.2345"),
+		{Base: "OJO", Quote: "USD"}:  sdk.MustNewDecFromStr("1.2345"),
+	}
+
+	require.Equal(t, prices, roundVotePrices(prices, nil))
+
+	// the rounding applies to the scaled vote values
+	rounded := roundVotePrices(
+		scaleVotePrices(prices, map[string]int{"ATOM": 3}),
+		map[string]types.VoteRounding{
+			"ATOM": {Decimals: 0, Mode: types.RoundDown},
+			"OJO":  {Decimals: 3, Mode: types.RoundHalfUp},
+		},
+	)
+	require.Equal(t, "ATOM:1234.000000000000000000,OJO:1.235000000000000000", GenerateExchangeRatesString(rounded))
+
+	// the input prices are left unrounded
+	require.Equal(t, sdk.MustNewDecFromStr("1.2345"), prices[types.CurrencyPair{Base: "OJO", Quote: "USD"}])
+}
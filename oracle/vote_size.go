@@ -0,0 +1,53 @@
+package oracle
+
+import (
+	"sort"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+
+	"github.com/ojo-network/price-feeder/oracle/types"
+)
+
+// trimVotePrices drops prices from the vote, lowest priority first, until
+// the size of the vote of the remaining prices returned by voteSize fits
+// maxSize. Bases without a priority are dropped before any other, and bases
+// of the same priority in reverse alphabetical order, so the trimming is
+// deterministic. It returns the remaining prices and the dropped bases.
+func trimVotePrices(
+	prices types.CurrencyPairDec,
+	priorities map[string]int,
+	maxSize int,
+	voteSize func(exchangeRates string) int,
+) (types.CurrencyPairDec, []string) {
+	trimmed := make(types.CurrencyPairDec, len(prices))
+	order := make([]types.CurrencyPair, 0, len(prices))
+	for cp, price := range prices {
+		trimmed[cp] = price
+		order = append(order, cp)
+	}
+	sort.Slice(order, func(i, j int) bool {
+		pi, pj := priorities[order[i].Base], priorities[order[j].Base]
+		if pi != pj {
+			return pi < pj
+		}
+		return order[i].Base > order[j].Base
+	})
+
+	dropped := []string{}
+	for _, cp := range order {
+		if voteSize(GenerateExchangeRatesString(trimmed)) <= maxSize {
+			break
+		}
+		delete(trimmed, cp)
+		dropped = append(dropped, cp.Base)
+	}
+	return trimmed, dropped
+}
+
+// voteMsgSize returns the encoded size of a vote message.
+func voteMsgSize(msg sdk.Msg) int {
+	if sized, ok := msg.(interface{ Size() int }); ok {
+		return sized.Size()
+	}
+	return len(msg.String())
+}
@@ -0,0 +1,99 @@
+package oracle
+
+import (
+	"testing"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ojo-network/price-feeder/oracle/client"
+	"github.com/ojo-network/price-feeder/oracle/types"
+)
+
+func TestTrimVotePrices(t *testing.T) {
+	prices := types.CurrencyPairDec{
+		{Base: "ATOM", Quote: "USD"}: sdk.MustNewDecFromStr("10.5"),
+		{Base: "BTC", Quote: "USD"}:  sdk.MustNewDecFromStr("60000"),
+		{Base: "ETH", Quote: "USD"}:  sdk.MustNewDecFromStr("3000"),
+		{Base: "JUNO", Quote: "USD"}: sdk.MustNewDecFromStr("0.2"),
+		{Base: "OSMO", Quote: "USD"}: sdk.MustNewDecFromStr("0.5"),
+	}
+	priorities := map[string]int{
+		"ATOM": 5,
+		"BTC":  9,
+		"ETH":  9,
+		"JUNO": 1,
+		"OSMO": 5,
+	}
+
+	// every rate is "<BASE>:<price with 18 decimals>" joined by commas
+	rateSize := func(base string, price string) int {
+		return len(base) + 1 + len(sdk.MustNewDecFromStr(price).String())
+	}
+	voteSize := func(exchangeRates string) int {
+		return len(exchangeRates)
+	}
+	fullSize := voteSize(GenerateExchangeRatesString(prices))
+
+	testCases := []struct {
+		name    string
+		maxSize int
+		dropped []string
+	}{
+		{
+			"vote fits",
+			fullSize,
+			[]string{},
+		},
+		{
+			"lowest priority dropped first",
+			fullSize - 1,
+			[]string{"JUNO"},
+		},
+		{
+			"same priority dropped in reverse alphabetical order",
+			fullSize - rateSize("JUNO", "0.2") - 2,
+			[]string{"JUNO", "OSMO"},
+		},
+		{
+			"highest priority kept longest",
+			rateSize("BTC", "60000"),
+			[]string{"JUNO", "OSMO", "ATOM", "ETH"},
+		},
+		{
+			"everything dropped",
+			0,
+			[]string{"JUNO", "OSMO", "ATOM", "ETH", "BTC"},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			trimmed, dropped := trimVotePrices(prices, priorities, tc.maxSize, voteSize)
+			require.Equal(t, tc.dropped, dropped)
+			require.Len(t, trimmed, len(prices)-len(tc.dropped))
+			require.LessOrEqual(t, voteSize(GenerateExchangeRatesString(trimmed)), tc.maxSize)
+			for _, base := range dropped {
+				require.NotContains(t, trimmed, types.CurrencyPair{Base: base, Quote: "USD"})
+			}
+		})
+	}
+
+	// the input prices are left untouched
+	require.Len(t, prices, 5)
+}
+
+func TestVoteMsgSize(t *testing.T) {
+	voteBuilder := client.UmeeVoteBuilder{}
+	salt := "1234567890abcdef1234567890abcdef1234567890abcdef1234567890abcdef"
+
+	small := voteMsgSize(voteBuilder.VoteMsg(salt, "ATOM:10.500000000000000000", "feeder", "validator"))
+	large := voteMsgSize(voteBuilder.VoteMsg(
+		salt,
+		"ATOM:10.500000000000000000,BTC:60000.000000000000000000",
+		"feeder",
+		"validator",
+	))
+	require.Greater(t, small, len(salt))
+	require.Equal(t, len(",BTC:60000.000000000000000000"), large-small)
+}
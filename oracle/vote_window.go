@@ -0,0 +1,43 @@
+package oracle
+
+import (
+	"time"
+)
+
+// SetVoteSafetyMargin sets the minimum time that must remain in the voting
+// period for a pre-vote or vote to be broadcast. A broadcast closer to the end
+// of the voting period is skipped as a near miss rather than risking its
+// inclusion in the next one. A zero margin disables the guard.
+func (o *Oracle) SetVoteSafetyMargin(margin time.Duration) {
+	o.voteSafetyMargin = margin
+}
+
+// remainingVoteWindow estimates the time left to include a transaction in the
+// voting period, which closes when its last block is proposed, one block time
+// before it is received. blocksRemaining counts the blocks of the voting
+// period after the last received block.
+func remainingVoteWindow(
+	blocksRemaining int64,
+	lastBlockReceived time.Time,
+	blockTime time.Duration,
+	now time.Time,
+) time.Duration {
+	return lastBlockReceived.Add(time.Duration(blocksRemaining-1) * blockTime).Sub(now)
+}
+
+// checkVoteWindow returns the estimated time remaining in the voting period,
+// and false if it is below the vote safety margin. The guard passes if it is
+// disabled or the block time is not known yet.
+func (o *Oracle) checkVoteWindow(
+	blocksRemaining int64,
+	lastBlockReceived time.Time,
+	blockTime time.Duration,
+	now time.Time,
+) (time.Duration, bool) {
+	if o.voteSafetyMargin <= 0 || blockTime <= 0 || lastBlockReceived.IsZero() {
+		return 0, true
+	}
+
+	remaining := remainingVoteWindow(blocksRemaining, lastBlockReceived, blockTime, now)
+	return remaining, remaining >= o.voteSafetyMargin
+}
@@ -0,0 +1,56 @@
+package oracle
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestRemainingVoteWindow(t *testing.T) {
+	lastBlock := time.Unix(1700000000, 0)
+
+	// the window closes when the last block is proposed, a block time before
+	// it is received
+	require.Equal(t, 10*time.Second, remainingVoteWindow(3, lastBlock, 5*time.Second, lastBlock))
+	require.Equal(t, 7*time.Second, remainingVoteWindow(3, lastBlock, 5*time.Second, lastBlock.Add(3*time.Second)))
+	require.Equal(t, -time.Second, remainingVoteWindow(2, lastBlock, 5*time.Second, lastBlock.Add(6*time.Second)))
+}
+
+func TestOracle_checkVoteWindow(t *testing.T) {
+	lastBlock := time.Unix(1700000000, 0)
+	blockTime := 5 * time.Second
+	o := &Oracle{}
+
+	// the guard is disabled by default
+	_, ok := o.checkVoteWindow(2, lastBlock, blockTime, lastBlock.Add(time.Hour))
+	require.True(t, ok)
+
+	o.SetVoteSafetyMargin(2 * time.Second)
+	testCases := []struct {
+		name            string
+		blocksRemaining int64
+		elapsed         time.Duration
+		remaining       time.Duration
+		ok              bool
+	}{
+		{"well within the window", 4, time.Second, 14 * time.Second, true},
+		{"exactly the margin", 2, 3 * time.Second, 2 * time.Second, true},
+		{"a millisecond past the margin", 2, 3*time.Second + time.Millisecond, 2*time.Second - time.Millisecond, false},
+		{"window closed", 2, 6 * time.Second, -time.Second, false},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			remaining, ok := o.checkVoteWindow(tc.blocksRemaining, lastBlock, blockTime, lastBlock.Add(tc.elapsed))
+			require.Equal(t, tc.remaining, remaining)
+			require.Equal(t, tc.ok, ok)
+		})
+	}
+
+	// the guard passes until the block time is known
+	_, ok = o.checkVoteWindow(2, lastBlock, 0, lastBlock.Add(time.Hour))
+	require.True(t, ok)
+	_, ok = o.checkVoteWindow(2, time.Time{}, blockTime, lastBlock.Add(time.Hour))
+	require.True(t, ok)
+}
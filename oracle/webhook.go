@@ -0,0 +1,258 @@
+package oracle
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"time"
+
+	"github.com/cosmos/cosmos-sdk/telemetry"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/rs/zerolog"
+
+	"github.com/ojo-network/price-feeder/oracle/types"
+)
+
+const (
+	// webhookQueueSize is the number of alerts waiting to be posted past which
+	// new alerts are dropped.
+	webhookQueueSize = 16
+)
+
+// AlertPriceChange, AlertOmission and AlertPriceBand are the kinds of webhook
+// alerts.
+const (
+	AlertPriceChange = "price_change"
+	AlertOmission    = "omission"
+	AlertPriceBand   = "price_band"
+)
+
+type (
+	// WebhookTriggers defines the conditions firing a webhook alert. Price
+	// change alerts fire when the aggregated price of a pair moves by more
+	// than PriceChange, a fraction of the price, from the previous voting
+	// period, and are disabled if it is nil.
+	WebhookTriggers struct {
+		PriceChange sdk.Dec
+		Omission    bool
+		PriceBand   bool
+	}
+
+	// Webhook posts alerts on significant price moves, omitted pairs and
+	// prices outside of their band to a URL, evaluated once per voting period
+	// from the aggregated prices. Alerts are rate limited per pair and kind,
+	// queued and posted in the background, so a slow or unavailable endpoint
+	// never delays the vote.
+	Webhook struct {
+		logger    zerolog.Logger
+		url       string
+		client    *http.Client
+		triggers  WebhookTriggers
+		rateLimit time.Duration
+
+		queue          chan WebhookAlert
+		lastSent       map[string]time.Time
+		lastVotePeriod int64
+		lastPrices     types.CurrencyPairDec
+	}
+
+	// WebhookAlert defines the JSON body of an alert.
+	WebhookAlert struct {
+		Kind          string    `json:"kind"` // price_change|omission|price_band
+		Pair          string    `json:"pair"`
+		Price         *sdk.Dec  `json:"price,omitempty"`
+		PreviousPrice *sdk.Dec  `json:"previous_price,omitempty"`
+		Message       string    `json:"message"`
+		VotePeriod    int64     `json:"vote_period"`
+		Timestamp     time.Time `json:"timestamp"`
+	}
+)
+
+// NewWebhook returns a Webhook posting the alerts fired by the triggers to
+// url, at most once per rateLimit for an alert of a pair and kind. A post
+// times out after timeout.
+func NewWebhook(
+	logger zerolog.Logger,
+	url string,
+	timeout time.Duration,
+	triggers WebhookTriggers,
+	rateLimit time.Duration,
+) *Webhook {
+	return &Webhook{
+		logger:    logger.With().Str("module", "webhook").Logger(),
+		url:       url,
+		client:    &http.Client{Timeout: timeout},
+		triggers:  triggers,
+		rateLimit: rateLimit,
+		queue:     make(chan WebhookAlert, webhookQueueSize),
+		lastSent:  make(map[string]time.Time),
+	}
+}
+
+// SetWebhook sets the webhook alerts are posted to. A nil webhook disables
+// the alerts.
+func (o *Oracle) SetWebhook(webhook *Webhook) {
+	o.webhook = webhook
+}
+
+// checkAlerts evaluates the webhook triggers against the aggregated prices of
+// the voting period, unless no webhook is set.
+func (o *Oracle) checkAlerts(votePeriod int64) {
+	if o.webhook == nil {
+		return
+	}
+	o.webhook.Check(votePeriod, o.now(), o.GetPrices(), o.RequiredRates(), o.priceBands)
+}
+
+// Start posts the queued alerts until the context is canceled.
+func (w *Webhook) Start(ctx context.Context) {
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case alert := <-w.queue:
+			if err := w.post(ctx, alert); err != nil {
+				telemetry.IncrCounter(1, "webhook", "failure")
+				w.logger.Err(err).Str("kind", alert.Kind).Str("pair", alert.Pair).Msg("failed to post alert")
+				continue
+			}
+			w.logger.Debug().Str("kind", alert.Kind).Str("pair", alert.Pair).Msg("posted alert")
+		}
+	}
+}
+
+// Check queues the alerts fired by the prices of the voting period without
+// blocking. Voting periods already checked are ignored, and alerts of a pair
+// and kind posted within the rate limit are skipped.
+func (w *Webhook) Check(
+	votePeriod int64,
+	now time.Time,
+	prices types.CurrencyPairDec,
+	expected []types.CurrencyPair,
+	priceBands map[string]types.PriceBand,
+) {
+	if votePeriod <= w.lastVotePeriod {
+		return
+	}
+	w.lastVotePeriod = votePeriod
+
+	alerts := w.triggers.evaluate(w.lastPrices, prices, expected, priceBands)
+	w.lastPrices = prices
+
+	for _, alert := range alerts {
+		key := alert.Kind + "/" + alert.Pair
+		if sent, ok := w.lastSent[key]; ok && now.Sub(sent) < w.rateLimit {
+			continue
+		}
+
+		alert.VotePeriod = votePeriod
+		alert.Timestamp = now.UTC()
+		select {
+		case w.queue <- alert:
+			w.lastSent[key] = now
+		default:
+			telemetry.IncrCounter(1, "webhook", "failure")
+			w.logger.Warn().Str("kind", alert.Kind).Str("pair", alert.Pair).Msg("dropped alert; webhook is falling behind")
+		}
+	}
+}
+
+// evaluate returns the alerts fired by the prices, given the prices of the
+// previous voting period, the pairs expected to be priced and the price bands,
+// sorted by kind and pair.
+func (t WebhookTriggers) evaluate(
+	previous types.CurrencyPairDec,
+	prices types.CurrencyPairDec,
+	expected []types.CurrencyPair,
+	priceBands map[string]types.PriceBand,
+) []WebhookAlert {
+	var alerts []WebhookAlert
+
+	if !t.PriceChange.IsNil() {
+		for cp, price := range prices {
+			previousPrice, ok := previous[cp]
+			if !ok || !previousPrice.IsPositive() {
+				continue
+			}
+			change := price.Sub(previousPrice).Quo(previousPrice)
+			if change.Abs().GT(t.PriceChange) {
+				price, previousPrice := price, previousPrice
+				alerts = append(alerts, WebhookAlert{
+					Kind:          AlertPriceChange,
+					Pair:          cp.String(),
+					Price:         &price,
+					PreviousPrice: &previousPrice,
+					Message: fmt.Sprintf(
+						"price of %s moved by %.2f%% from the previous voting period",
+						cp,
+						change.MustFloat64()*100,
+					),
+				})
+			}
+		}
+	}
+
+	if t.Omission {
+		for _, cp := range expected {
+			if _, ok := prices[cp]; !ok {
+				alerts = append(alerts, WebhookAlert{
+					Kind:    AlertOmission,
+					Pair:    cp.String(),
+					Message: fmt.Sprintf("no price of %s to vote", cp),
+				})
+			}
+		}
+	}
+
+	if t.PriceBand {
+		for cp, price := range prices {
+			if band, ok := priceBands[cp.Base]; ok && !band.Contains(price) {
+				price := price
+				alerts = append(alerts, WebhookAlert{
+					Kind:    AlertPriceBand,
+					Pair:    cp.String(),
+					Price:   &price,
+					Message: fmt.Sprintf("price of %s is outside of its price band", cp),
+				})
+			}
+		}
+	}
+
+	sort.Slice(alerts, func(i, j int) bool {
+		if alerts[i].Kind != alerts[j].Kind {
+			return alerts[i].Kind < alerts[j].Kind
+		}
+		return alerts[i].Pair < alerts[j].Pair
+	})
+	return alerts
+}
+
+// post posts the alert to the webhook, failing on a non 2xx status.
+func (w *Webhook) post(ctx context.Context, alert WebhookAlert) error {
+	body, err := json.Marshal(alert)
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, w.url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := w.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	_, _ = io.Copy(io.Discard, resp.Body)
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf("webhook responded with status %d", resp.StatusCode)
+	}
+	return nil
+}
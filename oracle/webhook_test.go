@@ -0,0 +1,112 @@
+package oracle
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ojo-network/price-feeder/oracle/types"
+)
+
+func TestWebhookTriggers_evaluate(t *testing.T) {
+	atom := types.CurrencyPair{Base: "ATOM", Quote: "USD"}
+	ojo := types.CurrencyPair{Base: "OJO", Quote: "USD"}
+	umee := types.CurrencyPair{Base: "UMEE", Quote: "USD"}
+
+	previous := types.CurrencyPairDec{
+		atom: sdk.MustNewDecFromStr("10"),
+		ojo:  sdk.MustNewDecFromStr("1"),
+	}
+	prices := types.CurrencyPairDec{
+		// a 20% drop
+		atom: sdk.MustNewDecFromStr("8"),
+		// a 4% rise, outside of its band
+		ojo: sdk.MustNewDecFromStr("1.04"),
+	}
+	priceBands := map[string]types.PriceBand{"OJO": {Max: sdk.MustNewDecFromStr("1.02")}}
+	expected := []types.CurrencyPair{atom, ojo, umee}
+
+	triggers := WebhookTriggers{PriceChange: sdk.MustNewDecFromStr("0.05"), Omission: true, PriceBand: true}
+	alerts := triggers.evaluate(previous, prices, expected, priceBands)
+	require.Len(t, alerts, 3)
+
+	require.Equal(t, AlertOmission, alerts[0].Kind)
+	require.Equal(t, umee.String(), alerts[0].Pair)
+
+	require.Equal(t, AlertPriceBand, alerts[1].Kind)
+	require.Equal(t, ojo.String(), alerts[1].Pair)
+	require.Equal(t, sdk.MustNewDecFromStr("1.04"), *alerts[1].Price)
+
+	require.Equal(t, AlertPriceChange, alerts[2].Kind)
+	require.Equal(t, atom.String(), alerts[2].Pair)
+	require.Equal(t, sdk.MustNewDecFromStr("8"), *alerts[2].Price)
+	require.Equal(t, sdk.MustNewDecFromStr("10"), *alerts[2].PreviousPrice)
+	require.Equal(t, "price of ATOMUSD moved by -20.00% from the previous voting period", alerts[2].Message)
+
+	// no price change fires without previous prices
+	require.Empty(t, WebhookTriggers{PriceChange: sdk.MustNewDecFromStr("0.05")}.evaluate(nil, prices, expected, nil))
+
+	// disabled triggers never fire
+	require.Empty(t, WebhookTriggers{}.evaluate(previous, prices, expected, priceBands))
+}
+
+func TestWebhook_Check(t *testing.T) {
+	atom := types.CurrencyPair{Base: "ATOM", Quote: "USD"}
+	expected := []types.CurrencyPair{atom}
+	now := time.Unix(1700000000, 0)
+
+	w := NewWebhook(zerolog.Nop(), "http://localhost", time.Second, WebhookTriggers{Omission: true}, 10*time.Minute)
+
+	w.Check(1, now, types.CurrencyPairDec{}, expected, nil)
+	require.Len(t, w.queue, 1)
+	alert := <-w.queue
+	require.Equal(t, AlertOmission, alert.Kind)
+	require.Equal(t, int64(1), alert.VotePeriod)
+	require.Equal(t, now.UTC(), alert.Timestamp)
+
+	// a voting period is checked once
+	w.Check(1, now, types.CurrencyPairDec{}, expected, nil)
+	require.Empty(t, w.queue)
+
+	// the alert is rate limited
+	w.Check(2, now.Add(5*time.Minute), types.CurrencyPairDec{}, expected, nil)
+	require.Empty(t, w.queue)
+
+	w.Check(3, now.Add(10*time.Minute), types.CurrencyPairDec{}, expected, nil)
+	require.Len(t, w.queue, 1)
+}
+
+func TestWebhook_Start(t *testing.T) {
+	received := make(chan WebhookAlert, 1)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.Equal(t, "application/json", r.Header.Get("Content-Type"))
+		var alert WebhookAlert
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&alert))
+		received <- alert
+	}))
+	defer server.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	w := NewWebhook(zerolog.Nop(), server.URL, time.Second, WebhookTriggers{Omission: true}, time.Minute)
+	go w.Start(ctx)
+
+	w.Check(1, time.Now(), types.CurrencyPairDec{}, []types.CurrencyPair{{Base: "ATOM", Quote: "USD"}}, nil)
+
+	select {
+	case alert := <-received:
+		require.Equal(t, AlertOmission, alert.Kind)
+		require.Equal(t, "ATOMUSD", alert.Pair)
+		require.Equal(t, "no price of ATOMUSD to vote", alert.Message)
+	case <-time.After(5 * time.Second):
+		t.Fatal("alert was not posted")
+	}
+}
@@ -0,0 +1,175 @@
+package logrotate
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// backupTimeFormat is the format of the rotation time in the names of the
+// backup files, which sorts them chronologically and is valid on every OS.
+const backupTimeFormat = "2006-01-02T15-04-05.000"
+
+// Writer implements an io.WriteCloser writing to a file which is rotated once
+// a write would grow it beyond maxSize bytes. A rotated file is renamed after
+// the time of the rotation, e.g. "feeder-2024-01-02T15-04-05.000.log", and
+// removed once it is older than maxAge or not among the maxBackups most
+// recent backups. A zero maxSize, maxAge or maxBackups disables the
+// respective limit.
+type Writer struct {
+	filename   string
+	maxSize    int64
+	maxAge     time.Duration
+	maxBackups int
+
+	mtx  sync.Mutex
+	file *os.File
+	size int64
+	now  func() time.Time
+}
+
+// NewWriter opens, or creates, the file to append to and returns a Writer
+// rotating it. It returns an error if the file's directory is not writable.
+func NewWriter(filename string, maxSize int64, maxAge time.Duration, maxBackups int) (*Writer, error) {
+	w := &Writer{
+		filename:   filename,
+		maxSize:    maxSize,
+		maxAge:     maxAge,
+		maxBackups: maxBackups,
+		now:        time.Now,
+	}
+	if err := w.open(); err != nil {
+		return nil, err
+	}
+	return w, nil
+}
+
+// Write writes p to the file, rotating it first if p would grow it beyond
+// maxSize. A p larger than maxSize is written to a file of its own.
+func (w *Writer) Write(p []byte) (int, error) {
+	w.mtx.Lock()
+	defer w.mtx.Unlock()
+
+	if w.file == nil {
+		return 0, os.ErrClosed
+	}
+	if w.maxSize > 0 && w.size > 0 && w.size+int64(len(p)) > w.maxSize {
+		if err := w.rotate(); err != nil {
+			return 0, err
+		}
+	}
+
+	n, err := w.file.Write(p)
+	w.size += int64(n)
+	return n, err
+}
+
+// Close closes the file. Writes after Close return os.ErrClosed.
+func (w *Writer) Close() error {
+	w.mtx.Lock()
+	defer w.mtx.Unlock()
+
+	if w.file == nil {
+		return nil
+	}
+	err := w.file.Close()
+	w.file = nil
+	return err
+}
+
+// open opens the file to append to, creating it and its directory if needed.
+func (w *Writer) open() error {
+	if err := os.MkdirAll(filepath.Dir(w.filename), 0o755); err != nil {
+		return fmt.Errorf("failed to create log directory: %w", err)
+	}
+	file, err := os.OpenFile(w.filename, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0o644)
+	if err != nil {
+		return fmt.Errorf("failed to open log file: %w", err)
+	}
+	info, err := file.Stat()
+	if err != nil {
+		file.Close()
+		return fmt.Errorf("failed to stat log file: %w", err)
+	}
+
+	w.file = file
+	w.size = info.Size()
+	return nil
+}
+
+// rotate renames the current file to a backup, opens a new one and removes
+// the backups beyond the limits.
+func (w *Writer) rotate() error {
+	if err := w.file.Close(); err != nil {
+		return fmt.Errorf("failed to close log file: %w", err)
+	}
+	w.file = nil
+
+	if err := os.Rename(w.filename, w.backupName(w.now())); err != nil {
+		return fmt.Errorf("failed to rotate log file: %w", err)
+	}
+	if err := w.open(); err != nil {
+		return err
+	}
+	return w.removeBackups()
+}
+
+// backupName returns the name of the backup of the file rotated at t.
+func (w *Writer) backupName(t time.Time) string {
+	prefix, ext := w.prefixAndExt()
+	return prefix + t.UTC().Format(backupTimeFormat) + ext
+}
+
+func (w *Writer) prefixAndExt() (string, string) {
+	ext := filepath.Ext(w.filename)
+	return strings.TrimSuffix(w.filename, ext) + "-", ext
+}
+
+// removeBackups removes the backups older than maxAge and those beyond the
+// maxBackups most recent ones.
+func (w *Writer) removeBackups() error {
+	if w.maxAge <= 0 && w.maxBackups <= 0 {
+		return nil
+	}
+
+	type backup struct {
+		path string
+		time time.Time
+	}
+
+	prefix, ext := w.prefixAndExt()
+	matches, err := filepath.Glob(prefix + "*" + ext)
+	if err != nil {
+		return err
+	}
+
+	backups := make([]backup, 0, len(matches))
+	for _, path := range matches {
+		t, err := time.Parse(backupTimeFormat, strings.TrimSuffix(strings.TrimPrefix(path, prefix), ext))
+		if err != nil {
+			// not a backup of this file
+			continue
+		}
+		backups = append(backups, backup{path: path, time: t})
+	}
+	sort.Slice(backups, func(i, j int) bool {
+		return backups[i].time.After(backups[j].time)
+	})
+
+	cutoff := w.now().Add(-w.maxAge)
+	for i, b := range backups {
+		expired := w.maxAge > 0 && b.time.Before(cutoff)
+		excess := w.maxBackups > 0 && i >= w.maxBackups
+		if !expired && !excess {
+			continue
+		}
+		if err := os.Remove(b.path); err != nil && !os.IsNotExist(err) {
+			return fmt.Errorf("failed to remove log backup: %w", err)
+		}
+	}
+	return nil
+}
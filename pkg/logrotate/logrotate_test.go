@@ -0,0 +1,98 @@
+package logrotate
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestWriter_Rotate(t *testing.T) {
+	dir := t.TempDir()
+	filename := filepath.Join(dir, "feeder.log")
+
+	w, err := NewWriter(filename, 10, 0, 2)
+	require.NoError(t, err)
+	defer w.Close()
+
+	now := time.Date(2024, 1, 2, 15, 4, 5, 0, time.UTC)
+	w.now = func() time.Time { return now }
+
+	for i := 0; i < 4; i++ {
+		_, err = w.Write([]byte("12345678\n"))
+		require.NoError(t, err)
+		now = now.Add(time.Second)
+	}
+
+	bz, err := os.ReadFile(filename)
+	require.NoError(t, err)
+	require.Equal(t, "12345678\n", string(bz))
+
+	// three rotations, of which the two most recent backups are kept
+	backups, err := filepath.Glob(filepath.Join(dir, "feeder-*.log"))
+	require.NoError(t, err)
+	require.Equal(t, []string{
+		filepath.Join(dir, "feeder-2024-01-02T15-04-07.000.log"),
+		filepath.Join(dir, "feeder-2024-01-02T15-04-08.000.log"),
+	}, backups)
+}
+
+func TestWriter_MaxAge(t *testing.T) {
+	dir := t.TempDir()
+	filename := filepath.Join(dir, "feeder.log")
+
+	w, err := NewWriter(filename, 10, time.Hour, 0)
+	require.NoError(t, err)
+	defer w.Close()
+
+	now := time.Date(2024, 1, 2, 15, 4, 5, 0, time.UTC)
+	w.now = func() time.Time { return now }
+
+	_, err = w.Write([]byte("12345678\n"))
+	require.NoError(t, err)
+	_, err = w.Write([]byte("12345678\n"))
+	require.NoError(t, err)
+
+	now = now.Add(2 * time.Hour)
+	_, err = w.Write([]byte("12345678\n"))
+	require.NoError(t, err)
+
+	backups, err := filepath.Glob(filepath.Join(dir, "feeder-*.log"))
+	require.NoError(t, err)
+	require.Equal(t, []string{filepath.Join(dir, "feeder-2024-01-02T17-04-05.000.log")}, backups)
+}
+
+func TestWriter_Append(t *testing.T) {
+	filename := filepath.Join(t.TempDir(), "logs", "feeder.log")
+
+	w, err := NewWriter(filename, 0, 0, 0)
+	require.NoError(t, err)
+	_, err = w.Write([]byte("first\n"))
+	require.NoError(t, err)
+	require.NoError(t, w.Close())
+
+	_, err = w.Write([]byte("closed\n"))
+	require.ErrorIs(t, err, os.ErrClosed)
+
+	w, err = NewWriter(filename, 0, 0, 0)
+	require.NoError(t, err)
+	_, err = w.Write([]byte("second\n"))
+	require.NoError(t, err)
+	require.NoError(t, w.Close())
+
+	bz, err := os.ReadFile(filename)
+	require.NoError(t, err)
+	require.Equal(t, "first\nsecond\n", string(bz))
+}
+
+func TestNewWriter_NotWritable(t *testing.T) {
+	dir := t.TempDir()
+	file := filepath.Join(dir, "file")
+	require.NoError(t, os.WriteFile(file, nil, 0o644))
+
+	// the log directory can't be created below a file
+	_, err := NewWriter(filepath.Join(file, "feeder.log"), 0, 0, 0)
+	require.Error(t, err)
+}
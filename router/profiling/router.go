@@ -0,0 +1,99 @@
+package profiling
+
+import (
+	"net/http"
+	"net/http/pprof"
+	"runtime"
+	"time"
+
+	"github.com/gorilla/mux"
+	"github.com/justinas/alice"
+	"github.com/rs/zerolog"
+
+	"github.com/ojo-network/price-feeder/pkg/httputil"
+	"github.com/ojo-network/price-feeder/router/middleware"
+)
+
+const (
+	PprofPathPrefix = "/debug/pprof"
+	RuntimePath     = "/debug/runtime"
+)
+
+// RuntimeResponse defines the response type for the runtime stats handler,
+// a snapshot of the scheduler and the memory allocator of the process.
+type RuntimeResponse struct {
+	GoVersion    string `json:"go_version"`
+	NumCPU       int    `json:"num_cpu"`
+	GOMAXPROCS   int    `json:"gomaxprocs"`
+	NumGoroutine int    `json:"num_goroutine"`
+	UptimeSecs   int64  `json:"uptime_seconds"`
+
+	HeapAlloc    uint64 `json:"heap_alloc_bytes"`
+	HeapInuse    uint64 `json:"heap_inuse_bytes"`
+	HeapObjects  uint64 `json:"heap_objects"`
+	StackInuse   uint64 `json:"stack_inuse_bytes"`
+	Sys          uint64 `json:"sys_bytes"`
+	TotalAlloc   uint64 `json:"total_alloc_bytes"`
+	NumGC        uint32 `json:"num_gc"`
+	PauseTotalNs uint64 `json:"gc_pause_total_ns"`
+	LastGC       string `json:"last_gc,omitempty"`
+}
+
+// Router defines a router wrapper registering the profiling routes, the
+// standard net/http/pprof handlers and the runtime stats of the process. It is
+// served on its own listener, apart from the API and its CORS middleware.
+type Router struct {
+	logger    zerolog.Logger
+	startedAt time.Time
+}
+
+func New(logger zerolog.Logger) *Router {
+	return &Router{
+		logger:    logger.With().Str("module", "profiling").Logger(),
+		startedAt: time.Now(),
+	}
+}
+
+// RegisterRoutes register the profiling routes to the router. Requests are
+// logged, without CORS headers, as the profiles are not meant for browsers.
+func (r *Router) RegisterRoutes(rtr *mux.Router) {
+	mChain := middleware.AddRequestLoggingMiddleware(alice.New(), r.logger)
+
+	pprofRouter := rtr.PathPrefix(PprofPathPrefix).Subrouter()
+	pprofRouter.Handle("/cmdline", mChain.ThenFunc(pprof.Cmdline)).Methods(httputil.MethodGET)
+	pprofRouter.Handle("/profile", mChain.ThenFunc(pprof.Profile)).Methods(httputil.MethodGET)
+	pprofRouter.Handle("/symbol", mChain.ThenFunc(pprof.Symbol)).Methods(httputil.MethodGET, httputil.MethodPOST)
+	pprofRouter.Handle("/trace", mChain.ThenFunc(pprof.Trace)).Methods(httputil.MethodGET)
+	// the index serves the named profiles, ex. heap and goroutine
+	pprofRouter.PathPrefix("/").Handler(mChain.ThenFunc(pprof.Index)).Methods(httputil.MethodGET)
+
+	rtr.Handle(RuntimePath, mChain.ThenFunc(r.runtimeHandler())).Methods(httputil.MethodGET)
+}
+
+func (r *Router) runtimeHandler() http.HandlerFunc {
+	return func(w http.ResponseWriter, _ *http.Request) {
+		var memStats runtime.MemStats
+		runtime.ReadMemStats(&memStats)
+
+		resp := RuntimeResponse{
+			GoVersion:    runtime.Version(),
+			NumCPU:       runtime.NumCPU(),
+			GOMAXPROCS:   runtime.GOMAXPROCS(0),
+			NumGoroutine: runtime.NumGoroutine(),
+			UptimeSecs:   int64(time.Since(r.startedAt).Seconds()),
+			HeapAlloc:    memStats.HeapAlloc,
+			HeapInuse:    memStats.HeapInuse,
+			HeapObjects:  memStats.HeapObjects,
+			StackInuse:   memStats.StackInuse,
+			Sys:          memStats.Sys,
+			TotalAlloc:   memStats.TotalAlloc,
+			NumGC:        memStats.NumGC,
+			PauseTotalNs: memStats.PauseTotalNs,
+		}
+		if memStats.LastGC > 0 {
+			resp.LastGC = time.Unix(0, int64(memStats.LastGC)).UTC().Format(time.RFC3339)
+		}
+
+		httputil.RespondWithJSON(w, http.StatusOK, resp)
+	}
+}
@@ -0,0 +1,69 @@
+package profiling_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gorilla/mux"
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ojo-network/price-feeder/router/profiling"
+)
+
+func newProfilingRouter() *mux.Router {
+	rtr := mux.NewRouter()
+	profiling.New(zerolog.Nop()).RegisterRoutes(rtr)
+	return rtr
+}
+
+func TestRouter_Runtime(t *testing.T) {
+	rtr := newProfilingRouter()
+
+	req := httptest.NewRequest(http.MethodGet, profiling.RuntimePath, nil)
+	req.Header.Set("Origin", "https://example.com")
+	resp := httptest.NewRecorder()
+	rtr.ServeHTTP(resp, req)
+
+	require.Equal(t, http.StatusOK, resp.Code)
+	// the profiling routes serve no CORS headers
+	require.Empty(t, resp.Header().Get("Access-Control-Allow-Origin"))
+
+	var stats profiling.RuntimeResponse
+	require.NoError(t, json.Unmarshal(resp.Body.Bytes(), &stats))
+	require.Positive(t, stats.NumGoroutine)
+	require.Positive(t, stats.GOMAXPROCS)
+	require.Positive(t, stats.HeapAlloc)
+	require.True(t, strings.HasPrefix(stats.GoVersion, "go"))
+}
+
+func TestRouter_Pprof(t *testing.T) {
+	rtr := newProfilingRouter()
+
+	testCases := []struct {
+		path     string
+		contains string
+	}{
+		{path: profiling.PprofPathPrefix + "/", contains: "goroutine"},
+		{path: profiling.PprofPathPrefix + "/goroutine?debug=1", contains: "goroutine profile"},
+		{path: profiling.PprofPathPrefix + "/cmdline"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.path, func(t *testing.T) {
+			resp := httptest.NewRecorder()
+			rtr.ServeHTTP(resp, httptest.NewRequest(http.MethodGet, tc.path, nil))
+
+			require.Equal(t, http.StatusOK, resp.Code)
+			require.Contains(t, resp.Body.String(), tc.contains)
+		})
+	}
+
+	// preflight requests are not handled
+	resp := httptest.NewRecorder()
+	rtr.ServeHTTP(resp, httptest.NewRequest(http.MethodOptions, profiling.RuntimePath, nil))
+	require.Equal(t, http.StatusMethodNotAllowed, resp.Code)
+}
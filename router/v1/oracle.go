@@ -10,6 +10,12 @@ import (
 type Oracle interface {
 	GetLastPriceSyncTimestamp() time.Time
 	GetPrices() types.CurrencyPairDec
+	GetPriceDetails() []types.PriceDetail
+	GetStalePrices(maxAge time.Duration) map[types.CurrencyPair]types.StalePrice
 	GetTvwapPrices() types.CurrencyPairDecByProvider
 	GetVwapPrices() types.CurrencyPairDecByProvider
+	GetPausedPairs() []types.CurrencyPair
+	IsWarmingUp() bool
+	PausePair(cp types.CurrencyPair) error
+	ResumePair(cp types.CurrencyPair) error
 }
@@ -15,17 +15,39 @@ const (
 
 type (
 	// HealthZResponse defines the response type for the healthy API handler.
+	// The oracle is warming up while its prices are partially derived from
+	// the price cache loaded at startup.
 	HealthZResponse struct {
 		Status string `json:"status" yaml:"status"`
 		Oracle struct {
-			LastSync string `json:"last_sync"`
+			LastSync  string `json:"last_sync"`
+			WarmingUp bool   `json:"warming_up"`
 		} `json:"oracle"`
 	}
 
 	// PricesResponse defines the response type for getting the latest exchange
-	// rates from the oracle.
+	// rates from the oracle. In safe mode, the last aggregated rates of the
+	// pairs without a fresh rate are returned as stale prices. Paused pairs
+	// are priced but omitted from the votes. WarmingUp flags prices partially
+	// derived from the price cache loaded at startup.
 	PricesResponse struct {
-		Prices types.CurrencyPairDec `json:"prices"`
+		Prices      types.CurrencyPairDec                   `json:"prices"`
+		StalePrices map[types.CurrencyPair]types.StalePrice `json:"stale_prices,omitempty"`
+		PausedPairs []types.CurrencyPair                    `json:"paused_pairs,omitempty"`
+		WarmingUp   bool                                    `json:"warming_up,omitempty"`
+	}
+
+	// PairPauseResponse defines the response type for pausing or resuming
+	// the votes of a pair.
+	PairPauseResponse struct {
+		Pair   types.CurrencyPair `json:"pair"`
+		Paused bool               `json:"paused"`
+	}
+
+	// PriceDetailsResponse defines the response type for getting the
+	// breakdown of the latest exchange rates by provider.
+	PriceDetailsResponse struct {
+		Prices []types.PriceDetail `json:"prices"`
 	}
 
 	PricesPerProviderResponse struct {
@@ -1,8 +1,10 @@
 package v1
 
 import (
+	"errors"
 	"fmt"
 	"html/template"
+	"mime"
 	"net/http"
 	"strings"
 	"time"
@@ -11,6 +13,7 @@ import (
 	"github.com/rs/zerolog"
 
 	"github.com/ojo-network/price-feeder/config"
+	"github.com/ojo-network/price-feeder/oracle/types"
 	"github.com/ojo-network/price-feeder/pkg/httputil"
 	"github.com/ojo-network/price-feeder/router/middleware"
 )
@@ -70,6 +73,11 @@ func (r *Router) RegisterRoutes(rtr *mux.Router, prefix string) {
 		mChain.ThenFunc(r.pricesHandler()),
 	).Methods(httputil.MethodGET)
 
+	v1Router.Handle(
+		"/prices/detail",
+		mChain.ThenFunc(r.priceDetailsHandler()),
+	).Methods(httputil.MethodGET)
+
 	v1Router.Handle(
 		"/prices/providers/tvwap",
 		mChain.ThenFunc(r.candlePricesHandler()),
@@ -80,6 +88,18 @@ func (r *Router) RegisterRoutes(rtr *mux.Router, prefix string) {
 		mChain.ThenFunc(r.tickerPricesHandler()),
 	).Methods(httputil.MethodGET)
 
+	if r.cfg.Server.PairControl {
+		v1Router.Handle(
+			"/pairs/{base}/{quote}/pause",
+			mChain.ThenFunc(r.pairPauseHandler(true)),
+		).Methods(httputil.MethodPOST)
+
+		v1Router.Handle(
+			"/pairs/{base}/{quote}/resume",
+			mChain.ThenFunc(r.pairPauseHandler(false)),
+		).Methods(httputil.MethodPOST)
+	}
+
 	if r.cfg.Telemetry.Enabled {
 		v1Router.Handle(
 			"/metrics",
@@ -95,6 +115,7 @@ func (r *Router) healthzHandler() http.HandlerFunc {
 		}
 
 		resp.Oracle.LastSync = r.oracle.GetLastPriceSyncTimestamp().Format(time.RFC3339)
+		resp.Oracle.WarmingUp = r.oracle.IsWarmingUp()
 
 		httputil.RespondWithJSON(w, http.StatusOK, resp)
 	}
@@ -103,7 +124,28 @@ func (r *Router) healthzHandler() http.HandlerFunc {
 func (r *Router) pricesHandler() http.HandlerFunc {
 	return func(w http.ResponseWriter, req *http.Request) {
 		resp := PricesResponse{
-			Prices: r.oracle.GetPrices(),
+			Prices:      r.oracle.GetPrices(),
+			PausedPairs: r.oracle.GetPausedPairs(),
+			WarmingUp:   r.oracle.IsWarmingUp(),
+		}
+
+		if r.cfg.Server.SafeMode {
+			maxAge, err := r.cfg.Server.SafeModeMaxAgeDuration()
+			if err != nil {
+				writeErrorResponse(w, http.StatusInternalServerError, err.Error())
+				return
+			}
+			resp.StalePrices = r.oracle.GetStalePrices(maxAge)
+		}
+
+		httputil.RespondWithJSON(w, http.StatusOK, resp)
+	}
+}
+
+func (r *Router) priceDetailsHandler() http.HandlerFunc {
+	return func(w http.ResponseWriter, req *http.Request) {
+		resp := PriceDetailsResponse{
+			Prices: r.oracle.GetPriceDetails(),
 		}
 
 		httputil.RespondWithJSON(w, http.StatusOK, resp)
@@ -130,6 +172,62 @@ func (r *Router) tickerPricesHandler() http.HandlerFunc {
 	}
 }
 
+func (r *Router) pairPauseHandler(paused bool) http.HandlerFunc {
+	return func(w http.ResponseWriter, req *http.Request) {
+		if !r.isTrustedRequest(req) {
+			writeErrorResponse(
+				w,
+				http.StatusForbidden,
+				"request requires a Content-Type of application/json, an X-Requested-With header or an allowed origin",
+			)
+			return
+		}
+
+		vars := mux.Vars(req)
+		cp := types.CurrencyPair{
+			Base:  strings.ToUpper(vars["base"]),
+			Quote: strings.ToUpper(vars["quote"]),
+		}
+
+		pause := r.oracle.ResumePair
+		if paused {
+			pause = r.oracle.PausePair
+		}
+		if err := pause(cp); err != nil {
+			status := http.StatusInternalServerError
+			if errors.Is(err, types.ErrUnknownPair) {
+				status = http.StatusNotFound
+			}
+			writeErrorResponse(w, status, err.Error())
+			return
+		}
+
+		httputil.RespondWithJSON(w, http.StatusOK, PairPauseResponse{Pair: cp, Paused: paused})
+	}
+}
+
+// isTrustedRequest returns whether a state changing request can't be a
+// cross-origin "simple" request, which a browser sends without a preflight:
+// it carries a JSON Content-Type or an X-Requested-With header, or comes from
+// an allowed origin.
+func (r *Router) isTrustedRequest(req *http.Request) bool {
+	if mediaType, _, err := mime.ParseMediaType(req.Header.Get("Content-Type")); err == nil &&
+		mediaType == "application/json" {
+		return true
+	}
+	if req.Header.Get("X-Requested-With") != "" {
+		return true
+	}
+
+	origin := req.Header.Get("Origin")
+	for _, allowed := range r.cfg.Server.AllowedOrigins {
+		if origin != "" && origin == allowed {
+			return true
+		}
+	}
+	return false
+}
+
 func (r *Router) metricsHandler() http.HandlerFunc {
 	return func(w http.ResponseWriter, req *http.Request) {
 		format := strings.TrimSpace(req.FormValue("format"))
@@ -31,6 +31,39 @@ var (
 		OJOUSD:  sdk.MustNewDecFromStr("4.21"),
 	}
 
+	mockPausedPairs = []types.CurrencyPair{OJOUSD}
+
+	mockStalePrices = map[types.CurrencyPair]types.StalePrice{
+		FOOUSD: {Price: sdk.MustNewDecFromStr("1.05"), Stale: true, AgeSeconds: 120},
+	}
+
+	atomPrice        = sdk.MustNewDecFromStr("34.84")
+	mockPriceDetails = []types.PriceDetail{
+		{
+			Base:  "ATOM",
+			Quote: "USD",
+			Price: &atomPrice,
+			Providers: []types.ProviderPriceDetail{
+				{
+					Provider: provider.ProviderBinance,
+					Quote:    "USDT",
+					Source:   "candles",
+					Price:    sdk.MustNewDecFromStr("34.80"),
+					Weight:   sdk.OneDec(),
+					Included: true,
+				},
+				{
+					Provider: provider.ProviderKraken,
+					Quote:    "USD",
+					Source:   "candles",
+					Price:    sdk.MustNewDecFromStr("40.10"),
+					Weight:   sdk.ZeroDec(),
+					Reason:   types.FilterReasonDeviation,
+				},
+			},
+		},
+	}
+
 	mockComputedPrices = types.CurrencyPairDecByProvider{
 		provider.ProviderBinance: {
 			ATOMUSD: sdk.MustNewDecFromStr("28.21000000"),
@@ -43,7 +76,9 @@ var (
 	}
 )
 
-type mockOracle struct{}
+type mockOracle struct {
+	warmingUp bool
+}
 
 func (m mockOracle) GetLastPriceSyncTimestamp() time.Time {
 	return time.Now()
@@ -53,6 +88,14 @@ func (m mockOracle) GetPrices() types.CurrencyPairDec {
 	return mockPrices
 }
 
+func (m mockOracle) GetPriceDetails() []types.PriceDetail {
+	return mockPriceDetails
+}
+
+func (m mockOracle) GetStalePrices(time.Duration) map[types.CurrencyPair]types.StalePrice {
+	return mockStalePrices
+}
+
 func (m mockOracle) GetTvwapPrices() types.CurrencyPairDecByProvider {
 	return mockComputedPrices
 }
@@ -61,6 +104,25 @@ func (m mockOracle) GetVwapPrices() types.CurrencyPairDecByProvider {
 	return mockComputedPrices
 }
 
+func (m mockOracle) GetPausedPairs() []types.CurrencyPair {
+	return mockPausedPairs
+}
+
+func (m mockOracle) IsWarmingUp() bool {
+	return m.warmingUp
+}
+
+func (m mockOracle) PausePair(cp types.CurrencyPair) error {
+	if _, ok := mockPrices[cp]; !ok {
+		return types.ErrUnknownPair.Wrap(cp.String())
+	}
+	return nil
+}
+
+func (m mockOracle) ResumePair(cp types.CurrencyPair) error {
+	return m.PausePair(cp)
+}
+
 type mockMetrics struct{}
 
 func (mockMetrics) Gather(format string) (telemetry.GatherResponse, error) {
@@ -112,6 +174,32 @@ func (rts *RouterTestSuite) TestHealthz() {
 	var respBody map[string]interface{}
 	rts.Require().NoError(json.Unmarshal(response.Body.Bytes(), &respBody))
 	rts.Require().Equal(respBody["status"], v1.StatusAvailable)
+	rts.Require().Equal(false, respBody["oracle"].(map[string]interface{})["warming_up"])
+}
+
+func (rts *RouterTestSuite) TestWarmingUp() {
+	mux := mux.NewRouter()
+	cfg := config.Config{Server: config.Server{AllowedOrigins: []string{}}}
+	v1.New(zerolog.Nop(), cfg, mockOracle{warmingUp: true}, mockMetrics{}).RegisterRoutes(mux, v1.APIPathPrefix)
+
+	req, err := http.NewRequest("GET", "/api/v1/healthz", nil)
+	rts.Require().NoError(err)
+	response := httptest.NewRecorder()
+	mux.ServeHTTP(response, req)
+
+	var healthz v1.HealthZResponse
+	rts.Require().NoError(json.Unmarshal(response.Body.Bytes(), &healthz))
+	rts.Require().True(healthz.Oracle.WarmingUp)
+
+	req, err = http.NewRequest("GET", "/api/v1/prices", nil)
+	rts.Require().NoError(err)
+	response = httptest.NewRecorder()
+	mux.ServeHTTP(response, req)
+
+	var prices v1.PricesResponse
+	rts.Require().NoError(json.Unmarshal(response.Body.Bytes(), &prices))
+	rts.Require().True(prices.WarmingUp)
+	rts.Require().Equal(mockPrices[ATOMUSD], prices.Prices[ATOMUSD])
 }
 
 func (rts *RouterTestSuite) TestPrices() {
@@ -126,6 +214,144 @@ func (rts *RouterTestSuite) TestPrices() {
 	rts.Require().Equal(respBody.Prices[ATOMUSD], mockPrices[ATOMUSD])
 	rts.Require().Equal(respBody.Prices[OJOUSD], mockPrices[OJOUSD])
 	rts.Require().Equal(respBody.Prices[FOOUSD], sdk.Dec{})
+	rts.Require().Empty(respBody.StalePrices)
+	rts.Require().Equal(mockPausedPairs, respBody.PausedPairs)
+}
+
+func (rts *RouterTestSuite) TestPricesSafeMode() {
+	mux := mux.NewRouter()
+	cfg := config.Config{
+		Server: config.Server{
+			AllowedOrigins: []string{},
+			SafeMode:       true,
+		},
+	}
+	v1.New(zerolog.Nop(), cfg, mockOracle{}, mockMetrics{}).RegisterRoutes(mux, v1.APIPathPrefix)
+
+	req, err := http.NewRequest("GET", "/api/v1/prices", nil)
+	rts.Require().NoError(err)
+	response := httptest.NewRecorder()
+	mux.ServeHTTP(response, req)
+	rts.Require().Equal(http.StatusOK, response.Code)
+
+	var respBody v1.PricesResponse
+	rts.Require().NoError(json.Unmarshal(response.Body.Bytes(), &respBody))
+	rts.Require().Equal(mockPrices[ATOMUSD], respBody.Prices[ATOMUSD])
+	rts.Require().Equal(mockStalePrices[FOOUSD], respBody.StalePrices[FOOUSD])
+	rts.Require().True(respBody.StalePrices[FOOUSD].Stale)
+	rts.Require().Equal(int64(120), respBody.StalePrices[FOOUSD].AgeSeconds)
+}
+
+func (rts *RouterTestSuite) TestPairPause() {
+	// the endpoints are not served unless pair control is enabled
+	req, err := http.NewRequest("POST", "/api/v1/pairs/atom/usd/pause", nil)
+	rts.Require().NoError(err)
+	rts.Require().NotEqual(http.StatusOK, rts.executeRequest(req).Code)
+
+	mux := mux.NewRouter()
+	cfg := config.Config{
+		Server: config.Server{
+			AllowedOrigins: []string{},
+			PairControl:    true,
+		},
+	}
+	v1.New(zerolog.Nop(), cfg, mockOracle{}, mockMetrics{}).RegisterRoutes(mux, v1.APIPathPrefix)
+
+	for _, action := range []string{"pause", "resume"} {
+		req, err := http.NewRequest("POST", "/api/v1/pairs/atom/usd/"+action, nil)
+		rts.Require().NoError(err)
+		req.Header.Set("Content-Type", "application/json")
+		response := httptest.NewRecorder()
+		mux.ServeHTTP(response, req)
+		rts.Require().Equal(http.StatusOK, response.Code)
+
+		var respBody v1.PairPauseResponse
+		rts.Require().NoError(json.Unmarshal(response.Body.Bytes(), &respBody))
+		rts.Require().Equal(ATOMUSD, respBody.Pair)
+		rts.Require().Equal(action == "pause", respBody.Paused)
+	}
+
+	req, err = http.NewRequest("POST", "/api/v1/pairs/bar/usd/pause", nil)
+	rts.Require().NoError(err)
+	req.Header.Set("Content-Type", "application/json")
+	response := httptest.NewRecorder()
+	mux.ServeHTTP(response, req)
+	rts.Require().Equal(http.StatusNotFound, response.Code)
+}
+
+func (rts *RouterTestSuite) TestPairPauseCrossOrigin() {
+	mux := mux.NewRouter()
+	cfg := config.Config{
+		Server: config.Server{
+			AllowedOrigins: []string{"https://ops.ojo.network"},
+			PairControl:    true,
+		},
+	}
+	v1.New(zerolog.Nop(), cfg, mockOracle{}, mockMetrics{}).RegisterRoutes(mux, v1.APIPathPrefix)
+
+	testCases := []struct {
+		name       string
+		header     http.Header
+		expectCode int
+	}{
+		{
+			name:       "no header",
+			header:     http.Header{},
+			expectCode: http.StatusForbidden,
+		},
+		{
+			name: "simple form post from another origin",
+			header: http.Header{
+				"Content-Type": {"application/x-www-form-urlencoded"},
+				"Origin":       {"https://evil.example"},
+			},
+			expectCode: http.StatusForbidden,
+		},
+		{
+			name:       "text plain post",
+			header:     http.Header{"Content-Type": {"text/plain"}},
+			expectCode: http.StatusForbidden,
+		},
+		{
+			name:       "json content type",
+			header:     http.Header{"Content-Type": {"application/json; charset=utf-8"}},
+			expectCode: http.StatusOK,
+		},
+		{
+			name:       "x-requested-with header",
+			header:     http.Header{"X-Requested-With": {"curl"}},
+			expectCode: http.StatusOK,
+		},
+		{
+			name:       "allowed origin",
+			header:     http.Header{"Origin": {"https://ops.ojo.network"}},
+			expectCode: http.StatusOK,
+		},
+	}
+
+	for _, tc := range testCases {
+		rts.Run(tc.name, func() {
+			req, err := http.NewRequest("POST", "/api/v1/pairs/atom/usd/pause", nil)
+			rts.Require().NoError(err)
+			req.Header = tc.header
+			response := httptest.NewRecorder()
+			mux.ServeHTTP(response, req)
+			rts.Require().Equal(tc.expectCode, response.Code)
+		})
+	}
+}
+
+func (rts *RouterTestSuite) TestPriceDetails() {
+	req, err := http.NewRequest("GET", "/api/v1/prices/detail", nil)
+	rts.Require().NoError(err)
+	response := rts.executeRequest(req)
+	rts.Require().Equal(http.StatusOK, response.Code)
+
+	var respBody v1.PriceDetailsResponse
+	rts.Require().NoError(json.Unmarshal(response.Body.Bytes(), &respBody))
+	rts.Require().Equal(mockPriceDetails, respBody.Prices)
+	rts.Require().False(respBody.Prices[0].Providers[1].Included)
+	rts.Require().Equal(types.FilterReasonDeviation, respBody.Prices[0].Providers[1].Reason)
 }
 
 func (rts *RouterTestSuite) TestTvwap() {